@@ -225,6 +225,17 @@ func (c *Consumer) CloseSubscription(id string) error {
 	return nil
 }
 
+// Settle sends the acknowledgement for a delivery based on the result of its
+// processing. If `err` is nil the delivery is acknowledged. Failed deliveries
+// are requeued only when `err` is marked as retryable (see 'errors.IsRetryable'),
+// otherwise they are rejected and discarded or dead-lettered by the broker.
+func Settle(msg Delivery, err error) error {
+	if err == nil {
+		return msg.Ack(false)
+	}
+	return msg.Nack(false, errors.IsRetryable(err))
+}
+
 // RespondRPC will submit a response for a received RPC request. You
 // MUST set the response "CorrelationId" value to the request's "MessageId".
 func (c *Consumer) RespondRPC(msg Message, replyTo string) error {
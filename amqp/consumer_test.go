@@ -2,6 +2,10 @@ package amqp
 
 import (
 	"log"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
 )
 
 var consumer *Consumer
@@ -90,3 +94,38 @@ func ExampleConsumer_Subscribe() {
 		panic(err)
 	}
 }
+
+func TestSettle(t *testing.T) {
+	assert := tdd.New(t)
+	ack := new(testAcknowledger)
+	msg := Delivery{Acknowledger: ack, DeliveryTag: 1}
+
+	assert.Nil(Settle(msg, nil))
+	assert.Equal("ack", ack.result)
+	assert.Nil(Settle(msg, errors.MarkRetryable(errors.New("timeout"))))
+	assert.Equal("requeue", ack.result)
+	assert.Nil(Settle(msg, errors.New("invalid message")))
+	assert.Equal("reject", ack.result)
+}
+
+type testAcknowledger struct {
+	result string
+}
+
+func (ta *testAcknowledger) Ack(_ uint64, _ bool) error {
+	ta.result = "ack"
+	return nil
+}
+
+func (ta *testAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	ta.result = "reject"
+	if requeue {
+		ta.result = "requeue"
+	}
+	return nil
+}
+
+func (ta *testAcknowledger) Reject(_ uint64, _ bool) error {
+	ta.result = "reject"
+	return nil
+}
@@ -169,5 +169,13 @@ is opened with the "AutoAck" option).
 	if err = consumer.Close(); err != nil {
 		panic(err)
 	}
+
+Deliveries can also be settled based on the result of their processing. Failed
+deliveries are requeued only if the returned error is marked as retryable.
+
+	for msg := range tasksToHandle {
+		err := process(msg) // i.e. errors.MarkRetryable(err) for transient failures
+		_ = Settle(msg, err)
+	}
 */
 package amqp
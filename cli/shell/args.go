@@ -0,0 +1,431 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// ValueType identifies the kind of value expected for a flag or argument.
+type ValueType string
+
+const (
+	// TypeString is the default value type.
+	TypeString ValueType = "string"
+
+	// TypeInt values are parsed as `int`.
+	TypeInt ValueType = "int"
+
+	// TypeFloat values are parsed as `float64`.
+	TypeFloat ValueType = "float"
+
+	// TypeBool values are parsed as `bool`. Boolean flags don't require a
+	// value; `--verbose` is equivalent to `--verbose=true`.
+	TypeBool ValueType = "bool"
+
+	// TypeDuration values are parsed as `time.Duration`, e.g. "1m30s".
+	TypeDuration ValueType = "duration"
+)
+
+// Flag defines a named parameter accepted by a command. Flags can be
+// provided as `--name value`, `--name=value` or using the short version of
+// the name, e.g. `-n value`.
+type Flag struct {
+	// Long name for the flag, used as `--name`.
+	Name string
+
+	// Optional single-letter name for the flag, used as `-n`.
+	Short string
+
+	// Brief description of the flag purpose.
+	Description string
+
+	// Type of value expected. Defaults to `TypeString`.
+	Type ValueType
+
+	// Value used when the flag is not provided.
+	Default interface{}
+
+	// Return an error if the flag is not provided.
+	Required bool
+
+	// Optional method to validate the value provided.
+	Validate func(value interface{}) error
+}
+
+// Arg defines a positional argument accepted by a command. Arguments are
+// assigned in order from the values provided that are not flags.
+type Arg struct {
+	// Name used to retrieve the argument value.
+	Name string
+
+	// Brief description of the argument purpose.
+	Description string
+
+	// Type of value expected. Defaults to `TypeString`.
+	Type ValueType
+
+	// Value used when the argument is not provided.
+	Default interface{}
+
+	// Return an error if the argument is not provided.
+	Required bool
+
+	// Optional method to validate the value provided.
+	Validate func(value interface{}) error
+}
+
+// Input provides structured access to the flags and arguments provided
+// when invoking a command.
+type Input struct {
+	// Original input as provided by the user.
+	Raw string
+
+	values map[string]interface{}
+	set    map[string]bool
+	args   []string
+	format string
+	ctx    context.Context
+	out    io.Writer
+}
+
+// Context returns the context for the command execution. The context is
+// canceled when a command running as a background job is killed.
+func (in *Input) Context() context.Context {
+	if in.ctx == nil {
+		return context.Background()
+	}
+	return in.ctx
+}
+
+// Output returns a writer to report progress information while the command
+// is executing. For background jobs, the content is captured on the job's
+// output buffer.
+func (in *Input) Output() io.Writer {
+	if in.out == nil {
+		return io.Discard
+	}
+	return in.out
+}
+
+// Format returns the output format requested for the command results.
+func (in *Input) Format() string {
+	if in.format == "" {
+		return OutputText
+	}
+	return in.format
+}
+
+// Render returns the representation of `v` using the output format
+// requested for the command results.
+func (in *Input) Render(v interface{}) (string, error) {
+	return Render(v, in.Format())
+}
+
+// Get returns the value for a flag or argument, `nil` if not available.
+func (in *Input) Get(name string) interface{} {
+	return in.values[name]
+}
+
+// IsSet returns `true` if the flag or argument was explicitly provided.
+func (in *Input) IsSet(name string) bool {
+	return in.set[name]
+}
+
+// Args returns all the positional values provided, including the ones
+// assigned to declared arguments.
+func (in *Input) Args() []string {
+	return append([]string{}, in.args...)
+}
+
+// String returns the value for a flag or argument of type `TypeString`.
+func (in *Input) String(name string) string {
+	v, _ := in.values[name].(string)
+	return v
+}
+
+// Int returns the value for a flag or argument of type `TypeInt`.
+func (in *Input) Int(name string) int {
+	v, _ := in.values[name].(int)
+	return v
+}
+
+// Float returns the value for a flag or argument of type `TypeFloat`.
+func (in *Input) Float(name string) float64 {
+	v, _ := in.values[name].(float64)
+	return v
+}
+
+// Bool returns the value for a flag or argument of type `TypeBool`.
+func (in *Input) Bool(name string) bool {
+	v, _ := in.values[name].(bool)
+	return v
+}
+
+// Duration returns the value for a flag or argument of type `TypeDuration`.
+func (in *Input) Duration(name string) time.Duration {
+	v, _ := in.values[name].(time.Duration)
+	return v
+}
+
+// Process the raw input provided for a command using its flags and
+// arguments specification.
+func (c *Command) parse(raw string) (*Input, error) {
+	tokens, err := tokenize(raw)
+	if err != nil {
+		return nil, err
+	}
+	in := &Input{
+		Raw:    raw,
+		values: make(map[string]interface{}),
+		set:    make(map[string]bool),
+	}
+
+	// Flags
+	for i := 0; i < len(tokens); i++ {
+		tk := tokens[i]
+		if tk == "--" {
+			in.args = append(in.args, tokens[i+1:]...)
+			break
+		}
+		if !isFlag(tk) || (c.flag(tk) == nil && isNumber(tk)) {
+			in.args = append(in.args, tk)
+			continue
+		}
+		name, value, hasValue := strings.Cut(tk, "=")
+		f := c.flag(name)
+		if f == nil && name == "--output" {
+			// global output format flag
+			if !hasValue && i+1 < len(tokens) {
+				i++
+				value = tokens[i]
+			}
+			if !validFormat(value) {
+				return nil, errors.Errorf("invalid output format: '%s'", value)
+			}
+			in.format = value
+			continue
+		}
+		if f == nil {
+			return nil, errors.Errorf("unknown flag: %s", name)
+		}
+		if !hasValue && f.Type != TypeBool {
+			if i+1 >= len(tokens) {
+				return nil, errors.Errorf("flag needs a value: --%s", f.Name)
+			}
+			i++
+			value = tokens[i]
+		}
+		if !hasValue && f.Type == TypeBool {
+			value = "true"
+		}
+		v, err := convert(f.Type, value)
+		if err != nil {
+			return nil, errors.Errorf("invalid value for flag --%s: %s", f.Name, err)
+		}
+		if err := validate(f.Validate, v); err != nil {
+			return nil, errors.Errorf("invalid value for flag --%s: %s", f.Name, err)
+		}
+		in.values[f.Name] = v
+		in.set[f.Name] = true
+	}
+	for _, f := range c.Flags {
+		if in.set[f.Name] {
+			continue
+		}
+		if f.Required {
+			return nil, errors.Errorf("required flag not provided: --%s", f.Name)
+		}
+		in.values[f.Name] = defaultValue(f.Type, f.Default)
+	}
+
+	// Positional arguments
+	for i, a := range c.Args {
+		if i >= len(in.args) {
+			if a.Required {
+				return nil, errors.Errorf("required argument not provided: %s", a.Name)
+			}
+			in.values[a.Name] = defaultValue(a.Type, a.Default)
+			continue
+		}
+		v, err := convert(a.Type, in.args[i])
+		if err != nil {
+			return nil, errors.Errorf("invalid value for argument %s: %s", a.Name, err)
+		}
+		if err := validate(a.Validate, v); err != nil {
+			return nil, errors.Errorf("invalid value for argument %s: %s", a.Name, err)
+		}
+		in.values[a.Name] = v
+		in.set[a.Name] = true
+	}
+	return in, nil
+}
+
+// Locate a flag using its long (`--name`) or short (`-n`) form.
+func (c *Command) flag(name string) *Flag {
+	name, _, _ = strings.Cut(name, "=")
+	for _, f := range c.Flags {
+		if name == "--"+f.Name || (f.Short != "" && name == "-"+f.Short) {
+			return f
+		}
+	}
+	return nil
+}
+
+// Completion entries for the flags available on the command.
+func (c *Command) flagNames() []string {
+	list := make([]string, len(c.Flags))
+	for i, f := range c.Flags {
+		list[i] = "--" + f.Name
+	}
+	if c.Exec != nil && c.flag("--output") == nil {
+		list = append(list, "--output")
+	}
+	return list
+}
+
+// Description of the flags and arguments available on the command.
+func (c *Command) paramsHelp() string {
+	sb := strings.Builder{}
+	if len(c.Args) > 0 {
+		sb.WriteString("Arguments:\n")
+		for _, a := range c.Args {
+			sb.WriteString(fmt.Sprintf("  %-20s %s%s\n", a.Name, a.Description, paramDetails(a.Type, a.Default, a.Required)))
+		}
+	}
+	if len(c.Flags) > 0 {
+		sb.WriteString("Flags:\n")
+		for _, f := range c.Flags {
+			name := "--" + f.Name
+			if f.Short != "" {
+				name = fmt.Sprintf("-%s, %s", f.Short, name)
+			}
+			sb.WriteString(fmt.Sprintf("  %-20s %s%s\n", name, f.Description, paramDetails(f.Type, f.Default, f.Required)))
+		}
+	}
+	return sb.String()
+}
+
+func paramDetails(vt ValueType, def interface{}, required bool) string {
+	if vt == "" {
+		vt = TypeString
+	}
+	details := []string{string(vt)}
+	if required {
+		details = append(details, "required")
+	}
+	if def != nil {
+		details = append(details, fmt.Sprintf("default: %v", def))
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+}
+
+// Parse a raw value into the expected type.
+func convert(vt ValueType, value string) (interface{}, error) {
+	switch vt {
+	case TypeInt:
+		return strconv.Atoi(value)
+	case TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	case TypeBool:
+		return strconv.ParseBool(value)
+	case TypeDuration:
+		return time.ParseDuration(value)
+	case "", TypeString:
+		return value, nil
+	default:
+		return nil, errors.Errorf("unsupported type: %s", vt)
+	}
+}
+
+// Value used for parameters not provided; the zero value of the type
+// is used when no default is available.
+func defaultValue(vt ValueType, def interface{}) interface{} {
+	if def != nil {
+		return def
+	}
+	switch vt {
+	case TypeInt:
+		return 0
+	case TypeFloat:
+		return float64(0)
+	case TypeBool:
+		return false
+	case TypeDuration:
+		return time.Duration(0)
+	default:
+		return ""
+	}
+}
+
+func validate(fn func(interface{}) error, value interface{}) error {
+	if fn == nil {
+		return nil
+	}
+	return fn(value)
+}
+
+func isFlag(tk string) bool {
+	return len(tk) > 1 && strings.HasPrefix(tk, "-")
+}
+
+func isNumber(tk string) bool {
+	_, err := strconv.ParseFloat(tk, 64)
+	return err == nil
+}
+
+// Split a raw input line into individual values. Values can be quoted
+// using single or double quotes to include whitespace, and a backslash
+// can be used to escape the next character.
+func tokenize(line string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quote   rune
+		escaped bool
+		inToken bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t':
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quoted value")
+	}
+	if escaped {
+		return nil, errors.New("incomplete escape sequence")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
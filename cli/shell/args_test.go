@@ -0,0 +1,85 @@
+package shell
+
+import (
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestTokenize(t *testing.T) {
+	assert := tdd.New(t)
+	tokens, err := tokenize(`set  "display name" 'it''s' a\ b "say \"hi\""`)
+	assert.Nil(err)
+	assert.Equal([]string{"set", "display name", "its", "a b", `say "hi"`}, tokens)
+	_, err = tokenize(`"unterminated`)
+	assert.NotNil(err)
+}
+
+func TestParseInput(t *testing.T) {
+	assert := tdd.New(t)
+	cmd := &Command{
+		Name: "deploy",
+		Flags: []*Flag{
+			{Name: "replicas", Short: "r", Type: TypeInt, Default: 1, Validate: func(v interface{}) error {
+				if v.(int) < 1 {
+					return errors.New("must be positive")
+				}
+				return nil
+			}},
+			{Name: "timeout", Type: TypeDuration, Default: time.Minute},
+			{Name: "force", Type: TypeBool},
+			{Name: "region", Required: true},
+		},
+		Args: []*Arg{
+			{Name: "service", Required: true},
+			{Name: "ratio", Type: TypeFloat, Default: 1.0},
+		},
+	}
+
+	in, err := cmd.parse(`api -r 3 --force --region=eu-west "0.5" extra`)
+	assert.Nil(err)
+	assert.Equal(3, in.Int("replicas"))
+	assert.Equal(time.Minute, in.Duration("timeout"))
+	assert.True(in.Bool("force"))
+	assert.Equal("eu-west", in.String("region"))
+	assert.Equal("api", in.String("service"))
+	assert.Equal(0.5, in.Float("ratio"))
+	assert.Equal([]string{"api", "0.5", "extra"}, in.Args())
+	assert.True(in.IsSet("replicas"))
+	assert.False(in.IsSet("timeout"))
+
+	// negative numbers and explicit end of flags
+	in, err = cmd.parse(`--region us -- -1 2 --force`)
+	assert.Nil(err)
+	assert.Equal("-1", in.String("service"))
+	assert.Equal(2.0, in.Float("ratio"))
+	assert.False(in.Bool("force"))
+	assert.Equal([]string{"-1", "2", "--force"}, in.Args())
+
+	// errors
+	invalid := []string{
+		`api`,                             // required flag
+		`--region eu`,                     // required argument
+		`api --region eu --unknown`,       // unknown flag
+		`api --region eu --replicas`,      // missing value
+		`api --region eu --replicas two`,  // invalid type
+		`api --region eu --replicas 0`,    // validation
+		`api --region eu --force=maybe`,   // invalid bool
+		`api --region eu --timeout 5 min`, // invalid duration
+	}
+	for _, line := range invalid {
+		_, err = cmd.parse(line)
+		assert.NotNil(err, line)
+	}
+
+	// execution
+	cmd.Exec = func(in *Input) (string, error) {
+		return in.String("service"), nil
+	}
+	res, err := cmd.execute("web --region eu", &Input{format: OutputText})
+	assert.Nil(err)
+	assert.Equal("web", res)
+	assert.Contains(cmd.help(), "--replicas")
+}
@@ -1,14 +1,20 @@
 package shell
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/chzyer/readline"
 )
 
 // Command provides a mechanism to add functionality to a shell instance.
+// Commands can be nested to build multi-level command trees; for example
+// `config set key value` will be routed to the `set` sub-command of `config`
+// with `key value` as argument.
 type Command struct {
 	// Short name for the command.
 	Name string
@@ -19,11 +25,33 @@ type Command struct {
 	// Information about how to use the command.
 	Usage string
 
-	// Method to execute when the command is invoked.
+	// Method to execute when the command is invoked. Commands without a
+	// `Run` or `Exec` method will display contextual help when invoked
+	// directly.
 	Run func(arg string) string
 
+	// Method to execute when the command is invoked, receiving the flags
+	// and arguments provided already parsed and validated. If both `Exec`
+	// and `Run` are provided, `Exec` is used.
+	Exec func(in *Input) (string, error)
+
+	// Named parameters accepted by the command.
+	Flags []*Flag
+
+	// Positional arguments accepted by the command.
+	Args []*Arg
+
+	// Pass the input to the `Exec` method without processing flags and
+	// arguments; the content is available as `Input.Raw`. Useful for
+	// commands receiving free-form input, like JSON documents.
+	RawInput bool
+
 	// Sub-commands available, if any.
 	SubCommands []*Command
+
+	// Optional method to provide dynamic completion suggestions for the
+	// command arguments. It receives the current input line.
+	Complete func(line string) []string
 }
 
 // Build the proper auto-completer entries. It will handle nested elements as needed.
@@ -39,43 +67,99 @@ func (c *Command) getPCI() readline.PrefixCompleterInterface {
 			items = append(items, cc.getPCI())
 		}
 	}
+	if c.Complete != nil || c.Exec != nil || len(c.Flags) > 0 {
+		// the dynamic entry references itself to allow completing several
+		// flags and arguments in sequence
+		dyn := readline.PcItemDynamic(func(line string) []string {
+			list := c.flagNames()
+			if c.Complete != nil {
+				list = append(list, c.Complete(line)...)
+			}
+			return list
+		})
+		dyn.SetChildren([]readline.PrefixCompleterInterface{dyn})
+		items = append(items, dyn)
+	}
 	return readline.PcItem(c.Name, items...)
 }
 
-// Match an incoming user line with a command branch.
-func (c *Command) match(line string, sh *Instance) (bool, string) {
-	// Not a match
-	if strings.SplitN(line, " ", 2)[0] != c.Name {
-		return false, ""
+// Return the help information for a command instance.
+func (c *Command) help() string {
+	res := c.Description
+	if c.Usage != "" {
+		res += fmt.Sprintf("\nUsage: %s\n", c.Usage)
+	}
+	if params := c.paramsHelp(); params != "" {
+		res += "\n" + params
 	}
+	return res
+}
 
-	line = strings.TrimSpace(strings.Replace(line, c.Name, "", 1))
-	if c.SubCommands != nil {
-		for _, cc := range c.SubCommands {
-			if ok, res := cc.match(line, sh); ok {
-				return true, res
-			}
+// Determine if the command provides functionality to execute.
+func (c *Command) runnable() bool {
+	return c.Run != nil || c.Exec != nil
+}
+
+// Method used to execute a command with its input already resolved.
+type runner func(ctx context.Context, out io.Writer, background bool) (string, error)
+
+// Execute the command functionality using the provided input. `env` provides
+// the execution settings: context, output and default output format.
+func (c *Command) execute(arg string, env *Input) (string, error) {
+	if c.Exec == nil {
+		return c.Run(arg), nil
+	}
+	in := &Input{Raw: arg}
+	if !c.RawInput {
+		var err error
+		if in, err = c.parse(arg); err != nil {
+			return "", err
 		}
 	}
+	in.ctx = env.ctx
+	in.out = env.out
+	if in.format == "" {
+		in.format = env.format
+	}
+	return c.Exec(in)
+}
 
-	// Display help if required
-	if sh.shouldShowHelp(line) || c.Run == nil {
-		if len(c.SubCommands) > 0 {
-			sh.help(c.SubCommands)
-			return true, ""
+// Locate a command by name in the provided list.
+func lookup(list []*Command, name string) *Command {
+	for _, c := range list {
+		if c.Name == name {
+			return c
 		}
-		return true, c.help()
 	}
+	return nil
+}
 
-	// Execute command function
-	return true, c.Run(line)
+// Walk the command tree using the provided input line. Returns the deepest
+// command matched, the names used to reach it and the remaining input to be
+// used as argument. The returned command is `nil` if no top-level entry matched.
+func resolve(list []*Command, line string) (cmd *Command, path []string, rest string) {
+	name, rest := nextWord(line)
+	if cmd = lookup(list, name); cmd == nil {
+		return nil, nil, line
+	}
+	path = []string{cmd.Name}
+	for {
+		name, tail := nextWord(rest)
+		sub := lookup(cmd.SubCommands, name)
+		if sub == nil {
+			return cmd, path, rest
+		}
+		cmd = sub
+		path = append(path, sub.Name)
+		rest = tail
+	}
 }
 
-// Return the help information for a command instance.
-func (c *Command) help() string {
-	res := c.Description
-	if c.Usage != "" {
-		res += fmt.Sprintf("\nUsage: %s\n", c.Usage)
+// Split the first word from the provided input line.
+func nextWord(line string) (word string, rest string) {
+	line = strings.TrimSpace(line)
+	if i := strings.IndexFunc(line, unicode.IsSpace); i >= 0 {
+		return line[:i], strings.TrimSpace(line[i:])
 	}
-	return res
+	return line, ""
 }
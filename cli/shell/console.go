@@ -0,0 +1,130 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ServiceInvoker provides runtime access to the services exposed by an
+// RPC server; for example, using the server reflection protocol. The
+// `rpc.DynamicClient` type implements this interface.
+type ServiceInvoker interface {
+	// Services exposed by the server.
+	Services(ctx context.Context) ([]protoreflect.ServiceDescriptor, error)
+
+	// Invoke a method using its full name and a JSON-encoded request;
+	// returns the JSON-encoded response.
+	Invoke(ctx context.Context, method string, input []byte) ([]byte, error)
+}
+
+// RPCCommand returns a command providing interactive access to the services
+// exposed by an RPC server. Sub-commands are generated for every service and
+// unary method available, with auto-completion support. Requests are provided
+// as JSON documents and responses are pretty-printed.
+//
+//	rpc package.Service Method {"field": "value"}
+func RPCCommand(ctx context.Context, name string, client ServiceInvoker) (*Command, error) {
+	services, err := client.Services(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load services")
+	}
+	root := &Command{
+		Name:        name,
+		Description: "Invoke the methods exposed by the RPC server",
+		Usage:       fmt.Sprintf("%s <service> <method> [json request]", name),
+	}
+	for _, sd := range services {
+		svc := &Command{
+			Name:        string(sd.FullName()),
+			Description: fmt.Sprintf("Methods available on the %s service", sd.Name()),
+		}
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			if md.IsStreamingClient() || md.IsStreamingServer() {
+				continue // only unary methods are supported
+			}
+			svc.SubCommands = append(svc.SubCommands, rpcMethod(client, name, md))
+		}
+		if len(svc.SubCommands) > 0 {
+			root.SubCommands = append(root.SubCommands, svc)
+		}
+	}
+	return root, nil
+}
+
+// Command used to invoke an RPC method.
+func rpcMethod(client ServiceInvoker, root string, md protoreflect.MethodDescriptor) *Command {
+	method := fmt.Sprintf("%s/%s", md.Parent().FullName(), md.Name())
+	template := requestTemplate(md.Input())
+	return &Command{
+		Name:        string(md.Name()),
+		Description: fmt.Sprintf("Request: %s, response: %s", md.Input().FullName(), md.Output().FullName()),
+		Usage:       fmt.Sprintf("%s %s %s %s", root, md.Parent().FullName(), md.Name(), template),
+		RawInput:    true,
+		Complete: func(_ string) []string {
+			return []string{template}
+		},
+		Exec: func(in *Input) (string, error) {
+			res, err := client.Invoke(in.Context(), method, []byte(in.Raw))
+			if err != nil {
+				return "", err
+			}
+			if in.Format() == OutputYAML {
+				var v interface{}
+				if err := json.Unmarshal(res, &v); err != nil {
+					return "", errors.WithStack(err)
+				}
+				return in.Render(v)
+			}
+			out := bytes.NewBuffer(nil)
+			if err := json.Indent(out, res, "", "  "); err != nil {
+				return string(res), nil
+			}
+			return out.String(), nil
+		},
+	}
+}
+
+// Sample JSON request including the top-level fields of a message; used
+// as a completion hint.
+func requestTemplate(msg protoreflect.MessageDescriptor) string {
+	fields := msg.Fields()
+	entries := make([]string, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		entries = append(entries, fmt.Sprintf("%q:%s", fd.JSONName(), fieldTemplate(fd)))
+	}
+	return "{" + strings.Join(entries, ",") + "}"
+}
+
+// Placeholder value for a message field.
+func fieldTemplate(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.IsMap():
+		return "{}"
+	case fd.IsList():
+		return "[]"
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "false"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return `""`
+	case protoreflect.EnumKind:
+		return fmt.Sprintf("%q", fd.Enum().Values().Get(0).Name())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "{}"
+	case protoreflect.Int64Kind, protoreflect.Uint64Kind, protoreflect.Sint64Kind,
+		protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind:
+		return `"0"` // 64-bit integers are encoded as strings
+	default:
+		return "0"
+	}
+}
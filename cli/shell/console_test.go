@@ -0,0 +1,74 @@
+package shell
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/net/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthV1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestRPCCommand(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Server with reflection enabled
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	hs.SetServingStatus("api", healthV1.HealthCheckResponse_SERVING)
+	healthV1.RegisterHealthServer(srv, hs)
+	reflection.Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+	cc, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(err)
+	defer func() {
+		_ = cc.Close()
+	}()
+
+	// Generate commands
+	var client ServiceInvoker = rpc.NewDynamicClient(cc)
+	cmd, err := RPCCommand(context.Background(), "rpc", client)
+	assert.Nil(err)
+	assert.Len(cmd.SubCommands, 1)
+	assert.Equal("grpc.health.v1.Health", cmd.SubCommands[0].Name)
+	assert.Len(cmd.SubCommands[0].SubCommands, 1, "streaming methods are ignored")
+	assert.Equal([]string{`{"service":""}`}, cmd.SubCommands[0].SubCommands[0].Complete(""))
+
+	// Invoke methods
+	sh := &Instance{out: io.Discard, format: OutputText}
+	sh.commands = []*Command{cmd}
+	res, err := sh.match(`rpc grpc.health.v1.Health Check {"service": "api"}`)
+	assert.Nil(err)
+	assert.Equal("{\n  \"status\": \"SERVING\"\n}", res)
+	sh.format = OutputYAML
+	res, err = sh.match(`rpc grpc.health.v1.Health Check {"service": "api"}`)
+	assert.Nil(err)
+	assert.Equal("status: SERVING", res)
+	_, err = sh.match(`rpc grpc.health.v1.Health Check {"service": "unknown"}`)
+	assert.NotNil(err, "error response")
+
+	// Services not available
+	_, err = RPCCommand(context.Background(), "rpc", failingInvoker{})
+	assert.NotNil(err)
+}
+
+type failingInvoker struct {
+	ServiceInvoker
+}
+
+func (fi failingInvoker) Services(_ context.Context) ([]protoreflect.ServiceDescriptor, error) {
+	return nil, errors.New("reflection not available")
+}
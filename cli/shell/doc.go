@@ -34,5 +34,153 @@ executed when the user invokes them as part of an active interactive session.
 
 	// Start interactive session
 	sh.Start()
+
+Commands can be nested to build multi-level command trees. User input is
+routed automatically to the deepest command matched, and the remaining
+content is passed as argument to its `Run` method. Auto-completion is
+available for every level of the tree, and invoking a command without a
+`Run` method, or using a help keyword after it (or before it, e.g.
+`help config set`), displays contextual help.
+
+	sh.AddCommand(&Command{
+		Name:        "config",
+		Description: "manage configuration values",
+		SubCommands: []*Command{
+			{
+				Name:        "set",
+				Description: "adjust a configuration value",
+				Usage:       "config set key value",
+				Run: func(arg string) string {
+					// arg = "key value"
+					return ""
+				},
+			},
+		},
+	})
+
+Commands can also declare the flags and positional arguments they accept,
+including types, default values, required entries and custom validation.
+The input provided by the user is parsed and validated automatically, and
+the `Exec` method receives the structured values. Flag names are available
+for auto-completion, and included in the command help information.
+
+	sh.AddCommand(&Command{
+		Name:        "deploy",
+		Description: "deploy a new service version",
+		Args: []*Arg{
+			{Name: "service", Required: true},
+		},
+		Flags: []*Flag{
+			{Name: "replicas", Short: "r", Type: TypeInt, Default: 1},
+			{Name: "force", Type: TypeBool, Description: "skip safety checks"},
+		},
+		Exec: func(in *Input) (string, error) {
+			// deploy -r 3 --force api
+			return fmt.Sprintf("deploying %d replicas of %s", in.Int("replicas"), in.String("service")), nil
+		},
+	})
+
+The same command set can be used for automation by processing commands from
+a script file or any other reader; for example, the content piped to the
+standard input of the application. Scripts can define and reference variables,
+and processing stops on the first error encountered unless disabled with the
+`WithExitOnError` option.
+
+	script := `
+	REPLICAS=3
+	deploy --replicas $REPLICAS api
+	`
+	if err := sh.RunScript(strings.NewReader(script)); err != nil {
+		panic(err)
+	}
+
+Commands can use the provided rendering utilities to produce consistent
+results. Values can be displayed as aligned tables, JSON or YAML; the format
+is selected for the complete shell with the `WithOutputFormat` option, or for
+a single invocation using the `--output` flag. On interactive sessions, long
+results are displayed using a pager; `$PAGER` or "less" by default.
+
+	sh.AddCommand(&Command{
+		Name:        "status",
+		Description: "display the status of all services",
+		Exec: func(in *Input) (string, error) {
+			// status --output json
+			table := NewTable("SERVICE", "STATUS")
+			table.AddRow("api", "running")
+			return in.Render(table)
+		},
+	})
+
+The prompt can be built dynamically from a template, using the values
+available on the shell state store. The prompt is updated automatically
+every time the state changes; colors can be applied directly or using the
+roles defined on a theme.
+
+	sh, _ := New(
+		WithTheme(Theme{"env": "red"}),
+		WithPromptTemplate(`{{ .user }}@{{ style "env" .env }}> `),
+	)
+	sh.SetState("user", "admin")
+	sh.SetState("env", "prod") // admin@prod>
+
+When enabled with the `WithJobs` option, commands can be executed as background
+jobs by adding `&` at the end of the line. The output of background jobs is
+captured and the user is notified when they complete. Running jobs can be
+managed with the `jobs`, `wait` and `kill` built-in commands; killing a job
+cancels the context available to its `Exec` method.
+
+	» deploy api &
+	[1] started
+	» jobs
+	ID  STATUS   ELAPSED  COMMAND
+	1   running  2.5s     deploy api
+	» wait 1
+
+Middleware can be registered to customize the processing of every command
+executed by the shell; for example to provide audit logging, confirmation
+prompts for destructive commands or authorization checks.
+
+	sh.Use(
+		Confirm(func(inv *Invocation) bool {
+			return inv.Command.Name == "delete"
+		}),
+		Logging(logger),
+	)
+
+A shell can also be exposed to remote users over SSH or WebSocket connections,
+for example to provide operational consoles. A new shell instance is created
+for every session, and the setup function is used to register the commands
+available. Users must be authenticated using the hooks provided.
+
+	rs, _ := NewRemoteServer(
+		func(sh *Instance, s *Session) error {
+			// s.User, s.RemoteAddr and s.Transport are available
+			sh.AddCommand(statusCommand)
+			return nil
+		},
+		WithHostKey(hostKeyPEM),
+		WithPublicKeyAuth(authorizedKey),
+		WithWebSocketAuth(validateToken),
+		WithRemoteLogger(logger),
+	)
+	go rs.ServeSSH(sshListener)
+	http.Handle("/console", rs)
+
+Commands can also be generated automatically for the services exposed by an
+RPC server with reflection enabled, using the 'rpc.DynamicClient' type. This
+provides operators with an interactive console with auto-completion for every
+unary method available; requests are provided as JSON documents and responses
+are pretty-printed.
+
+	cmd, err := RPCCommand(ctx, "rpc", rpc.NewDynamicClient(conn))
+	if err != nil {
+		panic(err)
+	}
+	sh.AddCommand(cmd)
+
+	» rpc grpc.health.v1.Health Check {"service": "api"}
+	{
+	  "status": "SERVING"
+	}
 */
 package shell
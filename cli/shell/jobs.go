@@ -0,0 +1,227 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Status values for background jobs.
+const (
+	jobRunning = "running"
+	jobDone    = "done"
+	jobFailed  = "failed"
+	jobKilled  = "killed"
+)
+
+// Command executing in the background.
+type job struct {
+	id       int
+	line     string
+	started  time.Time
+	finished time.Time
+	status   string
+	out      *syncBuffer
+	cancel   context.CancelFunc
+	done     chan struct{}
+	mu       sync.Mutex
+}
+
+// Current job status.
+func (j *job) getStatus() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// Time spent running the job.
+func (j *job) elapsed() time.Duration {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.finished.IsZero() {
+		return time.Since(j.started).Round(time.Millisecond)
+	}
+	return j.finished.Sub(j.started).Round(time.Millisecond)
+}
+
+// Registry of background jobs.
+type jobTable struct {
+	seq  int
+	list []*job
+	mu   sync.Mutex
+}
+
+func (jt *jobTable) add(line string, cancel context.CancelFunc) *job {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+	jt.seq++
+	j := &job{
+		id:      jt.seq,
+		line:    line,
+		started: time.Now(),
+		status:  jobRunning,
+		out:     new(syncBuffer),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	jt.list = append(jt.list, j)
+	return j
+}
+
+func (jt *jobTable) get(id string) (*job, error) {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "%"))
+	if err != nil {
+		return nil, errors.Errorf("invalid job id: %s", id)
+	}
+	for _, j := range jt.list {
+		if j.id == n {
+			return j, nil
+		}
+	}
+	return nil, errors.Errorf("unknown job: %s", id)
+}
+
+func (jt *jobTable) all() []*job {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+	return append([]*job{}, jt.list...)
+}
+
+// Remove finished jobs from the table.
+func (jt *jobTable) prune(j *job) {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+	for i, el := range jt.list {
+		if el == j {
+			jt.list = append(jt.list[:i], jt.list[i+1:]...)
+			return
+		}
+	}
+}
+
+// Start the command on the provided line as a background job.
+func (sh *Instance) startJob(line string) (string, error) {
+	run, err := sh.prepare(line)
+	if run == nil || err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	j := sh.jobs.add(line, cancel)
+	go func() {
+		res, err := run(ctx, j.out, true)
+		if res != "" {
+			_, _ = fmt.Fprintln(j.out, res)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(j.out, "error: %s\n", err)
+		}
+
+		j.mu.Lock()
+		j.finished = time.Now()
+		switch {
+		case j.status == jobKilled:
+		case err != nil:
+			j.status = jobFailed
+		default:
+			j.status = jobDone
+		}
+		status := j.status
+		j.mu.Unlock()
+		cancel()
+		close(j.done)
+		sh.notify(fmt.Sprintf("[%d] %s: %s", j.id, status, j.line))
+	}()
+	return fmt.Sprintf("[%d] started", j.id), nil
+}
+
+// Process the built-in commands used to manage background jobs. Returns
+// `false` if the line doesn't correspond to a built-in command.
+func (sh *Instance) jobCommand(line string) (bool, string, error) {
+	name, arg := nextWord(line)
+	switch name {
+	case "jobs":
+		table := NewTable("ID", "STATUS", "ELAPSED", "COMMAND")
+		for _, j := range sh.jobs.all() {
+			table.AddRow(j.id, j.getStatus(), j.elapsed(), j.line)
+		}
+		return true, table.String(), nil
+	case "wait":
+		res, err := sh.waitJobs(arg)
+		return true, res, err
+	case "kill":
+		if arg == "" {
+			return true, "", errors.New("a job id is required")
+		}
+		j, err := sh.jobs.get(arg)
+		if err != nil {
+			return true, "", err
+		}
+		j.mu.Lock()
+		if j.status == jobRunning {
+			j.status = jobKilled
+		}
+		j.mu.Unlock()
+		j.cancel()
+		return true, "", nil
+	default:
+		return false, "", nil
+	}
+}
+
+// Wait for a job, or all jobs if no id is provided, to finish. The output
+// captured for completed jobs is returned and the jobs are removed from
+// the table.
+func (sh *Instance) waitJobs(id string) (string, error) {
+	list := sh.jobs.all()
+	if id != "" {
+		j, err := sh.jobs.get(id)
+		if err != nil {
+			return "", err
+		}
+		list = []*job{j}
+	}
+	sb := new(strings.Builder)
+	for _, j := range list {
+		<-j.done
+		sb.WriteString(fmt.Sprintf("[%d] %s: %s\n", j.id, j.getStatus(), j.line))
+		sb.WriteString(j.out.String())
+		sh.jobs.prune(j)
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// Report asynchronous events to the user without disrupting the
+// line currently being edited.
+func (sh *Instance) notify(msg string) {
+	if sh.rl != nil {
+		_, _ = fmt.Fprintln(sh.rl.Stdout(), msg)
+		return
+	}
+	sh.Print(msg)
+}
+
+// Buffer safe for concurrent use.
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
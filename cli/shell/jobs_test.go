@@ -0,0 +1,59 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestJobs(t *testing.T) {
+	assert := tdd.New(t)
+	sh := &Instance{out: io.Discard, jobs: new(jobTable)}
+	sh.commands = []*Command{
+		{
+			Name: "sleep",
+			Args: []*Arg{{Name: "duration", Type: TypeDuration}},
+			Exec: func(in *Input) (string, error) {
+				_, _ = fmt.Fprintln(in.Output(), "sleeping")
+				select {
+				case <-time.After(in.Duration("duration")):
+					return "awake", nil
+				case <-in.Context().Done():
+					return "", in.Context().Err()
+				}
+			},
+		},
+	}
+
+	// Start jobs
+	_, res, err := sh.process("sleep 10ms &")
+	assert.Nil(err)
+	assert.Equal("[1] started", res)
+	_, res, _ = sh.process("sleep 1m &")
+	assert.Equal("[2] started", res)
+	_, _, err = sh.process("invalid &")
+	assert.NotNil(err, "invalid command")
+
+	// List and wait for completion
+	_, res, _ = sh.process("jobs")
+	assert.Contains(res, "sleep 1m")
+	_, res, err = sh.process("wait 1")
+	assert.Nil(err)
+	assert.Equal("[1] done: sleep 10ms\nsleeping\nawake", res)
+
+	// Kill
+	_, _, err = sh.process("kill")
+	assert.NotNil(err, "missing id")
+	_, _, err = sh.process("kill 5")
+	assert.NotNil(err, "unknown job")
+	_, _, err = sh.process("kill 2")
+	assert.Nil(err)
+	_, res, _ = sh.process("wait")
+	assert.Contains(res, "[2] killed: sleep 1m")
+	assert.Contains(res, "context canceled")
+	_, res, _ = sh.process("jobs")
+	assert.Equal("ID  STATUS  ELAPSED  COMMAND", res)
+}
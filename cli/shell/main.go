@@ -1,11 +1,14 @@
 package shell
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 	"sync"
+	"text/template"
 
 	"github.com/chzyer/readline"
 	"go.bryk.io/pkg/errors"
@@ -14,14 +17,18 @@ import (
 // Instance defines the main interface for an interactive shell.
 type Instance struct {
 	// Settings
-	prompt       string   // CLI prompt value used by the shell
-	historyFile  string   // Store a history of tasks run in the shell
-	historyLimit int      // Maximum number of items to store in the shell history, set to 0 to disable it
-	startMessage string   // Message printed just after the shell is started
-	exitMessage  string   // Message printed just before the shell is closed
-	exitCommands []string // Reserved keywords to let the user close a running shell instance
-	helpMessage  string   // Message printed along the command list for the user
-	helpCommands []string // Reserved keywords to present a list of available top commands to the user
+	prompt       string    // CLI prompt value used by the shell
+	historyFile  string    // Store a history of tasks run in the shell
+	historyLimit int       // Maximum number of items to store in the shell history, set to 0 to disable it
+	startMessage string    // Message printed just after the shell is started
+	exitMessage  string    // Message printed just before the shell is closed
+	exitCommands []string  // Reserved keywords to let the user close a running shell instance
+	helpMessage  string    // Message printed along the command list for the user
+	helpCommands []string  // Reserved keywords to present a list of available top commands to the user
+	out          io.Writer // Destination for the shell output
+	exitOnError  bool      // Stop script processing on the first error encountered
+	format       string    // Default output format for command results
+	pager        string    // Command used to display long results, empty to disable
 
 	// Hooks
 	startHook Hook // Custom functionality to run before the shell instance is started
@@ -29,10 +36,18 @@ type Instance struct {
 	resetHook Hook // Custom functionality to run just after the shell state is reset
 
 	// Internal elements
-	mu       sync.Mutex
-	rl       *readline.Instance
-	cfg      *readline.Config
-	commands []*Command
+	mu        sync.Mutex
+	rl        *readline.Instance
+	cfg       *readline.Config
+	commands  []*Command
+	vars      map[string]string
+	state     map[string]interface{}
+	theme     Theme
+	promptTpl *template.Template
+	jobs      *jobTable
+	mw        []Middleware
+	session   *Session
+	scripts   int // scripts being processed, commands can't be interactive
 }
 
 // New ready-to-use interactive shell instance based on the provided configuration options.
@@ -43,6 +58,10 @@ func New(options ...Option) (*Instance, error) {
 		exitCommands: []string{"exit", "bye"},
 		helpCommands: []string{"?", "help"},
 		historyLimit: 0,
+		out:          os.Stdout,
+		exitOnError:  true,
+		format:       OutputText,
+		pager:        defaultPager(),
 	}
 
 	// Apply provided settings
@@ -51,10 +70,17 @@ func New(options ...Option) (*Instance, error) {
 	}
 
 	// Prepare internals
+	sh.mu.Lock()
+	sh.refreshPrompt()
+	sh.mu.Unlock()
 	conf := &readline.Config{
 		Prompt:       sh.prompt,
 		HistoryFile:  sh.historyFile,
 		HistoryLimit: sh.historyLimit,
+		Stdout:       sh.out,
+	}
+	if sh.session != nil {
+		sh.session.configure(conf)
 	}
 	rl, err := readline.NewEx(conf)
 	if err != nil {
@@ -140,7 +166,7 @@ func (sh *Instance) SetResetHook(hk Hook) {
 
 // Print will add content to the shell's main output.
 func (sh *Instance) Print(line string) {
-	fmt.Println(line)
+	_, _ = fmt.Fprintln(sh.out, line)
 }
 
 // AddCommand will register a command with the shell instance and update the autocomplete
@@ -188,17 +214,13 @@ func (sh *Instance) Start() {
 		sh.startHook()
 	}
 
-	sh.mu.Lock()
-	exitCommands := sh.exitCommands
-	sh.mu.Unlock()
-
 	// Show start message
 	if sh.startMessage != "" {
-		fmt.Println(sh.startMessage)
+		sh.Print(sh.startMessage)
 	}
 
 	// Show help instructions
-	fmt.Printf("For help use: %s\n", strings.Join(sh.helpCommands, ", "))
+	sh.Print(fmt.Sprintf("For help use: %s", strings.Join(sh.helpCommands, ", ")))
 
 	// Start read-line processing
 	for {
@@ -208,30 +230,58 @@ func (sh *Instance) Start() {
 			return
 		}
 
-		// check if line is empty
-		line = strings.TrimSpace(line)
-		if len(line) == 0 {
-			continue
+		// Process user input
+		exit, res, err := sh.process(line)
+		if exit {
+			return
 		}
-
-		// Look for exit commands
-		for _, ec := range exitCommands {
-			if line == ec {
-				return
-			}
+		if res != "" {
+			sh.display(res)
+		}
+		if err != nil {
+			sh.Print(fmt.Sprintf("error: %s", err))
 		}
+	}
+}
+
+// Process a single line of user input. Returns `true` if the line
+// corresponds to one of the exit commands, and the command results.
+func (sh *Instance) process(line string) (exit bool, res string, err error) {
+	// check if line is empty
+	line = strings.TrimSpace(line)
+	if len(line) == 0 {
+		return false, "", nil
+	}
 
-		// Look for help commands
-		if sh.shouldShowHelp(line) {
-			sh.help(sh.commands)
-			continue
+	// Look for exit commands
+	sh.mu.Lock()
+	exitCommands := sh.exitCommands
+	sh.mu.Unlock()
+	for _, ec := range exitCommands {
+		if line == ec {
+			return true, "", nil
 		}
+	}
+
+	// Look for help commands, optionally followed by a command path
+	if word, rest := nextWord(line); sh.shouldShowHelp(word) {
+		return false, "", sh.showHelp(rest)
+	}
 
-		// Execute the requested command
-		if ok := sh.match(line); !ok {
-			fmt.Println("unrecognized command: ", line)
+	// Background jobs
+	if sh.jobs != nil {
+		if strings.HasSuffix(line, "&") {
+			res, err = sh.startJob(strings.TrimSpace(strings.TrimSuffix(line, "&")))
+			return false, res, err
+		}
+		if ok, res, err := sh.jobCommand(line); ok {
+			return false, res, err
 		}
 	}
+
+	// Execute the requested command
+	res, err = sh.match(line)
+	return false, res, err
 }
 
 // Apply provided configuration options.
@@ -252,7 +302,7 @@ func (sh *Instance) close() error {
 		sh.stopHook()
 	}
 	if sh.exitMessage != "" {
-		fmt.Println(sh.exitMessage)
+		_, _ = fmt.Fprintln(sh.out, sh.exitMessage)
 	}
 	return errors.WithStack(sh.rl.Close())
 }
@@ -277,6 +327,9 @@ func (sh *Instance) updateAutoComplete() {
 	for i, c := range sh.commands {
 		items[i] = c.getPCI()
 	}
+	if sh.jobs != nil {
+		items = append(items, readline.PcItem("jobs"), readline.PcItem("wait"), readline.PcItem("kill"))
+	}
 	if len(items) > 0 {
 		completer := readline.NewPrefixCompleter(items...)
 		conf := sh.cfg
@@ -296,47 +349,138 @@ func (sh *Instance) shouldShowHelp(line string) (ok bool) {
 	return
 }
 
-// Show help information for a command branch.
-func (sh *Instance) help(cmd []*Command) {
+// Show help information for a command branch. Entries are displayed using
+// the provided prefix, if any.
+func (sh *Instance) help(cmd []*Command, prefix string) {
 	if len(cmd) > 0 {
 		// Sort command entries by name
 		sort.Slice(cmd, func(i, j int) bool {
 			return cmd[i].Name < cmd[j].Name
 		})
+		if prefix != "" {
+			prefix += " "
+		}
 
 		// Get proper command template
 		padding := 0
 		for _, c := range cmd {
-			if len(c.Name) > padding {
-				padding = len(c.Name)
+			if len(prefix+c.Name) > padding {
+				padding = len(prefix + c.Name)
 			}
 		}
 		tpl := fmt.Sprintf("  %%-%ds %%s\n", padding+4)
 
 		// Print commands list
-		fmt.Println("Available commands: ")
+		_, _ = fmt.Fprintln(sh.out, "Available commands: ")
 		for _, c := range cmd {
-			fmt.Printf(tpl, c.Name, c.Description)
+			_, _ = fmt.Fprintf(sh.out, tpl, prefix+c.Name, c.Description)
 		}
-		fmt.Println("")
+		_, _ = fmt.Fprintln(sh.out, "")
 	}
 	if sh.helpMessage != "" {
-		fmt.Println(sh.helpMessage)
+		sh.Print(sh.helpMessage)
+	}
+	if sh.jobs != nil {
+		sh.Print("To run a command in the background add '&' at the end; manage jobs with: jobs, wait, kill")
 	}
-	fmt.Printf("To close the session use: %s\n", strings.Join(sh.exitCommands, ", "))
+	sh.Print(fmt.Sprintf("To close the session use: %s", strings.Join(sh.exitCommands, ", ")))
 }
 
-// Match an incoming user line with a proper command to execute.
-func (sh *Instance) match(line string) (ok bool) {
-	// Iterate command branches looking for a proper match
-	for _, c := range sh.commands {
-		if m, res := c.match(line, sh); m {
-			if res != "" {
-				sh.Print(res)
-			}
-			ok = true
-			break
-		}
+// Show help information for the command path provided, or the list of
+// top-level commands if empty.
+func (sh *Instance) showHelp(line string) error {
+	sh.mu.Lock()
+	commands := sh.commands
+	sh.mu.Unlock()
+	if line == "" {
+		sh.help(commands, "")
+		return nil
 	}
-	return
+	cmd, path, _ := resolve(commands, line)
+	if cmd == nil {
+		return errors.Errorf("unrecognized command: %s", line)
+	}
+	sh.commandHelp(cmd, path)
+	return nil
+}
+
+// Show contextual help for a specific command in the tree.
+func (sh *Instance) commandHelp(cmd *Command, path []string) {
+	if len(cmd.SubCommands) == 0 {
+		sh.Print(cmd.help())
+		return
+	}
+	if cmd.Description != "" {
+		sh.Print(cmd.Description)
+	}
+	if cmd.Usage != "" {
+		sh.Print(fmt.Sprintf("Usage: %s", cmd.Usage))
+	}
+	sh.help(cmd.SubCommands, strings.Join(path, " "))
+}
+
+// Match an incoming user line with a proper command to execute. The line
+// is routed to the deepest command matched in the tree; the remaining
+// content is passed as argument. Returns the command results.
+func (sh *Instance) match(line string) (string, error) {
+	run, err := sh.prepare(line)
+	if run == nil || err != nil {
+		return "", err
+	}
+	return run(context.Background(), sh.out, false)
+}
+
+// Whether the user can be prompted for input; i.e., an interactive session
+// is available and no script is being processed.
+func (sh *Instance) interactive() bool {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.rl != nil && sh.scripts == 0
+}
+
+// Locate the command to execute for an incoming user line. Returns `nil`
+// if no execution is required, for example when displaying help.
+func (sh *Instance) prepare(line string) (runner, error) {
+	sh.mu.Lock()
+	commands := sh.commands
+	format := sh.format
+	mw := sh.mw
+	sh.mu.Unlock()
+
+	cmd, path, arg := resolve(commands, line)
+	if cmd == nil {
+		return nil, errors.Errorf("unrecognized command: %s", line)
+	}
+
+	// Display contextual help if required
+	if sh.shouldShowHelp(arg) || (!cmd.runnable() && arg == "") {
+		sh.commandHelp(cmd, path)
+		return nil, nil
+	}
+
+	// Invalid sub-command for a branch without functionality
+	if !cmd.runnable() {
+		word, _ := nextWord(arg)
+		return nil, errors.Errorf("unrecognized command: %s %s", strings.Join(path, " "), word)
+	}
+
+	// Execute command function, applying middleware
+	var handler Handler = func(inv *Invocation) (string, error) {
+		return cmd.execute(inv.Arg, &Input{ctx: inv.ctx, out: inv.out, format: format})
+	}
+	for _, m := range mw {
+		handler = m(handler)
+	}
+	return func(ctx context.Context, w io.Writer, background bool) (string, error) {
+		return handler(&Invocation{
+			Command:     cmd,
+			Name:        strings.Join(path, " "),
+			Arg:         arg,
+			Background:  background,
+			Interactive: !background && sh.interactive(),
+			Shell:       sh,
+			ctx:         ctx,
+			out:         w,
+		})
+	}, nil
 }
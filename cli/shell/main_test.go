@@ -140,6 +140,64 @@ func TestInstance(t *testing.T) {
 	assert.Nil(sh.close(), "shell close")
 }
 
+func TestCommandTree(t *testing.T) {
+	assert := tdd.New(t)
+
+	var received []string
+	record := func(name string) func(string) string {
+		return func(arg string) string {
+			received = append(received, fmt.Sprintf("%s:%s", name, arg))
+			return ""
+		}
+	}
+	sh := &Instance{helpCommands: []string{"help"}, out: io.Discard}
+	sh.commands = []*Command{
+		{
+			Name:        "config",
+			Description: "Manage configuration values",
+			SubCommands: []*Command{
+				{Name: "set", Run: record("set")},
+				{Name: "get", Run: record("get")},
+				{
+					Name: "profile",
+					Run:  record("profile"),
+					SubCommands: []*Command{
+						{Name: "use", Run: record("use")},
+					},
+				},
+			},
+		},
+	}
+
+	// Routing
+	assert.Nil(matchErr(sh, "config set key   value"))
+	assert.Nil(matchErr(sh, "config  get key"))
+	assert.Nil(matchErr(sh, "config profile use prod"))
+	assert.Nil(matchErr(sh, "config profile list"))
+	assert.Equal([]string{"set:key   value", "get:key", "use:prod", "profile:list"}, received)
+
+	// Contextual help and invalid entries
+	received = nil
+	assert.Nil(matchErr(sh, "config"), "branch without run method")
+	assert.Nil(matchErr(sh, "config set help"), "help for a sub-command")
+	assert.Nil(sh.showHelp("config profile"), "help for a branch")
+	assert.NotNil(matchErr(sh, "config invalid"), "invalid sub-command")
+	assert.NotNil(matchErr(sh, "unknown set"), "invalid command")
+	assert.Empty(received)
+
+	// Resolve
+	cmd, path, arg := resolve(sh.commands, "config profile use prod eu")
+	assert.Equal("use", cmd.Name)
+	assert.Equal([]string{"config", "profile", "use"}, path)
+	assert.Equal("prod eu", arg)
+}
+
+// Process a line and return only the error, if any.
+func matchErr(sh *Instance, line string) error {
+	_, err := sh.match(line)
+	return err
+}
+
 // Start a sample shell instance using most of the available options.
 func ExampleNew() {
 	// Configure shell instance
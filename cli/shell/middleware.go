@@ -0,0 +1,133 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	xlog "go.bryk.io/pkg/log"
+)
+
+// Invocation provides details about a command being executed.
+type Invocation struct {
+	// Command being executed.
+	Command *Command
+
+	// Full name of the command, including its parents; e.g. "config set".
+	Name string
+
+	// Raw argument provided for the command.
+	Arg string
+
+	// Whether the command is executing as a background job.
+	Background bool
+
+	// Whether the user can be prompted for input while executing the
+	// command. Commands executed by scripts or as background jobs are
+	// not interactive.
+	Interactive bool
+
+	// Shell instance processing the command.
+	Shell *Instance
+
+	ctx context.Context
+	out io.Writer
+}
+
+// Context returns the context for the command execution.
+func (inv *Invocation) Context() context.Context {
+	if inv.ctx == nil {
+		return context.Background()
+	}
+	return inv.ctx
+}
+
+// Handler executes a command invocation and returns its results.
+type Handler func(inv *Invocation) (string, error)
+
+// Middleware elements allow to customize the processing of every command
+// executed by the shell; for example to provide audit logging, confirmation
+// prompts or authorization checks.
+type Middleware func(next Handler) Handler
+
+// Use registers middleware to apply on every command executed by the shell.
+// Middleware is applied in the order provided, for example:
+//
+//	Use(foo bar baz)
+//
+// Will be applied as:
+//
+//	baz( bar( foo(handler) ) )
+func (sh *Instance) Use(mw ...Middleware) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.mw = append(sh.mw, mw...)
+}
+
+// Logging reports every command executed using the provided logger. Failed
+// commands are reported at the warning level.
+func Logging(ll xlog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) (string, error) {
+			start := time.Now()
+			res, err := next(inv)
+			fields := map[string]interface{}{
+				"shell.command":    inv.Name,
+				"shell.arg":        inv.Arg,
+				"shell.background": inv.Background,
+				"duration_ms":      time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				fields["error"] = err.Error()
+				ll.WithFields(fields).Warning("command failed")
+				return res, err
+			}
+			ll.WithFields(fields).Info("command executed")
+			return res, err
+		}
+	}
+}
+
+// Confirm asks the user for confirmation before executing the commands
+// selected by `match`, useful for destructive operations. If `match` is
+// nil confirmation is required for all commands. Commands executing as
+// background jobs, or processed by scripts, are rejected since confirmation
+// can't be requested.
+func Confirm(match func(inv *Invocation) bool) Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) (string, error) {
+			if match != nil && !match(inv) {
+				return next(inv)
+			}
+			if !inv.Interactive || inv.Shell == nil {
+				return "", errors.Errorf("'%s' requires confirmation and can't run non-interactively", inv.Name)
+			}
+			answer, err := inv.Shell.ReadString(fmt.Sprintf("execute '%s'? [y/N]", inv.Name))
+			if err != nil {
+				return "", err
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return next(inv)
+			default:
+				return "", errors.New("canceled by the user")
+			}
+		}
+	}
+}
+
+// Authorize executes `check` before every command; the command is not
+// executed if an error is returned.
+func Authorize(check func(inv *Invocation) error) Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) (string, error) {
+			if err := check(inv); err != nil {
+				return "", errors.Wrap(err, "unauthorized")
+			}
+			return next(inv)
+		}
+	}
+}
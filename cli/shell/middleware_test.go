@@ -0,0 +1,92 @@
+package shell
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	xlog "go.bryk.io/pkg/log"
+)
+
+func TestMiddleware(t *testing.T) {
+	assert := tdd.New(t)
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(inv *Invocation) (string, error) {
+				calls = append(calls, name+":"+inv.Name)
+				return next(inv)
+			}
+		}
+	}
+	sh := &Instance{out: io.Discard, jobs: new(jobTable)}
+	sh.commands = []*Command{
+		{
+			Name: "users",
+			SubCommands: []*Command{
+				{Name: "list", Run: func(_ string) string { return "ok" }},
+				{Name: "delete", Run: func(arg string) string { return "deleted " + arg }},
+			},
+		},
+	}
+	assert.Nil(sh.setup(WithMiddleware(trace("foo"), trace("bar"))))
+	sh.Use(
+		Confirm(func(inv *Invocation) bool {
+			return inv.Command.Name == "delete"
+		}),
+		Authorize(func(inv *Invocation) error {
+			if inv.Arg == "admin" {
+				return errors.New("protected user")
+			}
+			return nil
+		}),
+		Logging(xlog.Discard()),
+	)
+
+	// Middleware is applied in order
+	res, err := sh.match("users list")
+	assert.Nil(err)
+	assert.Equal("ok", res)
+	assert.Equal([]string{"bar:users list", "foo:users list"}, calls)
+
+	// Authorization
+	_, err = sh.match("users delete admin")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "unauthorized")
+
+	// Confirmation is not possible for background jobs
+	_, _, err = sh.process("users delete guest &")
+	assert.Nil(err)
+	res, _ = sh.waitJobs("")
+	assert.Contains(res, "requires confirmation")
+}
+
+func TestConfirmScript(t *testing.T) {
+	assert := tdd.New(t)
+	executed := false
+	sh, err := New(
+		WithOutput(io.Discard),
+		WithExitOnError(true),
+		WithMiddleware(Confirm(nil)),
+	)
+	assert.Nil(err)
+	defer func() {
+		_ = sh.rl.Close()
+	}()
+	sh.AddCommand(&Command{
+		Name: "purge",
+		Run: func(_ string) string {
+			executed = true
+			return "done"
+		},
+	})
+
+	// Confirmation can't be requested for commands processed by scripts
+	err = sh.RunScript(strings.NewReader("purge\ny\n"))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "requires confirmation")
+	assert.False(executed)
+	assert.True(sh.interactive(), "interactive mode is restored")
+}
@@ -1,5 +1,11 @@
 package shell
 
+import (
+	"io"
+
+	"go.bryk.io/pkg/errors"
+)
+
 // Option provides a functional method to adjust the settings on a shell instance.
 type Option func(*Instance) error
 
@@ -17,6 +23,27 @@ func WithPrompt(p string) Option {
 	}
 }
 
+// WithPromptTemplate set a template used to build the CLI prompt, based on
+// the values available on the shell state. The prompt is updated automatically
+// when the state changes. Refer to `SetPromptTemplate` for more information.
+func WithPromptTemplate(tpl string) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		return sh.parsePrompt(tpl)
+	}
+}
+
+// WithTheme set the colors used for the roles referenced on the prompt template.
+func WithTheme(theme Theme) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.theme = theme
+		return nil
+	}
+}
+
 // WithHistoryFile adjust the location to store a log of tasks executed in the shell.
 func WithHistoryFile(hf string) Option {
 	return func(sh *Instance) error {
@@ -119,3 +146,86 @@ func WithResetHook(hook Hook) Option {
 		return nil
 	}
 }
+
+// WithOutput set the destination for the content produced by the shell.
+// Defaults to `os.Stdout`.
+func WithOutput(w io.Writer) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.out = w
+		return nil
+	}
+}
+
+// WithExitOnError adjust whether script processing stops on the first error
+// encountered. Enabled by default; when disabled, errors are reported to the
+// shell output and processing continues with the next command.
+func WithExitOnError(enabled bool) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.exitOnError = enabled
+		return nil
+	}
+}
+
+// WithOutputFormat set the default format used for command results; supported
+// values are `text`, `json` and `yaml`. The format can also be adjusted for a
+// single invocation using the `--output` flag.
+func WithOutputFormat(format string) Option {
+	return func(sh *Instance) error {
+		if !validFormat(format) {
+			return errors.Errorf("invalid output format: '%s'", format)
+		}
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.format = format
+		return nil
+	}
+}
+
+// WithPager set the command used to display long results on interactive
+// sessions, for example "less -R". By default, the value of the `PAGER`
+// environment variable is used, or "less -FRX" if not set. Provide an
+// empty value to disable paging.
+func WithPager(cmd string) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.pager = cmd
+		return nil
+	}
+}
+
+// WithJobs enable the execution of commands as background jobs. A command
+// is started in the background by adding `&` at the end of the line; the
+// following built-in commands are available to manage running jobs:
+//   - jobs: list registered jobs and their status
+//   - wait [id]: wait for a job, or all of them, to finish and display its output
+//   - kill <id>: cancel the context of a running job
+func WithJobs() Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.jobs = new(jobTable)
+		return nil
+	}
+}
+
+// WithMiddleware registers middleware to apply on every command executed by
+// the shell. Middleware is applied in the order provided, for example:
+//
+//	WithMiddleware(foo bar baz)
+//
+// Will be applied as:
+//
+//	baz( bar( foo(handler) ) )
+func WithMiddleware(mw ...Middleware) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.mw = append(sh.mw, mw...)
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats available for command results. The format can be adjusted
+// for the complete shell using the `WithOutputFormat` option, or for a single
+// invocation using the `--output` flag on commands with an `Exec` method.
+const (
+	// OutputText renders values in a human-readable form; tables are
+	// displayed as aligned columns.
+	OutputText = "text"
+
+	// OutputJSON renders values as indented JSON documents.
+	OutputJSON = "json"
+
+	// OutputYAML renders values as YAML documents.
+	OutputYAML = "yaml"
+)
+
+// Table provides an aligned, column-based, representation of data. When
+// rendered as JSON or YAML, each row is encoded as an object using the
+// headers as keys.
+type Table struct {
+	// Column names.
+	Headers []string
+
+	// Data entries; each row should include a value for every column.
+	Rows [][]string
+}
+
+// NewTable returns an empty table with the provided column names.
+func NewTable(headers ...string) *Table {
+	return &Table{Headers: headers}
+}
+
+// AddRow registers a new data entry on the table. Values are converted to
+// their default string representation.
+func (t *Table) AddRow(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = fmt.Sprint(v)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// String returns the table content using aligned columns.
+func (t *Table) String() string {
+	sb := new(strings.Builder)
+	tw := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+	if len(t.Headers) > 0 {
+		_, _ = fmt.Fprintln(tw, strings.Join(t.Headers, "\t"))
+	}
+	for _, row := range t.Rows {
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// MarshalJSON encodes the table rows as a list of objects.
+func (t *Table) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.records())
+}
+
+// MarshalYAML encodes the table rows as a list of objects.
+func (t *Table) MarshalYAML() (interface{}, error) {
+	return t.records(), nil
+}
+
+func (t *Table) records() []map[string]string {
+	list := make([]map[string]string, len(t.Rows))
+	for i, row := range t.Rows {
+		rec := make(map[string]string, len(t.Headers))
+		for j, h := range t.Headers {
+			if j < len(row) {
+				rec[h] = row[j]
+			}
+		}
+		list[i] = rec
+	}
+	return list
+}
+
+// Render returns the representation of `v` in the requested output format.
+// In text format strings and `fmt.Stringer` values, like tables, are used
+// as-is and other values use their default representation.
+func Render(v interface{}, format string) (string, error) {
+	switch format {
+	case OutputJSON:
+		out, err := json.MarshalIndent(v, "", "  ")
+		return string(out), errors.WithStack(err)
+	case OutputYAML:
+		out, err := yaml.Marshal(v)
+		return strings.TrimSuffix(string(out), "\n"), errors.WithStack(err)
+	case "", OutputText:
+		switch val := v.(type) {
+		case string:
+			return val, nil
+		case fmt.Stringer:
+			return val.String(), nil
+		default:
+			return fmt.Sprintf("%+v", v), nil
+		}
+	default:
+		return "", errors.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Verify the provided value is a supported output format.
+func validFormat(format string) bool {
+	return format == OutputText || format == OutputJSON || format == OutputYAML
+}
+
+// Display command results on interactive sessions. Long content is sent
+// to the pager, if enabled and the output is a terminal.
+func (sh *Instance) display(res string) {
+	sh.mu.Lock()
+	pager := sh.pager
+	sh.mu.Unlock()
+
+	f, ok := sh.out.(*os.File)
+	if pager == "" || !ok || !term.IsTerminal(int(f.Fd())) {
+		sh.Print(res)
+		return
+	}
+	if _, height, err := term.GetSize(int(f.Fd())); err != nil || strings.Count(res, "\n") < height-1 {
+		sh.Print(res)
+		return
+	}
+	cmd := exec.Command("sh", "-c", pager) // nolint:gosec
+	cmd.Stdin = strings.NewReader(res + "\n")
+	cmd.Stdout = f
+	if err := cmd.Run(); err != nil {
+		sh.Print(res) // pager not available
+	}
+}
+
+// Default pager used for long content.
+func defaultPager() string {
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+	return "less -FRX"
+}
@@ -0,0 +1,50 @@
+package shell
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	assert := tdd.New(t)
+	table := NewTable("NAME", "STATUS")
+	table.AddRow("api", "running")
+	table.AddRow("worker-pool", 3)
+
+	// Text
+	out, err := Render(table, OutputText)
+	assert.Nil(err)
+	assert.Equal("NAME         STATUS\napi          running\nworker-pool  3", out)
+
+	// JSON
+	out, err = Render(table, OutputJSON)
+	assert.Nil(err)
+	assert.JSONEq(`[{"NAME":"api","STATUS":"running"},{"NAME":"worker-pool","STATUS":"3"}]`, out)
+
+	// YAML
+	out, err = Render(map[string]int{"replicas": 3}, OutputYAML)
+	assert.Nil(err)
+	assert.Equal("replicas: 3", out)
+
+	// Invalid format
+	_, err = Render(table, "xml")
+	assert.NotNil(err)
+
+	// Global output flag
+	cmd := &Command{
+		Name: "status",
+		Exec: func(in *Input) (string, error) {
+			return in.Render(table)
+		},
+	}
+	out, err = cmd.execute("--output json", &Input{format: OutputText})
+	assert.Nil(err)
+	assert.JSONEq(`[{"NAME":"api","STATUS":"running"},{"NAME":"worker-pool","STATUS":"3"}]`, out)
+	out, err = cmd.execute("", &Input{format: OutputYAML})
+	assert.Nil(err)
+	assert.Contains(out, "- NAME: api")
+	_, err = cmd.execute("--output=xml", &Input{format: OutputText})
+	assert.NotNil(err)
+	assert.Contains(cmd.flagNames(), "--output")
+}
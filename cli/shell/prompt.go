@@ -0,0 +1,129 @@
+package shell
+
+import (
+	"strings"
+	"text/template"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// ANSI escape sequences for the colors available on prompt templates.
+var ansiColors = map[string]string{
+	"black":   "\033[30m",
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+	"gray":    "\033[90m",
+	"bold":    "\033[1m",
+}
+
+// Reset all text attributes.
+const ansiReset = "\033[0m"
+
+// Theme assigns colors to semantic roles used on prompt templates; for
+// example `{"user": "green", "danger": "red"}`. Roles are applied on
+// templates using the `style` function: `{{ style "danger" .env }}`.
+// Available colors: black, red, green, yellow, blue, magenta, cyan,
+// white, gray and bold.
+type Theme map[string]string
+
+// SetPromptTemplate set a template used to build the shell prompt. The
+// template is rendered using the values available on the shell state, and
+// the prompt is updated automatically when the state changes. For example:
+//
+//	{{ .user }}@{{ style "env" .env }}>
+//
+// The following functions are available on templates:
+//   - color: apply a color to a value, `{{ color "red" .env }}`
+//   - style: apply a theme role to a value, `{{ style "env" .env }}`
+func (sh *Instance) SetPromptTemplate(tpl string) error {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if err := sh.parsePrompt(tpl); err != nil {
+		return err
+	}
+	sh.refreshPrompt()
+	return nil
+}
+
+// SetState registers a value on the shell state store, making it available
+// to the prompt template. The prompt is updated accordingly.
+func (sh *Instance) SetState(key string, value interface{}) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if sh.state == nil {
+		sh.state = make(map[string]interface{})
+	}
+	sh.state[key] = value
+	sh.refreshPrompt()
+}
+
+// RemoveState deletes a value from the shell state store. The prompt is
+// updated accordingly.
+func (sh *Instance) RemoveState(key string) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	delete(sh.state, key)
+	sh.refreshPrompt()
+}
+
+// State returns a value from the shell state store, `nil` if not available.
+func (sh *Instance) State(key string) interface{} {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.state[key]
+}
+
+// Parse a prompt template. Must be called while holding the lock.
+func (sh *Instance) parsePrompt(tpl string) error {
+	funcs := template.FuncMap{
+		"color": colorize,
+		"style": func(role string, v interface{}) string {
+			return colorize(sh.theme[role], v)
+		},
+	}
+	t, err := template.New("prompt").Funcs(funcs).Option("missingkey=zero").Parse(tpl)
+	if err != nil {
+		return errors.Wrap(err, "invalid prompt template")
+	}
+	sh.promptTpl = t
+	return nil
+}
+
+// Render the prompt template, if any, using the current state and update
+// the active prompt. Must be called while holding the lock.
+func (sh *Instance) refreshPrompt() {
+	if sh.promptTpl == nil {
+		return
+	}
+	buf := new(strings.Builder)
+	if err := sh.promptTpl.Execute(buf, sh.state); err != nil {
+		return // keep the current prompt
+	}
+	// text/template renders missing entries as "<no value>"
+	sh.prompt = strings.ReplaceAll(buf.String(), "<no value>", "")
+	if sh.rl != nil {
+		sh.rl.SetPrompt(sh.prompt)
+	}
+}
+
+func colorize(color string, v interface{}) string {
+	str := toString(v)
+	code, ok := ansiColors[color]
+	if !ok || str == "" {
+		return str
+	}
+	return code + str + ansiReset
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	s, _ := Render(v, OutputText)
+	return s
+}
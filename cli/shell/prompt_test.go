@@ -0,0 +1,32 @@
+package shell
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestPromptTemplate(t *testing.T) {
+	assert := tdd.New(t)
+	sh := &Instance{prompt: "> "}
+	assert.Nil(sh.setup(
+		WithTheme(Theme{"env": "red"}),
+		WithPromptTemplate(`{{ if .user }}{{ .user }}@{{ end }}{{ style "env" .env }}{{ color "invalid" "" }}> `),
+	))
+	assert.NotNil(sh.SetPromptTemplate("{{ .user "), "invalid template")
+
+	// Prompt is updated when the state changes
+	sh.SetState("env", "prod")
+	assert.Equal("\033[31mprod\033[0m> ", sh.prompt)
+	sh.SetState("user", "admin")
+	assert.Equal("admin@\033[31mprod\033[0m> ", sh.prompt)
+	assert.Equal("admin", sh.State("user"))
+	sh.RemoveState("user")
+	sh.RemoveState("env")
+	assert.Equal("> ", sh.prompt)
+	assert.Nil(sh.State("user"))
+
+	// Replace template
+	assert.Nil(sh.SetPromptTemplate(`{{ color "green" .missing }}$ `))
+	assert.Equal("$ ", sh.prompt)
+}
@@ -0,0 +1,511 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/gorilla/websocket"
+	"go.bryk.io/pkg/errors"
+	xlog "go.bryk.io/pkg/log"
+	"golang.org/x/crypto/ssh"
+)
+
+// Transports available for remote sessions.
+const (
+	TransportSSH       = "ssh"
+	TransportWebSocket = "websocket"
+)
+
+// Session provides details about a remote user connected to a shell.
+type Session struct {
+	// Authenticated user.
+	User string
+
+	// Network address of the remote user.
+	RemoteAddr string
+
+	// Transport used by the session, "ssh" or "websocket".
+	Transport string
+
+	in       io.ReadCloser
+	out      io.Writer
+	width    int32
+	onResize func()
+	mu       sync.Mutex
+}
+
+// Adjust the terminal width available for the session.
+func (s *Session) resize(width int) {
+	if width <= 0 || width > 1<<15 {
+		return
+	}
+	atomic.StoreInt32(&s.width, int32(width))
+	s.mu.Lock()
+	cb := s.onResize
+	s.mu.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
+
+// Setup readline to use the session I/O instead of the local terminal.
+// The remote client is responsible for the terminal raw mode.
+func (s *Session) configure(conf *readline.Config) {
+	conf.Stdin = s.in
+	conf.Stdout = s.out
+	conf.Stderr = s.out
+	conf.ForceUseInteractive = true
+	conf.FuncIsTerminal = func() bool { return true }
+	conf.FuncMakeRaw = func() error { return nil }
+	conf.FuncExitRaw = func() error { return nil }
+	conf.FuncGetWidth = func() int { return int(atomic.LoadInt32(&s.width)) }
+	conf.FuncOnWidthChanged = func(cb func()) {
+		s.mu.Lock()
+		s.onResize = cb
+		s.mu.Unlock()
+	}
+}
+
+// SessionSetup is used to prepare the shell instance created for a new
+// remote session; for example, to register the available commands.
+type SessionSetup func(sh *Instance, s *Session) error
+
+// RemoteOption allows to adjust the settings on a remote server instance.
+type RemoteOption func(rs *RemoteServer) error
+
+// RemoteServer allows to expose a shell to remote users over SSH or
+// WebSocket connections. A new shell instance is created for each session.
+type RemoteServer struct {
+	setup    SessionSetup
+	opts     []Option
+	sshConf  *ssh.ServerConfig
+	hostKey  bool
+	wsAuth   func(r *http.Request) (string, error)
+	upgrader websocket.Upgrader
+	log      xlog.Logger
+	closers  map[io.Closer]struct{}
+	closed   bool
+	mu       sync.Mutex
+}
+
+// NewRemoteServer returns a server ready to handle remote shell sessions.
+// `setup` is executed for every new session and should register the
+// commands available. Users are not allowed to connect unless at least one
+// authentication mechanism is enabled.
+func NewRemoteServer(setup SessionSetup, options ...RemoteOption) (*RemoteServer, error) {
+	if setup == nil {
+		return nil, errors.New("a session setup function is required")
+	}
+	rs := &RemoteServer{
+		setup:    setup,
+		sshConf:  &ssh.ServerConfig{},
+		closers:  make(map[io.Closer]struct{}),
+		upgrader: websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 1024},
+		log:      xlog.Discard(),
+	}
+	for _, opt := range options {
+		if err := opt(rs); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return rs, nil
+}
+
+// WithSessionOptions set the options used for the shell instances created
+// for remote sessions. Options related to the local terminal, like the
+// history file, are ignored.
+func WithSessionOptions(opts ...Option) RemoteOption {
+	return func(rs *RemoteServer) error {
+		rs.opts = append(rs.opts, opts...)
+		return nil
+	}
+}
+
+// WithRemoteLogger set the logger used to report server-side events, like
+// rejected authentication attempts. By default, all output is discarded.
+func WithRemoteLogger(ll xlog.Logger) RemoteOption {
+	return func(rs *RemoteServer) error {
+		if ll == nil {
+			return errors.New("a logger instance is required")
+		}
+		rs.log = ll
+		return nil
+	}
+}
+
+// WithHostKey set the private key, in PEM format, used to identify the
+// server on SSH connections. Required to accept SSH connections.
+func WithHostKey(pemKey []byte) RemoteOption {
+	return func(rs *RemoteServer) error {
+		signer, err := ssh.ParsePrivateKey(pemKey)
+		if err != nil {
+			return errors.Wrap(err, "invalid host key")
+		}
+		rs.sshConf.AddHostKey(signer)
+		rs.hostKey = true
+		return nil
+	}
+}
+
+// WithPasswordAuth enable password authentication for SSH connections.
+// `check` must return an error for invalid credentials.
+func WithPasswordAuth(check func(user string, password []byte) error) RemoteOption {
+	return func(rs *RemoteServer) error {
+		rs.sshConf.PasswordCallback = func(md ssh.ConnMetadata, pwd []byte) (*ssh.Permissions, error) {
+			if err := check(md.User(), pwd); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+		return nil
+	}
+}
+
+// WithPublicKeyAuth enable public key authentication for SSH connections.
+// `check` must return an error for keys not authorized for the user.
+func WithPublicKeyAuth(check func(user string, key ssh.PublicKey) error) RemoteOption {
+	return func(rs *RemoteServer) error {
+		rs.sshConf.PublicKeyCallback = func(md ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if err := check(md.User(), key); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+		return nil
+	}
+}
+
+// WithWebSocketAuth enable WebSocket connections. `auth` is used to validate
+// the incoming HTTP request, before upgrading the connection, and return the
+// user name for the session. To allow unauthenticated access, `auth` must
+// explicitly accept all requests.
+func WithWebSocketAuth(auth func(r *http.Request) (user string, err error)) RemoteOption {
+	return func(rs *RemoteServer) error {
+		rs.wsAuth = auth
+		return nil
+	}
+}
+
+// WithWebSocketOrigin set the method used to validate the origin of incoming
+// WebSocket requests. By default, only same-origin requests are allowed.
+func WithWebSocketOrigin(check func(r *http.Request) bool) RemoteOption {
+	return func(rs *RemoteServer) error {
+		rs.upgrader.CheckOrigin = check
+		return nil
+	}
+}
+
+// ServeSSH accepts SSH connections on the provided listener and starts
+// a shell session for each of them. The method blocks until the listener
+// is closed.
+func (rs *RemoteServer) ServeSSH(ln net.Listener) error {
+	if !rs.hostKey {
+		return errors.New("a host key is required to accept SSH connections")
+	}
+	if !rs.track(ln) {
+		return errors.New("server closed")
+	}
+	defer rs.untrack(ln)
+	for {
+		nc, err := ln.Accept()
+		if err != nil {
+			if rs.isClosed() || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		go rs.handleSSH(nc)
+	}
+}
+
+// ServeHTTP upgrades the incoming request to a WebSocket connection and
+// starts a shell session on it. Data is exchanged as raw terminal I/O,
+// suitable for browser-based terminal emulators. The initial terminal
+// width can be provided using the `cols` query parameter. Errors returned
+// by the authentication function are logged but not disclosed to clients.
+func (rs *RemoteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rs.wsAuth == nil {
+		http.Error(w, "remote sessions are not enabled", http.StatusForbidden)
+		return
+	}
+	user, err := rs.wsAuth(r)
+	if err != nil {
+		rs.log.WithFields(map[string]interface{}{
+			"error":       err.Error(),
+			"remote_addr": r.RemoteAddr,
+		}).Warning("websocket authentication failed")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	conn, err := rs.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // error response already sent by the upgrader
+	}
+	rw := &wsConn{conn: conn}
+	s := &Session{
+		User:       user,
+		RemoteAddr: r.RemoteAddr,
+		Transport:  TransportWebSocket,
+		in:         rw,
+		out:        &crlfWriter{w: rw},
+		width:      80,
+	}
+	if cols, err := strconv.Atoi(r.URL.Query().Get("cols")); err == nil {
+		s.resize(cols)
+	}
+	rs.run(s, rw)
+}
+
+// Close stops accepting new connections and terminates all active sessions.
+func (rs *RemoteServer) Close() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.closed = true
+	for c := range rs.closers {
+		_ = c.Close()
+	}
+	rs.closers = make(map[io.Closer]struct{})
+	return nil
+}
+
+// Handle a new SSH connection.
+func (rs *RemoteServer) handleSSH(nc net.Conn) {
+	_ = nc.SetDeadline(time.Now().Add(30 * time.Second)) // handshake timeout
+	conn, channels, requests, err := ssh.NewServerConn(nc, rs.sshConf)
+	if err != nil {
+		_ = nc.Close()
+		return
+	}
+	_ = nc.SetDeadline(time.Time{})
+	if !rs.track(conn) {
+		_ = conn.Close()
+		return
+	}
+	defer rs.untrack(conn)
+	go ssh.DiscardRequests(requests)
+	for nch := range channels {
+		if nch.ChannelType() != "session" {
+			_ = nch.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		ch, chReqs, err := nch.Accept()
+		if err != nil {
+			continue
+		}
+		s := &Session{
+			User:       conn.User(),
+			RemoteAddr: conn.RemoteAddr().String(),
+			Transport:  TransportSSH,
+			in:         ch,
+			out:        &crlfWriter{w: ch},
+			width:      80,
+		}
+		go rs.handleSSHRequests(s, ch, chReqs)
+	}
+}
+
+// Process the requests received on an SSH session channel.
+func (rs *RemoteServer) handleSSHRequests(s *Session, ch ssh.Channel, requests <-chan *ssh.Request) {
+	started := false
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			pty := struct {
+				Term    string
+				Columns uint32
+				Rows    uint32
+				Width   uint32
+				Height  uint32
+				Modes   string
+			}{}
+			if err := ssh.Unmarshal(req.Payload, &pty); err == nil {
+				s.resize(int(pty.Columns))
+			}
+			_ = req.Reply(true, nil)
+		case "window-change":
+			size := struct {
+				Columns uint32
+				Rows    uint32
+				Width   uint32
+				Height  uint32
+			}{}
+			if err := ssh.Unmarshal(req.Payload, &size); err == nil {
+				s.resize(int(size.Columns))
+			}
+		case "shell":
+			_ = req.Reply(!started, nil)
+			if !started {
+				started = true
+				go rs.run(s, sshChannel{ch})
+			}
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+// Start an interactive shell for the session; blocks until the session
+// is closed.
+func (rs *RemoteServer) run(s *Session, conn io.Closer) {
+	if !rs.track(conn) {
+		_ = conn.Close()
+		return
+	}
+	defer rs.untrack(conn)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	opts := append([]Option{}, rs.opts...)
+	opts = append(opts, withSession(s))
+	sh, err := New(opts...)
+	if err != nil {
+		_, _ = io.WriteString(s.out, "failed to start session\n")
+		return
+	}
+	if err := rs.setup(sh, s); err != nil {
+		_, _ = io.WriteString(s.out, "failed to start session\n")
+		_ = sh.close()
+		return
+	}
+	sh.Start()
+}
+
+func (rs *RemoteServer) track(c io.Closer) bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.closed {
+		return false
+	}
+	rs.closers[c] = struct{}{}
+	return true
+}
+
+func (rs *RemoteServer) untrack(c io.Closer) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	delete(rs.closers, c)
+}
+
+func (rs *RemoteServer) isClosed() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.closed
+}
+
+// Bind a shell instance to a remote session.
+func withSession(s *Session) Option {
+	return func(sh *Instance) error {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		sh.session = s
+		sh.out = s.out
+		sh.historyFile = ""
+		sh.pager = ""
+		return nil
+	}
+}
+
+// Session returns the details of the remote session the shell is bound
+// to, or `nil` for local shells.
+func (sh *Instance) Session() *Session {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.session
+}
+
+// Terminal output for remote clients; the client terminal operates in
+// raw mode so line feeds must include a carriage return.
+type crlfWriter struct {
+	w    io.Writer
+	last byte
+	mu   sync.Mutex
+}
+
+func (cw *crlfWriter) Write(p []byte) (int, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	if len(p) == 0 {
+		return 0, nil
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(p)+8))
+	prev := cw.last
+	for _, b := range p {
+		if b == '\n' && prev != '\r' {
+			buf.WriteByte('\r')
+		}
+		buf.WriteByte(b)
+		prev = b
+	}
+	if _, err := cw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	cw.last = prev
+	return len(p), nil
+}
+
+// SSH session channel; the exit status is reported to the client
+// before closing it.
+type sshChannel struct {
+	ssh.Channel
+}
+
+func (sc sshChannel) Close() error {
+	_, _ = sc.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+	return sc.Channel.Close()
+}
+
+// Raw terminal I/O over a WebSocket connection.
+type wsConn struct {
+	conn   *websocket.Conn
+	reader io.Reader
+	rmu    sync.Mutex
+	wmu    sync.Mutex
+}
+
+func (wc *wsConn) Read(p []byte) (int, error) {
+	wc.rmu.Lock()
+	defer wc.rmu.Unlock()
+	for {
+		if wc.reader == nil {
+			_, r, err := wc.conn.NextReader()
+			if err != nil {
+				return 0, io.EOF
+			}
+			wc.reader = r
+		}
+		n, err := wc.reader.Read(p)
+		if errors.Is(err, io.EOF) {
+			wc.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (wc *wsConn) Write(p []byte) (int, error) {
+	wc.wmu.Lock()
+	defer wc.wmu.Unlock()
+	if err := wc.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (wc *wsConn) Close() error {
+	wc.wmu.Lock()
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye")
+	_ = wc.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	wc.wmu.Unlock()
+	return wc.conn.Close()
+}
@@ -0,0 +1,155 @@
+package shell
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	xlog "go.bryk.io/pkg/log"
+	"golang.org/x/crypto/ssh"
+)
+
+// Read from `r` until `expected` is found or the timeout expires.
+func readUntil(r io.Reader, expected string) bool {
+	found := make(chan bool, 1)
+	go func() {
+		var data strings.Builder
+		b := make([]byte, 1)
+		for {
+			if _, err := r.Read(b); err != nil {
+				found <- false
+				return
+			}
+			data.WriteByte(b[0])
+			if strings.Contains(data.String(), expected) {
+				found <- true
+				return
+			}
+		}
+	}()
+	select {
+	case ok := <-found:
+		return ok
+	case <-time.After(5 * time.Second):
+		return false
+	}
+}
+
+func sessionSetup(sh *Instance, s *Session) error {
+	sh.AddCommand(&Command{
+		Name: "whoami",
+		Run: func(_ string) string {
+			return fmt.Sprintf("%s via %s", s.User, s.Transport)
+		},
+	})
+	return nil
+}
+
+func TestRemoteServer(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Host key
+	_, pk, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(pk, "")
+	assert.Nil(err)
+
+	_, err = NewRemoteServer(nil)
+	assert.NotNil(err, "setup function is required")
+	_, err = NewRemoteServer(sessionSetup, WithRemoteLogger(nil))
+	assert.NotNil(err, "logger is required")
+	rs, err := NewRemoteServer(sessionSetup,
+		WithHostKey(pem.EncodeToMemory(block)),
+		WithSessionOptions(WithPrompt("remote> ")),
+		WithRemoteLogger(xlog.Discard()),
+		WithPasswordAuth(func(user string, password []byte) error {
+			if user != "admin" || string(password) != "secret" {
+				return errors.New("invalid credentials")
+			}
+			return nil
+		}),
+		WithWebSocketAuth(func(r *http.Request) (string, error) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				return "", errors.New("invalid credentials")
+			}
+			return "operator", nil
+		}),
+	)
+	assert.Nil(err)
+	defer func() {
+		_ = rs.Close()
+	}()
+
+	t.Run("SSH", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(err)
+		go func() {
+			_ = rs.ServeSSH(ln)
+		}()
+
+		conf := &ssh.ClientConfig{
+			User:            "admin",
+			Auth:            []ssh.AuthMethod{ssh.Password("invalid")},
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(), // nolint:gosec
+		}
+		_, err = ssh.Dial("tcp", ln.Addr().String(), conf)
+		assert.NotNil(err, "invalid credentials")
+
+		conf.Auth = []ssh.AuthMethod{ssh.Password("secret")}
+		client, err := ssh.Dial("tcp", ln.Addr().String(), conf)
+		if !assert.Nil(err) {
+			return
+		}
+		defer func() {
+			_ = client.Close()
+		}()
+		session, err := client.NewSession()
+		assert.Nil(err)
+		assert.Nil(session.RequestPty("xterm", 40, 120, ssh.TerminalModes{}))
+		stdin, _ := session.StdinPipe()
+		stdout, _ := session.StdoutPipe()
+		assert.Nil(session.Shell())
+		assert.True(readUntil(stdout, "remote> "), "prompt")
+		_, _ = stdin.Write([]byte("whoami\r"))
+		assert.True(readUntil(stdout, "admin via ssh\r\n"), "command output")
+		_, _ = stdin.Write([]byte("exit\r"))
+		assert.Nil(session.Wait())
+	})
+
+	t.Run("WebSocket", func(t *testing.T) {
+		srv := httptest.NewServer(rs)
+		defer srv.Close()
+		endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+		_, res, err := websocket.DefaultDialer.Dial(endpoint, nil)
+		assert.NotNil(err, "invalid credentials")
+		assert.Equal(http.StatusUnauthorized, res.StatusCode)
+		body, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		assert.Equal("unauthorized", strings.TrimSpace(string(body)), "auth errors are not disclosed")
+
+		header := http.Header{}
+		header.Set("Authorization", "Bearer token")
+		conn, _, err := websocket.DefaultDialer.Dial(endpoint, header)
+		if !assert.Nil(err) {
+			return
+		}
+		wc := &wsConn{conn: conn}
+		defer func() {
+			_ = wc.Close()
+		}()
+		assert.True(readUntil(wc, "remote> "), "prompt")
+		_, _ = wc.Write([]byte("whoami\r"))
+		assert.True(readUntil(wc, "operator via websocket\r\n"), "command output")
+	})
+}
@@ -0,0 +1,127 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Variable assignments in scripts, e.g. `NAME=value`.
+var assignment = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+
+// RunScript executes, in order, the commands read from `r`. This allows
+// to use the same command set registered with the shell for automation
+// purposes; for example, by processing a script file or the content piped
+// to the standard input of the application.
+//
+//   - Empty lines and lines starting with `#` are ignored.
+//   - Lines ending with `\` continue on the next line.
+//   - Variables can be defined using `NAME=value` lines, and referenced in
+//     subsequent lines as `$NAME` or `${NAME}`. Environment variables are
+//     used for names not defined on the shell.
+//   - Processing stops when an exit command is found.
+//
+// By default processing stops on the first error encountered; use the
+// `WithExitOnError` option to adjust this behavior. Commands executed by a
+// script can't prompt the user for input.
+func (sh *Instance) RunScript(r io.Reader) error {
+	sh.mu.Lock()
+	exitOnError := sh.exitOnError
+	sh.scripts++
+	sh.mu.Unlock()
+	defer func() {
+		sh.mu.Lock()
+		sh.scripts--
+		sh.mu.Unlock()
+	}()
+
+	var (
+		failures int
+		buf      strings.Builder
+		start    int
+	)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		// join continuation lines
+		line := strings.TrimSpace(scanner.Text())
+		if buf.Len() == 0 {
+			start = n
+		}
+		if strings.HasSuffix(line, "\\") {
+			buf.WriteString(strings.TrimSpace(strings.TrimSuffix(line, "\\")) + " ")
+			continue
+		}
+		buf.WriteString(line)
+		line = strings.TrimSpace(buf.String())
+		buf.Reset()
+
+		// process line
+		exit, res, err := sh.runLine(line)
+		if exit {
+			return nil
+		}
+		if res != "" {
+			sh.Print(res)
+		}
+		if err == nil {
+			continue
+		}
+		err = errors.Wrapf(err, "line %d", start)
+		if exitOnError {
+			return err
+		}
+		failures++
+		sh.Print(fmt.Sprintf("error: %s", err))
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read script")
+	}
+	if failures > 0 {
+		return errors.Errorf("%d command(s) failed", failures)
+	}
+	return nil
+}
+
+// SetVariable registers a value that can be referenced in scripts
+// using `$name` or `${name}`.
+func (sh *Instance) SetVariable(name, value string) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if sh.vars == nil {
+		sh.vars = make(map[string]string)
+	}
+	sh.vars[name] = value
+}
+
+// Variable returns the value of a variable registered on the shell, or
+// the environment variable with the same name if not available.
+func (sh *Instance) Variable(name string) string {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if v, ok := sh.vars[name]; ok {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+// Process a single script line.
+func (sh *Instance) runLine(line string) (bool, string, error) {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false, "", nil
+	}
+	line = os.Expand(line, sh.Variable)
+	if m := assignment.FindStringSubmatch(line); m != nil {
+		value := strings.TrimSpace(m[2])
+		if tokens, err := tokenize(value); err == nil && len(tokens) == 1 {
+			value = tokens[0] // remove quotes
+		}
+		sh.SetVariable(m[1], value)
+		return false, "", nil
+	}
+	return sh.process(line)
+}
@@ -0,0 +1,63 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestRunScript(t *testing.T) {
+	assert := tdd.New(t)
+	out := bytes.NewBuffer(nil)
+	sh := &Instance{
+		out:          out,
+		exitOnError:  true,
+		exitCommands: []string{"exit"},
+		helpCommands: []string{"help"},
+	}
+	sh.commands = []*Command{
+		{
+			Name: "echo",
+			Run: func(arg string) string {
+				return arg
+			},
+		},
+		{
+			Name: "fail",
+			Exec: func(_ *Input) (string, error) {
+				return "", errors.New("failed")
+			},
+		},
+	}
+	t.Setenv("SHELL_TEST_REGION", "eu-west")
+
+	script := `# sample script
+NAME="sample value"
+echo $NAME in ${SHELL_TEST_REGION}
+echo multi \
+  line
+
+exit
+echo not executed
+`
+	assert.Nil(sh.RunScript(strings.NewReader(script)))
+	assert.Equal("sample value in eu-west\nmulti line\n", out.String())
+
+	// Exit on error
+	out.Reset()
+	err := sh.RunScript(strings.NewReader("echo first\nfail\necho second"))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "line 2")
+	assert.Equal("first\n", out.String())
+
+	// Continue on error
+	out.Reset()
+	sh.exitOnError = false
+	err = sh.RunScript(strings.NewReader("echo first\nfail\nunknown\necho second"))
+	assert.NotNil(err)
+	assert.Equal("2 command(s) failed", err.Error())
+	assert.Contains(out.String(), "second")
+}
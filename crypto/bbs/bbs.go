@@ -0,0 +1,437 @@
+package bbs
+
+import (
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha256" // register hash function
+	"encoding/binary"
+	"sort"
+
+	GG "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/expander"
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// PrivateKeySize is the size, in bytes, of private keys.
+	PrivateKeySize = 32
+
+	// PublicKeySize is the size, in bytes, of compressed public keys (G2).
+	PublicKeySize = 96
+
+	// SignatureSize is the size, in bytes, of signatures; a compressed G1
+	// point and a scalar value.
+	SignatureSize = 80
+)
+
+// Ciphersuite identifiers.
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-bbs-signatures-05.html#name-bls12-381-sha-256
+const (
+	ciphersuiteID = "BBS_BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	apiID         = ciphersuiteID + "H2G_HM2S_"
+	pointSize     = 48
+	scalarSize    = 32
+	expandLen     = 48
+)
+
+// GenerateKey returns a new random key pair.
+func GenerateKey() (priv []byte, pub []byte, err error) {
+	sk := new(GG.Scalar)
+	for sk.IsZero() == 1 {
+		if err = sk.Random(rand.Reader); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to generate new random key")
+		}
+	}
+	priv, _ = sk.MarshalBinary()
+	return priv, publicKey(sk), nil
+}
+
+// PublicKey returns the public key corresponding to the provided private
+// key.
+func PublicKey(priv []byte) ([]byte, error) {
+	sk, err := parsePrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return publicKey(sk), nil
+}
+
+// Sign produces a signature over the ordered list of `messages`. The
+// `header` value is optional and, if provided, is also bound to the
+// signature but can't be selectively disclosed.
+func Sign(priv, header []byte, messages [][]byte) ([]byte, error) {
+	sk, err := parsePrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	pk := publicKey(sk)
+	msgs := messagesToScalars(messages)
+	gens := createGenerators(len(msgs) + 1)
+	domain := calculateDomain(pk, gens, header)
+
+	// Deterministic "e" value
+	input := append([]byte{}, priv...)
+	for _, m := range msgs {
+		input = append(input, scalarBytes(m)...)
+	}
+	input = append(input, scalarBytes(domain)...)
+	e := hashToScalar(input, []byte(apiID+"H2S_"))
+
+	// A = B * (1 / (SK + e))
+	B := computeB(gens, domain, msgs, nil)
+	inv := new(GG.Scalar)
+	inv.Add(sk, e)
+	if inv.IsZero() == 1 {
+		return nil, errors.New("invalid signature")
+	}
+	inv.Inv(inv)
+	A := new(GG.G1)
+	A.ScalarMult(inv, B)
+	return append(A.BytesCompressed(), scalarBytes(e)...), nil
+}
+
+// Verify a signature produced with 'Sign' over the ordered list of
+// `messages`.
+func Verify(pub, signature, header []byte, messages [][]byte) bool {
+	W, err := parsePublicKey(pub)
+	if err != nil {
+		return false
+	}
+	A, e, err := parseSignature(signature)
+	if err != nil {
+		return false
+	}
+	msgs := messagesToScalars(messages)
+	gens := createGenerators(len(msgs) + 1)
+	domain := calculateDomain(pub, gens, header)
+	B := computeB(gens, domain, msgs, nil)
+
+	// e(A, W + BP2 * e) == e(B, BP2)
+	Q := new(GG.G2)
+	Q.ScalarMult(e, GG.G2Generator())
+	Q.Add(Q, W)
+	return GG.Pair(A, Q).IsEqual(GG.Pair(B, GG.G2Generator()))
+}
+
+// DeriveProof generates a zero-knowledge proof of knowledge of a signature,
+// revealing only the messages at the `disclosed` positions (zero-based).
+// The `presentationHeader` value is optional and is bound to the proof;
+// usually used to include a nonce provided by the verifier. Each proof is
+// randomized, so multiple proofs derived from the same signature are
+// unlinkable.
+func DeriveProof(pub, signature, header, presentationHeader []byte, messages [][]byte, disclosed []int) ([]byte, error) {
+	if !Verify(pub, signature, header, messages) {
+		return nil, errors.New("invalid signature")
+	}
+	A, e, _ := parseSignature(signature)
+	msgs := messagesToScalars(messages)
+	gens := createGenerators(len(msgs) + 1)
+	domain := calculateDomain(pub, gens, header)
+
+	// Disclosed and undisclosed indexes
+	revealed := make(map[int]bool)
+	for _, i := range disclosed {
+		if i < 0 || i >= len(msgs) {
+			return nil, errors.Errorf("invalid disclosed index: %d", i)
+		}
+		revealed[i] = true
+	}
+	var di, ui []int
+	for i := range msgs {
+		if revealed[i] {
+			di = append(di, i)
+		} else {
+			ui = append(ui, i)
+		}
+	}
+
+	// Random scalars
+	random, err := randomScalars(5 + len(ui))
+	if err != nil {
+		return nil, err
+	}
+	r1, r2, et, r1t, r3t, mt := random[0], random[1], random[2], random[3], random[4], random[5:]
+
+	// Proof initialization
+	B := computeB(gens, domain, msgs, nil)
+	D := mul(B, r2)
+	r1r2 := new(GG.Scalar)
+	r1r2.Mul(r1, r2)
+	Abar := mul(A, r1r2)
+	Bbar := mul(D, r1)
+	Bbar.Add(Bbar, neg(mul(Abar, e)))
+	T1 := mul(Abar, et)
+	T1.Add(T1, mul(D, r1t))
+	T2 := mul(D, r3t)
+	for j, i := range ui {
+		T2.Add(T2, mul(gens[i+1], mt[j]))
+	}
+	c := challenge(Abar, Bbar, D, T1, T2, di, msgs, domain, presentationHeader)
+
+	// Proof finalization
+	r3 := new(GG.Scalar)
+	r3.Inv(r2)
+	proof := append(Abar.BytesCompressed(), Bbar.BytesCompressed()...)
+	proof = append(proof, D.BytesCompressed()...)
+	proof = append(proof, scalarBytes(mulAdd(e, c, et))...)   // e^ = e~ + e * c
+	proof = append(proof, scalarBytes(mulSub(r1, c, r1t))...) // r1^ = r1~ - r1 * c
+	proof = append(proof, scalarBytes(mulSub(r3, c, r3t))...) // r3^ = r3~ - r3 * c
+	for j, i := range ui {
+		proof = append(proof, scalarBytes(mulAdd(msgs[i], c, mt[j]))...) // m^ = m~ + m * c
+	}
+	return append(proof, scalarBytes(c)...), nil
+}
+
+// VerifyProof validates a proof produced with 'DeriveProof'; `disclosed`
+// includes the revealed messages by position (zero-based).
+func VerifyProof(pub, proof, header, presentationHeader []byte, disclosed map[int][]byte) bool {
+	W, err := parsePublicKey(pub)
+	if err != nil {
+		return false
+	}
+
+	// Decode proof
+	fixed := 3*pointSize + 4*scalarSize
+	if len(proof) < fixed || (len(proof)-fixed)%scalarSize != 0 {
+		return false
+	}
+	points := make([]*GG.G1, 3)
+	for i := range points {
+		points[i] = new(GG.G1)
+		if err = points[i].SetBytes(proof[i*pointSize : (i+1)*pointSize]); err != nil {
+			return false
+		}
+	}
+	Abar, Bbar, D := points[0], points[1], points[2]
+	var scalars []*GG.Scalar
+	for off := 3 * pointSize; off < len(proof); off += scalarSize {
+		s := new(GG.Scalar)
+		if err = s.UnmarshalBinary(proof[off : off+scalarSize]); err != nil {
+			return false
+		}
+		scalars = append(scalars, s)
+	}
+	eh, r1h, r3h, c := scalars[0], scalars[1], scalars[2], scalars[len(scalars)-1]
+	mh := scalars[3 : len(scalars)-1]
+
+	// Disclosed and undisclosed indexes
+	total := len(mh) + len(disclosed)
+	di := make([]int, 0, len(disclosed))
+	for i := range disclosed {
+		if i < 0 || i >= total {
+			return false
+		}
+		di = append(di, i)
+	}
+	sort.Ints(di)
+	msgs := make([]*GG.Scalar, total)
+	for _, i := range di {
+		msgs[i] = hashToScalar(disclosed[i], []byte(apiID+"MAP_MSG_TO_SCALAR_AS_HASH_"))
+	}
+	gens := createGenerators(total + 1)
+	domain := calculateDomain(pub, gens, header)
+
+	// T1 = Bbar * c + Abar * e^ + D * r1^
+	T1 := mul(Bbar, c)
+	T1.Add(T1, mul(Abar, eh))
+	T1.Add(T1, mul(D, r1h))
+
+	// T2 = Bv * c + D * r3^ + H_j * m^_j
+	Bv := computeB(gens, domain, msgs, di)
+	T2 := mul(Bv, c)
+	T2.Add(T2, mul(D, r3h))
+	j := 0
+	for i := range msgs {
+		if msgs[i] == nil {
+			T2.Add(T2, mul(gens[i+1], mh[j]))
+			j++
+		}
+	}
+	if challenge(Abar, Bbar, D, T1, T2, di, msgs, domain, presentationHeader).IsEqual(c) != 1 {
+		return false
+	}
+
+	// e(Abar, W) == e(Bbar, BP2)
+	if Abar.IsIdentity() {
+		return false
+	}
+	return GG.Pair(Abar, W).IsEqual(GG.Pair(Bbar, GG.G2Generator()))
+}
+
+// Compute B = P1 + Q1 * domain + H_1 * msg_1 + ... + H_L * msg_L. If
+// `indexes` is provided, only the messages at those positions are used.
+func computeB(gens []*GG.G1, domain *GG.Scalar, msgs []*GG.Scalar, indexes []int) *GG.G1 {
+	B := p1()
+	B.Add(B, mul(gens[0], domain))
+	if indexes == nil {
+		for i := range msgs {
+			indexes = append(indexes, i)
+		}
+	}
+	for _, i := range indexes {
+		B.Add(B, mul(gens[i+1], msgs[i]))
+	}
+	return B
+}
+
+// Calculate the challenge value for a proof.
+//
+//	R | i1 | msg_i1 | ... | iR | msg_iR | Abar | Bbar | D | T1 | T2 | domain | len(ph) | ph
+func challenge(Abar, Bbar, D, T1, T2 *GG.G1, di []int, msgs []*GG.Scalar, domain *GG.Scalar, ph []byte) *GG.Scalar {
+	input := binary.BigEndian.AppendUint64(nil, uint64(len(di)))
+	for _, i := range di {
+		input = binary.BigEndian.AppendUint64(input, uint64(i))
+		input = append(input, scalarBytes(msgs[i])...)
+	}
+	for _, p := range []*GG.G1{Abar, Bbar, D, T1, T2} {
+		input = append(input, p.BytesCompressed()...)
+	}
+	input = append(input, scalarBytes(domain)...)
+	input = binary.BigEndian.AppendUint64(input, uint64(len(ph)))
+	input = append(input, ph...)
+	return hashToScalar(input, []byte(apiID+"H2S_"))
+}
+
+// Domain value binding the public key, generators and header.
+func calculateDomain(pk []byte, gens []*GG.G1, header []byte) *GG.Scalar {
+	input := append([]byte{}, pk...)
+	input = binary.BigEndian.AppendUint64(input, uint64(len(gens)-1))
+	for _, g := range gens {
+		input = append(input, g.BytesCompressed()...)
+	}
+	input = append(input, apiID...)
+	input = binary.BigEndian.AppendUint64(input, uint64(len(header)))
+	input = append(input, header...)
+	return hashToScalar(input, []byte(apiID+"H2S_"))
+}
+
+// Random scalars used when deriving a proof; replaced with deterministic
+// values on tests.
+var randomScalars = func(count int) ([]*GG.Scalar, error) {
+	list := make([]*GG.Scalar, count)
+	for i := range list {
+		list[i] = new(GG.Scalar)
+		if err := list[i].Random(rand.Reader); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Map messages to scalar values.
+func messagesToScalars(messages [][]byte) []*GG.Scalar {
+	dst := []byte(apiID + "MAP_MSG_TO_SCALAR_AS_HASH_")
+	list := make([]*GG.Scalar, len(messages))
+	for i, m := range messages {
+		list[i] = hashToScalar(m, dst)
+	}
+	return list
+}
+
+// Deterministically create `count` generator points.
+func createGenerators(count int) []*GG.G1 {
+	return hashToGenerators(count, []byte(apiID+"MESSAGE_GENERATOR_SEED"))
+}
+
+// Base point P1.
+func p1() *GG.G1 {
+	return hashToGenerators(1, []byte(apiID+"BP_MESSAGE_GENERATOR_SEED"))[0]
+}
+
+// Hash to generator points using the provided seed.
+func hashToGenerators(count int, seed []byte) []*GG.G1 {
+	seedDST := []byte(apiID + "SIG_GENERATOR_SEED_")
+	genDST := []byte(apiID + "SIG_GENERATOR_DST_")
+	v := expander.NewExpanderMD(crypto.SHA256, seedDST).Expand(seed, expandLen)
+	list := make([]*GG.G1, count)
+	for i := range list {
+		v = expander.NewExpanderMD(crypto.SHA256, seedDST).Expand(binary.BigEndian.AppendUint64(v, uint64(i+1)), expandLen)
+		list[i] = new(GG.G1)
+		list[i].Hash(v, genDST)
+	}
+	return list
+}
+
+// Hash an arbitrary input to a scalar value.
+func hashToScalar(msg, dst []byte) *GG.Scalar {
+	s := new(GG.Scalar)
+	s.SetBytes(expander.NewExpanderMD(crypto.SHA256, dst).Expand(msg, expandLen))
+	return s
+}
+
+// Public key for the provided private key; W = BP2 * SK.
+func publicKey(sk *GG.Scalar) []byte {
+	W := new(GG.G2)
+	W.ScalarMult(sk, GG.G2Generator())
+	return W.BytesCompressed()
+}
+
+// Decode a private key.
+func parsePrivateKey(priv []byte) (*GG.Scalar, error) {
+	sk := new(GG.Scalar)
+	if len(priv) != PrivateKeySize || sk.UnmarshalBinary(priv) != nil || sk.IsZero() == 1 {
+		return nil, errors.New("invalid private key")
+	}
+	return sk, nil
+}
+
+// Decode a public key.
+func parsePublicKey(pub []byte) (*GG.G2, error) {
+	W := new(GG.G2)
+	if len(pub) != PublicKeySize || W.SetBytes(pub) != nil || W.IsIdentity() {
+		return nil, errors.New("invalid public key")
+	}
+	return W, nil
+}
+
+// Decode a signature value.
+func parseSignature(sig []byte) (*GG.G1, *GG.Scalar, error) {
+	if len(sig) != SignatureSize {
+		return nil, nil, errors.New("invalid signature")
+	}
+	A := new(GG.G1)
+	if err := A.SetBytes(sig[:pointSize]); err != nil || A.IsIdentity() {
+		return nil, nil, errors.New("invalid signature")
+	}
+	e := new(GG.Scalar)
+	if err := e.UnmarshalBinary(sig[pointSize:]); err != nil || e.IsZero() == 1 {
+		return nil, nil, errors.New("invalid signature")
+	}
+	return A, e, nil
+}
+
+// Return P * k.
+func mul(P *GG.G1, k *GG.Scalar) *GG.G1 {
+	R := new(GG.G1)
+	R.ScalarMult(k, P)
+	return R
+}
+
+// Return -P.
+func neg(P *GG.G1) *GG.G1 {
+	R := *P
+	R.Neg()
+	return &R
+}
+
+// Return r + x * c.
+func mulAdd(x, c, r *GG.Scalar) *GG.Scalar {
+	s := new(GG.Scalar)
+	s.Mul(x, c)
+	s.Add(r, s)
+	return s
+}
+
+// Return r - x * c.
+func mulSub(x, c, r *GG.Scalar) *GG.Scalar {
+	s := new(GG.Scalar)
+	s.Mul(x, c)
+	s.Sub(r, s)
+	return s
+}
+
+// Encode a scalar value.
+func scalarBytes(s *GG.Scalar) []byte {
+	b, _ := s.MarshalBinary()
+	return b
+}
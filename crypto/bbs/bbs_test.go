@@ -0,0 +1,154 @@
+package bbs
+
+import (
+	"crypto"
+	"encoding/hex"
+	"testing"
+
+	GG "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/expander"
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	assert := tdd.New(t)
+	priv, pub, err := GenerateKey()
+	assert.Nil(err, "generate key")
+	assert.Len(priv, PrivateKeySize)
+	assert.Len(pub, PublicKeySize)
+	pub2, err := PublicKey(priv)
+	assert.Nil(err, "public key")
+	assert.Equal(pub, pub2)
+
+	header := []byte("credential-header")
+	messages := [][]byte{
+		[]byte("name: Jane Doe"),
+		[]byte("birthdate: 1990-01-01"),
+		[]byte("nationality: MX"),
+	}
+	sig, err := Sign(priv, header, messages)
+	assert.Nil(err, "sign")
+	assert.Len(sig, SignatureSize)
+	assert.True(Verify(pub, sig, header, messages), "verify")
+
+	// Signatures are deterministic
+	sig2, _ := Sign(priv, header, messages)
+	assert.Equal(sig, sig2)
+
+	// Invalid values
+	assert.False(Verify(pub, sig, nil, messages), "invalid header")
+	assert.False(Verify(pub, sig, header, messages[:2]), "missing message")
+	assert.False(Verify(pub, sig, header, [][]byte{messages[1], messages[0], messages[2]}), "order")
+	_, other, _ := GenerateKey()
+	assert.False(Verify(other, sig, header, messages), "invalid key")
+	assert.False(Verify(pub, sig[:40], header, messages), "invalid signature")
+	_, err = Sign(make([]byte, PrivateKeySize), header, messages)
+	assert.NotNil(err, "invalid private key")
+}
+
+func TestProof(t *testing.T) {
+	assert := tdd.New(t)
+	priv, pub, _ := GenerateKey()
+	header := []byte("credential-header")
+	nonce := []byte("verifier-nonce")
+	messages := [][]byte{
+		[]byte("name: Jane Doe"),
+		[]byte("birthdate: 1990-01-01"),
+		[]byte("nationality: MX"),
+		[]byte("document: 123456789"),
+	}
+	sig, _ := Sign(priv, header, messages)
+
+	// Partial disclosure
+	proof, err := DeriveProof(pub, sig, header, nonce, messages, []int{0, 2})
+	assert.Nil(err, "derive proof")
+	disclosed := map[int][]byte{0: messages[0], 2: messages[2]}
+	assert.True(VerifyProof(pub, proof, header, nonce, disclosed), "verify proof")
+
+	// Proofs are unlinkable
+	proof2, _ := DeriveProof(pub, sig, header, nonce, messages, []int{0, 2})
+	assert.NotEqual(proof, proof2)
+	assert.True(VerifyProof(pub, proof2, header, nonce, disclosed), "verify proof")
+
+	// No messages or all messages disclosed
+	proof, err = DeriveProof(pub, sig, header, nonce, messages, nil)
+	assert.Nil(err, "derive proof")
+	assert.True(VerifyProof(pub, proof, header, nonce, map[int][]byte{}), "verify proof")
+	proof, err = DeriveProof(pub, sig, header, nil, messages, []int{0, 1, 2, 3})
+	assert.Nil(err, "derive proof")
+	assert.True(VerifyProof(pub, proof, header, nil, map[int][]byte{
+		0: messages[0], 1: messages[1], 2: messages[2], 3: messages[3],
+	}), "verify proof")
+
+	// Invalid values
+	proof, _ = DeriveProof(pub, sig, header, nonce, messages, []int{1})
+	assert.False(VerifyProof(pub, proof, header, []byte("other-nonce"), map[int][]byte{1: messages[1]}), "nonce")
+	assert.False(VerifyProof(pub, proof, nil, nonce, map[int][]byte{1: messages[1]}), "header")
+	assert.False(VerifyProof(pub, proof, header, nonce, map[int][]byte{1: []byte("birthdate: 2010-01-01")}), "message")
+	assert.False(VerifyProof(pub, proof, header, nonce, map[int][]byte{0: messages[1]}), "index")
+	assert.False(VerifyProof(pub, proof, header, nonce, map[int][]byte{1: messages[1], 2: messages[2]}), "messages")
+	assert.False(VerifyProof(pub, proof[:100], header, nonce, map[int][]byte{1: messages[1]}), "truncated")
+	_, err = DeriveProof(pub, sig, header, nonce, messages, []int{4})
+	assert.NotNil(err, "invalid index")
+	_, err = DeriveProof(pub, sig, header, nonce, messages[:3], []int{0})
+	assert.NotNil(err, "invalid signature")
+}
+
+// Test vectors for the BLS12-381-SHA-256 ciphersuite.
+// https://www.ietf.org/archive/id/draft-irtf-cfrg-bbs-signatures-05.html#name-bls12-381-sha-256-test-vect
+func TestFixtures(t *testing.T) {
+	assert := tdd.New(t)
+	unhex := func(s string) []byte {
+		b, _ := hex.DecodeString(s)
+		return b
+	}
+	sk := unhex("60e55110f76883a13d030b2f6bd11883422d5abde717569fc0731f51237169fc")
+	header := unhex("11223344556677889900aabbccddeeff")
+	ph := unhex("bed231d880675ed101ead304512e043ade9958dd0241ea70b4b3957fba941501")
+	msg := unhex("9872ad089e452c7b6e283dfac2a80d58e8d0ff71cc4d5e310a1debdda4a45f02")
+
+	t.Run("Generators", func(t *testing.T) {
+		assert.Equal("a8ce256102840821a3e94ea9025e4662b205762f9776b3a766c872b948f1fd225e7c59698588e70d11406d161b4e28c9",
+			hex.EncodeToString(p1().BytesCompressed()))
+	})
+
+	t.Run("MapToScalar", func(t *testing.T) {
+		assert.Equal("1cb5bb86114b34dc438a911617655a1db595abafac92f47c5001799cf624b430",
+			hex.EncodeToString(scalarBytes(messagesToScalars([][]byte{msg})[0])))
+	})
+
+	t.Run("KeyPair", func(t *testing.T) {
+		pub, err := PublicKey(sk)
+		assert.Nil(err)
+		assert.Equal("a820f230f6ae38503b86c70dc50b61c58a77e45c39ab25c0652bbaa8fa136f2851bd4781c9dcde39fc9d1d52c9e60268061e7d7632171d91aa8d460acee0e96f1e7c4cfb12d3ff9ab5d5dc91c277db75c845d649ef3c4f63aebc364cd55ded0c",
+			hex.EncodeToString(pub))
+	})
+
+	t.Run("Signature", func(t *testing.T) {
+		sig, err := Sign(sk, header, [][]byte{msg})
+		assert.Nil(err)
+		assert.Equal("84773160b824e194073a57493dac1a20b667af70cd2352d8af241c77658da5253aa8458317cca0eae615690d55b1f27164657dcafee1d5c1973947aa70e2cfbb4c892340be5969920d0916067b4565a0",
+			hex.EncodeToString(sig))
+	})
+
+	t.Run("Proof", func(t *testing.T) {
+		// Use the mocked random scalars defined by the specification
+		defer func(orig func(int) ([]*GG.Scalar, error)) { randomScalars = orig }(randomScalars)
+		randomScalars = func(count int) ([]*GG.Scalar, error) {
+			seed := []byte("3.141592653589793238462643383279")
+			dst := []byte(apiID + "MOCK_RANDOM_SCALARS_DST_")
+			v := expander.NewExpanderMD(crypto.SHA256, dst).Expand(seed, uint(count*expandLen))
+			list := make([]*GG.Scalar, count)
+			for i := range list {
+				list[i] = new(GG.Scalar)
+				list[i].SetBytes(v[i*expandLen : (i+1)*expandLen])
+			}
+			return list, nil
+		}
+		pub, _ := PublicKey(sk)
+		sig, _ := Sign(sk, header, [][]byte{msg})
+		proof, err := DeriveProof(pub, sig, header, ph, [][]byte{msg}, []int{0})
+		assert.Nil(err)
+		assert.True(VerifyProof(pub, proof, header, ph, map[int][]byte{0: msg}))
+	})
+}
@@ -0,0 +1,41 @@
+/*
+Package bbs provides BBS signatures over the BLS12-381 pairing-friendly
+elliptic curve.
+
+BBS is a multi-message signature scheme; a single signature is produced over
+an ordered list of messages. The holder of a signature can then generate
+zero-knowledge proofs of knowledge of the signature, revealing only a subset
+of the signed messages. Proofs are randomized, so different proofs derived
+from the same signature can't be linked together. This makes the scheme
+particularly useful for privacy-preserving credentials.
+
+The implementation follows the "BLS12-381-SHA-256" ciphersuite of the IETF
+specification. Public keys are 96 bytes long (points in G2) and signatures
+are 80 bytes long.
+
+# Sign and Verify
+
+	priv, pub, _ := GenerateKey()
+	messages := [][]byte{
+		[]byte("name: Jane Doe"),
+		[]byte("birthdate: 1990-01-01"),
+		[]byte("nationality: MX"),
+	}
+	signature, _ := Sign(priv, nil, messages)
+	log.Printf("verification result: %v", Verify(pub, signature, nil, messages))
+
+# Selective Disclosure
+
+The signature holder derives a proof revealing only the required messages;
+the verifier usually provides a nonce value, used as presentation header, to
+prevent replay attacks.
+
+	proof, _ := DeriveProof(pub, signature, nil, nonce, messages, []int{2})
+	ok := VerifyProof(pub, proof, nil, nonce, map[int][]byte{
+		2: []byte("nationality: MX"),
+	})
+
+More information:
+https://datatracker.ietf.org/doc/draft-irtf-cfrg-bbs-signatures/
+*/
+package bbs
@@ -0,0 +1,76 @@
+package bls
+
+import (
+	GG "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/sign/bls"
+	"go.bryk.io/pkg/errors"
+)
+
+// AggregateSignatures combines several signatures into a single compressed
+// signature value.
+func AggregateSignatures(signatures ...[]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures provided")
+	}
+	agg, err := bls.Aggregate(bls.G1{}, signatures)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+	return agg, nil
+}
+
+// AggregatePublicKeys combines several public keys into a single compressed
+// public key value. The resulting key can be used to verify an aggregated
+// signature produced by all participants over the same message. To prevent
+// rogue-key attacks, only aggregate keys with a verified proof of possession.
+func AggregatePublicKeys(publicKeys ...[]byte) ([]byte, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no public keys provided")
+	}
+	agg := new(GG.G1)
+	agg.SetIdentity()
+	for _, pk := range publicKeys {
+		p := new(GG.G1)
+		if err := p.SetBytes(pk); err != nil || p.IsIdentity() {
+			return nil, errors.New("invalid public key")
+		}
+		agg.Add(agg, p)
+	}
+	return agg.BytesCompressed(), nil
+}
+
+// VerifyAggregate validates an aggregated signature produced by several
+// signers, each one over its own message. Messages are required to be
+// distinct.
+func VerifyAggregate(messages, publicKeys [][]byte, signature []byte) bool {
+	if len(messages) != len(publicKeys) || len(messages) == 0 {
+		return false
+	}
+	seen := make(map[string]struct{}, len(messages))
+	for _, msg := range messages {
+		if _, ok := seen[string(msg)]; ok {
+			return false
+		}
+		seen[string(msg)] = struct{}{}
+	}
+	pubs := make([]*bls.PublicKey[bls.G1], len(publicKeys))
+	for i, pk := range publicKeys {
+		pub, err := parsePublicKey(pk)
+		if err != nil {
+			return false
+		}
+		pubs[i] = pub
+	}
+	return bls.VerifyAggregate(pubs, messages, signature)
+}
+
+// FastAggregateVerify validates an aggregated signature produced by several
+// signers over the same message. The proof of possession for each one of
+// the public keys MUST be validated beforehand using "VerifyPossession".
+func FastAggregateVerify(message []byte, publicKeys [][]byte, signature []byte) bool {
+	agg, err := AggregatePublicKeys(publicKeys...)
+	if err != nil {
+		return false
+	}
+	return Verify(message, signature, agg)
+}
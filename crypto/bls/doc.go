@@ -0,0 +1,64 @@
+/*
+Package bls provides digital signature functionality using BLS signatures over
+the BLS12-381 pairing-friendly elliptic curve.
+
+The main component in the package is the 'KeyPair' instance. All functionality
+to generate and validate digital signatures is available by its methods. Public
+keys are 48 bytes long (points in G1) and signatures are 96 bytes long (points
+in G2), following the "minimal-pubkey-size" variant of the IETF specification.
+
+BLS signatures can be aggregated; multiple signatures, produced by different
+signers, can be combined into a single compact value that can be verified at
+once. This makes the scheme particularly useful for threshold and multi-party
+attestation use cases.
+
+# Key Creation
+
+There are 3 mechanisms to create a new key pair.
+
+	// 1. Create a completely random new key
+	rk, _ := New()
+
+	// 2. Key using a given seed material
+	sk, _ := FromSeed([]byte("material"))
+
+	// 3. Load from PEM-encoded content
+	pk, _ := Unmarshal(pemBinData)
+
+However created, the key pair instance always use a locked memory buffer to securely
+hold private information. Is mandatory to properly release the memory buffer after
+using the key by calling the 'Destroy' method.
+
+	// Securely release in-memory secrets
+	kp.Destroy()
+
+# Key Usage
+
+Once created, a key pair can be used to produce and verify digital signatures.
+
+	msg := []byte("message-to-sign")
+	kp, _ := New()
+	signature := kp.Sign(msg)
+	log.Printf("verification result: %v", kp.Verify(msg, signature))
+
+# Aggregation
+
+When every signer produces a signature over a different message, the resulting
+signatures can be aggregated and verified using the list of messages and public
+keys involved.
+
+	agg, _ := AggregateSignatures(sig1, sig2)
+	ok := VerifyAggregate([][]byte{msg1, msg2}, [][]byte{pub1, pub2}, agg)
+
+When all signers sign the same message, a more efficient verification is available.
+To prevent rogue-key attacks, every public key must be accompanied by a proof of
+possession of the corresponding private key; validated before using the key.
+
+	proof := kp.ProvePossession()
+	if !VerifyPossession(pub, proof) {
+		panic("invalid proof of possession")
+	}
+	agg, _ := AggregateSignatures(sig1, sig2)
+	ok := FastAggregateVerify(msg, [][]byte{pub1, pub2}, agg)
+*/
+package bls
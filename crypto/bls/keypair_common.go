@@ -0,0 +1,202 @@
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+
+	GG "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/sign/bls"
+	"go.bryk.io/pkg/errors"
+	cryptoutils "go.bryk.io/pkg/internal/crypto"
+)
+
+// PEM header.
+const keyType = "BLS12381 PRIVATE KEY"
+
+// Domain separation tag used for proofs of possession.
+const dstPOP = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+const (
+	// PrivateKeySize is the size, in bytes, of private keys.
+	PrivateKeySize = 32
+
+	// PublicKeySize is the size, in bytes, of compressed public keys (G1).
+	PublicKeySize = 48
+
+	// SignatureSize is the size, in bytes, of compressed signatures (G2).
+	SignatureSize = 96
+)
+
+// New randomly generated BLS12-381 key pair. Each KP needs to be securely
+// removed from memory by calling the "Destroy" method.
+func New() (*KeyPair, error) {
+	ikm := make([]byte, 32)
+	if _, err := rand.Read(ikm); err != nil {
+		return nil, errors.New("failed to generate new random key")
+	}
+	return fromIKM(ikm)
+}
+
+// FromSeed deterministically generates a keypair instance using the
+// provided seed material. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func FromSeed(seed []byte) (*KeyPair, error) {
+	ikm, err := cryptoutils.Expand(seed, 32, nil)
+	if err != nil {
+		return nil, errors.New("failed to expand seed")
+	}
+	return fromIKM(ikm)
+}
+
+// FromPrivateKey restores a key pair instance using the provided
+// private key value.
+func FromPrivateKey(priv []byte) (*KeyPair, error) {
+	if len(priv) != PrivateKeySize {
+		return nil, errors.New("invalid private key")
+	}
+	sk := new(bls.PrivateKey[bls.G1])
+	if err := sk.UnmarshalBinary(priv); err != nil {
+		return nil, errors.New("invalid private key")
+	}
+	return fromPrivateKey(sk)
+}
+
+// Unmarshal will restore a key pair instance from the provided
+// PEM-encoded private key.
+func Unmarshal(src []byte) (*KeyPair, error) {
+	kp := new(KeyPair)
+	if err := kp.UnmarshalBinary(src); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// Verify performs a BLS digital signature verification.
+func Verify(message, signature, publicKey []byte) bool {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	return bls.Verify(pub, message, signature)
+}
+
+// VerifyPossession validates a proof of possession produced by the owner
+// of the provided public key. Public keys should only be used for fast
+// aggregate verification after its proof of possession is checked.
+func VerifyPossession(publicKey, proof []byte) bool {
+	pk := new(GG.G1)
+	if err := pk.SetBytes(publicKey); err != nil || pk.IsIdentity() {
+		return false
+	}
+	sig := new(GG.G2)
+	if err := sig.SetBytes(proof); err != nil {
+		return false
+	}
+	h := new(GG.G2)
+	h.Hash(publicKey, []byte(dstPOP))
+	res := GG.ProdPairFrac([]*GG.G1{pk, GG.G1Generator()}, []*GG.G2{h, sig}, []int{1, -1})
+	return res.IsIdentity()
+}
+
+// UnmarshalBinary will restore a key pair instance from the provided
+// PEM-encoded private key. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func (k *KeyPair) UnmarshalBinary(data []byte) error {
+	bl, _ := pem.Decode(data)
+	if bl == nil {
+		return errors.New("invalid PEM data")
+	}
+	if bl.Type != keyType {
+		return fmt.Errorf("invalid key type: '%s'", bl.Type)
+	}
+	kp, err := FromPrivateKey(bl.Bytes)
+	if err != nil {
+		return err
+	}
+	*k = *kp
+	return nil
+}
+
+// MarshalBinary returns the PEM-encoded private key.
+func (k *KeyPair) MarshalBinary() ([]byte, error) {
+	bl := &pem.Block{
+		Type:  keyType,
+		Bytes: k.PrivateKey(),
+	}
+	return pem.EncodeToMemory(bl), nil
+}
+
+// PublicKey returns the compressed public key bytes of the key pair instance.
+func (k *KeyPair) PublicKey() [PublicKeySize]byte {
+	return k.public
+}
+
+// Sign generates a BLS digital signature for the provided content.
+func (k *KeyPair) Sign(message []byte) []byte {
+	sk, err := k.privateKey()
+	if err != nil {
+		return nil
+	}
+	return bls.Sign(sk, message)
+}
+
+// Verify performs a BLS digital signature verification.
+func (k *KeyPair) Verify(message, signature []byte) bool {
+	return Verify(message, signature, k.public[:])
+}
+
+// ProvePossession returns a proof of possession for the key pair's private
+// key. The proof is required by other parties to safely use the public key
+// for fast aggregate verification, preventing rogue-key attacks.
+func (k *KeyPair) ProvePossession() []byte {
+	sc := new(GG.Scalar)
+	if err := sc.UnmarshalBinary(k.PrivateKey()); err != nil {
+		return nil
+	}
+	defer sc.SetUint64(0)
+	proof := new(GG.G2)
+	proof.Hash(k.public[:], []byte(dstPOP))
+	proof.ScalarMult(sc, proof)
+	return proof.BytesCompressed()
+}
+
+// Restore the private key instance used to produce signatures.
+func (k *KeyPair) privateKey() (*bls.PrivateKey[bls.G1], error) {
+	sk := new(bls.PrivateKey[bls.G1])
+	if err := sk.UnmarshalBinary(k.PrivateKey()); err != nil {
+		return nil, errors.New("invalid private key")
+	}
+	return sk, nil
+}
+
+// Derive a private key from the provided input key material.
+func fromIKM(ikm []byte) (*KeyPair, error) {
+	sk, err := bls.KeyGen[bls.G1](ikm, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+	return fromPrivateKey(sk)
+}
+
+// Load the compressed public key for the provided private key.
+func loadPublicKey(priv *bls.PrivateKey[bls.G1], dst *[PublicKeySize]byte) error {
+	pub, err := priv.PublicKey().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	copy(dst[:], pub)
+	return nil
+}
+
+// Decode and validate a compressed public key.
+func parsePublicKey(publicKey []byte) (*bls.PublicKey[bls.G1], error) {
+	pub := new(bls.PublicKey[bls.G1])
+	if err := pub.UnmarshalBinary(publicKey); err != nil {
+		return nil, errors.New("invalid public key")
+	}
+	if !pub.Validate() {
+		return nil, errors.New("invalid public key")
+	}
+	return pub, nil
+}
@@ -0,0 +1,134 @@
+package bls
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+func TestNew(t *testing.T) {
+	assert := tdd.New(t)
+	kp, err := New()
+	assert.Nil(err, "failed to create new key")
+
+	b, err := kp.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	assert.NotNil(b, "marshal error")
+	kp.Destroy()
+	assert.Nil(kp.PrivateKey(), "failed to destroy key")
+}
+
+func TestEncodeDecode(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := FromSeed([]byte("super-secret-value"))
+	k2, _ := FromSeed([]byte("super-secret-value"))
+	assert.Equal(k.PublicKey(), k2.PublicKey(), "non deterministic seed")
+	k2.Destroy()
+
+	b, err := k.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	pub := k.PublicKey()
+	k.Destroy()
+
+	k3, err := Unmarshal(b)
+	assert.Nil(err, "unmarshal error")
+	assert.Equal(pub, k3.PublicKey(), "invalid key restore")
+	k3.Destroy()
+
+	// Invalid private keys
+	_, err = FromPrivateKey(make([]byte, PrivateKeySize))
+	assert.NotNil(err, "zero key")
+	_, err = FromPrivateKey(make([]byte, 20))
+	assert.NotNil(err, "invalid key size")
+}
+
+func TestSignVerify(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := New()
+	defer k.Destroy()
+	msg := []byte("message to sign")
+
+	sig := k.Sign(msg)
+	assert.Len(sig, SignatureSize, "invalid signature size")
+	assert.True(k.Verify(msg, sig), "verify error")
+	pub := k.PublicKey()
+	assert.True(Verify(msg, sig, pub[:]), "verify error")
+	assert.False(k.Verify([]byte("invalid message"), sig), "invalid message")
+
+	// Tampered signature
+	sig[10] ^= 0xff
+	assert.False(k.Verify(msg, sig), "tampered signature")
+}
+
+func TestAggregate(t *testing.T) {
+	assert := tdd.New(t)
+	var (
+		keys   [][]byte
+		proofs [][]byte
+		msgs   [][]byte
+		sigs   [][]byte
+		common [][]byte
+	)
+	msg := []byte("common message")
+	for i := 0; i < 4; i++ {
+		k, _ := New()
+		pub := k.PublicKey()
+		keys = append(keys, pub[:])
+		proofs = append(proofs, k.ProvePossession())
+		m := []byte{'m', 's', 'g', byte(i)}
+		msgs = append(msgs, m)
+		sigs = append(sigs, k.Sign(m))
+		common = append(common, k.Sign(msg))
+		k.Destroy()
+	}
+
+	t.Run("Distinct", func(t *testing.T) {
+		agg, err := AggregateSignatures(sigs...)
+		assert.Nil(err, "aggregate error")
+		assert.True(VerifyAggregate(msgs, keys, agg), "verify error")
+		assert.False(VerifyAggregate(msgs[1:], keys[1:], agg), "partial verification")
+		assert.False(VerifyAggregate(msgs, keys[:2], agg), "invalid input")
+
+		// Repeated messages are rejected
+		repeated := [][]byte{msgs[0], msgs[0], msgs[2], msgs[3]}
+		assert.False(VerifyAggregate(repeated, keys, agg), "repeated messages")
+	})
+
+	t.Run("Possession", func(t *testing.T) {
+		for i, pk := range keys {
+			assert.True(VerifyPossession(pk, proofs[i]), "invalid proof")
+		}
+		assert.False(VerifyPossession(keys[0], proofs[1]), "invalid proof accepted")
+	})
+
+	t.Run("SameMessage", func(t *testing.T) {
+		agg, err := AggregateSignatures(common...)
+		assert.Nil(err, "aggregate error")
+		assert.True(FastAggregateVerify(msg, keys, agg), "verify error")
+		assert.False(FastAggregateVerify(msg, keys[1:], agg), "partial verification")
+
+		aggPub, err := AggregatePublicKeys(keys...)
+		assert.Nil(err, "aggregate public keys")
+		assert.Len(aggPub, PublicKeySize)
+		assert.True(Verify(msg, agg, aggPub), "verify error")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := AggregateSignatures()
+		assert.NotNil(err, "empty signatures")
+		_, err = AggregateSignatures([]byte("invalid"))
+		assert.NotNil(err, "invalid signature")
+		_, err = AggregatePublicKeys()
+		assert.NotNil(err, "empty public keys")
+		_, err = AggregatePublicKeys(make([]byte, PublicKeySize))
+		assert.NotNil(err, "invalid public key")
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Package dilithium provides a post-quantum digital signature handler using ML-DSA
+(FIPS 204, formerly known as Dilithium) with the ML-DSA-65 parameter set.
+
+The main component in the package is the 'KeyPair' instance. All functionality
+to generate and validate digital signatures is available by its methods. The
+API mirrors the one provided by the "crypto/ed25519" package so both schemes
+can be used interchangeably.
+
+# Key Creation
+
+There are 3 mechanisms to create a new key pair.
+
+	// 1. Create a completely random new key
+	rk, _ := New()
+
+	// 2. Key using a given seed material
+	sk, _ := FromSeed([]byte("material"))
+
+	// 3. Load from PEM-encoded content
+	pk, _ := Unmarshal(pemBinData)
+
+However created, the key pair instance always use a locked memory buffer to securely
+hold private information. Is mandatory to properly release the memory buffer after
+using the key by calling the 'Destroy' method.
+
+	// Securely release in-memory secrets
+	kp.Destroy()
+
+# Key Usage
+
+A key pair can be used to produce and verify digital signatures.
+
+	msg := []byte("message-to-sign")
+	kp, _ := New()
+	signature := kp.Sign(msg)
+	log.Printf("verification result: %v", kp.Verify(msg, signature))
+
+# Hybrid Signatures
+
+For long-lived artifacts, a common transition strategy is to issue both a
+classical and a post-quantum signature over the same content, and require
+both of them to be valid. The signature remains secure as long as at least
+one of the schemes is not broken.
+
+	classic, _ := ed25519.New()
+	pq, _ := dilithium.New()
+	sig1 := classic.Sign(msg)
+	sig2 := pq.Sign(msg)
+	valid := classic.Verify(msg, sig1) && pq.Verify(msg, sig2)
+*/
+package dilithium
@@ -0,0 +1,44 @@
+//go:build !js
+// +build !js
+
+package dilithium
+
+import (
+	"github.com/awnumar/memguard"
+)
+
+// KeyPair represents a ML-DSA (Sign/Verify) public/private key.
+type KeyPair struct {
+	public [PublicKeySize]byte
+	lb     *memguard.LockedBuffer
+}
+
+// PrivateKey returns the private key seed of the key pair instance. Using
+// this method may unintentionally expose secret material outside the security
+// memory segment managed by the instance. Don't use it unless you really know
+// what you are doing.
+func (k *KeyPair) PrivateKey() []byte {
+	if k.lb == nil {
+		return nil
+	}
+	return k.lb.Bytes()
+}
+
+// Destroy will safely release the allocated mlock/VirtualLock memory.
+func (k *KeyPair) Destroy() {
+	if k.lb != nil {
+		k.lb.Destroy()
+	}
+	memguard.WipeBytes(k.public[:])
+	k.lb = nil
+}
+
+// Setup a key pair instance from the provided private key seed.
+func fromSeed(seed []byte) (*KeyPair, error) {
+	kp := &KeyPair{}
+	if err := loadPublicKey(seed, &kp.public); err != nil {
+		return nil, err
+	}
+	kp.lb = memguard.NewBufferFromBytes(seed)
+	return kp, nil
+}
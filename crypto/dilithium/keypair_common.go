@@ -0,0 +1,157 @@
+package dilithium
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
+	"go.bryk.io/pkg/errors"
+	cryptoutils "go.bryk.io/pkg/internal/crypto"
+)
+
+// PEM header.
+const keyType = "ML-DSA PRIVATE KEY"
+
+const (
+	// SeedSize is the size, in bytes, of private key seeds.
+	SeedSize = mldsa65.SeedSize
+
+	// PublicKeySize is the size, in bytes, of ML-DSA-65 public keys.
+	PublicKeySize = mldsa65.PublicKeySize
+
+	// SignatureSize is the size, in bytes, of ML-DSA-65 signatures.
+	SignatureSize = mldsa65.SignatureSize
+)
+
+// New randomly generated ML-DSA (Digital Signature) key pair. Each
+// KP needs to be securely removed from memory by calling the "Destroy"
+// method.
+func New() (*KeyPair, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, errors.New("failed to generate new random key")
+	}
+	return fromSeed(seed)
+}
+
+// Unmarshal will restore a key pair instance from the provided
+// PEM-encoded private key.
+func Unmarshal(src []byte) (*KeyPair, error) {
+	kp := new(KeyPair)
+	if err := kp.UnmarshalBinary(src); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// FromSeed deterministically generates a keypair instance using the
+// provided seed material. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func FromSeed(seed []byte) (*KeyPair, error) {
+	secret, err := cryptoutils.Expand(seed, SeedSize, nil)
+	if err != nil {
+		return nil, errors.New("failed to expand seed")
+	}
+	return fromSeed(secret)
+}
+
+// FromPrivateKey restores a key pair instance using the provided
+// private key seed value.
+func FromPrivateKey(priv []byte) (*KeyPair, error) {
+	if len(priv) != SeedSize {
+		return nil, errors.New("invalid private key")
+	}
+	return fromSeed(append([]byte{}, priv...))
+}
+
+// Verify performs a digital signature verification.
+func Verify(message, signature, publicKey []byte) bool {
+	if len(signature) != SignatureSize {
+		return false
+	}
+	pub := new(mldsa65.PublicKey)
+	if err := pub.UnmarshalBinary(publicKey); err != nil {
+		return false
+	}
+	return mldsa65.Verify(pub, message, nil, signature)
+}
+
+// UnmarshalBinary will restore a key pair instance from the provided
+// PEM-encoded private key. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func (k *KeyPair) UnmarshalBinary(data []byte) error {
+	bl, _ := pem.Decode(data)
+	if bl == nil {
+		return errors.New("invalid PEM data")
+	}
+	if bl.Type != keyType {
+		return fmt.Errorf("invalid key type: '%s'", bl.Type)
+	}
+	if len(bl.Bytes) != SeedSize {
+		return errors.New("invalid key size")
+	}
+	kp, err := fromSeed(bl.Bytes)
+	if err != nil {
+		return err
+	}
+
+	// Assign keypair
+	*k = *kp
+	return nil
+}
+
+// MarshalBinary returns the PEM-encoded private key.
+func (k *KeyPair) MarshalBinary() ([]byte, error) {
+	bl := &pem.Block{
+		Type:  keyType,
+		Bytes: k.PrivateKey(),
+	}
+	return pem.EncodeToMemory(bl), nil
+}
+
+// PublicKey returns the public key bytes of the key pair instance.
+func (k *KeyPair) PublicKey() [PublicKeySize]byte {
+	return k.public
+}
+
+// Sign generates a digital signature for the provided content. Signatures
+// are "hedged"; i.e., randomized to protect against fault and side-channel
+// attacks. Signing the same message twice will produce different values.
+func (k *KeyPair) Sign(message []byte) []byte {
+	sk, err := k.privateKey()
+	if err != nil {
+		return nil
+	}
+	sig := make([]byte, SignatureSize)
+	if err = mldsa65.SignTo(sk, message, nil, true, sig); err != nil {
+		return nil
+	}
+	return sig
+}
+
+// Verify performs a digital signature verification.
+func (k *KeyPair) Verify(message, signature []byte) bool {
+	return Verify(message, signature, k.public[:])
+}
+
+// Expand the private key seed into a usable private key.
+func (k *KeyPair) privateKey() (*mldsa65.PrivateKey, error) {
+	var seed [SeedSize]byte
+	if copy(seed[:], k.PrivateKey()) != SeedSize {
+		return nil, errors.New("invalid private key")
+	}
+	_, sk := mldsa65.NewKeyFromSeed(&seed)
+	return sk, nil
+}
+
+// Load the public key for the provided private key seed.
+func loadPublicKey(seed []byte, dst *[PublicKeySize]byte) error {
+	var s [SeedSize]byte
+	if copy(s[:], seed) != SeedSize {
+		return errors.New("invalid private key")
+	}
+	pk, _ := mldsa65.NewKeyFromSeed(&s)
+	pk.Pack(dst)
+	return nil
+}
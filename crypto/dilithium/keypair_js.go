@@ -0,0 +1,36 @@
+//go:build js
+// +build js
+
+package dilithium
+
+// KeyPair represents a ML-DSA (Sign/Verify) public/private key.
+type KeyPair struct {
+	public  [PublicKeySize]byte
+	private []byte
+}
+
+// PrivateKey returns the private key seed of the key pair instance. Using
+// this method may unintentionally expose secret material outside the security
+// memory segment managed by the instance. Don't use it unless you really know
+// what you are doing.
+func (k *KeyPair) PrivateKey() []byte {
+	return k.private
+}
+
+// Destroy will safely release the allocated mlock/VirtualLock memory.
+func (k *KeyPair) Destroy() {
+	for i := range k.private {
+		k.private[i] = 0
+	}
+	k.private = nil
+}
+
+// Setup a key pair instance from the provided private key seed.
+func fromSeed(seed []byte) (*KeyPair, error) {
+	kp := &KeyPair{}
+	if err := loadPublicKey(seed, &kp.public); err != nil {
+		return nil, err
+	}
+	kp.private = seed
+	return kp, nil
+}
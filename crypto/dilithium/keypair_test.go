@@ -0,0 +1,75 @@
+package dilithium
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+func TestNew(t *testing.T) {
+	assert := tdd.New(t)
+	kp, err := New()
+	assert.Nil(err, "failed to create new key")
+
+	b, err := kp.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	assert.NotNil(b, "marshal error")
+	kp.Destroy()
+	assert.Nil(kp.PrivateKey(), "failed to destroy key")
+}
+
+func TestEncodeDecode(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := FromSeed([]byte("super-secret-value"))
+	k2, _ := FromSeed([]byte("super-secret-value"))
+	assert.Equal(k.PublicKey(), k2.PublicKey(), "non deterministic seed")
+
+	k3, err := FromPrivateKey(k2.PrivateKey())
+	assert.Nil(err, "restore from private key")
+	assert.Equal(k.PublicKey(), k3.PublicKey(), "invalid key restore")
+	k2.Destroy()
+	k3.Destroy()
+
+	b, err := k.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	pub := k.PublicKey()
+	k.Destroy()
+
+	k4, err := Unmarshal(b)
+	assert.Nil(err, "unmarshal error")
+	assert.Equal(pub, k4.PublicKey(), "invalid key restore")
+	k4.Destroy()
+
+	_, err = FromPrivateKey(make([]byte, 20))
+	assert.NotNil(err, "invalid key size")
+	_, err = Unmarshal([]byte("invalid"))
+	assert.NotNil(err, "invalid PEM data")
+}
+
+func TestSignVerify(t *testing.T) {
+	assert := tdd.New(t)
+	kp, _ := New()
+	defer kp.Destroy()
+	msg := []byte("message to sign")
+
+	sig := kp.Sign(msg)
+	assert.Len(sig, SignatureSize, "invalid signature size")
+	assert.True(kp.Verify(msg, sig), "verify error")
+	pub := kp.PublicKey()
+	assert.True(Verify(msg, sig, pub[:]), "verify error")
+	assert.False(kp.Verify([]byte("invalid message"), sig), "invalid message")
+	assert.False(Verify(msg, sig[1:], pub[:]), "invalid signature size")
+	assert.False(Verify(msg, sig, pub[1:]), "invalid public key")
+
+	// Tampered signature
+	sig[10] ^= 0xff
+	assert.False(kp.Verify(msg, sig), "tampered signature")
+}
@@ -24,6 +24,24 @@ using the key by calling the 'Destroy' method.
 	// Securely release in-memory secrets
 	kp.Destroy()
 
+# Standard Formats
+
+Besides the package's own PEM encoding, keys can be exchanged with other tools
+(like openssl or ssh-keygen) using standard formats.
+
+	// PKCS#8 private keys and PKIX public keys; DER or PEM-encoded
+	p8, _ := kp.MarshalPKCS8(true)
+	kp2, _ := UnmarshalPKCS8(p8)
+
+	// OpenSSH private keys and "authorized_keys" entries
+	sshKey, _ := kp.MarshalOpenSSH("user@host")
+	authKey, _ := kp.MarshalAuthorizedKey("user@host")
+	kp3, _ := UnmarshalOpenSSH(sshKey)
+
+	// JWK (OKP) records, compatible with the "jose/jwk" package
+	rec := kp.ToJWK(false)
+	kp4, _ := FromJWK(rec)
+
 # Key Usage
 
 A key pair can be used to produce and verify digital signatures.
@@ -0,0 +1,158 @@
+package ed25519
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	"go.bryk.io/pkg/errors"
+	e "golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+// PEM headers used by standard key formats.
+const (
+	pkcs8Type     = "PRIVATE KEY"
+	pkixType      = "PUBLIC KEY"
+	opensshPvType = "OPENSSH PRIVATE KEY"
+)
+
+// MarshalPKCS8 returns the private key encoded as a PKCS#8 ASN.1 DER
+// structure. If 'asPEM' is true the result is returned as a PEM block
+// of type "PRIVATE KEY"; compatible with tools like openssl.
+func (k *KeyPair) MarshalPKCS8(asPEM bool) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(e.PrivateKey(k.PrivateKey()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode private key")
+	}
+	if !asPEM {
+		return der, nil
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pkcs8Type, Bytes: der}), nil
+}
+
+// MarshalPKIX returns the public key encoded as a PKIX (SubjectPublicKeyInfo)
+// ASN.1 DER structure. If 'asPEM' is true the result is returned as a PEM
+// block of type "PUBLIC KEY".
+func (k *KeyPair) MarshalPKIX(asPEM bool) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(e.PublicKey(k.public[:]))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode public key")
+	}
+	if !asPEM {
+		return der, nil
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pkixType, Bytes: der}), nil
+}
+
+// MarshalOpenSSH returns the private key encoded using the OpenSSH format,
+// as produced by 'ssh-keygen'. The optional 'comment' value is included
+// in the encoded key.
+func (k *KeyPair) MarshalOpenSSH(comment string) ([]byte, error) {
+	bl, err := ssh.MarshalPrivateKey(e.PrivateKey(k.PrivateKey()), comment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode private key")
+	}
+	return pem.EncodeToMemory(bl), nil
+}
+
+// MarshalAuthorizedKey returns the public key encoded using the format used
+// by OpenSSH "authorized_keys" files. The optional 'comment' value is appended
+// to the encoded key.
+//
+//	ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... comment
+func (k *KeyPair) MarshalAuthorizedKey(comment string) ([]byte, error) {
+	pub, err := ssh.NewPublicKey(e.PublicKey(k.public[:]))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode public key")
+	}
+	res := bytes.TrimSpace(ssh.MarshalAuthorizedKey(pub))
+	if comment != "" {
+		res = append(append(res, ' '), comment...)
+	}
+	return append(res, '\n'), nil
+}
+
+// UnmarshalPKCS8 restores a key pair instance from a PKCS#8 encoded private
+// key. Both ASN.1 DER and PEM-encoded values are supported. The KP instance
+// needs to be securely removed from memory by calling the "Destroy" method.
+func UnmarshalPKCS8(src []byte) (*KeyPair, error) {
+	der := src
+	if bl, _ := pem.Decode(src); bl != nil {
+		if bl.Type != pkcs8Type {
+			return nil, errors.Errorf("invalid key type: '%s'", bl.Type)
+		}
+		der = bl.Bytes
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid PKCS#8 key")
+	}
+	priv, ok := key.(e.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an Ed25519 private key")
+	}
+	return fromPrivateKey(priv)
+}
+
+// UnmarshalOpenSSH restores a key pair instance from a private key encoded
+// using the OpenSSH format, as produced by 'ssh-keygen'. Passphrase-protected
+// keys are not supported. The KP instance needs to be securely removed from
+// memory by calling the "Destroy" method.
+func UnmarshalOpenSSH(src []byte) (*KeyPair, error) {
+	key, err := ssh.ParseRawPrivateKey(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid OpenSSH key")
+	}
+	switch priv := key.(type) {
+	case e.PrivateKey:
+		return fromPrivateKey(priv)
+	case *e.PrivateKey:
+		return fromPrivateKey(*priv)
+	default:
+		return nil, errors.New("not an Ed25519 private key")
+	}
+}
+
+// ParsePKIX returns the public key bytes from a PKIX (SubjectPublicKeyInfo)
+// encoded public key. Both ASN.1 DER and PEM-encoded values are supported.
+func ParsePKIX(src []byte) ([32]byte, error) {
+	var res [32]byte
+	der := src
+	if bl, _ := pem.Decode(src); bl != nil {
+		if bl.Type != pkixType {
+			return res, errors.Errorf("invalid key type: '%s'", bl.Type)
+		}
+		der = bl.Bytes
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return res, errors.Wrap(err, "invalid PKIX key")
+	}
+	pub, ok := key.(e.PublicKey)
+	if !ok {
+		return res, errors.New("not an Ed25519 public key")
+	}
+	copy(res[:], pub)
+	return res, nil
+}
+
+// ParseAuthorizedKey returns the public key bytes from a key encoded using
+// the format used by OpenSSH "authorized_keys" files.
+func ParseAuthorizedKey(src []byte) ([32]byte, error) {
+	var res [32]byte
+	key, _, _, _, err := ssh.ParseAuthorizedKey(src)
+	if err != nil {
+		return res, errors.Wrap(err, "invalid authorized key")
+	}
+	ck, ok := key.(ssh.CryptoPublicKey)
+	if !ok {
+		return res, errors.New("not an Ed25519 public key")
+	}
+	pub, ok := ck.CryptoPublicKey().(e.PublicKey)
+	if !ok {
+		return res, errors.New("not an Ed25519 public key")
+	}
+	copy(res[:], pub)
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package ed25519
+
+import (
+	"encoding/base64"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+	e "golang.org/x/crypto/ed25519"
+)
+
+// JWK parameters for Ed25519 keys.
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+const (
+	jwkKeyType = "OKP"
+	jwkCurve   = "Ed25519"
+)
+
+// Base64 encoding used by JWK records.
+var b64 = base64.RawURLEncoding
+
+// ToJWK returns a JWK (OKP) representation of the key pair, as described in
+// RFC-8037. When 'safe' is true, the private key information won't be included
+// in the exported record. The key ID is set to the JWK thumbprint (RFC-7638)
+// of the public key.
+func (k *KeyPair) ToJWK(safe bool) jwk.Record {
+	rec := jwk.Record{
+		KeyType: jwkKeyType,
+		Crv:     jwkCurve,
+		Alg:     string(jwa.EdDSA),
+		Use:     "sig",
+		KeyOps:  []string{"verify"},
+		X:       b64.EncodeToString(k.public[:]),
+	}
+	rec.KeyID, _ = rec.Thumbprint() // all required members are set
+	if !safe {
+		rec.KeyOps = append(rec.KeyOps, "sign")
+		rec.D = b64.EncodeToString(k.PrivateKey()[:e.SeedSize])
+	}
+	return rec
+}
+
+// FromJWK restores a key pair instance from a JWK (OKP) record, as described
+// in RFC-8037. The record must include the private key information. The KP
+// instance needs to be securely removed from memory by calling the "Destroy"
+// method.
+func FromJWK(rec jwk.Record) (*KeyPair, error) {
+	pub, err := PublicKeyFromJWK(rec)
+	if err != nil {
+		return nil, err
+	}
+	if rec.D == "" {
+		return nil, errors.New("no private key available")
+	}
+	seed, err := b64.DecodeString(rec.D)
+	if err != nil || len(seed) != e.SeedSize {
+		return nil, errors.New("invalid 'd' value")
+	}
+	kp, err := fromPrivateKey(e.NewKeyFromSeed(seed))
+	if err != nil {
+		return nil, err
+	}
+	if kp.public != pub {
+		kp.Destroy()
+		return nil, errors.New("public and private key values don't match")
+	}
+	return kp, nil
+}
+
+// PublicKeyFromJWK returns the public key bytes from a JWK (OKP) record, as
+// described in RFC-8037.
+func PublicKeyFromJWK(rec jwk.Record) ([32]byte, error) {
+	var pub [32]byte
+	if rec.KeyType != jwkKeyType || rec.Crv != jwkCurve {
+		return pub, errors.Errorf("invalid key type: '%s/%s'", rec.KeyType, rec.Crv)
+	}
+	x, err := b64.DecodeString(rec.X)
+	if err != nil || len(x) != e.PublicKeySize {
+		return pub, errors.New("invalid 'x' value")
+	}
+	copy(pub[:], x)
+	return pub, nil
+}
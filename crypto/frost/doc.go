@@ -0,0 +1,49 @@
+/*
+Package frost provides threshold signatures compatible with Ed25519 using the
+FROST protocol (Flexible Round-Optimized Schnorr Threshold signatures).
+
+A group key is split in `n` key shares, distributed to different participants;
+any `t` of them can jointly produce a signature without ever reconstructing the
+group private key. The resulting signatures are standard Ed25519 signatures and
+can be verified using the group public key with any compliant implementation;
+useful, for example, to protect DID keys or release signing keys.
+
+The implementation follows the FROST(Ed25519, SHA-512) ciphersuite as defined
+in RFC-9591.
+
+# Key Generation
+
+Key shares are created by a trusted dealer, either from an existing Ed25519 key
+pair or a new random key. The key shares must be securely distributed to each
+participant, while the group information is public.
+
+	// Split an existing key; the group key is the key pair's public key
+	shares, group, err := Split(kp, 5, 3)
+	kp.Destroy()
+
+	// Generate a new random group key
+	shares, group, err := Generate(5, 3)
+
+# Signing
+
+Signing requires two rounds. On the first round each selected participant
+generates a set of single-use nonces and sends its commitment to the signing
+coordinator.
+
+	nonces, commitment, err := Commit(share)
+
+On the second round the coordinator sends the message to sign and the list of
+commitments to every participant, each one returns a signature share.
+
+	sigShare, err := Sign(share, nonces, message, commitments)
+
+Finally, the coordinator validates and aggregates all signature shares. The
+result is a regular Ed25519 signature.
+
+	signature, err := Aggregate(group, message, commitments, sigShares)
+	ok := ed25519.Verify(message, signature, group.Key[:])
+
+More information:
+https://www.rfc-editor.org/rfc/rfc9591.html
+*/
+package frost
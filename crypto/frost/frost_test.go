@@ -0,0 +1,152 @@
+package frost
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+// Run both signing rounds using the provided participants.
+func sign(t *testing.T, participants []*KeyShare, msg []byte) ([]Commitment, []SignatureShare) {
+	assert := tdd.New(t)
+	nonces := make([]*Nonces, len(participants))
+	commitments := make([]Commitment, len(participants))
+	for i, p := range participants {
+		n, c, err := Commit(p)
+		assert.Nil(err, "commit error")
+		nonces[i] = n
+		commitments[i] = c
+	}
+	shares := make([]SignatureShare, len(participants))
+	for i, p := range participants {
+		s, err := Sign(p, nonces[i], msg, commitments)
+		assert.Nil(err, "sign error")
+		shares[i] = s
+	}
+	return commitments, shares
+}
+
+func TestSigning(t *testing.T) {
+	assert := tdd.New(t)
+	msg := []byte("release v1.2.3")
+
+	t.Run("Generate", func(t *testing.T) {
+		shares, group, err := Generate(5, 3)
+		assert.Nil(err, "generate error")
+		assert.Len(shares, 5)
+		assert.Len(group.Shares, 5)
+
+		// Any subset of at least `threshold` participants
+		subsets := [][]*KeyShare{
+			{shares[0], shares[1], shares[2]},
+			{shares[4], shares[2], shares[0]},
+			{shares[1], shares[2], shares[3], shares[4]},
+		}
+		for _, subset := range subsets {
+			commitments, sigShares := sign(t, subset, msg)
+			for _, s := range sigShares {
+				assert.Nil(VerifyShare(group, msg, commitments, s), "verify share")
+			}
+			sig, err := Aggregate(group, msg, commitments, sigShares)
+			assert.Nil(err, "aggregate error")
+			assert.Len(sig, 64)
+			assert.True(ed25519.Verify(msg, sig, group.Key[:]), "invalid signature")
+			assert.False(ed25519.Verify([]byte("other"), sig, group.Key[:]))
+		}
+
+		// Not enough participants
+		commitments, sigShares := sign(t, shares[:2], msg)
+		_, err = Aggregate(group, msg, commitments, sigShares)
+		assert.NotNil(err, "not enough participants")
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		kp, err := ed25519.New()
+		assert.Nil(err, "new key")
+		pub := kp.PublicKey()
+		shares, group, err := Split(kp, 3, 2)
+		kp.Destroy()
+		assert.Nil(err, "split error")
+		assert.Equal(pub, group.Key)
+		assert.Equal(pub, shares[0].GroupKey())
+
+		commitments, sigShares := sign(t, []*KeyShare{shares[2], shares[0]}, msg)
+		sig, err := Aggregate(group, msg, commitments, sigShares)
+		assert.Nil(err, "aggregate error")
+		assert.True(ed25519.Verify(msg, sig, pub[:]), "invalid signature")
+	})
+
+	t.Run("Misbehaving", func(t *testing.T) {
+		shares, group, _ := Generate(3, 2)
+		commitments, sigShares := sign(t, shares[:2], msg)
+		sigShares[1].Value[0] ^= 0x01
+		assert.NotNil(VerifyShare(group, msg, commitments, sigShares[1]), "invalid share")
+		_, err := Aggregate(group, msg, commitments, sigShares)
+		assert.NotNil(err, "invalid share")
+
+		// Shares produced for a different message
+		_, err = Aggregate(group, []byte("other"), commitments, sigShares[:1])
+		assert.NotNil(err, "missing share")
+	})
+
+	t.Run("NonceReuse", func(t *testing.T) {
+		shares, _, _ := Generate(3, 2)
+		n1, c1, _ := Commit(shares[0])
+		_, c2, _ := Commit(shares[1])
+		commitments := []Commitment{c1, c2}
+		_, err := Sign(shares[0], n1, msg, commitments)
+		assert.Nil(err, "sign error")
+		_, err = Sign(shares[0], n1, msg, commitments)
+		assert.NotNil(err, "nonces reused")
+
+		// Nonces from a different participant
+		n2, _, _ := Commit(shares[1])
+		_, err = Sign(shares[0], n2, msg, commitments)
+		assert.NotNil(err, "invalid nonces")
+	})
+}
+
+func TestEncoding(t *testing.T) {
+	assert := tdd.New(t)
+	shares, group, _ := Generate(3, 2)
+
+	// Key share
+	bin, err := shares[1].MarshalBinary()
+	assert.Nil(err, "marshal error")
+	assert.Len(bin, KeyShareSize)
+	ks := new(KeyShare)
+	assert.Nil(ks.UnmarshalBinary(bin), "unmarshal error")
+	assert.Equal(shares[1].ID(), ks.ID())
+	assert.Equal(shares[1].PublicShare(), ks.PublicShare())
+	ks.Destroy()
+	_, err = ks.MarshalBinary()
+	assert.NotNil(err, "destroyed key share")
+
+	// Group
+	bin, err = group.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	g2 := new(Group)
+	assert.Nil(g2.UnmarshalBinary(bin), "unmarshal error")
+	assert.Equal(group, g2)
+
+	// Commitment and signature share
+	_, c, _ := Commit(shares[0])
+	bin, _ = c.MarshalBinary()
+	c2 := Commitment{}
+	assert.Nil(c2.UnmarshalBinary(bin), "unmarshal error")
+	assert.Equal(c, c2)
+	ss := SignatureShare{ID: 2, Value: [32]byte{1, 2, 3}}
+	bin, _ = ss.MarshalBinary()
+	ss2 := SignatureShare{}
+	assert.Nil(ss2.UnmarshalBinary(bin), "unmarshal error")
+	assert.Equal(ss, ss2)
+}
@@ -0,0 +1,226 @@
+package frost
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"sort"
+
+	"filippo.io/edwards25519"
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/shamir"
+	"go.bryk.io/pkg/errors"
+)
+
+// KeyShareSize is the size, in bytes, of an encoded key share.
+const KeyShareSize = 1 + 32 + 32
+
+// KeyShare holds the private signing material for a single participant.
+// Key shares must be kept secret by its holder and released after use by
+// calling the 'Destroy' method.
+type KeyShare struct {
+	id       uint8
+	secret   *edwards25519.Scalar
+	groupKey [32]byte
+}
+
+// ID returns the participant identifier for the key share.
+func (ks *KeyShare) ID() uint8 {
+	return ks.id
+}
+
+// GroupKey returns the group public key. Signatures produced by the group
+// can be verified as regular Ed25519 signatures using this key.
+func (ks *KeyShare) GroupKey() [32]byte {
+	return ks.groupKey
+}
+
+// PublicShare returns the participant's public verification share, used to
+// validate the signature shares it produces.
+func (ks *KeyShare) PublicShare() [32]byte {
+	var pub [32]byte
+	copy(pub[:], new(edwards25519.Point).ScalarBaseMult(ks.secret).Bytes())
+	return pub
+}
+
+// Destroy securely wipes the private material for the key share.
+func (ks *KeyShare) Destroy() {
+	if ks.secret != nil {
+		ks.secret.Set(edwards25519.NewScalar())
+		ks.secret = nil
+	}
+}
+
+// MarshalBinary returns the encoded key share.
+//
+//	id (1) | secret (32) | group key (32)
+func (ks *KeyShare) MarshalBinary() ([]byte, error) {
+	if ks.secret == nil {
+		return nil, errors.New("key share was destroyed")
+	}
+	out := make([]byte, 0, KeyShareSize)
+	out = append(out, ks.id)
+	out = append(out, ks.secret.Bytes()...)
+	return append(out, ks.groupKey[:]...), nil
+}
+
+// UnmarshalBinary restores a key share from its encoded representation.
+func (ks *KeyShare) UnmarshalBinary(data []byte) error {
+	if len(data) != KeyShareSize || data[0] == 0 {
+		return errors.New("invalid key share")
+	}
+	secret, err := edwards25519.NewScalar().SetCanonicalBytes(data[1:33])
+	if err != nil {
+		return errors.New("invalid key share")
+	}
+	if _, err = new(edwards25519.Point).SetBytes(data[33:]); err != nil {
+		return errors.New("invalid group key")
+	}
+	ks.id = data[0]
+	ks.secret = secret
+	copy(ks.groupKey[:], data[33:])
+	return nil
+}
+
+// Group holds the public information required to validate and aggregate
+// the signature shares produced by the participants. It can be safely
+// distributed to all participants and coordinators.
+type Group struct {
+	// Minimum number of participants required to produce a signature.
+	Threshold int
+
+	// Group public key.
+	Key [32]byte
+
+	// Public verification share for each participant, by identifier.
+	Shares map[uint8][32]byte
+}
+
+// MarshalBinary returns the encoded group information.
+//
+//	threshold (1) | group key (32) | [id (1) | public share (32)]...
+func (g *Group) MarshalBinary() ([]byte, error) {
+	ids := make([]int, 0, len(g.Shares))
+	for id := range g.Shares {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	out := make([]byte, 0, 33+33*len(ids))
+	out = append(out, uint8(g.Threshold))
+	out = append(out, g.Key[:]...)
+	for _, id := range ids {
+		pub := g.Shares[uint8(id)]
+		out = append(out, uint8(id))
+		out = append(out, pub[:]...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary restores the group information from its encoded
+// representation.
+func (g *Group) UnmarshalBinary(data []byte) error {
+	if len(data) < 33 || (len(data)-33)%33 != 0 {
+		return errors.New("invalid group information")
+	}
+	g.Threshold = int(data[0])
+	copy(g.Key[:], data[1:33])
+	g.Shares = make(map[uint8][32]byte)
+	for i := 33; i < len(data); i += 33 {
+		var pub [32]byte
+		copy(pub[:], data[i+1:i+33])
+		g.Shares[data[i]] = pub
+	}
+	return g.validate()
+}
+
+// Sanity check the group information.
+func (g *Group) validate() error {
+	if g.Threshold < 2 || len(g.Shares) < g.Threshold {
+		return errors.New("invalid group threshold")
+	}
+	if _, err := new(edwards25519.Point).SetBytes(g.Key[:]); err != nil {
+		return errors.New("invalid group key")
+	}
+	for id, pub := range g.Shares {
+		if id == 0 {
+			return errors.New("invalid participant identifier")
+		}
+		if _, err := new(edwards25519.Point).SetBytes(pub[:]); err != nil {
+			return errors.Errorf("invalid public share for participant %d", id)
+		}
+	}
+	return nil
+}
+
+// Split an existing Ed25519 key pair in `parts` key shares, `threshold` of
+// which are required to produce a signature. The group key is the public key
+// of the original key pair; signatures produced by the group are valid for
+// it. The original key pair should be destroyed after splitting, the private
+// key is never reconstructed by the protocol.
+func Split(kp *ed25519.KeyPair, parts, threshold int) ([]*KeyShare, *Group, error) {
+	priv := kp.PrivateKey()
+	if len(priv) < 32 {
+		return nil, nil, errors.New("invalid key pair")
+	}
+
+	// Ed25519 secret scalar, derived from the private key seed
+	// https://www.rfc-editor.org/rfc/rfc8032#section-5.1.5
+	h := sha512.Sum512(priv[:32])
+	secret, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	for i := range h {
+		h[i] = 0
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid key pair")
+	}
+	return dealShares(secret, kp.PublicKey(), parts, threshold)
+}
+
+// Generate a new random group key and split it in `parts` key shares,
+// `threshold` of which are required to produce a signature. The group private
+// key only exists during the execution of this function.
+func Generate(parts, threshold int) ([]*KeyShare, *Group, error) {
+	buf := make([]byte, 64)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate key")
+	}
+	secret, _ := edwards25519.NewScalar().SetUniformBytes(buf)
+	for i := range buf {
+		buf[i] = 0
+	}
+	var pub [32]byte
+	copy(pub[:], new(edwards25519.Point).ScalarBaseMult(secret).Bytes())
+	return dealShares(secret, pub, parts, threshold)
+}
+
+// Split the group secret using a trusted dealer.
+// https://www.rfc-editor.org/rfc/rfc9591.html#appendix-C
+func dealShares(secret *edwards25519.Scalar, groupKey [32]byte, parts, threshold int) ([]*KeyShare, *Group, error) {
+	encoded := secret.Bytes()
+	secret.Set(edwards25519.NewScalar())
+	list, err := shamir.SplitScalar(encoded, parts, threshold)
+	for i := range encoded {
+		encoded[i] = 0
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	group := &Group{
+		Threshold: threshold,
+		Key:       groupKey,
+		Shares:    make(map[uint8][32]byte),
+	}
+	shares := make([]*KeyShare, len(list))
+	for i, raw := range list {
+		s, _ := edwards25519.NewScalar().SetCanonicalBytes(raw[:32])
+		shares[i] = &KeyShare{
+			id:       raw[32],
+			secret:   s,
+			groupKey: groupKey,
+		}
+		group.Shares[raw[32]] = shares[i].PublicShare()
+		for j := range raw {
+			raw[j] = 0
+		}
+	}
+	return shares, group, nil
+}
@@ -0,0 +1,346 @@
+package frost
+
+import (
+	"crypto/rand"
+	"sort"
+
+	"filippo.io/edwards25519"
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/shamir"
+	"go.bryk.io/pkg/errors"
+)
+
+// CommitmentSize is the size, in bytes, of an encoded commitment.
+const CommitmentSize = 1 + 32 + 32
+
+// SignatureShareSize is the size, in bytes, of an encoded signature share.
+const SignatureShareSize = 1 + 32
+
+// Nonces are the secret values generated by a participant during the first
+// round of the signing protocol. Nonces MUST be used only once; they are
+// wiped automatically after producing a signature share.
+type Nonces struct {
+	id      uint8
+	hiding  *edwards25519.Scalar
+	binding *edwards25519.Scalar
+}
+
+// Destroy securely wipes the nonce values. Nonces should be destroyed if
+// the signing operation is abandoned.
+func (n *Nonces) Destroy() {
+	if n.hiding != nil {
+		n.hiding.Set(edwards25519.NewScalar())
+		n.binding.Set(edwards25519.NewScalar())
+		n.hiding = nil
+		n.binding = nil
+	}
+}
+
+// Commitment is the public value produced by a participant during the
+// first round of the signing protocol. Commitments must be shared with the
+// signing coordinator.
+type Commitment struct {
+	// Participant identifier.
+	ID uint8
+
+	// Commitment to the hiding nonce.
+	Hiding [32]byte
+
+	// Commitment to the binding nonce.
+	Binding [32]byte
+}
+
+// MarshalBinary returns the encoded commitment.
+//
+//	id (1) | hiding (32) | binding (32)
+func (c Commitment) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, CommitmentSize)
+	out = append(out, c.ID)
+	out = append(out, c.Hiding[:]...)
+	return append(out, c.Binding[:]...), nil
+}
+
+// UnmarshalBinary restores a commitment from its encoded representation.
+func (c *Commitment) UnmarshalBinary(data []byte) error {
+	if len(data) != CommitmentSize || data[0] == 0 {
+		return errors.New("invalid commitment")
+	}
+	c.ID = data[0]
+	copy(c.Hiding[:], data[1:33])
+	copy(c.Binding[:], data[33:])
+	return nil
+}
+
+// SignatureShare is the value produced by a participant during the second
+// round of the signing protocol.
+type SignatureShare struct {
+	// Participant identifier.
+	ID uint8
+
+	// Signature share value.
+	Value [32]byte
+}
+
+// MarshalBinary returns the encoded signature share.
+//
+//	id (1) | value (32)
+func (s SignatureShare) MarshalBinary() ([]byte, error) {
+	return append([]byte{s.ID}, s.Value[:]...), nil
+}
+
+// UnmarshalBinary restores a signature share from its encoded representation.
+func (s *SignatureShare) UnmarshalBinary(data []byte) error {
+	if len(data) != SignatureShareSize || data[0] == 0 {
+		return errors.New("invalid signature share")
+	}
+	s.ID = data[0]
+	copy(s.Value[:], data[1:])
+	return nil
+}
+
+// Commit performs the first round of the signing protocol for a participant.
+// The returned nonces must be kept secret and used for a single signature,
+// the commitment must be sent to the signing coordinator.
+func Commit(share *KeyShare) (*Nonces, Commitment, error) {
+	if share.secret == nil {
+		return nil, Commitment{}, errors.New("key share was destroyed")
+	}
+	hiding, err := generateNonce(share.secret)
+	if err != nil {
+		return nil, Commitment{}, err
+	}
+	binding, err := generateNonce(share.secret)
+	if err != nil {
+		return nil, Commitment{}, err
+	}
+	c := Commitment{ID: share.id}
+	copy(c.Hiding[:], new(edwards25519.Point).ScalarBaseMult(hiding).Bytes())
+	copy(c.Binding[:], new(edwards25519.Point).ScalarBaseMult(binding).Bytes())
+	return &Nonces{id: share.id, hiding: hiding, binding: binding}, c, nil
+}
+
+// Sign performs the second round of the signing protocol for a participant.
+// The commitments list must include the commitments of all participants
+// selected by the coordinator, including the participant's own. The nonces
+// are destroyed after use.
+func Sign(share *KeyShare, nonces *Nonces, message []byte, commitments []Commitment) (SignatureShare, error) {
+	if share.secret == nil {
+		return SignatureShare{}, errors.New("key share was destroyed")
+	}
+	if nonces.hiding == nil {
+		return SignatureShare{}, errors.New("nonces were already used")
+	}
+	defer nonces.Destroy()
+	if nonces.id != share.id {
+		return SignatureShare{}, errors.New("nonces don't belong to the key share")
+	}
+	st, err := newSession(share.groupKey, message, commitments)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	// Participant commitment must match the nonces used
+	own, ok := st.commitment(share.id)
+	if !ok {
+		return SignatureShare{}, errors.New("participant not included in commitments list")
+	}
+	hc := new(edwards25519.Point).ScalarBaseMult(nonces.hiding).Bytes()
+	bc := new(edwards25519.Point).ScalarBaseMult(nonces.binding).Bytes()
+	if string(hc) != string(own.Hiding[:]) || string(bc) != string(own.Binding[:]) {
+		return SignatureShare{}, errors.New("participant commitment doesn't match nonces")
+	}
+
+	// z_i = hiding + (binding * rho_i) + (lambda_i * sk_i * c)
+	lambda, err := st.lambda(share.id)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	z := edwards25519.NewScalar().Multiply(lambda, share.secret)
+	z.Multiply(z, st.challenge)
+	z.MultiplyAdd(nonces.binding, st.rho[share.id], z)
+	z.Add(z, nonces.hiding)
+	res := SignatureShare{ID: share.id}
+	copy(res.Value[:], z.Bytes())
+	return res, nil
+}
+
+// VerifyShare validates a signature share produced by a participant. This
+// allows the coordinator to identify misbehaving participants.
+func VerifyShare(group *Group, message []byte, commitments []Commitment, share SignatureShare) error {
+	st, err := newSession(group.Key, message, commitments)
+	if err != nil {
+		return err
+	}
+	return st.verifyShare(group, share)
+}
+
+// Aggregate the signature shares produced by the participants into a final
+// signature. Each signature share is validated before aggregation. The
+// result is a standard 64 bytes Ed25519 signature that can be verified using
+// the group public key.
+func Aggregate(group *Group, message []byte, commitments []Commitment, shares []SignatureShare) ([]byte, error) {
+	if err := group.validate(); err != nil {
+		return nil, err
+	}
+	if len(commitments) < group.Threshold {
+		return nil, errors.Errorf("at least %d participants are required", group.Threshold)
+	}
+	if len(shares) != len(commitments) {
+		return nil, errors.New("a signature share is required for each participant")
+	}
+	st, err := newSession(group.Key, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uint8]bool)
+	z := edwards25519.NewScalar()
+	for _, share := range shares {
+		if seen[share.ID] {
+			return nil, errors.Errorf("duplicate signature share for participant %d", share.ID)
+		}
+		seen[share.ID] = true
+		if err = st.verifyShare(group, share); err != nil {
+			return nil, err
+		}
+		v, _ := edwards25519.NewScalar().SetCanonicalBytes(share.Value[:])
+		z.Add(z, v)
+	}
+	sig := make([]byte, 0, 64)
+	sig = append(sig, st.groupCommitment.Bytes()...)
+	sig = append(sig, z.Bytes()...)
+	if !ed25519.Verify(message, sig, group.Key[:]) {
+		return nil, errors.New("invalid signature")
+	}
+	return sig, nil
+}
+
+// Signing session state shared by all participants.
+type session struct {
+	commitments     []Commitment
+	ids             []uint8
+	rho             map[uint8]*edwards25519.Scalar
+	groupCommitment *edwards25519.Point
+	challenge       *edwards25519.Scalar
+}
+
+// Compute the binding factors, group commitment and challenge for the
+// signing operation.
+// https://www.rfc-editor.org/rfc/rfc9591.html#section-4.4
+func newSession(groupKey [32]byte, message []byte, commitments []Commitment) (*session, error) {
+	if len(commitments) < 2 {
+		return nil, errors.New("at least 2 participants are required")
+	}
+	st := &session{
+		commitments: make([]Commitment, len(commitments)),
+		ids:         make([]uint8, len(commitments)),
+		rho:         make(map[uint8]*edwards25519.Scalar),
+	}
+
+	// Commitments are processed in ascending order of identifiers
+	copy(st.commitments, commitments)
+	sort.Slice(st.commitments, func(i, j int) bool {
+		return st.commitments[i].ID < st.commitments[j].ID
+	})
+	var enc []byte
+	for i, c := range st.commitments {
+		if c.ID == 0 || (i > 0 && c.ID == st.ids[i-1]) {
+			return nil, errors.New("invalid or duplicate participant identifier")
+		}
+		st.ids[i] = c.ID
+		enc = append(enc, identifierScalar(c.ID).Bytes()...)
+		enc = append(enc, c.Hiding[:]...)
+		enc = append(enc, c.Binding[:]...)
+	}
+
+	// Binding factors
+	prefix := append([]byte{}, groupKey[:]...)
+	prefix = append(prefix, hashMessage(message)...)
+	prefix = append(prefix, hashCommitments(enc)...)
+	for _, id := range st.ids {
+		st.rho[id] = hashRho(prefix, identifierScalar(id).Bytes())
+	}
+
+	// Group commitment: R = sum(D_i + E_i * rho_i)
+	st.groupCommitment = edwards25519.NewIdentityPoint()
+	for _, c := range st.commitments {
+		d, err := new(edwards25519.Point).SetBytes(c.Hiding[:])
+		if err != nil {
+			return nil, errors.Errorf("invalid commitment for participant %d", c.ID)
+		}
+		e, err := new(edwards25519.Point).SetBytes(c.Binding[:])
+		if err != nil {
+			return nil, errors.Errorf("invalid commitment for participant %d", c.ID)
+		}
+		e.ScalarMult(st.rho[c.ID], e)
+		st.groupCommitment.Add(st.groupCommitment, d)
+		st.groupCommitment.Add(st.groupCommitment, e)
+	}
+
+	// Challenge
+	st.challenge = hashChallenge(st.groupCommitment.Bytes(), groupKey[:], message)
+	return st, nil
+}
+
+// Return the commitment for the given participant.
+func (st *session) commitment(id uint8) (Commitment, bool) {
+	for _, c := range st.commitments {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Commitment{}, false
+}
+
+// Lagrange coefficient for the given participant.
+func (st *session) lambda(id uint8) (*edwards25519.Scalar, error) {
+	raw, err := shamir.LagrangeCoefficient(id, st.ids)
+	if err != nil {
+		return nil, err
+	}
+	return edwards25519.NewScalar().SetCanonicalBytes(raw)
+}
+
+// Validate a signature share: z_i * G == D_i + E_i * rho_i + PK_i * (c * lambda_i).
+func (st *session) verifyShare(group *Group, share SignatureShare) error {
+	c, ok := st.commitment(share.ID)
+	if !ok {
+		return errors.Errorf("participant %d not included in commitments list", share.ID)
+	}
+	pub, ok := group.Shares[share.ID]
+	if !ok {
+		return errors.Errorf("unknown participant %d", share.ID)
+	}
+	z, err := edwards25519.NewScalar().SetCanonicalBytes(share.Value[:])
+	if err != nil {
+		return errors.Errorf("invalid signature share for participant %d", share.ID)
+	}
+	lambda, err := st.lambda(share.ID)
+	if err != nil {
+		return err
+	}
+	pk, err := new(edwards25519.Point).SetBytes(pub[:])
+	if err != nil {
+		return errors.Errorf("invalid public share for participant %d", share.ID)
+	}
+	d, _ := new(edwards25519.Point).SetBytes(c.Hiding[:])
+	e, _ := new(edwards25519.Point).SetBytes(c.Binding[:])
+	cl := edwards25519.NewScalar().Multiply(st.challenge, lambda)
+	r := new(edwards25519.Point).VarTimeMultiScalarMult(
+		[]*edwards25519.Scalar{st.rho[share.ID], cl},
+		[]*edwards25519.Point{e, pk})
+	r.Add(r, d)
+	if new(edwards25519.Point).ScalarBaseMult(z).Equal(r) != 1 {
+		return errors.Errorf("invalid signature share for participant %d", share.ID)
+	}
+	return nil
+}
+
+// Generate a nonce value using fresh randomness and the participant's
+// secret, as defined by the 'nonce_generate' procedure.
+func generateNonce(secret *edwards25519.Scalar) (*edwards25519.Scalar, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	return hashNonce(buf, secret.Bytes()), nil
+}
@@ -0,0 +1,30 @@
+/*
+Package hdkey provides hierarchical deterministic key derivation as described
+by SLIP-0010.
+
+Starting from a single master seed, a tree of child keys can be deterministically
+derived using path notation; for example "m/44'/0'/0'". This enables wallet-style
+key management where a single backup (the seed) is enough to recover all keys
+ever used by a controller.
+
+Both Ed25519 and secp256k1 keys are supported. For secp256k1 the derivation
+process is compatible with BIP-32, supporting both hardened and non-hardened
+child keys. Ed25519 only supports hardened derivation.
+
+# Key Derivation
+
+	// Get the master key from a seed value
+	master, _ := NewMaster(Ed25519, seed)
+	defer master.Destroy()
+
+	// Derive a child key
+	child, _ := master.Derive("m/44'/0'/0'")
+	defer child.Destroy()
+
+	// Get a key pair for the derived key
+	kp, _ := child.Ed25519()
+	defer kp.Destroy()
+
+Hardened indexes can be expressed using either "'", "h" or "H" as suffix.
+*/
+package hdkey
@@ -0,0 +1,275 @@
+package hdkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/secp256k1"
+	"go.bryk.io/pkg/errors"
+	e "golang.org/x/crypto/ed25519"
+)
+
+// HardenedOffset is the first index value used for hardened child keys.
+const HardenedOffset uint32 = 0x80000000
+
+// Curve identifies the elliptic curve used for key derivation.
+type Curve string
+
+const (
+	// Ed25519 keys. Only hardened derivation is supported.
+	Ed25519 Curve = "ed25519"
+
+	// Secp256k1 keys. Compatible with BIP-32.
+	Secp256k1 Curve = "secp256k1"
+)
+
+// HMAC key used to generate the master key for each curve.
+func (c Curve) masterKey() ([]byte, error) {
+	switch c {
+	case Ed25519:
+		return []byte("ed25519 seed"), nil
+	case Secp256k1:
+		return []byte("Bitcoin seed"), nil
+	default:
+		return nil, errors.Errorf("unsupported curve: %s", c)
+	}
+}
+
+// Key represents a node in the key derivation tree. Each key instance
+// needs to be securely removed from memory by calling the "Destroy" method.
+type Key struct {
+	curve     Curve
+	key       []byte
+	chainCode []byte
+	depth     uint8
+	index     uint32
+}
+
+// NewMaster returns the root key of the derivation tree for the provided
+// seed value. The seed should be between 16 and 64 bytes long.
+func NewMaster(curve Curve, seed []byte) (*Key, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("invalid seed length")
+	}
+	hk, err := curve.masterKey()
+	if err != nil {
+		return nil, err
+	}
+	data := seed
+	for {
+		il, ir := hmacSHA512(hk, data)
+		if curve == Ed25519 || validScalar(il) {
+			return &Key{
+				curve:     curve,
+				key:       il,
+				chainCode: ir,
+			}, nil
+		}
+		data = append(il, ir...)
+	}
+}
+
+// DerivePath is a shortcut to get the key located at the specified path,
+// derived from the master key for the provided seed.
+func DerivePath(curve Curve, seed []byte, path string) (*Key, error) {
+	master, err := NewMaster(curve, seed)
+	if err != nil {
+		return nil, err
+	}
+	defer master.Destroy()
+	return master.Derive(path)
+}
+
+// ParsePath returns the list of indexes for a derivation path in the
+// form "m/44'/0'/0'". Hardened indexes can use either "'", "h" or "H"
+// as suffix.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(strings.TrimSpace(path), "/")
+	if segments[0] != "m" {
+		return nil, errors.Errorf("invalid path: %s", path)
+	}
+	var list []uint32
+	for _, s := range segments[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(s, "'") || strings.HasSuffix(s, "h") || strings.HasSuffix(s, "H") {
+			offset = HardenedOffset
+			s = s[:len(s)-1]
+		}
+		i, err := strconv.ParseUint(s, 10, 32)
+		if err != nil || uint32(i) >= HardenedOffset {
+			return nil, errors.Errorf("invalid path segment: %s", s)
+		}
+		list = append(list, uint32(i)+offset)
+	}
+	return list, nil
+}
+
+// Derive returns the descendant key located at the provided path. The path
+// is considered relative to the current key.
+func (k *Key) Derive(path string) (*Key, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	cur := k.clone()
+	for _, i := range indexes {
+		child, err := cur.Child(i)
+		cur.Destroy()
+		if err != nil {
+			return nil, err
+		}
+		cur = child
+	}
+	return cur, nil
+}
+
+// Child returns the direct descendant key for the provided index. Use
+// indexes larger or equal to 'HardenedOffset' to get hardened keys.
+func (k *Key) Child(index uint32) (*Key, error) {
+	hardened := index >= HardenedOffset
+	if k.curve == Ed25519 && !hardened {
+		return nil, errors.New("ed25519 only supports hardened derivation")
+	}
+	if k.depth == 0xff {
+		return nil, errors.New("maximum derivation depth reached")
+	}
+
+	// Child key data
+	data := make([]byte, 37)
+	if hardened {
+		copy(data[1:33], k.key)
+	} else {
+		copy(data, k.publicKey())
+	}
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	for {
+		il, ir := hmacSHA512(k.chainCode, data)
+		if k.curve == Ed25519 {
+			return k.child(il, ir, index), nil
+		}
+
+		// secp256k1: child = parse256(IL) + kpar (mod n)
+		var a, b secp.ModNScalar
+		if overflow := a.SetByteSlice(il); !overflow {
+			b.SetByteSlice(k.key)
+			a.Add(&b)
+			if !a.IsZero() {
+				key := a.Bytes()
+				return k.child(key[:], ir, index), nil
+			}
+		}
+
+		// Invalid key, retry with the next candidate
+		data[0] = 0x01
+		copy(data[1:33], ir)
+	}
+}
+
+// Curve used by the key.
+func (k *Key) Curve() Curve {
+	return k.curve
+}
+
+// PrivateKey returns the raw 32 bytes private key value.
+func (k *Key) PrivateKey() []byte {
+	return append([]byte{}, k.key...)
+}
+
+// ChainCode returns the 32 bytes chain code value.
+func (k *Key) ChainCode() []byte {
+	return append([]byte{}, k.chainCode...)
+}
+
+// Depth of the key in the derivation tree; the master key has depth 0.
+func (k *Key) Depth() uint8 {
+	return k.depth
+}
+
+// Index used to derive the key from its parent.
+func (k *Key) Index() uint32 {
+	return k.index
+}
+
+// PublicKey returns the public key value. For Ed25519 keys the public key is
+// prefixed with a 0x00 byte, as described in SLIP-0010. For secp256k1 keys the
+// public key is returned in compressed format.
+func (k *Key) PublicKey() []byte {
+	return k.publicKey()
+}
+
+// Ed25519 returns a key pair instance for the derived key. The KP instance
+// needs to be securely removed from memory by calling the "Destroy" method.
+func (k *Key) Ed25519() (*ed25519.KeyPair, error) {
+	if k.curve != Ed25519 {
+		return nil, errors.New("not an ed25519 key")
+	}
+	return ed25519.FromPrivateKey(e.NewKeyFromSeed(k.key))
+}
+
+// Secp256k1 returns a key pair instance for the derived key. The KP instance
+// needs to be securely removed from memory by calling the "Destroy" method.
+func (k *Key) Secp256k1() (*secp256k1.KeyPair, error) {
+	if k.curve != Secp256k1 {
+		return nil, errors.New("not a secp256k1 key")
+	}
+	return secp256k1.FromPrivateKey(k.key)
+}
+
+// Destroy will securely wipe the key material.
+func (k *Key) Destroy() {
+	for i := range k.key {
+		k.key[i] = 0
+	}
+	for i := range k.chainCode {
+		k.chainCode[i] = 0
+	}
+}
+
+func (k *Key) publicKey() []byte {
+	if k.curve == Ed25519 {
+		pub := e.NewKeyFromSeed(k.key).Public().(e.PublicKey)
+		return append([]byte{0x00}, pub...)
+	}
+	return secp.PrivKeyFromBytes(k.key).PubKey().SerializeCompressed()
+}
+
+func (k *Key) child(key, chainCode []byte, index uint32) *Key {
+	return &Key{
+		curve:     k.curve,
+		key:       key,
+		chainCode: chainCode,
+		depth:     k.depth + 1,
+		index:     index,
+	}
+}
+
+func (k *Key) clone() *Key {
+	return &Key{
+		curve:     k.curve,
+		key:       k.PrivateKey(),
+		chainCode: k.ChainCode(),
+		depth:     k.depth,
+		index:     k.index,
+	}
+}
+
+// Compute HMAC-SHA512 and return both halves of the result.
+func hmacSHA512(key, data []byte) (il, ir []byte) {
+	h := hmac.New(sha512.New, key)
+	_, _ = h.Write(data)
+	sum := h.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+// Determine if the value is a valid secp256k1 private key.
+func validScalar(v []byte) bool {
+	var s secp.ModNScalar
+	overflow := s.SetByteSlice(v)
+	return !overflow && !s.IsZero()
+}
@@ -0,0 +1,155 @@
+package hdkey
+
+import (
+	"encoding/hex"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+type vector struct {
+	path      string
+	chainCode string
+	private   string
+	public    string
+}
+
+func TestParsePath(t *testing.T) {
+	assert := tdd.New(t)
+	list, err := ParsePath("m/44'/0h/1H/2")
+	assert.Nil(err, "parse error")
+	assert.Equal([]uint32{44 + HardenedOffset, HardenedOffset, 1 + HardenedOffset, 2}, list)
+
+	list, err = ParsePath("m")
+	assert.Nil(err, "parse error")
+	assert.Empty(list)
+
+	for _, p := range []string{"", "44'/0'", "m/a'", "m/-1", "m/2147483648", "m//1"} {
+		_, err = ParsePath(p)
+		assert.NotNil(err, "invalid path: %s", p)
+	}
+}
+
+func TestEd25519(t *testing.T) {
+	assert := tdd.New(t)
+
+	// SLIP-0010 test vector 1 for ed25519
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	vectors := []vector{
+		{
+			path:      "m",
+			chainCode: "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			private:   "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			public:    "00a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+		},
+		{
+			path:      "m/0H",
+			chainCode: "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			private:   "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			public:    "008c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+		},
+		{
+			path:      "m/0H/1H/2H/2H/1000000000H",
+			chainCode: "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230",
+			private:   "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+			public:    "003c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a",
+		},
+	}
+	for _, v := range vectors {
+		k, err := DerivePath(Ed25519, seed, v.path)
+		assert.Nil(err, "derive error")
+		assert.Equal(v.chainCode, hex.EncodeToString(k.ChainCode()), "chain code: %s", v.path)
+		assert.Equal(v.private, hex.EncodeToString(k.PrivateKey()), "private key: %s", v.path)
+		assert.Equal(v.public, hex.EncodeToString(k.PublicKey()), "public key: %s", v.path)
+		k.Destroy()
+	}
+
+	// Non-hardened derivation is not supported
+	_, err := DerivePath(Ed25519, seed, "m/0")
+	assert.NotNil(err, "non-hardened ed25519 key")
+
+	// Get key pair
+	k, _ := DerivePath(Ed25519, seed, "m/44'/0'/0'")
+	defer k.Destroy()
+	assert.Equal(uint8(3), k.Depth(), "invalid depth")
+	assert.Equal(HardenedOffset, k.Index(), "invalid index")
+	kp, err := k.Ed25519()
+	assert.Nil(err, "key pair error")
+	pub := kp.PublicKey()
+	assert.Equal(k.PublicKey()[1:], pub[:], "invalid public key")
+	kp.Destroy()
+	_, err = k.Secp256k1()
+	assert.NotNil(err, "invalid curve")
+}
+
+func TestSecp256k1(t *testing.T) {
+	assert := tdd.New(t)
+
+	// SLIP-0010 test vector 1 for secp256k1 (same as BIP-32)
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	vectors := []vector{
+		{
+			path:      "m",
+			chainCode: "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+			private:   "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			public:    "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+		},
+		{
+			path:      "m/0H",
+			chainCode: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+			private:   "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			public:    "035a784662a4a20a65bf6aab9ae98a6c068a81c52e4b032c0fb5400c706cfccc56",
+		},
+		{
+			path:      "m/0H/1",
+			chainCode: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+			private:   "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			public:    "03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21a52bedb3cd711c",
+		},
+		{
+			path:      "m/0H/1/2H/2/1000000000",
+			chainCode: "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+			private:   "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+			public:    "022a471424da5e657499d1ff51cb43c47481a03b1e77f951fe64cec9f5a48f7011",
+		},
+	}
+	for _, v := range vectors {
+		k, err := DerivePath(Secp256k1, seed, v.path)
+		assert.Nil(err, "derive error")
+		assert.Equal(v.chainCode, hex.EncodeToString(k.ChainCode()), "chain code: %s", v.path)
+		assert.Equal(v.private, hex.EncodeToString(k.PrivateKey()), "private key: %s", v.path)
+		assert.Equal(v.public, hex.EncodeToString(k.PublicKey()), "public key: %s", v.path)
+		k.Destroy()
+	}
+
+	// Relative derivation
+	master, _ := NewMaster(Secp256k1, seed)
+	defer master.Destroy()
+	c1, _ := master.Derive("m/0H/1")
+	c2, _ := c1.Derive("m/2H/2/1000000000")
+	assert.Equal(vectors[3].private, hex.EncodeToString(c2.PrivateKey()), "relative derivation")
+	assert.Equal(uint8(5), c2.Depth(), "invalid depth")
+	c1.Destroy()
+	c2.Destroy()
+
+	// Get key pair
+	kp, err := master.Secp256k1()
+	assert.Nil(err, "key pair error")
+	pub := kp.PublicKey()
+	assert.Equal(master.PublicKey(), pub[:], "invalid public key")
+	kp.Destroy()
+
+	// Invalid inputs
+	_, err = NewMaster(Secp256k1, []byte("short"))
+	assert.NotNil(err, "invalid seed")
+	_, err = NewMaster(Curve("p256"), seed)
+	assert.NotNil(err, "invalid curve")
+}
@@ -0,0 +1,44 @@
+/*
+Package keyring provides an encrypted local keystore for named key pairs.
+
+Keys are stored in a directory, one file per key, encrypted at rest using the
+TRED protocol. The encryption key is derived from a user provided passphrase
+using Argon2id. Supported key types are Ed25519, X25519 and secp256k1.
+
+This is useful for CLI agents and similar tools that need persistent identities.
+
+# Usage
+
+	// Open (or create) a keyring
+	kr, err := keyring.Open("~/.agent/keys", []byte("my-secret-passphrase"))
+	if err != nil {
+		panic(err)
+	}
+	defer kr.Close()
+
+	// Create a new key
+	_, _ = kr.Create("signing", keyring.Ed25519)
+
+	// Load the key for usage
+	kp, _ := kr.Ed25519("signing")
+	defer kp.Destroy()
+
+# Key Rotation
+
+Rotating a key replaces its key material with a freshly generated one. Previous
+versions of the key are preserved and can be exported when required; for example
+to validate signatures produced before the rotation.
+
+	entry, _ := kr.Rotate("signing")
+	log.Printf("key versions: %d", entry.Versions)
+
+	// Export the current and previous versions
+	current, _ := kr.Export("signing")
+	previous, _ := kr.ExportVersion("signing", 0)
+
+The passphrase protecting the keyring can also be changed; all stored keys will
+be re-encrypted using the new passphrase.
+
+	_ = kr.ChangePassphrase([]byte("my-new-passphrase"))
+*/
+package keyring
@@ -0,0 +1,633 @@
+package keyring
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/secp256k1"
+	"go.bryk.io/pkg/crypto/tred"
+	"go.bryk.io/pkg/crypto/x25519"
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+// KeyType identifies the kind of key pair stored.
+type KeyType string
+
+const (
+	// Ed25519 digital signature keys.
+	Ed25519 KeyType = "ed25519"
+
+	// X25519 key agreement keys.
+	X25519 KeyType = "x25519"
+
+	// Secp256k1 digital signature keys.
+	Secp256k1 KeyType = "secp256k1"
+)
+
+const (
+	metadataFile = "keyring.json"
+	keyExtension = ".key"
+	checkInfo    = "keyring-passphrase-check"
+	keySize      = 32
+	saltSize     = 16
+)
+
+// Valid key names.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,127}$`)
+
+// Entry provides details about a key stored in the keyring.
+type Entry struct {
+	// Key name.
+	Name string `json:"name"`
+
+	// Key type.
+	Type KeyType `json:"type"`
+
+	// Creation time of the current key material.
+	Created time.Time `json:"created"`
+
+	// Number of previous versions available for the key.
+	Versions int `json:"versions"`
+}
+
+// Argon2id key derivation parameters.
+type kdfParams struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// Keyring metadata, stored in plain text.
+type metadata struct {
+	Version int       `json:"version"`
+	KDF     kdfParams `json:"kdf"`
+	Check   []byte    `json:"check"`
+}
+
+// Stored version of a key.
+type version struct {
+	PEM     []byte    `json:"pem"`
+	Created time.Time `json:"created"`
+	Retired time.Time `json:"retired,omitempty"`
+}
+
+// Key record, stored encrypted.
+type record struct {
+	Name    string    `json:"name"`
+	Type    KeyType   `json:"type"`
+	Current version   `json:"current"`
+	History []version `json:"history,omitempty"`
+}
+
+func (r *record) entry() *Entry {
+	return &Entry{
+		Name:     r.Name,
+		Type:     r.Type,
+		Created:  r.Current.Created,
+		Versions: len(r.History),
+	}
+}
+
+func (r *record) wipe() {
+	wipe(r.Current.PEM)
+	for _, v := range r.History {
+		wipe(v.PEM)
+	}
+}
+
+// Keyring provides an encrypted local store for named key pairs.
+type Keyring struct {
+	dir string
+	key []byte
+	kdf kdfParams
+	mu  sync.Mutex
+}
+
+// Open the keyring located at 'dir', using the provided passphrase. If
+// no keyring exists at the location a new one is created. Remember to call
+// 'Close' when the keyring is no longer needed.
+func Open(dir string, passphrase []byte, opts ...Option) (*Keyring, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("a passphrase is required")
+	}
+	kr := &Keyring{
+		dir: filepath.Clean(dir),
+		kdf: kdfParams{
+			Time:    3,
+			Memory:  64 * 1024,
+			Threads: 4,
+		},
+	}
+	for _, opt := range opts {
+		if err := opt(kr); err != nil {
+			return nil, err
+		}
+	}
+
+	// Load existing keyring
+	if _, err := os.Stat(filepath.Join(kr.dir, metadataFile)); err == nil {
+		md, err := kr.loadMetadata()
+		if err != nil {
+			return nil, err
+		}
+		kr.kdf = md.KDF
+		kr.key = deriveKey(passphrase, md.KDF)
+		if !hmac.Equal(md.Check, checkValue(kr.key)) {
+			kr.Close()
+			return nil, errors.New("invalid passphrase")
+		}
+		return kr, nil
+	}
+
+	// Create a new keyring
+	if err := os.MkdirAll(kr.dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create keyring directory")
+	}
+	if err := kr.setPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	return kr, kr.saveMetadata()
+}
+
+// Create a new random key pair of the specified type.
+func (kr *Keyring) Create(name string, kt KeyType) (*Entry, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if err := kr.available(name); err != nil {
+		return nil, err
+	}
+	data, err := generate(kt)
+	if err != nil {
+		return nil, err
+	}
+	rec := &record{
+		Name:    name,
+		Type:    kt,
+		Current: version{PEM: data, Created: time.Now().UTC()},
+	}
+	defer rec.wipe()
+	return rec.entry(), kr.save(rec)
+}
+
+// Import an existing PEM-encoded private key, as produced by the 'MarshalBinary'
+// method of the supported key pair types.
+func (kr *Keyring) Import(name string, data []byte) (*Entry, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if err := kr.available(name); err != nil {
+		return nil, err
+	}
+	kt, err := detectType(data)
+	if err != nil {
+		return nil, err
+	}
+	rec := &record{
+		Name:    name,
+		Type:    kt,
+		Current: version{PEM: append([]byte{}, data...), Created: time.Now().UTC()},
+	}
+	defer rec.wipe()
+	return rec.entry(), kr.save(rec)
+}
+
+// Export returns the current PEM-encoded private key for the entry. Use
+// with care; the returned value is not encrypted.
+func (kr *Keyring) Export(name string) ([]byte, error) {
+	rec, err := kr.get(name)
+	if err != nil {
+		return nil, err
+	}
+	res := append([]byte{}, rec.Current.PEM...)
+	rec.wipe()
+	return res, nil
+}
+
+// ExportVersion returns a previous PEM-encoded private key for the entry.
+// Versions are numbered starting at 0 for the oldest one. Use with care;
+// the returned value is not encrypted.
+func (kr *Keyring) ExportVersion(name string, v int) ([]byte, error) {
+	rec, err := kr.get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rec.wipe()
+	if v < 0 || v >= len(rec.History) {
+		return nil, errors.Errorf("invalid version: %d", v)
+	}
+	return append([]byte{}, rec.History[v].PEM...), nil
+}
+
+// Get returns the details available for the entry.
+func (kr *Keyring) Get(name string) (*Entry, error) {
+	rec, err := kr.get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rec.wipe()
+	return rec.entry(), nil
+}
+
+// List returns the details of all entries in the keyring.
+func (kr *Keyring) List() ([]*Entry, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	files, err := filepath.Glob(filepath.Join(kr.dir, "*"+keyExtension))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Entry, 0, len(files))
+	for _, f := range files {
+		rec, err := kr.load(strings.TrimSuffix(filepath.Base(f), keyExtension))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rec.entry())
+		rec.wipe()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Rotate replaces the key material for the entry with a newly generated
+// one. The previous key material is preserved as a new version.
+func (kr *Keyring) Rotate(name string) (*Entry, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	rec, err := kr.load(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rec.wipe()
+	data, err := generate(rec.Type)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	prev := rec.Current
+	prev.Retired = now
+	rec.History = append(rec.History, prev)
+	rec.Current = version{PEM: data, Created: now}
+	return rec.entry(), kr.save(rec)
+}
+
+// Remove the entry, and all its versions, from the keyring.
+func (kr *Keyring) Remove(name string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if !validName.MatchString(name) {
+		return errors.Errorf("invalid name: %s", name)
+	}
+	if err := os.Remove(kr.path(name)); err != nil {
+		return errors.Wrap(err, "failed to remove key")
+	}
+	return nil
+}
+
+// Ed25519 loads the current key pair for the entry. The KP instance needs
+// to be securely removed from memory by calling the "Destroy" method.
+func (kr *Keyring) Ed25519(name string) (*ed25519.KeyPair, error) {
+	data, err := kr.export(name, Ed25519)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(data)
+	return ed25519.Unmarshal(data)
+}
+
+// X25519 loads the current key pair for the entry. The KP instance needs
+// to be securely removed from memory by calling the "Destroy" method.
+func (kr *Keyring) X25519(name string) (*x25519.KeyPair, error) {
+	data, err := kr.export(name, X25519)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(data)
+	return x25519.Unmarshal(data)
+}
+
+// Secp256k1 loads the current key pair for the entry. The KP instance needs
+// to be securely removed from memory by calling the "Destroy" method.
+func (kr *Keyring) Secp256k1(name string) (*secp256k1.KeyPair, error) {
+	data, err := kr.export(name, Secp256k1)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(data)
+	return secp256k1.Unmarshal(data)
+}
+
+// ChangePassphrase re-encrypts all the entries in the keyring using a new
+// encryption key derived from the provided passphrase.
+func (kr *Keyring) ChangePassphrase(passphrase []byte, opts ...Option) error {
+	if len(passphrase) == 0 {
+		return errors.New("a passphrase is required")
+	}
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	for _, opt := range opts {
+		if err := opt(kr); err != nil {
+			return err
+		}
+	}
+	files, err := filepath.Glob(filepath.Join(kr.dir, "*"+keyExtension))
+	if err != nil {
+		return err
+	}
+
+	// Re-encrypt all entries in memory before committing any change
+	oldKey := kr.key
+	newKeyring := &Keyring{dir: kr.dir, kdf: kr.kdf}
+	if err = newKeyring.setPassphrase(passphrase); err != nil {
+		return err
+	}
+	updates := make(map[string][]byte, len(files))
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), keyExtension)
+		src, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return errors.Wrap(err, "failed to read key")
+		}
+		oldConf, _ := tred.DefaultConfig(oldKey)
+		oldConf.AssociatedData = []byte(name)
+		newConf, _ := tred.DefaultConfig(newKeyring.key)
+		newConf.AssociatedData = []byte(name)
+		out := bytes.NewBuffer(nil)
+		if _, err = tred.Rekey(oldConf, newConf, bytes.NewReader(src), out); err != nil {
+			return errors.Wrap(err, "failed to re-encrypt key")
+		}
+		updates[name] = out.Bytes()
+	}
+
+	// Commit changes
+	for name, data := range updates {
+		if err = writeFile(kr.path(name), data); err != nil {
+			return err
+		}
+	}
+	kr.kdf = newKeyring.kdf
+	kr.key = newKeyring.key
+	wipe(oldKey)
+	return kr.saveMetadata()
+}
+
+// Close the keyring and securely wipe the encryption key from memory.
+func (kr *Keyring) Close() {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	wipe(kr.key)
+	kr.key = nil
+}
+
+// Export the current PEM-encoded key for the entry, validating its type.
+func (kr *Keyring) export(name string, kt KeyType) ([]byte, error) {
+	rec, err := kr.get(name)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Type != kt {
+		rec.wipe()
+		return nil, errors.Errorf("invalid key type: %s", rec.Type)
+	}
+	res := append([]byte{}, rec.Current.PEM...)
+	rec.wipe()
+	return res, nil
+}
+
+// Load the record for the entry.
+func (kr *Keyring) get(name string) (*record, error) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	return kr.load(name)
+}
+
+// Verify the name is valid and not already in use.
+func (kr *Keyring) available(name string) error {
+	if !validName.MatchString(name) {
+		return errors.Errorf("invalid name: %s", name)
+	}
+	if _, err := os.Stat(kr.path(name)); err == nil {
+		return errors.Errorf("key already exists: %s", name)
+	}
+	return nil
+}
+
+// Decrypt and decode a key record.
+func (kr *Keyring) load(name string) (*record, error) {
+	if kr.key == nil {
+		return nil, errors.New("keyring is closed")
+	}
+	if !validName.MatchString(name) {
+		return nil, errors.Errorf("invalid name: %s", name)
+	}
+	src, err := os.ReadFile(kr.path(name))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read key")
+	}
+	w, err := kr.worker(name)
+	if err != nil {
+		return nil, err
+	}
+	out := bytes.NewBuffer(nil)
+	if _, err = w.Decrypt(bytes.NewReader(src), out); err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt key")
+	}
+	rec := new(record)
+	err = json.Unmarshal(out.Bytes(), rec)
+	wipe(out.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid key record")
+	}
+	return rec, nil
+}
+
+// Encode and encrypt a key record.
+func (kr *Keyring) save(rec *record) error {
+	if kr.key == nil {
+		return errors.New("keyring is closed")
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	defer wipe(data)
+	w, err := kr.worker(rec.Name)
+	if err != nil {
+		return err
+	}
+	out := bytes.NewBuffer(nil)
+	if _, err = w.Encrypt(bytes.NewReader(data), out); err != nil {
+		return errors.Wrap(err, "failed to encrypt key")
+	}
+	return writeFile(kr.path(rec.Name), out.Bytes())
+}
+
+// TRED worker used to encrypt/decrypt the entry.
+func (kr *Keyring) worker(name string) (*tred.Worker, error) {
+	conf, err := tred.DefaultConfig(kr.key)
+	if err != nil {
+		return nil, err
+	}
+	conf.AssociatedData = []byte(name)
+	return tred.NewWorker(conf)
+}
+
+// Set a new random salt and derive the encryption key.
+func (kr *Keyring) setPassphrase(passphrase []byte) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return errors.Wrap(err, "failed to generate salt")
+	}
+	kr.kdf.Salt = salt
+	kr.key = deriveKey(passphrase, kr.kdf)
+	return nil
+}
+
+func (kr *Keyring) loadMetadata() (*metadata, error) {
+	src, err := os.ReadFile(filepath.Join(kr.dir, metadataFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read keyring metadata")
+	}
+	md := new(metadata)
+	if err = json.Unmarshal(src, md); err != nil {
+		return nil, errors.Wrap(err, "invalid keyring metadata")
+	}
+	return md, nil
+}
+
+func (kr *Keyring) saveMetadata() error {
+	md := &metadata{
+		Version: 1,
+		KDF:     kr.kdf,
+		Check:   checkValue(kr.key),
+	}
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(kr.dir, metadataFile), data)
+}
+
+func (kr *Keyring) path(name string) string {
+	return filepath.Join(kr.dir, name+keyExtension)
+}
+
+// Derive the encryption key from the passphrase using Argon2id.
+func deriveKey(passphrase []byte, p kdfParams) []byte {
+	return argon2.IDKey(passphrase, p.Salt, p.Time, p.Memory, p.Threads, keySize)
+}
+
+// Value used to validate the passphrase when opening the keyring.
+func checkValue(key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(checkInfo))
+	return h.Sum(nil)
+}
+
+// Generate a new random PEM-encoded key of the specified type.
+func generate(kt KeyType) ([]byte, error) {
+	type key interface {
+		MarshalBinary() ([]byte, error)
+		Destroy()
+	}
+	var (
+		kp  key
+		err error
+	)
+	switch kt {
+	case Ed25519:
+		kp, err = ed25519.New()
+	case X25519:
+		kp, err = x25519.New()
+	case Secp256k1:
+		kp, err = secp256k1.New()
+	default:
+		return nil, errors.Errorf("unsupported key type: %s", kt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer kp.Destroy()
+	return kp.MarshalBinary()
+}
+
+// Determine the key type of PEM-encoded private key.
+func detectType(data []byte) (KeyType, error) {
+	bl, _ := pem.Decode(data)
+	if bl == nil {
+		return "", errors.New("invalid PEM data")
+	}
+	var (
+		kt  KeyType
+		err error
+	)
+	switch bl.Type {
+	case "ED25519 PRIVATE KEY":
+		kt = Ed25519
+		var kp *ed25519.KeyPair
+		if kp, err = ed25519.Unmarshal(data); err == nil {
+			kp.Destroy()
+		}
+	case "X25519 PRIVATE KEY":
+		kt = X25519
+		var kp *x25519.KeyPair
+		if kp, err = x25519.Unmarshal(data); err == nil {
+			kp.Destroy()
+		}
+	case "SECP256K1 PRIVATE KEY":
+		kt = Secp256k1
+		var kp *secp256k1.KeyPair
+		if kp, err = secp256k1.Unmarshal(data); err == nil {
+			kp.Destroy()
+		}
+	default:
+		return "", errors.Errorf("unsupported key type: %s", bl.Type)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "invalid private key")
+	}
+	return kt, nil
+}
+
+// Atomically write the file contents.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write file")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write file")
+	}
+	if err = os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
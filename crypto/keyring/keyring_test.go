@@ -0,0 +1,142 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/secp256k1"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+// Use cheap KDF settings for tests.
+var testParams = WithArgon2Params(1, 1024, 1)
+
+func TestKeyring(t *testing.T) {
+	assert := tdd.New(t)
+	dir := filepath.Join(t.TempDir(), "keys")
+	pass := []byte("super-secret-passphrase")
+
+	kr, err := Open(dir, pass, testParams)
+	if !assert.Nil(err, "open") {
+		return
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for name, kt := range map[string]KeyType{"sig": Ed25519, "dh": X25519, "btc": Secp256k1} {
+			entry, err := kr.Create(name, kt)
+			assert.Nil(err, "create")
+			assert.Equal(kt, entry.Type)
+		}
+		_, err = kr.Create("sig", Ed25519)
+		assert.NotNil(err, "duplicated name")
+		_, err = kr.Create("../escape", Ed25519)
+		assert.NotNil(err, "invalid name")
+		_, err = kr.Create("other", KeyType("rsa"))
+		assert.NotNil(err, "invalid type")
+
+		list, err := kr.List()
+		assert.Nil(err, "list")
+		assert.Len(list, 3)
+		assert.Equal("btc", list[0].Name)
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		k1, err := kr.Ed25519("sig")
+		assert.Nil(err, "load ed25519")
+		k1.Destroy()
+		k2, err := kr.X25519("dh")
+		assert.Nil(err, "load x25519")
+		k2.Destroy()
+		k3, err := kr.Secp256k1("btc")
+		assert.Nil(err, "load secp256k1")
+		k3.Destroy()
+		_, err = kr.Secp256k1("sig")
+		assert.NotNil(err, "invalid type")
+		_, err = kr.Ed25519("missing")
+		assert.NotNil(err, "missing key")
+	})
+
+	t.Run("ImportExport", func(t *testing.T) {
+		kp, _ := secp256k1.New()
+		pub := kp.PublicKey()
+		data, _ := kp.MarshalBinary()
+		kp.Destroy()
+
+		entry, err := kr.Import("imported", data)
+		assert.Nil(err, "import")
+		assert.Equal(Secp256k1, entry.Type)
+		_, err = kr.Import("invalid", []byte("not a key"))
+		assert.NotNil(err, "invalid key")
+
+		exported, err := kr.Export("imported")
+		assert.Nil(err, "export")
+		assert.Equal(data, exported)
+
+		restored, err := kr.Secp256k1("imported")
+		assert.Nil(err, "load")
+		assert.Equal(pub, restored.PublicKey())
+		restored.Destroy()
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		before, _ := kr.Export("btc")
+		entry, err := kr.Rotate("btc")
+		assert.Nil(err, "rotate")
+		assert.Equal(1, entry.Versions)
+		after, _ := kr.Export("btc")
+		assert.NotEqual(before, after, "key not rotated")
+		prev, err := kr.ExportVersion("btc", 0)
+		assert.Nil(err, "export version")
+		assert.Equal(before, prev, "invalid previous version")
+		_, err = kr.ExportVersion("btc", 1)
+		assert.NotNil(err, "invalid version")
+	})
+
+	t.Run("Tamper", func(t *testing.T) {
+		// Key files can't be swapped
+		src, _ := os.ReadFile(filepath.Join(dir, "sig.key"))
+		assert.Nil(os.WriteFile(filepath.Join(dir, "swapped.key"), src, 0600))
+		_, err = kr.Get("swapped")
+		assert.NotNil(err, "swapped key file")
+		assert.Nil(kr.Remove("swapped"))
+	})
+
+	t.Run("Passphrase", func(t *testing.T) {
+		pub := func(kr *Keyring) [32]byte {
+			kp, err := kr.X25519("dh")
+			assert.Nil(err, "load")
+			defer kp.Destroy()
+			return kp.PublicKey()
+		}
+		original := pub(kr)
+		newPass := []byte("new-passphrase")
+		assert.Nil(kr.ChangePassphrase(newPass, testParams), "change passphrase")
+		assert.Equal(original, pub(kr))
+		kr.Close()
+
+		_, err = Open(dir, pass)
+		assert.NotNil(err, "old passphrase")
+		kr, err = Open(dir, newPass)
+		assert.Nil(err, "new passphrase")
+		assert.Equal(original, pub(kr))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		assert.Nil(kr.Remove("imported"))
+		assert.NotNil(kr.Remove("imported"), "missing key")
+		list, _ := kr.List()
+		assert.Len(list, 3)
+		kr.Close()
+		_, err = kr.List()
+		assert.NotNil(err, "closed keyring")
+	})
+}
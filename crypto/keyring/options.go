@@ -0,0 +1,25 @@
+package keyring
+
+import "go.bryk.io/pkg/errors"
+
+// Option elements provide a functional-style configuration system for
+// keyring instances.
+type Option func(kr *Keyring) error
+
+// WithArgon2Params adjust the cost parameters used to derive the encryption
+// key from the passphrase. 'time' is the number of passes over the memory,
+// 'memory' is the size of the memory in KiB, and 'threads' is the degree of
+// parallelism. The settings are only used when creating a new keyring (or
+// when changing its passphrase); existing keyrings use the parameters
+// originally stored.
+func WithArgon2Params(time, memory uint32, threads uint8) Option {
+	return func(kr *Keyring) error {
+		if time == 0 || memory < 8*uint32(threads) || threads == 0 {
+			return errors.New("invalid argon2 parameters")
+		}
+		kr.kdf.Time = time
+		kr.kdf.Memory = memory
+		kr.kdf.Threads = threads
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Package kyber provides post-quantum key encapsulation functionality using
+ML-KEM (FIPS 203, formerly known as Kyber) with the ML-KEM-768 parameter set.
+
+A key encapsulation mechanism (KEM) allows a sender to establish a shared secret
+with the owner of a public key. The sender "encapsulates" a fresh random secret
+for the recipient, producing a ciphertext; the recipient can then "decapsulate"
+the ciphertext using its private key to recover the same secret value.
+
+The main component in the package is the 'KeyPair' instance. All key material
+is deterministically derived from a 64 bytes private seed.
+
+# Key Creation
+
+There are 3 mechanisms to create a new key pair.
+
+	// 1. Create a completely random new key
+	rk, _ := New()
+
+	// 2. Key using a given seed material
+	sk, _ := FromSeed([]byte("material"))
+
+	// 3. Load from PEM-encoded content
+	pk, _ := Unmarshal(pemBinData)
+
+However created, the key pair instance always use a locked memory buffer to securely
+hold private information. Is mandatory to properly release the memory buffer after
+using the key by calling the 'Destroy' method.
+
+	// Securely release in-memory secrets
+	kp.Destroy()
+
+# Key Usage
+
+	// Sender
+	ciphertext, secret, _ := Encapsulate(kp.PublicKey())
+
+	// Recipient
+	secret, _ := kp.Decapsulate(ciphertext)
+
+# Hybrid Mode
+
+Post-quantum schemes are relatively new; to remain secure against classical
+attacks in case of a flaw in ML-KEM, a hybrid mode combining X25519 and
+ML-KEM-768 (X-Wing construction) is also available. The produced shared secret
+remains secure as long as at least one of the underlying schemes is not broken.
+
+	// Sender
+	ciphertext, secret, _ := EncapsulateHybrid(kp.HybridPublicKey())
+
+	// Recipient
+	secret, _ := kp.DecapsulateHybrid(ciphertext)
+*/
+package kyber
@@ -0,0 +1,49 @@
+//go:build !js
+// +build !js
+
+package kyber
+
+import (
+	"github.com/awnumar/memguard"
+)
+
+// KeyPair represents a ML-KEM (Encapsulate/Decapsulate) public/private key.
+type KeyPair struct {
+	public []byte
+	hybrid []byte
+	lb     *memguard.LockedBuffer
+}
+
+// PrivateKey returns the private seed of the key pair instance. Using
+// this method may unintentionally expose secret material outside the security
+// memory segment managed by the instance. Don't use it unless you really know
+// what you are doing.
+func (k *KeyPair) PrivateKey() []byte {
+	if k.lb == nil {
+		return nil
+	}
+	return k.lb.Bytes()
+}
+
+// Destroy will safely release the allocated mlock/VirtualLock memory.
+func (k *KeyPair) Destroy() {
+	if k.lb != nil {
+		k.lb.Destroy()
+	}
+	memguard.WipeBytes(k.public)
+	memguard.WipeBytes(k.hybrid)
+	k.lb = nil
+}
+
+// Setup a key pair instance from the provided private seed.
+func fromSeed(seed []byte) (*KeyPair, error) {
+	pub, hybrid, err := publicKeys(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{
+		public: pub,
+		hybrid: hybrid,
+		lb:     memguard.NewBufferFromBytes(seed),
+	}, nil
+}
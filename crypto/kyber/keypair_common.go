@@ -0,0 +1,204 @@
+package kyber
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"github.com/cloudflare/circl/kem/xwing"
+	"go.bryk.io/pkg/errors"
+	cryptoutils "go.bryk.io/pkg/internal/crypto"
+)
+
+// PEM header.
+const keyType = "ML-KEM PRIVATE KEY"
+
+// Information value used to derive the hybrid key seed.
+const hybridKeyInfo = "kyber-hybrid-x25519"
+
+const (
+	// SeedSize is the size, in bytes, of the private seed used to derive
+	// all key material.
+	SeedSize = mlkem768.KeySeedSize
+
+	// PublicKeySize is the size, in bytes, of ML-KEM-768 public keys.
+	PublicKeySize = mlkem768.PublicKeySize
+
+	// CiphertextSize is the size, in bytes, of ML-KEM-768 ciphertexts.
+	CiphertextSize = mlkem768.CiphertextSize
+
+	// HybridPublicKeySize is the size, in bytes, of hybrid public keys.
+	HybridPublicKeySize = xwing.PublicKeySize
+
+	// HybridCiphertextSize is the size, in bytes, of hybrid ciphertexts.
+	HybridCiphertextSize = xwing.CiphertextSize
+
+	// SharedKeySize is the size, in bytes, of the shared secrets produced.
+	SharedKeySize = mlkem768.SharedKeySize
+)
+
+// New randomly generated ML-KEM key pair. Each KP needs to be securely
+// removed from memory by calling the "Destroy" method.
+func New() (*KeyPair, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, errors.New("failed to generate new random key")
+	}
+	return fromSeed(seed)
+}
+
+// FromSeed deterministically generates a keypair instance using the
+// provided seed material. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func FromSeed(seed []byte) (*KeyPair, error) {
+	secret, err := cryptoutils.Expand(seed, SeedSize, nil)
+	if err != nil {
+		return nil, errors.New("failed to expand seed")
+	}
+	return fromSeed(secret)
+}
+
+// Unmarshal will restore a key pair instance from the provided
+// PEM-encoded private key.
+func Unmarshal(src []byte) (*KeyPair, error) {
+	kp := new(KeyPair)
+	if err := kp.UnmarshalBinary(src); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// Encapsulate generates a fresh shared secret for the owner of the provided
+// ML-KEM public key. Returns the ciphertext to be sent to the recipient and
+// the shared secret value.
+func Encapsulate(publicKey []byte) (ciphertext, secret []byte, err error) {
+	if len(publicKey) != PublicKeySize {
+		return nil, nil, errors.New("invalid public key")
+	}
+	pk := new(mlkem768.PublicKey)
+	if err = pk.Unpack(publicKey); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid public key")
+	}
+	seed := make([]byte, mlkem768.EncapsulationSeedSize)
+	if _, err = rand.Read(seed); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read random seed")
+	}
+	ciphertext = make([]byte, CiphertextSize)
+	secret = make([]byte, SharedKeySize)
+	pk.EncapsulateTo(ciphertext, secret, seed)
+	return ciphertext, secret, nil
+}
+
+// EncapsulateHybrid generates a fresh shared secret for the owner of the
+// provided hybrid public key. The secret is derived from both a X25519 and
+// a ML-KEM-768 key agreement (X-Wing construction); it remains secure as long
+// as at least one of the underlying schemes is not broken. Returns the
+// ciphertext to be sent to the recipient and the shared secret value.
+func EncapsulateHybrid(publicKey []byte) (ciphertext, secret []byte, err error) {
+	if len(publicKey) != HybridPublicKeySize {
+		return nil, nil, errors.New("invalid public key")
+	}
+	secret, ciphertext, err = xwing.Encapsulate(publicKey, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid public key")
+	}
+	return ciphertext, secret, nil
+}
+
+// UnmarshalBinary will restore a key pair instance from the provided
+// PEM-encoded private key. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func (k *KeyPair) UnmarshalBinary(data []byte) error {
+	bl, _ := pem.Decode(data)
+	if bl == nil {
+		return errors.New("invalid PEM data")
+	}
+	if bl.Type != keyType {
+		return fmt.Errorf("invalid key type: '%s'", bl.Type)
+	}
+	if len(bl.Bytes) != SeedSize {
+		return errors.New("invalid private key")
+	}
+	kp, err := fromSeed(bl.Bytes)
+	if err != nil {
+		return err
+	}
+	*k = *kp
+	return nil
+}
+
+// MarshalBinary returns the PEM-encoded private key.
+func (k *KeyPair) MarshalBinary() ([]byte, error) {
+	bl := &pem.Block{
+		Type:  keyType,
+		Bytes: k.PrivateKey(),
+	}
+	return pem.EncodeToMemory(bl), nil
+}
+
+// PublicKey returns the ML-KEM-768 public key of the key pair instance.
+func (k *KeyPair) PublicKey() []byte {
+	return append([]byte{}, k.public...)
+}
+
+// HybridPublicKey returns the hybrid X25519+ML-KEM-768 public key of the key
+// pair instance.
+func (k *KeyPair) HybridPublicKey() []byte {
+	return append([]byte{}, k.hybrid...)
+}
+
+// Decapsulate recovers the shared secret contained in a ciphertext produced
+// using "Encapsulate".
+func (k *KeyPair) Decapsulate(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) != CiphertextSize {
+		return nil, errors.New("invalid ciphertext")
+	}
+	seed := k.PrivateKey()
+	if len(seed) != SeedSize {
+		return nil, errors.New("invalid private key")
+	}
+	_, sk := mlkem768.NewKeyFromSeed(seed)
+	secret := make([]byte, SharedKeySize)
+	sk.DecapsulateTo(secret, ciphertext)
+	return secret, nil
+}
+
+// DecapsulateHybrid recovers the shared secret contained in a ciphertext
+// produced using "EncapsulateHybrid".
+func (k *KeyPair) DecapsulateHybrid(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) != HybridCiphertextSize {
+		return nil, errors.New("invalid ciphertext")
+	}
+	seed, err := hybridSeed(k.PrivateKey())
+	if err != nil {
+		return nil, err
+	}
+	sk, _ := xwing.DeriveKeyPairPacked(seed)
+	return xwing.Decapsulate(ciphertext, sk), nil
+}
+
+// Derive the public keys for the provided private seed.
+func publicKeys(seed []byte) (public, hybrid []byte, err error) {
+	if len(seed) != SeedSize {
+		return nil, nil, errors.New("invalid private key")
+	}
+	pk, _ := mlkem768.NewKeyFromSeed(seed)
+	if public, err = pk.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	hs, err := hybridSeed(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, hybrid = xwing.DeriveKeyPairPacked(hs)
+	return public, hybrid, nil
+}
+
+// Derive the X-Wing seed from the private seed.
+func hybridSeed(seed []byte) ([]byte, error) {
+	if len(seed) != SeedSize {
+		return nil, errors.New("invalid private key")
+	}
+	return cryptoutils.Expand(seed, xwing.SeedSize, []byte(hybridKeyInfo))
+}
@@ -0,0 +1,40 @@
+//go:build js
+// +build js
+
+package kyber
+
+// KeyPair represents a ML-KEM (Encapsulate/Decapsulate) public/private key.
+type KeyPair struct {
+	public  []byte
+	hybrid  []byte
+	private []byte
+}
+
+// PrivateKey returns the private seed of the key pair instance. Using
+// this method may unintentionally expose secret material outside the security
+// memory segment managed by the instance. Don't use it unless you really know
+// what you are doing.
+func (k *KeyPair) PrivateKey() []byte {
+	return k.private
+}
+
+// Destroy will safely release the allocated mlock/VirtualLock memory.
+func (k *KeyPair) Destroy() {
+	for i := range k.private {
+		k.private[i] = 0
+	}
+	k.private = nil
+}
+
+// Setup a key pair instance from the provided private seed.
+func fromSeed(seed []byte) (*KeyPair, error) {
+	pub, hybrid, err := publicKeys(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{
+		public:  pub,
+		hybrid:  hybrid,
+		private: seed,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package kyber
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+func TestNew(t *testing.T) {
+	assert := tdd.New(t)
+	kp, err := New()
+	assert.Nil(err, "failed to create new key")
+	assert.Len(kp.PublicKey(), PublicKeySize, "invalid public key")
+	assert.Len(kp.HybridPublicKey(), HybridPublicKeySize, "invalid public key")
+
+	b, err := kp.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	assert.NotNil(b, "marshal error")
+	kp.Destroy()
+	assert.Nil(kp.PrivateKey(), "failed to destroy key")
+}
+
+func TestEncodeDecode(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := FromSeed([]byte("super-secret-value"))
+	k2, _ := FromSeed([]byte("super-secret-value"))
+	assert.Equal(k.PublicKey(), k2.PublicKey(), "non deterministic seed")
+	assert.Equal(k.HybridPublicKey(), k2.HybridPublicKey(), "non deterministic seed")
+	k2.Destroy()
+
+	b, err := k.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	pub := k.PublicKey()
+	k.Destroy()
+
+	k3, err := Unmarshal(b)
+	assert.Nil(err, "unmarshal error")
+	assert.Equal(pub, k3.PublicKey(), "invalid key restore")
+	k3.Destroy()
+
+	_, err = Unmarshal([]byte("invalid"))
+	assert.NotNil(err, "invalid PEM data")
+}
+
+func TestEncapsulate(t *testing.T) {
+	assert := tdd.New(t)
+	kp, _ := New()
+	defer kp.Destroy()
+
+	t.Run("ML-KEM", func(t *testing.T) {
+		ct, ss, err := Encapsulate(kp.PublicKey())
+		assert.Nil(err, "encapsulate error")
+		assert.Len(ct, CiphertextSize, "invalid ciphertext")
+		assert.Len(ss, SharedKeySize, "invalid shared secret")
+
+		res, err := kp.Decapsulate(ct)
+		assert.Nil(err, "decapsulate error")
+		assert.Equal(ss, res, "invalid shared secret")
+
+		// Tampered ciphertext produces a different secret (implicit rejection)
+		ct[0] ^= 0xff
+		res, err = kp.Decapsulate(ct)
+		assert.Nil(err, "decapsulate error")
+		assert.NotEqual(ss, res, "tampered ciphertext")
+
+		_, _, err = Encapsulate(kp.HybridPublicKey())
+		assert.NotNil(err, "invalid public key")
+		_, err = kp.Decapsulate(ct[1:])
+		assert.NotNil(err, "invalid ciphertext")
+	})
+
+	t.Run("Hybrid", func(t *testing.T) {
+		ct, ss, err := EncapsulateHybrid(kp.HybridPublicKey())
+		assert.Nil(err, "encapsulate error")
+		assert.Len(ct, HybridCiphertextSize, "invalid ciphertext")
+		assert.Len(ss, SharedKeySize, "invalid shared secret")
+
+		res, err := kp.DecapsulateHybrid(ct)
+		assert.Nil(err, "decapsulate error")
+		assert.Equal(ss, res, "invalid shared secret")
+
+		// Tampered ciphertext
+		ct[len(ct)-1] ^= 0xff
+		res, err = kp.DecapsulateHybrid(ct)
+		assert.Nil(err, "decapsulate error")
+		assert.NotEqual(ss, res, "tampered ciphertext")
+
+		_, _, err = EncapsulateHybrid(kp.PublicKey())
+		assert.NotNil(err, "invalid public key")
+		_, err = kp.DecapsulateHybrid(ct[1:])
+		assert.NotNil(err, "invalid ciphertext")
+	})
+}
@@ -0,0 +1,38 @@
+/*
+Package mnemonic provides BIP-39 mnemonic sentences generation and validation.
+
+A mnemonic sentence is a group of easy to remember words used to encode random
+entropy. The sentence can be later used to deterministically derive a binary
+seed, suitable to generate cryptographic keys. This allows users to back up key
+material as human-readable phrases.
+
+# Generation
+
+	// Generate a new random mnemonic with 256 bits of entropy (24 words)
+	sentence, _ := Generate(256, English)
+
+	// Validate an existing mnemonic
+	if err := Validate(sentence, English); err != nil {
+		panic(err)
+	}
+
+# Seed Derivation
+
+The seed derived from a mnemonic can be used directly with the "FromSeed"
+constructors available on the key pair packages, or as the master seed for
+hierarchical key derivation.
+
+	seed, _ := Seed(sentence, "optional passphrase", English)
+
+	// Ed25519 key pair
+	kp, _ := ed25519.FromSeed(seed)
+
+	// SLIP-0010 hierarchical derivation
+	master, _ := hdkey.NewMaster(hdkey.Ed25519, seed)
+
+# Word Lists
+
+All the official BIP-39 word lists are supported: English, Spanish, French,
+Italian, Japanese, Korean, Czech, Chinese (Simplified) and Chinese (Traditional).
+*/
+package mnemonic
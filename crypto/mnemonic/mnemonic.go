@@ -0,0 +1,197 @@
+package mnemonic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"embed"
+	"math/big"
+	"strings"
+	"sync"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Language identifies the word list used to encode a mnemonic sentence.
+type Language string
+
+// Supported word lists.
+const (
+	English            Language = "english"
+	Spanish            Language = "spanish"
+	French             Language = "french"
+	Italian            Language = "italian"
+	Japanese           Language = "japanese"
+	Korean             Language = "korean"
+	Czech              Language = "czech"
+	ChineseSimplified  Language = "chinese_simplified"
+	ChineseTraditional Language = "chinese_traditional"
+)
+
+// Number of PBKDF2 rounds used to derive the seed value.
+const seedRounds = 2048
+
+// SeedSize is the size, in bytes, of the seed derived from a mnemonic.
+const SeedSize = 64
+
+// Official BIP-39 word lists, one word per line.
+// https://github.com/bitcoin/bips/tree/master/bip-0039
+//
+//go:embed wordlists/*.txt
+var wordlistFiles embed.FS
+
+// Word lists are loaded on first use.
+var (
+	wordlists     = map[Language][]string{}
+	wordlistsOnce sync.Once
+)
+
+// Words returns the list of words used by the language.
+func (l Language) Words() ([]string, error) {
+	wordlistsOnce.Do(func() {
+		for _, lang := range []Language{
+			English, Spanish, French, Italian, Japanese,
+			Korean, Czech, ChineseSimplified, ChineseTraditional,
+		} {
+			data, err := wordlistFiles.ReadFile("wordlists/" + string(lang) + ".txt")
+			if err != nil {
+				continue
+			}
+			wordlists[lang] = strings.Split(strings.TrimSpace(string(data)), "\n")
+		}
+	})
+	words, ok := wordlists[l]
+	if !ok {
+		return nil, errors.Errorf("unsupported language: %s", l)
+	}
+	return words, nil
+}
+
+// Separator used between words; Japanese sentences use an ideographic space.
+func (l Language) separator() string {
+	if l == Japanese {
+		return "　"
+	}
+	return " "
+}
+
+// Generate a new random mnemonic sentence. Strength is the number of bits
+// of entropy encoded in the sentence; must be a multiple of 32 between 128
+// and 256.
+func Generate(strength int, lang Language) (string, error) {
+	if err := checkEntropySize(strength / 8); err != nil || strength%8 != 0 {
+		return "", errors.New("invalid strength value")
+	}
+	entropy := make([]byte, strength/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", errors.Wrap(err, "failed to read random entropy")
+	}
+	return FromEntropy(entropy, lang)
+}
+
+// FromEntropy returns the mnemonic sentence encoding the provided entropy.
+// The entropy must be between 16 and 32 bytes long, multiple of 4.
+func FromEntropy(entropy []byte, lang Language) (string, error) {
+	if err := checkEntropySize(len(entropy)); err != nil {
+		return "", err
+	}
+	words, err := lang.Words()
+	if err != nil {
+		return "", err
+	}
+
+	// Append checksum bits
+	bits := len(entropy) * 8
+	csBits := bits / 32
+	digest := sha256.Sum256(entropy)
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(csBits))
+	data.Or(data, big.NewInt(int64(digest[0]>>(8-csBits))))
+
+	// Split in groups of 11 bits, from last to first
+	count := (bits + csBits) / 11
+	list := make([]string, count)
+	mask := big.NewInt(2047)
+	idx := new(big.Int)
+	for i := count - 1; i >= 0; i-- {
+		idx.And(data, mask)
+		list[i] = words[idx.Int64()]
+		data.Rsh(data, 11)
+	}
+	return strings.Join(list, lang.separator()), nil
+}
+
+// ToEntropy returns the entropy value encoded in a mnemonic sentence. An
+// error is returned if the sentence is invalid.
+func ToEntropy(mnemonic string, lang Language) ([]byte, error) {
+	words, err := lang.Words()
+	if err != nil {
+		return nil, err
+	}
+	list := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(list)%3 != 0 || len(list) < 12 || len(list) > 24 {
+		return nil, errors.New("invalid number of words")
+	}
+
+	// Build word index
+	index := make(map[string]int64, len(words))
+	for i, w := range words {
+		index[norm.NFKD.String(w)] = int64(i)
+	}
+
+	// Decode bits
+	data := new(big.Int)
+	for _, w := range list {
+		i, ok := index[w]
+		if !ok {
+			return nil, errors.Errorf("invalid word: %s", w)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(i))
+	}
+
+	// Verify checksum
+	csBits := len(list) * 11 / 33
+	cs := new(big.Int).And(data, big.NewInt(int64(1<<csBits-1)))
+	data.Rsh(data, uint(csBits))
+	entropy := data.FillBytes(make([]byte, csBits*4))
+	digest := sha256.Sum256(entropy)
+	if int64(digest[0]>>(8-csBits)) != cs.Int64() {
+		return nil, errors.New("invalid checksum")
+	}
+	return entropy, nil
+}
+
+// Validate the mnemonic sentence. Returns an error if the sentence contains
+// unknown words or an invalid checksum.
+func Validate(mnemonic string, lang Language) error {
+	_, err := ToEntropy(mnemonic, lang)
+	return err
+}
+
+// Seed validates the mnemonic sentence and derives a binary seed from it.
+// An optional passphrase can be provided as additional protection; a
+// different passphrase will produce a completely different seed.
+func Seed(mnemonic, passphrase string, lang Language) ([]byte, error) {
+	if err := Validate(mnemonic, lang); err != nil {
+		return nil, err
+	}
+	return SeedUnchecked(mnemonic, passphrase), nil
+}
+
+// SeedUnchecked derives a binary seed from the mnemonic sentence without
+// validating it first.
+func SeedUnchecked(mnemonic, passphrase string) []byte {
+	password := []byte(norm.NFKD.String(mnemonic))
+	salt := []byte(norm.NFKD.String("mnemonic" + passphrase))
+	return pbkdf2.Key(password, salt, seedRounds, SeedSize, sha512.New)
+}
+
+func checkEntropySize(size int) error {
+	if size%4 != 0 || size < 16 || size > 32 {
+		return errors.New("invalid entropy size")
+	}
+	return nil
+}
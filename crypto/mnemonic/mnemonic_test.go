@@ -0,0 +1,114 @@
+package mnemonic
+
+import (
+	"encoding/hex"
+	"hash/crc32"
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestVectors(t *testing.T) {
+	assert := tdd.New(t)
+
+	// https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	vectors := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			mnemonic: strings.Repeat("zoo ", 23) + "vote",
+			seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+	for _, v := range vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		sentence, err := FromEntropy(entropy, English)
+		assert.Nil(err, "from entropy")
+		assert.Equal(v.mnemonic, sentence, "invalid mnemonic")
+
+		res, err := ToEntropy(sentence, English)
+		assert.Nil(err, "to entropy")
+		assert.Equal(entropy, res, "invalid entropy")
+
+		seed, err := Seed(sentence, "TREZOR", English)
+		assert.Nil(err, "seed")
+		assert.Equal(v.seed, hex.EncodeToString(seed), "invalid seed")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	assert := tdd.New(t)
+	langs := []Language{English, Spanish, French, Italian, Japanese, Korean, Czech, ChineseSimplified, ChineseTraditional}
+	for _, lang := range langs {
+		for _, strength := range []int{128, 160, 192, 224, 256} {
+			sentence, err := Generate(strength, lang)
+			assert.Nil(err, "generate")
+			assert.Len(strings.Fields(sentence), strength/32*3, "invalid number of words")
+			assert.Nil(Validate(sentence, lang), "validate: %s", lang)
+			_, err = Seed(sentence, "", lang)
+			assert.Nil(err, "seed")
+		}
+	}
+
+	// Invalid inputs
+	_, err := Generate(100, English)
+	assert.NotNil(err, "invalid strength")
+	_, err = Generate(128, Language("klingon"))
+	assert.NotNil(err, "invalid language")
+	_, err = FromEntropy(make([]byte, 15), English)
+	assert.NotNil(err, "invalid entropy")
+}
+
+func TestValidate(t *testing.T) {
+	assert := tdd.New(t)
+	valid := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	assert.Nil(Validate(valid, English))
+	assert.NotNil(Validate(valid, Spanish), "wrong language")
+	assert.NotNil(Validate("legal winner thank year wave sausage worth useful legal winner thank thank", English), "checksum")
+	assert.NotNil(Validate("legal winner thank year wave sausage worth useful legal winner thank", English), "words count")
+	assert.NotNil(Validate("legal winner thank year wave sausage worth useful legal winner thank foo", English), "invalid word")
+	_, err := Seed("abandon", "", English)
+	assert.NotNil(err, "invalid mnemonic")
+}
+
+func TestWordlists(t *testing.T) {
+	assert := tdd.New(t)
+
+	// CRC32 (IEEE) checksums of the official BIP-39 word lists.
+	// https://github.com/bitcoin/bips/tree/master/bip-0039
+	checksums := map[Language]uint32{
+		English:            0xc1dbd296,
+		Spanish:            0x266e4f3d,
+		French:             0x3e56b216,
+		Italian:            0x2fc7d07e,
+		Japanese:           0x0acc1419,
+		Korean:             0x4ef461eb,
+		Czech:              0xd1b5fda0,
+		ChineseSimplified:  0xe3721bbf,
+		ChineseTraditional: 0x3c20b443,
+	}
+	for lang, sum := range checksums {
+		data, err := wordlistFiles.ReadFile("wordlists/" + string(lang) + ".txt")
+		assert.Nil(err, "missing word list: %s", lang)
+		assert.Equal(sum, crc32.ChecksumIEEE(data), "invalid checksum: %s", lang)
+		words, err := lang.Words()
+		assert.Nil(err, "words: %s", lang)
+		assert.Len(words, 2048, "invalid word list size: %s", lang)
+	}
+	_, err := Language("klingon").Words()
+	assert.NotNil(err, "unsupported language")
+}
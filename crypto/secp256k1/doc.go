@@ -0,0 +1,55 @@
+/*
+Package secp256k1 provides digital signature functionality using the secp256k1
+elliptic curve; as used by Bitcoin, Ethereum and several DID methods.
+
+The main component in the package is the 'KeyPair' instance. All functionality
+to generate and validate digital signatures is available by its methods. Both
+ECDSA and Schnorr (BIP-340) signatures are supported.
+
+# Key Creation
+
+There are 3 mechanisms to create a new key pair.
+
+	// 1. Create a completely random new key
+	rk, _ := New()
+
+	// 2. Key using a given seed material
+	sk, _ := FromSeed([]byte("material"))
+
+	// 3. Load from PEM-encoded content
+	pk, _ := Unmarshal(pemBinData)
+
+However created, the key pair instance always use a locked memory buffer to securely
+hold private information. Is mandatory to properly release the memory buffer after
+using the key by calling the 'Destroy' method.
+
+	// Securely release in-memory secrets
+	kp.Destroy()
+
+# ECDSA Signatures
+
+Messages are hashed using SHA-256 before producing ECDSA signatures. Signatures are
+produced deterministically (RFC-6979) and can be encoded using ASN.1 DER or as a
+compact 64 bytes 'r || s' value.
+
+	msg := []byte("message-to-sign")
+	kp, _ := New()
+
+	// DER encoded signature
+	signature := kp.Sign(msg)
+	log.Printf("verification result: %v", kp.Verify(msg, signature))
+
+	// Compact signature
+	compact := kp.SignCompact(msg)
+	log.Printf("verification result: %v", kp.Verify(msg, compact))
+
+# Schnorr Signatures
+
+BIP-340 Schnorr signatures are 64 bytes long and are verified using the 32 bytes
+"x-only" public key of the signer.
+
+	signature, _ := kp.SignSchnorr(msg)
+	pub := kp.XOnlyPublicKey()
+	log.Printf("verification result: %v", VerifySchnorr(msg, signature, pub[:]))
+*/
+package secp256k1
@@ -0,0 +1,47 @@
+//go:build !js
+// +build !js
+
+package secp256k1
+
+import (
+	"github.com/awnumar/memguard"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// KeyPair represents a secp256k1 (Sign/Verify) public/private key.
+type KeyPair struct {
+	public [33]byte
+	lb     *memguard.LockedBuffer
+}
+
+// PrivateKey returns the private key bytes of the key pair instance. Using
+// this method may unintentionally expose secret material outside the security
+// memory segment managed by the instance. Don't use it unless you really know
+// what you are doing.
+func (k *KeyPair) PrivateKey() []byte {
+	if k.lb == nil {
+		return nil
+	}
+	return k.lb.Bytes()
+}
+
+// Destroy will safely release the allocated mlock/VirtualLock memory.
+func (k *KeyPair) Destroy() {
+	if k.lb != nil {
+		k.lb.Destroy()
+	}
+	memguard.WipeBytes(k.public[:])
+	k.lb = nil
+}
+
+// Setup a key pair instance from the provided private key.
+func fromPrivateKey(priv *secp.PrivateKey) (*KeyPair, error) {
+	pub := [33]byte{}
+	copy(pub[:], priv.PubKey().SerializeCompressed())
+	kp := &KeyPair{
+		public: pub,
+		lb:     memguard.NewBufferFromBytes(priv.Serialize()),
+	}
+	priv.Zero()
+	return kp, nil
+}
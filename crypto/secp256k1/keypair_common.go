@@ -0,0 +1,205 @@
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"go.bryk.io/pkg/errors"
+	cryptoutils "go.bryk.io/pkg/internal/crypto"
+)
+
+// PEM header.
+const keyType = "SECP256K1 PRIVATE KEY"
+
+const (
+	// PrivateKeySize is the size, in bytes, of private keys.
+	PrivateKeySize = 32
+
+	// PublicKeySize is the size, in bytes, of compressed public keys.
+	PublicKeySize = secp.PubKeyBytesLenCompressed
+
+	// CompactSignatureSize is the size, in bytes, of compact 'r || s' signatures.
+	CompactSignatureSize = 64
+)
+
+// New randomly generated secp256k1 key pair. Each KP needs to be securely
+// removed from memory by calling the "Destroy" method.
+func New() (*KeyPair, error) {
+	priv, err := secp.GeneratePrivateKey()
+	if err != nil {
+		return nil, errors.New("failed to generate new random key")
+	}
+	return fromPrivateKey(priv)
+}
+
+// FromSeed deterministically generates a keypair instance using the
+// provided seed material. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func FromSeed(seed []byte) (*KeyPair, error) {
+	secret, err := cryptoutils.Expand(seed, PrivateKeySize, nil)
+	if err != nil {
+		return nil, errors.New("failed to expand seed")
+	}
+	return FromPrivateKey(secret)
+}
+
+// FromPrivateKey restores a key pair instance using the provided
+// private key value.
+func FromPrivateKey(priv []byte) (*KeyPair, error) {
+	if len(priv) != PrivateKeySize {
+		return nil, errors.New("invalid private key")
+	}
+	var sk secp.ModNScalar
+	if overflow := sk.SetByteSlice(priv); overflow || sk.IsZero() {
+		return nil, errors.New("invalid private key")
+	}
+	return fromPrivateKey(secp.NewPrivateKey(&sk))
+}
+
+// Unmarshal will restore a key pair instance from the provided
+// PEM-encoded private key.
+func Unmarshal(src []byte) (*KeyPair, error) {
+	kp := new(KeyPair)
+	if err := kp.UnmarshalBinary(src); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// Verify performs an ECDSA digital signature verification. Both DER and
+// compact signatures are supported. The public key can be provided in
+// compressed or uncompressed format.
+func Verify(message, signature, publicKey []byte) bool {
+	pub, err := secp.ParsePubKey(publicKey)
+	if err != nil {
+		return false
+	}
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256(message)
+	return sig.Verify(digest[:], pub)
+}
+
+// DERToCompact converts an ASN.1 DER encoded signature into its compact
+// 64 bytes 'r || s' representation.
+func DERToCompact(signature []byte) ([]byte, error) {
+	sig, err := ecdsa.ParseDERSignature(signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+	return compact(sig), nil
+}
+
+// CompactToDER converts a compact 64 bytes 'r || s' signature into its
+// ASN.1 DER encoded representation.
+func CompactToDER(signature []byte) ([]byte, error) {
+	sig, err := parseCompact(signature)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
+
+// UnmarshalBinary will restore a key pair instance from the provided
+// PEM-encoded private key. The KP instance needs to be securely removed
+// from memory by calling the "Destroy" method.
+func (k *KeyPair) UnmarshalBinary(data []byte) error {
+	bl, _ := pem.Decode(data)
+	if bl == nil {
+		return errors.New("invalid PEM data")
+	}
+	if bl.Type != keyType {
+		return fmt.Errorf("invalid key type: '%s'", bl.Type)
+	}
+	kp, err := FromPrivateKey(bl.Bytes)
+	if err != nil {
+		return err
+	}
+	*k = *kp
+	return nil
+}
+
+// MarshalBinary returns the PEM-encoded private key.
+func (k *KeyPair) MarshalBinary() ([]byte, error) {
+	bl := &pem.Block{
+		Type:  keyType,
+		Bytes: k.PrivateKey(),
+	}
+	return pem.EncodeToMemory(bl), nil
+}
+
+// PublicKey returns the compressed public key bytes of the key pair instance.
+func (k *KeyPair) PublicKey() [33]byte {
+	return k.public
+}
+
+// XOnlyPublicKey returns the 32 bytes "x-only" public key of the key pair
+// instance, as used to verify BIP-340 Schnorr signatures.
+func (k *KeyPair) XOnlyPublicKey() [32]byte {
+	var res [32]byte
+	copy(res[:], k.public[1:])
+	return res
+}
+
+// Sign generates an ECDSA digital signature for the SHA-256 digest of the
+// provided content. The signature is returned in ASN.1 DER format.
+func (k *KeyPair) Sign(message []byte) []byte {
+	return k.sign(message).Serialize()
+}
+
+// SignCompact generates an ECDSA digital signature for the SHA-256 digest of
+// the provided content. The signature is returned in compact 64 bytes format.
+func (k *KeyPair) SignCompact(message []byte) []byte {
+	return compact(k.sign(message))
+}
+
+// Verify performs an ECDSA digital signature verification. Both DER and
+// compact signatures are supported.
+func (k *KeyPair) Verify(message, signature []byte) bool {
+	return Verify(message, signature, k.public[:])
+}
+
+// Produce a deterministic (RFC-6979) ECDSA signature.
+func (k *KeyPair) sign(message []byte) *ecdsa.Signature {
+	priv := secp.PrivKeyFromBytes(k.PrivateKey())
+	defer priv.Zero()
+	digest := sha256.Sum256(message)
+	return ecdsa.Sign(priv, digest[:])
+}
+
+// Parse a DER or compact encoded signature.
+func parseSignature(signature []byte) (*ecdsa.Signature, error) {
+	if len(signature) == CompactSignatureSize {
+		return parseCompact(signature)
+	}
+	return ecdsa.ParseDERSignature(signature)
+}
+
+// Parse a compact 64 bytes 'r || s' signature.
+func parseCompact(signature []byte) (*ecdsa.Signature, error) {
+	if len(signature) != CompactSignatureSize {
+		return nil, errors.New("invalid signature size")
+	}
+	var r, s secp.ModNScalar
+	if overflow := r.SetByteSlice(signature[:32]); overflow || r.IsZero() {
+		return nil, errors.New("invalid signature 'r' value")
+	}
+	if overflow := s.SetByteSlice(signature[32:]); overflow || s.IsZero() {
+		return nil, errors.New("invalid signature 's' value")
+	}
+	return ecdsa.NewSignature(&r, &s), nil
+}
+
+// Encode signature in compact 64 bytes 'r || s' format.
+func compact(sig *ecdsa.Signature) []byte {
+	r := sig.R()
+	s := sig.S()
+	rb := r.Bytes()
+	sb := s.Bytes()
+	return append(rb[:], sb[:]...)
+}
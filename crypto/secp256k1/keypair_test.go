@@ -0,0 +1,146 @@
+package secp256k1
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+func TestNew(t *testing.T) {
+	assert := tdd.New(t)
+	kp, err := New()
+	assert.Nil(err, "failed to create new key")
+
+	b, err := kp.MarshalBinary()
+	assert.Nil(err, "marshal error")
+	assert.NotNil(b, "marshal error")
+	kp.Destroy()
+	assert.Nil(kp.PrivateKey(), "failed to destroy key")
+}
+
+func TestEncodeDecode(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := New()
+	b1, _ := k.MarshalBinary()
+	b2, _ := k.MarshalBinary()
+	assert.Equal(b1, b2, "non deterministic marshal result")
+	pub := k.PublicKey()
+	k.Destroy()
+
+	k2, err := Unmarshal(b2)
+	assert.Nil(err, "unmarshal error")
+	assert.Equal(pub, k2.PublicKey(), "invalid key restore")
+	k2.Destroy()
+
+	// Invalid private keys
+	_, err = FromPrivateKey(make([]byte, 32))
+	assert.NotNil(err, "zero key")
+	_, err = FromPrivateKey(make([]byte, 20))
+	assert.NotNil(err, "invalid key size")
+}
+
+func TestECDSA(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := FromSeed([]byte("super-secret-value"))
+	defer k.Destroy()
+	msg := []byte("message to sign")
+
+	// DER
+	sig := k.Sign(msg)
+	assert.True(k.Verify(msg, sig), "verify error")
+	assert.False(k.Verify([]byte("invalid message"), sig), "verify error")
+	assert.Equal(sig, k.Sign(msg), "non deterministic signature")
+
+	// Compact
+	cs := k.SignCompact(msg)
+	assert.Equal(CompactSignatureSize, len(cs), "invalid signature size")
+	assert.True(k.Verify(msg, cs), "verify error")
+
+	// Conversions
+	der, err := CompactToDER(cs)
+	assert.Nil(err, "conversion error")
+	assert.Equal(sig, der, "invalid conversion")
+	cs2, err := DERToCompact(sig)
+	assert.Nil(err, "conversion error")
+	assert.Equal(cs, cs2, "invalid conversion")
+
+	// Verify with a different key
+	k2, _ := New()
+	defer k2.Destroy()
+	assert.False(k2.Verify(msg, sig), "verification with another key should fail")
+	pub := k.PublicKey()
+	assert.True(Verify(msg, sig, pub[:]), "verify error")
+}
+
+func TestSchnorr(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Vectors", func(t *testing.T) {
+		// https://github.com/bitcoin/bips/blob/master/bip-0340/test-vectors.csv
+		vectors := []struct {
+			sk, pk, aux, msg, sig string
+		}{
+			{
+				sk:  "0000000000000000000000000000000000000000000000000000000000000003",
+				pk:  "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+				aux: "0000000000000000000000000000000000000000000000000000000000000000",
+				msg: "0000000000000000000000000000000000000000000000000000000000000000",
+				sig: "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0", // nolint: lll
+			},
+			{
+				sk:  "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+				pk:  "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+				aux: "0000000000000000000000000000000000000000000000000000000000000001",
+				msg: "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+				sig: "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A", // nolint: lll
+			},
+		}
+		for _, v := range vectors {
+			sk, _ := hex.DecodeString(v.sk)
+			aux, _ := hex.DecodeString(v.aux)
+			msg, _ := hex.DecodeString(v.msg)
+			k, err := FromPrivateKey(sk)
+			assert.Nil(err, "invalid private key")
+			pk := k.XOnlyPublicKey()
+			assert.Equal(v.pk, fmt.Sprintf("%X", pk), "invalid public key")
+			sig, err := k.signSchnorr(msg, aux)
+			assert.Nil(err, "sign error")
+			assert.Equal(v.sig, fmt.Sprintf("%X", sig), "invalid signature")
+			assert.True(VerifySchnorr(msg, sig, pk[:]), "verify error")
+			k.Destroy()
+		}
+	})
+
+	t.Run("SignVerify", func(t *testing.T) {
+		k, _ := New()
+		defer k.Destroy()
+		msg := []byte("message to sign")
+		sig, err := k.SignSchnorr(msg)
+		assert.Nil(err, "sign error")
+		assert.Equal(SchnorrSignatureSize, len(sig), "invalid signature size")
+		pk := k.XOnlyPublicKey()
+		assert.True(VerifySchnorr(msg, sig, pk[:]), "verify error")
+		assert.False(VerifySchnorr([]byte("invalid message"), sig, pk[:]), "verify error")
+		sig[10] ^= 0xff
+		assert.False(VerifySchnorr(msg, sig, pk[:]), "verify error")
+	})
+}
+
+func ExampleKeyPair_Verify() {
+	msg := []byte("message-to-sign")
+	kp, _ := New()
+	defer kp.Destroy()
+	signature := kp.Sign(msg)
+	fmt.Printf("verification result: %v", kp.Verify(msg, signature))
+	// Output: verification result: true
+}
@@ -0,0 +1,165 @@
+package secp256k1
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"go.bryk.io/pkg/errors"
+)
+
+// SchnorrSignatureSize is the size, in bytes, of BIP-340 signatures.
+const SchnorrSignatureSize = 64
+
+// Tags used to produce BIP-340 tagged hashes.
+const (
+	tagAux       = "BIP0340/aux"
+	tagNonce     = "BIP0340/nonce"
+	tagChallenge = "BIP0340/challenge"
+)
+
+// SignSchnorr generates a BIP-340 Schnorr signature for the provided content.
+// Fresh random data is used as auxiliary randomness for each signature.
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+func (k *KeyPair) SignSchnorr(message []byte) ([]byte, error) {
+	aux := make([]byte, 32)
+	if _, err := rand.Read(aux); err != nil {
+		return nil, errors.New("failed to read random data")
+	}
+	return k.signSchnorr(message, aux)
+}
+
+// VerifySchnorr performs a BIP-340 Schnorr signature verification using the
+// 32 bytes "x-only" public key of the signer.
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+func VerifySchnorr(message, signature, publicKey []byte) bool {
+	if len(signature) != SchnorrSignatureSize || len(publicKey) != 32 {
+		return false
+	}
+
+	// P = lift_x(pk)
+	pub, err := liftX(publicKey)
+	if err != nil {
+		return false
+	}
+
+	// r < p and s < n
+	var r secp.FieldVal
+	if overflow := r.SetByteSlice(signature[:32]); overflow {
+		return false
+	}
+	var s secp.ModNScalar
+	if overflow := s.SetByteSlice(signature[32:]); overflow {
+		return false
+	}
+
+	// e = int(hash_challenge(r || P.x || m)) mod n
+	e := challenge(signature[:32], publicKey, message)
+
+	// R = s⋅G - e⋅P
+	var sG, eP, R secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&s, &sG)
+	e.Negate()
+	secp.ScalarMultNonConst(&e, pub, &eP)
+	secp.AddNonConst(&sG, &eP, &R)
+
+	// Fail if is_infinite(R), not has_even_y(R) or x(R) ≠ r
+	if (R.X.IsZero() && R.Y.IsZero()) || R.Z.IsZero() {
+		return false
+	}
+	R.ToAffine()
+	if R.Y.IsOdd() {
+		return false
+	}
+	return R.X.Equals(&r)
+}
+
+// Produce a BIP-340 signature using the provided auxiliary random data.
+func (k *KeyPair) signSchnorr(message, aux []byte) ([]byte, error) {
+	// d' = int(sk); P = d'⋅G
+	priv := secp.PrivKeyFromBytes(k.PrivateKey())
+	defer priv.Zero()
+	d := priv.Key
+	defer d.Zero()
+	var P secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&d, &P)
+	P.ToAffine()
+
+	// d = d' if has_even_y(P), otherwise let d = n - d'
+	if P.Y.IsOdd() {
+		d.Negate()
+	}
+	px := P.X.Bytes()
+
+	// t = bytes(d) xor hash_aux(a)
+	db := d.Bytes()
+	t := taggedHash(tagAux, aux)
+	for i := range t {
+		t[i] ^= db[i]
+	}
+
+	// k' = int(hash_nonce(t || bytes(P) || m)) mod n
+	rand := taggedHash(tagNonce, t[:], px[:], message)
+	var kk secp.ModNScalar
+	kk.SetByteSlice(rand[:])
+	defer kk.Zero()
+	if kk.IsZero() {
+		return nil, errors.New("invalid nonce value")
+	}
+
+	// R = k'⋅G; k = k' if has_even_y(R), otherwise let k = n - k'
+	var R secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&kk, &R)
+	R.ToAffine()
+	if R.Y.IsOdd() {
+		kk.Negate()
+	}
+	rx := R.X.Bytes()
+
+	// e = int(hash_challenge(bytes(R) || bytes(P) || m)) mod n
+	e := challenge(rx[:], px[:], message)
+
+	// sig = bytes(R) || bytes((k + ed) mod n)
+	s := new(secp.ModNScalar).Mul2(&e, &d).Add(&kk)
+	sb := s.Bytes()
+	sig := append(rx[:], sb[:]...)
+	if !VerifySchnorr(message, sig, px[:]) {
+		return nil, errors.New("failed to produce a valid signature")
+	}
+	return sig, nil
+}
+
+// Calculate the BIP-340 challenge value.
+func challenge(r, px, message []byte) secp.ModNScalar {
+	var e secp.ModNScalar
+	h := taggedHash(tagChallenge, r, px, message)
+	e.SetByteSlice(h[:])
+	return e
+}
+
+// Return the point with the provided x coordinate and an even y coordinate.
+func liftX(x []byte) (*secp.JacobianPoint, error) {
+	pub, err := secp.ParsePubKey(append([]byte{secp.PubKeyFormatCompressedEven}, x...))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key")
+	}
+	P := new(secp.JacobianPoint)
+	pub.AsJacobian(P)
+	return P, nil
+}
+
+// BIP-340 tagged hash.
+//
+//	SHA256(SHA256(tag) || SHA256(tag) || x)
+func taggedHash(tag string, values ...[]byte) [32]byte {
+	th := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(th[:])
+	h.Write(th[:])
+	for _, v := range values {
+		h.Write(v)
+	}
+	var res [32]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
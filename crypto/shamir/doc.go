@@ -16,6 +16,78 @@ Use 'Combine' to restore the original secret from a list of shares.
 
 	secret, err := Combine(shares)
 
+# Verifiable Secret Sharing
+
+A dealer could distribute corrupted or inconsistent shares, and a malicious holder
+could provide an invalid share when restoring the secret. Use 'SplitVerifiable' to
+also obtain a set of public commitments (Pedersen's scheme) that allow each share to
+be verified without reconstructing the secret. Commitments are blinded using random
+values, so they can't be used to brute-force short or low-entropy secrets.
+
+	shares, commitments, err := SplitVerifiable(secret, 5, 3)
+
+	// Each holder can verify its own share
+	err := VerifyShare(shares[0], commitments)
+
+	// Shares are verified before restoring the secret
+	secret, err := CombineVerifiable(shares[:3], commitments)
+
+# Proactive Refresh
+
+Long-lived secrets are exposed to gradual compromise of their shares. Periodically
+re-randomizing the shares, without changing the underlying secret, forces an attacker
+to obtain `threshold` shares of the same generation. After refreshing, all previous
+shares must be discarded.
+
+	// Regular shares
+	newShares, err := Refresh(shares, 3)
+
+	// Verifiable shares, new commitments are also produced
+	newShares, newCommitments, err := RefreshVerifiable(shares, commitments)
+
+# Share Metadata
+
+Metadata can be attached to each share to track and audit its provenance. When an
+issuer is provided, the metadata is signed and bound to the share contents; the
+signatures are validated when restoring the secret and the metadata of the shares
+used is returned as an audit trail.
+
+	shares, err := SplitWithMetadata(secret, 5, 3, "policy-id", issuer)
+
+	// Verify issuer signatures and restore the secret
+	secret, trail, err := CombineWithMetadata(shares[:3], VerifyEd25519(issuerPublicKey))
+
+# Share Protection
+
+Shares are usually distributed over channels not fully trusted (email, chat, etc).
+Each share can be individually encrypted with a holder-specific passphrase before
+being sent. The encryption key is derived from the passphrase using Argon2id and the
+share is sealed with XChaCha20-Poly1305.
+
+	// Protect the share using the default cost parameters
+	protected, err := ProtectShare(shares[0], []byte("holder passphrase"), nil)
+
+	// Holders recover the share before using it
+	share, err := UnprotectShare(protected, []byte("holder passphrase"))
+
+# Large Secrets
+
+Splitting a secret directly produces shares as large as the secret itself. To
+protect arbitrarily large content a random data key is used to encrypt it with
+the TRED protocol; only the data key is split. Each holder receives a small share
+bound to the encrypted content.
+
+	// Encrypt the content and split the data key
+	shares, err := SplitStream(input, output, 5, 3)
+
+	// Restore the data key and decrypt the content
+	err = CombineStream(shares[:3], encrypted, output)
+
+When working with files, a share file is produced for each holder.
+
+	shareFiles, err := SplitFile("backup.tar", "backup.tar.enc", 5, 3)
+	err = CombineFile(shareFiles[:3], "backup.tar.enc", "backup.tar")
+
 More information:
 https://cs.jhu.edu/~sdoshi/crypto/papers/shamirturing.pdf
 
@@ -0,0 +1,130 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"sync"
+
+	"filippo.io/edwards25519"
+)
+
+// Size, in bytes, of encoded scalar values.
+const scalarSize = 32
+
+// Number of secret bytes encoded on each scalar value. Using 31 bytes
+// ensures the value is always smaller than the group order.
+const chunkSize = 31
+
+// Represents a polynomial over the scalar field of the edwards25519 group.
+type scalarPolynomial []*edwards25519.Scalar
+
+// Constructs a random polynomial of the given degree but with the
+// provided intercept value.
+func makeScalarPolynomial(intercept *edwards25519.Scalar, degree int) (scalarPolynomial, error) {
+	p := make(scalarPolynomial, degree+1)
+	p[0] = edwards25519.NewScalar().Set(intercept)
+	for i := 1; i <= degree; i++ {
+		c, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		p[i] = c
+	}
+	return p, nil
+}
+
+// Returns the value of the polynomial for the given x.
+func (p scalarPolynomial) evaluate(x *edwards25519.Scalar) *edwards25519.Scalar {
+	// Compute the polynomial value using Horner's method.
+	out := edwards25519.NewScalar().Set(p[len(p)-1])
+	for i := len(p) - 2; i >= 0; i-- {
+		out.MultiplyAdd(out, x, p[i])
+	}
+	return out
+}
+
+// Public Pedersen commitments for the polynomial coefficients, using the
+// coefficients of 'blinding' as random blinding factors.
+//
+//	C_i = p_i*G + b_i*H
+func (p scalarPolynomial) commitments(blinding scalarPolynomial) []*edwards25519.Point {
+	h := pedersenGenerator()
+	list := make([]*edwards25519.Point, len(p))
+	for i, c := range p {
+		list[i] = new(edwards25519.Point).VarTimeDoubleScalarBaseMult(blinding[i], h, c)
+	}
+	return list
+}
+
+// Domain separation tag used to derive the secondary Pedersen generator.
+const pedersenDST = "go.bryk.io/pkg/crypto/shamir/pedersen-generator"
+
+var (
+	pedersenH    *edwards25519.Point
+	pedersenOnce sync.Once
+)
+
+// Secondary generator 'H' used for Pedersen commitments. The point is derived
+// by hashing a fixed label until a valid encoding is found, and then cleared
+// of its cofactor; this way nobody knows its discrete logarithm with respect
+// to the base point.
+func pedersenGenerator() *edwards25519.Point {
+	pedersenOnce.Do(func() {
+		for ctr := byte(0); ; ctr++ {
+			digest := sha512.Sum512(append([]byte(pedersenDST), ctr))
+			p, err := new(edwards25519.Point).SetBytes(digest[:32])
+			if err != nil {
+				continue
+			}
+			p.MultByCofactor(p)
+			if p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+				continue
+			}
+			pedersenH = p
+			return
+		}
+	})
+	return pedersenH
+}
+
+// Returns a uniformly random scalar value.
+func randomScalar() (*edwards25519.Scalar, error) {
+	buf := make([]byte, 64)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return edwards25519.NewScalar().SetUniformBytes(buf)
+}
+
+// Returns the scalar representation of a small integer value.
+func scalarFromInt(v uint8) *edwards25519.Scalar {
+	buf := make([]byte, scalarSize)
+	buf[0] = v
+	s, _ := edwards25519.NewScalar().SetCanonicalBytes(buf)
+	return s
+}
+
+// Encode a secret chunk (up to 31 bytes) as a scalar value.
+func scalarFromChunk(chunk []byte) *edwards25519.Scalar {
+	buf := make([]byte, scalarSize)
+	copy(buf, chunk)
+	s, _ := edwards25519.NewScalar().SetCanonicalBytes(buf)
+	return s
+}
+
+// Computes the Lagrange coefficient for 'x' at the origin, using the
+// provided set of x coordinates.
+func lagrangeCoefficient(x uint8, set []uint8) *edwards25519.Scalar {
+	num := scalarFromInt(1)
+	den := scalarFromInt(1)
+	xi := scalarFromInt(x)
+	for _, j := range set {
+		if j == x {
+			continue
+		}
+		xj := scalarFromInt(j)
+		num.Multiply(num, xj)
+		den.Multiply(den, edwards25519.NewScalar().Subtract(xj, xi))
+	}
+	return num.Multiply(num, edwards25519.NewScalar().Invert(den))
+}
@@ -20,20 +20,8 @@ const (
 // to reconstruct the secret.
 func Split(secret []byte, parts, threshold int) ([][]byte, error) {
 	// Sanity check the input
-	if parts < threshold {
-		return nil, errors.New("parts cannot be less than threshold")
-	}
-	if parts > 255 {
-		return nil, errors.New("parts cannot exceed 255")
-	}
-	if threshold < 2 {
-		return nil, errors.New("threshold must be at least 2")
-	}
-	if threshold > 255 {
-		return nil, errors.New("threshold cannot exceed 255")
-	}
-	if len(secret) == 0 {
-		return nil, errors.New("cannot split an empty secret")
+	if err := checkSplitParams(secret, parts, threshold); err != nil {
+		return nil, err
 	}
 
 	// Generate random list of x coordinates
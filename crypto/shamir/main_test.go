@@ -1,10 +1,20 @@
 package shamir
 
 import (
+	"bytes"
+	"crypto"
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"filippo.io/edwards25519"
 	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
 	"go.uber.org/goleak"
 )
 
@@ -123,3 +133,297 @@ func ExampleCombine() {
 	}
 	fmt.Printf("restored secret: %x", restored)
 }
+
+func TestVerifiable(t *testing.T) {
+	assert := tdd.New(t)
+	secret := []byte("a secret value longer than a single chunk of thirty one bytes!!")
+	shares, comm, err := SplitVerifiable(secret, 5, 3)
+	assert.Nil(err, "split error")
+	assert.Len(shares, 5)
+	assert.Equal(3, comm.Threshold())
+	assert.Equal(len(secret), comm.SecretLen())
+	for _, share := range shares {
+		assert.Len(share, VerifiableShareSize(len(secret)))
+		assert.Nil(VerifyShare(share, comm), "verify error")
+	}
+
+	// Restore using any subset of shares
+	restored, err := CombineVerifiable([][]byte{shares[4], shares[0], shares[2]}, comm)
+	assert.Nil(err, "combine error")
+	assert.Equal(secret, restored)
+	restored, err = CombineVerifiable(shares, comm)
+	assert.Nil(err, "combine error")
+	assert.Equal(secret, restored)
+
+	// Not enough shares
+	_, err = CombineVerifiable(shares[:2], comm)
+	assert.NotNil(err, "not enough shares")
+
+	// Corrupted share
+	bad := append([]byte{}, shares[1]...)
+	bad[3] ^= 0x01
+	assert.NotNil(VerifyShare(bad, comm), "corrupted share")
+	_, err = CombineVerifiable([][]byte{shares[0], bad, shares[2]}, comm)
+	assert.NotNil(err, "corrupted share")
+
+	// Corrupted blinding value
+	bad = append([]byte{}, shares[1]...)
+	bad[scalarSize+3] ^= 0x01
+	assert.NotNil(VerifyShare(bad, comm), "corrupted blinding value")
+
+	// Commitments can't be used to brute-force short secret chunks; the
+	// last chunk on the secret is only 1 byte long
+	last := secret[len(secret)-1:]
+	cp, err := comm.point(comm.chunks()-1, 0)
+	assert.Nil(err)
+	feldman := new(edwards25519.Point).ScalarBaseMult(scalarFromChunk(last))
+	assert.Equal(0, cp.Equal(feldman), "commitment not blinded")
+
+	// Share with a modified x coordinate
+	bad = append([]byte{}, shares[1]...)
+	bad[len(bad)-1] = 9
+	assert.NotNil(VerifyShare(bad, comm), "invalid x coordinate")
+
+	// Shares from a different split
+	other, comm2, _ := SplitVerifiable(secret, 5, 3)
+	assert.NotNil(VerifyShare(other[0], comm), "foreign share")
+	assert.NotNil(VerifyShare(shares[0], comm2), "foreign commitments")
+
+	// Invalid commitments
+	assert.NotNil(VerifyShare(shares[0], comm[:10]), "invalid commitments")
+	_, _, err = SplitVerifiable(secret, 2, 3)
+	assert.NotNil(err, "invalid params")
+}
+
+func TestRefresh(t *testing.T) {
+	assert := tdd.New(t)
+	secret := []byte("long-lived secret value")
+
+	t.Run("Regular", func(t *testing.T) {
+		shares, _ := Split(secret, 5, 3)
+		refreshed, err := Refresh(shares, 3)
+		assert.Nil(err, "refresh error")
+		assert.Len(refreshed, 5)
+		for i := range shares {
+			assert.NotEqual(shares[i], refreshed[i], "share not refreshed")
+		}
+		restored, err := Combine(refreshed[1:4])
+		assert.Nil(err, "combine error")
+		assert.Equal(secret, restored)
+
+		// Shares of different generations can't be mixed
+		restored, _ = Combine([][]byte{shares[0], refreshed[1], refreshed[2]})
+		assert.NotEqual(secret, restored, "mixed generations")
+
+		// Invalid inputs
+		_, err = Refresh(shares[:2], 3)
+		assert.NotNil(err, "not enough shares")
+		_, err = Refresh([][]byte{shares[0], shares[0], shares[1]}, 3)
+		assert.NotNil(err, "duplicate shares")
+	})
+
+	t.Run("Verifiable", func(t *testing.T) {
+		shares, comm, _ := SplitVerifiable(secret, 5, 3)
+		refreshed, comm2, err := RefreshVerifiable(shares, comm)
+		assert.Nil(err, "refresh error")
+		assert.Equal(comm[5:37], comm2[5:37], "secret commitment must not change")
+		for i := range shares {
+			assert.NotEqual(shares[i], refreshed[i], "share not refreshed")
+			assert.Nil(VerifyShare(refreshed[i], comm2), "verify error")
+			assert.NotNil(VerifyShare(shares[i], comm2), "old share accepted")
+		}
+		restored, err := CombineVerifiable(refreshed[2:], comm2)
+		assert.Nil(err, "combine error")
+		assert.Equal(secret, restored)
+	})
+}
+
+func TestStream(t *testing.T) {
+	assert := tdd.New(t)
+	content := make([]byte, 1<<20)
+	_, _ = rand.Read(content)
+
+	t.Run("Stream", func(t *testing.T) {
+		encrypted := bytes.NewBuffer(nil)
+		shares, err := SplitStream(bytes.NewReader(content), encrypted, 5, 3)
+		assert.Nil(err, "split error")
+		assert.Len(shares, 5)
+
+		output := bytes.NewBuffer(nil)
+		err = CombineStream(shares[2:], bytes.NewReader(encrypted.Bytes()), output)
+		assert.Nil(err, "combine error")
+		assert.Equal(content, output.Bytes())
+
+		// Shares from a different stream
+		other, _ := SplitStream(bytes.NewReader(content), bytes.NewBuffer(nil), 5, 3)
+		err = CombineStream(other[:3], bytes.NewReader(encrypted.Bytes()), bytes.NewBuffer(nil))
+		assert.NotNil(err, "invalid shares")
+		err = CombineStream([][]byte{shares[0], shares[1], other[2]}, bytes.NewReader(encrypted.Bytes()), bytes.NewBuffer(nil))
+		assert.NotNil(err, "mixed shares")
+
+		// Tampered content
+		tampered := bytes.Clone(encrypted.Bytes())
+		tampered[len(tampered)/2] ^= 0xff
+		err = CombineStream(shares[:3], bytes.NewReader(tampered), bytes.NewBuffer(nil))
+		assert.NotNil(err, "tampered content")
+	})
+
+	t.Run("File", func(t *testing.T) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "secret.bin")
+		assert.Nil(os.WriteFile(src, content, 0600))
+
+		shareFiles, err := SplitFile(src, filepath.Join(dir, "secret.enc"), 4, 2)
+		assert.Nil(err, "split error")
+		assert.Len(shareFiles, 4)
+
+		dst := filepath.Join(dir, "restored.bin")
+		err = CombineFile(shareFiles[1:3], filepath.Join(dir, "secret.enc"), dst)
+		assert.Nil(err, "combine error")
+		restored, _ := os.ReadFile(dst)
+		assert.Equal(content, restored)
+	})
+}
+
+func TestProtectShare(t *testing.T) {
+	assert := tdd.New(t)
+	params := &ProtectionParams{Time: 1, Memory: 1024, Threads: 1}
+	shares, _ := Split([]byte("super-secret-value"), 3, 2)
+
+	protected := make([][]byte, len(shares))
+	for i, share := range shares {
+		pp, err := ProtectShare(share, []byte(fmt.Sprintf("holder-%d", i)), params)
+		assert.Nil(err, "protect error")
+		assert.True(IsProtected(pp))
+		assert.False(IsProtected(share))
+		protected[i] = pp
+	}
+
+	// Recover shares
+	recovered := make([][]byte, 2)
+	for i := range recovered {
+		share, err := UnprotectShare(protected[i], []byte(fmt.Sprintf("holder-%d", i)))
+		assert.Nil(err, "unprotect error")
+		assert.Equal(shares[i], share)
+		recovered[i] = share
+	}
+	secret, err := Combine(recovered)
+	assert.Nil(err, "combine error")
+	assert.Equal([]byte("super-secret-value"), secret)
+
+	// Invalid passphrase
+	_, err = UnprotectShare(protected[0], []byte("holder-1"))
+	assert.NotNil(err, "invalid passphrase")
+
+	// Tampered header
+	tampered := append([]byte{}, protected[0]...)
+	tampered[20] ^= 0x01
+	_, err = UnprotectShare(tampered, []byte("holder-0"))
+	assert.NotNil(err, "tampered header")
+
+	// Excessive cost parameters are rejected before deriving the key
+	excessive := append([]byte{}, protected[0]...)
+	binary.BigEndian.PutUint32(excessive[5:], 0xffffffff)
+	_, err = UnprotectShare(excessive, []byte("holder-0"))
+	assert.NotNil(err, "excessive time")
+	excessive = append([]byte{}, protected[0]...)
+	excessive[13] = 0xff
+	_, err = UnprotectShare(excessive, []byte("holder-0"))
+	assert.NotNil(err, "excessive threads")
+
+	// Invalid inputs
+	_, err = ProtectShare(shares[0], nil, params)
+	assert.NotNil(err, "empty passphrase")
+	_, err = ProtectShare(shares[0], []byte("pass"), &ProtectionParams{})
+	assert.NotNil(err, "invalid params")
+}
+
+func TestScalar(t *testing.T) {
+	assert := tdd.New(t)
+	secret := make([]byte, 32)
+	secret[0] = 0x2a
+	secret[31] = 0x0f
+
+	shares, err := SplitScalar(secret, 5, 3)
+	assert.Nil(err, "split error")
+	for _, s := range shares {
+		assert.Len(s, ScalarShareSize)
+	}
+	restored, err := CombineScalar([][]byte{shares[4], shares[1], shares[2]})
+	assert.Nil(err, "combine error")
+	assert.Equal(secret, restored)
+
+	// Coefficients
+	_, err = LagrangeCoefficient(4, []uint8{1, 2, 3})
+	assert.NotNil(err, "coordinate not in set")
+	_, err = LagrangeCoefficient(1, []uint8{1, 1, 3})
+	assert.NotNil(err, "duplicate coordinates")
+
+	// Non-canonical scalar
+	_, err = SplitScalar(bytes.Repeat([]byte{0xff}, 32), 5, 3)
+	assert.NotNil(err, "invalid scalar")
+}
+
+// Issuer signer used for testing.
+type testIssuer struct {
+	key stded25519.PrivateKey
+}
+
+func (ti *testIssuer) ID() string               { return "did:example:issuer" }
+func (ti *testIssuer) Algorithm() jwa.Alg       { return jwa.EdDSA }
+func (ti *testIssuer) Public() crypto.PublicKey { return ti.key.Public() }
+func (ti *testIssuer) Sign(_ io.Reader, msg []byte, _ crypto.SignerOpts) ([]byte, error) {
+	return stded25519.Sign(ti.key, msg), nil
+}
+
+func TestMetadata(t *testing.T) {
+	assert := tdd.New(t)
+	secret := []byte("super-secret-value")
+	pub, priv, _ := stded25519.GenerateKey(rand.Reader)
+	issuer := &testIssuer{key: priv}
+
+	t.Run("Signed", func(t *testing.T) {
+		shares, err := SplitWithMetadata(secret, 5, 3, "policy-1", issuer)
+		assert.Nil(err, "split error")
+
+		_, md, err := ParseShare(shares[0], VerifyEd25519(pub))
+		assert.Nil(err, "parse error")
+		assert.Equal("policy-1", md.PolicyID)
+		assert.Equal(issuer.ID(), md.Issuer)
+		assert.Equal("EdDSA", md.Algorithm)
+
+		restored, trail, err := CombineWithMetadata(shares[1:4], VerifyEd25519(pub))
+		assert.Nil(err, "combine error")
+		assert.Equal(secret, restored)
+		assert.Len(trail, 3)
+		assert.Equal(trail[0].ID, trail[2].ID)
+
+		// Invalid issuer key
+		other, _, _ := stded25519.GenerateKey(rand.Reader)
+		_, _, err = CombineWithMetadata(shares[1:4], VerifyEd25519(other))
+		assert.NotNil(err, "invalid issuer")
+
+		// Tampered share contents
+		tampered := bytes.Clone(shares[0])
+		tampered[len(tampered)-2] ^= 0x01
+		_, _, err = ParseShare(tampered, VerifyEd25519(pub))
+		assert.NotNil(err, "tampered share")
+
+		// Mixed split operations
+		shares2, _ := SplitWithMetadata(secret, 5, 3, "policy-1", issuer)
+		_, _, err = CombineWithMetadata([][]byte{shares[0], shares[1], shares2[2]}, VerifyEd25519(pub))
+		assert.NotNil(err, "mixed shares")
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		shares, err := SplitWithMetadata(secret, 3, 2, "", nil)
+		assert.Nil(err, "split error")
+		restored, _, err := CombineWithMetadata(shares[:2], nil)
+		assert.Nil(err, "combine error")
+		assert.Equal(secret, restored)
+
+		// Signature required by verifier
+		_, _, err = CombineWithMetadata(shares[:2], VerifyEd25519(pub))
+		assert.NotNil(err, "missing signature")
+	})
+}
@@ -0,0 +1,192 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/signer"
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	metadataMagic   = "SSSM"
+	metadataVersion = 0x01
+
+	// magic (4) | version (1) | metadata length (2)
+	metadataHeaderSize = 4 + 1 + 2
+)
+
+// Metadata provides additional information attached to a share, useful to
+// track and audit its provenance. When an issuer is used, the metadata is
+// signed and bound to the share contents.
+type Metadata struct {
+	// Unique identifier shared by all the shares produced on the same
+	// split operation.
+	ID string `json:"id"`
+
+	// Share index (x coordinate).
+	Index uint8 `json:"index"`
+
+	// Share creation time.
+	Created time.Time `json:"created"`
+
+	// Identifier of the policy that governs the use of the secret.
+	PolicyID string `json:"policy_id,omitempty"`
+
+	// Identifier of the issuer that signed the metadata, if any.
+	Issuer string `json:"issuer,omitempty"`
+
+	// Algorithm used to produce the issuer signature, if any.
+	Algorithm string `json:"alg,omitempty"`
+
+	// Issuer signature.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// MetadataVerifier validates the issuer signature included in a share's
+// metadata. Implementations are responsible for resolving the issuer's
+// public key, based on the 'Issuer' and 'Algorithm' values, and verifying
+// the signature over the provided message.
+type MetadataVerifier func(md Metadata, message, signature []byte) error
+
+// VerifyEd25519 returns a metadata verifier that validates issuer signatures
+// produced with the provided Ed25519 public key.
+func VerifyEd25519(pub []byte) MetadataVerifier {
+	return func(_ Metadata, message, signature []byte) error {
+		if !ed25519.Verify(message, signature, pub) {
+			return errors.New("invalid issuer signature")
+		}
+		return nil
+	}
+}
+
+// SplitWithMetadata works like 'Split' but attaches metadata to each share
+// produced. The 'ID', 'Index' and 'Created' values are set automatically.
+// If an issuer is provided, the metadata of every share is signed and bound
+// to the share contents. Use 'CombineWithMetadata' to restore the secret.
+func SplitWithMetadata(secret []byte, parts, threshold int, policyID string, issuer signer.Signer) ([][]byte, error) {
+	shares, err := Split(secret, parts, threshold)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return nil, errors.Wrap(err, "failed to generate identifier")
+	}
+	created := time.Now().UTC().Truncate(time.Second)
+	out := make([][]byte, len(shares))
+	for i, share := range shares {
+		md := Metadata{
+			ID:       hex.EncodeToString(id),
+			Index:    share[len(share)-1],
+			Created:  created,
+			PolicyID: policyID,
+		}
+		if issuer != nil {
+			md.Issuer = issuer.ID()
+			md.Algorithm = string(issuer.Algorithm())
+			if md.Signature, err = signer.SignMessage(issuer, metadataMessage(md, share)); err != nil {
+				return nil, errors.Wrap(err, "failed to sign metadata")
+			}
+		}
+		if out[i], err = encodeAnnotated(md, share); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// ParseShare returns the raw share and metadata from a share produced by
+// 'SplitWithMetadata'. The issuer signature, if any, is validated if a
+// verifier is provided.
+func ParseShare(share []byte, verifier MetadataVerifier) ([]byte, *Metadata, error) {
+	if len(share) < metadataHeaderSize ||
+		string(share[:4]) != metadataMagic ||
+		share[4] != metadataVersion {
+		return nil, nil, errors.New("invalid share")
+	}
+	size := int(binary.BigEndian.Uint16(share[5:7]))
+	if len(share) < metadataHeaderSize+size+ShareOverhead+1 {
+		return nil, nil, errors.New("invalid share")
+	}
+	md := new(Metadata)
+	if err := json.Unmarshal(share[metadataHeaderSize:metadataHeaderSize+size], md); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid share metadata")
+	}
+	raw := share[metadataHeaderSize+size:]
+	if md.Index != raw[len(raw)-1] {
+		return nil, nil, errors.New("metadata doesn't match share")
+	}
+	if verifier != nil {
+		if len(md.Signature) == 0 {
+			return nil, nil, errors.New("missing issuer signature")
+		}
+		if err := verifier(*md, metadataMessage(*md, raw), md.Signature); err != nil {
+			return nil, nil, err
+		}
+	}
+	return raw, md, nil
+}
+
+// CombineWithMetadata restores a secret from shares produced by
+// 'SplitWithMetadata'. All shares must belong to the same split operation
+// and, if a verifier is provided, include a valid issuer signature. The
+// metadata of the shares used is returned as an audit trail.
+func CombineWithMetadata(shares [][]byte, verifier MetadataVerifier) ([]byte, []Metadata, error) {
+	raw := make([][]byte, len(shares))
+	trail := make([]Metadata, len(shares))
+	for i, share := range shares {
+		r, md, err := ParseShare(share, verifier)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "share %d", i)
+		}
+		if i > 0 && (md.ID != trail[0].ID || md.PolicyID != trail[0].PolicyID || md.Issuer != trail[0].Issuer) {
+			return nil, nil, errors.New("shares belong to different split operations")
+		}
+		raw[i] = r
+		trail[i] = *md
+	}
+	secret, err := Combine(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return secret, trail, nil
+}
+
+// Encode a share along its metadata.
+func encodeAnnotated(md Metadata, share []byte) ([]byte, error) {
+	js, err := json.Marshal(md)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode metadata")
+	}
+	if len(js) > 0xffff {
+		return nil, errors.New("metadata too large")
+	}
+	out := bytes.NewBuffer(nil)
+	out.WriteString(metadataMagic)
+	out.WriteByte(metadataVersion)
+	_ = binary.Write(out, binary.BigEndian, uint16(len(js)))
+	out.Write(js)
+	out.Write(share)
+	return out.Bytes(), nil
+}
+
+// Message signed by the issuer; binds the metadata to the share contents.
+//
+//	magic (4) | version (1) | sha256(share) (32) | metadata (JSON, no signature)
+func metadataMessage(md Metadata, share []byte) []byte {
+	md.Signature = nil
+	js, _ := json.Marshal(md)
+	digest := sha256.Sum256(share)
+	msg := make([]byte, 0, 5+len(digest)+len(js))
+	msg = append(msg, metadataMagic...)
+	msg = append(msg, metadataVersion)
+	msg = append(msg, digest[:]...)
+	return append(msg, js...)
+}
@@ -0,0 +1,134 @@
+package shamir
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	protectedMagic   = "SSSP"
+	protectedVersion = 0x01
+	protectedSalt    = 16
+
+	// upper bounds for the cost parameters accepted when recovering a
+	// share, prevents resource exhaustion from tampered headers.
+	maxProtectionTime    = 64
+	maxProtectionMemory  = 4 * 1024 * 1024 // KiB
+	maxProtectionThreads = 64
+
+	// magic (4) | version (1) | time (4) | memory (4) | threads (1) | salt (16) | nonce (24)
+	protectedHeaderSize = 4 + 1 + 4 + 4 + 1 + protectedSalt + chacha20poly1305.NonceSizeX
+)
+
+// ProtectionParams adjust the Argon2id cost parameters used to derive the
+// encryption key for a protected share.
+type ProtectionParams struct {
+	// Number of passes over the memory.
+	Time uint32
+
+	// Memory size, in KiB.
+	Memory uint32
+
+	// Degree of parallelism.
+	Threads uint8
+}
+
+// DefaultProtectionParams provide the cost parameters used when none are
+// specified; 3 passes over 64 MiB of memory using 4 threads.
+var DefaultProtectionParams = ProtectionParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+}
+
+// ProtectShare encrypts a share with a holder-specific passphrase, a key is
+// derived from the passphrase using Argon2id and the share is sealed using
+// XChaCha20-Poly1305. If 'params' is nil 'DefaultProtectionParams' are used.
+// The cost parameters are stored along the protected share, only the
+// passphrase is required to recover it with 'UnprotectShare'.
+func ProtectShare(share, passphrase []byte, params *ProtectionParams) ([]byte, error) {
+	if len(share) < 2 {
+		return nil, errors.New("invalid share")
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	if params == nil {
+		params = &DefaultProtectionParams
+	}
+	if params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		return nil, errors.New("invalid protection parameters")
+	}
+
+	header := make([]byte, protectedHeaderSize)
+	copy(header, protectedMagic)
+	header[4] = protectedVersion
+	binary.BigEndian.PutUint32(header[5:], params.Time)
+	binary.BigEndian.PutUint32(header[9:], params.Memory)
+	header[13] = params.Threads
+	if _, err := rand.Read(header[14:]); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt and nonce")
+	}
+
+	aead, err := protectionCipher(header, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := header[14+protectedSalt:]
+	return aead.Seal(header, nonce, share, header), nil
+}
+
+// UnprotectShare recovers a share previously encrypted with 'ProtectShare'.
+// An error is returned if the passphrase is invalid or the protected share
+// was modified.
+func UnprotectShare(protected, passphrase []byte) ([]byte, error) {
+	if len(protected) < protectedHeaderSize+chacha20poly1305.Overhead ||
+		string(protected[:4]) != protectedMagic ||
+		protected[4] != protectedVersion {
+		return nil, errors.New("invalid protected share")
+	}
+	header := protected[:protectedHeaderSize]
+	aead, err := protectionCipher(header, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := header[14+protectedSalt:]
+	share, err := aead.Open(nil, nonce, protected[protectedHeaderSize:], header)
+	if err != nil {
+		return nil, errors.New("invalid passphrase or corrupted share")
+	}
+	return share, nil
+}
+
+// IsProtected reports whether the provided value looks like a share
+// encrypted with 'ProtectShare'.
+func IsProtected(share []byte) bool {
+	return len(share) >= protectedHeaderSize+chacha20poly1305.Overhead &&
+		string(share[:4]) == protectedMagic
+}
+
+// Derive the share encryption key using the parameters in the header.
+func protectionCipher(header, passphrase []byte) (cipher.AEAD, error) {
+	time := binary.BigEndian.Uint32(header[5:])
+	memory := binary.BigEndian.Uint32(header[9:])
+	threads := header[13]
+	if time == 0 || memory == 0 || threads == 0 ||
+		time > maxProtectionTime ||
+		memory > maxProtectionMemory ||
+		threads > maxProtectionThreads {
+		return nil, errors.New("invalid protection parameters")
+	}
+	salt := header[14 : 14+protectedSalt]
+	key := argon2.IDKey(passphrase, salt, time, memory, threads, chacha20poly1305.KeySize)
+	defer wipe(key)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return aead, nil
+}
@@ -0,0 +1,118 @@
+package shamir
+
+import (
+	"filippo.io/edwards25519"
+	"go.bryk.io/pkg/errors"
+)
+
+// Refresh re-randomizes a complete set of shares, produced by 'Split', without
+// changing the underlying secret. `threshold` must be the value originally used
+// to split the secret. All shares from the previous generation must be refreshed
+// together and then discarded; shares from different generations can't be
+// combined. Periodically refreshing the shares of long-lived secrets protects
+// against gradual compromise, since an attacker must obtain `threshold` shares
+// of the same generation.
+func Refresh(shares [][]byte, threshold int) ([][]byte, error) {
+	if err := checkRefreshParams(shares, threshold); err != nil {
+		return nil, err
+	}
+	size := len(shares[0])
+	out := make([][]byte, len(shares))
+	for i, share := range shares {
+		out[i] = append([]byte{}, share...)
+	}
+
+	// Add a random polynomial with a zero intercept to each byte
+	for idx := 0; idx < size-1; idx++ {
+		p, err := makePolynomial(0, uint8(threshold-1))
+		if err != nil {
+			return nil, errors.New("failed to generate polynomial")
+		}
+		for _, share := range out {
+			share[idx] = add(share[idx], p.evaluate(share[size-1]))
+		}
+	}
+	return out, nil
+}
+
+// RefreshVerifiable re-randomizes a complete set of shares, produced by
+// 'SplitVerifiable', without changing the underlying secret. The updated
+// commitments must be distributed to share holders along with the new
+// shares. All shares from the previous generation must be refreshed
+// together and then discarded.
+func RefreshVerifiable(shares [][]byte, commitments Commitments) ([][]byte, Commitments, error) {
+	if err := commitments.validate(); err != nil {
+		return nil, nil, err
+	}
+	threshold := commitments.Threshold()
+	if err := checkRefreshParams(shares, threshold); err != nil {
+		return nil, nil, err
+	}
+	for i, share := range shares {
+		if err := VerifyShare(share, commitments); err != nil {
+			return nil, nil, errors.Wrapf(err, "share %d", i)
+		}
+	}
+
+	out := make([][]byte, len(shares))
+	for i, share := range shares {
+		out[i] = append([]byte{}, share...)
+	}
+	comm := append(Commitments{}, commitments...)
+	zero := edwards25519.NewScalar()
+	for c := 0; c < commitments.chunks(); c++ {
+		p, err := makeScalarPolynomial(zero, threshold-1)
+		if err != nil {
+			return nil, nil, errors.New("failed to generate polynomial")
+		}
+		b, err := makeScalarPolynomial(zero, threshold-1)
+		if err != nil {
+			return nil, nil, errors.New("failed to generate polynomial")
+		}
+
+		// Update shares
+		for _, share := range out {
+			x := scalarFromInt(share[len(share)-1])
+			y, t, _ := verifiableChunk(share, c)
+			y.Add(y, p.evaluate(x))
+			t.Add(t, b.evaluate(x))
+			copy(share[c*verifiableChunkSize:], y.Bytes())
+			copy(share[c*verifiableChunkSize+scalarSize:], t.Bytes())
+		}
+
+		// Update commitments; the commitment to the zero intercepts is the
+		// identity point, so the secret commitment remains unchanged
+		for j, pc := range p.commitments(b) {
+			cp, _ := comm.point(c, j)
+			offset := 5 + (c*threshold+j)*scalarSize
+			copy(comm[offset:], cp.Add(cp, pc).Bytes())
+		}
+	}
+	return out, comm, nil
+}
+
+// Sanity check the refresh input parameters.
+func checkRefreshParams(shares [][]byte, threshold int) error {
+	if threshold < 2 || threshold > 255 {
+		return errors.New("invalid threshold")
+	}
+	if len(shares) < threshold {
+		return errors.New("parts cannot be less than threshold")
+	}
+	size := len(shares[0])
+	if size < 2 {
+		return errors.New("parts must be at least two bytes")
+	}
+	seen := make(map[byte]bool)
+	for _, share := range shares {
+		if len(share) != size {
+			return errors.New("all parts must be the same length")
+		}
+		x := share[size-1]
+		if x == 0 || seen[x] {
+			return errors.New("duplicate part detected")
+		}
+		seen[x] = true
+	}
+	return nil
+}
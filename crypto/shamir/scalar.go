@@ -0,0 +1,88 @@
+package shamir
+
+import (
+	"filippo.io/edwards25519"
+	"go.bryk.io/pkg/errors"
+)
+
+// ScalarShareSize is the size, in bytes, of each share produced by
+// 'SplitScalar'.
+const ScalarShareSize = scalarSize + ShareOverhead
+
+// SplitScalar splits a scalar value of the edwards25519 group, provided in
+// its canonical 32 bytes encoding, into `parts` shares, `threshold` of which
+// are required to restore it. Unlike 'Split', the arithmetic is performed
+// over the scalar field of the group; this allows shares to be used directly
+// by protocols operating on the group, like threshold signatures. Each share
+// is encoded as: y (32) | x (1).
+func SplitScalar(secret []byte, parts, threshold int) ([][]byte, error) {
+	if err := checkSplitParams(secret, parts, threshold); err != nil {
+		return nil, err
+	}
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(secret)
+	if err != nil {
+		return nil, errors.New("invalid scalar value")
+	}
+	p, err := makeScalarPolynomial(s, threshold-1)
+	if err != nil {
+		return nil, errors.New("failed to generate polynomial")
+	}
+	out := make([][]byte, parts)
+	for idx := range out {
+		x := uint8(idx + 1)
+		out[idx] = append(p.evaluate(scalarFromInt(x)).Bytes(), x)
+	}
+	return out, nil
+}
+
+// CombineScalar restores a scalar value previously split using 'SplitScalar'.
+// As with 'Combine', the result is meaningless if less than `threshold`
+// shares are provided.
+func CombineScalar(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("less than two parts cannot be used to reconstruct the secret")
+	}
+	xs := make([]uint8, len(shares))
+	ys := make([]*edwards25519.Scalar, len(shares))
+	seen := make(map[uint8]bool)
+	for i, share := range shares {
+		if len(share) != ScalarShareSize || share[scalarSize] == 0 {
+			return nil, errors.Errorf("invalid share %d", i)
+		}
+		x := share[scalarSize]
+		if seen[x] {
+			return nil, errors.New("duplicate part detected")
+		}
+		y, err := edwards25519.NewScalar().SetCanonicalBytes(share[:scalarSize])
+		if err != nil {
+			return nil, errors.Errorf("invalid share %d", i)
+		}
+		seen[x] = true
+		xs[i] = x
+		ys[i] = y
+	}
+	val := edwards25519.NewScalar()
+	for i, x := range xs {
+		val.MultiplyAdd(lagrangeCoefficient(x, xs), ys[i], val)
+	}
+	return val.Bytes(), nil
+}
+
+// LagrangeCoefficient returns, in its canonical 32 bytes encoding, the
+// Lagrange coefficient at the origin for the share with coordinate 'x' when
+// interpolating using the shares with coordinates in 'set'.
+func LagrangeCoefficient(x uint8, set []uint8) ([]byte, error) {
+	found := false
+	seen := make(map[uint8]bool)
+	for _, j := range set {
+		if j == 0 || seen[j] {
+			return nil, errors.New("invalid coordinates set")
+		}
+		seen[j] = true
+		found = found || j == x
+	}
+	if !found {
+		return nil, errors.New("coordinate not in set")
+	}
+	return lagrangeCoefficient(x, set).Bytes(), nil
+}
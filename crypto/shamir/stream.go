@@ -0,0 +1,202 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.bryk.io/pkg/crypto/tred"
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	streamMagic   = "SSSE"
+	shareMagic    = "SSSK"
+	streamVersion = 0x01
+	streamIDSize  = 16
+	dataKeySize   = 32
+
+	// magic (4) | version (1) | stream ID (16)
+	streamHeaderSize = 4 + 1 + streamIDSize
+)
+
+// SplitStream encrypts the (arbitrarily large) content read from 'input',
+// using a random data key and the TRED protocol, and writes the result to
+// 'output'. The data key is then split in `parts` shares, `threshold` of which
+// are required to restore it and decrypt the content. Each returned share is
+// bound to the encrypted content and should be distributed to a different
+// holder.
+func SplitStream(input io.Reader, output io.Writer, parts, threshold int) ([][]byte, error) {
+	header := make([]byte, streamHeaderSize)
+	copy(header, streamMagic)
+	header[4] = streamVersion
+	if _, err := rand.Read(header[5:]); err != nil {
+		return nil, errors.Wrap(err, "failed to generate stream ID")
+	}
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate data key")
+	}
+	defer wipe(key)
+
+	// Split data key
+	keyShares, err := Split(key, parts, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encrypt content
+	w, err := streamWorker(key, header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = output.Write(header); err != nil {
+		return nil, errors.Wrap(err, "failed to write output")
+	}
+	if _, err = w.Encrypt(input, output); err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt content")
+	}
+
+	// Encode shares
+	shares := make([][]byte, parts)
+	for i, ks := range keyShares {
+		shares[i] = append(append([]byte(shareMagic), header[4:]...), ks...)
+		wipe(ks)
+	}
+	return shares, nil
+}
+
+// CombineStream restores the data key from the provided shares and uses it
+// to decrypt the content produced by 'SplitStream'. The decrypted content
+// is written to 'output'.
+func CombineStream(shares [][]byte, input io.Reader, output io.Writer) error {
+	// Validate shares
+	var id []byte
+	keyShares := make([][]byte, len(shares))
+	for i, share := range shares {
+		if len(share) != streamHeaderSize+dataKeySize+ShareOverhead ||
+			string(share[:4]) != shareMagic ||
+			share[4] != streamVersion {
+			return errors.Errorf("invalid share %d", i)
+		}
+		if id == nil {
+			id = share[5:streamHeaderSize]
+		}
+		if !bytes.Equal(id, share[5:streamHeaderSize]) {
+			return errors.New("shares belong to different streams")
+		}
+		keyShares[i] = share[streamHeaderSize:]
+	}
+
+	// Validate stream header
+	header := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(input, header); err != nil {
+		return errors.Wrap(err, "failed to read input")
+	}
+	if string(header[:4]) != streamMagic || header[4] != streamVersion {
+		return errors.New("invalid input")
+	}
+	if !bytes.Equal(id, header[5:]) {
+		return errors.New("shares don't belong to the provided input")
+	}
+
+	// Restore data key and decrypt content
+	key, err := Combine(keyShares)
+	if err != nil {
+		return err
+	}
+	defer wipe(key)
+	w, err := streamWorker(key, header)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Decrypt(input, output); err != nil {
+		return errors.Wrap(err, "failed to decrypt content")
+	}
+	return nil
+}
+
+// SplitFile encrypts the file at 'src' and stores the result at 'dst'. A
+// share file is produced for each holder next to 'dst', using the naming
+// pattern "dst.share-N". Returns the paths of all share files created.
+func SplitFile(src, dst string, parts, threshold int) ([]string, error) {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create file")
+	}
+	shares, err := SplitStream(in, out, parts, threshold)
+	if cErr := out.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return nil, err
+	}
+	list := make([]string, len(shares))
+	for i, share := range shares {
+		list[i] = fmt.Sprintf("%s.share-%d", dst, i+1)
+		if err = os.WriteFile(list[i], share, 0600); err != nil {
+			return nil, errors.Wrap(err, "failed to write share file")
+		}
+	}
+	return list, nil
+}
+
+// CombineFile decrypts the file at 'src', produced by 'SplitFile', using the
+// provided share files; and stores the result at 'dst'.
+func CombineFile(shareFiles []string, src, dst string) error {
+	shares := make([][]byte, len(shareFiles))
+	for i, sf := range shareFiles {
+		share, err := os.ReadFile(filepath.Clean(sf))
+		if err != nil {
+			return errors.Wrap(err, "failed to read share file")
+		}
+		shares[i] = share
+	}
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	err = CombineStream(shares, in, out)
+	if cErr := out.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+	}
+	return err
+}
+
+// TRED worker used to process the stream contents. The stream header is
+// bound to every packet as associated data.
+func streamWorker(key, header []byte) (*tred.Worker, error) {
+	conf, err := tred.DefaultConfig(key)
+	if err != nil {
+		return nil, err
+	}
+	conf.AssociatedData = header
+	return tred.NewWorker(conf)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,232 @@
+package shamir
+
+import (
+	"encoding/binary"
+
+	"filippo.io/edwards25519"
+	"go.bryk.io/pkg/errors"
+)
+
+// Commitments are the public values produced when splitting a secret using
+// verifiable secret sharing. They allow each share holder to verify its share
+// is consistent with all other shares. Pedersen commitments are used; every
+// value is blinded using a random factor, so even short or low-entropy secrets
+// can't be recovered by brute-forcing the commitments.
+//
+//	threshold (1) | secret length (4) | points (32 * threshold * chunks)
+type Commitments []byte
+
+// Threshold returns the number of shares required to restore the secret.
+func (c Commitments) Threshold() int {
+	return int(c[0])
+}
+
+// SecretLen returns the size, in bytes, of the original secret.
+func (c Commitments) SecretLen() int {
+	return int(binary.BigEndian.Uint32(c[1:5]))
+}
+
+// Validate the commitments structure.
+func (c Commitments) validate() error {
+	if len(c) < 5 || c.Threshold() < 2 || c.SecretLen() == 0 {
+		return errors.New("invalid commitments")
+	}
+	if len(c) != 5+c.chunks()*c.Threshold()*scalarSize {
+		return errors.New("invalid commitments")
+	}
+	return nil
+}
+
+// Number of secret chunks.
+func (c Commitments) chunks() int {
+	return (c.SecretLen() + chunkSize - 1) / chunkSize
+}
+
+// Commitment point for the coefficient 'j' of the chunk 'i' polynomial.
+func (c Commitments) point(i, j int) (*edwards25519.Point, error) {
+	offset := 5 + (i*c.Threshold()+j)*scalarSize
+	return new(edwards25519.Point).SetBytes(c[offset : offset+scalarSize])
+}
+
+// Size, in bytes, of each chunk on a verifiable share.
+//
+//	value (32) | blinding (32)
+const verifiableChunkSize = 2 * scalarSize
+
+// VerifiableShareSize returns the size, in bytes, of each share produced by
+// 'SplitVerifiable' for a secret of the given size.
+func VerifiableShareSize(secretLen int) int {
+	return ((secretLen+chunkSize-1)/chunkSize)*verifiableChunkSize + ShareOverhead
+}
+
+// SplitVerifiable works like 'Split' but, in addition to the shares, returns
+// a set of public commitments (Pedersen's verifiable secret sharing) that can
+// be used to verify each individual share using 'VerifyShare'. The secret is
+// processed in chunks of 31 bytes, each one encoded as a scalar value of the
+// edwards25519 group. Shares include a blinding value for each chunk and are
+// larger than the ones produced by 'Split'.
+func SplitVerifiable(secret []byte, parts, threshold int) ([][]byte, Commitments, error) {
+	if err := checkSplitParams(secret, parts, threshold); err != nil {
+		return nil, nil, err
+	}
+	chunks := (len(secret) + chunkSize - 1) / chunkSize
+
+	// Commitments header
+	comm := make(Commitments, 5, 5+chunks*threshold*scalarSize)
+	comm[0] = uint8(threshold)
+	binary.BigEndian.PutUint32(comm[1:5], uint32(len(secret)))
+
+	// Allocate shares; x coordinates are 1..parts
+	out := make([][]byte, parts)
+	for idx := range out {
+		out[idx] = make([]byte, chunks*verifiableChunkSize+ShareOverhead)
+		out[idx][chunks*verifiableChunkSize] = uint8(idx + 1)
+	}
+
+	// Use a new polynomial for each chunk of the secret
+	for i := 0; i < chunks; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(secret) {
+			end = len(secret)
+		}
+		p, err := makeScalarPolynomial(scalarFromChunk(secret[i*chunkSize:end]), threshold-1)
+		if err != nil {
+			return nil, nil, errors.New("failed to generate polynomial")
+		}
+		r, err := randomScalar()
+		if err != nil {
+			return nil, nil, errors.New("failed to generate polynomial")
+		}
+		b, err := makeScalarPolynomial(r, threshold-1)
+		if err != nil {
+			return nil, nil, errors.New("failed to generate polynomial")
+		}
+		for _, c := range p.commitments(b) {
+			comm = append(comm, c.Bytes()...)
+		}
+		for j := range out {
+			x := scalarFromInt(uint8(j + 1))
+			copy(out[j][i*verifiableChunkSize:], p.evaluate(x).Bytes())
+			copy(out[j][i*verifiableChunkSize+scalarSize:], b.evaluate(x).Bytes())
+		}
+	}
+	return out, comm, nil
+}
+
+// VerifyShare checks the provided share is consistent with the public
+// commitments produced when the secret was split.
+func VerifyShare(share []byte, commitments Commitments) error {
+	if err := commitments.validate(); err != nil {
+		return err
+	}
+	chunks := commitments.chunks()
+	if len(share) != chunks*verifiableChunkSize+ShareOverhead {
+		return errors.New("invalid share length")
+	}
+	x := share[len(share)-1]
+	if x == 0 {
+		return errors.New("invalid share")
+	}
+
+	// Powers of x: 1, x, x^2, ...
+	powers := make([]*edwards25519.Scalar, commitments.Threshold())
+	powers[0] = scalarFromInt(1)
+	for j := 1; j < len(powers); j++ {
+		powers[j] = edwards25519.NewScalar().Multiply(powers[j-1], scalarFromInt(x))
+	}
+
+	// For each chunk verify: y*G + t*H == sum(C_j * x^j)
+	h := pedersenGenerator()
+	for i := 0; i < chunks; i++ {
+		y, t, err := verifiableChunk(share, i)
+		if err != nil {
+			return errors.New("invalid share")
+		}
+		points := make([]*edwards25519.Point, len(powers))
+		for j := range points {
+			if points[j], err = commitments.point(i, j); err != nil {
+				return errors.New("invalid commitments")
+			}
+		}
+		expected := new(edwards25519.Point).VarTimeMultiScalarMult(powers, points)
+		if new(edwards25519.Point).VarTimeDoubleScalarBaseMult(t, h, y).Equal(expected) != 1 {
+			return errors.New("share verification failed")
+		}
+	}
+	return nil
+}
+
+// CombineVerifiable verifies each one of the provided shares against the
+// public commitments and, if all of them are valid, reconstructs the secret.
+func CombineVerifiable(shares [][]byte, commitments Commitments) ([]byte, error) {
+	if err := commitments.validate(); err != nil {
+		return nil, err
+	}
+	if len(shares) < commitments.Threshold() {
+		return nil, errors.Errorf("at least %d shares are required", commitments.Threshold())
+	}
+	xs := make([]uint8, len(shares))
+	seen := make(map[uint8]bool)
+	for i, share := range shares {
+		if err := VerifyShare(share, commitments); err != nil {
+			return nil, errors.Wrapf(err, "share %d", i)
+		}
+		x := share[len(share)-1]
+		if seen[x] {
+			return nil, errors.New("duplicate part detected")
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+
+	// Lagrange interpolation at the origin for each chunk
+	coefficients := make([]*edwards25519.Scalar, len(xs))
+	for i, x := range xs {
+		coefficients[i] = lagrangeCoefficient(x, xs)
+	}
+	chunks := commitments.chunks()
+	secret := make([]byte, 0, chunks*chunkSize)
+	for c := 0; c < chunks; c++ {
+		val := edwards25519.NewScalar()
+		for i, share := range shares {
+			y, _, _ := verifiableChunk(share, c)
+			val.MultiplyAdd(coefficients[i], y, val)
+		}
+		secret = append(secret, val.Bytes()[:chunkSize]...)
+	}
+	return secret[:commitments.SecretLen()], nil
+}
+
+// Value and blinding factor for the chunk 'i' of a verifiable share.
+func verifiableChunk(share []byte, i int) (*edwards25519.Scalar, *edwards25519.Scalar, error) {
+	offset := i * verifiableChunkSize
+	y, err := edwards25519.NewScalar().SetCanonicalBytes(share[offset : offset+scalarSize])
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := edwards25519.NewScalar().SetCanonicalBytes(share[offset+scalarSize : offset+verifiableChunkSize])
+	if err != nil {
+		return nil, nil, err
+	}
+	return y, t, nil
+}
+
+// Sanity check the split input parameters.
+func checkSplitParams(secret []byte, parts, threshold int) error {
+	if parts < threshold {
+		return errors.New("parts cannot be less than threshold")
+	}
+	if parts > 255 {
+		return errors.New("parts cannot exceed 255")
+	}
+	if threshold < 2 {
+		return errors.New("threshold must be at least 2")
+	}
+	if threshold > 255 {
+		return errors.New("threshold cannot exceed 255")
+	}
+	if len(secret) == 0 {
+		return errors.New("cannot split an empty secret")
+	}
+	return nil
+}
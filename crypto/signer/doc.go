@@ -0,0 +1,48 @@
+/*
+Package signer provides a common abstraction for keys able to produce digital
+signatures, regardless of where the private key material is stored.
+
+A 'Signer' extends the standard 'crypto.Signer' interface with a key identifier
+and the JWA algorithm identifier for the signatures produced. This allows the
+same key to be used to generate DID proofs, JWT instances or TLS certificates;
+whether the key is held in memory or it never leaves an HSM or cloud KMS.
+
+# In-Memory Keys
+
+The key pairs provided by the "crypto/ed25519" and "crypto/secp256k1" packages
+can be used as signers.
+
+	kp, _ := ed25519.New()
+	sig := signer.Ed25519("my-key", kp)
+
+# Key Providers
+
+A 'KeyProvider' gives access to a collection of signers. Besides the in-memory
+provider included in this package, keys stored in HSMs can be used through the
+"pkcs11" sub-package.
+
+	kp := signer.NewMemoryProvider(sig)
+	s, err := kp.Signer("my-key")
+
+# Usage
+
+Signers can be used to issue JWT instances, produce DID proofs and enable TLS
+communications.
+
+	// Issue JWT instances, see "jose/jwt"
+	gen, _ := jwt.NewGenerator("acme.com", jwt.WithSigner(s))
+
+	// Produce DID signatures and proofs, see "did"
+	_ = id.AddSignerVerificationMethod("master", s)
+
+	// Use as private key for a TLS server, see "net/http", "net/rpc" and "net/drpc"
+	settings := http.TLS{Cert: certPEM, Signer: s}
+
+	// Or build the TLS certificate directly
+	cert, _ := signer.TLSCertificate(certPEM, s)
+
+Keys can also be used wherever a 'jwk.Key' is expected.
+
+	k, _ := signer.JWK(s)
+*/
+package signer
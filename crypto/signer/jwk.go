@@ -0,0 +1,96 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/asn1"
+	"io"
+	"math/big"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// JWK returns a JWK key instance producing signatures with the provided
+// signer; for example, to issue JWT instances using a key stored in an HSM.
+// The key ID is set to the signer's identifier. Since the private key
+// material is not available, the key can't be exported or encoded with its
+// private portion.
+func JWK(s Signer) (jwk.Key, error) {
+	rec, err := jwk.FromCryptoKey(s.Public(), s.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	rec.KeyID = s.ID()
+	pub, err := jwk.Import(rec)
+	if err != nil {
+		return nil, err
+	}
+	return &jwkSigner{Key: pub, s: s}, nil
+}
+
+// JWK key delegating signature operations to a signer; all other operations
+// use the public key.
+type jwkSigner struct {
+	jwk.Key
+	s Signer
+}
+
+func (k *jwkSigner) Public() crypto.PublicKey {
+	return k.s.Public()
+}
+
+// Sign produces a JWS signature value for `data`. ECDSA signatures are encoded
+// as the 'r || s' concatenation required by RFC-7518.
+func (k *jwkSigner) Sign(rr io.Reader, data []byte, hh crypto.SignerOpts) ([]byte, error) {
+	alg := k.s.Algorithm()
+	if alg == jwa.EdDSA {
+		return k.s.Sign(rr, data, crypto.Hash(0))
+	}
+	hf := hh.HashFunc()
+	h := hf.New()
+	_, _ = h.Write(data)
+	var opts crypto.SignerOpts = hf
+	if alg[0:2] == "PS" {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hf}
+	}
+	sig, err := k.s.Sign(rr, h.Sum(nil), opts)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := k.s.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return sig, nil
+	}
+
+	// Convert ASN.1 DER signature to 'r || s'
+	var es struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(sig, &es); err != nil {
+		return nil, errors.Wrap(err, "invalid ECDSA signature")
+	}
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	raw := make([]byte, 2*size)
+	es.R.FillBytes(raw[:size])
+	es.S.FillBytes(raw[size:])
+	return raw, nil
+}
+
+// Export returns the public key record, private key information is never
+// included.
+func (k *jwkSigner) Export(_ bool) jwk.Record {
+	return k.Key.Export(true)
+}
+
+func (k *jwkSigner) Import(_ jwk.Record) error {
+	return errors.New("signer keys can't be imported")
+}
+
+func (k *jwkSigner) MarshalBinary() ([]byte, error) {
+	return nil, errors.New("signer keys can't be encoded")
+}
+
+func (k *jwkSigner) UnmarshalBinary(_ []byte) error {
+	return errors.New("signer keys can't be decoded")
+}
@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"sort"
+	"sync"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// MemoryProvider is a key provider holding signers in memory.
+type MemoryProvider struct {
+	signers map[string]Signer
+	mu      sync.RWMutex
+}
+
+// NewMemoryProvider returns a new key provider instance holding the
+// provided signers.
+func NewMemoryProvider(signers ...Signer) *MemoryProvider {
+	mp := &MemoryProvider{signers: make(map[string]Signer)}
+	mp.Add(signers...)
+	return mp
+}
+
+// Add signers to the provider. Existing signers with the same
+// identifier are replaced.
+func (mp *MemoryProvider) Add(signers ...Signer) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	for _, s := range signers {
+		mp.signers[s.ID()] = s
+	}
+}
+
+// Remove the signer with the provided identifier.
+func (mp *MemoryProvider) Remove(id string) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	delete(mp.signers, id)
+}
+
+// Signer returns the signer for the key with the provided identifier.
+func (mp *MemoryProvider) Signer(id string) (Signer, error) {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	s, ok := mp.signers[id]
+	if !ok {
+		return nil, errors.Errorf("unknown key: %s", id)
+	}
+	return s, nil
+}
+
+// List returns the identifiers of all keys available.
+func (mp *MemoryProvider) List() ([]string, error) {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	list := make([]string, 0, len(mp.signers))
+	for id := range mp.signers {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// Close removes all signers from the provider.
+func (mp *MemoryProvider) Close() error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	mp.signers = make(map[string]Signer)
+	return nil
+}
@@ -0,0 +1,24 @@
+/*
+Package pkcs11 provides a key provider backed by a PKCS#11 module; usually an
+HSM, a smart card or a cloud KMS exposing a PKCS#11 interface.
+
+Private keys never leave the device, all signing operations are performed by
+the module. Keys are located by their 'CKA_LABEL' attribute or, for keys without
+a label, by the hex-encoded value of their 'CKA_ID' attribute. ECDSA (P-256,
+P-384, P-521 and secp256k1) and RSA keys are supported.
+
+	// Open a session with the token
+	hsm, err := pkcs11.New("/usr/lib/softhsm/libsofthsm2.so",
+		pkcs11.WithTokenLabel("my-token"),
+		pkcs11.WithPIN("1234"))
+	if err != nil {
+		panic(err)
+	}
+	defer hsm.Close()
+
+	// Get a signer for a key stored in the token
+	s, err := hsm.Signer("my-key")
+
+The package requires cgo to load the PKCS#11 module.
+*/
+package pkcs11
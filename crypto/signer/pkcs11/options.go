@@ -0,0 +1,33 @@
+//go:build cgo
+// +build cgo
+
+package pkcs11
+
+// Option elements provide a functional-style configuration system for
+// PKCS#11 providers.
+type Option func(p *Provider) error
+
+// WithSlot selects the slot to use by its identifier.
+func WithSlot(id uint) Option {
+	return func(p *Provider) error {
+		p.slot = &id
+		return nil
+	}
+}
+
+// WithTokenLabel selects the slot to use by the label of the token
+// present on it.
+func WithTokenLabel(label string) Option {
+	return func(p *Provider) error {
+		p.tokenLabel = label
+		return nil
+	}
+}
+
+// WithPIN sets the user PIN used to log in to the token.
+func WithPIN(pin string) Option {
+	return func(p *Provider) error {
+		p.pin = pin
+		return nil
+	}
+}
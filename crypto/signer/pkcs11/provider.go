@@ -0,0 +1,259 @@
+//go:build cgo
+// +build cgo
+
+package pkcs11
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+	"go.bryk.io/pkg/crypto/signer"
+	"go.bryk.io/pkg/errors"
+)
+
+// Returned when no object matches the search criteria.
+var errKeyNotFound = errors.New("key not found")
+
+// Provider gives access to the keys stored in a PKCS#11 token.
+type Provider struct {
+	ctx        *p11.Ctx
+	session    p11.SessionHandle
+	slot       *uint
+	tokenLabel string
+	pin        string
+	mu         sync.Mutex
+}
+
+// New loads the PKCS#11 module and opens a session with the selected
+// token. If no slot or token label is provided, the first slot with a
+// token present will be used. Remember to call 'Close' when the provider
+// is no longer needed.
+func New(module string, opts ...Option) (*Provider, error) {
+	p := &Provider{}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+
+	// Load module
+	p.ctx = p11.New(module)
+	if p.ctx == nil {
+		return nil, errors.Errorf("failed to load module: %s", module)
+	}
+	if err := p.ctx.Initialize(); err != nil {
+		p.ctx.Destroy()
+		return nil, errors.Wrap(err, "failed to initialize module")
+	}
+
+	// Open session
+	slot, err := p.findSlot()
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	p.session, err = p.ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION)
+	if err != nil {
+		p.release()
+		return nil, errors.Wrap(err, "failed to open session")
+	}
+	if p.pin != "" {
+		if err = p.ctx.Login(p.session, p11.CKU_USER, p.pin); err != nil {
+			_ = p.ctx.CloseSession(p.session)
+			p.release()
+			return nil, errors.Wrap(err, "failed to log in")
+		}
+	}
+	return p, nil
+}
+
+// Signer returns the signer for the private key with the provided identifier;
+// either its label or, for keys without a label, the hex-encoded value of its
+// 'CKA_ID' attribute, as returned by 'List'.
+func (p *Provider) Signer(id string) (signer.Signer, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	priv, err := p.findPrivateKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locate the corresponding public key; by CKA_ID if available
+	filter := []*p11.Attribute{p11.NewAttribute(p11.CKA_LABEL, id)}
+	attrs, err := p.ctx.GetAttributeValue(p.session, priv, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_ID, nil),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, nil),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read key attributes")
+	}
+	if len(attrs[0].Value) > 0 {
+		filter = []*p11.Attribute{p11.NewAttribute(p11.CKA_ID, attrs[0].Value)}
+	}
+	pub, err := p.findObject(p11.CKO_PUBLIC_KEY, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load public key
+	s := &hsmSigner{id: id, handle: priv, provider: p}
+	switch keyType := bytesToUint(attrs[1].Value); keyType {
+	case p11.CKK_EC:
+		err = s.loadEC(pub)
+	case p11.CKK_RSA:
+		err = s.loadRSA(pub)
+	default:
+		err = errors.Errorf("unsupported key type: %d", keyType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// List returns the identifiers of all private keys available. Keys are
+// identified by their label or, if not set, by the hex-encoded value of their
+// 'CKA_ID' attribute. Keys without a label or 'CKA_ID' can't be selected and
+// are not included.
+func (p *Provider) List() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	handles, err := p.findObjects([]*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, h := range handles {
+		attrs, err := p.ctx.GetAttributeValue(p.session, h, []*p11.Attribute{
+			p11.NewAttribute(p11.CKA_LABEL, nil),
+			p11.NewAttribute(p11.CKA_ID, nil),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read key attributes")
+		}
+		if label := string(attrs[0].Value); label != "" {
+			list = append(list, label)
+			continue
+		}
+		if len(attrs[1].Value) > 0 {
+			list = append(list, hex.EncodeToString(attrs[1].Value))
+		}
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// Close the session with the token and unload the module.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx == nil {
+		return nil
+	}
+	if p.pin != "" {
+		_ = p.ctx.Logout(p.session)
+	}
+	err := p.ctx.CloseSession(p.session)
+	p.release()
+	return err
+}
+
+// Select the slot to use based on the provider settings.
+func (p *Provider) findSlot() (uint, error) {
+	if p.slot != nil {
+		return *p.slot, nil
+	}
+	slots, err := p.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list slots")
+	}
+	for _, slot := range slots {
+		if p.tokenLabel == "" {
+			return slot, nil
+		}
+		info, err := p.ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if info.Label == p.tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, errors.New("no suitable slot found")
+}
+
+// Locate a private key by its label or, if no label matches, by the
+// hex-encoded value of its 'CKA_ID' attribute.
+func (p *Provider) findPrivateKey(id string) (p11.ObjectHandle, error) {
+	handle, err := p.findObject(p11.CKO_PRIVATE_KEY, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_LABEL, id),
+	})
+	if !errors.Is(err, errKeyNotFound) {
+		return handle, err
+	}
+	ckaID, hErr := hex.DecodeString(id)
+	if hErr != nil || len(ckaID) == 0 {
+		return 0, err
+	}
+	return p.findObject(p11.CKO_PRIVATE_KEY, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_ID, ckaID),
+	})
+}
+
+// Return the single object of the given class matching the filter.
+func (p *Provider) findObject(class uint, filter []*p11.Attribute) (p11.ObjectHandle, error) {
+	template := append([]*p11.Attribute{p11.NewAttribute(p11.CKA_CLASS, class)}, filter...)
+	handles, err := p.findObjects(template, 2)
+	if err != nil {
+		return 0, err
+	}
+	switch len(handles) {
+	case 0:
+		return 0, errKeyNotFound
+	case 1:
+		return handles[0], nil
+	default:
+		return 0, errors.New("multiple keys found")
+	}
+}
+
+// Return all objects matching the template; up to 'max' if greater than 0.
+func (p *Provider) findObjects(template []*p11.Attribute, max int) ([]p11.ObjectHandle, error) {
+	if err := p.ctx.FindObjectsInit(p.session, template); err != nil {
+		return nil, errors.Wrap(err, "failed to find objects")
+	}
+	defer func() {
+		_ = p.ctx.FindObjectsFinal(p.session)
+	}()
+	var list []p11.ObjectHandle
+	for {
+		handles, _, err := p.ctx.FindObjects(p.session, 100)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find objects")
+		}
+		list = append(list, handles...)
+		if len(handles) == 0 || (max > 0 && len(list) >= max) {
+			return list, nil
+		}
+	}
+}
+
+// Unload the module.
+func (p *Provider) release() {
+	_ = p.ctx.Finalize()
+	p.ctx.Destroy()
+	p.ctx = nil
+}
+
+// Decode a CK_ULONG attribute value (native byte order).
+func bytesToUint(b []byte) uint {
+	var v uint
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint(b[i])
+	}
+	return v
+}
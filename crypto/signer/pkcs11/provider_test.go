@@ -0,0 +1,131 @@
+//go:build cgo
+// +build cgo
+
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	p11 "github.com/miekg/pkcs11"
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/signer"
+)
+
+// Tests require access to a PKCS#11 module (e.g., SoftHSM) with an existing
+// key pair. Use the following environment variables to run them:
+//
+//	PKCS11_MODULE, PKCS11_TOKEN, PKCS11_PIN, PKCS11_KEY
+func TestProvider(t *testing.T) {
+	module := os.Getenv("PKCS11_MODULE")
+	if module == "" {
+		t.Skip("PKCS11_MODULE not set")
+	}
+	assert := tdd.New(t)
+	hsm, err := New(module,
+		WithTokenLabel(os.Getenv("PKCS11_TOKEN")),
+		WithPIN(os.Getenv("PKCS11_PIN")))
+	if !assert.Nil(err, "open session") {
+		return
+	}
+	defer func() {
+		assert.Nil(hsm.Close())
+	}()
+
+	list, err := hsm.List()
+	assert.Nil(err, "list keys")
+	assert.Contains(list, os.Getenv("PKCS11_KEY"))
+
+	var kp signer.KeyProvider = hsm
+	s, err := kp.Signer(os.Getenv("PKCS11_KEY"))
+	if !assert.Nil(err, "get signer") {
+		return
+	}
+	msg := []byte("message to sign")
+	sig, err := signer.SignMessage(s, msg)
+	assert.Nil(err, "sign")
+	hf, _ := s.Algorithm().HashFunction()
+	h := hf.New()
+	h.Write(msg)
+	digest := h.Sum(nil)
+	switch pub := s.Public().(type) {
+	case *ecdsa.PublicKey:
+		assert.True(ecdsa.VerifyASN1(pub, digest, sig), "invalid signature")
+	case *rsa.PublicKey:
+		assert.Nil(rsa.VerifyPKCS1v15(pub, hf, digest, sig), "invalid signature")
+	}
+}
+
+// Keys without a label are selected by the hex-encoded value of their
+// 'CKA_ID' attribute. A temporary (session) key pair is generated for the
+// test. Requires the PKCS11_MODULE, PKCS11_TOKEN and PKCS11_PIN environment
+// variables.
+func TestProviderUnlabeledKey(t *testing.T) {
+	module := os.Getenv("PKCS11_MODULE")
+	if module == "" {
+		t.Skip("PKCS11_MODULE not set")
+	}
+	assert := tdd.New(t)
+	hsm, err := New(module,
+		WithTokenLabel(os.Getenv("PKCS11_TOKEN")),
+		WithPIN(os.Getenv("PKCS11_PIN")))
+	if !assert.Nil(err, "open session") {
+		return
+	}
+	defer func() {
+		assert.Nil(hsm.Close())
+	}()
+
+	// Generate P-256 key pair without label
+	ckaID := make([]byte, 16)
+	_, _ = rand.Read(ckaID)
+	params, _ := asn1.Marshal(oidP256)
+	pub, priv, err := hsm.ctx.GenerateKeyPair(hsm.session,
+		[]*p11.Mechanism{p11.NewMechanism(p11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_TOKEN, false),
+			p11.NewAttribute(p11.CKA_VERIFY, true),
+			p11.NewAttribute(p11.CKA_EC_PARAMS, params),
+			p11.NewAttribute(p11.CKA_ID, ckaID),
+		},
+		[]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_TOKEN, false),
+			p11.NewAttribute(p11.CKA_SIGN, true),
+			p11.NewAttribute(p11.CKA_PRIVATE, true),
+			p11.NewAttribute(p11.CKA_ID, ckaID),
+		})
+	if !assert.Nil(err, "generate key pair") {
+		return
+	}
+	defer func() {
+		_ = hsm.ctx.DestroyObject(hsm.session, priv)
+		_ = hsm.ctx.DestroyObject(hsm.session, pub)
+	}()
+
+	id := hex.EncodeToString(ckaID)
+	list, err := hsm.List()
+	assert.Nil(err, "list keys")
+	assert.Contains(list, id)
+
+	s, err := hsm.Signer(id)
+	if !assert.Nil(err, "get signer") {
+		return
+	}
+	assert.Equal(id, s.ID())
+	msg := []byte("message to sign")
+	sig, err := signer.SignMessage(s, msg)
+	assert.Nil(err, "sign")
+	digest := sha256.Sum256(msg)
+	pk, ok := s.Public().(*ecdsa.PublicKey)
+	assert.True(ok, "public key")
+	assert.True(ecdsa.VerifyASN1(pk, digest[:], sig), "invalid signature")
+
+	_, err = hsm.Signer(hex.EncodeToString([]byte("unknown")))
+	assert.NotNil(err, "unknown key")
+}
@@ -0,0 +1,190 @@
+//go:build cgo
+// +build cgo
+
+package pkcs11
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"io"
+	"math/big"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	p11 "github.com/miekg/pkcs11"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// Supported named curves.
+var (
+	oidP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidP384      = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidP521      = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// DigestInfo prefixes used for PKCS#1 v1.5 signatures.
+// https://www.rfc-editor.org/rfc/rfc8017#section-9.2
+var digestInfo = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// PSS parameters for each supported hash function.
+var pssParams = map[crypto.Hash][2]uint{
+	crypto.SHA256: {p11.CKM_SHA256, p11.CKG_MGF1_SHA256},
+	crypto.SHA384: {p11.CKM_SHA384, p11.CKG_MGF1_SHA384},
+	crypto.SHA512: {p11.CKM_SHA512, p11.CKG_MGF1_SHA512},
+}
+
+// Signer for a private key stored in a PKCS#11 token.
+type hsmSigner struct {
+	id       string
+	alg      jwa.Alg
+	pub      crypto.PublicKey
+	handle   p11.ObjectHandle
+	provider *Provider
+}
+
+func (s *hsmSigner) ID() string {
+	return s.id
+}
+
+func (s *hsmSigner) Algorithm() jwa.Alg {
+	return s.alg
+}
+
+func (s *hsmSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *hsmSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts == nil || opts.HashFunc() == crypto.Hash(0) {
+		return nil, errors.New("a message digest is required")
+	}
+	if len(digest) != opts.HashFunc().Size() {
+		return nil, errors.New("invalid digest size")
+	}
+	switch s.pub.(type) {
+	case *ecdsa.PublicKey:
+		sig, err := s.sign(p11.NewMechanism(p11.CKM_ECDSA, nil), digest)
+		if err != nil {
+			return nil, err
+		}
+		// Convert raw 'r || s' value to ASN.1 DER
+		half := len(sig) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			R: new(big.Int).SetBytes(sig[:half]),
+			S: new(big.Int).SetBytes(sig[half:]),
+		})
+	case *rsa.PublicKey:
+		if pss, ok := opts.(*rsa.PSSOptions); ok {
+			params, ok := pssParams[pss.HashFunc()]
+			if !ok {
+				return nil, errors.New("unsupported hash function")
+			}
+			salt := pss.SaltLength
+			if salt == rsa.PSSSaltLengthAuto || salt == rsa.PSSSaltLengthEqualsHash {
+				salt = pss.HashFunc().Size()
+			}
+			mech := p11.NewMechanism(p11.CKM_RSA_PKCS_PSS, p11.NewPSSParams(params[0], params[1], uint(salt)))
+			return s.sign(mech, digest)
+		}
+		prefix, ok := digestInfo[opts.HashFunc()]
+		if !ok {
+			return nil, errors.New("unsupported hash function")
+		}
+		return s.sign(p11.NewMechanism(p11.CKM_RSA_PKCS, nil), append(append([]byte{}, prefix...), digest...))
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+}
+
+// Perform a signature operation on the token.
+func (s *hsmSigner) sign(mech *p11.Mechanism, data []byte) ([]byte, error) {
+	p := s.provider
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx == nil {
+		return nil, errors.New("provider is closed")
+	}
+	if err := p.ctx.SignInit(p.session, []*p11.Mechanism{mech}, s.handle); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize signature operation")
+	}
+	sig, err := p.ctx.Sign(p.session, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to produce signature")
+	}
+	return sig, nil
+}
+
+// Load an EC public key.
+func (s *hsmSigner) loadEC(handle p11.ObjectHandle) error {
+	p := s.provider
+	attrs, err := p.ctx.GetAttributeValue(p.session, handle, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+		p11.NewAttribute(p11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read public key")
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err = asn1.Unmarshal(attrs[0].Value, &curve); err != nil {
+		return errors.Wrap(err, "invalid curve parameters")
+	}
+
+	// EC point is usually encoded as an ASN.1 octet string
+	point := attrs[1].Value
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err == nil && len(rest) == 0 {
+		point = raw
+	}
+
+	var crv elliptic.Curve
+	switch {
+	case curve.Equal(oidP256):
+		crv, s.alg = elliptic.P256(), jwa.ES256
+	case curve.Equal(oidP384):
+		crv, s.alg = elliptic.P384(), jwa.ES384
+	case curve.Equal(oidP521):
+		crv, s.alg = elliptic.P521(), jwa.ES512
+	case curve.Equal(oidSecp256k1):
+		pk, err := secp.ParsePubKey(point)
+		if err != nil {
+			return errors.Wrap(err, "invalid public key")
+		}
+		s.alg = jwa.ES256K
+		s.pub = pk.ToECDSA()
+		return nil
+	default:
+		return errors.Errorf("unsupported curve: %s", curve)
+	}
+	x, y := elliptic.Unmarshal(crv, point) // nolint: staticcheck
+	if x == nil {
+		return errors.New("invalid public key")
+	}
+	s.pub = &ecdsa.PublicKey{Curve: crv, X: x, Y: y}
+	return nil
+}
+
+// Load a RSA public key.
+func (s *hsmSigner) loadRSA(handle p11.ObjectHandle) error {
+	p := s.provider
+	attrs, err := p.ctx.GetAttributeValue(p.session, handle, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_MODULUS, nil),
+		p11.NewAttribute(p11.CKA_PUBLIC_EXPONENT, nil),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read public key")
+	}
+	s.alg = jwa.RS256
+	s.pub = &rsa.PublicKey{
+		N: new(big.Int).SetBytes(attrs[0].Value),
+		E: int(new(big.Int).SetBytes(attrs[1].Value).Int64()),
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"crypto"
+	stded25519 "crypto/ed25519"
+	"crypto/sha256"
+	"io"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/secp256k1"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// Signer represents a private key able to produce digital signatures. The
+// private key material is not required to be accessible by the application;
+// for example when stored in an HSM or cloud KMS.
+//
+// The 'Sign' method follows the semantics of the standard 'crypto.Signer'
+// interface; i.e., ECDSA and RSA keys expect a message digest while Ed25519
+// keys expect the complete (unhashed) message.
+type Signer interface {
+	crypto.Signer
+
+	// ID returns the identifier of the key.
+	ID() string
+
+	// Algorithm returns the JWA identifier for the signatures produced.
+	Algorithm() jwa.Alg
+}
+
+// KeyProvider gives access to a collection of signers.
+type KeyProvider interface {
+	// Signer returns the signer for the key with the provided identifier.
+	Signer(id string) (Signer, error)
+
+	// List returns the identifiers of all keys available.
+	List() ([]string, error)
+
+	// Close releases any resources held by the provider.
+	Close() error
+}
+
+// SignMessage is a helper method to produce a signature over the provided
+// message; calculating the message digest first if required by the signer's
+// algorithm.
+func SignMessage(s Signer, message []byte) ([]byte, error) {
+	if s.Algorithm() == jwa.EdDSA {
+		return s.Sign(nil, message, crypto.Hash(0))
+	}
+	hf, err := s.Algorithm().HashFunction()
+	if err != nil {
+		return nil, err
+	}
+	h := hf.New()
+	_, _ = h.Write(message)
+	return s.Sign(nil, h.Sum(nil), hf)
+}
+
+// Ed25519 returns a signer instance for the provided key pair.
+func Ed25519(id string, kp *ed25519.KeyPair) Signer {
+	return &ed25519Signer{id: id, kp: kp}
+}
+
+// Secp256k1 returns a signer instance for the provided key pair. Signatures
+// are ASN.1 DER encoded.
+func Secp256k1(id string, kp *secp256k1.KeyPair) Signer {
+	return &secp256k1Signer{id: id, kp: kp}
+}
+
+type ed25519Signer struct {
+	id string
+	kp *ed25519.KeyPair
+}
+
+func (s *ed25519Signer) ID() string {
+	return s.id
+}
+
+func (s *ed25519Signer) Algorithm() jwa.Alg {
+	return jwa.EdDSA
+}
+
+func (s *ed25519Signer) Public() crypto.PublicKey {
+	pub := s.kp.PublicKey()
+	return stded25519.PublicKey(pub[:])
+}
+
+func (s *ed25519Signer) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("ed25519: pre-hashed messages are not supported")
+	}
+	return s.kp.Sign(message), nil
+}
+
+type secp256k1Signer struct {
+	id string
+	kp *secp256k1.KeyPair
+}
+
+func (s *secp256k1Signer) ID() string {
+	return s.id
+}
+
+func (s *secp256k1Signer) Algorithm() jwa.Alg {
+	return jwa.ES256K
+}
+
+func (s *secp256k1Signer) Public() crypto.PublicKey {
+	pub := s.kp.PublicKey()
+	pk, err := secp.ParsePubKey(pub[:])
+	if err != nil {
+		return nil
+	}
+	return pk.ToECDSA()
+}
+
+func (s *secp256k1Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts == nil || opts.HashFunc() != crypto.SHA256 || len(digest) != sha256.Size {
+		return nil, errors.New("secp256k1: a SHA-256 digest is required")
+	}
+	priv := secp.PrivKeyFromBytes(s.kp.PrivateKey())
+	defer priv.Zero()
+	return ecdsa.Sign(priv, digest).Serialize(), nil
+}
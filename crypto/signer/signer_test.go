@@ -0,0 +1,194 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	stded25519 "crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/secp256k1"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
+}
+
+func TestSecp256k1(t *testing.T) {
+	assert := tdd.New(t)
+	kp, _ := secp256k1.New()
+	defer kp.Destroy()
+	s := Secp256k1("key-1", kp)
+	assert.Equal("key-1", s.ID())
+	assert.Equal(jwa.ES256K, s.Algorithm())
+	_, ok := s.Public().(*ecdsa.PublicKey)
+	assert.True(ok, "invalid public key type")
+
+	msg := []byte("message to sign")
+	sig, err := SignMessage(s, msg)
+	assert.Nil(err, "sign error")
+	assert.True(kp.Verify(msg, sig), "invalid signature")
+
+	// Digest is required
+	_, err = s.Sign(nil, msg, crypto.Hash(0))
+	assert.NotNil(err, "unhashed message")
+	digest := sha256.Sum256(msg)
+	_, err = s.Sign(nil, digest[:], crypto.SHA256)
+	assert.Nil(err, "sign digest")
+}
+
+func TestEd25519(t *testing.T) {
+	assert := tdd.New(t)
+	kp, _ := ed25519.New()
+	defer kp.Destroy()
+	s := Ed25519("key-2", kp)
+	assert.Equal(jwa.EdDSA, s.Algorithm())
+	pub, ok := s.Public().(stded25519.PublicKey)
+	assert.True(ok, "invalid public key type")
+	kpPub := kp.PublicKey()
+	assert.Equal(kpPub[:], []byte(pub), "invalid public key")
+
+	// Pre-hashed messages are not supported
+	_, err := s.Sign(nil, make([]byte, 32), crypto.SHA256)
+	assert.NotNil(err, "pre-hashed message")
+}
+
+func TestMemoryProvider(t *testing.T) {
+	assert := tdd.New(t)
+	k1, _ := secp256k1.New()
+	k2, _ := ed25519.New()
+	defer k1.Destroy()
+	defer k2.Destroy()
+
+	var kp KeyProvider = NewMemoryProvider(Secp256k1("b", k1), Ed25519("a", k2))
+	list, err := kp.List()
+	assert.Nil(err)
+	assert.Equal([]string{"a", "b"}, list)
+
+	s, err := kp.Signer("b")
+	assert.Nil(err)
+	assert.Equal(jwa.ES256K, s.Algorithm())
+	_, err = kp.Signer("c")
+	assert.NotNil(err, "unknown key")
+
+	kp.(*MemoryProvider).Remove("a")
+	list, _ = kp.List()
+	assert.Equal([]string{"b"}, list)
+	assert.Nil(kp.Close())
+	list, _ = kp.List()
+	assert.Empty(list)
+}
+
+// Signer backed by a standard library key, used to simulate keys held
+// by an external device.
+type stdSigner struct {
+	crypto.Signer
+	alg jwa.Alg
+}
+
+func (s *stdSigner) ID() string {
+	return "std-key"
+}
+
+func (s *stdSigner) Algorithm() jwa.Alg {
+	return s.alg
+}
+
+func TestJWK(t *testing.T) {
+	assert := tdd.New(t)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := stded25519.GenerateKey(rand.Reader)
+	signers := []Signer{
+		&stdSigner{Signer: ecKey, alg: jwa.ES384},
+		&stdSigner{Signer: rsaKey, alg: jwa.RS256},
+		&stdSigner{Signer: rsaKey, alg: jwa.PS256},
+		&stdSigner{Signer: edKey, alg: jwa.EdDSA},
+	}
+	msg := []byte("message to sign")
+	for _, s := range signers {
+		k, err := JWK(s)
+		if !assert.Nil(err, "jwk: %s", s.Algorithm()) {
+			continue
+		}
+		assert.Equal("std-key", k.ID())
+		assert.Equal(s.Algorithm(), k.Alg())
+		hf, _ := s.Algorithm().HashFunction()
+		sig, err := k.Sign(rand.Reader, msg, hf)
+		assert.Nil(err, "sign: %s", s.Algorithm())
+		assert.True(k.Verify(hf, msg, sig), "verify: %s", s.Algorithm())
+
+		// Private key is never exported
+		rec := k.Export(false)
+		assert.Empty(rec.D, "private key exported")
+		_, err = k.MarshalBinary()
+		assert.NotNil(err, "binary encoding")
+	}
+
+	// Unsupported key type
+	kp, _ := secp256k1.New()
+	defer kp.Destroy()
+	_, err := JWK(Secp256k1("key-1", kp))
+	assert.NotNil(err, "ES256K keys are not supported by JWK")
+}
+
+func TestTLSCertificate(t *testing.T) {
+	assert := tdd.New(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	// Invalid inputs
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, err := TLSCertificate(certPEM, &stdSigner{Signer: other, alg: jwa.ES256})
+	assert.NotNil(err, "key mismatch")
+	_, err = TLSCertificate(nil, &stdSigner{Signer: key, alg: jwa.ES256})
+	assert.NotNil(err, "no certificates")
+
+	// Complete a TLS handshake using the signer
+	cert, err := TLSCertificate(certPEM, &stdSigner{Signer: key, alg: jwa.ES256})
+	if !assert.Nil(err, "certificate") {
+		return
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert.Leaf)
+	sc, cc := net.Pipe()
+	defer func() {
+		_ = sc.Close()
+		_ = cc.Close()
+	}()
+	srv := tls.Server(sc, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Handshake()
+	}()
+	cl := tls.Client(cc, &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12})
+	assert.Nil(cl.Handshake(), "client handshake")
+	assert.Nil(<-done, "server handshake")
+}
@@ -0,0 +1,41 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// TLSCertificate returns a TLS certificate using the provided signer as its
+// private key; allowing TLS communications using keys that never leave an
+// HSM or cloud KMS. `certPEM` is the PEM-encoded certificate chain, the first
+// certificate must be the one for the signer's public key.
+func TLSCertificate(certPEM []byte, s crypto.Signer) (tls.Certificate, error) {
+	cert := tls.Certificate{PrivateKey: s}
+	for {
+		var block *pem.Block
+		block, certPEM = pem.Decode(certPEM)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			cert.Certificate = append(cert.Certificate, block.Bytes)
+		}
+	}
+	if len(cert.Certificate) == 0 {
+		return cert, errors.New("no certificates found")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return cert, errors.Wrap(err, "invalid certificate")
+	}
+	pub, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(s.Public()) {
+		return cert, errors.New("certificate doesn't match the signer's public key")
+	}
+	cert.Leaf = leaf
+	return cert, nil
+}
@@ -3,6 +3,7 @@ package tred
 import (
 	"crypto/rand"
 	"io"
+	"runtime"
 
 	"go.bryk.io/pkg/errors"
 )
@@ -16,20 +17,47 @@ type Config struct {
 	// Cipher code
 	Cipher byte
 
+	// Maximum payload size per packet, in bytes. Only used on version 2.0 of the
+	// protocol; must be between 1 KB and 16 MB. Defaults to 64 KB.
+	PayloadSize int
+
 	// Secure cryptographic key to use
 	Key []byte
 
+	// Optional context information (e.g., file name, object ID, tenant) bound
+	// to every packet as additional authenticated data. The exact same value
+	// must be provided when decrypting, otherwise packet validation will fail.
+	// This prevents ciphertext from being moved between different objects
+	// without detection.
+	AssociatedData []byte
+
+	// Append the manifest block to the output produced using version 1.0 of the
+	// protocol, and require it when decrypting version 1.0 content. Without the
+	// manifest, truncated content (i.e., missing trailing packets) can't be
+	// detected and 'Verify' can't be used. Version 1.0 content including the
+	// manifest can't be decrypted by versions of this package prior to its
+	// introduction. The manifest is always used on version 2.0.
+	Manifest bool
+
+	// Maximum number of packets to process concurrently. If not provided
+	// packets will be processed sequentially.
+	Workers int
+
 	// Internals
 	rng   io.Reader
 	nonce [8]byte
 }
 
 // DefaultConfig generates sane default configuration parameters using the provided key value.
+// Version 1.0 of the protocol is used by default, so the produced content can be decrypted by
+// previous versions of the package; set 'Version' to 'Version20' to use version 2.0.
 func DefaultConfig(k []byte) (*Config, error) {
 	c := &Config{
-		Version: Version10,
-		Cipher:  AES,
-		Key:     k,
+		Version:     Version10,
+		Cipher:      AES,
+		Key:         k,
+		PayloadSize: payloadSize,
+		Workers:     runtime.NumCPU(),
 	}
 	return c, c.init()
 }
@@ -42,9 +70,19 @@ func (c *Config) Validate() error {
 	}
 
 	// Version
-	if c.Version != Version10 {
+	if c.Version != Version10 && c.Version != Version20 {
 		return errors.New(ErrUnsupportedVersion)
 	}
+	if c.Version == Version10 && c.Cipher == XCHACHA20 {
+		return errors.New(ErrUnsupportedCipher)
+	}
+
+	// Payload size
+	if c.Version == Version20 && c.PayloadSize != 0 {
+		if c.PayloadSize < minPayloadSize || c.PayloadSize > maxPayloadSize {
+			return errors.New(ErrInvalidPayloadLen)
+		}
+	}
 
 	// No key if provided
 	if len(c.Key) == 0 {
@@ -53,6 +91,27 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// Maximum payload size per packet.
+func (c *Config) payloadSize() int {
+	if c.PayloadSize == 0 {
+		return payloadSize
+	}
+	return c.PayloadSize
+}
+
+// Whether the manifest block is used for the provided protocol version.
+func (c *Config) manifest(version byte) bool {
+	return c.Manifest || version == Version20
+}
+
+// Number of packets to process concurrently.
+func (c *Config) workers() int {
+	if c.Workers < 1 {
+		return 1
+	}
+	return c.Workers
+}
+
 // Initialize internal configuration elements.
 func (c *Config) init() error {
 	c.rng = rand.Reader
@@ -15,15 +15,135 @@ packet is properly tagged and processed using an authentication encryption ciphe
 output prevent manipulation (tamper attempts) of the produced cipher text.
 
 	// output:
-	packet[...]
+	stream header (v2 only) | packet[...] | manifest (optional on v1)
+
+	// stream header:
+	// salt is a random value used to derive a unique key for each stream
+	version (1) | cipher (1) | payload size (4) | salt (32)
 
 	// packet:
 	// tag is calculated and validated by the AEAD cipher
-	header (16) | payload (1 byte - 64 KB) | tag (16)
+	header (16) | payload (1 byte - 64 KB on v1, up to 16 MB on v2) | tag (16)
 
-	// header:
+	// header (v1):
 	version (1) | cipher (1) | payload length (2) | seq (4) | nonce (8)
 
+	// header (v2):
+	payload length (4) | seq (4) | nonce (8)
+
+	// manifest:
+	// checksum is an HMAC of all packets in the output
+	version (1) | cipher (1) | packets count (4) | checksum (32)
+
+# Protocol Versions
+
+Version 1.0 of the protocol is used by default. Version 2.0 supports larger
+(configurable) payload sizes, derives a unique key for each stream using a random
+salt, and allows the use of additional cipher suites (like XChaCha20-Poly1305).
+The cipher suite and payload size used are determined by the content itself when
+decrypting, and content produced using version 1.0 of the protocol can still be
+decrypted by any worker. Version 2.0 must be enabled explicitly; content produced
+with it can't be decrypted by versions of this package prior to its introduction.
+
+	conf, _ := DefaultConfig([]byte("super-secret-key"))
+	conf.Version = Version20
+	conf.Cipher = XCHACHA20
+	conf.PayloadSize = 1024 * 1024
+
+# Integrity Verification
+
+The manifest block at the end of the output allows to detect truncated, missing or
+corrupted packets. The manifest is automatically validated when decrypting content,
+and it's required for content produced using version 2.0 of the protocol. The
+worker's 'Verify' method can also be used to audit stored content without
+decrypting it.
+
+On version 1.0 the manifest is optional, to preserve the original output format;
+content including the manifest can't be decrypted by versions of this package
+prior to its introduction. Set the 'Manifest' configuration flag to include the
+manifest in the output and to require it when decrypting; otherwise the removal
+of trailing packets (along with the manifest) can't be detected.
+
+	conf.Version = Version10
+	conf.Manifest = true
+
+	if _, err := w.Verify(storedContent); err != nil {
+		panic("content is corrupted")
+	}
+
+# Concurrency
+
+Packets are independent of each other and can be processed concurrently. The
+'Workers' setting controls the maximum number of packets processed in parallel,
+the ordering of the produced output is always preserved. 'DefaultConfig' will
+use the number of available CPUs, setting it to '1' results in packets being
+processed sequentially.
+
+# Key Rotation
+
+Existing content can be re-encrypted using a different key and/or cipher suite
+with the 'Rekey' function. The operation is performed in a single pass without
+buffering the plaintext content.
+
+	oldConf, _ := DefaultConfig(oldKey)
+	newConf, _ := DefaultConfig(newKey)
+	res, err := Rekey(oldConf, newConf, input, output)
+
+# Files
+
+The worker's 'EncryptFile' and 'DecryptFile' helpers simplify the handling of
+files. Outputs are written atomically, and the original file permissions and
+modification time are preserved using a small encrypted metadata header. The
+original file name can also be optionally stored. The metadata header is bound to
+the content it describes and uses the same cipher suite.
+
+	// Encrypt file using a random name
+	_, err := w.EncryptFile("report.pdf", "a9f3c2.bin", WithFileName())
+
+	// Restore the original file on the 'docs' directory
+	_, err = w.DecryptFile("a9f3c2.bin", "docs")
+
+# Recipients
+
+Content can also be encrypted for one or more X25519 public keys using 'EncryptFor'.
+A random data key is used to encrypt the content and then wrapped independently for
+each recipient; any of them can decrypt the content using its private key, without
+the need to pre-share symmetric key material.
+
+	secure := bytes.NewBuffer(nil)
+	_, err := EncryptFor(conf, [][32]byte{alice.PublicKey(), bob.PublicKey()}, content, secure)
+
+	// Decrypt using bob's private key
+	_, err = DecryptWith(conf, bob, secure, output)
+
+# Progress
+
+Long-running operations can report their progress to an observer function. When
+the expected size of the input is known an estimated time to completion is also
+provided. Returning an error from the observer aborts the operation.
+
+	deadline := time.Now().Add(5 * time.Minute)
+	_, err := w.Encrypt(input, output,
+		WithTotalSize(size),
+		WithProgress(func(p Progress) error {
+			fmt.Printf("%d/%d bytes processed, ETA: %s\n", p.Bytes, p.Total, p.ETA)
+			if time.Now().After(deadline) {
+				return errors.New("timeout")
+			}
+			return nil
+		}))
+
+# Associated Data
+
+Optionally, external context information (like a file name, object ID or tenant
+identifier) can be bound to the produced output using the 'AssociatedData' setting.
+The value is authenticated as part of every packet but not included in the output,
+the exact same value must be provided when decrypting the data. This ensures that
+ciphertext moved between different objects will fail authentication.
+
+	conf, _ := DefaultConfig([]byte("super-secret-key"))
+	conf.AssociatedData = []byte("tenant-a/object-id")
+
 # Usage
 
 To facilitate the integration of the protocol with higher level components and primitives this
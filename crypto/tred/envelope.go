@@ -0,0 +1,185 @@
+package tred
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"go.bryk.io/pkg/crypto/x25519"
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+// Magic value used to identify content produced by 'EncryptFor'.
+var envelopeMagic = []byte("TRDE")
+
+const (
+	// HKDF info value used to derive recipient key wrapping keys.
+	envelopeKeyInfo = "tred-envelope"
+
+	// Each recipient stanza is a 80 bytes long array.
+	//	ephemeral public key (32) | wrapped data key (32) | tag (16)
+	stanzaSize = 32 + keySize + tagSize
+
+	// Maximum number of recipients supported on an envelope.
+	maxRecipients = 255
+)
+
+// EncryptFor will secure the 'input' content for one or more X25519 recipients
+// and send it to 'output'. A random data key is used to encrypt the content, the
+// data key is then wrapped independently for each recipient public key and
+// included in an envelope header. Any of the recipients can later decrypt the
+// content using 'DecryptWith' and its private key; without the need to pre-share
+// any symmetric key material.
+//
+// The provided 'conf' is used as a template to adjust the protocol settings
+// (version, cipher, associated data, etc.), its 'Key' value is ignored.
+func EncryptFor(conf *Config, recipients [][32]byte, input io.Reader, output io.Writer) (*Result, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New(ErrNoRecipients)
+	}
+	if len(recipients) > maxRecipients {
+		return nil, errors.Errorf("too many recipients, maximum is %d", maxRecipients)
+	}
+
+	// Generate random data key
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, errors.Wrap(err, "failed to generate data key")
+	}
+
+	// Build envelope header
+	//	magic (4) | recipients count (1) | stanza[...]
+	env := bytes.NewBuffer(nil)
+	env.Write(envelopeMagic)
+	env.WriteByte(byte(len(recipients)))
+	for _, rcp := range recipients {
+		stanza, err := wrapKey(rcp, dataKey)
+		if err != nil {
+			return nil, err
+		}
+		env.Write(stanza)
+	}
+	if _, err := output.Write(env.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// Encrypt content
+	w, err := NewWorker(envelopeConfig(conf, dataKey, env.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	return w.Encrypt(input, output)
+}
+
+// DecryptWith will open the secure 'input' content, previously produced using
+// 'EncryptFor', with the private key of one of its recipients and send the
+// results to 'output'. The provided 'conf' is used as a template to adjust the
+// protocol settings (associated data, workers, etc.), its 'Key' value is ignored.
+func DecryptWith(conf *Config, identity *x25519.KeyPair, input io.Reader, output io.Writer) (*Result, error) {
+	// Read envelope header
+	prefix := make([]byte, len(envelopeMagic)+1)
+	if _, err := io.ReadFull(input, prefix); err != nil {
+		return nil, errors.New(ErrInvalidEnvelope)
+	}
+	if !bytes.Equal(prefix[:len(envelopeMagic)], envelopeMagic) || prefix[len(envelopeMagic)] == 0 {
+		return nil, errors.New(ErrInvalidEnvelope)
+	}
+	stanzas := make([]byte, int(prefix[len(envelopeMagic)])*stanzaSize)
+	if _, err := io.ReadFull(input, stanzas); err != nil {
+		return nil, errors.New(ErrInvalidEnvelope)
+	}
+
+	// Locate a stanza for the provided identity
+	var dataKey []byte
+	for i := 0; i < len(stanzas); i += stanzaSize {
+		if dataKey = unwrapKey(identity, stanzas[i:i+stanzaSize]); dataKey != nil {
+			break
+		}
+	}
+	if dataKey == nil {
+		return nil, errors.New(ErrNotRecipient)
+	}
+
+	// Decrypt content
+	w, err := NewWorker(envelopeConfig(conf, dataKey, append(prefix, stanzas...)))
+	if err != nil {
+		return nil, err
+	}
+	return w.Decrypt(input, output)
+}
+
+// Prepare a worker configuration for an envelope. The envelope header is bound
+// to the content as additional authenticated data, along with any associated
+// data originally provided.
+func envelopeConfig(tpl *Config, key []byte, header []byte) *Config {
+	if tpl == nil {
+		tpl, _ = DefaultConfig(nil)
+	}
+	conf := *tpl
+	conf.Key = key
+	conf.AssociatedData = append(append([]byte{}, header...), tpl.AssociatedData...)
+	return &conf
+}
+
+// Wrap the data key for the recipient public key 'rcp' using an ephemeral
+// X25519 key.
+//
+//	ephemeral public key (32) | wrapped data key (32) | tag (16)
+func wrapKey(rcp [32]byte, dataKey []byte) ([]byte, error) {
+	ephemeral := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeral); err != nil {
+		return nil, errors.Wrap(err, "failed to generate ephemeral key")
+	}
+	ephPub, err := curve25519.X25519(ephemeral, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := curve25519.X25519(ephemeral, rcp[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid recipient key")
+	}
+	aead, err := stanzaCipher(shared, ephPub, rcp[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// Each wrapping key is used only once, a zero nonce is safe
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ephPub, nonce, dataKey, nil), nil
+}
+
+// Attempt to recover the data key from a recipient stanza using the provided
+// identity. Returns nil if the stanza was not produced for the identity.
+func unwrapKey(identity *x25519.KeyPair, stanza []byte) []byte {
+	pub := identity.PublicKey()
+	var ephPub [32]byte
+	copy(ephPub[:], stanza[:32])
+	shared := identity.DH(ephPub)
+	if shared == nil {
+		return nil
+	}
+	aead, err := stanzaCipher(shared, ephPub[:], pub[:])
+	if err != nil {
+		return nil
+	}
+	nonce := make([]byte, aead.NonceSize())
+	dataKey, err := aead.Open(nil, nonce, stanza[32:], nil)
+	if err != nil {
+		return nil
+	}
+	return dataKey
+}
+
+// Cipher used to wrap the data key for a specific recipient. The wrapping key
+// is derived from the shared secret and both public keys.
+func stanzaCipher(shared, ephPub, rcpPub []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte{}, ephPub...), rcpPub...)
+	k, err := deriveKey(shared, salt, []byte(envelopeKeyInfo))
+	if err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(k)
+}
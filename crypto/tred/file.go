@@ -0,0 +1,326 @@
+package tred
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Magic value used to identify files produced by 'EncryptFile'.
+var fileMagic = []byte("TRED")
+
+// HKDF info value used to derive the file metadata encryption key.
+const fileKeyInfo = "tred-file-metadata"
+
+// File metadata preserved when encrypting a file.
+//
+//	mode (4) | mtime (8) | name (variable)
+type fileMetadata struct {
+	mode  os.FileMode
+	mtime time.Time
+	name  string
+}
+
+func (fm *fileMetadata) encode() []byte {
+	b := make([]byte, 12+len(fm.name))
+	binary.LittleEndian.PutUint32(b[0:4], uint32(fm.mode.Perm()))
+	binary.LittleEndian.PutUint64(b[4:12], uint64(fm.mtime.UnixNano()))
+	copy(b[12:], fm.name)
+	return b
+}
+
+func (fm *fileMetadata) decode(b []byte) error {
+	if len(b) < 12 {
+		return errors.New(ErrInvalidFile)
+	}
+	fm.mode = os.FileMode(binary.LittleEndian.Uint32(b[0:4])).Perm()
+	fm.mtime = time.Unix(0, int64(binary.LittleEndian.Uint64(b[4:12])))
+	fm.name = string(b[12:])
+	return nil
+}
+
+// EncryptFile will secure the contents of the file at 'src' and store the results
+// at 'dst'. The output is written to a temporary file that atomically replaces
+// 'dst' once the operation completes successfully. A small encrypted metadata
+// header is included in the output to preserve the original file permissions and
+// modification time.
+func (w *Worker) EncryptFile(src, dst string, opts ...Option) (*Result, error) {
+	op := newSettings(opts)
+
+	// Open source file
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.Errorf("%s is not a regular file", src)
+	}
+
+	// Build metadata header
+	md := &fileMetadata{
+		mode:  info.Mode().Perm(),
+		mtime: info.ModTime(),
+	}
+	if op.storeName {
+		md.name = filepath.Base(src)
+	}
+
+	// Produce output; the metadata header is bound to the stream header
+	// and written before it
+	var res *Result
+	err = atomicWrite(dst, md.mode, func(out io.Writer) error {
+		bw := &boundWriter{
+			out:  out,
+			size: bindingSize(w.conf.Version),
+			header: func(binding []byte) ([]byte, error) {
+				return w.sealMetadata(md, binding)
+			},
+		}
+		if res, err = w.Encrypt(in, bw, append(opts, withDefaultSize(info.Size()))...); err != nil {
+			return err
+		}
+		return bw.flush()
+	})
+	return res, err
+}
+
+// DecryptFile will restore the original contents of the file at 'src', previously
+// secured using 'EncryptFile', and store the results at 'dst'. The original file
+// permissions and modification time are preserved. If the original file name was
+// stored when encrypting the file, 'dst' can point to an existing directory; in
+// which case the original file name will be used. The output is written to a
+// temporary file that atomically replaces 'dst' once the operation completes
+// successfully.
+func (w *Worker) DecryptFile(src, dst string, opts ...Option) (*Result, error) {
+	// Open source file
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read metadata header
+	md, input, err := w.openMetadata(in)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		if md.name == "" {
+			return nil, errors.New(ErrNoFileName)
+		}
+		dst = filepath.Join(dst, filepath.Base(md.name))
+	}
+
+	// Produce output
+	var res *Result
+	err = atomicWrite(dst, md.mode, func(out io.Writer) error {
+		res, err = w.Decrypt(input, out, append(opts, withDefaultSize(info.Size()))...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, os.Chtimes(dst, md.mtime, md.mtime)
+}
+
+// Set the expected input size, if not already provided.
+func withDefaultSize(size int64) Option {
+	return func(op *opSettings) {
+		if op.total == 0 {
+			op.total = size
+		}
+	}
+}
+
+// Build an encrypted metadata header. The header is authenticated along
+// with the first bytes of the secure stream ('binding'), which include the
+// random per-stream salt on version 2.0; this prevents moving a metadata
+// header between files.
+//
+//	magic (4) | cipher (1) | length (2) | nonce | sealed metadata
+func (w *Worker) sealMetadata(md *fileMetadata, binding []byte) ([]byte, error) {
+	c, err := w.metadataCipher(w.conf.Cipher)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, c.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, errors.New(ErrRandomNonce)
+	}
+	b := make([]byte, len(fileMagic)+3, len(fileMagic)+3+len(nonce)+len(md.name)+12+c.Overhead())
+	copy(b, fileMagic)
+	b[len(fileMagic)] = w.conf.Cipher
+	sealed := c.Seal(nonce, nonce, md.encode(), metadataAD(b[:len(fileMagic)+1], binding))
+	if len(sealed) > 0xffff {
+		return nil, errors.New(ErrInvalidFile)
+	}
+	binary.LittleEndian.PutUint16(b[len(fileMagic)+1:], uint16(len(sealed)))
+	return append(b, sealed...), nil
+}
+
+// Read and decrypt a metadata header from the provided input. The returned
+// reader must be used to consume the rest of the input.
+func (w *Worker) openMetadata(input io.Reader) (*fileMetadata, io.Reader, error) {
+	prefix := make([]byte, len(fileMagic)+3)
+	if _, err := io.ReadFull(input, prefix); err != nil {
+		return nil, input, errors.New(ErrInvalidFile)
+	}
+	if string(prefix[:len(fileMagic)]) != string(fileMagic) {
+		return nil, input, errors.New(ErrInvalidFile)
+	}
+	c, err := w.metadataCipher(prefix[len(fileMagic)])
+	if err != nil {
+		return nil, input, err
+	}
+	sealed := make([]byte, binary.LittleEndian.Uint16(prefix[len(fileMagic)+1:]))
+	if _, err = io.ReadFull(input, sealed); err != nil || len(sealed) < c.NonceSize() {
+		return nil, input, errors.New(ErrInvalidFile)
+	}
+
+	// Read the beginning of the secure stream
+	var binding []byte
+	version := make([]byte, 1)
+	if _, err = io.ReadFull(input, version); err == nil {
+		binding = make([]byte, bindingSize(version[0]))
+		binding[0] = version[0]
+		n, _ := io.ReadFull(input, binding[1:])
+		binding = binding[:1+n]
+	}
+	rest := io.MultiReader(bytes.NewReader(binding), input)
+
+	// Decrypt metadata
+	ns := c.NonceSize()
+	plain, err := c.Open(nil, sealed[:ns], sealed[ns:], metadataAD(prefix[:len(fileMagic)+1], binding))
+	if err != nil {
+		return nil, rest, errors.New(ErrInvalidFile)
+	}
+	md := new(fileMetadata)
+	return md, rest, md.decode(plain)
+}
+
+// Cipher instance used to protect file metadata headers.
+func (w *Worker) metadataCipher(suite byte) (cipher.AEAD, error) {
+	newCipher, ok := supportedCiphers[suite]
+	if !ok {
+		return nil, errors.New(ErrUnsupportedCipher)
+	}
+	k, err := deriveKey(w.conf.Key, nil, []byte(fileKeyInfo))
+	if err != nil {
+		return nil, err
+	}
+	return newCipher(k)
+}
+
+// Additional data used to authenticate a metadata header.
+//
+//	magic (4) | cipher (1) | stream binding
+func metadataAD(prefix, binding []byte) []byte {
+	ad := make([]byte, 0, len(prefix)+len(binding))
+	return append(append(ad, prefix...), binding...)
+}
+
+// Number of bytes at the beginning of the secure stream bound to the
+// metadata header; the stream header on version 2.0 and the first packet
+// header on version 1.0.
+func bindingSize(version byte) int {
+	if version == Version20 {
+		return streamHeaderSize
+	}
+	return headerSize
+}
+
+// Writer holding the first 'size' bytes written until the metadata header,
+// bound to those bytes, is produced and written to 'out'.
+type boundWriter struct {
+	out    io.Writer
+	size   int
+	buf    []byte
+	done   bool
+	header func(binding []byte) ([]byte, error)
+}
+
+func (bw *boundWriter) Write(p []byte) (int, error) {
+	if bw.done {
+		return bw.out.Write(p)
+	}
+	n := min(bw.size-len(bw.buf), len(p))
+	bw.buf = append(bw.buf, p[:n]...)
+	if len(bw.buf) < bw.size {
+		return len(p), nil
+	}
+	if err := bw.flush(); err != nil {
+		return 0, err
+	}
+	if _, err := bw.out.Write(p[n:]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Write the metadata header and any pending content; only the first call
+// has any effect.
+func (bw *boundWriter) flush() error {
+	if bw.done {
+		return nil
+	}
+	bw.done = true
+	h, err := bw.header(bw.buf)
+	if err != nil {
+		return err
+	}
+	if _, err = bw.out.Write(h); err != nil {
+		return err
+	}
+	_, err = bw.out.Write(bw.buf)
+	return err
+}
+
+// Atomically write the contents produced by 'fn' to the file at 'dst'. Contents
+// are written to a temporary file (on the same directory) that is renamed to
+// 'dst' only after 'fn' completes successfully.
+func atomicWrite(dst string, perm os.FileMode, fn func(out io.Writer) error) (err error) {
+	dst = filepath.Clean(dst)
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if err = fn(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -8,7 +8,11 @@ type headerBlock []byte
 
 // Retrieve the header section from a byte array.
 //
+//	// version 1.0
 //	version (1) | cipher (1) | payload length (2) | seq (4) | nonce (8)
+//
+//	// version 2.0
+//	payload length (4) | seq (4) | nonce (8)
 func header(b []byte) headerBlock {
 	return b[:headerSize]
 }
@@ -28,6 +32,11 @@ func (h headerBlock) Len() int {
 	return int(binary.LittleEndian.Uint16(h[2:])) + 1
 }
 
+// LongLen return package's payload length on version 2.0.
+func (h headerBlock) LongLen() int {
+	return int(binary.LittleEndian.Uint32(h[0:4])) + 1
+}
+
 // SequenceNumber return package's seq number.
 func (h headerBlock) SequenceNumber() uint32 {
 	return binary.LittleEndian.Uint32(h[4:])
@@ -48,6 +57,11 @@ func (h headerBlock) SetLen(length int) {
 	binary.LittleEndian.PutUint16(h[2:], uint16(length-1))
 }
 
+// SetLongLen adjust the package's payload length on version 2.0.
+func (h headerBlock) SetLongLen(length int) {
+	binary.LittleEndian.PutUint32(h[0:4], uint32(length-1))
+}
+
 // SetSequenceNumber adjust the package's seq number.
 func (h headerBlock) SetSequenceNumber(num uint32) {
 	binary.LittleEndian.PutUint32(h[4:], num)
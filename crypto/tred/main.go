@@ -17,18 +17,28 @@ const (
 	// Version10 provides the protocol version tag for 1.0.
 	Version10 = 0x10
 
+	// Version20 provides the protocol version tag for 2.0.
+	Version20 = 0x20
+
 	// AES in GCM mode cipher code.
 	AES = 0x00
 
 	// CHACHA20 cipher code.
 	CHACHA20 = 0x01
 
+	// XCHACHA20 cipher code. Only available on version 2.0 of the protocol.
+	XCHACHA20 = 0x02
+
 	// Encryption keys must be 32 bytes long to properly use ciphers in 256 bits mode.
 	keySize = 32
 
-	// Maximum payload size is 64kb.
+	// Maximum payload size is 64kb (default value for version 2.0).
 	payloadSize = 64 * 1024
 
+	// Payload size limits for version 2.0.
+	minPayloadSize = 1024
+	maxPayloadSize = 16 * 1024 * 1024
+
 	// Package header is a 16 long byte array.
 	// 	version (1) | cipher (1) | payload length (2) | seq (4) | nonce (8)
 	// 	seq is packages counter that prevents rearrange
@@ -40,6 +50,16 @@ const (
 
 	// Encrypted packet size.
 	packetSize = headerSize + payloadSize + tagSize
+
+	// Stream header used on version 2.0.
+	// 	version (1) | cipher (1) | payload size (4) | salt (32)
+	streamHeaderSize = 38
+
+	// HKDF info value used to derive the manifest authentication key.
+	manifestKeyInfo = "tred-manifest"
+
+	// HKDF info value used to derive per-stream keys on version 2.0.
+	streamKeyInfo = "tred-v2-stream"
 )
 
 // Common error values.
@@ -51,11 +71,19 @@ var (
 	ErrUnsupportedVersion    = "unsupported version code"
 	ErrNoKey                 = "value for key is required"
 	ErrRandomNonce           = "failed to read random nonce"
+	ErrNoManifest            = "missing manifest"
+	ErrInvalidManifest       = "invalid manifest"
+	ErrInvalidFile           = "invalid file header"
+	ErrNoFileName            = "original file name not available"
+	ErrNoRecipients          = "at least one recipient is required"
+	ErrNotRecipient          = "no matching recipient found"
+	ErrInvalidEnvelope       = "invalid envelope header"
 )
 
 // Supported cipher suites.
 var supportedCiphers = map[byte]func(key []byte) (cipher.AEAD, error){
-	CHACHA20: chacha20poly1305.New,
+	CHACHA20:  chacha20poly1305.New,
+	XCHACHA20: chacha20poly1305.NewX,
 	AES: func(key []byte) (cipher.AEAD, error) {
 		aes256, err := aes.NewCipher(key)
 		if err != nil {
@@ -79,6 +107,7 @@ type Worker struct {
 	conf  *Config
 	seq   uint32
 	mutex sync.Mutex
+	op    *opSettings
 }
 
 // NewWorker returns a usable protocol worker instance.
@@ -100,46 +129,45 @@ func NewWorker(c *Config) (*Worker, error) {
 }
 
 // Encrypt will secure the 'input' content and send it to 'output'.
-func (w *Worker) Encrypt(input io.Reader, output io.Writer) (*Result, error) {
-	// Get cipher
-	c, err := supportedCiphers[w.conf.Cipher](w.conf.Key[:])
-	if err != nil {
-		return nil, err
-	}
-
+func (w *Worker) Encrypt(input io.Reader, output io.Writer, opts ...Option) (*Result, error) {
 	// Lock internal state
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
 	// Reset worker
-	payload := make([]byte, payloadSize)
 	w.seq = 0
+	w.op = newSettings(opts)
 	start := time.Now()
 
-	// Process input
-	for {
-		n, err := input.Read(payload)
-		if err != nil && !errors.Is(err, io.EOF) {
+	// Start a new session
+	ss, err := w.newSession()
+	if err != nil {
+		return nil, err
+	}
+	digest := ss.digest()
+	if len(ss.prefix) > 0 {
+		if _, err = output.Write(ss.prefix); err != nil {
 			return nil, err
 		}
-		if n > 0 {
-			// Encrypt payload
-			// Use 'seq | nonce' as operation nonce
-			// Use 'version | cipher | payload length' as additional data
-			h := w.buildHeader(n)
-			ciphertext := c.Seal(nil, h[4:headerSize], payload, h[:4])
-
-			// Build package
-			packet := make([]byte, headerSize+len(ciphertext))
-			copy(packet[:headerSize], h)
-			copy(packet[headerSize:], ciphertext)
-			if _, err := output.Write(packet); err != nil {
-				return nil, err
-			}
-			w.seq++
-		}
-		if errors.Is(err, io.EOF) {
-			break
+		digest.Write(ss.prefix)
+	}
+
+	// Process input
+	_, err = w.process(input, output, stream{
+		size: ss.payload,
+		op:   ss.seal,
+		observe: func(_, packet []byte) {
+			digest.Write(packet)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Add manifest
+	if w.conf.manifest(ss.version) {
+		if _, err = output.Write(w.buildManifest(digest.Sum(nil))); err != nil {
+			return nil, err
 		}
 	}
 
@@ -151,78 +179,223 @@ func (w *Worker) Encrypt(input io.Reader, output io.Writer) (*Result, error) {
 }
 
 // Decrypt will open the secure 'input' content and send it to 'output'.
-func (w *Worker) Decrypt(input io.Reader, output io.Writer) (*Result, error) {
-	c, err := supportedCiphers[w.conf.Cipher](w.conf.Key[:])
+// Content produced using any supported version of the protocol can be
+// decrypted, the cipher suite used is determined by the content itself.
+func (w *Worker) Decrypt(input io.Reader, output io.Writer, opts ...Option) (*Result, error) {
+	// Lock internal state
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// Reset worker
+	w.seq = 0
+	w.op = newSettings(opts)
+	start := time.Now()
+
+	// Restore session
+	ss, input, err := w.openSession(input)
+	if err != nil {
+		return nil, err
+	}
+	if ss == nil {
+		return &Result{Duration: time.Since(start)}, nil
+	}
+	digest := ss.digest()
+	digest.Write(ss.prefix)
+
+	// Process input
+	m, err := w.process(input, output, stream{
+		size:    ss.packetSize(),
+		op:      ss.open,
+		trailer: manifestSize,
+		observe: func(packet, _ []byte) {
+			digest.Write(packet)
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Validate manifest; content produced using version 1.0 of the
+	// protocol may not include it, unless required by the configuration.
+	// On version 2.0 the manifest is always required to detect truncation.
+	if m == nil && w.conf.manifest(ss.version) {
+		return nil, errors.New(ErrNoManifest)
+	}
+	if m != nil {
+		if err = w.checkManifest(ss, m, digest.Sum(nil)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return final result
+	return &Result{
+		Packets:  w.seq,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// Verify will validate the integrity of the secure 'input' content without
+// producing any plaintext output. The packets sequence and the checksum value
+// included in the output manifest are validated; the individual packets are
+// not decrypted. This allows to perform cheap integrity audits of stored
+// content. On success, the manifest block included in the output is returned.
+func (w *Worker) Verify(input io.Reader, opts ...Option) (Manifest, error) {
 	// Lock internal state
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
 	// Reset worker
-	packet := make([]byte, packetSize)
 	w.seq = 0
-	start := time.Now()
+	w.op = newSettings(opts)
+
+	// Restore session
+	ss, input, err := w.openSession(input)
+	if err != nil {
+		return nil, err
+	}
+	if ss == nil {
+		return nil, errors.New(ErrNoManifest)
+	}
+	digest := ss.digest()
+	digest.Write(ss.prefix)
 
 	// Process input
-	for {
-		n, err := input.Read(packet)
-		if err != nil && !errors.Is(err, io.EOF) {
-			return nil, err
+	validate := func(seq uint32, packet []byte) ([]byte, error) {
+		if len(packet) < headerSize+tagSize {
+			return nil, errors.New(ErrInvalidPayloadLen)
 		}
-		if n > 0 {
-			// Validate packet sequence
-			h := header(packet)
-			if h.SequenceNumber() != w.seq {
-				return nil, errors.New(ErrInvalidSequenceNumber)
-			}
+		if header(packet).SequenceNumber() != seq {
+			return nil, errors.New(ErrInvalidSequenceNumber)
+		}
+		return nil, nil
+	}
+	m, err := w.process(input, io.Discard, stream{
+		size:    ss.packetSize(),
+		op:      validate,
+		trailer: manifestSize,
+		observe: func(packet, _ []byte) {
+			digest.Write(packet)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, errors.New(ErrNoManifest)
+	}
+	if err = w.checkManifest(ss, m, digest.Sum(nil)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Transformation applied to each individual chunk of data processed
+// by a worker; 'seq' is the position of the chunk on the stream.
+type packetOp func(seq uint32, chunk []byte) ([]byte, error)
+
+// Parameters for a stream processing operation.
+type stream struct {
+	// Size of the chunks read from the input
+	size int
 
-			// Decrypt and validate packet ciphertext
-			ciphertext := packet[headerSize:]
-			payload, err := c.Open(nil, h[4:headerSize], ciphertext, h[:4])
-			if err != nil {
-				return nil, errors.New(ErrInvalidPacketTag)
+	// Transformation applied to each chunk
+	op packetOp
+
+	// Optional function invoked in order for each chunk processed
+	// along with its corresponding result
+	observe func(chunk, result []byte)
+
+	// Size of an optional trailing block expected at the end of the input
+	trailer int
+}
+
+// Read 'input' in chunks of up to 's.size' bytes, transform each one of them
+// using the provided 's.op' and send the results to 'output'. Chunks are
+// processed concurrently in batches of up to 'conf.Workers' elements, the
+// original ordering is always preserved on the produced output. The worker
+// 'seq' counter is adjusted to the number of chunks processed and, if set, the
+// progress observer is notified after each batch; the operation is aborted if
+// the observer returns an error. If the input ends with a
+// trailing block it's not processed and returned to the caller instead.
+func (w *Worker) process(input io.Reader, output io.Writer, s stream) ([]byte, error) {
+	var (
+		trailer   []byte
+		processed int64
+	)
+	start := time.Now()
+	workers := w.conf.workers()
+	batch := make([][]byte, workers)
+	results := make([][]byte, workers)
+	errs := make([]error, workers)
+	for {
+		// Read next batch of chunks
+		n, eof := 0, false
+		for n < workers && !eof {
+			chunk := make([]byte, s.size)
+			r, err := io.ReadFull(input, chunk)
+			switch {
+			case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+				eof = true
+			case err != nil:
+				return nil, err
+			}
+			if r > 0 {
+				batch[n] = chunk[:r]
+				n++
 			}
+		}
 
-			// Validate payload length
-			if len(payload) < h.Len() {
-				return nil, errors.New(ErrInvalidPayloadLen)
+		// Detect trailing block
+		if eof && n > 0 && s.trailer > 0 && len(batch[n-1]) == s.trailer {
+			trailer = batch[n-1]
+			n--
+		}
+
+		// Process chunks
+		if n == 1 {
+			results[0], errs[0] = s.op(w.seq, batch[0])
+		} else {
+			wg := sync.WaitGroup{}
+			wg.Add(n)
+			for i := 0; i < n; i++ {
+				go func(i int) {
+					results[i], errs[i] = s.op(w.seq+uint32(i), batch[i])
+					wg.Done()
+				}(i)
 			}
+			wg.Wait()
+		}
 
-			// Add output
-			if _, err := output.Write(payload[:h.Len()]); err != nil {
+		// Send results to output in order
+		for i := 0; i < n; i++ {
+			if errs[i] != nil {
+				return nil, errs[i]
+			}
+			if s.observe != nil {
+				s.observe(batch[i], results[i])
+			}
+			processed += int64(len(batch[i]))
+			if _, err := output.Write(results[i]); err != nil {
 				return nil, err
 			}
 			w.seq++
 		}
-		if errors.Is(err, io.EOF) {
-			break
+
+		// Report progress
+		if w.op != nil && w.op.progress != nil && n > 0 {
+			if err := w.op.progress(w.op.report(w.seq, processed, start)); err != nil {
+				return nil, err
+			}
+		}
+		if eof {
+			return trailer, nil
 		}
 	}
-
-	// Return final result
-	return &Result{
-		Packets:  w.seq,
-		Duration: time.Since(start),
-	}, nil
-}
-
-// Build a valid packet header block.
-func (w *Worker) buildHeader(packetLength int) headerBlock {
-	h := headerBlock(make([]byte, headerSize))
-	h.SetVersion(w.conf.Version)
-	h.SetCipher(w.conf.Cipher)
-	h.SetLen(packetLength)
-	h.SetSequenceNumber(w.seq)
-	h.SetNonce(w.conf.nonce)
-	return h
 }
 
 // Build a valid output manifest block.
-func (w *Worker) buildManifest(digest []byte) manifestBlock {
-	m := manifestBlock(make([]byte, manifestSize))
+func (w *Worker) buildManifest(digest []byte) Manifest {
+	m := Manifest(make([]byte, manifestSize))
 	m.SetVersion(w.conf.Version)
 	m.SetCipher(w.conf.Cipher)
 	m.SetLen(int(w.seq))
@@ -232,15 +405,24 @@ func (w *Worker) buildManifest(digest []byte) manifestBlock {
 
 // Securely expand secret key material.
 func (w *Worker) expandKey(info []byte) error {
-	f := [keySize]byte{}
-	for i := 0; i < len(f); i++ {
-		f[i] = 0xff
+	k, err := deriveKey(w.conf.Key, nil, info)
+	if err != nil {
+		return err
+	}
+	w.conf.Key = k
+	return nil
+}
+
+// Derive a new key value from the provided secret material using HKDF.
+// If no 'salt' is provided a zero-filled value is used.
+func deriveKey(secret, salt, info []byte) ([]byte, error) {
+	if salt == nil {
+		salt = make([]byte, keySize)
 	}
-	h := hkdf.New(sha3.New256, w.conf.Key, make([]byte, keySize), info)
+	h := hkdf.New(sha3.New256, secret, salt, info)
 	buf := make([]byte, keySize)
 	if _, err := io.ReadFull(h, buf); err != nil {
-		return errors.New("failed to read HKDF key")
+		return nil, errors.New("failed to read HKDF key")
 	}
-	w.conf.Key = buf[:keySize]
-	return nil
+	return buf, nil
 }
@@ -3,17 +3,26 @@ package tred
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
+	"errors"
 	mr "math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/x25519"
 	"go.uber.org/goleak"
 )
 
 func TestMain(m *testing.M) {
-	goleak.VerifyTestMain(m)
+	// The method "github.com/awnumar/memguard/core.NewCoffer" currently
+	// leaks a routine used to re-key the global enclave handler.
+	// https://github.com/awnumar/memguard/blob/master/core/coffer.go#L36
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/awnumar/memguard/core.NewCoffer.func1"))
 }
 
 func TestConfig(t *testing.T) {
@@ -84,6 +93,142 @@ func TestManifest(t *testing.T) {
 	assert.Equal(m, m2, "restore error")
 }
 
+func TestVerify(t *testing.T) {
+	assert := tdd.New(t)
+	key := [32]byte{}
+	rand.Read(key[:])
+	conf, _ := DefaultConfig(key[:])
+	conf.Version = Version20
+	w, _ := NewWorker(conf)
+
+	// Encrypt content
+	content := make([]byte, (1024*1024)+100)
+	rand.Read(content)
+	output := bytes.NewBuffer([]byte{})
+	res, err := w.Encrypt(bytes.NewReader(content), output)
+	assert.Nil(err, "encrypt error")
+	ct := output.Bytes()
+
+	// Valid content
+	m, err := w.Verify(bytes.NewReader(ct))
+	assert.Nil(err, "verify error")
+	assert.Equal(int(res.Packets), m.Len(), "invalid packets count")
+	assert.Equal(manifest(ct[len(ct)-manifestSize:]), m, "invalid manifest")
+
+	// Corrupted packet
+	corrupted := make([]byte, len(ct))
+	copy(corrupted, ct)
+	corrupted[packetSize+headerSize+10] ^= 0xff
+	_, err = w.Verify(bytes.NewReader(corrupted))
+	assert.NotNil(err, "verify should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidManifest), "invalid error")
+
+	// Truncated content
+	truncated := make([]byte, 0, len(ct)-packetSize)
+	truncated = append(truncated, ct[:len(ct)-packetSize-manifestSize]...)
+	truncated = append(truncated, ct[len(ct)-manifestSize:]...)
+	_, err = w.Verify(bytes.NewReader(truncated))
+	assert.NotNil(err, "verify should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidManifest), "invalid error")
+	_, err = w.Decrypt(bytes.NewReader(truncated), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidManifest), "invalid error")
+
+	// Missing manifest
+	_, err = w.Verify(bytes.NewReader(ct[:len(ct)-manifestSize]))
+	assert.NotNil(err, "verify should fail")
+	assert.True(strings.Contains(err.Error(), ErrNoManifest), "invalid error")
+
+	// Content without manifest can't be decrypted
+	_, err = w.Decrypt(bytes.NewReader(ct[:len(ct)-manifestSize]), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrNoManifest), "invalid error")
+
+	// Truncated content, including the manifest, can't be decrypted
+	_, err = w.Decrypt(bytes.NewReader(ct[:len(ct)-packetSize-manifestSize]), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrNoManifest), "invalid error")
+}
+
+func TestFile(t *testing.T) {
+	assert := tdd.New(t)
+	key := [32]byte{}
+	rand.Read(key[:])
+	conf, _ := DefaultConfig(key[:])
+	conf.Version = Version20
+	w, _ := NewWorker(conf)
+
+	// Original file
+	dir := t.TempDir()
+	content := make([]byte, (1024*512)+7)
+	rand.Read(content)
+	src := filepath.Join(dir, "original.txt")
+	mtime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	assert.Nil(os.WriteFile(src, content, 0640))
+	assert.Nil(os.Chtimes(src, mtime, mtime))
+
+	// Encrypt
+	secure := filepath.Join(dir, "secure.bin")
+	_, err := w.EncryptFile(src, secure, WithFileName())
+	assert.Nil(err, "encrypt error")
+	ct, _ := os.ReadFile(secure)
+	assert.False(bytes.Contains(ct, []byte("original.txt")), "file name not encrypted")
+
+	// Decrypt to a specific path
+	restored := filepath.Join(dir, "restored.txt")
+	_, err = w.DecryptFile(secure, restored)
+	assert.Nil(err, "decrypt error")
+	got, _ := os.ReadFile(restored)
+	assert.Equal(content, got, "bad decrypt result")
+	info, _ := os.Stat(restored)
+	assert.Equal(os.FileMode(0640), info.Mode().Perm(), "permissions not preserved")
+	assert.True(mtime.Equal(info.ModTime()), "mtime not preserved")
+
+	// Decrypt to a directory using the original file name
+	out := filepath.Join(dir, "out")
+	assert.Nil(os.Mkdir(out, 0750))
+	_, err = w.DecryptFile(secure, out)
+	assert.Nil(err, "decrypt error")
+	got, _ = os.ReadFile(filepath.Join(out, "original.txt"))
+	assert.Equal(content, got, "bad decrypt result")
+
+	// Original file name not available
+	secure2 := filepath.Join(dir, "secure2.bin")
+	_, err = w.EncryptFile(src, secure2)
+	assert.Nil(err, "encrypt error")
+	_, err = w.DecryptFile(secure2, out)
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrNoFileName), "invalid error")
+
+	// Cipher is determined by the content
+	conf3, _ := DefaultConfig(key[:])
+	conf3.Cipher = CHACHA20
+	w3, _ := NewWorker(conf3)
+	_, err = w3.DecryptFile(secure, filepath.Join(dir, "restored-chacha.txt"))
+	assert.Nil(err, "decrypt error")
+
+	// Metadata header can't be moved to a different file
+	ct2, _ := os.ReadFile(secure2)
+	mdLen := len(fileMagic) + 3 + int(binary.LittleEndian.Uint16(ct[len(fileMagic)+1:]))
+	mdLen2 := len(fileMagic) + 3 + int(binary.LittleEndian.Uint16(ct2[len(fileMagic)+1:]))
+	swapped := filepath.Join(dir, "swapped.bin")
+	assert.Nil(os.WriteFile(swapped, append(append([]byte{}, ct[:mdLen]...), ct2[mdLen2:]...), 0600))
+	_, err = w.DecryptFile(swapped, filepath.Join(dir, "swapped.txt"))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidFile), "invalid error")
+
+	// Invalid key; no partial output is left behind
+	conf2, _ := DefaultConfig([]byte("invalid-key"))
+	w2, _ := NewWorker(conf2)
+	failed := filepath.Join(dir, "failed.txt")
+	_, err = w2.DecryptFile(secure, failed)
+	assert.NotNil(err, "decrypt should fail")
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		assert.False(strings.Contains(e.Name(), "failed"), "partial output")
+	}
+}
+
 func TestChaCha(t *testing.T) {
 	assert := tdd.New(t)
 	// Get random encryption key
@@ -136,6 +281,351 @@ func TestAES(t *testing.T) {
 	assert.Equal(originalContent, decrypted.Bytes(), "bad decrypt result")
 }
 
+func TestVersions(t *testing.T) {
+	assert := tdd.New(t)
+	key := [32]byte{}
+	rand.Read(key[:])
+	content := make([]byte, (1024*1024*2)+321)
+	rand.Read(content)
+
+	t.Run("Config", func(t *testing.T) {
+		conf, _ := DefaultConfig(key[:])
+		conf.Version = Version10
+		conf.Cipher = XCHACHA20
+		assert.NotNil(conf.Validate(), "xchacha20 is not supported on v1")
+
+		conf.Version = Version20
+		conf.PayloadSize = 100
+		assert.NotNil(conf.Validate(), "invalid payload size")
+		conf.PayloadSize = maxPayloadSize + 1
+		assert.NotNil(conf.Validate(), "invalid payload size")
+		conf.PayloadSize = 1024 * 1024
+		assert.Nil(conf.Validate(), "valid configuration")
+	})
+
+	t.Run("ReadV1", func(t *testing.T) {
+		// Encrypt using v1
+		conf, _ := DefaultConfig(key[:])
+		conf.Version = Version10
+		conf.Cipher = CHACHA20
+		w, _ := NewWorker(conf)
+		output := bytes.NewBuffer([]byte{})
+		_, err := w.Encrypt(bytes.NewReader(content), output)
+		assert.Nil(err, "encrypt error")
+		assert.Equal(byte(Version10), output.Bytes()[0], "invalid version")
+
+		// Decrypt using a v2 worker
+		conf2, _ := DefaultConfig(key[:])
+		conf2.Version = Version20
+		w2, _ := NewWorker(conf2)
+		decrypted := bytes.NewBuffer([]byte{})
+		_, err = w2.Decrypt(bytes.NewReader(output.Bytes()), decrypted)
+		assert.Nil(err, "decrypt error")
+		assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+	})
+
+	t.Run("ManifestV1", func(t *testing.T) {
+		// Version 1.0 output doesn't include the manifest by default
+		conf, _ := DefaultConfig(key[:])
+		conf.Version = Version10
+		w, _ := NewWorker(conf)
+		output := bytes.NewBuffer([]byte{})
+		res, err := w.Encrypt(bytes.NewReader(content), output)
+		assert.Nil(err, "encrypt error")
+		assert.Equal(int(res.Packets)*packetSize, output.Len(), "no manifest expected")
+		_, err = w.Verify(bytes.NewReader(output.Bytes()))
+		assert.NotNil(err, "verify should fail")
+		assert.True(strings.Contains(err.Error(), ErrNoManifest), "invalid error")
+
+		// Manifest can be enabled
+		conf2, _ := DefaultConfig(key[:])
+		conf2.Version = Version10
+		conf2.Manifest = true
+		w2, _ := NewWorker(conf2)
+		output.Reset()
+		res, err = w2.Encrypt(bytes.NewReader(content), output)
+		assert.Nil(err, "encrypt error")
+		ct := output.Bytes()
+		assert.Equal(int(res.Packets)*packetSize+manifestSize, len(ct), "manifest expected")
+		_, err = w2.Verify(bytes.NewReader(ct))
+		assert.Nil(err, "verify error")
+		decrypted := bytes.NewBuffer([]byte{})
+		_, err = w.Decrypt(bytes.NewReader(ct), decrypted)
+		assert.Nil(err, "manifest is validated when available")
+		assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+
+		// Truncated content
+		truncated := ct[:len(ct)-packetSize-manifestSize]
+		_, err = w.Decrypt(bytes.NewReader(truncated), bytes.NewBuffer([]byte{}))
+		assert.Nil(err, "manifest not required")
+		_, err = w2.Decrypt(bytes.NewReader(truncated), bytes.NewBuffer([]byte{}))
+		assert.NotNil(err, "decrypt should fail")
+		assert.True(strings.Contains(err.Error(), ErrNoManifest), "invalid error")
+	})
+
+	t.Run("CipherAgility", func(t *testing.T) {
+		for _, suite := range []byte{AES, CHACHA20, XCHACHA20} {
+			// Encrypt using a large payload size
+			conf, _ := DefaultConfig(key[:])
+			conf.Version = Version20
+			conf.Cipher = suite
+			conf.PayloadSize = 1024 * 1024
+			w, _ := NewWorker(conf)
+			output := bytes.NewBuffer([]byte{})
+			res, err := w.Encrypt(bytes.NewReader(content), output)
+			assert.Nil(err, "encrypt error")
+			assert.Equal(uint32(3), res.Packets, "invalid packets count")
+
+			// Cipher and payload size are determined by the content
+			conf2, _ := DefaultConfig(key[:])
+			w2, _ := NewWorker(conf2)
+			decrypted := bytes.NewBuffer([]byte{})
+			_, err = w2.Decrypt(bytes.NewReader(output.Bytes()), decrypted)
+			assert.Nil(err, "decrypt error")
+			assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+		}
+	})
+
+	t.Run("StreamSalt", func(t *testing.T) {
+		// Same content and key must produce different outputs
+		conf, _ := DefaultConfig(key[:])
+		conf.Version = Version20
+		w, _ := NewWorker(conf)
+		out1 := bytes.NewBuffer([]byte{})
+		out2 := bytes.NewBuffer([]byte{})
+		_, _ = w.Encrypt(bytes.NewReader(content), out1)
+		_, _ = w.Encrypt(bytes.NewReader(content), out2)
+		assert.NotEqual(out1.Bytes()[streamHeaderSize:], out2.Bytes()[streamHeaderSize:], "salt not used")
+
+		// Tampered stream header must be detected
+		tampered := out1.Bytes()
+		tampered[2] ^= 0x01
+		_, err := w.Decrypt(bytes.NewReader(tampered), bytes.NewBuffer([]byte{}))
+		assert.NotNil(err, "decrypt should fail")
+	})
+}
+
+func TestEnvelope(t *testing.T) {
+	assert := tdd.New(t)
+	alice, _ := x25519.New()
+	bob, _ := x25519.New()
+	eve, _ := x25519.New()
+	defer alice.Destroy()
+	defer bob.Destroy()
+	defer eve.Destroy()
+
+	content := make([]byte, (1024*1024)+99)
+	rand.Read(content)
+
+	// Encrypt for multiple recipients
+	conf, _ := DefaultConfig(nil)
+	conf.Cipher = CHACHA20
+	recipients := [][32]byte{alice.PublicKey(), bob.PublicKey()}
+	secure := bytes.NewBuffer([]byte{})
+	_, err := EncryptFor(conf, recipients, bytes.NewReader(content), secure)
+	assert.Nil(err, "encrypt error")
+
+	// Any recipient can decrypt the content
+	for _, id := range []*x25519.KeyPair{alice, bob} {
+		decrypted := bytes.NewBuffer([]byte{})
+		_, err = DecryptWith(nil, id, bytes.NewReader(secure.Bytes()), decrypted)
+		assert.Nil(err, "decrypt error")
+		assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+	}
+
+	// Not a recipient
+	_, err = DecryptWith(nil, eve, bytes.NewReader(secure.Bytes()), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrNotRecipient), "invalid error")
+
+	// Tampered envelope header
+	tampered := make([]byte, secure.Len())
+	copy(tampered, secure.Bytes())
+	tampered[len(envelopeMagic)+1+stanzaSize+5] ^= 0xff // bob's stanza
+	_, err = DecryptWith(nil, alice, bytes.NewReader(tampered), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+
+	// No recipients
+	_, err = EncryptFor(conf, nil, bytes.NewReader(content), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "encrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrNoRecipients), "invalid error")
+}
+
+func TestProgress(t *testing.T) {
+	assert := tdd.New(t)
+	key := [32]byte{}
+	rand.Read(key[:])
+	conf, _ := DefaultConfig(key[:])
+	conf.Workers = 2
+	w, _ := NewWorker(conf)
+	content := make([]byte, 1024*1024)
+	rand.Read(content)
+
+	// Collect progress reports
+	var reports []Progress
+	observer := func(p Progress) error {
+		reports = append(reports, p)
+		return nil
+	}
+	output := bytes.NewBuffer([]byte{})
+	opts := []Option{WithProgress(observer), WithTotalSize(int64(len(content)))}
+	_, err := w.Encrypt(bytes.NewReader(content), output, opts...)
+	assert.Nil(err, "encrypt error")
+	assert.Equal(8, len(reports), "invalid number of reports")
+	assert.Equal(uint32(2), reports[0].Packets, "invalid packets count")
+	assert.Equal(int64(2*payloadSize), reports[0].Bytes, "invalid bytes count")
+	assert.Equal(int64(len(content)), reports[0].Total, "invalid total size")
+	last := reports[len(reports)-1]
+	assert.Equal(int64(len(content)), last.Bytes, "invalid bytes count")
+	assert.Equal(time.Duration(0), last.ETA, "invalid ETA")
+
+	// Abort operation
+	limit := errors.New("operation timeout")
+	_, err = w.Decrypt(bytes.NewReader(output.Bytes()), bytes.NewBuffer([]byte{}), WithProgress(func(p Progress) error {
+		if p.Packets >= 4 {
+			return limit
+		}
+		return nil
+	}))
+	assert.ErrorIs(err, limit, "operation not aborted")
+}
+
+func TestAssociatedData(t *testing.T) {
+	assert := tdd.New(t)
+	key := [32]byte{}
+	rand.Read(key[:])
+	content := make([]byte, 1024*256)
+	rand.Read(content)
+
+	// Encrypt content bound to a specific object
+	conf, _ := DefaultConfig(key[:])
+	conf.AssociatedData = []byte("object-a")
+	w, _ := NewWorker(conf)
+	output := bytes.NewBuffer([]byte{})
+	_, err := w.Encrypt(bytes.NewReader(content), output)
+	assert.Nil(err, "encrypt error")
+
+	// Decrypt using the same context
+	conf2, _ := DefaultConfig(key[:])
+	conf2.AssociatedData = []byte("object-a")
+	w2, _ := NewWorker(conf2)
+	decrypted := bytes.NewBuffer([]byte{})
+	_, err = w2.Decrypt(bytes.NewReader(output.Bytes()), decrypted)
+	assert.Nil(err, "decrypt error")
+	assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+
+	// Decrypt using a different context
+	conf3, _ := DefaultConfig(key[:])
+	conf3.AssociatedData = []byte("object-b")
+	w3, _ := NewWorker(conf3)
+	_, err = w3.Decrypt(bytes.NewReader(output.Bytes()), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidPacketTag), "invalid error")
+
+	// Decrypt without context
+	conf4, _ := DefaultConfig(key[:])
+	w4, _ := NewWorker(conf4)
+	_, err = w4.Decrypt(bytes.NewReader(output.Bytes()), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+}
+
+func TestParallel(t *testing.T) {
+	assert := tdd.New(t)
+	key := [32]byte{}
+	rand.Read(key[:])
+	content := make([]byte, (1024*1024*4)+123)
+	rand.Read(content)
+
+	// Encrypt using multiple workers
+	conf, _ := DefaultConfig(key[:])
+	conf.Version = Version20
+	conf.Workers = 8
+	w, _ := NewWorker(conf)
+	output := bytes.NewBuffer([]byte{})
+	res, err := w.Encrypt(bytes.NewReader(content), output)
+	assert.Nil(err, "encrypt error")
+	assert.Equal(uint32(65), res.Packets, "invalid packets count")
+
+	// Decrypt sequentially
+	conf2, _ := DefaultConfig(key[:])
+	conf2.Workers = 1
+	w2, _ := NewWorker(conf2)
+	decrypted := bytes.NewBuffer([]byte{})
+	_, err = w2.Decrypt(bytes.NewReader(output.Bytes()), decrypted)
+	assert.Nil(err, "decrypt error")
+	assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+
+	// Decrypt using multiple workers
+	decrypted.Reset()
+	_, err = w.Decrypt(bytes.NewReader(output.Bytes()), decrypted)
+	assert.Nil(err, "decrypt error")
+	assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+
+	// Rearranged packets must be rejected
+	ct := output.Bytes()[streamHeaderSize:]
+	tampered := make([]byte, len(ct))
+	copy(tampered, ct)
+	copy(tampered[:packetSize], ct[packetSize:2*packetSize])
+	copy(tampered[packetSize:2*packetSize], ct[:packetSize])
+	tampered = append(output.Bytes()[:streamHeaderSize:streamHeaderSize], tampered...)
+	_, err = w.Decrypt(bytes.NewReader(tampered), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "decrypt should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidSequenceNumber), "invalid error")
+}
+
+func TestRekey(t *testing.T) {
+	assert := tdd.New(t)
+	oldKey := [32]byte{}
+	newKey := [32]byte{}
+	rand.Read(oldKey[:])
+	rand.Read(newKey[:])
+	content := make([]byte, (1024*1024*2)+42)
+	rand.Read(content)
+
+	// Encrypt original content
+	conf, _ := DefaultConfig(oldKey[:])
+	w, _ := NewWorker(conf)
+	secure := bytes.NewBuffer([]byte{})
+	_, err := w.Encrypt(bytes.NewReader(content), secure)
+	assert.Nil(err, "encrypt error")
+
+	// Rotate key and cipher
+	oldConf, _ := DefaultConfig(oldKey[:])
+	newConf, _ := DefaultConfig(newKey[:])
+	newConf.Cipher = CHACHA20
+	var reports int
+	rotated := bytes.NewBuffer([]byte{})
+	res, err := Rekey(oldConf, newConf, bytes.NewReader(secure.Bytes()), rotated, WithProgress(func(_ Progress) error {
+		reports++
+		return nil
+	}))
+	assert.Nil(err, "rekey error")
+	assert.Equal(uint32(33), res.Packets, "invalid packets count")
+	assert.True(reports > 0, "no progress reported")
+
+	// Decrypt with new key
+	verifyConf, _ := DefaultConfig(newKey[:])
+	verifyConf.Cipher = CHACHA20
+	w2, _ := NewWorker(verifyConf)
+	decrypted := bytes.NewBuffer([]byte{})
+	_, err = w2.Decrypt(bytes.NewReader(rotated.Bytes()), decrypted)
+	assert.Nil(err, "decrypt error")
+	assert.Equal(content, decrypted.Bytes(), "bad decrypt result")
+
+	// Nil options are ignored
+	oldConf2, _ := DefaultConfig(oldKey[:])
+	newConf2, _ := DefaultConfig(newKey[:])
+	_, err = Rekey(oldConf2, newConf2, bytes.NewReader(secure.Bytes()), bytes.NewBuffer([]byte{}), nil)
+	assert.Nil(err, "rekey with nil option")
+
+	// Invalid old key
+	badConf, _ := DefaultConfig(newKey[:])
+	newConf3, _ := DefaultConfig(newKey[:])
+	_, err = Rekey(badConf, newConf3, bytes.NewReader(secure.Bytes()), bytes.NewBuffer([]byte{}))
+	assert.NotNil(err, "rekey should fail")
+	assert.True(strings.Contains(err.Error(), ErrInvalidPacketTag), "invalid error")
+}
+
 func TestConcurrency(t *testing.T) {
 	assert := tdd.New(t)
 	key := [32]byte{}
@@ -1,12 +1,25 @@
 package tred
 
 import (
+	"crypto/hmac"
 	"encoding/binary"
+	"hash"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/sha3"
 )
 
-type manifestBlock []byte
+// Manifest is a block of data appended at the end of the output produced by
+// a worker. It allows to validate the integrity of the complete output (i.e.,
+// detect truncated, missing or corrupted packets) without the need to decrypt
+// its contents. The checksum value is calculated as an HMAC (using SHA3-256)
+// of all the produced packets (and the stream header on version 2.0), using
+// a key derived from the worker's secret key material.
+//
+//	version (1) | cipher (1) | packets count (4) | checksum (32)
+type Manifest []byte
 
-// Length in bytes for the output manifestBlock.
+// Length in bytes for the output Manifest.
 //
 //	version | cipher | length | checksum
 const manifestSize = 38
@@ -14,46 +27,66 @@ const manifestSize = 38
 // Retrieve the manifest section from a byte array.
 //
 //	version (1) | cipher (1) | length (4) | checksum (32)
-func manifest(b []byte) manifestBlock {
+func manifest(b []byte) Manifest {
 	return b[:manifestSize]
 }
 
 // Version return package's version byte.
-func (m manifestBlock) Version() byte {
+func (m Manifest) Version() byte {
 	return m[0]
 }
 
 // Cipher return package's used AEAD cipher.
-func (m manifestBlock) Cipher() byte {
+func (m Manifest) Cipher() byte {
 	return m[1]
 }
 
 // Len return manifest's packets count.
-func (m manifestBlock) Len() int {
-	return int(binary.LittleEndian.Uint16(m[2:6])) + 1
+func (m Manifest) Len() int {
+	return int(binary.LittleEndian.Uint32(m[2:6]))
 }
 
 // Checksum return manifest's checksum value.
-func (m manifestBlock) Checksum() []byte {
+func (m Manifest) Checksum() []byte {
 	return m[6:]
 }
 
 // SetVersion adjust manifest's version byte.
-func (m manifestBlock) SetVersion(version byte) {
+func (m Manifest) SetVersion(version byte) {
 	m[0] = version
 }
 
 // SetCipher adjust manifest's AEAD cipher byte.
-func (m manifestBlock) SetCipher(suite byte) {
+func (m Manifest) SetCipher(suite byte) {
 	m[1] = suite
 }
 
 // SetLen adjust manifest's packets count.
-func (m manifestBlock) SetLen(length int) {
-	binary.LittleEndian.PutUint16(m[2:6], uint16(length-1))
+func (m Manifest) SetLen(length int) {
+	binary.LittleEndian.PutUint32(m[2:6], uint32(length))
 }
 
 // SetChecksum adjust manifest's checksum value.
-func (m manifestBlock) SetChecksum(val []byte) {
+func (m Manifest) SetChecksum(val []byte) {
 	copy(m[6:], val[:])
 }
+
+// Return a new hash instance suitable to calculate a manifest checksum.
+func (ss *session) digest() hash.Hash {
+	return hmac.New(sha3.New256, ss.macKey)
+}
+
+// Validate the manifest block 'm' against the session details, the worker's
+// current state and the calculated packets 'checksum'.
+func (w *Worker) checkManifest(ss *session, m Manifest, checksum []byte) error {
+	if m.Version() != ss.version || m.Cipher() != ss.suite {
+		return errors.New(ErrInvalidManifest)
+	}
+	if m.Len() != int(w.seq) {
+		return errors.New(ErrInvalidManifest)
+	}
+	if !hmac.Equal(m.Checksum(), checksum) {
+		return errors.New(ErrInvalidManifest)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package tred
+
+import (
+	"time"
+)
+
+// Option allows to adjust the behavior of individual worker operations.
+type Option func(op *opSettings)
+
+// Settings available for an individual worker operation.
+type opSettings struct {
+	storeName bool
+	progress  ProgressObserver
+	total     int64
+}
+
+// Progress provides details about the state of an ongoing operation.
+type Progress struct {
+	// Number of packets processed so far
+	Packets uint32
+
+	// Number of input bytes processed so far
+	Bytes int64
+
+	// Expected size of the input, in bytes; 0 if unknown
+	Total int64
+
+	// Time elapsed since the operation started
+	Elapsed time.Duration
+
+	// Estimated time remaining for the operation to complete; only
+	// available if the expected size of the input is known
+	ETA time.Duration
+}
+
+// ProgressObserver is periodically notified while a long-running operation
+// is in progress. Returning an error from the observer will abort the operation,
+// this can be used, for example, to enforce timeouts.
+type ProgressObserver func(p Progress) error
+
+// WithProgress registers an observer to be notified while the operation is
+// in progress.
+func WithProgress(fn ProgressObserver) Option {
+	return func(op *opSettings) {
+		op.progress = fn
+	}
+}
+
+// WithTotalSize sets the expected size of the input, in bytes. This value is
+// used to estimate the time remaining for an operation to complete. When using
+// the file-oriented helpers the value is determined automatically.
+func WithTotalSize(size int64) Option {
+	return func(op *opSettings) {
+		op.total = size
+	}
+}
+
+// WithFileName stores the original file name, encrypted, as part of the metadata
+// header when using 'EncryptFile'. This allows to use arbitrary (e.g., random)
+// names for the encrypted files and still be able to restore the original ones
+// when decrypting.
+func WithFileName() Option {
+	return func(op *opSettings) {
+		op.storeName = true
+	}
+}
+
+// Apply the provided options to a new settings instance.
+func newSettings(opts []Option) *opSettings {
+	op := &opSettings{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(op)
+		}
+	}
+	return op
+}
+
+// Build a progress report.
+func (op *opSettings) report(packets uint32, processed int64, start time.Time) Progress {
+	p := Progress{
+		Packets: packets,
+		Bytes:   processed,
+		Total:   op.total,
+		Elapsed: time.Since(start),
+	}
+	if op.total > 0 && processed > 0 && processed < op.total {
+		p.ETA = time.Duration(float64(p.Elapsed) * float64(op.total-processed) / float64(processed))
+	}
+	return p
+}
@@ -0,0 +1,50 @@
+package tred
+
+import (
+	"io"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Rekey will decrypt the secure 'input' content using 'oldConf' and re-encrypt
+// it using 'newConf', sending the results to 'output'. The operation is performed
+// in a single pass; plaintext is never fully buffered in memory or persisted.
+// This can be used to support periodic key rotation policies or to switch the
+// cipher suite used to protect existing content.
+//
+// The provided options are applied when reading the secure 'input' content; e.g.,
+// to be notified while the operation is in progress. Both configuration values
+// are used to create new worker instances, so they should not be shared with
+// other existing workers.
+func Rekey(oldConf, newConf *Config, input io.Reader, output io.Writer, opts ...Option) (*Result, error) {
+	// Get workers
+	dw, err := NewWorker(oldConf)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid decrypt configuration")
+	}
+	ew, err := NewWorker(newConf)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encrypt configuration")
+	}
+
+	// Decrypted content is streamed directly to the encrypt worker
+	start := time.Now()
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := dw.Decrypt(input, pw, opts...)
+		_ = pw.CloseWithError(err)
+		done <- err
+	}()
+	res, err := ew.Encrypt(pr, output)
+	_ = pr.CloseWithError(err) // unblock decrypt worker if encrypt failed
+	if dErr := <-done; dErr != nil {
+		return nil, dErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Duration = time.Since(start)
+	return res, nil
+}
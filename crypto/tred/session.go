@@ -0,0 +1,212 @@
+package tred
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Cryptographic state used to process a single stream of data.
+type session struct {
+	// Protocol version
+	version byte
+
+	// Cipher code
+	suite byte
+
+	// Maximum payload size per packet
+	payload int
+
+	// AEAD cipher instance
+	aead cipher.AEAD
+
+	// Manifest authentication key
+	macKey []byte
+
+	// Stream header, only used on version 2.0; authenticated on every packet
+	prefix []byte
+
+	// Caller-supplied associated data
+	ad []byte
+
+	// Nonce value included in every packet header
+	nonce [8]byte
+}
+
+// Start a new session to encrypt data using the worker's configuration.
+func (w *Worker) newSession() (*session, error) {
+	if w.conf.Version == Version10 {
+		return w.session(Version10, w.conf.Cipher, payloadSize, nil)
+	}
+	salt := make([]byte, keySize)
+	if _, err := w.conf.rng.Read(salt); err != nil {
+		return nil, errors.New(ErrRandomNonce)
+	}
+	return w.session(Version20, w.conf.Cipher, w.conf.payloadSize(), salt)
+}
+
+// Restore the session used to produce the secure 'input' content. The
+// returned reader must be used to consume the rest of the input. If the
+// input is empty a nil session is returned.
+func (w *Worker) openSession(input io.Reader) (*session, io.Reader, error) {
+	b := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(input, b[:2]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, input, nil
+		}
+		return nil, input, errors.New(ErrInvalidPayloadLen)
+	}
+	switch b[0] {
+	case Version10:
+		// Version and cipher are the first 2 bytes of the first packet
+		ss, err := w.session(Version10, b[1], payloadSize, nil)
+		return ss, io.MultiReader(bytes.NewReader(b[:2]), input), err
+	case Version20:
+		if _, err := io.ReadFull(input, b[2:]); err != nil {
+			return nil, input, errors.New(ErrInvalidPayloadLen)
+		}
+		size := int(binary.LittleEndian.Uint32(b[2:6]))
+		if size < minPayloadSize || size > maxPayloadSize {
+			return nil, input, errors.New(ErrInvalidPayloadLen)
+		}
+		ss, err := w.session(Version20, b[1], size, b[6:])
+		return ss, input, err
+	default:
+		return nil, input, errors.New(ErrUnsupportedVersion)
+	}
+}
+
+// Initialize session state. On version 2.0 a per-stream key is derived from
+// the worker's secret key material using the provided random 'salt'.
+func (w *Worker) session(version, suite byte, payload int, salt []byte) (*session, error) {
+	newCipher, ok := supportedCiphers[suite]
+	if !ok || (version == Version10 && suite == XCHACHA20) {
+		return nil, errors.New(ErrUnsupportedCipher)
+	}
+	key := w.conf.Key
+	ss := &session{
+		version: version,
+		suite:   suite,
+		payload: payload,
+		ad:      w.conf.AssociatedData,
+		nonce:   w.conf.nonce,
+	}
+	if version == Version20 {
+		var err error
+		if key, err = deriveKey(w.conf.Key, salt, []byte(streamKeyInfo)); err != nil {
+			return nil, err
+		}
+		ss.prefix = make([]byte, streamHeaderSize)
+		ss.prefix[0] = version
+		ss.prefix[1] = suite
+		binary.LittleEndian.PutUint32(ss.prefix[2:6], uint32(payload))
+		copy(ss.prefix[6:], salt)
+	}
+	aead, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	macKey, err := deriveKey(key, nil, []byte(manifestKeyInfo))
+	if err != nil {
+		return nil, err
+	}
+	ss.aead = aead
+	ss.macKey = macKey
+	return ss, nil
+}
+
+// Encrypted packet size.
+func (ss *session) packetSize() int {
+	return headerSize + ss.payload + tagSize
+}
+
+// Encrypt a payload chunk and return the produced packet.
+func (ss *session) seal(seq uint32, chunk []byte) ([]byte, error) {
+	// Payload is always padded to its full size
+	payload := make([]byte, ss.payload)
+	copy(payload, chunk)
+
+	// Encrypt payload
+	// Use 'seq | nonce' as operation nonce
+	// Use 'header | stream header | associated data' as additional data
+	h := ss.header(seq, len(chunk))
+	packet := make([]byte, headerSize, ss.packetSize())
+	copy(packet, h)
+	return ss.aead.Seal(packet, ss.opNonce(h), payload, ss.additionalData(h)), nil
+}
+
+// Decrypt a packet and return its original payload chunk.
+func (ss *session) open(seq uint32, packet []byte) ([]byte, error) {
+	if len(packet) < headerSize+tagSize {
+		return nil, errors.New(ErrInvalidPacketTag)
+	}
+
+	// Validate packet sequence
+	h := header(packet)
+	if h.SequenceNumber() != seq {
+		return nil, errors.New(ErrInvalidSequenceNumber)
+	}
+
+	// Decrypt and validate packet ciphertext
+	ciphertext := packet[headerSize:]
+	payload, err := ss.aead.Open(nil, ss.opNonce(h), ciphertext, ss.additionalData(h))
+	if err != nil {
+		return nil, errors.New(ErrInvalidPacketTag)
+	}
+
+	// Validate payload length
+	if len(payload) < ss.payloadLen(h) {
+		return nil, errors.New(ErrInvalidPayloadLen)
+	}
+	return payload[:ss.payloadLen(h)], nil
+}
+
+// Build a valid packet header block.
+func (ss *session) header(seq uint32, payloadLength int) headerBlock {
+	h := headerBlock(make([]byte, headerSize))
+	if ss.version == Version10 {
+		h.SetVersion(ss.version)
+		h.SetCipher(ss.suite)
+		h.SetLen(payloadLength)
+	} else {
+		h.SetLongLen(payloadLength)
+	}
+	h.SetSequenceNumber(seq)
+	h.SetNonce(ss.nonce)
+	return h
+}
+
+// Payload length of the provided packet header.
+func (ss *session) payloadLen(h headerBlock) int {
+	if ss.version == Version10 {
+		return h.Len()
+	}
+	return h.LongLen()
+}
+
+// Build the nonce value used to process a packet. Ciphers requiring longer
+// nonce values are left-padded with zeros.
+//
+//	seq (4) | nonce (8)
+func (ss *session) opNonce(h headerBlock) []byte {
+	if ss.aead.NonceSize() == headerSize-4 {
+		return h[4:headerSize]
+	}
+	n := make([]byte, ss.aead.NonceSize())
+	copy(n[len(n)-(headerSize-4):], h[4:headerSize])
+	return n
+}
+
+// Build the additional data value used to authenticate a packet.
+//
+//	header (4) | stream header (0 or 38) | associated data (variable)
+func (ss *session) additionalData(h headerBlock) []byte {
+	ad := make([]byte, 4+len(ss.prefix)+len(ss.ad))
+	copy(ad, h[:4])
+	copy(ad[4:], ss.prefix)
+	copy(ad[4+len(ss.prefix):], ss.ad)
+	return ad
+}
@@ -0,0 +1,109 @@
+package x25519
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	c "golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// HKDF info value used to derive sealed box encryption keys.
+const boxInfo = "x25519-sealed-box"
+
+// BoxOverhead is the number of bytes added to a message when using 'Seal'.
+//
+//	ephemeral public key (32) | ciphertext (len(msg)) | tag (16)
+const BoxOverhead = c.PointSize + chacha20poly1305.Overhead
+
+// Seal encrypts 'msg' for the recipient public key 'pub'. An ephemeral key
+// pair is used to perform a Diffie-Hellman agreement with the recipient, the
+// shared secret is expanded using HKDF (SHA-256) and the message is encrypted
+// using ChaCha20-Poly1305. Only the owner of the private key corresponding to
+// 'pub' can open the produced box. The sender remains anonymous.
+func Seal(pub [32]byte, msg []byte) ([]byte, error) {
+	// Ephemeral key
+	eph := make([]byte, c.ScalarSize)
+	if _, err := rand.Read(eph); err != nil {
+		return nil, errors.New("failed to generate random seed")
+	}
+	defer wipe(eph)
+	ephPub, err := c.X25519(eph, c.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shared secret
+	secret, err := c.X25519(eph, pub[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key")
+	}
+	defer wipe(secret)
+	return seal(secret, ephPub, pub[:], msg)
+}
+
+// Open decrypts a 'box' previously produced using 'Seal' with the public
+// key of the provided key pair instance.
+func Open(kp *KeyPair, box []byte) ([]byte, error) {
+	if len(box) < BoxOverhead {
+		return nil, errors.New("invalid box size")
+	}
+	var ephPub [32]byte
+	copy(ephPub[:], box[:c.PointSize])
+	secret := kp.DH(ephPub)
+	if secret == nil {
+		return nil, errors.New("invalid box")
+	}
+	defer wipe(secret)
+	pub := kp.PublicKey()
+	return open(secret, ephPub[:], pub[:], box[c.PointSize:])
+}
+
+func seal(secret, ephPub, rcpPub, msg []byte) ([]byte, error) {
+	aead, err := boxCipher(secret, ephPub, rcpPub)
+	if err != nil {
+		return nil, err
+	}
+	// Every box uses a unique key, a zero nonce is safe
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	out := make([]byte, c.PointSize, BoxOverhead+len(msg))
+	copy(out, ephPub)
+	return aead.Seal(out, nonce, msg, ephPub), nil
+}
+
+func open(secret, ephPub, rcpPub, ciphertext []byte) ([]byte, error) {
+	aead, err := boxCipher(secret, ephPub, rcpPub)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	msg, err := aead.Open(nil, nonce, ciphertext, ephPub)
+	if err != nil {
+		return nil, errors.New("failed to open box")
+	}
+	return msg, nil
+}
+
+// Derive the box encryption key from the shared secret; both public keys
+// are used as salt to bind the key to the specific agreement.
+func boxCipher(secret, ephPub, rcpPub []byte) (cipher.AEAD, error) {
+	salt := make([]byte, 0, 2*c.PointSize)
+	salt = append(append(salt, ephPub...), rcpPub...)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(boxInfo)), key); err != nil {
+		return nil, errors.New("failed to derive key")
+	}
+	defer wipe(key)
+	return chacha20poly1305.New(key)
+}
+
+// Zero out sensitive values.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
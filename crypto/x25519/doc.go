@@ -43,5 +43,38 @@ generate a shared secret with a provided public key.
 	if !bytes.Equal(s1, s2) {
 		panic("failed to generate valid secret")
 	}
+
+# Sealed Boxes
+
+The 'Seal' and 'Open' helpers provide an authenticated hybrid encryption scheme. An
+ephemeral key is used to perform a Diffie-Hellman agreement with the recipient's
+public key, the shared secret is expanded using HKDF and the message is encrypted
+using ChaCha20-Poly1305. Only the recipient can open the produced box.
+
+	box, _ := Seal(bob.PublicKey(), []byte("secret message"))
+	msg, err := Open(bob, box)
+
+# Noise Handshakes
+
+The 'NoiseHandshake' type implements the XX and IK patterns of the Noise protocol
+framework (using the "25519_ChaChaPoly_SHA256" cipher suite). It provides a lightweight
+mutually authenticated channel when a full TLS/PKI setup is overkill. Use XX when the
+peers don't know each other's static keys in advance, and IK when the initiator already
+knows the responder's static key.
+
+	// Initiator
+	hs, _ := NewNoiseHandshake(&NoiseConfig{
+		Pattern:   NoiseXX,
+		Initiator: true,
+		StaticKey: alice,
+	})
+	msg, _ := hs.WriteMessage(nil)
+
+	// ... exchange messages with the peer until 'hs.Complete()' ...
+
+	// Protect transport messages
+	send, recv, _ := hs.Transport()
+	ct, _ := send.Encrypt(nil, []byte("hello"))
+	pt, err := recv.Decrypt(nil, incoming)
 */
 package x25519
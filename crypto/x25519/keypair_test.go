@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/flynn/noise"
 	tdd "github.com/stretchr/testify/assert"
 	"go.uber.org/goleak"
 )
@@ -86,6 +87,41 @@ func TestFromSeed(t *testing.T) {
 }
 
 // Generate a shared secret between two key pair instances.
+func TestSealedBox(t *testing.T) {
+	assert := tdd.New(t)
+	alice, _ := New()
+	eve, _ := New()
+	defer alice.Destroy()
+	defer eve.Destroy()
+
+	msg := []byte("super secret message")
+	box, err := Seal(alice.PublicKey(), msg)
+	assert.Nil(err, "seal error")
+	assert.Equal(len(msg)+BoxOverhead, len(box), "invalid box size")
+
+	// Each box uses a different ephemeral key
+	box2, _ := Seal(alice.PublicKey(), msg)
+	assert.NotEqual(box, box2, "non-random box")
+
+	// Open
+	res, err := Open(alice, box)
+	assert.Nil(err, "open error")
+	assert.Equal(msg, res, "invalid message")
+
+	// Wrong recipient
+	_, err = Open(eve, box)
+	assert.NotNil(err, "open should fail")
+
+	// Tampered box
+	box[len(box)-1] ^= 0xff
+	_, err = Open(alice, box)
+	assert.NotNil(err, "open should fail")
+
+	// Invalid box
+	_, err = Open(alice, box[:10])
+	assert.NotNil(err, "open should fail")
+}
+
 func ExampleNew() {
 	// Generate peers
 	alice, _ := New()
@@ -102,3 +138,154 @@ func ExampleNew() {
 		panic("failed to generate valid secret")
 	}
 }
+
+func TestNoise(t *testing.T) {
+	assert := tdd.New(t)
+	alice, _ := New()
+	bob, _ := New()
+	defer alice.Destroy()
+	defer bob.Destroy()
+
+	// Exchange handshake messages until completed
+	run := func(init, resp *NoiseHandshake) {
+		sender, receiver := init, resp
+		for !init.Complete() {
+			msg, err := sender.WriteMessage([]byte("payload"))
+			assert.Nil(err, "write message")
+			payload, err := receiver.ReadMessage(msg)
+			assert.Nil(err, "read message")
+			assert.Equal([]byte("payload"), payload)
+			sender, receiver = receiver, sender
+		}
+		assert.True(resp.Complete(), "incomplete handshake")
+		assert.Equal(init.HandshakeHash(), resp.HandshakeHash(), "invalid handshake hash")
+		alicePub, bobPub := alice.PublicKey(), bob.PublicKey()
+		assert.Equal(bobPub[:], init.PeerStatic(), "invalid peer static")
+		assert.Equal(alicePub[:], resp.PeerStatic(), "invalid peer static")
+
+		// Transport messages
+		s1, r1, err := init.Transport()
+		assert.Nil(err, "transport")
+		s2, r2, err := resp.Transport()
+		assert.Nil(err, "transport")
+		for i := 0; i < 3; i++ {
+			ct, _ := s1.Encrypt(nil, []byte("ping"))
+			pt, err := r2.Decrypt(nil, ct)
+			assert.Nil(err, "decrypt")
+			assert.Equal([]byte("ping"), pt)
+			ct, _ = s2.Encrypt([]byte("ad"), []byte("pong"))
+			pt, err = r1.Decrypt([]byte("ad"), ct)
+			assert.Nil(err, "decrypt")
+			assert.Equal([]byte("pong"), pt)
+		}
+		s1.Rekey()
+		r2.Rekey()
+		ct, _ := s1.Encrypt(nil, []byte("after rekey"))
+		_, err = r2.Decrypt(nil, ct)
+		assert.Nil(err, "decrypt after rekey")
+		_, err = r2.Decrypt(nil, ct)
+		assert.NotNil(err, "replayed message")
+	}
+
+	t.Run("XX", func(t *testing.T) {
+		init, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseXX, Initiator: true, StaticKey: alice})
+		resp, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseXX, StaticKey: bob})
+		run(init, resp)
+	})
+
+	t.Run("IK", func(t *testing.T) {
+		pub := bob.PublicKey()
+		init, err := NewNoiseHandshake(&NoiseConfig{
+			Pattern:    NoiseIK,
+			Initiator:  true,
+			StaticKey:  alice,
+			PeerStatic: pub[:],
+			Prologue:   []byte("v1"),
+		})
+		assert.Nil(err)
+		resp, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseIK, StaticKey: bob, Prologue: []byte("v1")})
+		run(init, resp)
+
+		// Missing peer static key
+		_, err = NewNoiseHandshake(&NoiseConfig{Pattern: NoiseIK, Initiator: true, StaticKey: alice})
+		assert.NotNil(err, "missing peer static")
+	})
+
+	t.Run("Prologue", func(t *testing.T) {
+		init, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseXX, Initiator: true, StaticKey: alice, Prologue: []byte("a")})
+		resp, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseXX, StaticKey: bob, Prologue: []byte("b")})
+		msg, _ := init.WriteMessage(nil)
+		_, err := resp.ReadMessage(msg)
+		assert.Nil(err, "first message is not authenticated")
+		msg, _ = resp.WriteMessage(nil)
+		_, err = init.ReadMessage(msg)
+		assert.NotNil(err, "prologue mismatch")
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		init, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseXX, Initiator: true, StaticKey: alice})
+		_, err := init.ReadMessage(nil)
+		assert.NotNil(err, "initiator must write first")
+		_, _, err = init.Transport()
+		assert.NotNil(err, "incomplete handshake")
+		_, err = NewNoiseHandshake(&NoiseConfig{Pattern: NoisePattern("NN"), StaticKey: alice})
+		assert.NotNil(err, "unsupported pattern")
+	})
+
+	// Interoperability with an independent implementation
+	t.Run("Interop", func(t *testing.T) {
+		suite := noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
+		static, _ := suite.GenerateKeypair(nil)
+		var peer [32]byte
+		copy(peer[:], static.Public)
+
+		for _, p := range []struct {
+			pattern NoisePattern
+			hs      noise.HandshakePattern
+		}{{NoiseXX, noise.HandshakeXX}, {NoiseIK, noise.HandshakeIK}} {
+			init, _ := NewNoiseHandshake(&NoiseConfig{
+				Pattern:    p.pattern,
+				Initiator:  true,
+				StaticKey:  alice,
+				PeerStatic: static.Public,
+				Prologue:   []byte("interop"),
+			})
+			resp, err := noise.NewHandshakeState(noise.Config{
+				CipherSuite:   suite,
+				Pattern:       p.hs,
+				StaticKeypair: static,
+				Prologue:      []byte("interop"),
+			})
+			assert.Nil(err)
+
+			var c1, c2 *noise.CipherState
+			for i := 0; !init.Complete(); i++ {
+				if i%2 == 0 {
+					msg, err := init.WriteMessage([]byte("hello"))
+					assert.Nil(err)
+					payload, cs1, cs2, err := resp.ReadMessage(nil, msg)
+					assert.Nil(err, "%s: responder read", p.pattern)
+					assert.Equal([]byte("hello"), payload)
+					c1, c2 = cs1, cs2
+				} else {
+					msg, cs1, cs2, err := resp.WriteMessage(nil, []byte("world"))
+					assert.Nil(err)
+					payload, err := init.ReadMessage(msg)
+					assert.Nil(err, "%s: initiator read", p.pattern)
+					assert.Equal([]byte("world"), payload)
+					c1, c2 = cs1, cs2
+				}
+			}
+			assert.Equal(resp.ChannelBinding(), init.HandshakeHash(), "handshake hash")
+			send, recv, _ := init.Transport()
+			ct, _ := send.Encrypt(nil, []byte("ping"))
+			pt, err := c1.Decrypt(nil, nil, ct)
+			assert.Nil(err, "transport decrypt")
+			assert.Equal([]byte("ping"), pt)
+			ct, _ = c2.Encrypt(nil, nil, []byte("pong"))
+			pt, err = recv.Decrypt(nil, ct)
+			assert.Nil(err, "transport decrypt")
+			assert.Equal([]byte("pong"), pt)
+		}
+	})
+}
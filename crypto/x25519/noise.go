@@ -0,0 +1,440 @@
+package x25519
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// NoisePattern identifies a supported Noise handshake pattern.
+type NoisePattern string
+
+const (
+	// NoiseXX provides mutual authentication where both parties transmit
+	// their static public keys during the handshake. No previous knowledge
+	// about the peer is required.
+	//
+	//	-> e
+	//	<- e, ee, s, es
+	//	-> s, se
+	NoiseXX NoisePattern = "XX"
+
+	// NoiseIK provides mutual authentication with a single round-trip when
+	// the initiator already knows the responder's static public key. The
+	// initiator's static key is transmitted encrypted in the first message.
+	//
+	//	<- s
+	//	...
+	//	-> e, es, s, ss
+	//	<- e, ee, se
+	NoiseIK NoisePattern = "IK"
+)
+
+// NoiseMaxMessageSize is the maximum size, in bytes, of any Noise message.
+const NoiseMaxMessageSize = 65535
+
+// Noise handshake tokens.
+type noiseToken int
+
+const (
+	tokenE noiseToken = iota
+	tokenS
+	tokenEE
+	tokenES
+	tokenSE
+	tokenSS
+)
+
+// Message patterns for each supported handshake.
+var noisePatterns = map[NoisePattern][][]noiseToken{
+	NoiseXX: {
+		{tokenE},
+		{tokenE, tokenEE, tokenS, tokenES},
+		{tokenS, tokenSE},
+	},
+	NoiseIK: {
+		{tokenE, tokenES, tokenS, tokenSS},
+		{tokenE, tokenEE, tokenSE},
+	},
+}
+
+// NoiseConfig provides the settings required to start a Noise handshake.
+type NoiseConfig struct {
+	// Handshake pattern to use.
+	Pattern NoisePattern
+
+	// Whether the local party is the initiator of the handshake.
+	Initiator bool
+
+	// Local static key pair. The key pair is not destroyed when the
+	// handshake is completed.
+	StaticKey *KeyPair
+
+	// Peer's static public key. Required by the initiator when using
+	// the IK pattern.
+	PeerStatic []byte
+
+	// Optional data both parties must agree on; i.e., a handshake will
+	// fail if the prologue values used are different.
+	Prologue []byte
+}
+
+// NoiseHandshake performs a Noise protocol handshake using the cipher suite
+// "25519_ChaChaPoly_SHA256". Once completed, the handshake produces a pair of
+// cipher states suitable to protect transport messages in each direction.
+type NoiseHandshake struct {
+	ss        *symmetricState
+	pattern   [][]noiseToken
+	initiator bool
+	s         *KeyPair
+	e         *KeyPair
+	rs        []byte
+	re        []byte
+	step      int
+}
+
+// NewNoiseHandshake returns a new handshake state for the provided settings.
+func NewNoiseHandshake(conf *NoiseConfig) (*NoiseHandshake, error) {
+	pattern, ok := noisePatterns[conf.Pattern]
+	if !ok {
+		return nil, errors.Errorf("unsupported handshake pattern: %s", conf.Pattern)
+	}
+	if conf.StaticKey == nil {
+		return nil, errors.New("a static key is required")
+	}
+	hs := &NoiseHandshake{
+		ss:        newSymmetricState("Noise_" + string(conf.Pattern) + "_25519_ChaChaPoly_SHA256"),
+		pattern:   pattern,
+		initiator: conf.Initiator,
+		s:         conf.StaticKey,
+	}
+	hs.ss.mixHash(conf.Prologue)
+
+	// Pre-messages
+	if conf.Pattern == NoiseIK {
+		if conf.Initiator {
+			if len(conf.PeerStatic) != 32 {
+				return nil, errors.New("peer static key is required")
+			}
+			hs.rs = append([]byte{}, conf.PeerStatic...)
+			hs.ss.mixHash(hs.rs)
+		} else {
+			pub := hs.s.PublicKey()
+			hs.ss.mixHash(pub[:])
+		}
+	}
+	return hs, nil
+}
+
+// WriteMessage produces the next handshake message, including the provided
+// payload. The payload will be encrypted if a shared key is already available
+// at the current step of the handshake.
+func (hs *NoiseHandshake) WriteMessage(payload []byte) ([]byte, error) {
+	if hs.Complete() {
+		return nil, errors.New("handshake already completed")
+	}
+	if hs.initiator != (hs.step%2 == 0) {
+		return nil, errors.New("unexpected write, a message must be read first")
+	}
+	var msg []byte
+	for _, token := range hs.pattern[hs.step] {
+		switch token {
+		case tokenE:
+			e, err := New()
+			if err != nil {
+				return nil, err
+			}
+			hs.e = e
+			pub := e.PublicKey()
+			msg = append(msg, pub[:]...)
+			hs.ss.mixHash(pub[:])
+		case tokenS:
+			pub := hs.s.PublicKey()
+			ct, err := hs.ss.encryptAndHash(pub[:])
+			if err != nil {
+				return nil, err
+			}
+			msg = append(msg, ct...)
+		default:
+			if err := hs.mixDH(token); err != nil {
+				return nil, err
+			}
+		}
+	}
+	ct, err := hs.ss.encryptAndHash(payload)
+	if err != nil {
+		return nil, err
+	}
+	msg = append(msg, ct...)
+	if len(msg) > NoiseMaxMessageSize {
+		return nil, errors.New("message too large")
+	}
+	hs.step++
+	return msg, nil
+}
+
+// ReadMessage process a handshake message received from the peer and
+// returns its payload.
+func (hs *NoiseHandshake) ReadMessage(msg []byte) ([]byte, error) {
+	if hs.Complete() {
+		return nil, errors.New("handshake already completed")
+	}
+	if hs.initiator == (hs.step%2 == 0) {
+		return nil, errors.New("unexpected read, a message must be written first")
+	}
+	if len(msg) > NoiseMaxMessageSize {
+		return nil, errors.New("message too large")
+	}
+	for _, token := range hs.pattern[hs.step] {
+		switch token {
+		case tokenE:
+			if len(msg) < 32 {
+				return nil, errors.New("invalid message")
+			}
+			hs.re = append([]byte{}, msg[:32]...)
+			hs.ss.mixHash(hs.re)
+			msg = msg[32:]
+		case tokenS:
+			size := 32
+			if hs.ss.cs.hasKey() {
+				size += chacha20poly1305.Overhead
+			}
+			if len(msg) < size {
+				return nil, errors.New("invalid message")
+			}
+			rs, err := hs.ss.decryptAndHash(msg[:size])
+			if err != nil {
+				return nil, err
+			}
+			hs.rs = rs
+			msg = msg[size:]
+		default:
+			if err := hs.mixDH(token); err != nil {
+				return nil, err
+			}
+		}
+	}
+	payload, err := hs.ss.decryptAndHash(msg)
+	if err != nil {
+		return nil, err
+	}
+	hs.step++
+	return payload, nil
+}
+
+// Complete returns true once all handshake messages were processed.
+func (hs *NoiseHandshake) Complete() bool {
+	return hs.step >= len(hs.pattern)
+}
+
+// PeerStatic returns the peer's static public key, if already known.
+func (hs *NoiseHandshake) PeerStatic() []byte {
+	return append([]byte{}, hs.rs...)
+}
+
+// HandshakeHash returns a value that uniquely identifies the handshake
+// session. Useful for channel binding once the handshake is completed.
+func (hs *NoiseHandshake) HandshakeHash() []byte {
+	return append([]byte{}, hs.ss.h...)
+}
+
+// Transport returns the cipher states used to protect transport messages
+// once the handshake is completed. 'send' must be used to encrypt outgoing
+// messages and 'recv' to decrypt incoming messages. Ephemeral keys are
+// destroyed by this method.
+func (hs *NoiseHandshake) Transport() (send, recv *NoiseCipherState, err error) {
+	if !hs.Complete() {
+		return nil, nil, errors.New("handshake not completed")
+	}
+	c1, c2 := hs.ss.split()
+	if hs.e != nil {
+		hs.e.Destroy()
+		hs.e = nil
+	}
+	if hs.initiator {
+		return c1, c2, nil
+	}
+	return c2, c1, nil
+}
+
+// Perform a DH operation and mix the result in the chaining key.
+func (hs *NoiseHandshake) mixDH(token noiseToken) error {
+	var (
+		local  *KeyPair
+		remote []byte
+	)
+	switch token {
+	case tokenEE:
+		local, remote = hs.e, hs.re
+	case tokenSS:
+		local, remote = hs.s, hs.rs
+	case tokenES:
+		if hs.initiator {
+			local, remote = hs.e, hs.rs
+		} else {
+			local, remote = hs.s, hs.re
+		}
+	case tokenSE:
+		if hs.initiator {
+			local, remote = hs.s, hs.re
+		} else {
+			local, remote = hs.e, hs.rs
+		}
+	}
+	if local == nil || len(remote) != 32 {
+		return errors.New("invalid handshake state")
+	}
+	var pub [32]byte
+	copy(pub[:], remote)
+	secret := local.DH(pub)
+	if secret == nil {
+		return errors.New("invalid public key")
+	}
+	hs.ss.mixKey(secret)
+	wipe(secret)
+	return nil
+}
+
+// NoiseCipherState protects transport messages after a Noise handshake is
+// completed. A cipher state is not safe for concurrent use.
+type NoiseCipherState struct {
+	k     []byte
+	n     uint64
+	aead  cipher.AEAD
+	nonce [chacha20poly1305.NonceSize]byte
+}
+
+// Encrypt the provided plaintext. The additional data 'ad' is authenticated
+// but not encrypted.
+func (cs *NoiseCipherState) Encrypt(ad, plaintext []byte) ([]byte, error) {
+	if !cs.hasKey() {
+		return append([]byte{}, plaintext...), nil
+	}
+	if cs.n == math.MaxUint64 {
+		return nil, errors.New("nonce exhausted")
+	}
+	ct := cs.aead.Seal(nil, cs.opNonce(cs.n), plaintext, ad)
+	cs.n++
+	return ct, nil
+}
+
+// Decrypt the provided ciphertext. The additional data 'ad' must match the
+// value used when encrypting.
+func (cs *NoiseCipherState) Decrypt(ad, ciphertext []byte) ([]byte, error) {
+	if !cs.hasKey() {
+		return append([]byte{}, ciphertext...), nil
+	}
+	if cs.n == math.MaxUint64 {
+		return nil, errors.New("nonce exhausted")
+	}
+	pt, err := cs.aead.Open(nil, cs.opNonce(cs.n), ciphertext, ad)
+	if err != nil {
+		return nil, errors.New("failed to decrypt message")
+	}
+	cs.n++
+	return pt, nil
+}
+
+// Rekey updates the cipher key using a one-way function. Both parties
+// must rekey at the same point in the message sequence.
+func (cs *NoiseCipherState) Rekey() {
+	if !cs.hasKey() {
+		return
+	}
+	k := cs.aead.Seal(nil, cs.opNonce(math.MaxUint64), make([]byte, 32), nil)
+	cs.setKey(k[:32])
+}
+
+func (cs *NoiseCipherState) hasKey() bool {
+	return cs.aead != nil
+}
+
+func (cs *NoiseCipherState) setKey(k []byte) {
+	cs.k = append([]byte{}, k...)
+	cs.n = 0
+	cs.aead, _ = chacha20poly1305.New(cs.k)
+}
+
+func (cs *NoiseCipherState) opNonce(n uint64) []byte {
+	binary.LittleEndian.PutUint64(cs.nonce[4:], n)
+	return cs.nonce[:]
+}
+
+// Noise symmetric state.
+type symmetricState struct {
+	cs *NoiseCipherState
+	ck []byte
+	h  []byte
+}
+
+func newSymmetricState(protocol string) *symmetricState {
+	ss := &symmetricState{cs: new(NoiseCipherState)}
+	if len(protocol) <= sha256.Size {
+		ss.h = make([]byte, sha256.Size)
+		copy(ss.h, protocol)
+	} else {
+		sum := sha256.Sum256([]byte(protocol))
+		ss.h = sum[:]
+	}
+	ss.ck = append([]byte{}, ss.h...)
+	return ss
+}
+
+func (ss *symmetricState) mixKey(ikm []byte) {
+	ck, k := noiseHKDF(ss.ck, ikm)
+	ss.ck = ck
+	ss.cs.setKey(k)
+}
+
+func (ss *symmetricState) mixHash(data []byte) {
+	h := sha256.New()
+	_, _ = h.Write(ss.h)
+	_, _ = h.Write(data)
+	ss.h = h.Sum(nil)
+}
+
+func (ss *symmetricState) encryptAndHash(plaintext []byte) ([]byte, error) {
+	ct, err := ss.cs.Encrypt(ss.h, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(ct)
+	return ct, nil
+}
+
+func (ss *symmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	pt, err := ss.cs.Decrypt(ss.h, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(ciphertext)
+	return pt, nil
+}
+
+func (ss *symmetricState) split() (*NoiseCipherState, *NoiseCipherState) {
+	k1, k2 := noiseHKDF(ss.ck, nil)
+	c1, c2 := new(NoiseCipherState), new(NoiseCipherState)
+	c1.setKey(k1)
+	c2.setKey(k2)
+	return c1, c2
+}
+
+// HKDF function as defined by the Noise specification, producing two outputs.
+func noiseHKDF(ck, ikm []byte) ([]byte, []byte) {
+	mac := func(key []byte, data ...[]byte) []byte {
+		h := hmac.New(sha256.New, key)
+		for _, d := range data {
+			_, _ = h.Write(d)
+		}
+		return h.Sum(nil)
+	}
+	tmp := mac(ck, ikm)
+	out1 := mac(tmp, []byte{0x01})
+	out2 := mac(tmp, out1, []byte{0x02})
+	return out1, out2
+}
@@ -0,0 +1,156 @@
+package comm
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/jose/jwe"
+)
+
+type party struct {
+	id *did.Identifier
+	m  *Messenger
+}
+
+func newParty(t *testing.T) *party {
+	id, err := did.NewKeyIdentifier(did.KeyTypeX25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMessenger(WithIdentity(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &party{id: id, m: m}
+}
+
+func TestMessenger(t *testing.T) {
+	assert := tdd.New(t)
+	alice := newParty(t)
+	bob := newParty(t)
+	carol := newParty(t)
+	eve := newParty(t)
+
+	// Ed25519 identifiers don't provide key agreement keys
+	ed, _ := did.NewKeyIdentifier(did.KeyTypeEd)
+	_, err := NewMessenger(WithIdentity(ed))
+	assert.NotNil(err, "no key agreement keys")
+
+	body := map[string]string{"greeting": "hello"}
+	newMsg := func() *Message {
+		msg, err := NewMessage("https://example.com/protocols/1.0/ping", body)
+		assert.Nil(err, "new message")
+		msg.From = alice.id.DID()
+		msg.To = []string{bob.id.DID(), carol.id.DID()}
+		return msg
+	}
+
+	t.Run("Anoncrypt", func(t *testing.T) {
+		msg := newMsg()
+		msg.From = ""
+		packed, err := alice.m.PackAnon(msg)
+		assert.Nil(err, "pack")
+		for _, p := range []*party{bob, carol} {
+			res, md, err := p.m.Unpack(packed)
+			assert.Nil(err, "unpack")
+			assert.True(md.Encrypted)
+			assert.False(md.Authenticated)
+			assert.Equal(msg.ID, res.ID)
+			rb := map[string]string{}
+			assert.Nil(res.DecodeBody(&rb))
+			assert.Equal(body, rb)
+		}
+		_, _, err = eve.m.Unpack(packed)
+		assert.NotNil(err, "not a recipient")
+	})
+
+	t.Run("Authcrypt", func(t *testing.T) {
+		msg := newMsg()
+		packed, err := alice.m.PackAuth(msg)
+		assert.Nil(err, "pack")
+		res, md, err := bob.m.Unpack(packed)
+		assert.Nil(err, "unpack")
+		assert.True(md.Authenticated)
+		assert.Equal(alice.id.DID(), res.From)
+		assert.Equal(alice.id.VerificationMethods()[0].ID, md.SenderKeyID)
+
+		// Sender not registered
+		msg.From = eve.id.DID()
+		_, err = alice.m.PackAuth(msg)
+		assert.NotNil(err, "unknown sender")
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		packed, err := alice.m.PackAuth(newMsg())
+		assert.Nil(err, "pack")
+		env := new(jwe.Message)
+		assert.Nil(json.Unmarshal(packed, env))
+
+		// Remove a recipient
+		env.Recipients = env.Recipients[:1]
+		js, _ := json.Marshal(env)
+		_, _, err = bob.m.Unpack(js)
+		assert.NotNil(err, "invalid apv")
+
+		// Modified ciphertext
+		assert.Nil(json.Unmarshal(packed, env))
+		ct, _ := b64.DecodeString(env.Ciphertext)
+		ct[0] ^= 0xff
+		env.Ciphertext = b64.EncodeToString(ct)
+		js, _ = json.Marshal(env)
+		_, _, err = bob.m.Unpack(js)
+		assert.NotNil(err, "invalid ciphertext")
+	})
+
+	t.Run("SpecificKey", func(t *testing.T) {
+		msg := newMsg()
+		msg.To = []string{bob.id.VerificationMethods()[0].ID}
+		packed, err := alice.m.PackAnon(msg)
+		assert.Nil(err, "pack")
+		_, md, err := bob.m.Unpack(packed)
+		assert.Nil(err, "unpack")
+		assert.Equal(msg.To[0], md.RecipientKeyID)
+
+		msg.To = []string{bob.id.DID() + "#unknown"}
+		_, err = alice.m.PackAnon(msg)
+		assert.NotNil(err, "unknown key")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		msg := newMsg()
+		msg.ExpiresTime = time.Now().Add(-1 * time.Minute).Unix()
+		_, err := alice.m.PackAnon(msg)
+		assert.NotNil(err, "expired")
+	})
+
+	t.Run("Forward", func(t *testing.T) {
+		mediator := newParty(t)
+		msg := newMsg()
+		msg.To = []string{bob.id.DID()}
+		packed, err := alice.m.PackAuth(msg)
+		assert.Nil(err, "pack")
+		routed, err := alice.m.Forward(packed, bob.id.DID(), mediator.id.VerificationMethods()[0].ID)
+		assert.Nil(err, "forward")
+		_, _, err = bob.m.Unpack(routed)
+		assert.NotNil(err, "not a recipient")
+
+		// Mediator
+		fwd, _, err := mediator.m.Unpack(routed)
+		assert.Nil(err, "unpack forward")
+		next, inner, err := fwd.Forwarded()
+		assert.Nil(err, "forwarded")
+		assert.Equal(bob.id.DID(), next)
+
+		// Recipient
+		res, md, err := bob.m.Unpack(inner)
+		assert.Nil(err, "unpack")
+		assert.True(md.Authenticated)
+		assert.Equal(msg.ID, res.ID)
+
+		_, _, err = res.Forwarded()
+		assert.NotNil(err, "not a forward message")
+	})
+}
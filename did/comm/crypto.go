@@ -0,0 +1,236 @@
+package comm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"sort"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Key management and content encryption algorithms supported.
+// https://identity.foundation/didcomm-messaging/spec/v2.0/#curves-and-content-encryption-algorithms
+const (
+	algAnon = "ECDH-ES+A256KW"
+	algAuth = "ECDH-1PU+A256KW"
+	encAnon = "A256GCM"
+	encAuth = "A256CBC-HS512"
+	crvX    = "X25519"
+)
+
+// Base64 encoding used by all serialization formats.
+var b64 = base64.RawURLEncoding
+
+// Protected header shared by all recipients of an encrypted message.
+type envelopeHeader struct {
+	Type         string        `json:"typ"`
+	Algorithm    string        `json:"alg"`
+	Encryption   string        `json:"enc"`
+	EphemeralKey *ephemeralKey `json:"epk"`
+	SenderKeyID  string        `json:"skid,omitempty"`
+	PartyUInfo   string        `json:"apu,omitempty"`
+	PartyVInfo   string        `json:"apv"`
+}
+
+// Public JWK record for the ephemeral key used on an encrypted message.
+type ephemeralKey struct {
+	KeyType string `json:"kty"`
+	Crv     string `json:"crv"`
+	X       string `json:"x"`
+}
+
+// Return the ephemeral public key.
+func (ek *ephemeralKey) public() (*ecdh.PublicKey, error) {
+	if ek == nil || ek.KeyType != "OKP" || ek.Crv != crvX {
+		return nil, errors.New("unsupported ephemeral key")
+	}
+	x, err := b64.DecodeString(ek.X)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ephemeral key")
+	}
+	return ecdh.X25519().NewPublicKey(x)
+}
+
+// Content encryption result.
+type sealed struct {
+	iv         []byte
+	ciphertext []byte
+	tag        []byte
+}
+
+// Encrypt the plaintext using the content encryption algorithm `enc`;
+// `aad` is the additional authenticated data.
+func seal(enc string, cek, plaintext, aad []byte) (*sealed, error) {
+	switch enc {
+	case encAnon:
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+		iv := make([]byte, aead.NonceSize())
+		if _, err = rand.Read(iv); err != nil {
+			return nil, err
+		}
+		ct := aead.Seal(nil, iv, plaintext, aad)
+		split := len(ct) - aead.Overhead()
+		return &sealed{iv: iv, ciphertext: ct[:split], tag: ct[split:]}, nil
+	case encAuth:
+		if len(cek) != 64 {
+			return nil, errors.New("invalid content encryption key")
+		}
+		block, err := aes.NewCipher(cek[32:])
+		if err != nil {
+			return nil, err
+		}
+		iv := make([]byte, aes.BlockSize)
+		if _, err = rand.Read(iv); err != nil {
+			return nil, err
+		}
+		pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+		ct := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, ct)
+		return &sealed{iv: iv, ciphertext: ct, tag: cbcTag(cek[:32], aad, iv, ct)}, nil
+	default:
+		return nil, errors.Errorf("unsupported 'enc' value '%s'", enc)
+	}
+}
+
+// Decrypt and authenticate the content previously encrypted with 'seal'.
+func open(enc string, cek, aad []byte, s *sealed) ([]byte, error) {
+	switch enc {
+	case encAnon:
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+		if len(s.iv) != aead.NonceSize() {
+			return nil, errors.New("invalid initialization vector")
+		}
+		return aead.Open(nil, s.iv, append(append([]byte{}, s.ciphertext...), s.tag...), aad)
+	case encAuth:
+		if len(cek) != 64 {
+			return nil, errors.New("invalid content encryption key")
+		}
+		if len(s.iv) != aes.BlockSize || len(s.ciphertext) == 0 || len(s.ciphertext)%aes.BlockSize != 0 {
+			return nil, errors.New("invalid ciphertext")
+		}
+		if subtle.ConstantTimeCompare(cbcTag(cek[:32], aad, s.iv, s.ciphertext), s.tag) != 1 {
+			return nil, errors.New("message authentication failed")
+		}
+		block, err := aes.NewCipher(cek[32:])
+		if err != nil {
+			return nil, err
+		}
+		pt := make([]byte, len(s.ciphertext))
+		cipher.NewCBCDecrypter(block, s.iv).CryptBlocks(pt, s.ciphertext)
+		pad := int(pt[len(pt)-1])
+		if pad == 0 || pad > aes.BlockSize {
+			return nil, errors.New("invalid padding")
+		}
+		return pt[:len(pt)-pad], nil
+	default:
+		return nil, errors.Errorf("unsupported 'enc' value '%s'", enc)
+	}
+}
+
+// Content encryption key size, in bytes, for the algorithm `enc`.
+func cekSize(enc string) int {
+	if enc == encAuth {
+		return 64
+	}
+	return 32
+}
+
+// Authentication tag for AES-CBC with HMAC-SHA-512.
+// https://www.rfc-editor.org/rfc/rfc7518.html#section-5.2.2.1
+func cbcTag(key, aad, iv, ciphertext []byte) []byte {
+	al := binary.BigEndian.AppendUint64(nil, uint64(len(aad))*8)
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write(aad)
+	_, _ = mac.Write(iv)
+	_, _ = mac.Write(ciphertext)
+	_, _ = mac.Write(al)
+	return mac.Sum(nil)[:32]
+}
+
+// Return an AES GCM cipher for the provided key.
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Derive the key encryption key for a recipient. For "ECDH-1PU" the shared
+// secret is the concatenation of the ephemeral and static secrets, and the
+// content authentication tag is included in the KDF input.
+// https://datatracker.ietf.org/doc/html/draft-madden-jose-ecdh-1pu-04#section-2.3
+func deriveKEK(he *envelopeHeader, ze, zs, tag []byte) ([]byte, error) {
+	apu, err := b64.DecodeString(he.PartyUInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid 'apu' value")
+	}
+	apv, err := b64.DecodeString(he.PartyVInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid 'apv' value")
+	}
+	z := append(append([]byte{}, ze...), zs...)
+	defer wipe(z)
+	if he.Algorithm != algAuth {
+		tag = nil
+	}
+	return concatKDF(z, he.Algorithm, apu, apv, tag), nil
+}
+
+// Derive a 256 bits key using the Concat KDF with SHA-256.
+// https://www.rfc-editor.org/rfc/rfc7518.html#section-4.6.2
+func concatKDF(z []byte, alg string, apu, apv, tag []byte) []byte {
+	info := make([]byte, 0, 20+len(alg)+len(apu)+len(apv)+len(tag))
+	info = lengthPrefixed(info, []byte(alg))
+	info = lengthPrefixed(info, apu)
+	info = lengthPrefixed(info, apv)
+	info = binary.BigEndian.AppendUint32(info, 256) // SuppPubInfo
+	if tag != nil {
+		info = lengthPrefixed(info, tag)
+	}
+
+	// A single round is enough for 256 bits
+	h := sha256.New()
+	_, _ = h.Write([]byte{0, 0, 0, 1})
+	_, _ = h.Write(z)
+	_, _ = h.Write(info)
+	return h.Sum(nil)
+}
+
+// Append `data` to `buf`, prefixed by its length as a 32-bit big-endian
+// value.
+func lengthPrefixed(buf, data []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+// Value used as "apv"; the hash of the sorted recipient key identifiers.
+func partyVInfo(kids []string) string {
+	sorted := append([]string{}, kids...)
+	sort.Strings(sorted)
+	digest := sha256.Sum256([]byte(strings.Join(sorted, ".")))
+	return b64.EncodeToString(digest[:])
+}
+
+// Overwrite sensitive material.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
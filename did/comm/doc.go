@@ -0,0 +1,60 @@
+/*
+Package comm provides DIDComm v2 messaging support.
+
+DIDComm enables secure and private communication between parties identified
+by DIDs. Messages are encrypted using the "keyAgreement" keys available on
+the DID documents of its recipients; only X25519 keys are supported.
+
+	alice, _ := did.NewKeyIdentifier(did.KeyTypeX25519)
+	messenger, _ := comm.NewMessenger(comm.WithIdentity(alice))
+
+	msg, _ := comm.NewMessage("https://example.com/protocols/1.0/ping", map[string]string{
+		"greeting": "hello",
+	})
+	msg.From = alice.DID()
+	msg.To = []string{bobDID}
+
+# Packing Messages
+
+Messages can be encrypted anonymously ("anoncrypt") or authenticating its
+sender ("authcrypt"). Authenticated messages require the sender DID to be
+registered on the messenger using 'WithIdentity'.
+
+	// ECDH-ES+A256KW with A256GCM
+	packed, _ := messenger.PackAnon(msg)
+
+	// ECDH-1PU+A256KW with A256CBC-HS512
+	packed, _ := messenger.PackAuth(msg)
+
+Recipients unpack received messages using the keys of their own DIDs.
+
+	msg, md, err := messenger.Unpack(packed)
+	if md.Authenticated {
+		fmt.Printf("message sent by: %s", msg.From)
+	}
+
+Only "did:key" identifiers are resolved by default, use a resolver instance
+to enable additional methods.
+
+	messenger, _ := comm.NewMessenger(
+		comm.WithIdentity(alice),
+		comm.WithResolver(resolverInstance),
+	)
+
+# Routing
+
+Messages can be delivered through mediators using "forward" messages. Each
+mediator unpacks the forward message and relays the attached message to
+the next hop.
+
+	// Sender
+	routed, _ := messenger.Forward(packed, bobDID, mediatorKey)
+
+	// Mediator
+	fwd, _, _ := mediator.Unpack(routed)
+	next, packed, _ := fwd.Forwarded()
+
+More information:
+https://identity.foundation/didcomm-messaging/spec/v2.0/
+*/
+package comm
@@ -0,0 +1,38 @@
+package comm
+
+import (
+	"encoding/json"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Forward wraps a packed message for delivery through one or more
+// mediators. `next` is the final recipient of the message, usually the
+// DID on its "to" value, and `routingKeys` the keys of the mediators to
+// use, as listed on the recipient's "DIDCommMessaging" service; the first
+// routing key corresponds to the first mediator the message is delivered
+// to. Each forward message is encrypted ("anoncrypt") for its mediator.
+// https://identity.foundation/didcomm-messaging/spec/v2.0/#routing-protocol-20
+func (m *Messenger) Forward(packed []byte, next string, routingKeys ...string) ([]byte, error) {
+	if !json.Valid(packed) {
+		return nil, errors.New("invalid packed message")
+	}
+	for i := len(routingKeys) - 1; i >= 0; i-- {
+		fwd, err := NewMessage(ForwardType, map[string]string{"next": next})
+		if err != nil {
+			return nil, err
+		}
+		fwd.To = []string{routingKeys[i]}
+		fwd.Attachments = []Attachment{
+			{
+				MediaType: MediaTypeEncrypted,
+				Data:      AttachmentData{JSON: packed},
+			},
+		}
+		if packed, err = m.PackAnon(fwd); err != nil {
+			return nil, errors.Wrapf(err, "routing key '%s'", routingKeys[i])
+		}
+		next = routingKeys[i]
+	}
+	return packed, nil
+}
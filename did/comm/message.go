@@ -0,0 +1,140 @@
+package comm
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// MediaTypePlain is the media type for plaintext messages.
+	MediaTypePlain = "application/didcomm-plain+json"
+
+	// MediaTypeEncrypted is the media type for encrypted messages.
+	MediaTypeEncrypted = "application/didcomm-encrypted+json"
+
+	// ForwardType is the message type used to route messages through
+	// mediators.
+	// https://identity.foundation/didcomm-messaging/spec/v2.0/#messages
+	ForwardType = "https://didcomm.org/routing/2.0/forward"
+)
+
+// Message is a DIDComm plaintext message. Messages are encrypted for its
+// recipients using 'Messenger.PackAnon' or 'Messenger.PackAuth'.
+// https://identity.foundation/didcomm-messaging/spec/v2.0/#plaintext-message-structure
+type Message struct {
+	// Unique identifier for the message.
+	ID string `json:"id"`
+
+	// Media type of the message.
+	Typ string `json:"typ,omitempty"`
+
+	// URI identifying the message protocol and type.
+	Type string `json:"type"`
+
+	// DID of the sender, optional for anonymous messages.
+	From string `json:"from,omitempty"`
+
+	// DIDs, or DID URLs of specific keys, of the intended recipients.
+	To []string `json:"to,omitempty"`
+
+	// Identifier of the thread the message belongs to.
+	ThreadID string `json:"thid,omitempty"`
+
+	// Identifier of the parent thread.
+	ParentThreadID string `json:"pthid,omitempty"`
+
+	// Creation time, as seconds since the UNIX epoch.
+	CreatedTime int64 `json:"created_time,omitempty"`
+
+	// Expiration time, as seconds since the UNIX epoch; expired messages
+	// are rejected when unpacked.
+	ExpiresTime int64 `json:"expires_time,omitempty"`
+
+	// Message content, as defined by the message type.
+	Body json.RawMessage `json:"body"`
+
+	// Additional content included in the message.
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment provides additional content for a message.
+// https://identity.foundation/didcomm-messaging/spec/v2.0/#attachments
+type Attachment struct {
+	// Identifier for the attachment, optional.
+	ID string `json:"id,omitempty"`
+
+	// Media type of the attached content.
+	MediaType string `json:"media_type,omitempty"`
+
+	// Attached content.
+	Data AttachmentData `json:"data"`
+}
+
+// AttachmentData contains the attached content; only one of the
+// representations should be used.
+type AttachmentData struct {
+	// JSON content.
+	JSON json.RawMessage `json:"json,omitempty"`
+
+	// Base64url-encoded content.
+	Base64 string `json:"base64,omitempty"`
+
+	// Locations where the content can be retrieved.
+	Links []string `json:"links,omitempty"`
+
+	// Multihash of the content, required when using links.
+	Hash string `json:"hash,omitempty"`
+}
+
+// NewMessage returns a new plaintext message of type `typ`. The `body`
+// value must be JSON-encodable.
+func NewMessage(typ string, body interface{}) (*Message, error) {
+	js, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid message body")
+	}
+	return &Message{
+		ID:          uuid.NewString(),
+		Typ:         MediaTypePlain,
+		Type:        typ,
+		CreatedTime: time.Now().Unix(),
+		Body:        js,
+	}, nil
+}
+
+// DecodeBody decodes the message body into `v`.
+func (m *Message) DecodeBody(v interface{}) error {
+	return json.Unmarshal(m.Body, v)
+}
+
+// Forwarded returns the next hop and the packed message of a "forward"
+// message. Intended to be used by mediators to relay received messages.
+func (m *Message) Forwarded() (string, []byte, error) {
+	if m.Type != ForwardType {
+		return "", nil, errors.New("not a forward message")
+	}
+	body := struct {
+		Next string `json:"next"`
+	}{}
+	if err := m.DecodeBody(&body); err != nil || body.Next == "" {
+		return "", nil, errors.New("invalid forward message")
+	}
+	if len(m.Attachments) != 1 || len(m.Attachments[0].Data.JSON) == 0 {
+		return "", nil, errors.New("invalid forward message")
+	}
+	return body.Next, m.Attachments[0].Data.JSON, nil
+}
+
+// Validate the message is structurally valid and not expired.
+func (m *Message) Validate() error {
+	if m.ID == "" || m.Type == "" {
+		return errors.New("invalid message: missing 'id' or 'type'")
+	}
+	if m.ExpiresTime != 0 && time.Now().Unix() > m.ExpiresTime {
+		return errors.New("expired message")
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package comm
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"sort"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/did/resolver"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwe"
+)
+
+// Resolver instances provide access to the DID documents of message
+// senders and recipients. A 'resolver.Instance' satisfies this interface.
+type Resolver interface {
+	// Resolve a DID into a DID document.
+	Resolve(id string, opts *resolver.ResolutionOptions) (*resolver.Result, error)
+}
+
+// Metadata provides details on how a received message was secured.
+type Metadata struct {
+	// Whether the message was encrypted.
+	Encrypted bool
+
+	// Whether the sender of the message was authenticated ("authcrypt").
+	Authenticated bool
+
+	// Key used by the sender to authenticate the message.
+	SenderKeyID string
+
+	// Key used to decrypt the message.
+	RecipientKeyID string
+}
+
+// Messenger instances pack (encrypt) and unpack (decrypt) DIDComm messages
+// using the "keyAgreement" keys available on the DID documents of senders
+// and recipients. Only X25519 keys are supported.
+type Messenger struct {
+	resolver Resolver
+	keys     map[string]*ecdh.PrivateKey
+}
+
+// Key agreement key of a message recipient.
+type recipientKey struct {
+	kid string
+	pub *ecdh.PublicKey
+}
+
+// NewMessenger returns a new messenger instance. By default, only "did:key"
+// identifiers can be resolved.
+func NewMessenger(opts ...Option) (*Messenger, error) {
+	m := &Messenger{keys: make(map[string]*ecdh.PrivateKey)}
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, err
+		}
+	}
+	if m.resolver == nil {
+		r, err := resolver.New()
+		if err != nil {
+			return nil, err
+		}
+		m.resolver = r
+	}
+	return m, nil
+}
+
+// PackAnon encrypts a message for all its recipients ("anoncrypt"). The
+// sender of the message is not authenticated.
+// https://identity.foundation/didcomm-messaging/spec/v2.0/#anoncrypt
+func (m *Messenger) PackAnon(msg *Message) ([]byte, error) {
+	pt, recipients, err := m.prepare(msg)
+	if err != nil {
+		return nil, err
+	}
+	return m.encrypt(pt, algAnon, "", nil, recipients)
+}
+
+// PackAuth encrypts a message for all its recipients and authenticates
+// its sender ("authcrypt"). The "from" DID of the message must be
+// registered on the messenger instance using 'WithIdentity'.
+// https://identity.foundation/didcomm-messaging/spec/v2.0/#authcrypt
+func (m *Messenger) PackAuth(msg *Message) ([]byte, error) {
+	if msg.From == "" {
+		return nil, errors.New("missing message sender")
+	}
+	skid, sender := m.senderKey(msg.From)
+	if sender == nil {
+		return nil, errors.Errorf("no key agreement keys available for '%s'", msg.From)
+	}
+	pt, recipients, err := m.prepare(msg)
+	if err != nil {
+		return nil, err
+	}
+	return m.encrypt(pt, algAuth, skid, sender, recipients)
+}
+
+// Unpack a received message. Nested envelopes are decrypted until the
+// plaintext message is available; plaintext messages are also accepted.
+// Expired messages are rejected.
+func (m *Messenger) Unpack(data []byte) (*Message, *Metadata, error) {
+	md := new(Metadata)
+	for {
+		env := new(jwe.Message)
+		if err := json.Unmarshal(data, env); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid message")
+		}
+		if env.Ciphertext == "" {
+			break
+		}
+		pt, he, kid, err := m.decrypt(env)
+		if err != nil {
+			return nil, nil, err
+		}
+		md.Encrypted = true
+		md.RecipientKeyID = kid
+		if he.Algorithm == algAuth {
+			md.Authenticated = true
+			md.SenderKeyID = he.SenderKeyID
+		}
+		data = pt
+	}
+	msg := new(Message)
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid message")
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if md.Authenticated {
+		ref, err := did.Parse(md.SenderKeyID)
+		if err != nil || ref.DID() != msg.From {
+			return nil, nil, errors.New("message sender doesn't match the authenticated key")
+		}
+	}
+	if md.Encrypted && len(msg.To) > 0 && !addressed(msg.To, md.RecipientKeyID) {
+		return nil, nil, errors.New("message not addressed to the recipient key")
+	}
+	return msg, md, nil
+}
+
+// Validate a message and return its encoded form along with the keys of
+// all its recipients.
+func (m *Messenger) prepare(msg *Message) ([]byte, []recipientKey, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if len(msg.To) == 0 {
+		return nil, nil, errors.New("missing message recipients")
+	}
+	var recipients []recipientKey
+	for _, to := range msg.To {
+		keys, err := m.agreementKeys(to)
+		if err != nil {
+			return nil, nil, err
+		}
+		recipients = append(recipients, keys...)
+	}
+	pt, err := json.Marshal(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pt, recipients, nil
+}
+
+// Encrypt the plaintext for all recipients; `sender` is only required for
+// "authcrypt" messages.
+func (m *Messenger) encrypt(pt []byte, alg, skid string, sender *ecdh.PrivateKey, recipients []recipientKey) ([]byte, error) {
+	epk, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	kids := make([]string, len(recipients))
+	for i, rk := range recipients {
+		kids[i] = rk.kid
+	}
+	he := &envelopeHeader{
+		Type:       MediaTypeEncrypted,
+		Algorithm:  alg,
+		Encryption: encAnon,
+		EphemeralKey: &ephemeralKey{
+			KeyType: "OKP",
+			Crv:     crvX,
+			X:       b64.EncodeToString(epk.PublicKey().Bytes()),
+		},
+		PartyVInfo: partyVInfo(kids),
+	}
+	if alg == algAuth {
+		he.Encryption = encAuth
+		he.SenderKeyID = skid
+		he.PartyUInfo = b64.EncodeToString([]byte(skid))
+	}
+	js, err := json.Marshal(he)
+	if err != nil {
+		return nil, err
+	}
+	env := &jwe.Message{Protected: b64.EncodeToString(js)}
+
+	// Encrypt content
+	cek := make([]byte, cekSize(he.Encryption))
+	defer wipe(cek)
+	if _, err = rand.Read(cek); err != nil {
+		return nil, err
+	}
+	s, err := seal(he.Encryption, cek, pt, []byte(env.Protected))
+	if err != nil {
+		return nil, err
+	}
+	env.IV = b64.EncodeToString(s.iv)
+	env.Ciphertext = b64.EncodeToString(s.ciphertext)
+	env.Tag = b64.EncodeToString(s.tag)
+
+	// Encrypt the content encryption key for each recipient
+	for _, rk := range recipients {
+		ze, err := epk.ECDH(rk.pub)
+		if err != nil {
+			return nil, err
+		}
+		var zs []byte
+		if sender != nil {
+			if zs, err = sender.ECDH(rk.pub); err != nil {
+				return nil, err
+			}
+		}
+		kek, err := deriveKEK(he, ze, zs, s.tag)
+		if err != nil {
+			return nil, err
+		}
+		ek, err := jwe.KeyWrap(kek, cek)
+		wipe(kek)
+		if err != nil {
+			return nil, err
+		}
+		env.Recipients = append(env.Recipients, jwe.Recipient{
+			Header:       &jwe.Header{KeyID: rk.kid},
+			EncryptedKey: b64.EncodeToString(ek),
+		})
+	}
+	return json.Marshal(env)
+}
+
+// Decrypt an envelope using one of the keys registered on the messenger.
+// Returns the plaintext, the protected header and the key used.
+func (m *Messenger) decrypt(env *jwe.Message) ([]byte, *envelopeHeader, string, error) {
+	js, err := b64.DecodeString(env.Protected)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "invalid protected header")
+	}
+	he := new(envelopeHeader)
+	if err = json.Unmarshal(js, he); err != nil {
+		return nil, nil, "", errors.Wrap(err, "invalid protected header")
+	}
+	switch {
+	case he.Algorithm == algAnon && (he.Encryption == encAnon || he.Encryption == encAuth):
+	case he.Algorithm == algAuth && he.Encryption == encAuth:
+		if he.PartyUInfo != b64.EncodeToString([]byte(he.SenderKeyID)) {
+			return nil, nil, "", errors.New("invalid 'apu' value")
+		}
+	default:
+		return nil, nil, "", errors.Errorf("unsupported algorithms '%s' and '%s'", he.Algorithm, he.Encryption)
+	}
+	kids := make([]string, len(env.Recipients))
+	for i, rec := range env.Recipients {
+		if rec.Header != nil {
+			kids[i] = rec.Header.KeyID
+		}
+	}
+	if he.PartyVInfo != partyVInfo(kids) {
+		return nil, nil, "", errors.New("invalid 'apv' value")
+	}
+	epk, err := he.EphemeralKey.public()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// Select recipient
+	var (
+		kid  string
+		priv *ecdh.PrivateKey
+		ek   []byte
+	)
+	for i, k := range kids {
+		if pk, ok := m.keys[k]; ok {
+			if ek, err = b64.DecodeString(env.Recipients[i].EncryptedKey); err != nil {
+				return nil, nil, "", errors.Wrap(err, "invalid encrypted key")
+			}
+			kid, priv = k, pk
+			break
+		}
+	}
+	if priv == nil {
+		return nil, nil, "", errors.New("no recipient key available")
+	}
+
+	// Recover content encryption key
+	ze, err := priv.ECDH(epk)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	var zs []byte
+	if he.Algorithm == algAuth {
+		sender, err := m.agreementKeys(he.SenderKeyID)
+		if err != nil {
+			return nil, nil, "", errors.Wrap(err, "invalid sender key")
+		}
+		if zs, err = priv.ECDH(sender[0].pub); err != nil {
+			return nil, nil, "", err
+		}
+	}
+	s := new(sealed)
+	if s.iv, err = b64.DecodeString(env.IV); err != nil {
+		return nil, nil, "", errors.Wrap(err, "invalid initialization vector")
+	}
+	if s.ciphertext, err = b64.DecodeString(env.Ciphertext); err != nil {
+		return nil, nil, "", errors.Wrap(err, "invalid ciphertext")
+	}
+	if s.tag, err = b64.DecodeString(env.Tag); err != nil {
+		return nil, nil, "", errors.Wrap(err, "invalid authentication tag")
+	}
+	kek, err := deriveKEK(he, ze, zs, s.tag)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer wipe(kek)
+	cek, err := jwe.KeyUnwrap(kek, ek)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed to decrypt content encryption key")
+	}
+	defer wipe(cek)
+	if len(cek) != cekSize(he.Encryption) {
+		return nil, nil, "", errors.New("invalid content encryption key")
+	}
+	pt, err := open(he.Encryption, cek, []byte(env.Protected), s)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "failed to decrypt message")
+	}
+	return pt, he, kid, nil
+}
+
+// Resolve the X25519 key agreement keys available for `ref`. If `ref` is
+// a DID URL, only the key it references is returned.
+func (m *Messenger) agreementKeys(ref string) ([]recipientKey, error) {
+	id, err := did.Parse(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid identifier '%s'", ref)
+	}
+	res, err := m.resolver.Resolve(id.DID(), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve '%s'", id.DID())
+	}
+	var keys []recipientKey
+	for _, kid := range res.Document.KeyAgreement {
+		if id.Fragment() != "" && kid != ref {
+			continue
+		}
+		for _, vm := range res.Document.VerificationMethod {
+			if vm.ID != kid || vm.Type != did.KeyTypeX25519 {
+				continue
+			}
+			raw, err := vm.Bytes()
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid key '%s'", kid)
+			}
+			pub, err := ecdh.X25519().NewPublicKey(raw)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid key '%s'", kid)
+			}
+			keys = append(keys, recipientKey{kid: kid, pub: pub})
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("no key agreement keys available for '%s'", ref)
+	}
+	return keys, nil
+}
+
+// Return a registered key agreement key for the sender DID, if any.
+func (m *Messenger) senderKey(from string) (string, *ecdh.PrivateKey) {
+	kids := make([]string, 0, len(m.keys))
+	for kid := range m.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		if ref, err := did.Parse(kid); err == nil && ref.DID() == from {
+			return kid, m.keys[kid]
+		}
+	}
+	return "", nil
+}
+
+// Verify the recipient key belongs to one of the message recipients.
+func addressed(to []string, kid string) bool {
+	ref, err := did.Parse(kid)
+	if err != nil {
+		return false
+	}
+	for _, t := range to {
+		if t == kid || t == ref.DID() {
+			return true
+		}
+	}
+	return false
+}
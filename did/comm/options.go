@@ -0,0 +1,53 @@
+package comm
+
+import (
+	"crypto/ecdh"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// Option elements provide a functional-style configuration mechanism for
+// messenger instances.
+type Option func(m *Messenger) error
+
+// WithResolver sets the resolver used to retrieve the DID documents of
+// message senders and recipients.
+func WithResolver(r Resolver) Option {
+	return func(m *Messenger) error {
+		if r == nil {
+			return errors.New("invalid resolver")
+		}
+		m.resolver = r
+		return nil
+	}
+}
+
+// WithIdentity registers the "keyAgreement" keys of a DID controlled by the
+// messenger. The identifier must include the private X25519 keys; these
+// are used to decrypt received messages and to authenticate sent messages.
+// Can be provided multiple times.
+func WithIdentity(id *did.Identifier) Option {
+	return func(m *Messenger) error {
+		if id == nil {
+			return errors.New("invalid identifier")
+		}
+		added := 0
+		for _, ref := range id.Document(false).KeyAgreement {
+			vm := id.VerificationMethod(ref)
+			if vm == nil || vm.Type != did.KeyTypeX25519 || len(vm.Private) == 0 {
+				continue
+			}
+			priv, err := ecdh.X25519().NewPrivateKey(vm.Private)
+			if err != nil {
+				return errors.Wrapf(err, "invalid key '%s'", vm.ID)
+			}
+			m.keys[vm.ID] = priv
+			added++
+		}
+		if added == 0 {
+			return errors.Errorf("no X25519 key agreement keys available for '%s'", id.DID())
+		}
+		return nil
+	}
+}
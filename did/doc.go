@@ -119,6 +119,101 @@ JSON-LD signatures produce a document similar to the following.
 	  "signatureValue": "9coFFyo3Vgq+HJg5yj+QRyub9/5A2sGUfc8ermPV9LEgmV+/Q79jX84ktKo8ZPo0T9MT5TCb/STNGeKBXqbZCw=="
 	}
 
+Keys held by an external signer, like an HSM or cloud KMS, can be used to produce signatures
+and proofs. See the "crypto/signer" package. The private key is never included in the DID
+document.
+
+	s, _ := hsm.Signer("master-key")
+	_ = id.AddSignerVerificationMethod("hsm", s)
+
+	// Signer can be attached to existing keys, for example after decoding a document
+	_ = id.VerificationMethod("hsm").UseSigner(s)
+
+# did:key
+
+Self-certifying identifiers using the "key" method include the public key
+material on the DID itself, so they can be resolved without a verifiable data
+registry. Ed25519, X25519, secp256k1, P-256 and BLS12-381 (BBS) keys are
+supported. Verification methods use the same multicodec-prefixed
+"publicKeyMultibase" representation, see 'EncodeMultibaseKey'.
+
+	// Generate a new identifier; includes the private key
+	id, _ := NewKeyIdentifier(KeyTypeP256)
+
+	// Resolve an existing identifier; includes only the public key
+	peer, _ := ResolveKeyIdentifier("did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169")
+
+# did:peer
+
+Peer identifiers are derived from key material and service endpoints, without
+a verifiable data registry; useful for pairwise relationships, for example on
+DIDComm flows. Identifiers using a single inception key (numalgo 0) or multiple
+keys and services (numalgo 2) are supported.
+
+	id, _ := NewPeerIdentifierWithKeys([]PeerKey{
+		{Type: KeyTypeEd, Purpose: AuthenticationVM},
+		{Type: KeyTypeX25519, Purpose: KeyAgreementVM},
+	}, ServiceEndpoint{
+		Type:     "DIDCommMessaging",
+		Endpoint: "https://example.com/didcomm",
+	})
+
+	// Resolve an existing identifier; includes only the public keys
+	peer, _ := ResolvePeerIdentifier(id.DID())
+
+# Key Rotation
+
+The cryptographic material used by a verification method can be rotated while
+preserving its identifier and verification relationships. Each rotation is
+recorded, including a proof produced by the previous key, so signatures can
+be verified against the key that was valid when they were produced.
+
+	rotation, _ := id.RotateVerificationMethod("master", KeyTypeEd)
+	valid := id.VerifyAt("master", data, signature, signedAt)
+
+# Selective Disclosure
+
+BBS keys ("KeyTypeBBS") produce a single signature over a list of messages; for
+example, the individual claims of a credential. The holder of the signature can
+then derive zero-knowledge proofs revealing only some of the messages. Proofs
+are bound to a nonce provided by the verifier and are unlinkable to each other.
+
+	key := id.VerificationMethod("bbs")
+	signature, _ := key.SignMessages(claims)
+
+	// Holder discloses only the third claim
+	proof, _ := key.DeriveProof(signature, claims, []int{2}, nonce)
+
+	// Verifier
+	ok := key.VerifyDerivedProof(proof, map[int][]byte{2: claims[2]}, nonce)
+
+# Services
+
+Service endpoints are usually expressed as a URI. Some service types use a
+structured endpoint instead, set using the 'Value' field. Well-known service
+types (LinkedDomains, DIDCommMessaging and CredentialRegistry) provide typed
+accessors and are validated accordingly; additional types can be registered
+using 'RegisterServiceType'.
+
+	_ = id.AddService(&ServiceEndpoint{
+		ID:   "didcomm",
+		Type: ServiceDIDCommMessaging,
+		Value: DIDCommEndpoint{
+			URI:         "https://example.com/didcomm",
+			RoutingKeys: []string{"did:example:mediator#key-1"},
+		},
+	})
+	endpoints, _ := id.Service("didcomm").DIDCommMessaging()
+
+# Validation
+
+DID documents can be validated for conformance with the DID core specification
+before being published. All violations found are returned.
+
+	for _, v := range id.Document(true).Validate() {
+		fmt.Println(v.Property, v.Message)
+	}
+
 More information:
 https://w3c-ccg.github.io/did-spec/
 */
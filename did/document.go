@@ -179,6 +179,13 @@ type ServiceEndpoint struct {
 	// Main URL for interactions.
 	Endpoint string `json:"serviceEndpoint" yaml:"serviceEndpoint"`
 
+	// Structured endpoint value; when set, it's used as the `serviceEndpoint`
+	// value instead of 'Endpoint'. Useful for services that express their
+	// endpoint as a map or a set; for example a "DIDCommMessaging" service
+	// including routing keys. When decoding a structured endpoint, 'Endpoint'
+	// is set to its primary URI.
+	Value interface{} `json:"-" yaml:"-"`
+
 	// Extensions used on the service endpoint instance.
 	Extensions []Extension `json:"extensions,omitempty" yaml:"extensions,omitempty"`
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"go.bryk.io/pkg/crypto/signer"
 	"go.bryk.io/pkg/errors"
 )
 
@@ -96,6 +97,9 @@ type identifierData struct {
 	// Service endpoints enabled.
 	Services []*ServiceEndpoint
 
+	// Verification method rotations, sorted from oldest to newest.
+	Rotations []*KeyRotation
+
 	// Time of original creation normalized to UTC 00:00.
 	Created *time.Time
 
@@ -389,6 +393,30 @@ func (d *Identifier) AddVerificationMethod(id string, private []byte, kt KeyType
 	return nil
 }
 
+// AddSignerVerificationMethod attach a cryptographic key held by an external
+// signer to the identifier; for example, a key stored in an HSM or cloud KMS.
+// The key type is selected based on the signer's public key. Ed25519, RSA,
+// secp256k1 and P-256 keys are supported.
+func (d *Identifier) AddSignerVerificationMethod(id string, s signer.Signer) error {
+	if !strings.HasPrefix(id, prefix) {
+		id = d.GetReference(id)
+	}
+	for _, k := range d.data.VerificationMethods {
+		if k.ID == id {
+			return errors.New("duplicated key identifier")
+		}
+	}
+	pk, err := newSignerKey(s)
+	if err != nil {
+		return err
+	}
+	pk.Controller = d.DID()
+	pk.ID = id
+	d.data.VerificationMethods = append(d.data.VerificationMethods, pk)
+	d.update()
+	return nil
+}
+
 // RemoveVerificationMethod will permanently eliminate a registered key from the
 // instance. An error will be produced if the key you're trying to remove is the
 // only enabled authentication key.
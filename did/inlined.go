@@ -9,9 +9,9 @@ import (
 
 // Returns true if a byte is not allowed in a ID from the grammar:
 //
-//	idchar = ALPHA / DIGIT / "." / "-"
+//	idchar = ALPHA / DIGIT / "." / "-" / "_"
 func isNotValidIDChar(char byte) bool {
-	return isNotAlpha(char) && isNotDigit(char) && char != '.' && char != '-'
+	return isNotAlpha(char) && isNotDigit(char) && char != '.' && char != '-' && char != '_'
 }
 
 // isNotValidParamChar returns true if a byte is not allowed in a param-name
@@ -11,7 +11,9 @@ import (
 
 	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"go.bryk.io/pkg/crypto/bbs"
 	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/signer"
 	"go.bryk.io/pkg/errors"
 	e "golang.org/x/crypto/ed25519"
 )
@@ -42,6 +44,9 @@ type VerificationKey struct {
 
 	// Private portion of the cryptographic key.
 	Private []byte `json:"private,omitempty" yaml:"private,omitempty"`
+
+	// External signer holding the private key, if any.
+	sg signer.Signer
 }
 
 // String uses the key ID value as its textual representation.
@@ -195,6 +200,9 @@ func (k *VerificationKey) GetExtension(id string, version string, holder interfa
 
 // Sign the provided data.
 func (k *VerificationKey) sign(data []byte) ([]byte, error) {
+	if k.sg != nil {
+		return k.signWithSigner(data)
+	}
 	if len(k.Private) == 0 {
 		return nil, errors.New("no private key available")
 	}
@@ -220,6 +228,12 @@ func (k *VerificationKey) sign(data []byte) ([]byte, error) {
 			return nil, errors.New("failed to sign message")
 		}
 		return ss.Serialize(), nil
+	case KeyTypeP256:
+		return p256Sign(k.Private, data)
+	case KeyTypeBBS:
+		return bbs.Sign(k.Private, nil, [][]byte{data})
+	case KeyTypeX25519:
+		return nil, errors.New("key agreement keys can't produce signatures")
 	default:
 		return nil, errors.New("invalid key type")
 	}
@@ -259,6 +273,10 @@ func (k *VerificationKey) verify(data, signature []byte) bool {
 			return false
 		}
 		return sig.Verify(data, pub)
+	case KeyTypeP256:
+		return p256Verify(pubBytes, data, signature)
+	case KeyTypeBBS:
+		return bbs.Verify(pubBytes, signature, nil, [][]byte{data})
 	default:
 		return false
 	}
@@ -296,6 +314,26 @@ func newCryptoKey(kt KeyType) (*VerificationKey, error) {
 		}
 		pk.Private = key.Serialize()
 		pub = key.PubKey().SerializeCompressed()
+	case KeyTypeP256:
+		var err error
+		pub, pk.Private, err = newP256Key()
+		if err != nil {
+			return nil, wrap(err, "failed to create new P-256 key")
+		}
+	case KeyTypeX25519:
+		var err error
+		pub, pk.Private, err = newX25519Key()
+		if err != nil {
+			return nil, wrap(err, "failed to create new X25519 key")
+		}
+	case KeyTypeBBS:
+		var err error
+		pk.Private, pub, err = bbs.GenerateKey()
+		if err != nil {
+			return nil, wrap(err, "failed to create new BLS12-381 key")
+		}
+	default:
+		return nil, errors.New("invalid key type")
 	}
 
 	// Set encoded value
@@ -337,6 +375,23 @@ func loadExistingKey(private []byte, kt KeyType) (*VerificationKey, error) {
 		if err != nil {
 			return nil, err
 		}
+	case KeyTypeP256:
+		pub, err = validateKeyP256(private, challenge)
+		if err != nil {
+			return nil, err
+		}
+	case KeyTypeX25519:
+		pub, err = validateKeyX25519(private)
+		if err != nil {
+			return nil, err
+		}
+	case KeyTypeBBS:
+		pub, err = validateKeyBBS(private, challenge)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("invalid key type")
 	}
 
 	// Set encoded public key value
@@ -0,0 +1,79 @@
+package did
+
+import (
+	"go.bryk.io/pkg/crypto/bbs"
+	"go.bryk.io/pkg/errors"
+)
+
+// SignMessages produces a single signature over the ordered list of
+// `messages`; for example, the individual claims of a credential. Only
+// available for BBS keys.
+func (k *VerificationKey) SignMessages(messages [][]byte) ([]byte, error) {
+	if k.Type != KeyTypeBBS {
+		return nil, errors.New("multi-message signatures require a BBS key")
+	}
+	if len(k.Private) == 0 {
+		return nil, errors.New("no private key available")
+	}
+	return bbs.Sign(k.Private, nil, messages)
+}
+
+// VerifyMessages validates a signature produced with 'SignMessages' over
+// the ordered list of `messages`.
+func (k *VerificationKey) VerifyMessages(messages [][]byte, signature []byte) bool {
+	pub, err := k.bbsPublicKey()
+	if err != nil {
+		return false
+	}
+	return bbs.Verify(pub, signature, nil, messages)
+}
+
+// DeriveProof generates a zero-knowledge proof of knowledge of a signature
+// produced with 'SignMessages', revealing only the messages at the
+// `disclosed` positions (zero-based). The `nonce` value, usually provided by
+// the verifier, is bound to the proof to prevent replay attacks. Only the
+// public key is required, so proofs can be derived by the holder of the
+// signature. Each proof is randomized, so multiple proofs derived from the
+// same signature are unlinkable.
+func (k *VerificationKey) DeriveProof(signature []byte, messages [][]byte, disclosed []int, nonce []byte) ([]byte, error) {
+	pub, err := k.bbsPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return bbs.DeriveProof(pub, signature, nil, nonce, messages, disclosed)
+}
+
+// VerifyDerivedProof validates a proof produced with 'DeriveProof';
+// `disclosed` includes the revealed messages by position (zero-based).
+func (k *VerificationKey) VerifyDerivedProof(proof []byte, disclosed map[int][]byte, nonce []byte) bool {
+	pub, err := k.bbsPublicKey()
+	if err != nil {
+		return false
+	}
+	return bbs.VerifyProof(pub, proof, nil, nonce, disclosed)
+}
+
+// Public key bytes for BBS keys.
+func (k *VerificationKey) bbsPublicKey() ([]byte, error) {
+	if k.Type != KeyTypeBBS {
+		return nil, errors.New("selective disclosure requires a BBS key")
+	}
+	return k.Bytes()
+}
+
+// Validate the provided 'private' key is a BLS12-381 BBS key. Return the
+// corresponding public key (compressed G2 point).
+func validateKeyBBS(private, challenge []byte) ([]byte, error) {
+	pub, err := bbs.PublicKey(private)
+	if err != nil {
+		return nil, wrap(err, "invalid BLS12-381 private key")
+	}
+	s, err := bbs.Sign(private, nil, [][]byte{challenge})
+	if err != nil {
+		return nil, err
+	}
+	if !bbs.Verify(pub, s, nil, [][]byte{challenge}) {
+		return nil, errors.New("invalid BLS12-381 private key")
+	}
+	return pub, nil
+}
@@ -0,0 +1,60 @@
+package did
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestSelectiveDisclosure(t *testing.T) {
+	assert := tdd.New(t)
+	id, err := NewIdentifierWithMode("bryk", "", ModeUUID)
+	assert.Nil(err, "new identifier")
+	assert.Nil(id.AddNewVerificationMethod("bbs", KeyTypeBBS), "add key")
+	assert.Nil(id.AddVerificationRelationship(id.GetReference("bbs"), AssertionVM))
+	key := id.VerificationMethod("bbs")
+	assert.Equal("Multikey", key.Type.String())
+	assert.Equal("DataIntegrityProof", key.Type.SignatureType())
+	assert.NotEmpty(key.Public)
+	assert.Empty(id.Document(true).Validate(), "valid document")
+
+	// Single message signatures
+	msg := []byte("sample message")
+	sig, err := key.Sign(msg)
+	assert.Nil(err, "sign")
+	assert.True(key.Verify(msg, sig), "verify")
+	proof, err := key.ProduceProof(msg, "assertionMethod", "did.bryk.io")
+	assert.Nil(err, "produce proof")
+	assert.True(key.VerifyProof(msg, proof), "verify proof")
+
+	// Restore key
+	restored, err := loadExistingKey(key.Private, KeyTypeBBS)
+	assert.Nil(err, "load existing key")
+	assert.Equal(key.Public, restored.Public)
+
+	// Multi-message signature
+	claims := [][]byte{
+		[]byte("name: Jane Doe"),
+		[]byte("birthdate: 1990-01-01"),
+		[]byte("nationality: MX"),
+	}
+	sig, err = key.SignMessages(claims)
+	assert.Nil(err, "sign messages")
+	assert.True(key.VerifyMessages(claims, sig), "verify messages")
+
+	// Holder derives a proof using only the public key
+	pub := id.Document(true).VerificationMethod[0]
+	nonce := []byte("verifier-nonce")
+	dp, err := pub.DeriveProof(sig, claims, []int{2}, nonce)
+	assert.Nil(err, "derive proof")
+	assert.True(pub.VerifyDerivedProof(dp, map[int][]byte{2: claims[2]}, nonce), "verify derived proof")
+	assert.False(pub.VerifyDerivedProof(dp, map[int][]byte{2: claims[1]}, nonce), "invalid message")
+	assert.False(pub.VerifyDerivedProof(dp, map[int][]byte{2: claims[2]}, nil), "invalid nonce")
+
+	// Other key types
+	assert.Nil(id.AddNewVerificationMethod("master", KeyTypeEd), "add key")
+	_, err = id.VerificationMethod("master").SignMessages(claims)
+	assert.NotNil(err, "invalid key type")
+	_, err = id.VerificationMethod("master").DeriveProof(sig, claims, []int{0}, nonce)
+	assert.NotNil(err, "invalid key type")
+}
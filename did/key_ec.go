@@ -0,0 +1,95 @@
+package did
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Returns a new P-256 key pair. The private key is returned as its 32
+// bytes scalar value and the public key in compressed form.
+func newP256Key() (pub []byte, priv []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	priv = key.D.FillBytes(make([]byte, 32))
+	pub = elliptic.MarshalCompressed(elliptic.P256(), key.X, key.Y)
+	return pub, priv, nil
+}
+
+// Load a P-256 private key from its scalar value.
+func p256PrivateKey(private []byte) (*ecdsa.PrivateKey, error) {
+	sk, err := ecdh.P256().NewPrivateKey(private)
+	if err != nil {
+		return nil, wrap(err, "invalid P-256 private key")
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), sk.PublicKey().Bytes()) // nolint: staticcheck
+	if x == nil {
+		return nil, errors.New("invalid P-256 private key")
+	}
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+		D:         new(big.Int).SetBytes(private),
+	}, nil
+}
+
+// Produce an ASN.1 encoded signature for the SHA256 hashed data.
+func p256Sign(private, data []byte) ([]byte, error) {
+	key, err := p256PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	return ecdsa.SignASN1(rand.Reader, key, getHash(data))
+}
+
+// Verify an ASN.1 encoded signature using a compressed public key.
+func p256Verify(pub, data, signature []byte) bool {
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pub)
+	if x == nil {
+		return false
+	}
+	pk := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	return ecdsa.VerifyASN1(pk, getHash(data), signature)
+}
+
+// Validate the provided 'private' key is P-256. Return the corresponding
+// public key (compressed).
+func validateKeyP256(private, challenge []byte) ([]byte, error) {
+	key, err := p256PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	pub := elliptic.MarshalCompressed(elliptic.P256(), key.X, key.Y)
+	s, err := p256Sign(private, challenge)
+	if err != nil {
+		return nil, err
+	}
+	if !p256Verify(pub, challenge, s) {
+		return nil, errors.New("invalid P-256 private key")
+	}
+	return pub, nil
+}
+
+// Returns a new X25519 key pair.
+func newX25519Key() (pub []byte, priv []byte, err error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key.PublicKey().Bytes(), key.Bytes(), nil
+}
+
+// Validate the provided 'private' key is X25519. Return the corresponding
+// public key.
+func validateKeyX25519(private []byte) ([]byte, error) {
+	key, err := ecdh.X25519().NewPrivateKey(private)
+	if err != nil {
+		return nil, wrap(err, "invalid X25519 private key")
+	}
+	return key.PublicKey().Bytes(), nil
+}
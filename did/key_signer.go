@@ -0,0 +1,116 @@
+package did
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	stded25519 "crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"go.bryk.io/pkg/crypto/signer"
+	"go.bryk.io/pkg/errors"
+)
+
+// UseSigner sets an external signer to produce the signatures, proofs and
+// LD signatures for the key; for example, a key stored in an HSM or cloud KMS.
+// The signer's public key must match the key's public material, and the
+// 'Private' value is no longer used. The signer is not included when the key
+// is encoded, it must be set again after decoding the key.
+func (k *VerificationKey) UseSigner(s signer.Signer) error {
+	kt, pub, err := signerPublicKey(s)
+	if err != nil {
+		return err
+	}
+	current, err := k.Bytes()
+	if err != nil {
+		return err
+	}
+	if kt != k.Type || !bytes.Equal(pub, current) {
+		return errors.New("signer doesn't match the key's public material")
+	}
+	k.sg = s
+	return nil
+}
+
+// Build a verification key for the provided signer; the key type is selected
+// based on the signer's public key. The signer is validated by producing and
+// verifying a signature for a random challenge.
+func newSignerKey(s signer.Signer) (*VerificationKey, error) {
+	kt, pub, err := signerPublicKey(s)
+	if err != nil {
+		return nil, err
+	}
+	pk := &VerificationKey{Type: kt, sg: s}
+	kt.EncodePublicKey(pk, pub)
+
+	// Use a challenge to validate key usage
+	challenge := make([]byte, 32)
+	if _, err = rand.Read(challenge); err != nil {
+		return nil, wrap(err, "failed to create challenge")
+	}
+	sig, err := pk.sign(challenge)
+	if err != nil {
+		return nil, wrap(err, "failed to produce signature")
+	}
+	if !pk.verify(challenge, sig) {
+		return nil, errors.New("invalid signature produced by signer")
+	}
+	return pk, nil
+}
+
+// Produce a signature for `data` using the external signer. Signatures are
+// equivalent to the ones produced with the private key.
+func (k *VerificationKey) signWithSigner(data []byte) ([]byte, error) {
+	switch k.Type {
+	case KeyTypeEd:
+		return k.sg.Sign(rand.Reader, data, crypto.Hash(0))
+	case KeyTypeRSA, KeyTypeP256:
+		return k.sg.Sign(rand.Reader, getHash(data), crypto.SHA256)
+	case KeyTypeSecp256k1:
+		// The input is used directly as the value to sign, only its first 32
+		// bytes are used and shorter values are interpreted as big-endian
+		// integers.
+		digest := make([]byte, 32)
+		if len(data) >= len(digest) {
+			copy(digest, data)
+		} else {
+			copy(digest[len(digest)-len(data):], data)
+		}
+		return k.sg.Sign(rand.Reader, digest, crypto.SHA256)
+	default:
+		return nil, errors.New("key type not supported by signers")
+	}
+}
+
+// Return the key type and public key bytes for the provided signer.
+func signerPublicKey(s signer.Signer) (KeyType, []byte, error) {
+	switch pub := s.Public().(type) {
+	case stded25519.PublicKey:
+		return KeyTypeEd, []byte(pub), nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return KeyTypeP256, elliptic.MarshalCompressed(pub.Curve, pub.X, pub.Y), nil
+		case secp.S256():
+			var x, y secp.FieldVal
+			x.SetByteSlice(pub.X.Bytes())
+			y.SetByteSlice(pub.Y.Bytes())
+			return KeyTypeSecp256k1, secp.NewPublicKey(&x, &y).SerializeCompressed(), nil
+		default:
+			return 0, nil, errors.New("unsupported curve")
+		}
+	case *rsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return 0, nil, err
+		}
+		return KeyTypeRSA, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	default:
+		return 0, nil, errors.Errorf("unsupported public key type: %T", pub)
+	}
+}
@@ -0,0 +1,85 @@
+package did
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	stded25519 "crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/secp256k1"
+	"go.bryk.io/pkg/crypto/signer"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// Signer backed by a standard library key, used to simulate a key held by
+// an HSM.
+type hsmKey struct {
+	crypto.Signer
+	alg jwa.Alg
+}
+
+func (k *hsmKey) ID() string {
+	return "hsm-key"
+}
+
+func (k *hsmKey) Algorithm() jwa.Alg {
+	return k.alg
+}
+
+func TestSignerVerificationMethod(t *testing.T) {
+	assert := tdd.New(t)
+	_, edKey, _ := stded25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	kp, _ := secp256k1.New()
+	defer kp.Destroy()
+	signers := map[KeyType]signer.Signer{
+		KeyTypeEd:        &hsmKey{Signer: edKey, alg: jwa.EdDSA},
+		KeyTypeP256:      &hsmKey{Signer: ecKey, alg: jwa.ES256},
+		KeyTypeRSA:       &hsmKey{Signer: rsaKey, alg: jwa.RS256},
+		KeyTypeSecp256k1: signer.Secp256k1("hsm-key", kp),
+	}
+	data := []byte("document to sign")
+	for kt, s := range signers {
+		id, err := NewIdentifierWithMode("bryk", "", ModeUUID)
+		if !assert.Nil(err, "new identifier") {
+			return
+		}
+		assert.Nil(id.AddSignerVerificationMethod("hsm", s), "add signer: %s", kt)
+		assert.NotNil(id.AddSignerVerificationMethod("hsm", s), "duplicated key")
+		key := id.VerificationMethod("hsm")
+		if !assert.NotNil(key, "get key: %s", kt) {
+			continue
+		}
+		assert.Equal(kt, key.Type)
+		assert.Empty(key.Private, "no private key")
+
+		// Proofs and signatures
+		proof, err := key.ProduceProof(data, "authentication", "bryk.io")
+		assert.Nil(err, "produce proof: %s", kt)
+		assert.True(key.VerifyProof(data, proof), "verify proof: %s", kt)
+		sig, err := key.ProduceSignatureLD(data, "bryk.io")
+		assert.Nil(err, "produce signature: %s", kt)
+		assert.True(key.VerifySignatureLD(data, sig), "verify signature: %s", kt)
+
+		// Public key only instances can be attached to the signer
+		pub := *key
+		pub.sg = nil
+		_, err = pub.Sign(data)
+		assert.NotNil(err, "no private key")
+		assert.Nil(pub.UseSigner(s), "use signer: %s", kt)
+		sv, err := pub.Sign(data)
+		assert.Nil(err, "sign: %s", kt)
+		assert.True(key.Verify(data, sv), "verify: %s", kt)
+	}
+
+	// Signer must match the key
+	id, _ := NewIdentifierWithMode("bryk", "", ModeUUID)
+	assert.Nil(id.AddNewVerificationMethod("master", KeyTypeP256))
+	err := id.VerificationMethod("master").UseSigner(signers[KeyTypeP256])
+	assert.NotNil(err, "invalid signer")
+}
@@ -23,6 +23,22 @@ const (
 	// KeyTypeSecp256k1 specify an ECDSA secp256k1 keypair.
 	// https://w3c-dvcg.github.io/lds-ecdsa-secp256k1-2019/
 	KeyTypeSecp256k1
+
+	// KeyTypeX25519 specify a X25519 keypair. These keys can only be used
+	// for key agreement, not to produce signatures.
+	// https://w3c-ccg.github.io/lds-x25519-2020/
+	KeyTypeX25519
+
+	// KeyTypeP256 specify an ECDSA P-256 (secp256r1) keypair.
+	// https://www.w3.org/TR/vc-di-ecdsa/
+	KeyTypeP256
+
+	// KeyTypeBBS specify a BLS12-381 (G2) keypair used to produce BBS
+	// signatures. A single signature covers a list of messages, and
+	// zero-knowledge proofs can be derived from it to selectively disclose
+	// only some of them. Keys are represented using the "Multikey" format.
+	// https://www.w3.org/TR/vc-di-bbs/
+	KeyTypeBBS
 )
 
 // String returns the value identifier for a given key type value.
@@ -31,8 +47,11 @@ func (v KeyType) String() string {
 		"Ed25519VerificationKey2020",
 		"RsaVerificationKey2018",
 		"EcdsaSecp256k1VerificationKey2019",
+		"X25519KeyAgreementKey2020",
+		"EcdsaSecp256r1VerificationKey2019",
+		"Multikey",
 	}
-	if int(v) > len(values) {
+	if int(v) >= len(values) {
 		return "unknown key type"
 	}
 	return values[v]
@@ -45,17 +64,26 @@ func (v KeyType) SignatureType() string {
 		"Ed25519Signature2020",
 		"RsaSignature2018",
 		"EcdsaSecp256k1Signature2019",
+		"unknown signature type", // key agreement only
+		"EcdsaSecp256r1Signature2019",
+		"DataIntegrityProof",
 	}
-	if int(v) > len(values) {
+	if int(v) >= len(values) {
 		return "unknown signature type"
 	}
 	return values[v]
 }
 
 // EncodePublicKey adjust the `vk` verification key to properly
-// encode its public bytes representation.
+// encode its public bytes representation. Key types using the
+// `publicKeyMultibase` representation include the multicodec prefix
+// for the key type.
 func (v KeyType) EncodePublicKey(vk *VerificationKey, pub []byte) {
-	if v == KeyTypeEd {
+	if v.multibase() {
+		if value, err := EncodeMultibaseKey(v, pub); err == nil {
+			vk.Public = value
+			return
+		}
 		vk.Public = multibaseEncode(pub)
 		return
 	}
@@ -63,14 +91,51 @@ func (v KeyType) EncodePublicKey(vk *VerificationKey, pub []byte) {
 }
 
 // DecodePublicKey returns public key byte representation for the
-// provided verification key instance.
+// provided verification key instance. The `publicKeyMultibase`
+// representation is supported for all key types, with or without
+// multicodec prefix.
 func (v KeyType) DecodePublicKey(vk *VerificationKey) ([]byte, error) {
-	if v == KeyTypeEd {
-		return multibaseDecode(vk.Public)
+	if vk.Public != "" {
+		src, err := multibaseDecode(vk.Public)
+		if err != nil {
+			return nil, err
+		}
+		if kt, pub, err := multicodecDecode(src); err == nil && kt == v {
+			return pub, nil
+		}
+		return src, nil // no multicodec prefix
 	}
 	return base58.Decode(vk.PublicKeyBase58)
 }
 
+// EncodeMultibaseKey returns the `publicKeyMultibase` representation for
+// a public key; i.e., the key prefixed with the multicodec identifier for
+// its type and encoded as base58-btc. This is the same representation
+// used by "did:key" identifiers.
+// https://w3c-ccg.github.io/did-method-key/#format
+func EncodeMultibaseKey(kt KeyType, pub []byte) (string, error) {
+	mc, err := multicodecEncode(kt, pub)
+	if err != nil {
+		return "", err
+	}
+	return multibaseEncode(mc), nil
+}
+
+// DecodeMultibaseKey returns the key type and public key bytes for a
+// multicodec-prefixed `publicKeyMultibase` value.
+func DecodeMultibaseKey(value string) (KeyType, []byte, error) {
+	src, err := multibaseDecode(value)
+	if err != nil {
+		return 0, nil, wrap(err, "invalid multibase value")
+	}
+	return multicodecDecode(src)
+}
+
+// Key types using the `publicKeyMultibase` representation.
+func (v KeyType) multibase() bool {
+	return v == KeyTypeEd || v == KeyTypeX25519 || v == KeyTypeP256 || v == KeyTypeBBS
+}
+
 // MarshalJSON provides custom encoding implementation.
 func (v *KeyType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(v.String())
@@ -120,6 +185,15 @@ func keyTypeFromString(val string) (kt KeyType, err error) {
 	case KeyTypeSecp256k1.String():
 		kt = KeyTypeSecp256k1
 		return
+	case KeyTypeX25519.String():
+		kt = KeyTypeX25519
+		return
+	case KeyTypeP256.String():
+		kt = KeyTypeP256
+		return
+	case KeyTypeBBS.String():
+		kt = KeyTypeBBS
+		return
 	default:
 		err = fmt.Errorf("unknown key type: %s", val)
 		return
@@ -0,0 +1,148 @@
+package did
+
+import (
+	"encoding/binary"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// KeyMethod is the method name used by "did:key" identifiers. These
+// identifiers are self-certifying; the DID itself contains the public key
+// material, so no verifiable data registry is required to resolve them.
+// https://w3c-ccg.github.io/did-method-key/
+const KeyMethod = "key"
+
+// Multicodec identifiers for supported public key types.
+// https://github.com/multiformats/multicodec/blob/master/table.csv
+var multicodecKeyTypes = map[uint64]KeyType{
+	0xed:   KeyTypeEd,
+	0xec:   KeyTypeX25519,
+	0xe7:   KeyTypeSecp256k1,
+	0x1200: KeyTypeP256,
+	0xeb:   KeyTypeBBS,
+}
+
+// Expected public key sizes, in bytes, for supported key types. EC keys
+// use the compressed form.
+var multicodecKeySizes = map[KeyType]int{
+	KeyTypeEd:        32,
+	KeyTypeX25519:    32,
+	KeyTypeSecp256k1: 33,
+	KeyTypeP256:      33,
+	KeyTypeBBS:       96,
+}
+
+// NewKeyIdentifier generates a new cryptographic key of type `kt` and
+// returns the corresponding "did:key" identifier. The identifier includes
+// the private key material. Supported key types are: Ed25519, X25519,
+// secp256k1, P-256 and BLS12-381 (BBS).
+func NewKeyIdentifier(kt KeyType) (*Identifier, error) {
+	if _, err := multicodecCode(kt); err != nil {
+		return nil, err
+	}
+	key, err := newCryptoKey(kt)
+	if err != nil {
+		return nil, err
+	}
+	return keyIdentifier(key)
+}
+
+// ResolveKeyIdentifier expands a "did:key" value into an identifier instance
+// including its public verification method.
+func ResolveKeyIdentifier(id string) (*Identifier, error) {
+	ID, err := Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	if ID.Method() != KeyMethod {
+		return nil, errors.Errorf("invalid method: %s", ID.Method())
+	}
+	kt, pub, err := DecodeMultibaseKey(ID.data.ID)
+	if err != nil {
+		return nil, err
+	}
+	key := &VerificationKey{Type: kt}
+	kt.EncodePublicKey(key, pub)
+	return keyIdentifier(key)
+}
+
+// Build the "did:key" identifier for the provided verification key.
+func keyIdentifier(key *VerificationKey) (*Identifier, error) {
+	return inceptionKeyIdentifier(KeyMethod, "", key)
+}
+
+// Build an identifier derived from a single inception key; the
+// method-specific id is the multibase-encoded public key, with an
+// optional tag. Used by "did:key" and "did:peer" (numalgo 0).
+func inceptionKeyIdentifier(method, tag string, key *VerificationKey) (*Identifier, error) {
+	pub, err := key.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	value, err := EncodeMultibaseKey(key.Type, pub)
+	if err != nil {
+		return nil, err
+	}
+	id, err := NewIdentifier(method, tag+value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Identifiers are not registered, creation date is not available
+	id.data.Created = nil
+	key.ID = id.GetReference(value)
+	key.Controller = id.DID()
+	id.data.VerificationMethods = append(id.data.VerificationMethods, key)
+	if key.Type == KeyTypeX25519 {
+		err = id.AddVerificationRelationship(key.ID, KeyAgreementVM)
+		return id, err
+	}
+	for _, vm := range []VerificationRelationship{
+		AuthenticationVM,
+		AssertionVM,
+		CapabilityInvocationVM,
+		CapabilityDelegationVM,
+	} {
+		if err = id.AddVerificationRelationship(key.ID, vm); err != nil {
+			return nil, err
+		}
+	}
+	return id, nil
+}
+
+// Return the multicodec identifier for a key type.
+func multicodecCode(kt KeyType) (uint64, error) {
+	for code, t := range multicodecKeyTypes {
+		if t == kt {
+			return code, nil
+		}
+	}
+	return 0, errors.Errorf("unsupported key type: %s", kt)
+}
+
+// Prefix the public key with the multicodec identifier for its type.
+func multicodecEncode(kt KeyType, pub []byte) ([]byte, error) {
+	code, err := multicodecCode(kt)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, code)
+	return append(buf[:n], pub...), nil
+}
+
+// Return the key type and public key from a multicodec-prefixed value.
+func multicodecDecode(src []byte) (KeyType, []byte, error) {
+	code, n := binary.Uvarint(src)
+	if n <= 0 {
+		return 0, nil, errors.New("invalid multicodec value")
+	}
+	kt, ok := multicodecKeyTypes[code]
+	if !ok {
+		return 0, nil, errors.Errorf("unsupported multicodec key type: 0x%x", code)
+	}
+	if len(src[n:]) != multicodecKeySizes[kt] {
+		return 0, nil, errors.Errorf("invalid public key size for: %s", kt)
+	}
+	return kt, src[n:], nil
+}
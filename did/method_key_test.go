@@ -0,0 +1,112 @@
+package did
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestKeyIdentifier(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Resolve", func(t *testing.T) {
+		// Test vectors from the method specification
+		vectors := map[string]KeyType{
+			"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp":  KeyTypeEd,
+			"did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F":  KeyTypeX25519,
+			"did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme": KeyTypeSecp256k1,
+			"did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169": KeyTypeP256,
+		}
+		for value, kt := range vectors {
+			id, err := ResolveKeyIdentifier(value)
+			assert.Nil(err, "resolve")
+			assert.Equal(value, id.DID())
+			doc := id.Document(true)
+			assert.Len(doc.VerificationMethod, 1, "verification methods")
+			vm := doc.VerificationMethod[0]
+			assert.Equal(kt, vm.Type, "key type")
+			assert.Equal(value+"#"+id.data.ID, vm.ID, "key identifier")
+			if kt == KeyTypeX25519 {
+				assert.Equal([]string{vm.ID}, doc.KeyAgreement)
+				assert.Empty(doc.Authentication)
+			} else {
+				assert.Equal([]string{vm.ID}, doc.Authentication)
+				assert.Equal([]string{vm.ID}, doc.AssertionMethod)
+			}
+		}
+
+		// Invalid values
+		_, err := ResolveKeyIdentifier("did:web:example.com")
+		assert.NotNil(err, "invalid method")
+		_, err = ResolveKeyIdentifier("did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDoo")
+		assert.NotNil(err, "invalid key size")
+		_, err = ResolveKeyIdentifier("did:key:invalid")
+		assert.NotNil(err, "invalid encoding")
+	})
+
+	t.Run("Generate", func(t *testing.T) {
+		for _, kt := range []KeyType{KeyTypeSecp256k1, KeyTypeP256, KeyTypeX25519} {
+			id, err := NewKeyIdentifier(kt)
+			assert.Nil(err, "new identifier")
+			key := id.VerificationMethods()[0]
+			assert.NotEmpty(key.Private, "private key")
+
+			// Resolved document matches the generated one
+			rid, err := ResolveKeyIdentifier(id.DID())
+			assert.Nil(err, "resolve")
+			assert.Equal(id.Document(true), rid.Document(true))
+
+			// Signatures
+			msg := []byte("self-certifying identifiers")
+			sig, err := key.Sign(msg)
+			if kt == KeyTypeX25519 {
+				assert.NotNil(err, "key agreement keys can't sign")
+				continue
+			}
+			assert.Nil(err, "sign")
+			assert.True(rid.VerificationMethods()[0].Verify(msg, sig), "verify")
+		}
+		_, err := NewKeyIdentifier(KeyTypeRSA)
+		assert.NotNil(err, "unsupported key type")
+	})
+
+	t.Run("Multibase", func(t *testing.T) {
+		// The multibase key value matches the method-specific identifier
+		value := "z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+		id, err := ResolveKeyIdentifier("did:key:" + value)
+		assert.Nil(err, "resolve")
+		key := id.VerificationMethods()[0]
+		assert.Equal(value, key.Public)
+		kt, pub, err := DecodeMultibaseKey(value)
+		assert.Nil(err, "decode")
+		assert.Equal(KeyTypeEd, kt)
+		assert.Len(pub, 32)
+		kb, err := key.Bytes()
+		assert.Nil(err)
+		assert.Equal(pub, kb)
+
+		// Values without multicodec prefix are still supported
+		legacy := &VerificationKey{Type: KeyTypeEd, Public: multibaseEncode(pub)}
+		lb, err := legacy.Bytes()
+		assert.Nil(err, "legacy value")
+		assert.Equal(pub, lb)
+
+		// Multibase values for key types using base58 by default
+		bbs, err := NewKeyIdentifier(KeyTypeBBS)
+		assert.Nil(err, "new identifier")
+		bk := bbs.VerificationMethods()[0]
+		pub, _ = bk.Bytes()
+		bk.Public, _ = EncodeMultibaseKey(KeyTypeBBS, pub)
+		bk.PublicKeyBase58 = ""
+		sig, _ := bbs.VerificationMethods()[0].Sign([]byte("data"))
+		assert.True(bk.Verify([]byte("data"), sig), "verify")
+
+		// Invalid values
+		_, _, err = DecodeMultibaseKey("")
+		assert.NotNil(err, "empty value")
+		_, _, err = DecodeMultibaseKey(multibaseEncode(pub))
+		assert.NotNil(err, "no multicodec prefix")
+		_, err = EncodeMultibaseKey(KeyTypeRSA, pub)
+		assert.NotNil(err, "unsupported key type")
+	})
+}
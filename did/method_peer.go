@@ -0,0 +1,263 @@
+package did
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// PeerMethod identifier. Peer identifiers are derived from key material and
+// service endpoints, without a verifiable data registry; intended for
+// pairwise and n-wise relationships, for example on DIDComm flows.
+// https://identity.foundation/peer-did-method-spec/
+const PeerMethod = "peer"
+
+// Purpose codes used for the elements of "did:peer" (numalgo 2)
+// identifiers.
+var peerPurposes = map[byte]VerificationRelationship{
+	'A': AssertionVM,
+	'E': KeyAgreementVM,
+	'V': AuthenticationVM,
+	'I': CapabilityInvocationVM,
+	'D': CapabilityDelegationVM,
+}
+
+// Abbreviations used for service values on "did:peer" (numalgo 2)
+// identifiers.
+var peerAbbreviations = map[string]string{
+	"DIDCommMessaging": "dm",
+}
+
+// PeerKey describes a key included on a "did:peer" (numalgo 2) identifier.
+type PeerKey struct {
+	// Type of key to generate.
+	Type KeyType
+
+	// Verification relationship enabled for the key.
+	Purpose VerificationRelationship
+}
+
+// Compact service representation used on "did:peer" identifiers.
+type peerService struct {
+	Type     string          `json:"t"`
+	Endpoint json.RawMessage `json:"s"`
+}
+
+// NewPeerIdentifier generates a new cryptographic key of type `kt` and
+// returns the corresponding "did:peer" identifier using the inception key
+// without document algorithm (numalgo 0). The resulting document is
+// equivalent to the one of a "did:key" identifier for the same key.
+func NewPeerIdentifier(kt KeyType) (*Identifier, error) {
+	if _, err := multicodecCode(kt); err != nil {
+		return nil, err
+	}
+	key, err := newCryptoKey(kt)
+	if err != nil {
+		return nil, err
+	}
+	return inceptionKeyIdentifier(PeerMethod, "0", key)
+}
+
+// NewPeerIdentifierWithKeys generates the keys described and returns the
+// corresponding "did:peer" identifier using the multiple inception keys
+// algorithm (numalgo 2). Service endpoints are optional; service IDs are
+// assigned automatically based on their position. The identifier includes
+// the private key material.
+func NewPeerIdentifierWithKeys(keys []PeerKey, services ...ServiceEndpoint) (*Identifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+	var (
+		value   = "2"
+		private = make([][]byte, len(keys))
+	)
+	for i, pk := range keys {
+		code := peerPurposeCode(pk.Purpose)
+		if code == 0 {
+			return nil, errors.New("invalid verification relationship")
+		}
+		if _, err := multicodecCode(pk.Type); err != nil {
+			return nil, err
+		}
+		key, err := newCryptoKey(pk.Type)
+		if err != nil {
+			return nil, err
+		}
+		pub, err := key.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		mk, err := EncodeMultibaseKey(key.Type, pub)
+		if err != nil {
+			return nil, err
+		}
+		value += fmt.Sprintf(".%c%s", code, mk)
+		private[i] = key.Private
+	}
+	for _, se := range services {
+		if se.Type == "" || se.Endpoint == "" {
+			return nil, errors.New("invalid service endpoint")
+		}
+		var endpoint []byte
+		if se.Value != nil {
+			endpoint, _ = json.Marshal(se.Value)
+		} else {
+			endpoint, _ = json.Marshal(se.Endpoint)
+		}
+		ps := peerService{Type: se.Type, Endpoint: endpoint}
+		if abbr, ok := peerAbbreviations[se.Type]; ok {
+			ps.Type = abbr
+		}
+		js, err := json.Marshal(ps)
+		if err != nil {
+			return nil, err
+		}
+		value += ".S" + base64.RawURLEncoding.EncodeToString(js)
+	}
+
+	// Build identifier from its string representation, and restore the
+	// private key material
+	id, err := ResolvePeerIdentifier(fmt.Sprintf("%s%s:%s", prefix, PeerMethod, value))
+	if err != nil {
+		return nil, err
+	}
+	for i, vm := range id.data.VerificationMethods {
+		vm.Private = private[i]
+	}
+	return id, nil
+}
+
+// ResolvePeerIdentifier expands a "did:peer" value, using numalgo 0 or 2,
+// into an identifier instance including its public verification methods
+// and service endpoints.
+func ResolvePeerIdentifier(id string) (*Identifier, error) {
+	ID, err := Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	if ID.Method() != PeerMethod {
+		return nil, errors.Errorf("invalid method: %s", ID.Method())
+	}
+	value := ID.data.ID
+	switch {
+	case strings.HasPrefix(value, "0"):
+		key, err := peerKey(value[1:])
+		if err != nil {
+			return nil, err
+		}
+		return inceptionKeyIdentifier(PeerMethod, "0", key)
+	case strings.HasPrefix(value, "2."):
+		return peerIdentifier(ID.DID(), strings.Split(value[2:], "."))
+	default:
+		return nil, errors.New("unsupported numalgo value")
+	}
+}
+
+// Build a "did:peer" (numalgo 2) identifier from its elements.
+func peerIdentifier(did string, elements []string) (*Identifier, error) {
+	id, err := Parse(did)
+	if err != nil {
+		return nil, err
+	}
+	id.data.Context = defaultContexts
+	keys, services := 0, 0
+	for _, el := range elements {
+		if len(el) < 2 {
+			return nil, errors.New("invalid peer identifier element")
+		}
+		if el[0] == 'S' {
+			se, err := peerServiceEndpoint(el[1:])
+			if err != nil {
+				return nil, err
+			}
+			se.ID = "service"
+			if services > 0 {
+				se.ID = fmt.Sprintf("service-%d", services)
+			}
+			services++
+			if err = id.AddService(se); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		purpose, ok := peerPurposes[el[0]]
+		if !ok {
+			return nil, errors.Errorf("invalid purpose code: %c", el[0])
+		}
+		key, err := peerKey(el[1:])
+		if err != nil {
+			return nil, err
+		}
+		keys++
+		key.ID = id.GetReference(fmt.Sprintf("key-%d", keys))
+		key.Controller = id.DID()
+		id.data.VerificationMethods = append(id.data.VerificationMethods, key)
+		if err = id.AddVerificationRelationship(key.ID, purpose); err != nil {
+			return nil, err
+		}
+	}
+	if keys == 0 {
+		return nil, errors.New("no keys available on peer identifier")
+	}
+
+	// Identifiers are not registered, creation date is not available
+	id.data.Created = nil
+	id.data.Updated = nil
+	return id, nil
+}
+
+// Decode a multibase, multicodec-prefixed, public key.
+func peerKey(value string) (*VerificationKey, error) {
+	if value == "" {
+		return nil, errors.New("invalid key value")
+	}
+	kt, pub, err := DecodeMultibaseKey(value)
+	if err != nil {
+		return nil, err
+	}
+	key := &VerificationKey{Type: kt}
+	kt.EncodePublicKey(key, pub)
+	return key, nil
+}
+
+// Decode a compact service representation. The endpoint can be expressed
+// as a URI, or as a structured value including the URI.
+func peerServiceEndpoint(value string) (*ServiceEndpoint, error) {
+	js, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, wrap(err, "invalid service value")
+	}
+	ps := new(peerService)
+	if err = json.Unmarshal(js, ps); err != nil {
+		return nil, wrap(err, "invalid service value")
+	}
+	se := &ServiceEndpoint{Type: ps.Type}
+	for full, abbr := range peerAbbreviations {
+		if ps.Type == abbr {
+			se.Type = full
+		}
+	}
+	if err = json.Unmarshal(ps.Endpoint, &se.Endpoint); err != nil {
+		if err = json.Unmarshal(ps.Endpoint, &se.Value); err != nil {
+			return nil, wrap(err, "invalid service endpoint")
+		}
+		se.Endpoint = primaryURI(se.Value)
+	}
+	if se.Type == "" || se.Endpoint == "" {
+		return nil, errors.New("invalid service endpoint")
+	}
+	return se, nil
+}
+
+// Return the purpose code for a verification relationship.
+func peerPurposeCode(vr VerificationRelationship) byte {
+	for code, rel := range peerPurposes {
+		if rel == vr {
+			return code
+		}
+	}
+	return 0
+}
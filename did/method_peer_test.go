@@ -0,0 +1,88 @@
+package did
+
+import (
+	"encoding/base64"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestPeerIdentifier(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Numalgo0", func(t *testing.T) {
+		value := "did:peer:0z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+		id, err := ResolvePeerIdentifier(value)
+		assert.Nil(err, "resolve")
+		assert.Equal(value, id.DID())
+		doc := id.Document(true)
+		assert.Len(doc.VerificationMethod, 1)
+		assert.Equal(value+"#z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp", doc.VerificationMethod[0].ID)
+		assert.Equal([]string{doc.VerificationMethod[0].ID}, doc.Authentication)
+
+		// Generate
+		id, err = NewPeerIdentifier(KeyTypeX25519)
+		assert.Nil(err, "new identifier")
+		assert.NotEmpty(id.VerificationMethods()[0].Private)
+		restored, err := ResolvePeerIdentifier(id.DID())
+		assert.Nil(err, "resolve")
+		assert.Equal(id.Document(true), restored.Document(true))
+		_, err = NewPeerIdentifier(KeyTypeRSA)
+		assert.NotNil(err, "unsupported key type")
+	})
+
+	t.Run("Numalgo2", func(t *testing.T) {
+		svc := base64.RawURLEncoding.EncodeToString([]byte(`{"t":"dm","s":{"uri":"https://example.com/didcomm","a":["didcomm/v2"]}}`))
+		value := "did:peer:2" +
+			".Vz6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp" +
+			".Ez6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F" +
+			".S" + svc
+		id, err := ResolvePeerIdentifier(value)
+		assert.Nil(err, "resolve")
+		doc := id.Document(true)
+		assert.Len(doc.VerificationMethod, 2)
+		assert.Equal(value+"#key-1", doc.VerificationMethod[0].ID)
+		assert.Equal(KeyTypeEd, doc.VerificationMethod[0].Type)
+		assert.Equal([]string{value + "#key-1"}, doc.Authentication)
+		assert.Equal([]string{value + "#key-2"}, doc.KeyAgreement)
+		assert.Len(doc.Services, 1)
+		assert.Equal(value+"#service", doc.Services[0].ID)
+		assert.Equal("DIDCommMessaging", doc.Services[0].Type)
+		assert.Equal("https://example.com/didcomm", doc.Services[0].Endpoint)
+		assert.Empty(doc.Validate())
+
+		// Generate
+		id, err = NewPeerIdentifierWithKeys([]PeerKey{
+			{Type: KeyTypeEd, Purpose: AuthenticationVM},
+			{Type: KeyTypeX25519, Purpose: KeyAgreementVM},
+		}, ServiceEndpoint{
+			Type:     "DIDCommMessaging",
+			Endpoint: "https://example.com/didcomm",
+		}, ServiceEndpoint{
+			Type:     "LinkedDomains",
+			Endpoint: "https://example.com",
+		})
+		assert.Nil(err, "new identifier")
+		for _, vm := range id.VerificationMethods() {
+			assert.NotEmpty(vm.Private)
+		}
+		restored, err := ResolvePeerIdentifier(id.DID())
+		assert.Nil(err, "resolve")
+		assert.Equal(id.Document(true), restored.Document(true))
+		assert.Equal(id.DID()+"#service-1", restored.Services()[1].ID)
+
+		// Invalid values
+		_, err = NewPeerIdentifierWithKeys(nil)
+		assert.NotNil(err, "no keys")
+		for _, invalid := range []string{
+			"did:peer:1zQmZMygzYqNwU6Uhmewx5Xepf2VLp5S4HLSwwgf2aiKZuwa",
+			"did:peer:2.Xz6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+			"did:peer:2.Sinvalid",
+			"did:peer:2.S" + svc,
+			"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+		} {
+			_, err = ResolvePeerIdentifier(invalid)
+			assert.NotNil(err, invalid)
+		}
+	})
+}
@@ -127,7 +127,7 @@ func (p *parser) parseMethod() parserStep {
 //
 //	specific-idstring = idstring *( ":" idstring )
 //	idstring          = 1*idchar
-//	idchar            = ALPHA / DIGIT / "." / "-"
+//	idchar            = ALPHA / DIGIT / "." / "-" / "_" / pct-encoded
 //
 // p.out.IDStrings is later concatenated by the Parse function before it returns.
 func (p *parser) parseID() parserStep {
@@ -176,10 +176,22 @@ func (p *parser) parseID() parserStep {
 			break
 		}
 
+		if char == '%' {
+			// a % must be followed by 2 hex digits
+			if (currentIndex+2 >= inputLength) ||
+				isNotHexDigit(input[currentIndex+1]) ||
+				isNotHexDigit(input[currentIndex+2]) {
+				return p.errorf(currentIndex, "%% is not followed by 2 hex digits")
+			}
+			// percent encoded char, jump three chars
+			currentIndex = currentIndex + 3
+			continue
+		}
+
 		// make sure current char is a valid idchar
-		// idchar = ALPHA / DIGIT / "." / "-"
+		// idchar = ALPHA / DIGIT / "." / "-" / "_"
 		if isNotValidIDChar(char) {
-			return p.errorf(currentIndex, "byte is not ALPHA OR DIGIT OR '.' OR '-'")
+			return p.errorf(currentIndex, "byte is not ALPHA OR DIGIT OR '.' OR '-' OR '_'")
 		}
 
 		// move to the next char
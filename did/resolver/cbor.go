@@ -0,0 +1,150 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// internal CBOR encoder instance.
+var cborEnc Encoder
+
+func init() {
+	cborEnc = new(cborEncoder)
+}
+
+// CBOR major types.
+const (
+	cborUint   byte = 0
+	cborNegInt byte = 1
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborSimple byte = 7
+)
+
+// Produce deterministic DAG-CBOR representations of DID documents, based on
+// the document JSON data model. Map keys are sorted by length first and then
+// bytewise, integers use the shortest encoding available and floating point
+// values are always encoded using 64 bits.
+// https://ipld.io/specs/codecs/dag-cbor/spec/
+type cborEncoder struct{}
+
+func (ce *cborEncoder) Encode(doc *did.Document) ([]byte, error) {
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.New(ErrInternal)
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var data interface{}
+	if err = dec.Decode(&data); err != nil {
+		return nil, errors.New(ErrInternal)
+	}
+	buf := new(bytes.Buffer)
+	if err = cborValue(buf, data); err != nil {
+		return nil, errors.New(ErrInternal)
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode a value from the JSON data model.
+func cborValue(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteByte(cborSimple<<5 | 22)
+	case bool:
+		if val {
+			buf.WriteByte(cborSimple<<5 | 21)
+		} else {
+			buf.WriteByte(cborSimple<<5 | 20)
+		}
+	case string:
+		cborHead(buf, cborText, uint64(len(val)))
+		buf.WriteString(val)
+	case json.Number:
+		return cborNumber(buf, val)
+	case []interface{}:
+		cborHead(buf, cborArray, uint64(len(val)))
+		for _, el := range val {
+			if err := cborValue(buf, el); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		cborHead(buf, cborMap, uint64(len(val)))
+		for _, k := range keys {
+			cborHead(buf, cborText, uint64(len(k)))
+			buf.WriteString(k)
+			if err := cborValue(buf, val[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("unsupported value type: %T", v)
+	}
+	return nil
+}
+
+// Encode a numeric value; integers are preserved when possible.
+func cborNumber(buf *bytes.Buffer, n json.Number) error {
+	if !strings.ContainsAny(n.String(), ".eE") {
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			if i < 0 {
+				cborHead(buf, cborNegInt, uint64(-(i + 1)))
+			} else {
+				cborHead(buf, cborUint, uint64(i))
+			}
+			return nil
+		}
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			cborHead(buf, cborUint, u)
+			return nil
+		}
+	}
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return errors.Errorf("invalid number: %s", n)
+	}
+	buf.WriteByte(cborSimple<<5 | 27)
+	_ = binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+	return nil
+}
+
+// Write the initial bytes of a data item using the shortest form
+// available for the argument value.
+func cborHead(buf *bytes.Buffer, major byte, arg uint64) {
+	major <<= 5
+	switch {
+	case arg < 24:
+		buf.WriteByte(major | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.Write([]byte{major | 24, byte(arg)})
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		_ = binary.Write(buf, binary.BigEndian, uint16(arg))
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		_ = binary.Write(buf, binary.BigEndian, uint32(arg))
+	default:
+		buf.WriteByte(major | 27)
+		_ = binary.Write(buf, binary.BigEndian, arg)
+	}
+}
@@ -0,0 +1,54 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func TestCBOREncoder(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Test vectors from RFC-8949, appendix A
+	vectors := map[string]string{
+		`0`:                         "00",
+		`23`:                        "17",
+		`24`:                        "1818",
+		`1000`:                      "1903e8",
+		`1000000`:                   "1a000f4240",
+		`18446744073709551615`:      "1bffffffffffffffff",
+		`-1`:                        "20",
+		`-1000`:                     "3903e7",
+		`1.1`:                       "fb3ff199999999999a",
+		`false`:                     "f4",
+		`true`:                      "f5",
+		`null`:                      "f6",
+		`"IETF"`:                    "6449455446",
+		`[1, [2, 3], [4, 5]]`:       "8301820203820405",
+		`{"a": 1, "b": [2, 3]}`:     "a26161016162820203",
+		`{"bb": 1, "a": 2, "c": 3}`: "a361610261630362626201",
+	}
+	for input, expected := range vectors {
+		dec := json.NewDecoder(bytes.NewReader([]byte(input)))
+		dec.UseNumber()
+		var v interface{}
+		assert.Nil(dec.Decode(&v), input)
+		buf := new(bytes.Buffer)
+		assert.Nil(cborValue(buf, v), input)
+		assert.Equal(expected, hex.EncodeToString(buf.Bytes()), input)
+	}
+
+	// Deterministic representation
+	id, err := did.NewKeyIdentifier(did.KeyTypeEd)
+	assert.Nil(err, "new identifier")
+	ri, _ := New()
+	res, err := ri.ResolveRepresentation(id.String(), &ResolutionOptions{Accept: ContentTypeCBOR})
+	assert.Nil(err, "resolve")
+	again, err := cborEnc.Encode(res.Document)
+	assert.Nil(err, "encode")
+	assert.Equal(res.Representation, again)
+}
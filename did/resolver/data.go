@@ -23,6 +23,12 @@ const (
 	// default behavior.
 	// https://w3c-ccg.github.io/did-resolution/#output-didresolutionresult
 	ContentTypeWithProfile = "application/ld+json;profile=\"https://w3id.org/did-resolution\""
+
+	// ContentTypeCBOR instructs the resolution endpoint to return a
+	// deterministic DAG-CBOR representation of the DID document. Useful
+	// for constrained environments and content-addressed storage.
+	// https://ipld.io/specs/codecs/dag-cbor/spec/
+	ContentTypeCBOR = "application/did+cbor"
 )
 
 // Common error codes.
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// ServiceEndpoint dereferences a DID URL including a `service` query
+// parameter, and an optional `relativeRef` one, into the URL of the
+// selected service endpoint. The `relativeRef` value is resolved using
+// the service endpoint as base URL, and the fragment of the DID URL, if
+// any, is preserved.
+//
+//	did:example:123?service=files&relativeRef=%2Fresume.pdf -> https://example.com/resume.pdf
+//
+// If an error is returned is must be a valid error code as defined in
+// the spec.
+// https://w3c-ccg.github.io/did-resolution/#dereferencing-algorithm-primary
+func (ri *Instance) ServiceEndpoint(didURL string) (string, error) {
+	return ri.serviceEndpoint(context.Background(), didURL)
+}
+
+func (ri *Instance) serviceEndpoint(ctx context.Context, didURL string) (string, error) {
+	// is DID URL valid?
+	ID, err := did.Parse(didURL)
+	if err != nil {
+		return "", errors.New(ErrInvalidURL)
+	}
+	query, err := ID.Query()
+	if err != nil || query.Get("service") == "" {
+		return "", errors.New(ErrInvalidURL)
+	}
+
+	// resolve DID document
+	res, err := ri.resolve(ctx, ID.DID(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	// select service
+	name := query.Get("service")
+	var se *did.ServiceEndpoint
+	for i, s := range res.Document.Services {
+		if serviceID(res.Document.Subject, s.ID) == serviceID(res.Document.Subject, name) {
+			se = &res.Document.Services[i]
+			break
+		}
+	}
+	if se == nil {
+		return "", errors.New(ErrNotFound)
+	}
+	base, err := url.Parse(se.Endpoint)
+	if err != nil || !base.IsAbs() {
+		return "", errors.New(ErrInvalidDocument)
+	}
+
+	// apply relative reference and fragment
+	endpoint := base
+	if ref := query.Get("relativeRef"); ref != "" {
+		rel, err := url.Parse(ref)
+		if err != nil {
+			return "", errors.New(ErrInvalidURL)
+		}
+		endpoint = base.ResolveReference(rel)
+	}
+	if frag := ID.Fragment(); frag != "" && endpoint.Fragment == "" {
+		endpoint.Fragment = strings.TrimPrefix(frag, "#")
+	}
+	return endpoint.String(), nil
+}
+
+// Absolute identifier for a service; values can be relative to the DID
+// subject ("#files"), just the fragment ("files") or absolute DID URLs.
+func serviceID(subject, id string) string {
+	switch {
+	case strings.HasPrefix(id, "did:"):
+		return id
+	case strings.HasPrefix(id, "#"):
+		return subject + id
+	default:
+		return subject + "#" + id
+	}
+}
+
+// Returns true if `id` is a DID URL including a `service` query parameter.
+func isServiceURL(id string) bool {
+	ID, err := did.Parse(id)
+	if err != nil {
+		return false
+	}
+	query, err := ID.Query()
+	return err == nil && query.Get("service") != ""
+}
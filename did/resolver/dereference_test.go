@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func TestServiceEndpoint(t *testing.T) {
+	assert := tdd.New(t)
+	ri, err := New()
+	assert.Nil(err, "new resolver")
+	peer, err := did.NewPeerIdentifierWithKeys([]did.PeerKey{
+		{Type: did.KeyTypeEd, Purpose: did.AuthenticationVM},
+	}, did.ServiceEndpoint{
+		Type:     "LinkedDomains",
+		Endpoint: "https://example.com/",
+	}, did.ServiceEndpoint{
+		Type:     "FileStorage",
+		Endpoint: "https://files.example.com/users/alice/",
+	})
+	assert.Nil(err, "new identifier")
+
+	t.Run("Dereference", func(t *testing.T) {
+		cases := map[string]string{
+			"?service=service":                             "https://example.com/",
+			"?service=service-1&relativeRef=resume.pdf":    "https://files.example.com/users/alice/resume.pdf",
+			"?service=service-1&relativeRef=%2Fresume.pdf": "https://files.example.com/resume.pdf",
+			"?service=service&relativeRef=%2Fabout#team":   "https://example.com/about#team",
+		}
+		for query, expected := range cases {
+			location, err := ri.ServiceEndpoint(peer.DID() + query)
+			assert.Nil(err, query)
+			assert.Equal(expected, location, query)
+		}
+
+		// Invalid values
+		_, err = ri.ServiceEndpoint(peer.DID())
+		assert.Equal(ErrInvalidURL, err.Error())
+		_, err = ri.ServiceEndpoint(peer.DID() + "?service=unknown")
+		assert.Equal(ErrNotFound, err.Error())
+		_, err = ri.ServiceEndpoint("did:unknown:123?service=files")
+		assert.Equal(ErrMethodNotSupported, err.Error())
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		requests := []string{
+			"/1.0/identifiers/" + peer.DID() + "?service=service-1&relativeRef=resume.pdf",
+			"/1.0/identifiers/" + url.PathEscape(peer.DID()+"?service=service-1&relativeRef=resume.pdf"),
+		}
+		for _, path := range requests {
+			rec := httptest.NewRecorder()
+			ri.ResolutionHandler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(http.StatusSeeOther, rec.Code)
+			assert.Equal("https://files.example.com/users/alice/resume.pdf", rec.Header().Get("Location"))
+		}
+
+		// Unknown service
+		rec := httptest.NewRecorder()
+		ri.ResolutionHandler(rec, httptest.NewRequest(http.MethodGet, "/1.0/identifiers/"+peer.DID()+"?service=unknown", nil))
+		assert.Equal(http.StatusNotFound, rec.Code)
+
+		// Regular resolution requests are not affected
+		rec = httptest.NewRecorder()
+		ri.ResolutionHandler(rec, httptest.NewRequest(http.MethodGet, "/1.0/identifiers/"+peer.DID(), nil))
+		assert.Equal(http.StatusOK, rec.Code)
+	})
+}
@@ -9,6 +9,47 @@ dereferencing is the process of retrieving a representation of a resource for a
 given DID URL. Software and/or hardware that is able to execute these processes is
 called a DID resolver.
 
+Resolver instances support the "did:key" and "did:peer" methods by default.
+Additional methods are enabled by registering providers; for example, "did:web"
+identifiers are resolved by retrieving the DID document published by the domain.
+
+	resolver, _ := New(
+		WithProvider(WebMethod, NewWebProvider(WebProviderOptions{})),
+	)
+	res, err := resolver.Resolve("did:web:example.com", nil)
+
+Methods not supported locally can be delegated to a remote "DIF Universal Resolver"
+endpoint by registering a fallback provider.
+
+	upstream, _ := NewUniversalProvider(UniversalProviderOptions{
+		Endpoint: "https://dev.uniresolver.io",
+	})
+	resolver, _ := New(WithFallback(upstream))
+
+Besides the JSON-LD representations, a deterministic DAG-CBOR representation
+of DID documents is available using the "application/did+cbor" content type.
+
+	res, err := resolver.ResolveRepresentation("did:web:example.com", &ResolutionOptions{
+		Accept: ContentTypeCBOR,
+	})
+
+DID URLs selecting a service endpoint, using the "service" and "relativeRef"
+query parameters, are dereferenced into the URL of the service; when handled
+by 'ResolutionHandler' the client is redirected (HTTP 303) to that location.
+
+	// https://example.com/resume.pdf
+	location, err := resolver.ServiceEndpoint("did:example:123?service=files&relativeRef=%2Fresume.pdf")
+
+Resolution operations, including requests handled by 'ResolutionHandler', are
+instrumented using OpenTelemetry. Spans and metrics are reported using the
+global providers, for example the ones set by an 'otel/sdk' instance; no data
+is collected otherwise. The following metrics are available:
+
+  - did.resolution.duration: duration of resolution operations, in seconds.
+  - did.resolution.errors: failed resolution operations per method and error.
+  - did.resolution.cache.requests: cache lookups performed by providers; the
+    'did.resolution.cache_hit' attribute can be used to calculate hit ratios.
+
 More information:
 https://w3c-ccg.github.io/did-resolution
 */
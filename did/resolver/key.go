@@ -0,0 +1,23 @@
+package resolver
+
+import (
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// KeyProvider resolves "did:key" identifiers. These identifiers are
+// self-certifying, the DID document is derived from the public key
+// material included in the identifier itself, so no verifiable data
+// registry is required. A key provider is registered by default on all
+// resolver instances.
+// https://w3c-ccg.github.io/did-method-key/
+type KeyProvider struct{}
+
+// Read returns the DID document for a "did:key" identifier.
+func (kp *KeyProvider) Read(id string) (*did.Document, *did.DocumentMetadata, error) {
+	ID, err := did.ResolveKeyIdentifier(id)
+	if err != nil {
+		return nil, nil, errors.New(ErrInvalidDID)
+	}
+	return ID.Document(true), ID.GetMetadata(), nil
+}
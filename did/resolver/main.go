@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -8,6 +9,9 @@ import (
 
 	"go.bryk.io/pkg/did"
 	"go.bryk.io/pkg/errors"
+	otelApi "go.bryk.io/pkg/otel/api"
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // Provider instances are method-specific and abstract away the
@@ -45,16 +49,23 @@ type Instance struct {
 
 	// Encoders available to obtain DID representations.
 	encoders map[string]Encoder
+
+	// Provider used for methods not explicitly registered.
+	fallback Provider
 }
 
 // New returns a ready-to-use DID resolver instance.
 func New(opts ...Option) (*Instance, error) {
 	i := &Instance{
-		providers: make(map[string]Provider),
+		providers: map[string]Provider{
+			did.KeyMethod:  new(KeyProvider),
+			did.PeerMethod: new(PeerProvider),
+		},
 		encoders: map[string]Encoder{
 			ContentTypeLD:          jsEnc,
 			ContentTypeDocument:    jsEnc,
 			ContentTypeWithProfile: jsEnc,
+			ContentTypeCBOR:        cborEnc,
 		},
 	}
 	for _, opt := range opts {
@@ -69,6 +80,21 @@ func New(opts ...Option) (*Instance, error) {
 // applicable DID method.
 // https://www.w3.org/TR/did-core/#did-resolution
 func (ri *Instance) Resolve(id string, opts *ResolutionOptions) (*Result, error) {
+	return ri.resolve(context.Background(), id, opts)
+}
+
+// ResolveRepresentation attempts to resolve a DID into a DID document by using
+// the "Read" operation of the applicable DID method and encode a suitable
+// representation based on the options provided.
+// https://www.w3.org/TR/did-core/#did-resolution
+func (ri *Instance) ResolveRepresentation(id string, opts *ResolutionOptions) (*Result, error) {
+	return ri.resolveRepresentation(context.Background(), id, opts)
+}
+
+func (ri *Instance) resolve(ctx context.Context, id string, opts *ResolutionOptions) (res *Result, err error) {
+	task := startResolution(ctx, "resolve", id)
+	defer func() { task.end(err) }()
+
 	// Use default resolution options
 	if opts == nil {
 		opts = new(ResolutionOptions)
@@ -76,7 +102,7 @@ func (ri *Instance) Resolve(id string, opts *ResolutionOptions) (*Result, error)
 	_ = opts.Validate()
 
 	// prepare result holder
-	res := &Result{
+	res = &Result{
 		Context: []interface{}{ldContext},
 		ResolutionMetadata: &ResolutionMetadata{
 			ContentType: ContentTypeDocument,
@@ -93,7 +119,7 @@ func (ri *Instance) Resolve(id string, opts *ResolutionOptions) (*Result, error)
 	}
 
 	// is method supported?
-	provider, ok := ri.providers[ID.Method()]
+	provider, ok := ri.provider(ID.Method())
 	if !ok {
 		err = errors.New(ErrMethodNotSupported)
 		res.ResolutionMetadata.Error = err.Error()
@@ -118,11 +144,10 @@ func (ri *Instance) Resolve(id string, opts *ResolutionOptions) (*Result, error)
 	return res, nil
 }
 
-// ResolveRepresentation attempts to resolve a DID into a DID document by using
-// the "Read" operation of the applicable DID method and encode a suitable
-// representation based on the options provided.
-// https://www.w3.org/TR/did-core/#did-resolution
-func (ri *Instance) ResolveRepresentation(id string, opts *ResolutionOptions) (*Result, error) {
+func (ri *Instance) resolveRepresentation(ctx context.Context, id string, opts *ResolutionOptions) (res *Result, err error) {
+	task := startResolution(ctx, "resolveRepresentation", id)
+	defer func() { task.end(err) }()
+
 	// Use default resolution options
 	if opts == nil {
 		opts = new(ResolutionOptions)
@@ -130,7 +155,7 @@ func (ri *Instance) ResolveRepresentation(id string, opts *ResolutionOptions) (*
 	_ = opts.Validate()
 
 	// prepare result holder
-	res := &Result{
+	res = &Result{
 		Context: []interface{}{ldContext},
 		ResolutionMetadata: &ResolutionMetadata{
 			ContentType: opts.Accept,
@@ -147,7 +172,7 @@ func (ri *Instance) ResolveRepresentation(id string, opts *ResolutionOptions) (*
 	}
 
 	// is method supported?
-	provider, ok := ri.providers[ID.Method()]
+	provider, ok := ri.provider(ID.Method())
 	if !ok {
 		err = errors.New(ErrMethodNotSupported)
 		res.ResolutionMetadata.Error = err.Error()
@@ -187,6 +212,15 @@ func (ri *Instance) ResolveRepresentation(id string, opts *ResolutionOptions) (*
 	return res, nil
 }
 
+// Return the provider registered for a DID method, or the fallback
+// provider if available.
+func (ri *Instance) provider(method string) (Provider, bool) {
+	if prov, ok := ri.providers[method]; ok {
+		return prov, true
+	}
+	return ri.fallback, ri.fallback != nil
+}
+
 // ResolutionHandler exposes the `resolve` operations through an HTTP endpoint
 // compatible with the DIF specification.
 // https://w3c-ccg.github.io/did-resolution/#bindings-https
@@ -194,10 +228,49 @@ func (ri *Instance) ResolutionHandler(rw http.ResponseWriter, rq *http.Request)
 	// get requested identifier
 	id := strings.TrimPrefix(rq.URL.Path, "/1.0/identifiers/")
 
+	// continue trace context propagated by the client, if any
+	ctx := apiOtel.GetTextMapPropagator().Extract(rq.Context(), propagation.HeaderCarrier(rq.Header))
+	task := otelApi.Start(ctx, "did.resolver.http",
+		otelApi.WithSpanKind(otelApi.SpanKindServer),
+		otelApi.WithAttributes(map[string]interface{}{
+			"http.request.method": rq.Method,
+			"did.id":              id,
+		}))
+	defer task.End(nil)
+
+	// DID URLs selecting a service are dereferenced by redirecting the
+	// client to the service endpoint. The DID URL query can be provided
+	// URL-encoded as part of the path, or as the request query.
+	if rq.URL.RawQuery != "" && !strings.Contains(id, "?") {
+		id += "?" + rq.URL.RawQuery
+	}
+	if isServiceURL(id) {
+		location, err := ri.serviceEndpoint(task.Context(), id)
+		if err != nil {
+			status := errToStatus(err.Error())
+			task.SetAttribute("http.response.status_code", status)
+			rw.Header().Set("Content-Type", ContentTypeWithProfile+";charset=utf-8")
+			rw.WriteHeader(status)
+			_ = json.NewEncoder(rw).Encode(&Result{
+				Context: []interface{}{ldContext},
+				ResolutionMetadata: &ResolutionMetadata{
+					ContentType: ContentTypeWithProfile,
+					Retrieved:   time.Now().UTC().Format(time.RFC3339),
+					Error:       err.Error(),
+				},
+			})
+			return
+		}
+		task.SetAttribute("http.response.status_code", http.StatusSeeOther)
+		http.Redirect(rw, rq, location, http.StatusSeeOther)
+		return
+	}
+
 	// process resolution request
 	opts := new(ResolutionOptions)
 	opts.FromRequest(rq)
 	_ = opts.Validate()
+	task.SetAttribute("did.resolution.accept", opts.Accept)
 
 	var (
 		res *Result
@@ -206,28 +279,33 @@ func (ri *Instance) ResolutionHandler(rw http.ResponseWriter, rq *http.Request)
 	if strings.Count(opts.Accept, "json") > 0 {
 		// standard JSON LD mime types are handled directly by a
 		// 'resolve' operation
-		res, err = ri.Resolve(id, opts)
+		res, err = ri.resolve(task.Context(), id, opts)
 	} else {
 		// custom mime types are hadled by specialized encoders
-		res, err = ri.ResolveRepresentation(id, opts)
+		res, err = ri.resolveRepresentation(task.Context(), id, opts)
 	}
 
 	// return error
 	if err != nil {
+		status := errToStatus(err.Error())
+		task.SetAttribute("http.response.status_code", status)
 		rw.Header().Set("Content-Type", ContentTypeWithProfile+";charset=utf-8")
-		rw.WriteHeader(errToStatus(err.Error()))
+		rw.WriteHeader(status)
 		_ = json.NewEncoder(rw).Encode(res)
 		return
 	}
 
 	// return deactivated doc
 	if res.DocumentMetadata != nil && res.DocumentMetadata.Deactivated {
+		task.SetAttribute("http.response.status_code", deactivatedStatus)
 		rw.Header().Set("Content-Type", ContentTypeWithProfile+";charset=utf-8")
 		rw.WriteHeader(deactivatedStatus)
 		_ = json.NewEncoder(rw).Encode(res)
 		return
 	}
 
+	task.SetAttribute("http.response.status_code", http.StatusOK)
+
 	// return result
 	// https://w3c-ccg.github.io/did-resolution/#did-resolution-result
 	switch opts.Accept {
@@ -235,6 +235,23 @@ func TestResolutionHandler(t *testing.T) {
 		assert.Nil(json.Unmarshal(body, val))
 	})
 
+	// must return a CBOR representation
+	t.Run(ContentTypeCBOR, func(t *testing.T) {
+		endpoint := "http://localhost:3000/1.0/identifiers/" + activeID
+		req, _ := http.NewRequest(http.MethodGet, endpoint, nil)
+		req.Header.Set("Accept", ContentTypeCBOR)
+		res, err := http.DefaultClient.Do(req)
+
+		assert.Nil(err)
+		assert.Equal(res.Header.Get("content-type"), ContentTypeCBOR)
+		assert.Equal(res.StatusCode, http.StatusOK)
+
+		body, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		assert.NotEmpty(body)
+		assert.Equal(byte(0xa0), body[0]&0xe0, "map expected")
+	})
+
 	// must return a "notFound" error
 	t.Run(ErrNotFound, func(t *testing.T) {
 		endpoint := "http://localhost:3000/1.0/identifiers/did:dev:not-found"
@@ -292,3 +309,40 @@ func TestResolutionHandler(t *testing.T) {
 		assert.True(val.DocumentMetadata.Deactivated)
 	})
 }
+
+func TestKeyProvider(t *testing.T) {
+	assert := tdd.New(t)
+	ri, err := New()
+	assert.Nil(err, "new resolver")
+
+	// "did:key" identifiers are supported by default
+	id := "did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+	res, err := ri.Resolve(id, nil)
+	assert.Nil(err, "resolve")
+	assert.Equal(id, res.Document.Subject)
+	assert.Len(res.Document.VerificationMethod, 1, "verification methods")
+
+	// Invalid identifier
+	_, err = ri.Resolve("did:key:invalid", nil)
+	assert.Equal(ErrInvalidDID, err.Error())
+}
+
+func TestPeerProvider(t *testing.T) {
+	assert := tdd.New(t)
+	ri, err := New()
+	assert.Nil(err, "new resolver")
+
+	// "did:peer" identifiers are supported by default
+	peer, err := did.NewPeerIdentifierWithKeys([]did.PeerKey{
+		{Type: did.KeyTypeX25519, Purpose: did.KeyAgreementVM},
+	})
+	assert.Nil(err, "new identifier")
+	res, err := ri.Resolve(peer.DID(), nil)
+	assert.Nil(err, "resolve")
+	assert.Equal(peer.DID(), res.Document.Subject)
+	assert.Len(res.Document.KeyAgreement, 1, "key agreement")
+
+	// Invalid identifier
+	_, err = ri.Resolve("did:peer:3invalid", nil)
+	assert.Equal(ErrInvalidDID, err.Error())
+}
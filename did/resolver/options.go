@@ -5,7 +5,7 @@ package resolver
 type Option func(i *Instance) error
 
 // WithProvider registers/enables a DID method handler with the resolver
-// instance.
+// instance. A provider for the "key" method is registered by default.
 func WithProvider(method string, prov Provider) Option {
 	return func(i *Instance) error {
 		i.providers[method] = prov
@@ -26,3 +26,14 @@ func WithEncoder(mime string, enc Encoder) Option {
 		return nil
 	}
 }
+
+// WithFallback registers a provider used to resolve identifiers for methods
+// not explicitly registered with the resolver instance. For example, a
+// 'UniversalProvider' can be used to delegate unsupported methods to a remote
+// resolver while local methods are still resolved in-process.
+func WithFallback(prov Provider) Option {
+	return func(i *Instance) error {
+		i.fallback = prov
+		return nil
+	}
+}
@@ -0,0 +1,23 @@
+package resolver
+
+import (
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// PeerProvider resolves "did:peer" identifiers using numalgo 0 or 2. The
+// DID document is derived from the key material and service endpoints
+// included in the identifier itself, so no verifiable data registry is
+// required. A peer provider is registered by default on all resolver
+// instances.
+// https://identity.foundation/peer-did-method-spec/
+type PeerProvider struct{}
+
+// Read returns the DID document for a "did:peer" identifier.
+func (pp *PeerProvider) Read(id string) (*did.Document, *did.DocumentMetadata, error) {
+	ID, err := did.ResolvePeerIdentifier(id)
+	if err != nil {
+		return nil, nil, errors.New(ErrInvalidDID)
+	}
+	return ID.Document(true), ID.GetMetadata(), nil
+}
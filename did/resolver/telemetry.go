@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"go.bryk.io/pkg/did"
+	otelApi "go.bryk.io/pkg/otel/api"
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Instrumentation scope used for all resolver metrics.
+const meterName = "go.bryk.io/pkg/did/resolver"
+
+// Attribute keys used on resolver spans and metrics.
+const (
+	methodKey    = attribute.Key("did.method")
+	operationKey = attribute.Key("did.resolution.operation")
+	errorKey     = attribute.Key("did.resolution.error")
+	cacheHitKey  = attribute.Key("did.resolution.cache_hit")
+)
+
+// Resolver metric instruments. Instruments are created using the global
+// meter provider; measurements are discarded unless a provider is set,
+// for example by an 'otel/sdk' instance.
+var (
+	resolutionDuration metric.Float64Histogram
+	resolutionErrors   metric.Int64Counter
+	cacheRequests      metric.Int64Counter
+)
+
+func init() {
+	meter := apiOtel.Meter(meterName)
+	resolutionDuration, _ = meter.Float64Histogram("did.resolution.duration",
+		metric.WithDescription("Duration of DID resolution operations."),
+		metric.WithUnit("s"))
+	resolutionErrors, _ = meter.Int64Counter("did.resolution.errors",
+		metric.WithDescription("Number of failed DID resolution operations."),
+		metric.WithUnit("{error}"))
+	cacheRequests, _ = meter.Int64Counter("did.resolution.cache.requests",
+		metric.WithDescription("Number of cache lookups performed by DID providers; "+
+			"use the 'did.resolution.cache_hit' attribute to calculate the hit ratio."),
+		metric.WithUnit("{request}"))
+}
+
+// Tracks a single resolution operation.
+type resolution struct {
+	ctx    context.Context
+	span   otelApi.Span
+	start  time.Time
+	method string
+	op     string
+}
+
+// Start tracking a resolution operation.
+func startResolution(ctx context.Context, op, id string) *resolution {
+	method := "unknown"
+	if ID, err := did.Parse(id); err == nil {
+		method = ID.Method()
+	}
+	sp := otelApi.Start(ctx, "did."+op, otelApi.WithAttributes(map[string]interface{}{
+		string(methodKey):    method,
+		string(operationKey): op,
+		"did.id":             id,
+	}))
+	return &resolution{
+		ctx:    sp.Context(),
+		span:   sp,
+		start:  time.Now(),
+		method: method,
+		op:     op,
+	}
+}
+
+// Complete the resolution operation and record its metrics.
+func (r *resolution) end(err error) {
+	attrs := []attribute.KeyValue{
+		methodKey.String(r.method),
+		operationKey.String(r.op),
+	}
+	if err != nil {
+		errAttrs := append(attrs, errorKey.String(err.Error()))
+		r.span.SetAttribute(string(errorKey), err.Error())
+		resolutionErrors.Add(r.ctx, 1, metric.WithAttributes(errAttrs...))
+	}
+	resolutionDuration.Record(r.ctx, time.Since(r.start).Seconds(), metric.WithAttributes(attrs...))
+	r.span.End(err)
+}
+
+// Record a cache lookup performed by a provider.
+func recordCacheLookup(method string, hit bool) {
+	cacheRequests.Add(context.Background(), 1, metric.WithAttributes(
+		methodKey.String(method),
+		cacheHitKey.Bool(hit),
+	))
+}
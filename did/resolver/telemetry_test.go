@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+	apiOtel "go.opentelemetry.io/otel"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTelemetry(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Collect spans and metrics in memory
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkMetric.NewManualReader()
+	apiOtel.SetTracerProvider(sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(spans)))
+	apiOtel.SetMeterProvider(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+
+	id, err := did.NewKeyIdentifier(did.KeyTypeEd)
+	assert.Nil(err, "new identifier")
+	r, _ := New()
+	_, err = r.Resolve(id.DID(), nil)
+	assert.Nil(err, "resolve")
+	_, err = r.Resolve("did:dev:123", nil)
+	assert.NotNil(err, "unsupported method")
+
+	// HTTP handler
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/1.0/identifiers/"+id.DID(), nil)
+	req.Header.Set("Accept", ContentTypeCBOR)
+	r.ResolutionHandler(rec, req)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	// Spans
+	names := []string{}
+	for _, sp := range spans.Ended() {
+		names = append(names, sp.Name())
+	}
+	assert.Equal([]string{"did.resolve", "did.resolve", "did.resolveRepresentation", "did.resolver.http"}, names)
+	child, parent := spans.Ended()[2], spans.Ended()[3]
+	assert.Equal(parent.SpanContext().SpanID(), child.Parent().SpanID(), "nested span")
+
+	// Metrics
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(reader.Collect(context.Background(), &rm), "collect metrics")
+	collected := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			collected[m.Name] = m.Data
+		}
+	}
+	duration, ok := collected["did.resolution.duration"].(metricdata.Histogram[float64])
+	assert.True(ok, "duration histogram")
+	count := uint64(0)
+	for _, dp := range duration.DataPoints {
+		count += dp.Count
+	}
+	assert.Equal(uint64(3), count, "resolutions recorded")
+	errs, ok := collected["did.resolution.errors"].(metricdata.Sum[int64])
+	assert.True(ok, "errors counter")
+	assert.Len(errs.DataPoints, 1)
+	method, _ := errs.DataPoints[0].Attributes.Value(methodKey)
+	assert.Equal("dev", method.AsString())
+}
@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// Maximum size allowed for resolution results.
+const maxResultSize = 1 << 20
+
+// UniversalProviderOptions defines the configuration settings available
+// for a universal resolver provider.
+type UniversalProviderOptions struct {
+	// Base URL of the remote resolver. For example:
+	// "https://dev.uniresolver.io". Required.
+	Endpoint string
+
+	// HTTP client used to submit requests. If not provided, the default
+	// client is used.
+	Client *http.Client
+
+	// Additional HTTP headers included in all requests; for example to
+	// provide authentication credentials.
+	Headers map[string]string
+
+	// Timeout for each individual request. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// UniversalProvider resolves identifiers using a remote "DIF Universal
+// Resolver" endpoint. The provider can be registered for specific methods or
+// as a fallback for all methods not supported locally.
+// https://github.com/decentralized-identity/universal-resolver
+type UniversalProvider struct {
+	opts UniversalProviderOptions
+}
+
+// NewUniversalProvider returns a new provider instance using the remote
+// resolver available at `opts.Endpoint`.
+func NewUniversalProvider(opts UniversalProviderOptions) (*UniversalProvider, error) {
+	ep, err := url.Parse(opts.Endpoint)
+	if err != nil || ep.Host == "" {
+		return nil, errors.New("invalid resolver endpoint")
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+	return &UniversalProvider{opts: opts}, nil
+}
+
+// Read the DID document for an identifier using the remote resolver. Error
+// codes reported by the remote resolver are returned as-is.
+func (up *UniversalProvider) Read(id string) (*did.Document, *did.DocumentMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), up.opts.Timeout)
+	defer cancel()
+	endpoint := up.opts.Endpoint + "/1.0/identifiers/" + url.PathEscape(id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, errors.New(ErrInvalidDID)
+	}
+	req.Header.Set("Accept", ContentTypeWithProfile)
+	for k, v := range up.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := up.opts.Client.Do(req)
+	if err != nil {
+		return nil, nil, errors.New(ErrInternal)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResultSize))
+	if err != nil {
+		return nil, nil, errors.New(ErrInternal)
+	}
+
+	// Decode resolution result
+	result := new(Result)
+	if err = json.Unmarshal(body, result); err != nil {
+		if res.StatusCode == http.StatusNotFound {
+			return nil, nil, errors.New(ErrNotFound)
+		}
+		return nil, nil, errors.New(ErrInternal)
+	}
+	if result.ResolutionMetadata != nil && result.ResolutionMetadata.Error != "" {
+		return nil, nil, errors.New(result.ResolutionMetadata.Error)
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != deactivatedStatus {
+		return nil, nil, errors.New(ErrInternal)
+	}
+	return result.Document, result.DocumentMetadata, nil
+}
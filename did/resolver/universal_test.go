@@ -0,0 +1,73 @@
+package resolver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func TestUniversalProvider(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Remote resolver supporting the "dev" method
+	prov := new(sampleProvider)
+	prov.dir = make(map[string]*did.Identifier)
+	activeID := prov.registerNew()
+	inactiveID := prov.registerNew()
+	prov.deactivate(inactiveID)
+	remote, err := New(WithProvider("dev", prov))
+	assert.Nil(err, "remote resolver")
+	var lastHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastHeader = r.Header.Get("X-Api-Key")
+		remote.ResolutionHandler(w, r)
+	}))
+	defer ts.Close()
+
+	// Invalid endpoint
+	_, err = NewUniversalProvider(UniversalProviderOptions{Endpoint: "not-a-url"})
+	assert.NotNil(err, "invalid endpoint")
+
+	// Local resolver delegating unsupported methods
+	up, err := NewUniversalProvider(UniversalProviderOptions{
+		Endpoint: ts.URL + "/",
+		Headers:  map[string]string{"X-Api-Key": "secret"},
+	})
+	assert.Nil(err, "new provider")
+	ri, err := New(WithFallback(up))
+	assert.Nil(err, "new resolver")
+
+	t.Run("Remote", func(t *testing.T) {
+		res, err := ri.Resolve(activeID, nil)
+		assert.Nil(err, "resolve")
+		assert.Equal(activeID, res.Document.Subject)
+		assert.Equal("secret", lastHeader, "custom headers")
+
+		// Deactivated identifiers still return document and metadata
+		res, err = ri.Resolve(inactiveID, nil)
+		assert.Nil(err, "resolve")
+		assert.True(res.DocumentMetadata.Deactivated, "deactivated")
+	})
+
+	t.Run("Local", func(t *testing.T) {
+		id, err := did.NewKeyIdentifier(did.KeyTypeEd)
+		assert.Nil(err, "new identifier")
+		lastHeader = ""
+		res, err := ri.Resolve(id.DID(), nil)
+		assert.Nil(err, "resolve")
+		assert.Equal(id.DID(), res.Document.Subject)
+		assert.Empty(lastHeader, "resolved in-process")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := ri.Resolve("did:dev:not-found", nil)
+		assert.Equal(ErrNotFound, err.Error())
+		_, err = ri.Resolve("did:dev:with-internal-error", nil)
+		assert.Equal(ErrInternal, err.Error())
+		_, err = ri.Resolve("did:other:12345", nil)
+		assert.Equal(ErrMethodNotSupported, err.Error())
+	})
+}
@@ -0,0 +1,195 @@
+package resolver
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// WebMethod is the method name used by "did:web" identifiers.
+// https://w3c-ccg.github.io/did-method-web/
+const WebMethod = "web"
+
+// Maximum size allowed for retrieved DID documents.
+const maxWebDocumentSize = 1 << 20
+
+// WebProviderOptions defines the configuration settings available
+// for a "did:web" provider.
+type WebProviderOptions struct {
+	// HTTP client used to retrieve DID documents. If not provided, a client
+	// requiring TLS 1.2 or newer is used. TLS certificates are always
+	// validated by the default client.
+	Client *http.Client
+
+	// Time to keep retrieved documents in memory. Defaults to 5 minutes,
+	// use a negative value to disable caching.
+	CacheTTL time.Duration
+
+	// Timeout for each individual request. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// WebProvider resolves "did:web" identifiers by retrieving the DID
+// document published by the domain; using the well-known location for
+// domain-level identifiers or a path-based location otherwise.
+//
+//	did:web:example.com                  -> https://example.com/.well-known/did.json
+//	did:web:example.com:user:alice       -> https://example.com/user/alice/did.json
+//	did:web:example.com%3A8443           -> https://example.com:8443/.well-known/did.json
+//
+// Documents are only retrieved over HTTPS and must have the requested
+// DID as subject.
+type WebProvider struct {
+	opts  WebProviderOptions
+	cache map[string]webCacheEntry
+	mu    sync.Mutex
+}
+
+type webCacheEntry struct {
+	doc     *did.Document
+	expires time.Time
+}
+
+// NewWebProvider returns a new "did:web" provider instance.
+func NewWebProvider(opts WebProviderOptions) *WebProvider {
+	if opts.Client == nil {
+		opts.Client = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+			},
+		}
+	}
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = 5 * time.Minute
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &WebProvider{
+		opts:  opts,
+		cache: make(map[string]webCacheEntry),
+	}
+}
+
+// Read retrieves the DID document published for a "did:web" identifier.
+func (wp *WebProvider) Read(id string) (*did.Document, *did.DocumentMetadata, error) {
+	ID, err := did.Parse(id)
+	if err != nil || ID.Method() != WebMethod {
+		return nil, nil, errors.New(ErrInvalidDID)
+	}
+	subject := ID.DID()
+
+	// Use cached document, if available
+	if wp.opts.CacheTTL > 0 {
+		doc := wp.cached(subject)
+		recordCacheLookup(WebMethod, doc != nil)
+		if doc != nil {
+			return doc, nil, nil
+		}
+	}
+
+	// Retrieve document
+	endpoint, err := WebDocumentURL(subject)
+	if err != nil {
+		return nil, nil, errors.New(ErrInvalidDID)
+	}
+	doc, err := wp.fetch(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	if doc.Subject != subject {
+		return nil, nil, errors.New(ErrInvalidDocument)
+	}
+	if wp.opts.CacheTTL > 0 {
+		wp.mu.Lock()
+		wp.cache[subject] = webCacheEntry{doc: doc, expires: time.Now().Add(wp.opts.CacheTTL)}
+		wp.mu.Unlock()
+	}
+	return doc, nil, nil
+}
+
+// WebDocumentURL returns the location of the DID document for a "did:web"
+// identifier.
+func WebDocumentURL(id string) (string, error) {
+	ID, err := did.Parse(id)
+	if err != nil {
+		return "", err
+	}
+	if ID.Method() != WebMethod {
+		return "", errors.Errorf("invalid method: %s", ID.Method())
+	}
+	segments := strings.Split(ID.Subject(), ":")
+	for i, seg := range segments {
+		if segments[i], err = url.PathUnescape(seg); err != nil {
+			return "", err
+		}
+	}
+	host := segments[0]
+	if strings.ContainsAny(host, "/?#@") {
+		return "", errors.Errorf("invalid domain: %s", host)
+	}
+	path := "/.well-known"
+	if len(segments) > 1 {
+		path = "/" + strings.Join(segments[1:], "/")
+	}
+	u := url.URL{Scheme: "https", Host: host, Path: path + "/did.json"}
+	return u.String(), nil
+}
+
+// Retrieve a cached document, if available.
+func (wp *WebProvider) cached(subject string) *did.Document {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	entry, ok := wp.cache[subject]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(wp.cache, subject)
+		return nil
+	}
+	return entry.doc
+}
+
+// Retrieve and decode the DID document available at `endpoint`.
+func (wp *WebProvider) fetch(endpoint string) (*did.Document, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), wp.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.New(ErrInvalidDID)
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+	res, err := wp.opts.Client.Do(req)
+	if err != nil {
+		return nil, errors.New(ErrInternal)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return nil, errors.New(ErrNotFound)
+	case res.StatusCode != http.StatusOK:
+		return nil, errors.New(ErrInternal)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxWebDocumentSize))
+	if err != nil {
+		return nil, errors.New(ErrInternal)
+	}
+	doc := new(did.Document)
+	if err = json.Unmarshal(body, doc); err != nil {
+		return nil, errors.New(ErrInvalidDocument)
+	}
+	return doc, nil
+}
@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func TestWebDocumentURL(t *testing.T) {
+	assert := tdd.New(t)
+	cases := map[string]string{
+		"did:web:example.com":                  "https://example.com/.well-known/did.json",
+		"did:web:example.com:user:alice":       "https://example.com/user/alice/did.json",
+		"did:web:localhost%3A8443":             "https://localhost:8443/.well-known/did.json",
+		"did:web:example.com%3A3000:user:rick": "https://example.com:3000/user/rick/did.json",
+	}
+	for id, expected := range cases {
+		endpoint, err := WebDocumentURL(id)
+		assert.Nil(err, "document URL")
+		assert.Equal(expected, endpoint)
+	}
+	_, err := WebDocumentURL("did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp")
+	assert.NotNil(err, "invalid method")
+}
+
+func TestWebProvider(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Sample server publishing DID documents
+	var (
+		requests int32
+		docs     = map[string]*did.Document{}
+	)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		doc, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/did+json")
+		_ = json.NewEncoder(w).Encode(doc)
+	}))
+	defer ts.Close()
+	host := strings.Replace(strings.TrimPrefix(ts.URL, "https://"), ":", "%3A", 1)
+	for _, path := range []string{"/.well-known/did.json", "/user/alice/did.json"} {
+		segments := strings.Split(strings.TrimSuffix(path, "/did.json"), "/")[1:]
+		if segments[0] == ".well-known" {
+			segments = nil
+		}
+		id, err := did.NewIdentifier(WebMethod, strings.Join(append([]string{host}, segments...), ":"))
+		assert.Nil(err, "new identifier")
+		docs[path] = id.Document(true)
+	}
+	docs["/user/mallory/did.json"] = docs["/user/alice/did.json"] // invalid subject
+
+	prov := NewWebProvider(WebProviderOptions{Client: ts.Client(), CacheTTL: time.Minute})
+	ri, err := New(WithProvider(WebMethod, prov))
+	assert.Nil(err, "new resolver")
+
+	t.Run("Resolve", func(t *testing.T) {
+		for _, id := range []string{"did:web:" + host, "did:web:" + host + ":user:alice"} {
+			res, err := ri.Resolve(id, nil)
+			assert.Nil(err, "resolve")
+			assert.Equal(id, res.Document.Subject)
+		}
+
+		// Documents are cached
+		current := atomic.LoadInt32(&requests)
+		_, err = ri.Resolve("did:web:"+host, nil)
+		assert.Nil(err, "resolve")
+		assert.Equal(current, atomic.LoadInt32(&requests), "cached document")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := ri.Resolve("did:web:"+host+":user:rick", nil)
+		assert.Equal(ErrNotFound, err.Error())
+		_, err = ri.Resolve("did:web:"+host+":user:mallory", nil)
+		assert.Equal(ErrInvalidDocument, err.Error())
+
+		// TLS certificates are validated
+		_, _, err = NewWebProvider(WebProviderOptions{}).Read("did:web:" + host)
+		assert.Equal(ErrInternal, err.Error())
+	})
+}
@@ -0,0 +1,232 @@
+package did
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// KeyRotation records the replacement of the cryptographic material used by
+// a verification method. The verification method identifier, and hence its
+// verification relationships, remains unchanged. Each record includes a
+// proof, produced by the previous key, authorizing the new key.
+type KeyRotation struct {
+	// Identifier of the rotated verification method.
+	ID string `json:"id" yaml:"id"`
+
+	// Key used before the rotation, public material only.
+	Previous VerificationKey `json:"previous" yaml:"previous"`
+
+	// Key used after the rotation, public material only.
+	Next VerificationKey `json:"next" yaml:"next"`
+
+	// Start of the validity interval of the previous key.
+	ValidFrom time.Time `json:"validFrom" yaml:"validFrom"`
+
+	// End of the validity interval of the previous key; i.e., the time of
+	// the rotation.
+	ValidUntil time.Time `json:"validUntil" yaml:"validUntil"`
+
+	// Signature value produced by the previous key over the rotation
+	// statement.
+	Proof []byte `json:"proof" yaml:"proof"`
+}
+
+// Verify the rotation proof was produced by the previous key.
+func (kr KeyRotation) Verify() bool {
+	st, err := kr.statement()
+	if err != nil {
+		return false
+	}
+	return kr.Previous.Verify(st, kr.Proof)
+}
+
+// Deterministic statement signed by the previous key.
+func (kr KeyRotation) statement() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"id":         kr.ID,
+		"previous":   publicKeyValue(&kr.Previous),
+		"next":       publicKeyValue(&kr.Next),
+		"nextType":   kr.Next.Type.String(),
+		"validFrom":  kr.ValidFrom.UTC().Format(time.RFC3339Nano),
+		"validUntil": kr.ValidUntil.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// RotateVerificationMethod replaces the cryptographic material used by an
+// existing verification method with a newly generated key of type `kt`.
+// The rotation is recorded on the identifier's history, including a proof
+// produced by the previous key; hence, keys only usable for key agreement
+// can't be rotated. The previous key remains available to verify signatures
+// produced while it was valid.
+func (d *Identifier) RotateVerificationMethod(id string, kt KeyType) (*KeyRotation, error) {
+	current := d.VerificationMethod(id)
+	if current == nil {
+		return nil, errors.New("invalid key identifier")
+	}
+	if len(current.Private) == 0 {
+		return nil, errors.New("private key is required to rotate a verification method")
+	}
+	if current.Type == KeyTypeX25519 {
+		return nil, errors.New("key agreement keys can't produce rotation proofs")
+	}
+	next, err := newCryptoKey(kt)
+	if err != nil {
+		return nil, err
+	}
+	next.ID = current.ID
+	next.Controller = current.Controller
+
+	// Prepare and sign rotation record
+	rec := &KeyRotation{
+		ID:         current.ID,
+		Previous:   publicKey(current),
+		Next:       publicKey(next),
+		ValidFrom:  d.keyValidFrom(current.ID),
+		ValidUntil: time.Now().UTC(),
+	}
+	st, err := rec.statement()
+	if err != nil {
+		return nil, err
+	}
+	if rec.Proof, err = current.Sign(st); err != nil {
+		return nil, wrap(err, "failed to produce rotation proof")
+	}
+
+	// Replace key
+	for i, k := range d.data.VerificationMethods {
+		if k.ID == current.ID {
+			d.data.VerificationMethods[i] = next
+		}
+	}
+	d.data.Rotations = append(d.data.Rotations, rec)
+	d.update()
+	res := *rec
+	return &res, nil
+}
+
+// RotationHistory returns the rotations recorded for a verification method,
+// sorted from oldest to newest. If `id` is empty the history for all
+// verification methods is returned.
+func (d *Identifier) RotationHistory(id string) []KeyRotation {
+	if id != "" && !strings.HasPrefix(id, prefix) {
+		id = d.GetReference(id)
+	}
+	var list []KeyRotation
+	for _, rec := range d.data.Rotations {
+		if id == "" || rec.ID == id {
+			list = append(list, *rec)
+		}
+	}
+	return list
+}
+
+// RestoreRotationHistory loads previously recorded rotations, for example
+// when restoring an identifier using 'FromDocument'. All records are
+// verified, and for each verification method the records must form an
+// unbroken chain ending on its current key.
+func (d *Identifier) RestoreRotationHistory(records []KeyRotation) error {
+	sorted := append([]KeyRotation{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ValidUntil.Before(sorted[j].ValidUntil)
+	})
+	last := make(map[string]*KeyRotation)
+	for i, rec := range sorted {
+		if !rec.Verify() {
+			return errors.Errorf("invalid rotation proof for '%s'", rec.ID)
+		}
+		if prev, ok := last[rec.ID]; ok {
+			if publicKeyValue(&prev.Next) != publicKeyValue(&rec.Previous) || !prev.ValidUntil.Equal(rec.ValidFrom) {
+				return errors.Errorf("broken rotation history for '%s'", rec.ID)
+			}
+		}
+		last[rec.ID] = &sorted[i]
+	}
+	for id, rec := range last {
+		current := d.VerificationMethod(id)
+		if current == nil || publicKeyValue(current) != publicKeyValue(&rec.Next) {
+			return errors.Errorf("rotation history doesn't match current key for '%s'", id)
+		}
+	}
+	d.data.Rotations = make([]*KeyRotation, len(sorted))
+	for i := range sorted {
+		d.data.Rotations[i] = &sorted[i]
+	}
+	return nil
+}
+
+// VerificationMethodAt returns the key used by a verification method at
+// the time `t`, considering its rotation history. "nil" is returned if the
+// verification method didn't exist at the time provided.
+func (d *Identifier) VerificationMethodAt(id string, t time.Time) *VerificationKey {
+	current := d.VerificationMethod(id)
+	if current == nil {
+		return nil
+	}
+	for _, rec := range d.data.Rotations {
+		if rec.ID != current.ID {
+			continue
+		}
+		if t.Before(rec.ValidFrom) {
+			return nil
+		}
+		if t.Before(rec.ValidUntil) {
+			key := rec.Previous
+			return &key
+		}
+	}
+	if t.Before(d.keyValidFrom(current.ID)) {
+		return nil
+	}
+	return current
+}
+
+// VerifyAt validates a signature produced by a verification method at the
+// time `t`; i.e., using the key valid at the time, even if it was already
+// rotated.
+func (d *Identifier) VerifyAt(id string, data, signature []byte, t time.Time) bool {
+	key := d.VerificationMethodAt(id, t)
+	if key == nil {
+		return false
+	}
+	for _, rec := range d.data.Rotations {
+		if rec.ID == key.ID && !rec.Verify() {
+			return false
+		}
+	}
+	return key.Verify(data, signature)
+}
+
+// Start of the validity interval for the current key of a verification
+// method; i.e., the time of its latest rotation or, if never rotated, the
+// creation time of the identifier.
+func (d *Identifier) keyValidFrom(id string) time.Time {
+	for i := len(d.data.Rotations) - 1; i >= 0; i-- {
+		if d.data.Rotations[i].ID == id {
+			return d.data.Rotations[i].ValidUntil
+		}
+	}
+	if d.data.Created != nil {
+		return *d.data.Created
+	}
+	return time.Time{}
+}
+
+// Return a copy of the key without its private material.
+func publicKey(k *VerificationKey) VerificationKey {
+	pk := *k
+	pk.Private = nil
+	pk.Extensions = nil
+	return pk
+}
+
+// Encoded public key material.
+func publicKeyValue(k *VerificationKey) string {
+	if k.Public != "" {
+		return k.Public
+	}
+	return k.PublicKeyBase58
+}
@@ -0,0 +1,65 @@
+package did
+
+import (
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestRotateVerificationMethod(t *testing.T) {
+	assert := tdd.New(t)
+	id, err := NewIdentifierWithMode("bryk", "", ModeUUID)
+	assert.Nil(err, "new identifier")
+	assert.Nil(id.AddNewVerificationMethod("master", KeyTypeEd))
+	assert.Nil(id.AddVerificationRelationship(id.GetReference("master"), AuthenticationVM))
+	assert.Nil(id.AddNewVerificationMethod("enc", KeyTypeX25519))
+
+	// Signature produced with the original key
+	msg := []byte("original message")
+	t0 := time.Now()
+	sig0, err := id.VerificationMethod("master").Sign(msg)
+	assert.Nil(err, "sign")
+
+	// Rotate keys
+	_, err = id.RotateVerificationMethod("enc", KeyTypeX25519)
+	assert.NotNil(err, "key agreement keys can't be rotated")
+	_, err = id.RotateVerificationMethod("unknown", KeyTypeEd)
+	assert.NotNil(err, "invalid key")
+	r1, err := id.RotateVerificationMethod("master", KeyTypeP256)
+	assert.Nil(err, "rotate")
+	assert.True(r1.Verify(), "rotation proof")
+	assert.Empty(r1.Previous.Private)
+	t1 := time.Now()
+	sig1, _ := id.VerificationMethod("master").Sign(msg)
+	r2, err := id.RotateVerificationMethod("master", KeyTypeEd)
+	assert.Nil(err, "rotate")
+	assert.Equal(r1.ValidUntil, r2.ValidFrom)
+	assert.Equal(KeyTypeEd, id.VerificationMethod("master").Type)
+	assert.Equal([]string{id.GetReference("master")}, id.Document(true).Authentication)
+
+	// Verify signatures against the key valid at the time
+	assert.False(id.VerificationMethod("master").Verify(msg, sig0), "current key")
+	assert.True(id.VerifyAt("master", msg, sig0, t0), "original key")
+	assert.False(id.VerifyAt("master", msg, sig0, t1), "rotated key")
+	assert.True(id.VerifyAt("master", msg, sig1, t1), "second key")
+	assert.False(id.VerifyAt("master", msg, sig1, time.Now()), "current key")
+	assert.Nil(id.VerificationMethodAt("master", t0.Add(-1*time.Hour)), "before creation")
+
+	// History
+	history := id.RotationHistory("master")
+	assert.Len(history, 2)
+	assert.Len(id.RotationHistory(""), 2)
+	assert.Empty(id.RotationHistory("enc"))
+
+	// Restore
+	restored, err := FromDocument(id.Document(false))
+	assert.Nil(err, "from document")
+	assert.NotNil(restored.RestoreRotationHistory(history[:1]), "broken chain")
+	assert.NotNil(restored.RestoreRotationHistory([]KeyRotation{history[1], history[1]}), "broken chain")
+	tampered := append([]KeyRotation{}, history...)
+	tampered[0].ValidFrom = tampered[0].ValidFrom.Add(-1 * time.Hour)
+	assert.NotNil(restored.RestoreRotationHistory(tampered), "invalid proof")
+	assert.Nil(restored.RestoreRotationHistory([]KeyRotation{history[1], history[0]}), "restore")
+	assert.True(restored.VerifyAt("master", msg, sig0, t0), "original key")
+}
@@ -0,0 +1,236 @@
+package did
+
+import (
+	"encoding/json"
+	"net/url"
+	"sync"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Well-known service types.
+// https://www.w3.org/TR/did-spec-registries/#service-types
+const (
+	// ServiceLinkedDomains links the DID to one or more web origins.
+	// https://identity.foundation/.well-known/resources/did-configuration/#linked-domain-service-endpoint
+	ServiceLinkedDomains = "LinkedDomains"
+
+	// ServiceDIDCommMessaging advertises DIDComm v2 messaging endpoints.
+	// https://identity.foundation/didcomm-messaging/spec/v2.0/#service-endpoint
+	ServiceDIDCommMessaging = "DIDCommMessaging"
+
+	// ServiceCredentialRegistry advertises a registry of verifiable
+	// credentials.
+	// https://www.w3.org/TR/did-spec-registries/#credentialregistry
+	ServiceCredentialRegistry = "CredentialRegistry"
+)
+
+// ServiceValidator verifies the endpoint structure of a service type.
+type ServiceValidator func(se *ServiceEndpoint) error
+
+// Registered service types.
+var (
+	serviceTypes = map[string]ServiceValidator{
+		ServiceLinkedDomains:      validateLinkedDomains,
+		ServiceDIDCommMessaging:   validateDIDComm,
+		ServiceCredentialRegistry: validateCredentialRegistry,
+	}
+	serviceTypesMu sync.RWMutex
+)
+
+// RegisterServiceType enables validation of the endpoint structure for a
+// service type. Registering an existing type replaces its validator.
+// Services of unregistered types must use a URI as endpoint.
+func RegisterServiceType(name string, validator ServiceValidator) {
+	serviceTypesMu.Lock()
+	serviceTypes[name] = validator
+	serviceTypesMu.Unlock()
+}
+
+// DIDCommEndpoint is the endpoint structure used by "DIDCommMessaging"
+// services.
+type DIDCommEndpoint struct {
+	// Endpoint URI; can also be the DID of a mediator.
+	URI string `json:"uri"`
+
+	// Media types supported, in order of preference.
+	Accept []string `json:"accept,omitempty"`
+
+	// Keys of the mediators messages must be forwarded through, as DID
+	// URLs.
+	RoutingKeys []string `json:"routingKeys,omitempty"`
+}
+
+// ValidateEndpoint verifies the endpoint structure of the service. For
+// registered service types the type-specific validation is used; otherwise
+// the endpoint must be a URI.
+func (se *ServiceEndpoint) ValidateEndpoint() error {
+	serviceTypesMu.RLock()
+	validator, ok := serviceTypes[se.Type]
+	serviceTypesMu.RUnlock()
+	if ok {
+		return validator(se)
+	}
+	if se.Value != nil {
+		return nil
+	}
+	if !isURI(se.Endpoint) {
+		return errors.Errorf("invalid URI '%s'", se.Endpoint)
+	}
+	return nil
+}
+
+// DIDCommMessaging returns the endpoints of a "DIDCommMessaging" service.
+func (se *ServiceEndpoint) DIDCommMessaging() ([]DIDCommEndpoint, error) {
+	if se.Type != ServiceDIDCommMessaging {
+		return nil, errors.Errorf("invalid service type: %s", se.Type)
+	}
+	if se.Value == nil {
+		return []DIDCommEndpoint{{URI: se.Endpoint}}, nil
+	}
+	list := []DIDCommEndpoint{}
+	if err := se.decodeValue(&list); err == nil {
+		return list, nil
+	}
+	ep := DIDCommEndpoint{}
+	if err := se.decodeValue(&ep); err != nil {
+		return nil, errors.New("invalid DIDComm endpoint")
+	}
+	return []DIDCommEndpoint{ep}, nil
+}
+
+// LinkedDomains returns the origins of a "LinkedDomains" service.
+func (se *ServiceEndpoint) LinkedDomains() ([]string, error) {
+	if se.Type != ServiceLinkedDomains {
+		return nil, errors.Errorf("invalid service type: %s", se.Type)
+	}
+	if se.Value == nil {
+		return []string{se.Endpoint}, nil
+	}
+	ep := struct {
+		Origins []string `json:"origins"`
+	}{}
+	if err := se.decodeValue(&ep); err != nil || len(ep.Origins) == 0 {
+		return nil, errors.New("invalid linked domains endpoint")
+	}
+	return ep.Origins, nil
+}
+
+// CredentialRegistry returns the location of a "CredentialRegistry"
+// service.
+func (se *ServiceEndpoint) CredentialRegistry() (*url.URL, error) {
+	if se.Type != ServiceCredentialRegistry {
+		return nil, errors.Errorf("invalid service type: %s", se.Type)
+	}
+	if se.Value != nil || !isURI(se.Endpoint) {
+		return nil, errors.New("invalid credential registry endpoint")
+	}
+	return url.Parse(se.Endpoint)
+}
+
+// MarshalJSON provides custom encoding implementation.
+func (se ServiceEndpoint) MarshalJSON() ([]byte, error) {
+	type alias ServiceEndpoint
+	val := struct {
+		alias
+		Endpoint interface{} `json:"serviceEndpoint"`
+	}{alias: alias(se), Endpoint: se.Endpoint}
+	if se.Value != nil {
+		val.Endpoint = se.Value
+	}
+	return json.Marshal(val)
+}
+
+// UnmarshalJSON provides custom decoding implementation.
+func (se *ServiceEndpoint) UnmarshalJSON(b []byte) error {
+	type alias ServiceEndpoint
+	val := struct {
+		*alias
+		Endpoint json.RawMessage `json:"serviceEndpoint"`
+	}{alias: (*alias)(se)}
+	if err := json.Unmarshal(b, &val); err != nil {
+		return err
+	}
+	se.Endpoint, se.Value = "", nil
+	if len(val.Endpoint) == 0 || json.Unmarshal(val.Endpoint, &se.Endpoint) == nil {
+		return nil
+	}
+	if err := json.Unmarshal(val.Endpoint, &se.Value); err != nil {
+		return err
+	}
+	se.Endpoint = primaryURI(se.Value)
+	return nil
+}
+
+// Decode the structured endpoint value into `holder`.
+func (se *ServiceEndpoint) decodeValue(holder interface{}) error {
+	js, err := json.Marshal(se.Value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, holder)
+}
+
+// Primary URI for a structured endpoint value; i.e., the first URI
+// available on it.
+func primaryURI(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case []interface{}:
+		for _, el := range v {
+			if uri := primaryURI(el); uri != "" {
+				return uri
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range []string{"uri", "origins"} {
+			if uri := primaryURI(v[k]); uri != "" {
+				return uri
+			}
+		}
+	}
+	return ""
+}
+
+// "LinkedDomains" endpoints are a single origin or a map including a
+// list of origins.
+func validateLinkedDomains(se *ServiceEndpoint) error {
+	origins, err := se.LinkedDomains()
+	if err != nil {
+		return err
+	}
+	for _, origin := range origins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return errors.Errorf("invalid origin '%s'", origin)
+		}
+	}
+	return nil
+}
+
+// "DIDCommMessaging" endpoints are a URI, a map or a list of maps. Routing
+// keys must be DID URLs.
+func validateDIDComm(se *ServiceEndpoint) error {
+	endpoints, err := se.DIDCommMessaging()
+	if err != nil {
+		return err
+	}
+	for _, ep := range endpoints {
+		if !isURI(ep.URI) {
+			return errors.Errorf("invalid URI '%s'", ep.URI)
+		}
+		for _, rk := range ep.RoutingKeys {
+			if id, err := Parse(rk); err != nil || id.Fragment() == "" {
+				return errors.Errorf("invalid routing key '%s'", rk)
+			}
+		}
+	}
+	return nil
+}
+
+// "CredentialRegistry" endpoints must be a URI.
+func validateCredentialRegistry(se *ServiceEndpoint) error {
+	_, err := se.CredentialRegistry()
+	return err
+}
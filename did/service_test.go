@@ -0,0 +1,86 @@
+package did
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestServiceEndpoint(t *testing.T) {
+	assert := tdd.New(t)
+	id, err := NewIdentifierWithMode("bryk", "", ModeUUID)
+	assert.Nil(err, "new identifier")
+	mediator := "did:example:mediator#key-1"
+	assert.Nil(id.AddService(&ServiceEndpoint{
+		ID:   "didcomm",
+		Type: ServiceDIDCommMessaging,
+		Value: DIDCommEndpoint{
+			URI:         "https://example.com/didcomm",
+			Accept:      []string{"didcomm/v2"},
+			RoutingKeys: []string{mediator},
+		},
+	}))
+	assert.Nil(id.AddService(&ServiceEndpoint{
+		ID:    "domains",
+		Type:  ServiceLinkedDomains,
+		Value: map[string][]string{"origins": {"https://example.com", "https://example.org"}},
+	}))
+	assert.Nil(id.AddService(&ServiceEndpoint{
+		ID:       "registry",
+		Type:     ServiceCredentialRegistry,
+		Endpoint: "https://example.com/credentials",
+	}))
+
+	// Structured values are preserved when encoding
+	js, err := json.Marshal(id.Document(true))
+	assert.Nil(err, "encode")
+	assert.True(strings.Contains(string(js), `"routingKeys":["did:example:mediator#key-1"]`))
+	doc := new(Document)
+	assert.Nil(json.Unmarshal(js, doc), "decode")
+	assert.Empty(doc.Validate(), "valid document")
+
+	// Typed accessors
+	dc, err := doc.Services[0].DIDCommMessaging()
+	assert.Nil(err, "DIDComm endpoints")
+	assert.Equal("https://example.com/didcomm", doc.Services[0].Endpoint)
+	assert.Equal([]string{mediator}, dc[0].RoutingKeys)
+	origins, err := doc.Services[1].LinkedDomains()
+	assert.Nil(err, "linked domains")
+	assert.Equal([]string{"https://example.com", "https://example.org"}, origins)
+	reg, err := doc.Services[2].CredentialRegistry()
+	assert.Nil(err, "credential registry")
+	assert.Equal("/credentials", reg.Path)
+	_, err = doc.Services[2].DIDCommMessaging()
+	assert.NotNil(err, "invalid service type")
+
+	// String endpoints
+	se := &ServiceEndpoint{Type: ServiceDIDCommMessaging, Endpoint: "https://example.com/didcomm"}
+	dc, err = se.DIDCommMessaging()
+	assert.Nil(err)
+	assert.Equal("https://example.com/didcomm", dc[0].URI)
+
+	// Invalid endpoint structures
+	invalid := []*ServiceEndpoint{
+		{Type: ServiceDIDCommMessaging, Value: DIDCommEndpoint{URI: "https://example.com", RoutingKeys: []string{"invalid"}}},
+		{Type: ServiceLinkedDomains, Endpoint: "https://example.com/path"},
+		{Type: ServiceLinkedDomains, Value: map[string]interface{}{"origins": []string{}}},
+		{Type: ServiceCredentialRegistry, Value: []string{"https://example.com"}},
+		{Type: "CustomService", Endpoint: "invalid"},
+	}
+	for _, se := range invalid {
+		assert.NotNil(se.ValidateEndpoint(), se.Type)
+	}
+
+	// Custom service types
+	RegisterServiceType("CustomService", func(se *ServiceEndpoint) error {
+		if !strings.HasPrefix(se.Endpoint, "urn:") {
+			return errors.New("URN required")
+		}
+		return nil
+	})
+	assert.Nil((&ServiceEndpoint{Type: "CustomService", Endpoint: "urn:example:1"}).ValidateEndpoint())
+	assert.NotNil((&ServiceEndpoint{Type: "CustomService", Endpoint: "https://example.com"}).ValidateEndpoint())
+}
@@ -25,6 +25,9 @@ func multibaseEncode(data []byte) string {
 
 // https://datatracker.ietf.org/doc/html/draft-multiformats-multibase-03
 func multibaseDecode(src string) ([]byte, error) {
+	if len(src) < 2 {
+		return nil, fmt.Errorf("invalid multibase value: '%s'", src)
+	}
 	base := src[:1]
 	data := src[1:]
 	// https://datatracker.ietf.org/doc/html/draft-multiformats-multibase-03#appendix-D.1
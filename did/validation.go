@@ -0,0 +1,233 @@
+package did
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Violation describes a conformance issue found on a DID document.
+type Violation struct {
+	// Document property affected, using a JSON path-like notation; for
+	// example "verificationMethod[0].id".
+	Property string `json:"property" yaml:"property"`
+
+	// Description of the issue.
+	Message string `json:"message" yaml:"message"`
+}
+
+// String returns a textual representation of the violation.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Property, v.Message)
+}
+
+// Validate the document structure and its conformance with the DID core
+// specification. The returned list include all violations found, or is
+// empty for valid documents. Documents including private key material are
+// considered invalid, making this method suitable to validate documents
+// before being published to a verifiable data registry.
+// https://www.w3.org/TR/did-core/#conformance
+func (d *Document) Validate() []Violation {
+	dv := &docValidator{doc: d, ids: make(map[string]string)}
+	dv.context()
+	dv.subject()
+	dv.alsoKnownAs()
+	dv.methods()
+	dv.relationships()
+	dv.services()
+	return dv.list
+}
+
+// Internal helper used to collect document violations.
+type docValidator struct {
+	doc  *Document
+	did  string            // DID of the subject, if valid
+	ids  map[string]string // absolute ID -> property using it
+	list []Violation
+}
+
+// Register a new violation.
+func (dv *docValidator) add(prop, msg string, args ...interface{}) {
+	dv.list = append(dv.list, Violation{Property: prop, Message: fmt.Sprintf(msg, args...)})
+}
+
+// The first context entry must be the DID core context.
+func (dv *docValidator) context() {
+	if len(dv.doc.Context) == 0 {
+		dv.add("@context", "value is required")
+		return
+	}
+	if ctx, ok := dv.doc.Context[0].(string); !ok || ctx != defaultContext {
+		dv.add("@context[0]", "must be '%s'", defaultContext)
+	}
+	for i, ctx := range dv.doc.Context {
+		switch ctx.(type) {
+		case string, map[string]interface{}:
+		default:
+			dv.add(fmt.Sprintf("@context[%d]", i), "must be a URI or a context definition")
+		}
+	}
+}
+
+// Subject and controller values must be valid DIDs.
+func (dv *docValidator) subject() {
+	id, err := Parse(dv.doc.Subject)
+	switch {
+	case dv.doc.Subject == "":
+		dv.add("id", "value is required")
+	case err != nil:
+		dv.add("id", "invalid DID '%s'", dv.doc.Subject)
+	case id.IsURL():
+		dv.add("id", "must be a DID, not a DID URL")
+	default:
+		dv.did = id.DID()
+	}
+	if dv.doc.Controller != "" {
+		if c, err := Parse(dv.doc.Controller); err != nil || c.IsURL() {
+			dv.add("controller", "invalid DID '%s'", dv.doc.Controller)
+		}
+	}
+}
+
+// Alternative identifiers must be valid URIs.
+func (dv *docValidator) alsoKnownAs() {
+	for i, aka := range dv.doc.AlsoKnownAs {
+		if !isURI(aka) {
+			dv.add(fmt.Sprintf("alsoKnownAs[%d]", i), "invalid URI '%s'", aka)
+		}
+	}
+}
+
+// Verification methods must have unique and valid identifiers, a valid
+// controller and public key material.
+func (dv *docValidator) methods() {
+	for i, vm := range dv.doc.VerificationMethod {
+		prop := fmt.Sprintf("verificationMethod[%d]", i)
+		dv.uniqueID(prop, vm.ID, true)
+		if vm.Type.String() == "unknown key type" {
+			dv.add(prop+".type", "unsupported key type")
+		}
+		if c, err := Parse(vm.Controller); err != nil || c.IsURL() {
+			dv.add(prop+".controller", "invalid DID '%s'", vm.Controller)
+		}
+		switch {
+		case vm.Public != "" && vm.PublicKeyBase58 != "":
+			dv.add(prop, "only one public key representation is allowed")
+		case vm.Public == "" && vm.PublicKeyBase58 == "":
+			dv.add(prop, "public key material is required")
+		default:
+			if pub, err := vm.Bytes(); err != nil || len(pub) == 0 {
+				dv.add(prop, "invalid public key material for '%s'", vm.Type)
+			}
+		}
+		if len(vm.Private) > 0 {
+			dv.add(prop+".private", "private key material must not be included")
+		}
+	}
+}
+
+// Verification relationships must reference valid verification methods;
+// methods on the document itself must exist. Key agreement keys can't be
+// used to produce signatures, so are only valid for "keyAgreement".
+func (dv *docValidator) relationships() {
+	rels := []struct {
+		name string
+		refs []string
+	}{
+		{"authentication", dv.doc.Authentication},
+		{"assertionMethod", dv.doc.AssertionMethod},
+		{"keyAgreement", dv.doc.KeyAgreement},
+		{"capabilityInvocation", dv.doc.CapabilityInvocation},
+		{"capabilityDelegation", dv.doc.CapabilityDelegation},
+	}
+	for _, rel := range rels {
+		seen := make(map[string]bool)
+		for i, ref := range rel.refs {
+			prop := fmt.Sprintf("%s[%d]", rel.name, i)
+			abs := dv.absolute(ref)
+			id, err := Parse(abs)
+			if err != nil || id.Fragment() == "" {
+				dv.add(prop, "invalid DID URL '%s'", ref)
+				continue
+			}
+			if seen[abs] {
+				dv.add(prop, "duplicated reference '%s'", ref)
+			}
+			seen[abs] = true
+			if id.DID() != dv.did {
+				continue // external verification method
+			}
+			vm := dv.method(abs)
+			if vm == nil {
+				dv.add(prop, "unknown verification method '%s'", ref)
+				continue
+			}
+			if (vm.Type == KeyTypeX25519) != (rel.name == "keyAgreement") {
+				dv.add(prop, "key type '%s' can't be used for '%s'", vm.Type, rel.name)
+			}
+		}
+	}
+}
+
+// Services must have unique identifiers, a type and a valid endpoint; the
+// endpoint structure is validated for registered service types.
+func (dv *docValidator) services() {
+	for i, se := range dv.doc.Services {
+		prop := fmt.Sprintf("service[%d]", i)
+		dv.uniqueID(prop, se.ID, false)
+		if se.Type == "" {
+			dv.add(prop+".type", "value is required")
+		}
+		if err := se.ValidateEndpoint(); err != nil {
+			dv.add(prop+".serviceEndpoint", err.Error())
+		}
+	}
+}
+
+// Verify the `id` value of a document element is valid and unique. When
+// `didURL` is true, the value must be a DID URL.
+func (dv *docValidator) uniqueID(prop, id string, didURL bool) {
+	if id == "" {
+		dv.add(prop+".id", "value is required")
+		return
+	}
+	abs := dv.absolute(id)
+	if didURL {
+		if ref, err := Parse(abs); err != nil || !ref.IsURL() {
+			dv.add(prop+".id", "invalid DID URL '%s'", id)
+			return
+		}
+	} else if !isURI(abs) {
+		dv.add(prop+".id", "invalid URI '%s'", id)
+		return
+	}
+	if prev, ok := dv.ids[abs]; ok {
+		dv.add(prop+".id", "duplicated identifier, already used by '%s'", prev)
+		return
+	}
+	dv.ids[abs] = prop
+}
+
+// Return the verification method with the provided absolute ID.
+func (dv *docValidator) method(id string) *VerificationKey {
+	for i, vm := range dv.doc.VerificationMethod {
+		if dv.absolute(vm.ID) == id {
+			return &dv.doc.VerificationMethod[i]
+		}
+	}
+	return nil
+}
+
+// Resolve relative DID URLs (e.g., "#key-1") against the document subject.
+func (dv *docValidator) absolute(ref string) string {
+	if strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "?") || strings.HasPrefix(ref, "/") {
+		return dv.doc.Subject + ref
+	}
+	return ref
+}
+
+// Verify `val` is an absolute URI.
+func isURI(val string) bool {
+	u, err := url.Parse(val)
+	return err == nil && u.Scheme != ""
+}
@@ -0,0 +1,94 @@
+package did
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestDocumentValidate(t *testing.T) {
+	assert := tdd.New(t)
+
+	id, err := NewIdentifierWithMode("bryk", "sample-network", ModeUUID)
+	assert.Nil(err, "new identifier")
+	assert.Nil(id.AddNewVerificationMethod("key-1", KeyTypeEd))
+	assert.Nil(id.AddNewVerificationMethod("key-2", KeyTypeRSA))
+	assert.Nil(id.AddNewVerificationMethod("enc", KeyTypeX25519))
+	assert.Nil(id.AddVerificationRelationship(id.GetReference("key-1"), AuthenticationVM))
+	assert.Nil(id.AddVerificationRelationship(id.GetReference("key-2"), AssertionVM))
+	assert.Nil(id.AddVerificationRelationship(id.GetReference("enc"), KeyAgreementVM))
+	assert.Nil(id.AddService(&ServiceEndpoint{
+		ID:       "profile",
+		Type:     "ProfileService",
+		Endpoint: "https://example.com/profile",
+	}))
+
+	// Valid document
+	assert.Empty(id.Document(true).Validate())
+
+	// Private keys must not be published
+	assert.Len(id.Document(false).Validate(), 3)
+
+	// Relative references
+	doc := id.Document(true)
+	doc.Authentication = []string{"#key-1"}
+	assert.Empty(doc.Validate())
+
+	cases := map[string]func(doc *Document){
+		"@context": func(doc *Document) {
+			doc.Context = nil
+		},
+		"@context[0]": func(doc *Document) {
+			doc.Context = []interface{}{"https://example.com/context.json"}
+		},
+		"id": func(doc *Document) {
+			doc.Subject = "invalid-did"
+		},
+		"controller": func(doc *Document) {
+			doc.Controller = doc.Subject + "#key-1"
+		},
+		"alsoKnownAs[0]": func(doc *Document) {
+			doc.AlsoKnownAs = []string{"not-a-uri"}
+		},
+		"verificationMethod[1].id": func(doc *Document) {
+			doc.VerificationMethod[1].ID = doc.VerificationMethod[0].ID
+		},
+		"verificationMethod[0]": func(doc *Document) {
+			doc.VerificationMethod[0].Public = ""
+		},
+		"verificationMethod[0].controller": func(doc *Document) {
+			doc.VerificationMethod[0].Controller = ""
+		},
+		"authentication[1]": func(doc *Document) {
+			doc.Authentication = append(doc.Authentication, "#key-1")
+		},
+		"assertionMethod[0]": func(doc *Document) {
+			doc.AssertionMethod = []string{doc.Subject + "#unknown"}
+		},
+		"capabilityInvocation[0]": func(doc *Document) {
+			doc.CapabilityInvocation = []string{doc.Subject + "#enc"}
+		},
+		"keyAgreement[0]": func(doc *Document) {
+			doc.KeyAgreement = []string{doc.Subject}
+		},
+		"service[0].id": func(doc *Document) {
+			doc.Services[0].ID = doc.VerificationMethod[0].ID
+		},
+		"service[0].serviceEndpoint": func(doc *Document) {
+			doc.Services[0].Endpoint = "example.com"
+		},
+	}
+	for prop, tamper := range cases {
+		doc := id.Document(true)
+		tamper(doc)
+		list := doc.Validate()
+		if assert.NotEmpty(list, prop) {
+			assert.Equal(prop, list[0].Property, list[0].String())
+		}
+	}
+
+	// External verification methods
+	doc = id.Document(true)
+	doc.CapabilityDelegation = []string{"did:example:123#key-1"}
+	assert.Empty(doc.Validate())
+}
@@ -0,0 +1,215 @@
+package wkc
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// WellKnownPath is the location of the DID configuration resource on
+// the origin.
+const WellKnownPath = "/.well-known/did-configuration.json"
+
+// Contexts used by the configuration resource and domain linkage
+// credentials.
+const (
+	configurationContext = "https://identity.foundation/.well-known/did-configuration/v1"
+	credentialsContext   = "https://www.w3.org/2018/credentials/v1"
+)
+
+// Proof purpose used for domain linkage credentials.
+const proofPurpose = "assertionMethod"
+
+// Configuration is the DID configuration resource published by an origin
+// to prove its control over one or more DIDs.
+// https://identity.foundation/.well-known/resources/did-configuration/
+type Configuration struct {
+	// JSON-LD context statement.
+	Context string `json:"@context"`
+
+	// Domain linkage credentials for the linked DIDs.
+	LinkedDIDs []*DomainLink `json:"linked_dids"`
+}
+
+// NewConfiguration returns an empty DID configuration resource.
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		Context:    configurationContext,
+		LinkedDIDs: []*DomainLink{},
+	}
+}
+
+// Add a domain linkage credential to the configuration resource.
+func (c *Configuration) Add(link *DomainLink) {
+	c.LinkedDIDs = append(c.LinkedDIDs, link)
+}
+
+// DomainLink is a domain linkage credential; a verifiable credential,
+// issued by the DID subject, asserting control over an origin. Only the
+// JSON-LD credential format is supported.
+type DomainLink struct {
+	// JSON-LD context statement.
+	Context []string `json:"@context"`
+
+	// Credential types.
+	Type []string `json:"type"`
+
+	// DID of the credential issuer; must be the same as the subject.
+	Issuer string `json:"issuer"`
+
+	// Credential issuance date, in RFC3339 format.
+	IssuanceDate string `json:"issuanceDate"`
+
+	// Credential expiration date, in RFC3339 format.
+	ExpirationDate string `json:"expirationDate"`
+
+	// DID and origin linked.
+	CredentialSubject LinkSubject `json:"credentialSubject"`
+
+	// Proof produced by the DID subject.
+	Proof *did.ProofLD `json:"proof,omitempty"`
+}
+
+// LinkSubject describes the DID and origin linked by a domain linkage
+// credential.
+type LinkSubject struct {
+	// Linked DID.
+	ID string `json:"id"`
+
+	// Linked origin; i.e., scheme, host and optional port.
+	Origin string `json:"origin"`
+}
+
+// NewDomainLink returns a domain linkage credential for `origin`, signed by
+// the `keyID` verification method of the DID. The key must be enabled
+// for the "assertionMethod" verification relationship. The credential
+// is valid until `expires`.
+func NewDomainLink(id *did.Identifier, keyID, origin string, expires time.Time) (*DomainLink, error) {
+	origin, err := parseOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+	key := id.VerificationMethod(keyID)
+	if key == nil {
+		return nil, errors.New("invalid key identifier")
+	}
+	if !contains(id.GetVerificationRelationship(did.AssertionVM), key.ID) {
+		return nil, errors.New("key is not enabled for 'assertionMethod'")
+	}
+	now := time.Now().UTC()
+	if !expires.After(now) {
+		return nil, errors.New("invalid expiration date")
+	}
+	dl := &DomainLink{
+		Context:        []string{credentialsContext, configurationContext},
+		Type:           []string{"VerifiableCredential", "DomainLinkageCredential"},
+		Issuer:         id.DID(),
+		IssuanceDate:   now.Format(time.RFC3339),
+		ExpirationDate: expires.UTC().Format(time.RFC3339),
+		CredentialSubject: LinkSubject{
+			ID:     id.DID(),
+			Origin: origin,
+		},
+	}
+	data, err := dl.proofInput()
+	if err != nil {
+		return nil, err
+	}
+	if dl.Proof, err = key.ProduceProof(data, proofPurpose, origin); err != nil {
+		return nil, errors.Wrap(err, "failed to produce credential proof")
+	}
+	return dl, nil
+}
+
+// Verify the credential links `origin` with the DID described by `doc`,
+// the document obtained when resolving the credential subject.
+func (dl *DomainLink) Verify(doc *did.Document, origin string) error {
+	origin, err := parseOrigin(origin)
+	if err != nil {
+		return err
+	}
+
+	// Credential contents
+	if !contains(dl.Type, "DomainLinkageCredential") {
+		return errors.New("invalid credential type")
+	}
+	if dl.Issuer != dl.CredentialSubject.ID {
+		return errors.New("credential issuer and subject must be the same")
+	}
+	if dl.CredentialSubject.Origin != origin {
+		return errors.Errorf("credential is not valid for origin: %s", origin)
+	}
+	if doc == nil || doc.Subject != dl.Issuer {
+		return errors.New("invalid DID document")
+	}
+	issued, err := time.Parse(time.RFC3339, dl.IssuanceDate)
+	if err != nil {
+		return errors.Wrap(err, "invalid issuance date")
+	}
+	expires, err := time.Parse(time.RFC3339, dl.ExpirationDate)
+	if err != nil {
+		return errors.Wrap(err, "invalid expiration date")
+	}
+	now := time.Now()
+	if now.Before(issued) || now.After(expires) {
+		return errors.New("credential is not valid at this time")
+	}
+
+	// Credential proof
+	if dl.Proof == nil {
+		return errors.New("missing credential proof")
+	}
+	if dl.Proof.Purpose != proofPurpose || dl.Proof.Domain != origin {
+		return errors.New("invalid credential proof")
+	}
+	id, err := did.FromDocument(doc)
+	if err != nil {
+		return errors.Wrap(err, "invalid DID document")
+	}
+	key := id.VerificationMethod(dl.Proof.VerificationMethod)
+	if key == nil || !contains(id.GetVerificationRelationship(did.AssertionVM), key.ID) {
+		return errors.New("invalid credential proof verification method")
+	}
+	data, err := dl.proofInput()
+	if err != nil {
+		return err
+	}
+	if !key.VerifyProof(data, dl.Proof) {
+		return errors.New("invalid credential proof")
+	}
+	return nil
+}
+
+// Data covered by the credential proof; the JSON encoding of the
+// credential without its proof.
+func (dl *DomainLink) proofInput() ([]byte, error) {
+	cp := *dl
+	cp.Proof = nil
+	return json.Marshal(cp)
+}
+
+// Validate and normalize an origin value; must be an HTTPS URL without
+// path, query or fragment.
+func parseOrigin(origin string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(origin, "/"))
+	if err != nil {
+		return "", errors.Wrap(err, "invalid origin")
+	}
+	if u.Scheme != "https" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", errors.Errorf("invalid origin: %s", origin)
+	}
+	return u.Scheme + "://" + strings.ToLower(u.Host), nil
+}
+
+func contains(list []string, val string) bool {
+	for _, v := range list {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+/*
+Package wkc provides support for the "Well Known DID Configuration" specification.
+
+An origin (i.e., a web domain) can prove control over one or more DIDs by
+publishing a DID configuration resource at a well-known location. The resource
+includes "domain linkage credentials", issued by each DID, asserting control
+over the origin. Verifiers retrieve the resource, resolve the DIDs referenced
+and validate the credentials; establishing a bidirectional link between the
+origin and the DIDs.
+
+# Publish a configuration
+
+	link, _ := NewDomainLink(id, "master", "https://example.com", time.Now().AddDate(1, 0, 0))
+	conf := NewConfiguration()
+	conf.Add(link)
+	http.Handle(WellKnownPath, Handler(conf))
+
+# Verify an origin
+
+	verifier, _ := NewVerifier(WithResolver(didResolver))
+	dids, err := verifier.Verify(context.TODO(), "https://example.com")
+
+Only the JSON-LD format for domain linkage credentials is supported.
+
+More information:
+https://identity.foundation/.well-known/resources/did-configuration/
+*/
+package wkc
@@ -0,0 +1,31 @@
+package wkc
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler serves the DID configuration resource. The handler is usually
+// registered at the well-known location of the origin.
+//
+//	http.Handle(wkc.WellKnownPath, wkc.Handler(conf))
+func Handler(conf *Configuration) http.HandlerFunc {
+	return func(rw http.ResponseWriter, rq *http.Request) {
+		if rq.Method != http.MethodGet && rq.Method != http.MethodHead {
+			rw.Header().Set("Allow", "GET, HEAD")
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		js, err := json.Marshal(conf)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Access-Control-Allow-Origin", "*")
+		rw.WriteHeader(http.StatusOK)
+		if rq.Method == http.MethodGet {
+			_, _ = rw.Write(js)
+		}
+	}
+}
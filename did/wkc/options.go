@@ -0,0 +1,46 @@
+package wkc
+
+import (
+	"net/http"
+	"time"
+
+	"go.bryk.io/pkg/did/resolver"
+	"go.bryk.io/pkg/errors"
+)
+
+// Option elements provide a functional-style configuration mechanism
+// for verifier instances.
+type Option func(v *Verifier) error
+
+// WithResolver sets the resolver instance used to obtain the DID documents
+// for linked DIDs.
+func WithResolver(r *resolver.Instance) Option {
+	return func(v *Verifier) error {
+		if r == nil {
+			return errors.New("invalid resolver instance")
+		}
+		v.resolver = r
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to retrieve DID configuration
+// resources. Uses 'http.DefaultClient' by default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(v *Verifier) error {
+		if client == nil {
+			return errors.New("invalid HTTP client")
+		}
+		v.client = client
+		return nil
+	}
+}
+
+// WithTimeout sets the maximum time allowed to retrieve a DID
+// configuration resource. Defaults to 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *Verifier) error {
+		v.timeout = timeout
+		return nil
+	}
+}
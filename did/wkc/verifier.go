@@ -0,0 +1,108 @@
+package wkc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"go.bryk.io/pkg/did/resolver"
+	"go.bryk.io/pkg/errors"
+)
+
+// Maximum size, in bytes, accepted for a DID configuration resource.
+const maxResourceSize = 1 << 20
+
+// Verifier instances retrieve the DID configuration resource published by
+// an origin and validate its domain linkage credentials.
+type Verifier struct {
+	resolver *resolver.Instance
+	client   *http.Client
+	timeout  time.Duration
+}
+
+// NewVerifier returns a new verifier instance. By default, a resolver
+// instance supporting the "did:key" and "did:peer" methods is used.
+func NewVerifier(opts ...Option) (*Verifier, error) {
+	v := &Verifier{
+		client:  http.DefaultClient,
+		timeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	if v.resolver == nil {
+		r, err := resolver.New()
+		if err != nil {
+			return nil, err
+		}
+		v.resolver = r
+	}
+	return v, nil
+}
+
+// Fetch retrieves the DID configuration resource published by `origin`.
+func (v *Verifier) Fetch(ctx context.Context, origin string) (*Configuration, error) {
+	origin, err := parseOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+WellKnownPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := v.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve DID configuration")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to retrieve DID configuration: %s", res.Status)
+	}
+	conf := new(Configuration)
+	if err = json.NewDecoder(io.LimitReader(res.Body, maxResourceSize)).Decode(conf); err != nil {
+		return nil, errors.Wrap(err, "invalid DID configuration")
+	}
+	if conf.Context != configurationContext {
+		return nil, errors.New("invalid DID configuration context")
+	}
+	return conf, nil
+}
+
+// Verify retrieves the DID configuration resource published by `origin`,
+// resolves the DIDs referenced and validates every domain linkage
+// credential. The list of DIDs linked to the origin is returned; an
+// error is returned if the resource is not available, doesn't include any
+// credentials or if any credential is invalid.
+func (v *Verifier) Verify(ctx context.Context, origin string) ([]string, error) {
+	conf, err := v.Fetch(ctx, origin)
+	if err != nil {
+		return nil, err
+	}
+	if len(conf.LinkedDIDs) == 0 {
+		return nil, errors.New("no linked DIDs")
+	}
+	var linked []string
+	for i, dl := range conf.LinkedDIDs {
+		if dl == nil {
+			return nil, errors.Errorf("invalid domain linkage credential: %d", i)
+		}
+		res, err := v.resolver.Resolve(dl.CredentialSubject.ID, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve '%s'", dl.CredentialSubject.ID)
+		}
+		if err = dl.Verify(res.Document, origin); err != nil {
+			return nil, errors.Wrapf(err, "invalid domain linkage credential for '%s'", dl.CredentialSubject.ID)
+		}
+		linked = append(linked, dl.CredentialSubject.ID)
+	}
+	return linked, nil
+}
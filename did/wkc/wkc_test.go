@@ -0,0 +1,97 @@
+package wkc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func TestDomainLink(t *testing.T) {
+	assert := tdd.New(t)
+	id, err := did.NewKeyIdentifier(did.KeyTypeEd)
+	assert.Nil(err, "new identifier")
+	key := id.VerificationMethods()[0].ID
+	origin := "https://example.com"
+	expires := time.Now().Add(time.Hour)
+
+	link, err := NewDomainLink(id, key, origin, expires)
+	assert.Nil(err, "new domain link")
+	assert.Equal(id.DID(), link.Issuer)
+	assert.Equal(origin, link.CredentialSubject.Origin)
+	doc := id.Document(true)
+	assert.Nil(link.Verify(doc, origin), "verify")
+	assert.Nil(link.Verify(doc, "https://EXAMPLE.com/"), "normalized origin")
+
+	// Survives encoding
+	js, _ := json.Marshal(link)
+	restored := new(DomainLink)
+	assert.Nil(json.Unmarshal(js, restored))
+	assert.Nil(restored.Verify(doc, origin), "verify restored")
+
+	// Invalid values
+	assert.NotNil(link.Verify(doc, "https://other.com"), "invalid origin")
+	other, _ := did.NewKeyIdentifier(did.KeyTypeEd)
+	assert.NotNil(link.Verify(other.Document(true), origin), "invalid document")
+	restored.CredentialSubject.Origin = "https://other.com"
+	assert.NotNil(restored.Verify(doc, "https://other.com"), "tampered credential")
+	_, err = NewDomainLink(id, key, "http://example.com", expires)
+	assert.NotNil(err, "insecure origin")
+	_, err = NewDomainLink(id, key, "https://example.com/path", expires)
+	assert.NotNil(err, "origin with path")
+	_, err = NewDomainLink(id, key, origin, time.Now().Add(-time.Hour))
+	assert.NotNil(err, "expired")
+	_, err = NewDomainLink(id, "unknown", origin, expires)
+	assert.NotNil(err, "invalid key")
+	enc, _ := did.NewKeyIdentifier(did.KeyTypeX25519)
+	_, err = NewDomainLink(enc, enc.VerificationMethods()[0].ID, origin, expires)
+	assert.NotNil(err, "key not enabled for assertions")
+}
+
+func TestVerifier(t *testing.T) {
+	assert := tdd.New(t)
+	conf := NewConfiguration()
+	srv := httptest.NewTLSServer(Handler(conf))
+	defer srv.Close()
+
+	verifier, err := NewVerifier(WithHTTPClient(srv.Client()))
+	assert.Nil(err, "new verifier")
+
+	// No linked DIDs
+	_, err = verifier.Verify(context.Background(), srv.URL)
+	assert.NotNil(err, "empty configuration")
+
+	// Valid configuration
+	var ids []string
+	for _, kt := range []did.KeyType{did.KeyTypeEd, did.KeyTypeP256} {
+		id, _ := did.NewKeyIdentifier(kt)
+		link, err := NewDomainLink(id, id.VerificationMethods()[0].ID, srv.URL, time.Now().Add(time.Hour))
+		assert.Nil(err, "new domain link")
+		conf.Add(link)
+		ids = append(ids, id.DID())
+	}
+	linked, err := verifier.Verify(context.Background(), srv.URL)
+	assert.Nil(err, "verify")
+	assert.Equal(ids, linked)
+
+	// Credential issued for a different origin
+	id, _ := did.NewKeyIdentifier(did.KeyTypeEd)
+	link, _ := NewDomainLink(id, id.VerificationMethods()[0].ID, "https://example.com", time.Now().Add(time.Hour))
+	conf.Add(link)
+	_, err = verifier.Verify(context.Background(), srv.URL)
+	assert.NotNil(err, "invalid credential")
+
+	// Handler
+	rec := httptest.NewRecorder()
+	Handler(conf)(rec, httptest.NewRequest(http.MethodPost, WellKnownPath, nil))
+	assert.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	// Resource not available
+	_, err = verifier.Verify(context.Background(), "https://127.0.0.1:1")
+	assert.NotNil(err, "unavailable origin")
+}
@@ -172,6 +172,7 @@ func As(err error, target interface{}) bool {
 //     it will be used and the result returned
 //   - Comparison is true between `target` and `src` cause
 //   - Comparison is true between `src` and `target` cause
+//   - If `src` is an error group, comparison is true for any of its members
 func Is(src, target error) bool {
 	// Are both the same object?
 	// reflect.ValueOf(src).Equal(reflect.ValueOf(target))
@@ -179,6 +180,11 @@ func Is(src, target error) bool {
 		return true
 	}
 
+	// Compare with all members on error groups
+	if me, ok := src.(*MultiError); ok { // nolint: errorlint
+		return me.Is(target)
+	}
+
 	// Compare with `src` cause
 	var csE *Error
 	if As(src, &csE) {
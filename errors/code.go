@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tag used to register error codes on error instances.
+const codeTag = "error.code"
+
+// Code provides a stable identifier for a specific error condition. Codes
+// are scoped by domain, usually the name of the service or package defining
+// them, and allow clients to branch on specific failures without relying on
+// error messages.
+type Code struct {
+	// Domain for the code, for example "storage". Required.
+	Domain string `json:"domain"`
+
+	// String identifier, unique within the domain. For example,
+	// "RECORD_NOT_FOUND". Required.
+	ID string `json:"id"`
+
+	// Optional numeric identifier, unique within the domain when set.
+	Number int `json:"number,omitempty"`
+
+	// Optional category applied to errors using the code.
+	Category Category `json:"category,omitempty"`
+
+	// Short description of the error condition.
+	Description string `json:"description,omitempty"`
+}
+
+// String returns the textual representation of the code in the form
+// "domain/ID".
+func (c Code) String() string {
+	return fmt.Sprintf("%s/%s", c.Domain, c.ID)
+}
+
+// Is returns true if both codes have the same domain and identifier.
+func (c Code) Is(other Code) bool {
+	return c.Domain == other.Domain && c.ID == other.ID
+}
+
+// Process-wide error codes registry.
+var registry = &codeRegistry{
+	ids:     make(map[string]Code),
+	numbers: make(map[string]Code),
+}
+
+type codeRegistry struct {
+	ids     map[string]Code // "domain/ID" => code
+	numbers map[string]Code // "domain/number" => code
+	mu      sync.RWMutex
+}
+
+// RegisterCode adds a new code to the process-wide registry. An error is
+// returned if the code is invalid or collides with a previously registered
+// code, i.e., same domain and identifier or number.
+func RegisterCode(c Code) error {
+	if c.Domain == "" || c.ID == "" {
+		return New("code domain and identifier are required")
+	}
+	if strings.Contains(c.Domain, "/") {
+		return Errorf("invalid code domain: %s", c.Domain)
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if prev, ok := registry.ids[c.String()]; ok {
+		return Errorf("code collision: %s is already registered", prev)
+	}
+	num := fmt.Sprintf("%s/%d", c.Domain, c.Number)
+	if prev, ok := registry.numbers[num]; ok && c.Number != 0 {
+		return Errorf("code collision: %s uses number %d", prev, c.Number)
+	}
+	registry.ids[c.String()] = c
+	if c.Number != 0 {
+		registry.numbers[num] = c
+	}
+	return nil
+}
+
+// MustRegisterCode adds a new code to the process-wide registry and returns
+// it; panics if the code can't be registered. Useful when defining codes as
+// package-level variables.
+//
+//	var ErrCodeNotFound = errors.MustRegisterCode(errors.Code{
+//		Domain:   "storage",
+//		ID:       "RECORD_NOT_FOUND",
+//		Category: errors.NotFound,
+//	})
+func MustRegisterCode(c Code) Code {
+	if err := RegisterCode(c); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// LookupCode returns a registered code using its "domain/ID" textual
+// representation.
+func LookupCode(code string) (Code, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	c, ok := registry.ids[code]
+	return c, ok
+}
+
+// RegisteredCodes returns all codes on the process-wide registry, sorted
+// by domain and identifier.
+func RegisteredCodes() []Code {
+	registry.mu.RLock()
+	list := make([]Code, 0, len(registry.ids))
+	for _, c := range registry.ids {
+		list = append(list, c)
+	}
+	registry.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].String() < list[j].String()
+	})
+	return list
+}
+
+// WithCode returns a wrapped version of `err` including the provided code.
+// If the code has a category, it is also applied to the error. The original
+// error message and stacktrace are preserved.
+func WithCode(err error, c Code) error {
+	markers := map[string]interface{}{codeTag: c.String()}
+	if c.Category != "" {
+		markers[categoryTag] = string(c.Category)
+	}
+	return mark(err, markers)
+}
+
+// CodeOf returns the code attached to `err`, if any. If the code is not
+// available on the local registry, for example when received from a remote
+// service, only its domain and identifier are set. When multiple codes are
+// present, the outermost one in the error chain is used.
+func CodeOf(err error) (Code, bool) {
+	v, ok := lookupMarker(err, codeTag)
+	if !ok {
+		return Code{}, false
+	}
+	str, _ := v.(string)
+	if c, ok := LookupCode(str); ok {
+		return c, true
+	}
+	domain, id, ok := strings.Cut(str, "/")
+	if !ok {
+		return Code{}, false
+	}
+	return Code{Domain: domain, ID: id}, true
+}
+
+// HasCode returns true if `err` has the provided code attached.
+func HasCode(err error, c Code) bool {
+	ec, ok := CodeOf(err)
+	return ok && ec.Is(c)
+}
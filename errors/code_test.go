@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCode(t *testing.T) {
+	assert := tdd.New(t)
+	errCode := Code{
+		Domain:      "test.storage",
+		ID:          "RECORD_NOT_FOUND",
+		Number:      1,
+		Category:    NotFound,
+		Description: "requested record doesn't exist",
+	}
+
+	// Registry
+	assert.Nil(RegisterCode(errCode), "register")
+	assert.NotNil(RegisterCode(errCode), "duplicated identifier")
+	assert.NotNil(RegisterCode(Code{Domain: "test.storage", ID: "OTHER", Number: 1}), "duplicated number")
+	assert.NotNil(RegisterCode(Code{Domain: "test.storage"}), "missing identifier")
+	assert.NotNil(RegisterCode(Code{Domain: "test/storage", ID: "OTHER"}), "invalid domain")
+	assert.Nil(RegisterCode(Code{Domain: "test.network", ID: "TIMEOUT", Number: 1}), "same number on other domain")
+	assert.Panics(func() { MustRegisterCode(errCode) }, "must register")
+	c, ok := LookupCode("test.storage/RECORD_NOT_FOUND")
+	assert.True(ok, "lookup")
+	assert.Equal(errCode, c)
+	assert.Contains(RegisteredCodes(), errCode)
+
+	// Attach codes to errors
+	err := Wrap(WithCode(New("no record with id: 123"), errCode), "request")
+	c, ok = CodeOf(err)
+	assert.True(ok, "code")
+	assert.Equal(errCode, c)
+	assert.True(HasCode(err, errCode), "has code")
+	assert.True(IsNotFound(err), "code category")
+	assert.Equal("request: no record with id: 123", err.Error())
+	_, ok = CodeOf(New("no code"))
+	assert.False(ok, "no code")
+
+	// Wire encoding
+	st := ToStatus(err, codes.Unknown)
+	assert.Equal(codes.NotFound, st.Code(), "status code")
+	assert.True(HasCode(FromStatus(st), errCode), "restored code")
+
+	// Problem details
+	p := ToProblem(err, "")
+	assert.Equal(http.StatusNotFound, p.Status)
+	assert.Equal("test.storage/RECORD_NOT_FOUND", p.Code)
+}
@@ -0,0 +1,327 @@
+package errors
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain used to identify error reports included in `google.rpc.Status`
+// details.
+const statusDomain = "go.bryk.io/pkg/errors"
+
+// Status codes used for classified errors.
+var categoryCodes = map[Category]codes.Code{
+	NotFound:    codes.NotFound,
+	Conflict:    codes.Aborted,
+	RateLimited: codes.ResourceExhausted,
+}
+
+// Redactor applied by default to errors encoded as gRPC status.
+var statusRedactor, _ = NewRedactor()
+
+// StatusOption allows to adjust the information included on the gRPC status
+// generated for an error.
+type StatusOption func(*statusSettings)
+
+type statusSettings struct {
+	stack    bool
+	redactor *Redactor
+}
+
+// WithStackTrace includes the stacktraces of the error as part of the
+// status details. Stacktraces disclose internal information about the
+// service (like file paths, function names and source code) and are
+// omitted by default.
+func WithStackTrace() StatusOption {
+	return func(s *statusSettings) {
+		s.stack = true
+	}
+}
+
+// WithStatusRedactor sets the redactor applied to errors before generating
+// a gRPC status. If not provided, a redactor with the default settings is
+// used; set to `nil` to disable redaction.
+func WithStatusRedactor(r *Redactor) StatusOption {
+	return func(s *statusSettings) {
+		s.redactor = r
+	}
+}
+
+// CodecGRPC encodes error data as `google.rpc.Status` messages. The error
+// structure (wrapped causes, hints, tags, fields and events) is preserved,
+// allowing the use of `Is` and `As` on the decoded errors. Refer to
+// 'ToStatus' for details on the information included.
+func CodecGRPC(opts ...StatusOption) Codec {
+	return &grpcCodec{opts: opts}
+}
+
+// ToStatus returns a gRPC status for the provided error. The status includes
+// the error structure as details and can be restored using 'FromStatus'.
+// If `err` wraps a gRPC status error its code is used, otherwise `code` is.
+// When `code` is 'codes.Unknown' and the error was classified using
+// 'MarkCategory', a corresponding code is selected.
+//
+// The status is meant to be sent to other services; the error is redacted
+// before encoding, including its message, and stacktraces are omitted unless
+// 'WithStackTrace' is used.
+func ToStatus(err error, code codes.Code, opts ...StatusOption) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	conf := &statusSettings{redactor: statusRedactor}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	var (
+		de *statusError
+		se interface{ GRPCStatus() *status.Status }
+	)
+	if As(err, &de) {
+		code = de.code
+	} else if As(err, &se) {
+		code = se.GRPCStatus().Code()
+	} else if cc, ok := categoryCodes[CategoryOf(err)]; ok && code == codes.Unknown {
+		code = cc
+	}
+	if conf.redactor != nil {
+		err = conf.redactor.Redact(err)
+	}
+	st := status.New(code, err.Error())
+
+	// Report details
+	rep := encodeChain(err)
+	if !conf.stack {
+		dropFrames(rep)
+	}
+	js, jErr := json.Marshal(rep)
+	if jErr != nil {
+		return st
+	}
+	info := &errdetails.ErrorInfo{
+		Reason:   "ERROR_REPORT",
+		Domain:   statusDomain,
+		Metadata: map[string]string{"report": string(js)},
+	}
+	if ec, ok := CodeOf(err); ok {
+		// Error code is also available for clients not using this package
+		info.Metadata["code"] = ec.String()
+	}
+	details := []protoadapt.MessageV1{info}
+
+	// Stacktrace as debug information, for clients not using this package
+	if len(rep) > 0 && len(rep[0].Frames) > 0 {
+		di := &errdetails.DebugInfo{Detail: err.Error()}
+		for _, f := range rep[0].Frames {
+			di.StackEntries = append(di.StackEntries, fmt.Sprintf("%+v", f))
+		}
+		details = append(details, di)
+	}
+	if withDetails, dErr := st.WithDetails(details...); dErr == nil {
+		return withDetails
+	}
+	return st
+}
+
+// FromStatus restores an error instance previously encoded with 'ToStatus'.
+// If the status doesn't include an error report, a regular gRPC status
+// error is returned.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != statusDomain {
+			continue
+		}
+		var rep []chainLink
+		if err := json.Unmarshal([]byte(info.GetMetadata()["report"]), &rep); err != nil || len(rep) == 0 {
+			break
+		}
+		return &statusError{err: decodeChain(rep), code: st.Code()}
+	}
+	return st.Err()
+}
+
+// Element on an encoded error chain; from the outermost error to the
+// root cause.
+type chainLink struct {
+	Msg    string                 `json:"msg,omitempty"`
+	Prefix string                 `json:"prefix,omitempty"`
+	Stamp  int64                  `json:"stamp,omitempty"`
+	Frames []StackFrame           `json:"frames,omitempty"`
+	Hints  []string               `json:"hints,omitempty"`
+	Tags   map[string]interface{} `json:"tags,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Events []Event                `json:"events,omitempty"`
+	Errors [][]chainLink          `json:"errors,omitempty"`
+}
+
+// Decoded errors retain the original gRPC status code.
+type statusError struct {
+	err  *Error
+	code codes.Code
+}
+
+// Error returns the decoded error's message.
+func (se *statusError) Error() string {
+	return se.err.Error()
+}
+
+// GRPCStatus returns the gRPC status for the error, including its report.
+func (se *statusError) GRPCStatus() *status.Status {
+	return ToStatus(se.err, se.code)
+}
+
+// Unwrap returns the decoded error instance.
+func (se *statusError) Unwrap() error {
+	return se.err
+}
+
+type grpcCodec struct {
+	opts []StatusOption
+}
+
+func (c *grpcCodec) Marshal(err error) ([]byte, error) {
+	return proto.Marshal(ToStatus(err, codes.Unknown, c.opts...).Proto())
+}
+
+func (c *grpcCodec) Unmarshal(src []byte) (bool, error) {
+	st := new(spb.Status)
+	if err := proto.Unmarshal(src, st); err != nil {
+		return false, nil
+	}
+	err := FromStatus(status.FromProto(st))
+	var se *statusError
+	if !As(err, &se) {
+		return false, nil
+	}
+	return true, se.err
+}
+
+// Produce the list of links for an error chain. Stacktraces are only
+// included when different from the previous (outer) link.
+func encodeChain(err error) []chainLink {
+	var (
+		chain []chainLink
+		prev  []StackFrame
+	)
+	for err != nil {
+		if se, ok := err.(*statusError); ok { // nolint: errorlint
+			err = se.err
+		}
+		if me, ok := err.(*MultiError); ok { // nolint: errorlint
+			// Error groups are always the root of the chain, each
+			// member is encoded independently
+			link := chainLink{Msg: me.Error(), Stamp: me.ts, Frames: portableTrace(me.frames)}
+			for _, e := range me.errs {
+				link.Errors = append(link.Errors, encodeChain(e))
+			}
+			chain = append(chain, link)
+			break
+		}
+		oe, ok := err.(*Error) // nolint: errorlint
+		if !ok {
+			// Regular error values are treated as root causes
+			chain = append(chain, chainLink{Msg: err.Error()})
+			break
+		}
+		link := chainLink{
+			Prefix: oe.prefix,
+			Stamp:  oe.ts,
+			Hints:  oe.hints,
+			Tags:   oe.tags,
+			Fields: oe.fields,
+			Events: oe.events,
+		}
+		if !reflect.DeepEqual(oe.frames, prev) {
+			link.Frames = oe.PortableTrace()
+			prev = oe.frames
+		}
+		if _, ok := oe.err.(*MultiError); ok && oe.prev == nil { // nolint: errorlint
+			chain = append(chain, link)
+			err = oe.err
+			continue
+		}
+		if oe.prev == nil {
+			link.Msg = oe.err.Error()
+			chain = append(chain, link)
+			break
+		}
+		chain = append(chain, link)
+		err = oe.prev
+	}
+	return chain
+}
+
+// Remove stacktraces from all the elements on an encoded error chain.
+func dropFrames(chain []chainLink) {
+	for i := range chain {
+		chain[i].Frames = nil
+		for _, member := range chain[i].Errors {
+			dropFrames(member)
+		}
+	}
+}
+
+// Restore an error chain, starting with the root cause.
+func decodeChain(chain []chainLink) *Error {
+	// Restore omitted stacktraces
+	for i := 1; i < len(chain); i++ {
+		if len(chain[i].Frames) == 0 {
+			chain[i].Frames = chain[i-1].Frames
+		}
+	}
+	var err *Error
+	for i := len(chain) - 1; i >= 0; i-- {
+		link := chain[i]
+		rec := &Error{
+			ts:     link.Stamp,
+			prefix: link.Prefix,
+			frames: link.Frames,
+			hints:  link.Hints,
+			tags:   link.Tags,
+			fields: link.Fields,
+			events: link.Events,
+		}
+		switch {
+		case err == nil && len(link.Errors) > 0:
+			me := &MultiError{ts: link.Stamp, frames: link.Frames}
+			for _, member := range link.Errors {
+				me.errs = append(me.errs, decodeChain(member))
+			}
+			rec.err, rec.prev = me, me
+		case err == nil:
+			rec.err = stdErrors.New(link.Msg)
+		default:
+			rec.err = &Error{err: err}
+			rec.prev = err
+		}
+		err = rec
+	}
+	return err
+}
+
+// Return a copy of `err` after applying `fn` to its encoded chain.
+func transform(err error, fn func(chain []chainLink)) error {
+	if err == nil {
+		return nil
+	}
+	chain := encodeChain(err)
+	fn(chain)
+	rec := decodeChain(chain)
+	var se *statusError
+	if As(err, &se) {
+		return &statusError{err: rec, code: se.code}
+	}
+	return rec
+}
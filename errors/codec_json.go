@@ -18,7 +18,9 @@ type errReport struct {
 	Frames []StackFrame           `json:"frames,omitempty"`
 	Hints  []string               `json:"hints,omitempty"`
 	Tags   map[string]interface{} `json:"tags,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
 	Events []Event                `json:"events,omitempty"`
+	Errors []*errReport           `json:"errors,omitempty"`
 }
 
 type jsonCodec struct {
@@ -26,8 +28,35 @@ type jsonCodec struct {
 }
 
 func (c *jsonCodec) Marshal(err error) ([]byte, error) {
+	rec := newReport(err)
+	if c.pretty {
+		return json.MarshalIndent(rec, "", "  ")
+	}
+	return json.Marshal(rec)
+}
+
+func (c *jsonCodec) Unmarshal(src []byte) (bool, error) {
+	// validate error report
+	rep := new(errReport)
+	if err := json.Unmarshal(src, rep); err != nil {
+		return false, nil
+	}
+	return true, restoreReport(rep)
+}
+
+// Generate the report for an error instance. The members of error
+// groups are included as a flat list.
+func newReport(err error) *errReport {
 	rec := new(errReport)
 	rec.Msg = err.Error()
+	if me, ok := err.(*MultiError); ok { // nolint: errorlint
+		rec.Stamp = me.Stamp()
+		rec.Frames = portableTrace(me.StackTrace())
+		for _, e := range me.errs {
+			rec.Errors = append(rec.Errors, newReport(e))
+		}
+		return rec
+	}
 	var oe *Error
 	if As(err, &oe) {
 		rec.Stamp = oe.Stamp()
@@ -35,18 +64,22 @@ func (c *jsonCodec) Marshal(err error) ([]byte, error) {
 		rec.Hints = oe.Hints()
 		rec.Tags = oe.Tags()
 		rec.Events = oe.Events()
+		if fields := Fields(err).Values(); len(fields) > 0 {
+			rec.Fields = fields
+		}
 	}
-	if c.pretty {
-		return json.MarshalIndent(rec, "", "  ")
-	}
-	return json.Marshal(rec)
+	return rec
 }
 
-func (c *jsonCodec) Unmarshal(src []byte) (bool, error) {
-	// validate error report
-	rep := new(errReport)
-	if err := json.Unmarshal(src, rep); err != nil {
-		return false, nil
+// Restore an error instance from its report.
+func restoreReport(rep *errReport) error {
+	// restore error groups
+	if len(rep.Errors) > 0 {
+		me := &MultiError{ts: rep.Stamp, frames: rep.Frames}
+		for _, r := range rep.Errors {
+			me.errs = append(me.errs, restoreReport(r))
+		}
+		return me
 	}
 
 	// restore recovered error details
@@ -56,6 +89,7 @@ func (c *jsonCodec) Unmarshal(src []byte) (bool, error) {
 	rec.hints = rep.Hints
 	rec.tags = rep.Tags
 	rec.events = rep.Events
+	rec.fields = rep.Fields
 
 	// parse error message
 	msg := strings.Split(rep.Msg, ":")
@@ -65,5 +99,5 @@ func (c *jsonCodec) Unmarshal(src []byte) (bool, error) {
 	} else {
 		rec.err = fmt.Errorf("%s", rep.Msg)
 	}
-	return true, rec
+	return rec
 }
@@ -18,6 +18,7 @@ type Error struct {
 	hints  []string               // additional contextual information
 	events []Event                // events associated to the error
 	tags   map[string]interface{} // additional metadata details
+	fields map[string]interface{} // structured attributes
 	mu     sync.Mutex
 }
 
@@ -77,12 +78,7 @@ func (e *Error) StackTrace() []StackFrame {
 // to remove any paths specific to the local system, making the
 // information a bit more readable and portable.
 func (e *Error) PortableTrace() []StackFrame {
-	fr := make([]StackFrame, len(e.frames))
-	copy(fr, e.frames)
-	for i := range fr {
-		fr[i].File = printFile(fr[i].File)
-	}
-	return fr
+	return portableTrace(e.frames)
 }
 
 // AddHint registers additional information on the error instance.
@@ -131,6 +127,14 @@ func (e *Error) Tags() map[string]interface{} {
 	return e.tags
 }
 
+// Fields returns the structured attributes set on the error instance
+// using 'WithFields'. To retrieve the fields for the entire error chain
+// use the package-level 'Fields' function. If no fields are set on the
+// error instance this method returns `nil`.
+func (e *Error) Fields() map[string]interface{} {
+	return e.fields
+}
+
 // Hints provide additional context to an error in the form of meaningful
 // text messages. If no hints are set on the error instance this method
 // returns `nil`.
@@ -177,6 +181,12 @@ func (e *Error) Format(s fmt.State, verb rune) {
 					str += fmt.Sprintf("\t- %s=%v\n", k, v)
 				}
 			}
+			if fields := Fields(e).Values(); len(fields) > 0 {
+				str += "‹fields›\n"
+				for k, v := range fields {
+					str += fmt.Sprintf("\t- %s=%v\n", k, v)
+				}
+			}
 			if len(e.events) > 0 {
 				str += "‹events›\n"
 				for _, ev := range e.events {
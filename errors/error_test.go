@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	tdd "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestErrorUsage(t *testing.T) {
@@ -63,6 +65,63 @@ func TestReport(t *testing.T) {
 	})
 }
 
+func TestCodecGRPC(t *testing.T) {
+	assert := tdd.New(t)
+	errNotFound := New("record not found")
+
+	// Error chain with additional details
+	e1 := Wrap(errNotFound, "storage")
+	var oe *Error
+	As(e1, &oe)
+	oe.AddHint("check the record identifier")
+	oe.SetTag("record", "rec-123")
+	e2 := Wrapf(e1, "request %d", 1)
+
+	// Status
+	st := ToStatus(e2, codes.NotFound)
+	assert.Equal(codes.NotFound, st.Code())
+	assert.Equal(e2.Error(), st.Message())
+	restored := FromStatus(st)
+	assert.Equal(e2.Error(), restored.Error(), "invalid message")
+	assert.True(Is(restored, errNotFound), "cause analysis")
+	assert.True(As(restored, &oe), "type casting")
+	assert.Equal("request 1", oe.prefix)
+	assert.Empty(oe.StackTrace(), "stacktrace is omitted by default")
+	assert.Len(st.Details(), 1, "no debug info")
+	assert.Equal(codes.NotFound, status.Code(restored), "status code")
+
+	// Stacktrace is included only when requested
+	st = ToStatus(e2, codes.NotFound, WithStackTrace())
+	assert.True(As(FromStatus(st), &oe), "type casting")
+	assert.Equal(e2.(*Error).PortableTrace(), oe.StackTrace(), "invalid stacktrace")
+	assert.Len(st.Details(), 2, "debug info")
+
+	// Details are preserved on wrapped causes
+	As(Unwrap(oe), &oe)
+	assert.Equal([]string{"check the record identifier"}, oe.Hints())
+	assert.Equal("rec-123", oe.Tags()["record"])
+
+	// Errors can be propagated multiple times
+	again := FromStatus(ToStatus(Wrap(restored, "gateway"), codes.Unknown))
+	assert.Equal(codes.NotFound, status.Code(again), "status code")
+	assert.True(Is(again, errNotFound), "cause analysis")
+
+	// Regular status errors
+	plain := status.New(codes.PermissionDenied, "denied")
+	assert.Equal(plain.Err(), FromStatus(plain))
+	assert.Nil(FromStatus(status.New(codes.OK, "")))
+
+	// Codec
+	codec := CodecGRPC()
+	data, err := Report(e2, codec)
+	assert.Nil(err, "failed to generate report")
+	ok, rec := codec.Unmarshal(data)
+	assert.True(ok, "unmarshal failed")
+	assert.True(Is(rec, errNotFound), "cause analysis")
+	ok, _ = codec.Unmarshal([]byte("invalid"))
+	assert.False(ok, "invalid report")
+}
+
 type customErrorA struct{ msg string }
 type customErrorB struct{ msg string }
 
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"time"
+
+	"go.bryk.io/pkg/metadata"
+)
+
+// WithFields returns a wrapped version of `err` including the provided
+// structured attributes. Fields are preserved when wrapping the error and
+// included in reports; use them instead of adding values to the error
+// message. The original error message and stacktrace are preserved.
+func WithFields(err error, fields metadata.MD) error {
+	if err == nil {
+		return nil
+	}
+	frames := getStack(1)
+	var se HasStack
+	if As(err, &se) {
+		frames = se.StackTrace()
+	}
+	return &Error{
+		ts:     time.Now().UnixMilli(),
+		err:    &Error{err: err},
+		prev:   err,
+		frames: frames,
+		fields: fields.Copy().Values(),
+	}
+}
+
+// Fields returns all the structured attributes attached to the error chain
+// using 'WithFields'. When the same field is present multiple times, the
+// outermost value in the error chain is used.
+func Fields(err error) metadata.MD {
+	var chain []*Error
+	for err != nil {
+		if oe, ok := err.(*Error); ok { // nolint: errorlint
+			chain = append(chain, oe)
+		}
+		err = stdErrors.Unwrap(err)
+	}
+	md := metadata.New()
+	for i := len(chain) - 1; i >= 0; i-- {
+		md.Load(chain[i].Fields())
+	}
+	return md
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/metadata"
+	"google.golang.org/grpc/codes"
+)
+
+func TestFields(t *testing.T) {
+	assert := tdd.New(t)
+	assert.Nil(WithFields(nil, metadata.New()))
+	assert.True(Fields(New("no fields")).IsEmpty())
+
+	// Fields are preserved when wrapping errors
+	root := New("record not found")
+	e1 := WithFields(root, metadata.FromMap(map[string]interface{}{"record": "rec-123", "table": "users"}))
+	e2 := Wrap(WithFields(Wrap(e1, "storage"), metadata.FromMap(map[string]interface{}{"table": "accounts"})), "request")
+	assert.Equal("request: storage: record not found", e2.Error())
+	assert.True(Is(e2, root), "cause analysis")
+	fields := Fields(e2)
+	assert.Equal("rec-123", fields.Get("record"))
+	assert.Equal("accounts", fields.Get("table"), "outermost value")
+	assert.Contains(fmt.Sprintf("%+v", e2), "‹fields›")
+
+	// Reports
+	js, err := Report(e2, CodecJSON(false))
+	assert.Nil(err, "failed to generate report")
+	ok, rec := CodecJSON(false).Unmarshal(js)
+	assert.True(ok, "unmarshal failed")
+	assert.Equal("rec-123", Fields(rec).Get("record"))
+	restored := FromStatus(ToStatus(e2, codes.Unknown))
+	assert.Equal(fields.Values(), Fields(restored).Values())
+}
@@ -0,0 +1,250 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Maximum number of frames, per stacktrace, used to calculate fingerprints.
+const fingerprintDepth = 16
+
+// Fingerprint returns a stable identifier for the failure condition that
+// produced `err`. The value is calculated using the error type, code and the
+// functions on the stacktraces of the error chain. Frames on the standard
+// library and the Go runtime are ignored. Line numbers and messages are not
+// used, so the fingerprint is not affected by unrelated code changes or
+// variable values; i.e., different occurrences of the same issue share the
+// same fingerprint.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := sha256.New()
+	fingerprint(h, err)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Add the elements of the error chain to the fingerprint hash.
+func fingerprint(h hash.Hash, err error) {
+	if code, ok := CodeOf(err); ok {
+		_, _ = fmt.Fprintf(h, "%s\n", code)
+	} else {
+		_, _ = fmt.Fprintf(h, "%T\n", Cause(err))
+	}
+	var prev []StackFrame
+	for e := err; e != nil; e = stdErrors.Unwrap(e) {
+		if me, ok := e.(*MultiError); ok { // nolint: errorlint
+			for _, member := range me.errs {
+				fingerprint(h, member)
+			}
+			return
+		}
+		hs, ok := e.(HasStack)
+		if !ok {
+			continue
+		}
+		frames := trimStack(hs.StackTrace())
+		if sameFunctions(frames, prev) {
+			continue // wrapped errors sharing the same stacktrace
+		}
+		prev = frames
+		for _, f := range frames {
+			_, _ = fmt.Fprintf(h, "%s.%s\n", f.Package, f.Function)
+		}
+	}
+}
+
+// Remove frames on the standard library and the Go runtime, and limit the
+// stacktrace to the most recent calls.
+func trimStack(frames []StackFrame) []StackFrame {
+	var res []StackFrame
+	for _, f := range frames {
+		if isStdPackage(f.Package) {
+			continue
+		}
+		res = append(res, f)
+		if len(res) == fingerprintDepth {
+			break
+		}
+	}
+	return res
+}
+
+// Packages on the standard library don't include a domain element on
+// their import path.
+func isStdPackage(pkg string) bool {
+	if pkg == "" || pkg == "main" {
+		return false
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return !strings.Contains(first, ".")
+}
+
+func sameFunctions(a, b []StackFrame) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Package != b[i].Package || a[i].Function != b[i].Function {
+			return false
+		}
+	}
+	return true
+}
+
+// Occurrence provides aggregated details about the instances of an issue,
+// i.e., errors sharing the same fingerprint.
+type Occurrence struct {
+	// Fingerprint of the issue.
+	Fingerprint string
+
+	// Total number of occurrences of the issue.
+	Count uint64
+
+	// Number of occurrences not reported since the last reported one.
+	Suppressed uint64
+
+	// Time of the first occurrence.
+	First time.Time
+
+	// Time of the most recent occurrence.
+	Last time.Time
+
+	// Whether this occurrence should be reported, based on the tracker
+	// settings.
+	Report bool
+}
+
+// Tracker aggregates error occurrences using their fingerprint; allowing
+// reporting sinks to receive a single report per issue, along with its
+// occurrence count, instead of one report per occurrence. The first
+// occurrence of an issue is always reported, additional occurrences are
+// only reported once per window or, if sampling is enabled, once every
+// N occurrences. Issues not seen during the retention period are discarded.
+type Tracker struct {
+	window time.Duration
+	retain time.Duration
+	sample uint64
+	issues map[string]*issue
+	sweep  time.Time
+	mu     sync.Mutex
+}
+
+// TrackerOption allows to adjust the behavior of a tracker instance.
+type TrackerOption func(t *Tracker)
+
+type issue struct {
+	count      uint64
+	suppressed uint64
+	first      time.Time
+	last       time.Time
+	reported   time.Time
+}
+
+// NewTracker returns a new tracker instance. By default, occurrences are
+// aggregated on a 1-minute window, issues are retained for 1 hour and
+// sampling is disabled.
+func NewTracker(options ...TrackerOption) *Tracker {
+	t := &Tracker{
+		window: time.Minute,
+		retain: time.Hour,
+		issues: make(map[string]*issue),
+		sweep:  time.Now(),
+	}
+	for _, opt := range options {
+		opt(t)
+	}
+	return t
+}
+
+// WithWindow sets the time window used to aggregate occurrences of an
+// issue.
+func WithWindow(window time.Duration) TrackerOption {
+	return func(t *Tracker) {
+		if window > 0 {
+			t.window = window
+		}
+	}
+}
+
+// WithRetention sets the period after which issues with no new occurrences
+// are discarded.
+func WithRetention(period time.Duration) TrackerOption {
+	return func(t *Tracker) {
+		if period > 0 {
+			t.retain = period
+		}
+	}
+}
+
+// WithSampling enables reporting one every `n` occurrences of an issue,
+// in addition to one report per window.
+func WithSampling(n uint64) TrackerOption {
+	return func(t *Tracker) {
+		t.sample = n
+	}
+}
+
+// Observe registers an occurrence of `err` and returns the aggregated
+// details of its issue.
+func (t *Tracker) Observe(err error) Occurrence {
+	fp := Fingerprint(err)
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(now)
+	is, ok := t.issues[fp]
+	if !ok {
+		is = &issue{first: now}
+		t.issues[fp] = is
+	}
+	is.count++
+	is.last = now
+	occ := Occurrence{
+		Fingerprint: fp,
+		Count:       is.count,
+		First:       is.first,
+		Last:        is.last,
+	}
+	occ.Report = is.count == 1 ||
+		now.Sub(is.reported) >= t.window ||
+		(t.sample > 0 && is.count%t.sample == 0)
+	if occ.Report {
+		occ.Suppressed = is.suppressed
+		is.suppressed = 0
+		is.reported = now
+	} else {
+		is.suppressed++
+	}
+	return occ
+}
+
+// Count returns the total number of occurrences registered for an issue.
+func (t *Tracker) Count(fp string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if is, ok := t.issues[fp]; ok {
+		return is.count
+	}
+	return 0
+}
+
+// Discard issues not seen during the retention period; executed at most
+// once per window.
+func (t *Tracker) prune(now time.Time) {
+	if now.Sub(t.sweep) < t.window {
+		return
+	}
+	t.sweep = now
+	for fp, is := range t.issues {
+		if now.Sub(is.last) >= t.retain {
+			delete(t.issues, fp)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/metadata"
+)
+
+func TestFingerprint(t *testing.T) {
+	assert := tdd.New(t)
+	code := Code{Domain: "test.fingerprint", ID: "FAILED"}
+
+	// Stable across occurrences; messages and wrappers are not relevant
+	assert.Equal(Fingerprint(sampleIssue("a")), Fingerprint(sampleIssue("b")))
+	assert.Equal(Fingerprint(sampleIssue("a")), Fingerprint(WithFields(sampleIssue("b"), metadata.New())))
+	assert.NotEqual(Fingerprint(sampleIssue("a")), Fingerprint(New("a")), "different location")
+	assert.NotEqual(Fingerprint(sampleIssue("a")), Fingerprint(WithCode(sampleIssue("a"), code)), "error code")
+	assert.NotEqual(Fingerprint(sampleIssue("a")), Fingerprint(Join(sampleIssue("a"), sampleIssue("b"))), "multi error")
+	assert.Empty(Fingerprint(nil))
+
+	// Standard library frames are ignored
+	trimmed := trimStack(sampleIssue("a").(HasStack).StackTrace())
+	for _, f := range trimmed {
+		assert.NotEqual("testing", f.Package)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Window", func(t *testing.T) {
+		tr := NewTracker(WithWindow(50*time.Millisecond), WithRetention(100*time.Millisecond))
+		occ := tr.Observe(sampleIssue("a"))
+		assert.True(occ.Report, "first occurrence")
+		assert.Equal(uint64(1), occ.Count)
+		for i := 0; i < 4; i++ {
+			assert.False(tr.Observe(sampleIssue("b")).Report, "aggregated occurrence")
+		}
+		assert.True(tr.Observe(New("other")).Report, "different issue")
+		assert.Equal(uint64(5), tr.Count(occ.Fingerprint))
+
+		<-time.After(60 * time.Millisecond)
+		occ = tr.Observe(sampleIssue("c"))
+		assert.True(occ.Report, "new window")
+		assert.Equal(uint64(6), occ.Count)
+		assert.Equal(uint64(4), occ.Suppressed)
+
+		// Issues not seen during the retention period are discarded
+		<-time.After(110 * time.Millisecond)
+		occ = tr.Observe(New("other"))
+		assert.Equal(uint64(1), occ.Count)
+		assert.Zero(tr.Count(Fingerprint(sampleIssue("a"))))
+	})
+
+	t.Run("Sampling", func(t *testing.T) {
+		tr := NewTracker(WithWindow(time.Hour), WithSampling(3))
+		reported := 0
+		for i := 0; i < 9; i++ {
+			if tr.Observe(sampleIssue("a")).Report {
+				reported++
+			}
+		}
+		assert.Equal(4, reported, "first occurrence plus samples")
+	})
+}
+
+func sampleIssue(msg string) error {
+	return New(msg)
+}
@@ -0,0 +1,147 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProblemContentType is the media type used for problem details documents.
+const ProblemContentType = "application/problem+json"
+
+// Problem provides machine-readable details of errors in HTTP responses,
+// as described in RFC-7807.
+type Problem struct {
+	// URI reference that identifies the problem type. Defaults to
+	// "about:blank", in which case the title is the HTTP status text.
+	Type string `json:"type"`
+
+	// Short, human-readable summary of the problem type.
+	Title string `json:"title"`
+
+	// HTTP status code generated by the origin server.
+	Status int `json:"status"`
+
+	// Human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+
+	// URI reference that identifies the specific occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+
+	// Error code, if available; in the form "domain/ID". This is an
+	// extension member.
+	Code string `json:"code,omitempty"`
+}
+
+// Non-standard status code used when a client closes the request before
+// the server responds.
+const statusClientClosed = 499
+
+// Status codes used for classified errors.
+var categoryHTTPCodes = map[Category]int{
+	NotFound:    http.StatusNotFound,
+	Conflict:    http.StatusConflict,
+	RateLimited: http.StatusTooManyRequests,
+}
+
+// HTTP status codes used for gRPC codes. Based on the mapping used by the
+// gRPC gateway.
+// https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+var grpcHTTPCodes = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           statusClientClosed,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP status code corresponding to `err`. The value
+// is selected, in order, using:
+//   - A custom `HTTPStatus() int` method on any error in the chain.
+//   - The category assigned with 'MarkCategory'.
+//   - The code of a wrapped gRPC status error.
+//   - Context cancellation and deadline errors.
+//
+// If no mapping is available, "500 Internal Server Error" is returned.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var hs interface{ HTTPStatus() int }
+	if As(err, &hs) {
+		return hs.HTTPStatus()
+	}
+	if code, ok := categoryHTTPCodes[CategoryOf(err)]; ok {
+		return code
+	}
+	var se interface{ GRPCStatus() *status.Status }
+	if As(err, &se) {
+		if code, ok := grpcHTTPCodes[se.GRPCStatus().Code()]; ok {
+			return code
+		}
+	}
+	switch {
+	case Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case Is(err, context.Canceled):
+		return statusClientClosed
+	}
+	return http.StatusInternalServerError
+}
+
+// ToProblem returns the problem details document for `err`. To prevent
+// leaking sensitive information only the error message is used as detail;
+// stacktraces, hints, tags and events are never included. Messages created
+// with 'SensitiveMessage' are redacted, and details for server errors (5xx)
+// are omitted entirely.
+func ToProblem(err error, instance string) *Problem {
+	code := HTTPStatus(err)
+	p := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Instance: instance,
+	}
+	if code == statusClientClosed {
+		p.Title = "Client Closed Request"
+	}
+	if ec, ok := CodeOf(err); ok {
+		p.Code = ec.String()
+	}
+	if err != nil && code < http.StatusInternalServerError {
+		p.Detail = err.Error()
+		if se, ok := err.(interface{ GRPCStatus() *status.Status }); ok { // nolint: errorlint
+			// Omit the code information included in status errors
+			p.Detail = se.GRPCStatus().Message()
+		}
+	}
+	return p
+}
+
+// WriteProblem renders `err` as a problem details document, as described in
+// RFC-7807, using the request path as instance value.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	p := ToProblem(err, "")
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	assert := tdd.New(t)
+	assert.Equal(http.StatusOK, HTTPStatus(nil))
+	assert.Equal(http.StatusInternalServerError, HTTPStatus(New("unexpected")))
+	assert.Equal(http.StatusNotFound, HTTPStatus(Wrap(MarkCategory(New("missing"), NotFound), "request")))
+	assert.Equal(http.StatusConflict, HTTPStatus(MarkCategory(New("exists"), Conflict)))
+	assert.Equal(http.StatusTooManyRequests, HTTPStatus(MarkCategory(New("slow down"), RateLimited)))
+	assert.Equal(http.StatusForbidden, HTTPStatus(status.Error(codes.PermissionDenied, "denied")))
+	assert.Equal(http.StatusGatewayTimeout, HTTPStatus(Wrap(context.DeadlineExceeded, "request")))
+	assert.Equal(http.StatusTeapot, HTTPStatus(Wrap(&customHTTPError{}, "request")))
+
+	t.Run("Problem", func(t *testing.T) {
+		// Client errors include a (redacted) detail
+		err := MarkCategory(New(SensitiveMessage("user %s not found", "rick")), NotFound)
+		rec := httptest.NewRecorder()
+		WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/users/rick", nil), err)
+		assert.Equal(http.StatusNotFound, rec.Code)
+		assert.Equal(ProblemContentType, rec.Header().Get("Content-Type"))
+		p := new(Problem)
+		assert.Nil(json.NewDecoder(rec.Body).Decode(p), "decode response")
+		assert.Equal("about:blank", p.Type)
+		assert.Equal("Not Found", p.Title)
+		assert.Equal("/users/rick", p.Instance)
+		assert.Equal("user ‹×› not found", p.Detail)
+
+		// Status errors
+		p = ToProblem(status.Error(codes.InvalidArgument, "invalid name"), "")
+		assert.Equal(http.StatusBadRequest, p.Status)
+		assert.Equal("invalid name", p.Detail)
+
+		// Server errors don't include details
+		p = ToProblem(New("database password is invalid"), "")
+		assert.Equal(http.StatusInternalServerError, p.Status)
+		assert.Empty(p.Detail)
+	})
+}
+
+type customHTTPError struct{}
+
+func (ce *customHTTPError) Error() string {
+	return "custom error"
+}
+
+func (ce *customHTTPError) HTTPStatus() int {
+	return http.StatusTeapot
+}
@@ -0,0 +1,131 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"time"
+)
+
+// Tags used to register markers on error instances. Markers are regular
+// tags and are preserved by the available codecs.
+const (
+	retryableTag = "error.retryable"
+	categoryTag  = "error.category"
+)
+
+// Category values can be used to classify errors and make decisions
+// without relying on specific error values or messages.
+type Category string
+
+const (
+	// NotFound indicates a requested entity doesn't exist.
+	NotFound Category = "not_found"
+
+	// Conflict indicates an operation failed due to the current state of
+	// the target entity; for example, when it already exists or was
+	// concurrently modified.
+	Conflict Category = "conflict"
+
+	// RateLimited indicates an operation was rejected due to usage limits.
+	// Errors on this category are considered retryable by default.
+	RateLimited Category = "rate_limited"
+)
+
+// MarkRetryable returns a wrapped version of `err` indicating the failed
+// operation can be safely retried. The original error message and stacktrace
+// are preserved.
+func MarkRetryable(err error) error {
+	return mark(err, map[string]interface{}{retryableTag: true})
+}
+
+// MarkPermanent returns a wrapped version of `err` indicating the failed
+// operation should not be retried. The original error message and stacktrace
+// are preserved.
+func MarkPermanent(err error) error {
+	return mark(err, map[string]interface{}{retryableTag: false})
+}
+
+// MarkCategory returns a wrapped version of `err` classified using the
+// provided category. The original error message and stacktrace are preserved.
+func MarkCategory(err error, c Category) error {
+	return mark(err, map[string]interface{}{categoryTag: string(c)})
+}
+
+// IsRetryable returns true if `err` was marked as retryable. Errors on the
+// 'RateLimited' category are considered retryable unless explicitly marked
+// as permanent. When multiple markers are present, the outermost one
+// in the error chain is used.
+func IsRetryable(err error) bool {
+	if v, ok := lookupMarker(err, retryableTag); ok {
+		retry, _ := v.(bool)
+		return retry
+	}
+	return CategoryOf(err) == RateLimited
+}
+
+// IsPermanent returns true if `err` was explicitly marked as permanent.
+func IsPermanent(err error) bool {
+	v, ok := lookupMarker(err, retryableTag)
+	if !ok {
+		return false
+	}
+	retry, _ := v.(bool)
+	return !retry
+}
+
+// CategoryOf returns the category assigned to `err`, if any. When multiple
+// categories are present, the outermost one in the error chain is used.
+func CategoryOf(err error) Category {
+	v, _ := lookupMarker(err, categoryTag)
+	c, _ := v.(string)
+	return Category(c)
+}
+
+// IsNotFound returns true if `err` is on the 'NotFound' category.
+func IsNotFound(err error) bool {
+	return CategoryOf(err) == NotFound
+}
+
+// IsConflict returns true if `err` is on the 'Conflict' category.
+func IsConflict(err error) bool {
+	return CategoryOf(err) == Conflict
+}
+
+// IsRateLimited returns true if `err` is on the 'RateLimited' category.
+func IsRateLimited(err error) bool {
+	return CategoryOf(err) == RateLimited
+}
+
+// Wrap `err` into a new error instance including the provided markers.
+func mark(err error, markers map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	frames := getStack(2)
+	var se HasStack
+	if As(err, &se) {
+		frames = se.StackTrace()
+	}
+	return &Error{
+		ts:     time.Now().UnixMilli(),
+		err:    &Error{err: err},
+		prev:   err,
+		frames: frames,
+		tags:   markers,
+	}
+}
+
+// Retrieve the outermost value registered for a marker on the error chain.
+func lookupMarker(err error, key string) (interface{}, bool) {
+	for err != nil {
+		if oe, ok := err.(*Error); ok { // nolint: errorlint
+			oe.mu.Lock()
+			v, found := oe.tags[key]
+			oe.mu.Unlock()
+			if found {
+				return v, true
+			}
+		}
+		err = stdErrors.Unwrap(err)
+	}
+	return nil, false
+}
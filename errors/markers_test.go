@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMarkers(t *testing.T) {
+	assert := tdd.New(t)
+	errTimeout := New("timeout")
+
+	// Unmarked errors
+	assert.False(IsRetryable(errTimeout))
+	assert.False(IsPermanent(errTimeout))
+	assert.Empty(CategoryOf(errTimeout))
+	assert.Nil(MarkRetryable(nil))
+
+	// Markers survive wrapping and preserve the original error
+	err := Wrap(MarkRetryable(errTimeout), "request")
+	assert.True(IsRetryable(err), "retryable")
+	assert.Equal("request: timeout", err.Error())
+	assert.True(Is(err, errTimeout), "cause analysis")
+	assert.Equal(errTimeout.(*Error).StackTrace(), err.(*Error).StackTrace(), "stacktrace")
+
+	// Outermost marker is used
+	err = MarkPermanent(err)
+	assert.False(IsRetryable(err), "permanent")
+	assert.True(IsPermanent(err), "permanent")
+
+	// Categories
+	err = Wrap(MarkCategory(errTimeout, RateLimited), "request")
+	assert.True(IsRateLimited(err), "category")
+	assert.True(IsRetryable(err), "rate limited errors are retryable")
+	assert.False(IsRetryable(MarkPermanent(err)), "explicit marker")
+	assert.True(IsNotFound(MarkCategory(New("missing"), NotFound)))
+	assert.True(IsConflict(MarkCategory(New("exists"), Conflict)))
+
+	// Markers survive wire transport
+	st := ToStatus(err, codes.Unknown)
+	assert.Equal(codes.ResourceExhausted, st.Code(), "status code")
+	restored := FromStatus(st)
+	assert.True(IsRateLimited(restored), "restored category")
+	assert.True(IsRetryable(restored), "restored marker")
+	js, rErr := Report(MarkRetryable(errTimeout), CodecJSON(false))
+	assert.Nil(rErr, "failed to generate report")
+	ok, rec := CodecJSON(false).Unmarshal(js)
+	assert.True(ok, "unmarshal failed")
+	assert.True(IsRetryable(rec), "restored marker")
+}
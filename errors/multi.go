@@ -0,0 +1,186 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MultiError aggregates multiple error instances into a single value. Cause
+// analysis (Is, IsAny, As) is performed across all members of the group and
+// reports include the details of every member.
+type MultiError struct {
+	ts     int64        // UNIX timestamp (in milliseconds)
+	errs   []error      // group members
+	frames []StackFrame // error stacktrace
+}
+
+// Join returns an error that aggregates all the non-nil errors provided.
+// Nested groups are flattened; the returned value will be `nil` if no
+// errors are provided. The stacktrace will point to the line of code that
+// called this function.
+func Join(errs ...error) error {
+	return join(2, errs...)
+}
+
+// Append adds the `errs` values provided to `err`. If `err` is a group
+// created with 'Join' or 'Append', the new members are added to it; otherwise
+// a new group is returned including `err` and `errs`. Nil values are ignored.
+func Append(err error, errs ...error) error {
+	me, ok := err.(*MultiError) // nolint: errorlint
+	if !ok {
+		return join(2, append([]error{err}, errs...)...)
+	}
+	return &MultiError{
+		ts:     me.ts,
+		errs:   flatten(append(append([]error{}, me.errs...), errs...)),
+		frames: me.frames,
+	}
+}
+
+// Errors returns the members of an error group. If `err` is not a group,
+// a list containing only `err` is returned.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var me *MultiError
+	if As(err, &me) {
+		return append([]error{}, me.errs...)
+	}
+	return []error{err}
+}
+
+// Error returns the messages of all the members in the group.
+func (e *MultiError) Error() string {
+	if len(e.errs) == 1 {
+		return e.errs[0].Error()
+	}
+	msg := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msg[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e.errs), strings.Join(msg, "; "))
+}
+
+// Unwrap returns the members of the group. Used by the standard library
+// to traverse error trees.
+func (e *MultiError) Unwrap() []error {
+	return e.errs
+}
+
+// Is returns true if `target` matches any of the members in the group.
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.errs {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// StackTrace returns the frames in the callers stack when the group
+// was created.
+func (e *MultiError) StackTrace() []StackFrame {
+	return e.frames
+}
+
+// Stamp returns error creation UNIX timestamp (in milliseconds).
+func (e *MultiError) Stamp() int64 {
+	return e.ts
+}
+
+// Format error values using the escape codes defined by fmt.Formatter.
+// The following verbs are supported:
+//
+//	%s   error message. Simply prints the basic error message as a
+//	     string representation.
+//	%v   basic format. Print the message of each member.
+//	%+v  extended format. Print each member using its own extended
+//	     format, if available.
+func (e *MultiError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'v':
+		str := fmt.Sprintf("%d errors occurred:\n", len(e.errs))
+		for i, err := range e.errs {
+			if s.Flag('+') {
+				str += fmt.Sprintf("‹%d› %+v\n", i, err)
+				continue
+			}
+			str += fmt.Sprintf("‹%d› %s\n", i, err)
+		}
+		_, _ = io.WriteString(s, str)
+	}
+}
+
+// Group collects the errors produced by multiple tasks, for example
+// parallel workers. Unlike the first-error semantics commonly used, all
+// failures are preserved. A zero Group is valid and ready to use.
+type Group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go runs the provided function in a new goroutine, collecting the
+// returned error (if any).
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.Add(fn())
+	}()
+}
+
+// Add registers an error on the group. Nil values are ignored.
+func (g *Group) Add(err error) {
+	if err == nil {
+		return
+	}
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+}
+
+// Wait blocks until all functions started with 'Go' have returned, and
+// returns an error aggregating all the failures collected. If no errors
+// were collected the returned value is `nil`.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return join(2, g.errs...)
+}
+
+// Create a new group; `skip` is used to adjust the stacktrace.
+func join(skip int, errs ...error) error {
+	members := flatten(errs)
+	if len(members) == 0 {
+		return nil
+	}
+	return &MultiError{
+		ts:     time.Now().UnixMilli(),
+		errs:   members,
+		frames: getStack(skip),
+	}
+}
+
+// Remove nil values and expand nested groups.
+func flatten(errs []error) []error {
+	var list []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if me, ok := err.(*MultiError); ok { // nolint: errorlint
+			list = append(list, me.errs...)
+			continue
+		}
+		list = append(list, err)
+	}
+	return list
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestMultiError(t *testing.T) {
+	assert := tdd.New(t)
+	errNotFound := New("record not found")
+	errTimeout := New("timeout")
+
+	// Empty groups
+	assert.Nil(Join())
+	assert.Nil(Join(nil, nil))
+	assert.Nil(Errors(nil))
+
+	// Nested groups are flattened
+	err := Join(errNotFound, nil, Join(Wrap(errTimeout, "worker-1"), io.EOF))
+	assert.Len(Errors(err), 3, "members")
+	assert.Equal("3 errors occurred: record not found; worker-1: timeout; EOF", err.Error())
+	err = Append(err, New(&customErrorA{msg: "a-1"}), &customErrorB{msg: "b-1"})
+	assert.Len(Errors(err), 5, "members")
+	assert.Len(Errors(Append(nil, io.EOF)), 1, "append to nil")
+	assert.Equal(io.EOF.Error(), Join(io.EOF).Error(), "single member")
+
+	// Cause analysis across all members
+	wrapped := Wrap(err, "processing")
+	assert.True(Is(wrapped, errNotFound), "is: first member")
+	assert.True(Is(wrapped, errTimeout), "is: wrapped member")
+	assert.True(Is(wrapped, io.EOF), "is: standard error")
+	assert.True(Is(wrapped, &customErrorA{msg: "a-1"}), "is: custom evaluation")
+	assert.False(Is(wrapped, &customErrorA{msg: "a-2"}), "is: no match")
+	var ce *customErrorB
+	assert.True(As(wrapped, &ce), "as: member")
+	assert.Equal("b-1", ce.msg)
+	var me *MultiError
+	assert.True(As(wrapped, &me), "as: group")
+	assert.NotEmpty(me.StackTrace(), "stacktrace")
+	assert.Equal(me.StackTrace(), wrapped.(*Error).StackTrace(), "preserve stacktrace")
+	assert.Contains(fmt.Sprintf("%+v", err), "‹3›", "extended format")
+
+	// Group
+	group := new(Group)
+	for i := 0; i < 5; i++ {
+		i := i
+		group.Go(func() error {
+			if i%2 == 0 {
+				return Errorf("task %d failed", i)
+			}
+			return nil
+		})
+	}
+	err = group.Wait()
+	assert.Len(Errors(err), 3, "group members")
+	assert.Nil(new(Group).Wait(), "empty group")
+
+	t.Run("Report", func(t *testing.T) {
+		err := Wrap(Join(errNotFound, Wrap(errTimeout, "worker-1")), "processing")
+
+		// JSON
+		js, rErr := Report(Join(errNotFound, Wrap(errTimeout, "worker-1")), CodecJSON(false))
+		assert.Nil(rErr, "failed to generate report")
+		ok, rec := CodecJSON(false).Unmarshal(js)
+		assert.True(ok, "unmarshal failed")
+		assert.Len(Errors(rec), 2, "restored members")
+
+		// gRPC
+		codec := CodecGRPC()
+		data, rErr := Report(err, codec)
+		assert.Nil(rErr, "failed to generate report")
+		ok, rec = codec.Unmarshal(data)
+		assert.True(ok, "unmarshal failed")
+		assert.Equal(err.Error(), rec.Error(), "invalid message")
+		assert.True(Is(rec, errNotFound), "cause analysis")
+		assert.True(Is(rec, errTimeout), "cause analysis")
+		assert.Len(Errors(rec), 2, "restored members")
+	})
+}
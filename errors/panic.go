@@ -1,10 +1,19 @@
 package errors
 
 import (
+	"bytes"
 	"fmt"
+	"runtime"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
+)
+
+// Tags used to register details of recovered panics on error instances.
+const (
+	panicTag     = "error.panic"
+	goroutineTag = "error.goroutine"
 )
 
 type uncaughtPanic struct {
@@ -15,6 +24,86 @@ func (p uncaughtPanic) Error() string {
 	return p.message
 }
 
+// PanicError provides details of a recovered panic event.
+type PanicError struct {
+	// Value provided to `panic`.
+	Value interface{}
+
+	// Identifier of the panicking goroutine.
+	Goroutine uint64
+}
+
+// Error returns the textual representation of the panic value.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("%v", p.Value)
+}
+
+// Unwrap returns the panic value if it is an error instance.
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recover converts a panic event into an error instance and assigns it to
+// `err`. The produced error includes the stacktrace of the panicking
+// goroutine, starting at the point where `panic` was called, and the
+// goroutine identifier. Must be called directly using `defer`.
+//
+//	func process() (err error) {
+//		defer errors.Recover(&err)
+//		// ...
+//	}
+func Recover(err *error) {
+	if v := recover(); v != nil && err != nil {
+		*err = FromPanic(v)
+	}
+}
+
+// CapturePanic executes `fn` and returns its result. If `fn` panics, the
+// panic event is returned as an error instance instead.
+func CapturePanic(fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = FromPanic(v)
+		}
+	}()
+	return fn()
+}
+
+// FromPanic returns an error instance for the value of a panic event. Must
+// be called from a deferred function while the goroutine is panicking, for
+// example by custom recovery handlers.
+//
+//	defer func() {
+//		if v := recover(); v != nil {
+//			err = errors.FromPanic(v)
+//		}
+//	}()
+func FromPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	pe := &PanicError{Value: v, Goroutine: goroutineID()}
+	return &Error{
+		ts:     time.Now().UnixMilli(),
+		err:    pe,
+		prev:   pe,
+		frames: panicStack(getStack(1)),
+		tags: map[string]interface{}{
+			panicTag:     true,
+			goroutineTag: pe.Goroutine,
+		},
+	}
+}
+
+// IsPanic returns true if `err` was produced by a recovered panic event.
+func IsPanic(err error) bool {
+	_, ok := lookupMarker(err, panicTag)
+	return ok
+}
+
 // FromRecover is a utility function to facilitate obtaining a useful
 // error instance from a panicked goroutine. To use it, simply pass the
 // native `recover()` to it from within the panicking goroutine:
@@ -143,3 +232,35 @@ func parsePanicFrame(name string, line string, createdBy bool) (*StackFrame, err
 		SourceLine: sourceLine(file, int(lno)),
 	}, nil
 }
+
+// Remove the frames corresponding to the recovery process from a stack
+// captured while panicking; i.e., the produced stack starts at the point
+// where `panic` was called. If no panic frame is found the stack is returned
+// unchanged.
+func panicStack(frames []StackFrame) []StackFrame {
+	for i, f := range frames {
+		if f.Package != "runtime" || f.Function != "gopanic" {
+			continue
+		}
+		// Runtime functions triggering the panic, like 'panicmem' or
+		// 'sigpanic', are also removed
+		i++
+		for i < len(frames) && frames[i].Package == "runtime" {
+			i++
+		}
+		return frames[i:]
+	}
+	return frames
+}
+
+// Return the identifier of the current goroutine.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if idx := bytes.IndexByte(buf, ' '); idx > 0 {
+		buf = buf[:idx]
+	}
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
@@ -46,3 +46,53 @@ func a() error { return b() }
 func b() error { return c() }
 
 func c() error { panic("cool programs never panic!!!") }
+
+func TestRecover(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Recover", func(t *testing.T) {
+		err := recoverSample()
+		assert.NotNil(err, "recovered error")
+		assert.Equal("cool programs never panic!!!", err.Error())
+		assert.True(IsPanic(err), "panic marker")
+		assert.False(IsPanic(New("regular error")), "regular error")
+		var pe *PanicError
+		assert.True(As(err, &pe), "panic error")
+		assert.NotZero(pe.Goroutine, "goroutine id")
+		frames := err.(HasStack).StackTrace()
+		assert.Equal("c", frames[0].Function, "stack starts at panic location")
+		assert.Equal("b", frames[1].Function)
+	})
+
+	t.Run("CapturePanic", func(t *testing.T) {
+		sentinel := New("sentinel")
+		err := CapturePanic(func() error { panic(sentinel) })
+		assert.True(Is(err, sentinel), "panic value is preserved")
+		assert.True(IsPanic(err), "panic marker")
+
+		// Runtime errors
+		err = CapturePanic(func() error {
+			var m map[string]int
+			m["invalid"] = 1
+			return nil
+		})
+		assert.True(IsPanic(err), "runtime error")
+		assert.Equal("TestRecover.func2.2", err.(HasStack).StackTrace()[0].Function)
+
+		// No panic
+		assert.Nil(CapturePanic(func() error { return nil }))
+		assert.Equal(sentinel, CapturePanic(func() error { return sentinel }))
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		js, err := Report(CapturePanic(func() error { panic("oops") }), CodecJSON(false))
+		assert.Nil(err, "report")
+		assert.Contains(string(js), panicTag)
+		assert.Contains(string(js), goroutineTag)
+	})
+}
+
+func recoverSample() (err error) {
+	defer Recover(&err)
+	return a()
+}
@@ -0,0 +1,243 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Default patterns used to identify sensitive values on free-form strings.
+var defaultPatterns = []string{
+	`[\w.+-]+@[\w-]+\.[\w.-]+`,                           // email addresses
+	`(?i)bearer\s+[\w.~+/-]+=*`,                          // bearer credentials
+	`eyJ[\w-]+\.[\w-]+\.[\w-]*`,                          // JWT values
+	`\b(?:\d{1,3}\.){3}\d{1,3}\b`,                        // IPv4 addresses
+	`\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b`,       // IPv6 addresses
+	`(?i)\b(?:[0-9a-f]{1,4}:){1,6}:(?:[0-9a-f]{1,4})?\b`, // IPv6 addresses (compressed)
+}
+
+// Default field names considered sensitive; matched as case-insensitive
+// substrings of tag, field and event attribute keys.
+var defaultFields = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"api_key",
+	"apikey",
+}
+
+// Redactor removes sensitive information from errors before generating
+// reports; so values like emails, credentials and IP addresses never leave
+// the process. Redaction is applied on a copy of the error, the original
+// instance is never modified.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	fields   []string
+	hooks    []func(string) string
+	strict   bool
+	safe     map[string]bool
+}
+
+// RedactorOption allows to adjust the behavior of a redactor instance.
+type RedactorOption func(r *Redactor) error
+
+// NewRedactor returns a new redactor instance. By default, emails, bearer
+// credentials, JWT values and IP addresses are removed from free-form
+// strings; and values for commonly sensitive keys (like "password" or
+// "token") are removed from tags, fields and event attributes.
+func NewRedactor(options ...RedactorOption) (*Redactor, error) {
+	r := &Redactor{
+		fields: append([]string{}, defaultFields...),
+		safe:   make(map[string]bool),
+	}
+	for _, p := range defaultPatterns {
+		r.patterns = append(r.patterns, regexp.MustCompile(p))
+	}
+	for _, opt := range options {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// WithPatterns registers additional regular expressions used to identify
+// sensitive values on free-form strings.
+func WithPatterns(expr ...string) RedactorOption {
+	return func(r *Redactor) error {
+		for _, e := range expr {
+			re, err := regexp.Compile(e)
+			if err != nil {
+				return Wrapf(err, "invalid pattern: %s", e)
+			}
+			r.patterns = append(r.patterns, re)
+		}
+		return nil
+	}
+}
+
+// WithSensitiveFields registers additional keys for which values are always
+// removed from tags, fields and event attributes. Keys are matched as
+// case-insensitive substrings.
+func WithSensitiveFields(keys ...string) RedactorOption {
+	return func(r *Redactor) error {
+		for _, k := range keys {
+			r.fields = append(r.fields, strings.ToLower(k))
+		}
+		return nil
+	}
+}
+
+// WithRedactionHook registers a custom function applied to all free-form
+// strings after the regular expressions.
+func WithRedactionHook(fn func(string) string) RedactorOption {
+	return func(r *Redactor) error {
+		if fn == nil {
+			return New("invalid redaction hook")
+		}
+		r.hooks = append(r.hooks, fn)
+		return nil
+	}
+}
+
+// WithStrictMode drops unsafe free-form strings entirely: hints, event
+// messages and attributes, and string values on tags and fields are removed,
+// except for the `safe` keys provided and the markers set by this package.
+// Error messages are preserved but still scrubbed.
+func WithStrictMode(safe ...string) RedactorOption {
+	return func(r *Redactor) error {
+		r.strict = true
+		for _, k := range safe {
+			r.safe[k] = true
+		}
+		return nil
+	}
+}
+
+// Redact returns a copy of `err` with all sensitive information removed.
+// The copy preserves the error structure (i.e., it can be used with `Is`,
+// `As`, and codecs).
+func (r *Redactor) Redact(err error) error {
+	return transform(err, r.links)
+}
+
+// String returns a redacted version of a free-form string.
+func (r *Redactor) String(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, piiMarker)
+	}
+	for _, fn := range r.hooks {
+		s = fn(s)
+	}
+	return s
+}
+
+// Codec returns a codec that applies the redactor to errors before
+// generating reports with `cc`.
+func (r *Redactor) Codec(cc Codec) Codec {
+	return &redactedCodec{cc: cc, r: r}
+}
+
+// Apply redaction to all the elements on an encoded error chain.
+func (r *Redactor) links(chain []chainLink) {
+	for i := range chain {
+		link := &chain[i]
+		link.Msg = r.String(link.Msg)
+		link.Prefix = r.String(link.Prefix)
+		link.Tags = r.values(link.Tags)
+		link.Fields = r.values(link.Fields)
+		for j := range link.Frames {
+			// Source lines can include literal values
+			link.Frames[j].SourceLine = r.String(link.Frames[j].SourceLine)
+			link.Frames[j].PreContext = r.strings(link.Frames[j].PreContext)
+			link.Frames[j].PostContext = r.strings(link.Frames[j].PostContext)
+		}
+
+		// Hints and events are copied to preserve the original error
+		hints := link.Hints
+		link.Hints = nil
+		for _, h := range hints {
+			if !r.strict {
+				link.Hints = append(link.Hints, r.String(h))
+			}
+		}
+		link.Events = append([]Event{}, link.Events...)
+		for j := range link.Events {
+			ev := &link.Events[j]
+			if r.strict {
+				ev.Message = ""
+				ev.Attributes = nil
+				continue
+			}
+			ev.Message = r.String(ev.Message)
+			ev.Attributes = r.values(ev.Attributes)
+		}
+		for _, member := range link.Errors {
+			r.links(member)
+		}
+	}
+}
+
+// Redact a list of free-form strings; a new list is returned.
+func (r *Redactor) strings(src []string) []string {
+	if src == nil {
+		return nil
+	}
+	res := make([]string, len(src))
+	for i, s := range src {
+		res[i] = r.String(s)
+	}
+	return res
+}
+
+// Redact a set of key/value pairs; a new map is returned.
+func (r *Redactor) values(src map[string]interface{}) map[string]interface{} {
+	if src == nil {
+		return nil
+	}
+	res := make(map[string]interface{}, len(src))
+	for k, v := range src {
+		switch {
+		case strings.HasPrefix(k, "error."):
+			res[k] = v // markers set by this package are always preserved
+		case r.sensitive(k):
+			res[k] = piiMarker
+		case r.strict && !r.safe[k]:
+			if _, ok := v.(string); ok {
+				continue // drop unsafe strings
+			}
+			res[k] = v
+		default:
+			if s, ok := v.(string); ok {
+				v = r.String(s)
+			}
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Determine if a key is considered sensitive.
+func (r *Redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, f := range r.fields {
+		if strings.Contains(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+type redactedCodec struct {
+	cc Codec
+	r  *Redactor
+}
+
+func (c *redactedCodec) Marshal(err error) ([]byte, error) {
+	return c.cc.Marshal(c.r.Redact(err))
+}
+
+func (c *redactedCodec) Unmarshal(src []byte) (bool, error) {
+	return c.cc.Unmarshal(src)
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRedactor(t *testing.T) {
+	assert := tdd.New(t)
+	root := New("login failed for rick@c137.com from 10.0.0.12")
+	oe := root.(*Error)
+	oe.AddHint("token used: Bearer abc.def-123")
+	oe.SetTag("password", "wubba-lubba")
+	oe.SetTag("attempts", 3)
+	oe.AddEvent(Event{Kind: "auth", Message: "user rick@c137.com", Attributes: map[string]interface{}{"ip": "10.0.0.12"}})
+	err := WithCode(WithFields(Wrap(root, "auth"), metadata.FromMap(map[string]interface{}{
+		"user":    "rick@c137.com",
+		"account": "acc-123",
+	})), Code{Domain: "test.auth", ID: "LOGIN_FAILED"})
+
+	t.Run("Default", func(t *testing.T) {
+		r, rErr := NewRedactor(WithPatterns(`acc-\d+`))
+		assert.Nil(rErr, "new redactor")
+		red := r.Redact(err)
+		assert.Equal("auth: login failed for ‹×› from ‹×›", red.Error())
+		assert.Equal("‹×›", Fields(red).Get("user"))
+		assert.Equal("‹×›", Fields(red).Get("account"))
+		assert.True(HasCode(red, Code{Domain: "test.auth", ID: "LOGIN_FAILED"}), "markers are preserved")
+		js, _ := Report(err, r.Codec(CodecJSON(false)))
+		for _, secret := range []string{"rick@c137.com", "10.0.0.12", "abc.def-123", "wubba-lubba", "acc-123"} {
+			assert.NotContains(string(js), secret, "report")
+		}
+
+		// Original error is not modified
+		assert.Equal("wubba-lubba", oe.Tags()["password"])
+		assert.Equal("token used: Bearer abc.def-123", oe.Hints()[0])
+		assert.Equal("user rick@c137.com", oe.Events()[0].Message)
+
+		// Status codes are preserved
+		restored := FromStatus(ToStatus(err, codes.PermissionDenied))
+		st := ToStatus(r.Redact(restored), codes.Unknown)
+		assert.Equal(codes.PermissionDenied, st.Code())
+		assert.False(strings.Contains(st.Message(), "rick@c137.com"))
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		r, rErr := NewRedactor(WithStrictMode("account"), WithRedactionHook(strings.ToUpper))
+		assert.Nil(rErr, "new redactor")
+		red := r.Redact(err)
+		assert.Equal("AUTH: LOGIN FAILED FOR ‹×› FROM ‹×›", red.Error())
+		assert.Nil(Fields(red).Get("user"), "unsafe value")
+		assert.Equal("ACC-123", Fields(red).Get("account"), "safe value")
+		codec := CodecGRPC()
+		data, _ := Report(err, r.Codec(codec))
+		ok, rec := codec.Unmarshal(data)
+		assert.True(ok, "unmarshal")
+		assert.True(HasCode(rec, Code{Domain: "test.auth", ID: "LOGIN_FAILED"}), "markers are preserved")
+		for _, secret := range []string{"wubba-lubba", "token used", "user RICK"} {
+			assert.NotContains(string(data), secret, "report")
+		}
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		// Redaction is applied by default
+		st := ToStatus(err, codes.Unknown)
+		assert.Equal("auth: login failed for ‹×› from ‹×›", st.Message())
+		data, _ := proto.Marshal(st.Proto())
+		for _, secret := range []string{"rick@c137.com", "10.0.0.12", "abc.def-123", "wubba-lubba"} {
+			assert.NotContains(string(data), secret, "status")
+		}
+		restored := FromStatus(st)
+		assert.True(HasCode(restored, Code{Domain: "test.auth", ID: "LOGIN_FAILED"}), "markers are preserved")
+
+		// Custom redactor
+		r, _ := NewRedactor(WithPatterns(`acc-\d+`))
+		data, _ = Report(err, CodecGRPC(WithStatusRedactor(r)))
+		assert.NotContains(string(data), "acc-123", "custom redactor")
+
+		// Redaction can be disabled explicitly
+		st = ToStatus(err, codes.Unknown, WithStatusRedactor(nil))
+		assert.Equal(err.Error(), st.Message())
+	})
+
+	_, rErr := NewRedactor(WithPatterns(`(`))
+	assert.NotNil(rErr, "invalid pattern")
+}
@@ -0,0 +1,47 @@
+package reporters
+
+import (
+	"context"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/metadata"
+)
+
+// Aggregate returns a sink that uses `tracker` to group error occurrences
+// by fingerprint before delivering them to `sink`. Occurrences not selected
+// for reporting are discarded; reported errors include the following fields:
+//   - error.fingerprint: fingerprint of the issue
+//   - error.occurrences: total number of occurrences of the issue
+//   - error.suppressed: occurrences discarded since the last report
+func Aggregate(sink Sink, tracker *errors.Tracker) Sink {
+	return &aggregate{sink: sink, tracker: tracker}
+}
+
+type aggregate struct {
+	sink    Sink
+	tracker *errors.Tracker
+}
+
+func (ag *aggregate) Report(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	occ := ag.tracker.Observe(err)
+	if !occ.Report {
+		return nil
+	}
+	return ag.sink.Report(ctx, errors.WithFields(err, metadata.FromMap(map[string]interface{}{
+		"error.fingerprint": occ.Fingerprint,
+		"error.occurrences": occ.Count,
+		"error.suppressed":  occ.Suppressed,
+	})))
+}
+
+func (ag *aggregate) Flush(timeout time.Duration) bool {
+	return ag.sink.Flush(timeout)
+}
+
+func (ag *aggregate) Close() error {
+	return ag.sink.Close()
+}
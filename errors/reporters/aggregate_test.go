@@ -0,0 +1,53 @@
+package reporters
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestAggregate(t *testing.T) {
+	assert := tdd.New(t)
+	ts := new(testSink)
+	sink := Aggregate(ts, errors.NewTracker(errors.WithSampling(5)))
+	for i := 0; i < 10; i++ {
+		assert.Nil(sink.Report(context.Background(), sampleError("a")))
+	}
+	assert.Nil(sink.Report(context.Background(), nil), "nil error")
+	assert.True(sink.Flush(time.Second), "flush")
+	assert.Nil(sink.Close(), "close")
+
+	assert.Len(ts.reports, 3, "aggregated reports")
+	fields := errors.Fields(ts.reports[2])
+	assert.Equal(errors.Fingerprint(ts.reports[0]), fields.Get("error.fingerprint"))
+	assert.Equal(uint64(10), fields.Get("error.occurrences"))
+	assert.Equal(uint64(4), fields.Get("error.suppressed"))
+}
+
+func sampleError(msg string) error {
+	return errors.New(msg)
+}
+
+type testSink struct {
+	reports []error
+	mu      sync.Mutex
+}
+
+func (ts *testSink) Report(_ context.Context, err error) error {
+	ts.mu.Lock()
+	ts.reports = append(ts.reports, err)
+	ts.mu.Unlock()
+	return nil
+}
+
+func (ts *testSink) Flush(_ time.Duration) bool {
+	return true
+}
+
+func (ts *testSink) Close() error {
+	return nil
+}
@@ -0,0 +1,224 @@
+package reporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Default endpoint of the Bugsnag error reporting API.
+const bugsnagEndpoint = "https://notify.bugsnag.com/"
+
+// BugsnagOptions defines the configuration settings for the Bugsnag sink.
+type BugsnagOptions struct {
+	// Project API key. Required.
+	APIKey string `mapstructure:"api_key" yaml:"api_key" json:"api_key"`
+
+	// Release stage used for events. Defaults to "production".
+	ReleaseStage string `mapstructure:"release_stage" yaml:"release_stage" json:"release_stage"`
+
+	// Version of the application.
+	AppVersion string `mapstructure:"app_version" yaml:"app_version" json:"app_version"`
+
+	// Server name reported with events. Defaults to the hostname.
+	Hostname string `mapstructure:"hostname" yaml:"hostname" json:"hostname"`
+
+	// Additional values included in the metadata of all events.
+	Tags map[string]string `mapstructure:"tags" yaml:"tags" json:"tags"`
+
+	// Custom API endpoint, for on-premise installations. Defaults to
+	// "https://notify.bugsnag.com/".
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+
+	// Maximum number of reports waiting to be delivered. Defaults to 1000.
+	QueueSize int `mapstructure:"queue_size" yaml:"queue_size" json:"queue_size"`
+
+	// Maximum number of additional attempts for failed requests. Each
+	// attempt doubles the delay before the next one. Defaults to 3.
+	MaxRetries uint `mapstructure:"max_retries" yaml:"max_retries" json:"max_retries"`
+
+	// The maximum time to wait for reports to be delivered when closing the
+	// sink. Defaults to 5 seconds.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout" yaml:"flush_timeout" json:"flush_timeout"`
+
+	// Redactor applied to errors before generating reports. If not
+	// provided, a redactor with the default settings is used.
+	Redactor *errors.Redactor `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Optional source code context included with stack frames.
+	SourceContext *errors.SourceContext `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Custom HTTP client, used for testing.
+	client *http.Client
+}
+
+// Bugsnag delivers error reports to a Bugsnag project.
+type Bugsnag struct {
+	opts BugsnagOptions
+	dsp  *dispatcher
+}
+
+// NewBugsnag returns a new sink instance to deliver error reports to Bugsnag.
+func NewBugsnag(opts BugsnagOptions) (*Bugsnag, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("bugsnag: API key is required")
+	}
+	if opts.ReleaseStage == "" {
+		opts.ReleaseStage = "production"
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = bugsnagEndpoint
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.FlushTimeout == 0 {
+		opts.FlushTimeout = 5 * time.Second
+	}
+	if opts.Redactor == nil {
+		opts.Redactor, _ = errors.NewRedactor()
+	}
+	client := opts.client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Bugsnag{
+		opts: opts,
+		dsp: newEndpointDispatcher(dispatchOptions{
+			name:       "bugsnag",
+			queueSize:  opts.QueueSize,
+			maxRetries: opts.MaxRetries,
+			retryDelay: 500 * time.Millisecond,
+		}, &endpoint{
+			name: "bugsnag",
+			url:  opts.Endpoint,
+			headers: map[string]string{
+				"Bugsnag-Api-Key":         opts.APIKey,
+				"Bugsnag-Payload-Version": "5",
+			},
+			client: client,
+		}),
+	}, nil
+}
+
+// Report submits an error instance to Bugsnag. The event includes the error
+// chain as exceptions along with its stacktraces; events are included as
+// breadcrumbs, while hints, tags and fields are included as metadata.
+// Sensitive information is removed from the error before generating the
+// report. Occurrences are grouped using a fingerprint calculated from the
+// error chain. Person and operation details available on the context are
+// included as the event's user and context.
+func (b *Bugsnag) Report(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if b.opts.SourceContext != nil {
+		err = b.opts.SourceContext.Enrich(err)
+	}
+	err = b.opts.Redactor.Redact(err)
+	payload, jErr := json.Marshal(map[string]interface{}{
+		"apiKey":         b.opts.APIKey,
+		"payloadVersion": "5",
+		"notifier": map[string]interface{}{
+			"name":    "go.bryk.io/pkg/errors/reporters",
+			"version": "0.1.0",
+			"url":     "https://github.com/bryk-io/pkg",
+		},
+		"events": []interface{}{b.event(ctx, err)},
+	})
+	if jErr != nil {
+		return errors.Wrap(jErr, "bugsnag")
+	}
+	return b.dsp.enqueue(payload)
+}
+
+// Flush waits until all pending reports are delivered, or the timeout
+// is reached.
+func (b *Bugsnag) Flush(timeout time.Duration) bool {
+	return b.dsp.wait(timeout)
+}
+
+// Close the sink instance, pending reports are flushed before returning.
+func (b *Bugsnag) Close() error {
+	return b.dsp.close(b.opts.FlushTimeout)
+}
+
+// Build the Bugsnag event for an error instance.
+// https://bugsnagerrorreportingapi.docs.apiary.io
+func (b *Bugsnag) event(ctx context.Context, err error) map[string]interface{} {
+	kind := errorType(err)
+	var exceptions []map[string]interface{}
+	for _, entry := range errorChain(err) {
+		// frames are sorted from the most recent call to the oldest one
+		frames := make([]map[string]interface{}, len(entry.frames))
+		for i, f := range entry.frames {
+			frames[i] = map[string]interface{}{
+				"file":       f.File,
+				"lineNumber": f.LineNumber,
+				"method":     f.Function,
+				"inProject":  true,
+			}
+		}
+		exceptions = append(exceptions, map[string]interface{}{
+			"errorClass": kind,
+			"message":    entry.message,
+			"stacktrace": frames,
+			"type":       "go",
+		})
+	}
+
+	// events are reported as breadcrumbs
+	var breadcrumbs []map[string]interface{}
+	data := customData(err, b.opts.Tags)
+	if events, ok := data["events"].([]errors.Event); ok {
+		delete(data, "events")
+		for _, e := range events {
+			breadcrumbs = append(breadcrumbs, map[string]interface{}{
+				"timestamp": time.UnixMilli(e.Stamp).UTC().Format(time.RFC3339Nano),
+				"name":      e.Message,
+				"type":      "log",
+				"metaData":  mergeKind(e.Attributes, e.Kind),
+			})
+		}
+	}
+
+	ev := map[string]interface{}{
+		"exceptions":     exceptions,
+		"breadcrumbs":    breadcrumbs,
+		"severity":       "error",
+		"unhandled":      false,
+		"severityReason": map[string]interface{}{"type": "handledException"},
+		"groupingHash":   errors.Fingerprint(err),
+		"app": map[string]interface{}{
+			"version":      b.opts.AppVersion,
+			"releaseStage": b.opts.ReleaseStage,
+		},
+		"device":   map[string]interface{}{"hostname": b.opts.Hostname},
+		"metaData": map[string]interface{}{"error": data},
+	}
+	if op := operationFrom(ctx); op != "" {
+		ev["context"] = op
+	}
+	if p, ok := personFrom(ctx); ok {
+		ev["user"] = map[string]interface{}{"id": p.ID, "name": p.Username, "email": p.Email}
+	}
+	return ev
+}
+
+func mergeKind(attrs map[string]interface{}, kind string) map[string]interface{} {
+	md := map[string]interface{}{"kind": kind}
+	for k, v := range attrs {
+		md[k] = v
+	}
+	return md
+}
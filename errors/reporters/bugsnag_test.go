@@ -0,0 +1,69 @@
+package reporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestBugsnag(t *testing.T) {
+	assert := tdd.New(t)
+	srv := &vendorEndpoint{status: http.StatusOK}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	_, err := NewBugsnag(BugsnagOptions{})
+	assert.NotNil(err, "missing API key")
+	var sink Sink
+	sink, err = NewBugsnag(BugsnagOptions{
+		APIKey:       "api-key",
+		ReleaseStage: "staging",
+		AppVersion:   "1.2.0",
+		Endpoint:     ts.URL,
+		client:       ts.Client(),
+	})
+	assert.Nil(err, "new sink")
+
+	// Report error
+	e1 := errors.New("payment declined")
+	e1.(*errors.Error).AddEvent(errors.Event{Kind: "query", Message: "charge attempt"})
+	e1.(*errors.Error).SetTag("customer", "rick@c137.com")
+	ctx := WithPerson(context.Background(), Person{ID: "42", Email: "rick@c137.com"})
+	ctx = WithOperation(ctx, "checkout")
+	assert.Nil(sink.Report(ctx, errors.Wrap(e1, "billing")), "report")
+	assert.True(sink.Flush(time.Second), "flush")
+	assert.Nil(sink.Close(), "close")
+
+	// Validate event
+	assert.Equal("api-key", srv.header.Get("Bugsnag-Api-Key"))
+	assert.Len(srv.payloads, 1, "notifications")
+	payload := struct {
+		Events []struct {
+			Context      string                            `json:"context"`
+			GroupingHash string                            `json:"groupingHash"`
+			User         map[string]string                 `json:"user"`
+			App          map[string]string                 `json:"app"`
+			Exceptions   []map[string]interface{}          `json:"exceptions"`
+			Breadcrumbs  []map[string]interface{}          `json:"breadcrumbs"`
+			MetaData     map[string]map[string]interface{} `json:"metaData"`
+		} `json:"events"`
+	}{}
+	assert.Nil(json.Unmarshal(srv.payloads[0], &payload))
+	assert.Len(payload.Events, 1)
+	ev := payload.Events[0]
+	assert.Equal("checkout", ev.Context)
+	assert.Equal(errors.Fingerprint(errors.Wrap(e1, "billing")), ev.GroupingHash, "deduplication")
+	assert.Equal("42", ev.User["id"])
+	assert.Equal("rick@c137.com", ev.User["email"], "explicit person details are not redacted")
+	assert.Equal("staging", ev.App["releaseStage"])
+	assert.Equal("billing: payment declined", ev.Exceptions[0]["message"])
+	assert.NotEmpty(ev.Exceptions[0]["stacktrace"], "stacktrace")
+	assert.Len(ev.Breadcrumbs, 1, "error events")
+	assert.Equal("‹×›", ev.MetaData["error"]["customer"], "redacted values")
+}
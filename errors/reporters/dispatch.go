@@ -0,0 +1,229 @@
+package reporters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// Returned when a report can't be added to a full delivery queue.
+var errQueueFull = errors.New("queue is full, report was dropped")
+
+// Dispatcher settings shared by all sinks delivering reports asynchronously.
+type dispatchOptions struct {
+	name       string        // sink name, used as prefix on errors
+	queueSize  int           // maximum number of pending reports
+	batchSize  int           // maximum number of reports on each delivery
+	interval   time.Duration // maximum time reports are kept before delivery
+	maxRetries uint          // additional attempts for failed requests
+	retryDelay time.Duration // initial delay between attempts
+}
+
+// Asynchronous delivery of reports. Reports are collected in batches of up
+// to `batchSize` elements and handed to the `deliver` function, when the
+// batch is full, when the interval elapses or when the dispatcher is
+// flushed or closed.
+type dispatcher struct {
+	opts    dispatchOptions
+	deliver func(batch []json.RawMessage)
+	queue   chan json.RawMessage
+	flush   chan chan struct{}
+	halt    chan struct{}
+	done    chan struct{}
+	closed  bool
+	closeMu sync.Mutex
+}
+
+func newDispatcher(opts dispatchOptions, deliver func(batch []json.RawMessage)) *dispatcher {
+	if opts.batchSize <= 0 {
+		opts.batchSize = 1
+	}
+	d := &dispatcher{
+		opts:    opts,
+		deliver: deliver,
+		queue:   make(chan json.RawMessage, opts.queueSize),
+		flush:   make(chan chan struct{}),
+		halt:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Add a report to the delivery queue. Returns an error wrapping `errQueueFull`
+// if the queue is full.
+func (d *dispatcher) enqueue(report json.RawMessage) error {
+	d.closeMu.Lock()
+	defer d.closeMu.Unlock()
+	if d.closed {
+		return errors.Errorf("%s: sink is closed", d.opts.name)
+	}
+	select {
+	case d.queue <- report:
+		return nil
+	default:
+		return errors.Wrap(errQueueFull, d.opts.name)
+	}
+}
+
+// Wait until all queued reports are delivered or the timeout is reached.
+func (d *dispatcher) wait(timeout time.Duration) bool {
+	ack := make(chan struct{})
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case d.flush <- ack:
+	case <-d.done:
+		return true
+	case <-timer.C:
+		return false
+	}
+	select {
+	case <-ack:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Stop accepting new reports and deliver pending ones.
+func (d *dispatcher) close(timeout time.Duration) error {
+	d.closeMu.Lock()
+	if d.closed {
+		d.closeMu.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.halt)
+	d.closeMu.Unlock()
+	select {
+	case <-d.done:
+		return nil
+	case <-time.After(timeout):
+		return errors.Errorf("%s: timeout while flushing reports", d.opts.name)
+	}
+}
+
+// Main processing loop.
+func (d *dispatcher) run() {
+	defer close(d.done)
+	var tick <-chan time.Time
+	if d.opts.interval > 0 {
+		ticker := time.NewTicker(d.opts.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	var batch []json.RawMessage
+	for {
+		select {
+		case report := <-d.queue:
+			batch = append(batch, report)
+			if len(batch) >= d.opts.batchSize {
+				d.deliver(batch)
+				batch = nil
+			}
+		case <-tick:
+			d.deliver(batch)
+			batch = nil
+		case ack := <-d.flush:
+			d.deliver(d.drain(batch))
+			batch = nil
+			close(ack)
+		case <-d.halt:
+			d.deliver(d.drain(batch))
+			return
+		}
+	}
+}
+
+// Collect all reports available on the queue.
+func (d *dispatcher) drain(batch []json.RawMessage) []json.RawMessage {
+	for {
+		select {
+		case report := <-d.queue:
+			batch = append(batch, report)
+		default:
+			return batch
+		}
+	}
+}
+
+// Execute `fn`, retrying failed attempts. Each attempt doubles the delay
+// before the next one. Permanent errors are not retried.
+func (opts dispatchOptions) retry(fn func() error) error {
+	delay := opts.retryDelay
+	for attempt := uint(0); ; attempt++ {
+		err := fn()
+		if err == nil || errors.IsPermanent(err) || attempt >= opts.maxRetries {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// HTTP endpoint receiving JSON payloads.
+type endpoint struct {
+	name    string            // sink name, used as prefix on errors
+	url     string            // endpoint location
+	headers map[string]string // additional headers
+	client  *http.Client      // HTTP client
+	timeout time.Duration     // request timeout, defaults to the client's
+	limiter *rate.Limiter     // optional rate limiter
+}
+
+// Submit a single request to the endpoint. Responses with a 4xx status
+// code, other than 429, are considered permanent failures.
+func (ep *endpoint) post(payload []byte) error {
+	timeout := ep.timeout
+	if timeout == 0 {
+		timeout = ep.client.Timeout
+	}
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if ep.limiter != nil {
+		if err := ep.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.MarkPermanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range ep.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := ep.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	if res.StatusCode < 300 {
+		return nil
+	}
+	err = errors.Errorf("%s: unexpected status code %d", ep.name, res.StatusCode)
+	if res.StatusCode >= 400 && res.StatusCode < 500 && res.StatusCode != http.StatusTooManyRequests {
+		return errors.MarkPermanent(err)
+	}
+	return err
+}
+
+// Dispatcher delivering each report individually to a single endpoint; used
+// by sinks submitting reports to vendor APIs.
+func newEndpointDispatcher(opts dispatchOptions, ep *endpoint) *dispatcher {
+	return newDispatcher(opts, func(batch []json.RawMessage) {
+		for _, report := range batch {
+			_ = opts.retry(func() error { return ep.post(report) })
+		}
+	})
+}
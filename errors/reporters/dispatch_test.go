@@ -0,0 +1,50 @@
+package reporters
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestDispatcher(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Deliveries are blocked until released
+	release := make(chan struct{})
+	delivered := make(chan int, 10)
+	d := newDispatcher(dispatchOptions{name: "test", queueSize: 1, batchSize: 2}, func(batch []json.RawMessage) {
+		<-release
+		delivered <- len(batch)
+	})
+
+	// Full queue
+	assert.Nil(d.enqueue(json.RawMessage(`"first"`)))
+	assert.Eventually(func() bool {
+		return d.enqueue(json.RawMessage(`"second"`)) == nil
+	}, time.Second, time.Millisecond, "report should be added to the batch")
+	assert.Eventually(func() bool {
+		return d.enqueue(json.RawMessage(`"third"`)) == nil
+	}, time.Second, time.Millisecond, "batch should be delivered")
+	err := d.enqueue(json.RawMessage(`"fourth"`))
+	assert.True(errors.Is(err, errQueueFull), "queue is full")
+	assert.Contains(err.Error(), "test:")
+
+	// Pending reports are delivered when closed
+	close(release)
+	assert.Nil(d.close(time.Second))
+	assert.Equal(2, <-delivered, "full batch")
+	assert.Equal(1, <-delivered, "pending reports")
+	assert.NotNil(d.enqueue(json.RawMessage(`"closed"`)), "closed dispatcher")
+
+	// Retries
+	attempts := 0
+	opts := dispatchOptions{maxRetries: 2, retryDelay: time.Millisecond}
+	assert.NotNil(opts.retry(func() error { attempts++; return errors.New("failed") }))
+	assert.Equal(3, attempts, "retried")
+	attempts = 0
+	assert.NotNil(opts.retry(func() error { attempts++; return errors.MarkPermanent(errors.New("failed")) }))
+	assert.Equal(1, attempts, "permanent errors are not retried")
+}
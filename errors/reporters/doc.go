@@ -0,0 +1,77 @@
+/*
+Package reporters provides sinks to deliver error reports to external services.
+
+Reports are produced using the structure preserved by the "errors" package;
+including the error chain, stacktraces, hints, tags, fields and events. Sinks
+are intended to be used directly by applications that don't require the full
+OpenTelemetry integration.
+
+# Sentry
+
+Reports can be sent to Sentry using its project DSN. Errors are grouped on
+Sentry using a fingerprint calculated from the error chain.
+
+	sink, err := NewSentry(SentryOptions{
+		DSN:         "https://public@sentry.example.com/1",
+		Environment: "production",
+		Release:     "my-service@1.0.0",
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer sink.Close()
+
+	// Report errors
+	_ = sink.Report(ctx, err)
+
+# Rollbar and Bugsnag
+
+Reports can be sent to Rollbar and Bugsnag projects using their APIs.
+Occurrences of the same issue are grouped using a fingerprint calculated
+from the error chain. Details about the user affected and the operation
+being executed can be attached to the context used to report errors.
+
+	sink, err := NewRollbar(RollbarOptions{
+		Token:       "my-access-token",
+		Environment: "production",
+		CodeVersion: "08a9b71",
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer sink.Close()
+
+	// Report errors
+	ctx = WithPerson(ctx, Person{ID: "42", Username: "rick"})
+	ctx = WithOperation(ctx, "GET /records/{id}")
+	_ = sink.Report(ctx, err)
+
+# Webhooks
+
+Reports can be delivered to one or more HTTP endpoints. Reports are batched,
+rate-limited and failed requests are retried. When a spill directory is
+provided, reports that can't be delivered during an outage are stored on
+disk and delivered once the endpoint is available again.
+
+	sink, err := NewWebhook(WebhookOptions{
+		URLs:     []string{"https://alerts.example.com/errors"},
+		Headers:  map[string]string{"Authorization": "Bearer my-token"},
+		SpillDir: "/var/lib/my-service/reports",
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer sink.Close()
+
+Sensitive information is removed from all errors before generating reports.
+
+# Aggregation
+
+To prevent sending one report per occurrence of the same issue, sinks can be
+wrapped to group errors by fingerprint. Reported errors include the number of
+occurrences of the issue.
+
+	tracker := errors.NewTracker(errors.WithWindow(5*time.Minute))
+	sink = Aggregate(sink, tracker)
+*/
+package reporters
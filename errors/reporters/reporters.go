@@ -0,0 +1,98 @@
+package reporters
+
+import (
+	"context"
+	stdErrors "errors"
+	"reflect"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Sink instances receive error reports and deliver them to an external
+// service.
+type Sink interface {
+	// Report submits an error instance. Delivery is usually asynchronous,
+	// a nil result doesn't guarantee the report was received.
+	Report(ctx context.Context, err error) error
+
+	// Flush waits until all pending reports are delivered, or the timeout
+	// is reached. Returns `false` if the timeout was reached.
+	Flush(timeout time.Duration) bool
+
+	// Close the sink instance, pending reports are flushed before returning.
+	Close() error
+}
+
+// Person identifies the user affected by an error. When available on the
+// context provided to `Report`, the details are included with the report.
+type Person struct {
+	// Unique identifier.
+	ID string `json:"id"`
+
+	// Display or user name.
+	Username string `json:"username,omitempty"`
+
+	// Email address.
+	Email string `json:"email,omitempty"`
+}
+
+type personKey struct{}
+
+type operationKey struct{}
+
+// WithPerson returns a copy of `ctx` including the details of the user
+// affected by errors reported with it.
+func WithPerson(ctx context.Context, p Person) context.Context {
+	return context.WithValue(ctx, personKey{}, p)
+}
+
+// WithOperation returns a copy of `ctx` including the name of the operation
+// (e.g., a route or a job name) being executed when errors reported with it
+// occurred.
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+func personFrom(ctx context.Context) (Person, bool) {
+	if ctx == nil {
+		return Person{}, false
+	}
+	p, ok := ctx.Value(personKey{}).(Person)
+	return p, ok
+}
+
+func operationFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+// Single element on an error chain.
+type chainEntry struct {
+	message string
+	frames  []errors.StackFrame
+}
+
+// Return the entries on the error chain, starting with the outermost one.
+// Wrapped errors sharing the same stacktrace are reported once.
+func errorChain(err error) []chainEntry {
+	var (
+		list []chainEntry
+		prev []errors.StackFrame
+	)
+	for e := err; e != nil; e = stdErrors.Unwrap(e) {
+		var frames []errors.StackFrame
+		if hs, ok := e.(errors.HasStack); ok {
+			frames = hs.StackTrace()
+		}
+		if len(list) > 0 && reflect.DeepEqual(frames, prev) {
+			continue
+		}
+		prev = frames
+		list = append(list, chainEntry{message: e.Error(), frames: frames})
+	}
+	return list
+}
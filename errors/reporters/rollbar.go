@@ -0,0 +1,223 @@
+package reporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Default endpoint of the Rollbar items API.
+const rollbarEndpoint = "https://api.rollbar.com/api/1/item/"
+
+// RollbarOptions defines the configuration settings for the Rollbar sink.
+type RollbarOptions struct {
+	// Project access token, with the `post_server_item` scope. Required.
+	Token string `mapstructure:"token" yaml:"token" json:"token"`
+
+	// Environment identifier used for items. Defaults to "production".
+	Environment string `mapstructure:"environment" yaml:"environment" json:"environment"`
+
+	// Version of the application; usually a commit hash or release tag.
+	CodeVersion string `mapstructure:"code_version" yaml:"code_version" json:"code_version"`
+
+	// Server name reported with items. Defaults to the hostname.
+	ServerName string `mapstructure:"server_name" yaml:"server_name" json:"server_name"`
+
+	// Additional values included in the custom data of all items.
+	Tags map[string]string `mapstructure:"tags" yaml:"tags" json:"tags"`
+
+	// Custom API endpoint. Defaults to "https://api.rollbar.com/api/1/item/".
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+
+	// Maximum number of reports waiting to be delivered. Defaults to 1000.
+	QueueSize int `mapstructure:"queue_size" yaml:"queue_size" json:"queue_size"`
+
+	// Maximum number of additional attempts for failed requests. Each
+	// attempt doubles the delay before the next one. Defaults to 3.
+	MaxRetries uint `mapstructure:"max_retries" yaml:"max_retries" json:"max_retries"`
+
+	// The maximum time to wait for reports to be delivered when closing the
+	// sink. Defaults to 5 seconds.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout" yaml:"flush_timeout" json:"flush_timeout"`
+
+	// Redactor applied to errors before generating reports. If not
+	// provided, a redactor with the default settings is used.
+	Redactor *errors.Redactor `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Optional source code context included with stack frames.
+	SourceContext *errors.SourceContext `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Custom HTTP client, used for testing.
+	client *http.Client
+}
+
+// Rollbar delivers error reports to a Rollbar project.
+type Rollbar struct {
+	opts RollbarOptions
+	dsp  *dispatcher
+}
+
+// NewRollbar returns a new sink instance to deliver error reports to Rollbar.
+func NewRollbar(opts RollbarOptions) (*Rollbar, error) {
+	if opts.Token == "" {
+		return nil, errors.New("rollbar: access token is required")
+	}
+	if opts.Environment == "" {
+		opts.Environment = "production"
+	}
+	if opts.ServerName == "" {
+		opts.ServerName, _ = os.Hostname()
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = rollbarEndpoint
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.FlushTimeout == 0 {
+		opts.FlushTimeout = 5 * time.Second
+	}
+	if opts.Redactor == nil {
+		opts.Redactor, _ = errors.NewRedactor()
+	}
+	client := opts.client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Rollbar{
+		opts: opts,
+		dsp: newEndpointDispatcher(dispatchOptions{
+			name:       "rollbar",
+			queueSize:  opts.QueueSize,
+			maxRetries: opts.MaxRetries,
+			retryDelay: 500 * time.Millisecond,
+		}, &endpoint{
+			name:    "rollbar",
+			url:     opts.Endpoint,
+			headers: map[string]string{"X-Rollbar-Access-Token": opts.Token},
+			client:  client,
+		}),
+	}, nil
+}
+
+// Report submits an error instance to Rollbar. The item includes the error
+// chain as a trace chain along with its stacktraces; hints, tags, fields and
+// events are included as custom data. Sensitive information is removed from
+// the error before generating the report. Occurrences are grouped using a
+// fingerprint calculated from the error chain. Person and operation details
+// available on the context are included as the item's person and context.
+func (r *Rollbar) Report(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if r.opts.SourceContext != nil {
+		err = r.opts.SourceContext.Enrich(err)
+	}
+	err = r.opts.Redactor.Redact(err)
+	payload, jErr := json.Marshal(map[string]interface{}{"data": r.item(ctx, err)})
+	if jErr != nil {
+		return errors.Wrap(jErr, "rollbar")
+	}
+	return r.dsp.enqueue(payload)
+}
+
+// Flush waits until all pending reports are delivered, or the timeout
+// is reached.
+func (r *Rollbar) Flush(timeout time.Duration) bool {
+	return r.dsp.wait(timeout)
+}
+
+// Close the sink instance, pending reports are flushed before returning.
+func (r *Rollbar) Close() error {
+	return r.dsp.close(r.opts.FlushTimeout)
+}
+
+// Build the Rollbar item for an error instance.
+// https://docs.rollbar.com/reference/create-item
+func (r *Rollbar) item(ctx context.Context, err error) map[string]interface{} {
+	kind := errorType(err)
+	var traces []map[string]interface{}
+	for _, entry := range errorChain(err) {
+		// frames are sorted from the oldest call to the most recent one
+		frames := make([]map[string]interface{}, len(entry.frames))
+		for i, f := range entry.frames {
+			frames[len(entry.frames)-1-i] = map[string]interface{}{
+				"filename": f.File,
+				"lineno":   f.LineNumber,
+				"method":   f.Function,
+				"code":     f.SourceLine,
+			}
+		}
+		traces = append(traces, map[string]interface{}{
+			"frames": frames,
+			"exception": map[string]interface{}{
+				"class":   kind,
+				"message": entry.message,
+			},
+		})
+	}
+	item := map[string]interface{}{
+		"environment":  r.opts.Environment,
+		"level":        "error",
+		"timestamp":    time.Now().Unix(),
+		"platform":     "go",
+		"language":     "go",
+		"code_version": r.opts.CodeVersion,
+		"title":        err.Error(),
+		"fingerprint":  errors.Fingerprint(err),
+		"server":       map[string]interface{}{"host": r.opts.ServerName},
+		"body":         map[string]interface{}{"trace_chain": traces},
+		"custom":       customData(err, r.opts.Tags),
+		"notifier":     map[string]interface{}{"name": "go.bryk.io/pkg/errors/reporters"},
+	}
+	if op := operationFrom(ctx); op != "" {
+		item["context"] = op
+	}
+	if p, ok := personFrom(ctx); ok {
+		item["person"] = p
+	}
+	return item
+}
+
+// Additional details available on the error chain: tags, hints, fields
+// and events.
+func customData(err error, tags map[string]string) map[string]interface{} {
+	data := map[string]interface{}{}
+	for k, v := range tags {
+		data[k] = v
+	}
+	if code, ok := errors.CodeOf(err); ok {
+		data["error.code"] = code.String()
+	}
+	if c := errors.CategoryOf(err); c != "" {
+		data["error.category"] = string(c)
+	}
+	for k, v := range errors.Fields(err).Values() {
+		data[k] = v
+	}
+	var (
+		hints  []string
+		events []errors.Event
+	)
+	for _, oe := range chain(err) {
+		for k, v := range oe.Tags() {
+			data[k] = v
+		}
+		hints = append(hints, oe.Hints()...)
+		events = append(events, oe.Events()...)
+	}
+	if len(hints) > 0 {
+		data["hints"] = hints
+	}
+	if len(events) > 0 {
+		data["events"] = events
+	}
+	return data
+}
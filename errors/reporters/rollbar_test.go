@@ -0,0 +1,115 @@
+package reporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/metadata"
+)
+
+func TestRollbar(t *testing.T) {
+	assert := tdd.New(t)
+	srv := &vendorEndpoint{status: http.StatusOK}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	_, err := NewRollbar(RollbarOptions{})
+	assert.NotNil(err, "missing token")
+	var sink Sink
+	sink, err = NewRollbar(RollbarOptions{
+		Token:       "secret-token",
+		Environment: "testing",
+		CodeVersion: "08a9b71",
+		Tags:        map[string]string{"service": "test"},
+		Endpoint:    ts.URL,
+		client:      ts.Client(),
+	})
+	assert.Nil(err, "new sink")
+
+	// Report error
+	e1 := errors.New("record not found")
+	e1.(*errors.Error).AddHint("verify the record identifier")
+	e2 := errors.WithFields(errors.Wrap(e1, "storage"), metadata.FromMap(map[string]interface{}{"record": "rec-123"}))
+	ctx := WithPerson(context.Background(), Person{ID: "42", Username: "rick"})
+	ctx = WithOperation(ctx, "GET /records/{id}")
+	assert.Nil(sink.Report(ctx, e2), "report")
+	assert.Nil(sink.Report(ctx, nil), "nil error")
+	assert.True(sink.Flush(time.Second), "flush")
+	assert.Nil(sink.Close(), "close")
+	assert.NotNil(sink.Report(ctx, e2), "closed sink")
+
+	// Validate item
+	assert.Equal("secret-token", srv.header.Get("X-Rollbar-Access-Token"))
+	assert.Len(srv.payloads, 1, "items")
+	item := struct {
+		Data struct {
+			Environment string                   `json:"environment"`
+			CodeVersion string                   `json:"code_version"`
+			Fingerprint string                   `json:"fingerprint"`
+			Context     string                   `json:"context"`
+			Person      Person                   `json:"person"`
+			Custom      map[string]interface{}   `json:"custom"`
+			Body        map[string][]interface{} `json:"body"`
+			Server      map[string]string        `json:"server"`
+		} `json:"data"`
+	}{}
+	assert.Nil(json.Unmarshal(srv.payloads[0], &item))
+	assert.Equal("testing", item.Data.Environment)
+	assert.Equal("08a9b71", item.Data.CodeVersion)
+	assert.Equal(errors.Fingerprint(e2), item.Data.Fingerprint, "deduplication")
+	assert.Equal("GET /records/{id}", item.Data.Context)
+	assert.Equal("rick", item.Data.Person.Username)
+	assert.Equal("test", item.Data.Custom["service"])
+	assert.Equal("rec-123", item.Data.Custom["record"])
+	assert.Equal([]interface{}{"verify the record identifier"}, item.Data.Custom["hints"])
+	assert.Len(item.Data.Body["trace_chain"], 1, "wrapped errors sharing the same stacktrace")
+
+	// Permanent failures are not retried
+	srv.reset(http.StatusUnprocessableEntity)
+	sink, _ = NewRollbar(RollbarOptions{Token: "invalid", Endpoint: ts.URL, client: ts.Client()})
+	assert.Nil(sink.Report(ctx, e2), "report")
+	assert.Nil(sink.Close(), "close")
+	assert.Equal(1, srv.requests, "no retries")
+}
+
+// Test endpoint collecting the payloads received.
+type vendorEndpoint struct {
+	status   int
+	requests int
+	header   http.Header
+	payloads []json.RawMessage
+	mu       sync.Mutex
+}
+
+func (ve *vendorEndpoint) reset(status int) {
+	ve.mu.Lock()
+	ve.status = status
+	ve.requests = 0
+	ve.payloads = nil
+	ve.mu.Unlock()
+}
+
+func (ve *vendorEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ve.mu.Lock()
+	defer ve.mu.Unlock()
+	ve.requests++
+	ve.header = r.Header.Clone()
+	if ve.status != http.StatusOK {
+		w.WriteHeader(ve.status)
+		return
+	}
+	payload := json.RawMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ve.payloads = append(ve.payloads, payload)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,232 @@
+package reporters
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/getsentry/sentry-go"
+	"go.bryk.io/pkg/errors"
+)
+
+// SentryOptions defines the configuration settings for the Sentry sink.
+type SentryOptions struct {
+	// Project DSN provided by Sentry. Required.
+	DSN string `mapstructure:"dsn" yaml:"dsn" json:"dsn"`
+
+	// Environment identifier used for events.
+	Environment string `mapstructure:"environment" yaml:"environment" json:"environment"`
+
+	// Release identifier. Must be unique across all services.
+	// Usual format is:
+	//   service-name@version+commit-hash
+	Release string `mapstructure:"release" yaml:"release" json:"release"`
+
+	// Server name reported with events. Defaults to the hostname.
+	ServerName string `mapstructure:"server_name" yaml:"server_name" json:"server_name"`
+
+	// Additional tags included in all events.
+	Tags map[string]string `mapstructure:"tags" yaml:"tags" json:"tags"`
+
+	// The maximum time to wait for events to be sent when closing the
+	// sink. Defaults to 2 seconds.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout" yaml:"flush_timeout" json:"flush_timeout"`
+
+	// Redactor applied to errors before generating reports. If not
+	// provided, a redactor with the default settings is used.
+	Redactor *errors.Redactor `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Optional source code context included with stack frames. Source
+	// files must be available at runtime or provided using a custom file
+	// system.
+	SourceContext *errors.SourceContext `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Custom transport, used for testing.
+	transport sdk.Transport
+}
+
+// Sentry delivers error reports to a Sentry project.
+type Sentry struct {
+	client *sdk.Client
+	opts   SentryOptions
+}
+
+// NewSentry returns a new sink instance to deliver error reports to Sentry.
+// The sink uses its own client instance and doesn't alter the global Sentry
+// SDK configuration.
+func NewSentry(opts SentryOptions) (*Sentry, error) {
+	if opts.DSN == "" {
+		return nil, errors.New("sentry: DSN is required")
+	}
+	if opts.FlushTimeout == 0 {
+		opts.FlushTimeout = 2 * time.Second
+	}
+	if opts.Redactor == nil {
+		opts.Redactor, _ = errors.NewRedactor()
+	}
+	client, err := sdk.NewClient(sdk.ClientOptions{
+		Dsn:         opts.DSN,
+		Environment: opts.Environment,
+		Release:     opts.Release,
+		ServerName:  opts.ServerName,
+		Transport:   opts.transport,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "sentry")
+	}
+	return &Sentry{client: client, opts: opts}, nil
+}
+
+// Report submits an error instance to Sentry. The event includes the
+// error chain as exceptions along with its stacktraces; hints, tags, fields
+// and events are also included. Sensitive information is removed from the
+// error before generating the report. Events are grouped using a fingerprint
+// calculated from the error chain. Person and operation details available
+// on the context are reported as the event's user and transaction.
+func (s *Sentry) Report(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if s.opts.SourceContext != nil {
+		err = s.opts.SourceContext.Enrich(err)
+	}
+	err = s.opts.Redactor.Redact(err)
+	id := s.client.CaptureEvent(s.event(ctx, err), &sdk.EventHint{Context: ctx, OriginalException: err}, nil)
+	if id == nil {
+		return errors.New("sentry: event was dropped")
+	}
+	return nil
+}
+
+// Flush waits until all pending reports are delivered, or the timeout
+// is reached.
+func (s *Sentry) Flush(timeout time.Duration) bool {
+	return s.client.Flush(timeout)
+}
+
+// Close the sink instance, pending reports are flushed before returning.
+func (s *Sentry) Close() error {
+	if !s.client.Flush(s.opts.FlushTimeout) {
+		return errors.New("sentry: timeout while flushing events")
+	}
+	return nil
+}
+
+// Build the Sentry event for an error instance.
+func (s *Sentry) event(ctx context.Context, err error) *sdk.Event {
+	ev := sdk.NewEvent()
+	ev.Level = sdk.LevelError
+	ev.Transaction = operationFrom(ctx)
+	if p, ok := personFrom(ctx); ok {
+		ev.User = sdk.User{ID: p.ID, Username: p.Username, Email: p.Email}
+	}
+	ev.Exception = exceptions(err)
+	ev.Fingerprint = []string{errors.Fingerprint(err)}
+	for k, v := range s.opts.Tags {
+		ev.Tags[k] = v
+	}
+	if code, ok := errors.CodeOf(err); ok {
+		ev.Tags["error.code"] = code.String()
+	}
+	if c := errors.CategoryOf(err); c != "" {
+		ev.Tags["error.category"] = string(c)
+	}
+	for k, v := range errors.Fields(err).Values() {
+		ev.Extra[k] = v
+	}
+
+	// Details available on the error chain
+	details := sdk.Context{}
+	for _, oe := range chain(err) {
+		for k, v := range oe.Tags() {
+			details[k] = v
+		}
+		if hints := oe.Hints(); len(hints) > 0 {
+			details["hints"] = append(toStrings(details["hints"]), hints...)
+		}
+		for _, e := range oe.Events() {
+			ev.Breadcrumbs = append(ev.Breadcrumbs, &sdk.Breadcrumb{
+				Category:  e.Kind,
+				Message:   e.Message,
+				Data:      e.Attributes,
+				Level:     sdk.LevelInfo,
+				Timestamp: time.UnixMilli(e.Stamp),
+			})
+		}
+	}
+	if len(details) > 0 {
+		ev.Contexts["error"] = details
+	}
+	return ev
+}
+
+// Produce the list of exceptions for an error chain. Sentry expects the
+// outermost (main) exception to be the last on the list. Wrapped errors
+// sharing the same stacktrace are reported once.
+func exceptions(err error) []sdk.Exception {
+	var list []sdk.Exception
+	kind := errorType(err)
+	for _, entry := range errorChain(err) {
+		list = append(list, sdk.Exception{
+			Type:       kind,
+			Value:      entry.message,
+			Stacktrace: stacktrace(entry.frames),
+		})
+	}
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
+
+// Convert stack frames to the format expected by Sentry; from the oldest
+// call to the most recent one.
+func stacktrace(frames []errors.StackFrame) *sdk.Stacktrace {
+	if len(frames) == 0 {
+		return nil
+	}
+	st := &sdk.Stacktrace{Frames: make([]sdk.Frame, len(frames))}
+	for i, f := range frames {
+		st.Frames[len(frames)-1-i] = sdk.Frame{
+			Function:    f.Function,
+			Module:      f.Package,
+			AbsPath:     f.File,
+			Filename:    f.File,
+			Lineno:      f.LineNumber,
+			ContextLine: f.SourceLine,
+			PreContext:  f.PreContext,
+			PostContext: f.PostContext,
+			InApp:       true,
+		}
+	}
+	return st
+}
+
+// Error type used as title for the issue; the error code if available,
+// otherwise the type of the root cause.
+func errorType(err error) string {
+	if code, ok := errors.CodeOf(err); ok {
+		return code.String()
+	}
+	if cause := errors.Cause(err); cause != nil {
+		return fmt.Sprintf("%T", cause)
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// Return all error instances on the chain.
+func chain(err error) []*errors.Error {
+	var list []*errors.Error
+	for e := err; e != nil; e = stdErrors.Unwrap(e) {
+		if oe, ok := e.(*errors.Error); ok { // nolint: errorlint
+			list = append(list, oe)
+		}
+	}
+	return list
+}
+
+func toStrings(v interface{}) []string {
+	list, _ := v.([]string)
+	return list
+}
@@ -0,0 +1,74 @@
+package reporters
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/getsentry/sentry-go"
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/metadata"
+)
+
+func TestSentry(t *testing.T) {
+	assert := tdd.New(t)
+	tr := new(testTransport)
+	var sink Sink
+	sink, err := NewSentry(SentryOptions{
+		DSN:         "https://public@sentry.example.com/1",
+		Environment: "testing",
+		Release:     "reporters@0.1.0",
+		Tags:        map[string]string{"service": "test"},
+		transport:   tr,
+	})
+	assert.Nil(err, "new sink")
+	_, err = NewSentry(SentryOptions{})
+	assert.NotNil(err, "missing DSN")
+
+	// Report error
+	e1 := errors.New("record not found")
+	e1.(*errors.Error).SetTag("user", "rick@c137.com")
+	e1.(*errors.Error).AddHint("verify the record identifier")
+	e1.(*errors.Error).AddEvent(errors.Event{Kind: "query", Message: "record lookup"})
+	e2 := errors.WithFields(errors.Wrap(e1, "storage"), metadata.FromMap(map[string]interface{}{"record": "rec-123"}))
+	assert.Nil(sink.Report(context.Background(), e2), "report")
+	assert.Nil(sink.Report(context.Background(), nil), "nil error")
+	assert.True(sink.Flush(time.Second), "flush")
+	assert.Nil(sink.Close(), "close")
+
+	// Validate event
+	assert.Len(tr.events, 1, "events")
+	ev := tr.events[0]
+	assert.Equal("testing", ev.Environment)
+	assert.Equal("reporters@0.1.0", ev.Release)
+	assert.Equal("test", ev.Tags["service"])
+	assert.Equal("rec-123", ev.Extra["record"])
+	assert.Len(ev.Exception, 1, "wrapped errors sharing the same stacktrace")
+	assert.Equal("storage: record not found", ev.Exception[0].Value)
+	assert.NotEmpty(ev.Exception[0].Stacktrace.Frames, "stacktrace")
+	assert.Equal([]string{"verify the record identifier"}, ev.Contexts["error"]["hints"])
+	assert.Len(ev.Breadcrumbs, 1, "error events")
+	assert.Equal("‹×›", ev.Contexts["error"]["user"], "redacted values")
+
+	// Fingerprint is stable across occurrences
+	assert.Equal([]string{errors.Fingerprint(e2)}, ev.Fingerprint)
+}
+
+type testTransport struct {
+	events []*sdk.Event
+	mu     sync.Mutex
+}
+
+func (tt *testTransport) Flush(_ time.Duration) bool {
+	return true
+}
+
+func (tt *testTransport) Configure(_ sdk.ClientOptions) {}
+
+func (tt *testTransport) SendEvent(event *sdk.Event) {
+	tt.mu.Lock()
+	tt.events = append(tt.events, event)
+	tt.mu.Unlock()
+}
@@ -0,0 +1,291 @@
+package reporters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// WebhookOptions defines the configuration settings for the webhook sink.
+type WebhookOptions struct {
+	// Endpoints receiving the reports. Required.
+	URLs []string `mapstructure:"urls" yaml:"urls" json:"urls"`
+
+	// Additional HTTP headers included in all requests; for example to
+	// provide authentication credentials.
+	Headers map[string]string `mapstructure:"headers" yaml:"headers" json:"headers"`
+
+	// Maximum number of reports delivered on a single request.
+	// Defaults to 20.
+	BatchSize int `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`
+
+	// Maximum time reports are kept on the queue before being delivered.
+	// Defaults to 5 seconds.
+	FlushInterval time.Duration `mapstructure:"flush_interval" yaml:"flush_interval" json:"flush_interval"`
+
+	// Maximum number of requests per second sent to each endpoint.
+	// Defaults to 10.
+	RateLimit uint `mapstructure:"rate_limit" yaml:"rate_limit" json:"rate_limit"`
+
+	// Maximum number of additional attempts for failed requests. Each
+	// attempt doubles the delay before the next one. Defaults to 3.
+	MaxRetries uint `mapstructure:"max_retries" yaml:"max_retries" json:"max_retries"`
+
+	// Initial delay used between attempts. Defaults to 500ms.
+	RetryDelay time.Duration `mapstructure:"retry_delay" yaml:"retry_delay" json:"retry_delay"`
+
+	// Maximum number of reports waiting to be delivered. Defaults to 1000.
+	QueueSize int `mapstructure:"queue_size" yaml:"queue_size" json:"queue_size"`
+
+	// Timeout for individual requests. Defaults to 10 seconds.
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+
+	// The maximum time to wait for reports to be delivered when closing the
+	// sink. Defaults to 5 seconds.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout" yaml:"flush_timeout" json:"flush_timeout"`
+
+	// Optional local directory used to store reports that can't be delivered,
+	// for example during an outage of the endpoint or when the queue is
+	// full. Stored reports are delivered once the endpoint is available
+	// again. If not provided, such reports are discarded.
+	SpillDir string `mapstructure:"spill_dir" yaml:"spill_dir" json:"spill_dir"`
+
+	// Redactor applied to errors before generating reports. If not
+	// provided, a redactor with the default settings is used.
+	Redactor *errors.Redactor `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Optional source code context included with stack frames.
+	SourceContext *errors.SourceContext `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Custom HTTP client, used for testing.
+	client *http.Client
+}
+
+// Webhook delivers error reports to one or more HTTP endpoints. Reports are
+// generated using the JSON codec and delivered in batches as:
+//
+//	{"reports": [...]}
+//
+// Delivery is asynchronous, rate-limited and failed requests are retried.
+// When an endpoint is unavailable, undelivered reports can be stored on disk
+// and delivered later. Responses with a 4xx status code, other than 429, are
+// considered permanent failures and the reports are discarded.
+type Webhook struct {
+	opts      WebhookOptions
+	codec     errors.Codec
+	endpoints map[string]*endpoint
+	dspOpts   dispatchOptions
+	dsp       *dispatcher
+	seq       atomic.Uint64
+}
+
+// Batch of reports stored on disk for a specific endpoint.
+type spillRecord struct {
+	URL     string            `json:"url"`
+	Reports []json.RawMessage `json:"reports"`
+}
+
+// NewWebhook returns a new sink instance to deliver error reports to the
+// provided HTTP endpoints.
+func NewWebhook(opts WebhookOptions) (*Webhook, error) {
+	if len(opts.URLs) == 0 {
+		return nil, errors.New("webhook: at least one URL is required")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 20
+	}
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.RateLimit == 0 {
+		opts.RateLimit = 10
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.RetryDelay == 0 {
+		opts.RetryDelay = 500 * time.Millisecond
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.FlushTimeout == 0 {
+		opts.FlushTimeout = 5 * time.Second
+	}
+	if opts.Redactor == nil {
+		opts.Redactor, _ = errors.NewRedactor()
+	}
+	if opts.SpillDir != "" {
+		if err := os.MkdirAll(opts.SpillDir, 0700); err != nil {
+			return nil, errors.Wrap(err, "webhook: invalid spill directory")
+		}
+	}
+	client := opts.client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	codec := opts.Redactor.Codec(errors.CodecJSON(false))
+	if opts.SourceContext != nil {
+		codec = opts.SourceContext.Codec(codec)
+	}
+	wh := &Webhook{
+		opts:      opts,
+		codec:     codec,
+		endpoints: make(map[string]*endpoint),
+	}
+	for _, u := range opts.URLs {
+		wh.endpoints[u] = &endpoint{
+			name:    "webhook",
+			url:     u,
+			headers: opts.Headers,
+			client:  client,
+			timeout: opts.Timeout,
+			limiter: rate.NewLimiter(rate.Limit(opts.RateLimit), 1),
+		}
+	}
+	wh.dspOpts = dispatchOptions{
+		name:       "webhook",
+		queueSize:  opts.QueueSize,
+		batchSize:  opts.BatchSize,
+		interval:   opts.FlushInterval,
+		maxRetries: opts.MaxRetries,
+		retryDelay: opts.RetryDelay,
+	}
+	wh.dsp = newDispatcher(wh.dspOpts, wh.deliver)
+	return wh, nil
+}
+
+// Report adds an error instance to the delivery queue. Sensitive information
+// is removed from the error before generating the report. If the queue is
+// full, the report is stored on disk (when a spill directory is available)
+// or discarded.
+func (wh *Webhook) Report(_ context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	report, rErr := errors.Report(err, wh.codec)
+	if rErr != nil {
+		return errors.Wrap(rErr, "webhook")
+	}
+	qErr := wh.dsp.enqueue(report)
+	if !errors.Is(qErr, errQueueFull) || wh.opts.SpillDir == "" {
+		return qErr
+	}
+	for _, u := range wh.opts.URLs {
+		if sErr := wh.spill(u, []json.RawMessage{report}); sErr != nil {
+			return sErr
+		}
+	}
+	return nil
+}
+
+// Flush delivers all queued reports, along with reports previously stored
+// on disk, and waits until done or the timeout is reached.
+func (wh *Webhook) Flush(timeout time.Duration) bool {
+	return wh.dsp.wait(timeout)
+}
+
+// Close the sink instance, pending reports are delivered before returning.
+// Reports that can't be delivered are stored on disk, if a spill directory
+// is available.
+func (wh *Webhook) Close() error {
+	return wh.dsp.close(wh.opts.FlushTimeout)
+}
+
+// Deliver reports to all endpoints. Reports that can't be delivered are
+// stored on disk. Once an endpoint is available, previously stored reports
+// are delivered as well.
+func (wh *Webhook) deliver(batch []json.RawMessage) {
+	for _, u := range wh.opts.URLs {
+		available := true
+		for start := 0; start < len(batch); start += wh.opts.BatchSize {
+			end := start + wh.opts.BatchSize
+			if end > len(batch) {
+				end = len(batch)
+			}
+			err := wh.send(u, batch[start:end])
+			if err != nil {
+				available = false
+				if !errors.IsPermanent(err) {
+					_ = wh.spill(u, batch[start:end])
+				}
+			}
+		}
+		if available {
+			wh.replay(u)
+		}
+	}
+}
+
+// Send a batch of reports to the endpoint, retrying failed requests.
+func (wh *Webhook) send(url string, reports []json.RawMessage) error {
+	payload, err := json.Marshal(map[string]interface{}{"reports": reports})
+	if err != nil {
+		return errors.MarkPermanent(err)
+	}
+	ep := wh.endpoints[url]
+	return wh.dspOpts.retry(func() error { return ep.post(payload) })
+}
+
+// Store reports on disk to be delivered later to the endpoint.
+func (wh *Webhook) spill(url string, reports []json.RawMessage) error {
+	if wh.opts.SpillDir == "" || len(reports) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(spillRecord{URL: url, Reports: reports})
+	if err != nil {
+		return errors.Wrap(err, "webhook")
+	}
+	seq := wh.seq.Add(1)
+	name := fmt.Sprintf("%019d-%06d.json", time.Now().UnixNano(), seq)
+	file := filepath.Join(wh.opts.SpillDir, name)
+
+	// Write to a temporary file first so partial records are never replayed
+	if err = os.WriteFile(file+".tmp", data, 0600); err != nil {
+		return errors.Wrap(err, "webhook: failed to store reports")
+	}
+	return errors.Wrap(os.Rename(file+".tmp", file), "webhook: failed to store reports")
+}
+
+// Deliver reports previously stored on disk for the endpoint, in the same
+// order they were stored. Stops at the first failed delivery.
+func (wh *Webhook) replay(url string) {
+	if wh.opts.SpillDir == "" {
+		return
+	}
+	files, err := filepath.Glob(filepath.Join(wh.opts.SpillDir, "*.json"))
+	if err != nil {
+		return
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			continue
+		}
+		rec := spillRecord{}
+		if err = json.Unmarshal(data, &rec); err != nil {
+			_ = os.Remove(file) // corrupt record
+			continue
+		}
+		if rec.URL != url {
+			continue
+		}
+		if err = wh.send(url, rec.Reports); err != nil && !errors.IsPermanent(err) {
+			return
+		}
+		_ = os.Remove(file)
+	}
+}
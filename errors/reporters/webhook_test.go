@@ -0,0 +1,131 @@
+package reporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestWebhook(t *testing.T) {
+	assert := tdd.New(t)
+	srv := &testEndpoint{status: http.StatusOK}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	_, err := NewWebhook(WebhookOptions{})
+	assert.NotNil(err, "missing URLs")
+
+	t.Run("Batches", func(t *testing.T) {
+		srv.reset(http.StatusOK)
+		var sink Sink
+		sink, err := NewWebhook(WebhookOptions{
+			URLs:       []string{ts.URL},
+			Headers:    map[string]string{"Authorization": "Bearer secret"},
+			BatchSize:  2,
+			RateLimit:  100,
+			RetryDelay: 10 * time.Millisecond,
+		})
+		assert.Nil(err, "new sink")
+		for i := 0; i < 5; i++ {
+			assert.Nil(sink.Report(context.Background(), errors.Errorf("failed for rick@c137.com (%d)", i)))
+		}
+		assert.Nil(sink.Report(context.Background(), nil), "nil error")
+		assert.True(sink.Flush(time.Second), "flush")
+		assert.Nil(sink.Close(), "close")
+		assert.NotNil(sink.Report(context.Background(), errors.New("closed")), "closed sink")
+
+		assert.Len(srv.batches, 3, "batches")
+		assert.Len(srv.batches[0], 2, "batch size")
+		assert.Equal("Bearer secret", srv.auth, "custom headers")
+		report := map[string]interface{}{}
+		assert.Nil(json.Unmarshal(srv.batches[0][0], &report))
+		assert.Equal("failed for ‹×› (0)", report["error"], "redacted report")
+	})
+
+	t.Run("Outage", func(t *testing.T) {
+		srv.reset(http.StatusServiceUnavailable)
+		dir := t.TempDir()
+		sink, err := NewWebhook(WebhookOptions{
+			URLs:       []string{ts.URL},
+			RateLimit:  100,
+			MaxRetries: 1,
+			RetryDelay: 10 * time.Millisecond,
+			SpillDir:   dir,
+		})
+		assert.Nil(err, "new sink")
+		assert.Nil(sink.Report(context.Background(), errors.New("service unavailable")))
+		assert.True(sink.Flush(time.Second), "flush")
+		assert.Empty(srv.batches, "no reports delivered")
+		files, _ := os.ReadDir(dir)
+		assert.Len(files, 1, "reports stored on disk")
+
+		// Stored reports are delivered once the endpoint is available
+		srv.reset(http.StatusOK)
+		assert.True(sink.Flush(time.Second), "flush")
+		assert.Len(srv.batches, 1, "stored reports delivered")
+		files, _ = os.ReadDir(dir)
+		assert.Empty(files, "stored reports removed")
+		assert.Nil(sink.Close(), "close")
+	})
+
+	t.Run("Permanent", func(t *testing.T) {
+		srv.reset(http.StatusBadRequest)
+		dir := t.TempDir()
+		sink, err := NewWebhook(WebhookOptions{
+			URLs:       []string{ts.URL},
+			RateLimit:  100,
+			RetryDelay: 10 * time.Millisecond,
+			SpillDir:   dir,
+		})
+		assert.Nil(err, "new sink")
+		assert.Nil(sink.Report(context.Background(), errors.New("invalid request")))
+		assert.Nil(sink.Close(), "close")
+		assert.Equal(1, srv.requests, "no retries")
+		files, _ := os.ReadDir(dir)
+		assert.Empty(files, "reports discarded")
+	})
+}
+
+type testEndpoint struct {
+	status   int
+	requests int
+	auth     string
+	batches  [][]json.RawMessage
+	mu       sync.Mutex
+}
+
+func (te *testEndpoint) reset(status int) {
+	te.mu.Lock()
+	te.status = status
+	te.requests = 0
+	te.batches = nil
+	te.mu.Unlock()
+}
+
+func (te *testEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	te.requests++
+	te.auth = r.Header.Get("Authorization")
+	if te.status != http.StatusOK {
+		w.WriteHeader(te.status)
+		return
+	}
+	payload := struct {
+		Reports []json.RawMessage `json:"reports"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	te.batches = append(te.batches, payload.Reports)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,167 @@
+package errors
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Default number of lines included before and after the source line of
+// each frame.
+const defaultSourceLines = 3
+
+// SourceContext enriches stack frames with a few lines of source code
+// surrounding the reported location; dramatically reducing the time required
+// to diagnose an error when reading its report. Source files are read from
+// the local file system when available or, optionally, from a custom file
+// system like an embedded one. Frames for which the source file can't be
+// located are left unchanged.
+//
+//	//go:embed *.go
+//	var sources embed.FS
+//
+//	sc := errors.SourceContext{Lines: 5, FS: sources}
+//	report, _ := errors.Report(err, sc.Codec(errors.CodecJSON(true)))
+type SourceContext struct {
+	// Number of lines to include before and after the source line.
+	// Defaults to 3.
+	Lines int
+
+	// Optional file system used to locate source files. Paths are matched
+	// using the longest suffix of the frame's file path available on the
+	// file system; for example, a frame on "/src/app/api/server.go" can be
+	// resolved using "api/server.go" or "server.go".
+	FS fs.FS
+}
+
+// Enrich returns a copy of `err` with source code context included in its
+// stack frames. The copy preserves the error structure (i.e., it can be used
+// with `Is`, `As`, and codecs).
+func (sc SourceContext) Enrich(err error) error {
+	cache := make(map[string][]string)
+	var fn func(chain []chainLink)
+	fn = func(chain []chainLink) {
+		for i := range chain {
+			chain[i].Frames = sc.frames(chain[i].Frames, cache)
+			for _, member := range chain[i].Errors {
+				fn(member)
+			}
+		}
+	}
+	return transform(err, fn)
+}
+
+// Codec returns a codec that includes source code context on the reports
+// generated with `cc`.
+func (sc SourceContext) Codec(cc Codec) Codec {
+	return &sourceCodec{cc: cc, sc: sc}
+}
+
+// Add source code context to a list of frames; a new list is returned.
+func (sc SourceContext) frames(src []StackFrame, cache map[string][]string) []StackFrame {
+	if len(src) == 0 {
+		return src
+	}
+	n := sc.Lines
+	if n <= 0 {
+		n = defaultSourceLines
+	}
+	res := make([]StackFrame, len(src))
+	copy(res, src)
+	for i := range res {
+		f := &res[i]
+		lines, ok := cache[f.File]
+		if !ok {
+			lines = sc.load(f.File)
+			cache[f.File] = lines
+		}
+		if f.LineNumber <= 0 || f.LineNumber > len(lines) {
+			continue
+		}
+		idx := f.LineNumber - 1
+		start, end := idx-n, idx+n+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		f.PreContext = append([]string{}, lines[start:idx]...)
+		f.PostContext = append([]string{}, lines[idx+1:end]...)
+	}
+	return res
+}
+
+// Load the contents of a source file; 'nil' is returned if the file is
+// not available.
+func (sc SourceContext) load(file string) []string {
+	if file == "" {
+		return nil
+	}
+	// Restore portable file paths
+	if goRoot != "" && strings.HasPrefix(file, "GOROOT") {
+		file = goRoot + strings.TrimPrefix(file, "GOROOT")
+	}
+	if goPath != "" && strings.HasPrefix(file, "GOPATH") {
+		file = goPath + strings.TrimPrefix(file, "GOPATH")
+	}
+	if sc.FS != nil {
+		if lines := sc.loadFS(file); lines != nil {
+			return lines
+		}
+	}
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return readLines(f)
+}
+
+// Load a source file from the custom file system, if available.
+func (sc SourceContext) loadFS(file string) []string {
+	segments := strings.Split(strings.Trim(filepath.ToSlash(file), "/"), "/")
+	for i := range segments {
+		name := path.Join(segments[i:]...)
+		if !fs.ValidPath(name) {
+			continue
+		}
+		f, err := sc.FS.Open(name)
+		if err != nil {
+			continue
+		}
+		lines := readLines(f)
+		_ = f.Close()
+		return lines
+	}
+	return nil
+}
+
+// Read all lines available on `r`; trailing whitespace is removed.
+func readLines(r io.Reader) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	return lines
+}
+
+type sourceCodec struct {
+	cc Codec
+	sc SourceContext
+}
+
+func (c *sourceCodec) Marshal(err error) ([]byte, error) {
+	return c.cc.Marshal(c.sc.Enrich(err))
+}
+
+func (c *sourceCodec) Unmarshal(src []byte) (bool, error) {
+	return c.cc.Unmarshal(src)
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestSourceContext(t *testing.T) {
+	assert := tdd.New(t)
+	err := Wrap(New("connection refused"), "dial")
+
+	t.Run("LocalFiles", func(t *testing.T) {
+		sc := SourceContext{Lines: 2}
+		rec := sc.Enrich(err)
+		assert.Equal(err.Error(), rec.Error(), "message")
+		frame := rec.(HasStack).StackTrace()[0]
+		assert.Len(frame.PreContext, 2, "pre context")
+		assert.Len(frame.PostContext, 2, "post context")
+		assert.Contains(frame.SourceLine, "connection refused")
+		assert.Contains(frame.PreContext[1], "assert := tdd.New(t)")
+
+		// Original error is not modified
+		assert.Empty(err.(HasStack).StackTrace()[0].PreContext)
+
+		// Codec
+		js, _ := Report(err, sc.Codec(CodecJSON(false)))
+		assert.True(json.Valid(js), "valid report")
+		assert.Contains(string(js), "pre_context")
+	})
+
+	t.Run("CustomFS", func(t *testing.T) {
+		frame := err.(HasStack).StackTrace()[0]
+		lines := make([]string, frame.LineNumber+1)
+		for i := range lines {
+			lines[i] = "// embedded"
+		}
+		fsys := fstest.MapFS{
+			"errors/source_test.go": &fstest.MapFile{Data: []byte(strings.Join(lines, "\n"))},
+		}
+		sc := SourceContext{Lines: 1, FS: fsys}
+		frame = sc.Enrich(err).(HasStack).StackTrace()[0]
+		assert.Equal([]string{"// embedded"}, frame.PreContext)
+		assert.Equal([]string{"// embedded"}, frame.PostContext)
+	})
+
+	t.Run("MissingSource", func(t *testing.T) {
+		sc := SourceContext{FS: fstest.MapFS{}}
+		rec := sc.Enrich(&Error{err: New("no frames"), frames: []StackFrame{{File: "/invalid/file.go", LineNumber: 10}}})
+		assert.Empty(rec.(HasStack).StackTrace()[0].PreContext)
+		assert.Nil(sc.Enrich(nil))
+	})
+}
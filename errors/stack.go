@@ -44,6 +44,14 @@ type StackFrame struct {
 	// if available.
 	SourceLine string `json:"source_line,omitempty"`
 
+	// Lines of source code before `SourceLine`, if available. Only set
+	// when using a 'SourceContext'.
+	PreContext []string `json:"pre_context,omitempty"`
+
+	// Lines of source code after `SourceLine`, if available. Only set
+	// when using a 'SourceContext'.
+	PostContext []string `json:"post_context,omitempty"`
+
 	// The underlying ProgramCounter.
 	ProgramCounter uintptr `json:"program_counter,omitempty"`
 }
@@ -163,3 +171,13 @@ func printFile(file string) string {
 	}
 	return file
 }
+
+// Remove paths specific to the local system from the provided frames.
+func portableTrace(frames []StackFrame) []StackFrame {
+	fr := make([]StackFrame, len(frames))
+	copy(fr, frames)
+	for i := range fr {
+		fr[i].File = printFile(fr[i].File)
+	}
+	return fr
+}
@@ -45,10 +45,22 @@ const (
 	ES384 Alg = "ES384"
 	// ES512 - ECDSA using P-521 and SHA-512.
 	ES512 Alg = "ES512"
+	// ES256K - ECDSA using secp256k1 and SHA-256 (RFC-8812).
+	ES256K Alg = "ES256K"
+	// EdDSA - Edwards-curve Digital Signature Algorithm (RFC-8037).
+	EdDSA Alg = "EdDSA"
 )
 
 // HashFunction returns the proper crypto function for the algorithm identifier.
+// EdDSA signs the original message directly, in that case a zero value is
+// returned to signal that no pre-hashing is required.
 func (a Alg) HashFunction() (crypto.Hash, error) {
+	switch a {
+	case ES256K:
+		return crypto.SHA256, nil
+	case EdDSA:
+		return crypto.Hash(0), nil
+	}
 	alg := string(a)
 	switch s := alg[len(alg)-3:]; s {
 	case "256":
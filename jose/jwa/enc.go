@@ -0,0 +1,45 @@
+package jwa
+
+import (
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// RSAOAEP256 - RSAES OAEP using SHA-256 and MGF1 with SHA-256.
+	RSAOAEP256 Alg = "RSA-OAEP-256"
+	// ECDHESA128KW - ECDH-ES using Concat KDF and CEK wrapped with "A128KW".
+	ECDHESA128KW Alg = "ECDH-ES+A128KW"
+	// ECDHESA256KW - ECDH-ES using Concat KDF and CEK wrapped with "A256KW".
+	ECDHESA256KW Alg = "ECDH-ES+A256KW"
+)
+
+// Enc values provide valid content encryption algorithm identifiers as
+// described by RFC-7518. The value is used to set the 'enc' header on
+// JWE instances.
+//
+// https://www.rfc-editor.org/rfc/rfc7518.html#section-5.1
+type Enc string
+
+const (
+	// A128GCM - AES GCM using 128-bit key.
+	A128GCM Enc = "A128GCM"
+	// A192GCM - AES GCM using 192-bit key.
+	A192GCM Enc = "A192GCM"
+	// A256GCM - AES GCM using 256-bit key.
+	A256GCM Enc = "A256GCM"
+)
+
+// KeySize returns the size (in bytes) of the content encryption key
+// required by the algorithm.
+func (e Enc) KeySize() (int, error) {
+	switch e {
+	case A128GCM:
+		return 16, nil
+	case A192GCM:
+		return 24, nil
+	case A256GCM:
+		return 32, nil
+	default:
+		return 0, errors.Errorf("invalid content encryption algorithm '%s'", e)
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Package jwe implements JSON Web Encryption as described in RFC-7516.
+
+JSON Web Encryption (JWE) represents encrypted content using JSON-based
+data structures. This package supports the general JSON serialization,
+allowing a single payload to be encrypted for multiple recipients.
+
+The content is encrypted once using a random content encryption key (CEK)
+and AES GCM. The CEK is then encrypted individually for each recipient key;
+"RSA-OAEP-256" is used for RSA keys and "ECDH-ES+A256KW" for EC keys.
+
+	msg, err := Encrypt(document, jwa.A256GCM, nil, serviceKey, controllerKey)
+	js, _ := json.Marshal(msg)
+
+	// Each recipient decrypts the content using its private key
+	msg, _ = Parse(js)
+	document, err := msg.Decrypt(controllerPrivateKey)
+
+Any existing recipient can share the message with new recipients without
+encrypting the content again.
+
+	err := msg.AddRecipient(controllerPrivateKey, auditorKey)
+
+More information:
+https://www.rfc-editor.org/rfc/rfc7516.html
+*/
+package jwe
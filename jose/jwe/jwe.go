@@ -0,0 +1,271 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// Base64 encoding used by all serialization formats.
+var b64 = base64.RawURLEncoding
+
+// Size of the initialization vector used for AES GCM.
+const ivSize = 12
+
+// Header parameters used by JWE instances.
+// https://www.rfc-editor.org/rfc/rfc7516.html#section-4.1
+type Header struct {
+	// Key management algorithm used to encrypt the content encryption key.
+	Algorithm string `json:"alg,omitempty"`
+
+	// Content encryption algorithm.
+	Encryption string `json:"enc,omitempty"`
+
+	// Identifier for the key used to encrypt the content encryption key.
+	KeyID string `json:"kid,omitempty"`
+
+	// Media type of the complete JWE.
+	Type string `json:"typ,omitempty"`
+
+	// Media type of the secured content (the plaintext).
+	ContentType string `json:"cty,omitempty"`
+
+	// Ephemeral public key used for ECDH-ES key agreement.
+	EphemeralPublicKey *jwk.Record `json:"epk,omitempty"`
+}
+
+// Recipient entry in a JWE JSON serialization.
+// https://www.rfc-editor.org/rfc/rfc7516.html#section-7.2.1
+type Recipient struct {
+	// Per-recipient unprotected header values.
+	Header *Header `json:"header,omitempty"`
+
+	// Encoded content encryption key, encrypted for the recipient.
+	EncryptedKey string `json:"encrypted_key,omitempty"`
+}
+
+// Message is a JWE using the general JSON serialization. The content is
+// encrypted once using a random content encryption key (CEK), which is in
+// turn encrypted individually for each recipient.
+// https://www.rfc-editor.org/rfc/rfc7516.html#section-7.2
+type Message struct {
+	// Encoded protected header, shared by all recipients.
+	Protected string `json:"protected"`
+
+	// Recipients able to decrypt the message.
+	Recipients []Recipient `json:"recipients"`
+
+	// Encoded additional authenticated data, optional.
+	AAD string `json:"aad,omitempty"`
+
+	// Encoded initialization vector.
+	IV string `json:"iv"`
+
+	// Encoded ciphertext.
+	Ciphertext string `json:"ciphertext"`
+
+	// Encoded authentication tag.
+	Tag string `json:"tag"`
+}
+
+// Encrypt the plaintext for all the provided recipients using the content
+// encryption algorithm `enc`. Recipient keys must be RSA or EC public keys;
+// "RSA-OAEP-256" and "ECDH-ES+A256KW" are used by default as key management
+// algorithms. Optional protected header values can be provided, the "enc"
+// value is always set.
+func Encrypt(plaintext []byte, enc jwa.Enc, header *Header, recipients ...jwk.Record) (*Message, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+
+	// Protected header
+	size, err := enc.KeySize()
+	if err != nil {
+		return nil, err
+	}
+	he := Header{}
+	if header != nil {
+		he = *header
+	}
+	he.Encryption = string(enc)
+	js, err := json.Marshal(he)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid header")
+	}
+	msg := &Message{Protected: b64.EncodeToString(js)}
+
+	// Encrypt the content encryption key for each recipient
+	cek := make([]byte, size)
+	defer wipe(cek)
+	if _, err = rand.Read(cek); err != nil {
+		return nil, err
+	}
+	for _, rec := range recipients {
+		if err = msg.addRecipient(rec, cek); err != nil {
+			return nil, errors.Wrapf(err, "recipient '%s'", rec.KeyID)
+		}
+	}
+
+	// Encrypt content
+	iv := make([]byte, ivSize)
+	if _, err = rand.Read(iv); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(cek)
+	if err != nil {
+		return nil, err
+	}
+	ct := aead.Seal(nil, iv, plaintext, msg.authData())
+	msg.IV = b64.EncodeToString(iv)
+	msg.Ciphertext = b64.EncodeToString(ct[:len(ct)-aead.Overhead()])
+	msg.Tag = b64.EncodeToString(ct[len(ct)-aead.Overhead():])
+	return msg, nil
+}
+
+// Parse a message using the general JSON serialization.
+func Parse(data []byte) (*Message, error) {
+	msg := new(Message)
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, errors.Wrap(err, "invalid JWE JSON serialization")
+	}
+	if len(msg.Recipients) == 0 {
+		return nil, errors.New("no recipients available")
+	}
+	if _, err := msg.Header(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Header returns the message's protected header.
+func (m *Message) Header() (*Header, error) {
+	js, err := b64.DecodeString(m.Protected)
+	if err != nil {
+		return nil, errors.New("invalid protected header")
+	}
+	he := new(Header)
+	if err = json.Unmarshal(js, he); err != nil {
+		return nil, errors.New("invalid protected header")
+	}
+	return he, nil
+}
+
+// Decrypt the message content using the provided private key. The recipient
+// entry is selected based on the key identifier; entries without a key
+// identifier are also tried.
+func (m *Message) Decrypt(key jwk.Record) ([]byte, error) {
+	cek, err := m.contentKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer wipe(cek)
+	iv, err := b64.DecodeString(m.IV)
+	if err != nil || len(iv) != ivSize {
+		return nil, errors.New("invalid 'iv' value")
+	}
+	ct, err := b64.DecodeString(m.Ciphertext)
+	if err != nil {
+		return nil, errors.New("invalid 'ciphertext' value")
+	}
+	tag, err := b64.DecodeString(m.Tag)
+	if err != nil {
+		return nil, errors.New("invalid 'tag' value")
+	}
+	aead, err := newAEAD(cek)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := aead.Open(nil, iv, append(ct, tag...), m.authData())
+	if err != nil {
+		return nil, errors.New("failed to decrypt content")
+	}
+	return pt, nil
+}
+
+// AddRecipient allows an existing recipient, holding `key`, to share the
+// message with a new recipient without encrypting the content again. Only
+// the content encryption key is encrypted for the new recipient.
+func (m *Message) AddRecipient(key jwk.Record, recipient jwk.Record) error {
+	for _, r := range m.Recipients {
+		if r.Header != nil && r.Header.KeyID != "" && r.Header.KeyID == recipient.KeyID {
+			return errors.Errorf("duplicated recipient '%s'", recipient.KeyID)
+		}
+	}
+	cek, err := m.contentKey(key)
+	if err != nil {
+		return err
+	}
+	defer wipe(cek)
+	return m.addRecipient(recipient, cek)
+}
+
+// Encrypt the content encryption key for a new recipient.
+func (m *Message) addRecipient(rec jwk.Record, cek []byte) error {
+	he, ek, err := wrapKey(rec, cek)
+	if err != nil {
+		return err
+	}
+	m.Recipients = append(m.Recipients, Recipient{
+		Header:       he,
+		EncryptedKey: b64.EncodeToString(ek),
+	})
+	return nil
+}
+
+// Recover the content encryption key using the provided private key.
+func (m *Message) contentKey(key jwk.Record) ([]byte, error) {
+	he, err := m.Header()
+	if err != nil {
+		return nil, err
+	}
+	size, err := jwa.Enc(he.Encryption).KeySize()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range m.Recipients {
+		if r.Header == nil || (r.Header.KeyID != "" && r.Header.KeyID != key.KeyID) {
+			continue
+		}
+		ek, err := b64.DecodeString(r.EncryptedKey)
+		if err != nil {
+			continue
+		}
+		cek, err := unwrapKey(key, r.Header, ek)
+		if err != nil || len(cek) != size {
+			continue
+		}
+		return cek, nil
+	}
+	return nil, errors.New("no recipient entry available for the key")
+}
+
+// Additional authenticated data used for content encryption.
+// https://www.rfc-editor.org/rfc/rfc7516.html#section-5.1
+func (m *Message) authData() []byte {
+	if m.AAD == "" {
+		return []byte(m.Protected)
+	}
+	return []byte(m.Protected + "." + m.AAD)
+}
+
+// AES GCM cipher for the content encryption key.
+func newAEAD(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Securely remove sensitive values from memory.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,106 @@
+package jwe
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+func TestKeyWrap(t *testing.T) {
+	assert := tdd.New(t)
+
+	// https://www.rfc-editor.org/rfc/rfc3394.html#section-4.1
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+	expected, _ := hex.DecodeString("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+	wrapped, err := KeyWrap(kek, key)
+	assert.Nil(err, "wrap")
+	assert.Equal(expected, wrapped, "invalid result")
+	res, err := KeyUnwrap(kek, wrapped)
+	assert.Nil(err, "unwrap")
+	assert.Equal(key, res, "invalid result")
+
+	// Tampered value
+	wrapped[3] ^= 0xff
+	_, err = KeyUnwrap(kek, wrapped)
+	assert.NotNil(err, "tampered value")
+}
+
+func TestEncrypt(t *testing.T) {
+	assert := tdd.New(t)
+	content := []byte("document shared with multiple recipients")
+
+	// Recipient keys
+	var keys []jwk.Record
+	for _, alg := range []jwa.Alg{jwa.RS256, jwa.ES256, jwa.ES384, jwa.ES512} {
+		kty := "EC"
+		if alg == jwa.RS256 {
+			kty = "RSA"
+		}
+		rec, err := jwk.Generate(kty, alg)
+		assert.Nil(err, "generate key")
+		keys = append(keys, rec)
+	}
+	public := func(rec jwk.Record) jwk.Record {
+		rec.D, rec.P, rec.Q, rec.DP, rec.DQ, rec.Qi = "", "", "", "", "", ""
+		return rec
+	}
+
+	for _, enc := range []jwa.Enc{jwa.A128GCM, jwa.A256GCM} {
+		t.Run(string(enc), func(t *testing.T) {
+			msg, err := Encrypt(content, enc, &Header{ContentType: "text/plain"},
+				public(keys[0]), public(keys[1]), public(keys[2]))
+			assert.Nil(err, "encrypt")
+			assert.Len(msg.Recipients, 3)
+			js, err := json.Marshal(msg)
+			assert.Nil(err, "encode")
+
+			// Each recipient can decrypt the content
+			msg, err = Parse(js)
+			assert.Nil(err, "parse")
+			he, _ := msg.Header()
+			assert.Equal(string(enc), he.Encryption)
+			assert.Equal("text/plain", he.ContentType)
+			for _, k := range keys[:3] {
+				pt, err := msg.Decrypt(k)
+				assert.Nil(err, "decrypt")
+				assert.Equal(content, pt, "invalid content")
+			}
+
+			// Not a recipient
+			_, err = msg.Decrypt(keys[3])
+			assert.NotNil(err, "invalid recipient")
+			_, err = msg.Decrypt(public(keys[0]))
+			assert.NotNil(err, "no private key")
+
+			// Share with a new recipient
+			assert.Nil(msg.AddRecipient(keys[1], public(keys[3])), "add recipient")
+			assert.NotNil(msg.AddRecipient(keys[1], public(keys[3])), "duplicated recipient")
+			assert.NotNil(msg.AddRecipient(jwk.Record{}, public(keys[3])), "invalid key")
+			pt, err := msg.Decrypt(keys[3])
+			assert.Nil(err, "decrypt")
+			assert.Equal(content, pt, "invalid content")
+
+			// Tampered content
+			msg.Protected = msg.Protected[:len(msg.Protected)-2]
+			_, err = msg.Decrypt(keys[0])
+			assert.NotNil(err, "tampered header")
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := Encrypt(content, jwa.A256GCM, nil)
+		assert.NotNil(err, "no recipients")
+		_, err = Encrypt(content, "A512GCM", nil, keys[0])
+		assert.NotNil(err, "invalid enc")
+		okp, _ := jwk.Generate("OKP", jwa.EdDSA)
+		_, err = Encrypt(content, jwa.A256GCM, nil, okp)
+		assert.NotNil(err, "unsupported key")
+		_, err = Parse([]byte(`{"recipients":[]}`))
+		assert.NotNil(err, "no recipients")
+	})
+}
@@ -0,0 +1,158 @@
+package jwe
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// Key management algorithm used for a recipient key. If the record doesn't
+// specify a supported algorithm, a default value is selected based on the
+// key type.
+func keyAlgorithm(rec jwk.Record) (jwa.Alg, error) {
+	switch alg := jwa.Alg(rec.Alg); alg {
+	case jwa.RSAOAEP256, jwa.ECDHESA128KW, jwa.ECDHESA256KW:
+		return alg, nil
+	}
+	switch rec.KeyType {
+	case "RSA":
+		return jwa.RSAOAEP256, nil
+	case "EC":
+		return jwa.ECDHESA256KW, nil
+	default:
+		return "", errors.Errorf("unsupported key type '%s'", rec.KeyType)
+	}
+}
+
+// Encrypt the content encryption key for the recipient. The returned header
+// includes the per-recipient parameters required to decrypt it.
+func wrapKey(rec jwk.Record, cek []byte) (*Header, []byte, error) {
+	alg, err := keyAlgorithm(rec)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := rec.PublicKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	he := &Header{Algorithm: string(alg), KeyID: rec.KeyID}
+	switch alg {
+	case jwa.RSAOAEP256:
+		rk, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, errors.New("invalid RSA key")
+		}
+		ek, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, rk, cek, nil)
+		return he, ek, err
+	default:
+		ek, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, nil, errors.New("invalid EC key")
+		}
+		remote, err := ek.ECDH()
+		if err != nil {
+			return nil, nil, err
+		}
+		eph, err := remote.Curve().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		z, err := eph.ECDH(remote)
+		if err != nil {
+			return nil, nil, err
+		}
+		he.EphemeralPublicKey = ephemeralRecord(rec.Crv, eph.PublicKey())
+		wrapped, err := KeyWrap(concatKDF(z, alg), cek)
+		return he, wrapped, err
+	}
+}
+
+// Decrypt the content encryption key using the recipient's private key.
+func unwrapKey(rec jwk.Record, he *Header, ek []byte) ([]byte, error) {
+	priv, err := rec.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	switch alg := jwa.Alg(he.Algorithm); alg {
+	case jwa.RSAOAEP256:
+		rk, ok := priv.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("invalid RSA key")
+		}
+		return rsa.DecryptOAEP(sha256.New(), nil, rk, ek, nil)
+	case jwa.ECDHESA128KW, jwa.ECDHESA256KW:
+		sk, ok := priv.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("invalid EC key")
+		}
+		local, err := sk.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		if he.EphemeralPublicKey == nil || he.EphemeralPublicKey.Crv != rec.Crv {
+			return nil, errors.New("invalid 'epk' header")
+		}
+		remote, err := he.EphemeralPublicKey.PublicKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid 'epk' header")
+		}
+		epk, ok := remote.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errors.New("invalid 'epk' header")
+		}
+		pub, err := epk.ECDH()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid 'epk' header")
+		}
+		z, err := local.ECDH(pub)
+		if err != nil {
+			return nil, err
+		}
+		return KeyUnwrap(concatKDF(z, alg), ek)
+	default:
+		return nil, errors.Errorf("unsupported 'alg' value '%s'", alg)
+	}
+}
+
+// Public JWK record for an ephemeral ECDH key.
+func ephemeralRecord(crv string, pub *ecdh.PublicKey) *jwk.Record {
+	// uncompressed point: 0x04 || X || Y
+	point := pub.Bytes()[1:]
+	size := len(point) / 2
+	return &jwk.Record{
+		KeyType: "EC",
+		Crv:     crv,
+		X:       b64.EncodeToString(point[:size]),
+		Y:       b64.EncodeToString(point[size:]),
+	}
+}
+
+// Derive the key encryption key from the ECDH shared secret using the Concat
+// KDF with SHA-256; no "apu" or "apv" values are used.
+// https://www.rfc-editor.org/rfc/rfc7518.html#section-4.6.2
+func concatKDF(z []byte, alg jwa.Alg) []byte {
+	size := 32
+	if alg == jwa.ECDHESA128KW {
+		size = 16
+	}
+	info := make([]byte, 0, 4+len(alg)+12)
+	info = binary.BigEndian.AppendUint32(info, uint32(len(alg)))
+	info = append(info, alg...)
+	info = binary.BigEndian.AppendUint32(info, 0)              // PartyUInfo
+	info = binary.BigEndian.AppendUint32(info, 0)              // PartyVInfo
+	info = binary.BigEndian.AppendUint32(info, uint32(size*8)) // SuppPubInfo
+
+	// A single round is enough for key sizes up to 256 bits
+	h := sha256.New()
+	_, _ = h.Write([]byte{0, 0, 0, 1})
+	_, _ = h.Write(z)
+	_, _ = h.Write(info)
+	return h.Sum(nil)[:size]
+}
@@ -0,0 +1,89 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Default initial value for the AES key wrap algorithm.
+// https://www.rfc-editor.org/rfc/rfc3394.html#section-2.2.3.1
+var kwDefaultIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// KeyWrap encrypts the provided content encryption key using the AES key
+// wrap algorithm, as described in RFC-3394.
+func KeyWrap(kek, cek []byte) ([]byte, error) {
+	if len(cek)%8 != 0 || len(cek) < 16 {
+		return nil, errors.New("key wrap: invalid key size")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(cek) / 8
+	r := make([][]byte, n)
+	for i := range r {
+		r[i] = make([]byte, 8)
+		copy(r[i], cek[i*8:])
+	}
+	a := make([]byte, 8)
+	copy(a, kwDefaultIV)
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 0; i < n; i++ {
+			copy(b, a)
+			copy(b[8:], r[i])
+			block.Encrypt(b, b)
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:8])^t)
+			copy(r[i], b[8:])
+		}
+	}
+	out := make([]byte, 0, (n+1)*8)
+	out = append(out, a...)
+	for i := range r {
+		out = append(out, r[i]...)
+	}
+	return out, nil
+}
+
+// KeyUnwrap decrypts a content encryption key previously wrapped with
+// 'KeyWrap'.
+func KeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, errors.New("key wrap: invalid wrapped key size")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(wrapped)/8 - 1
+	r := make([][]byte, n)
+	for i := range r {
+		r[i] = make([]byte, 8)
+		copy(r[i], wrapped[(i+1)*8:])
+	}
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(b, binary.BigEndian.Uint64(a)^t)
+			copy(b[8:], r[i])
+			block.Decrypt(b, b)
+			copy(a, b[:8])
+			copy(r[i], b[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(a, kwDefaultIV) != 1 {
+		return nil, errors.New("key wrap: integrity check failed")
+	}
+	out := make([]byte, 0, n*8)
+	for i := range r {
+		out = append(out, r[i]...)
+	}
+	return out, nil
+}
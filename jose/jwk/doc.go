@@ -8,6 +8,39 @@ JWKs.  Cryptographic algorithms and identifiers for use with this
 specification are described in the separate JSON Web Algorithms (JWA)
 specification and IANA registries established by that specification.
 
+# Key Generation
+
+New keys can be generated directly as complete JWK records, using the JWK
+thumbprint (RFC-7638) as key identifier. Records can be validated to ensure
+all required members are present and the key "use" and "key_ops" values are
+consistent.
+
+	rec, _ := Generate("OKP", jwa.EdDSA)
+	tp, _ := rec.Thumbprint()
+	err := rec.Validate()
+
+# PEM and DER Encodings
+
+Keys can be converted from and to the encodings commonly used for TLS material;
+PKIX public keys, PKCS#8 private keys and X.509 certificates. When parsing a
+certificate chain it is included in the record as the "x5c" value. Since these
+encodings don't include the intended algorithm, a default value is selected
+based on the key type.
+
+	rec, _ := ParsePEM(pemData)
+	rec, _ := FromCryptoKey(ecdsaPrivateKey, jwa.ES256)
+	data, _ := rec.MarshalPEM(true) // public key only
+
+# Remote Key Sets
+
+Identity providers usually publish their keys as a JWK set over HTTPS. A
+'RemoteKeySet' fetches and caches such set, refreshing it when the cached
+version expires or an unknown key identifier is requested. Remote sets can
+be used directly to validate tokens.
+
+	jwks, _ := NewRemoteKeySet("https://idp.example.com/.well-known/jwks.json")
+	validator, _ := jwt.NewValidator(jwt.WithValidationKeys(jwks))
+
 More information:
 https://www.rfc-editor.org/rfc/rfc7517.html
 */
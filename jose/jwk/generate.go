@@ -0,0 +1,125 @@
+package jwk
+
+import (
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// Valid "key_ops" values, by intended "use".
+// https://www.rfc-editor.org/rfc/rfc7517.html#section-4.3
+var keyOps = map[string]string{
+	"sign":       "sig",
+	"verify":     "sig",
+	"encrypt":    "enc",
+	"decrypt":    "enc",
+	"wrapKey":    "enc",
+	"unwrapKey":  "enc",
+	"deriveKey":  "enc",
+	"deriveBits": "enc",
+}
+
+// Generate a new random key of the provided type, intended to be used with
+// the 'alg' algorithm. The returned record is complete, including private
+// key information, and uses the JWK thumbprint as key identifier. Supported
+// key types are: "RSA", "EC", "OKP" and "oct".
+func Generate(kty string, alg jwa.Alg) (Record, error) {
+	if expected := keyType(alg); expected == "" || expected != kty {
+		return Record{}, errors.Errorf("invalid 'alg' value '%s' for key type '%s'", alg, kty)
+	}
+	k, err := New(alg)
+	if err != nil {
+		return Record{}, err
+	}
+	rec := k.Export(false)
+	if rec.KeyID, err = rec.Thumbprint(); err != nil {
+		return Record{}, err
+	}
+	return rec, rec.Validate()
+}
+
+// Validate the record is well-formed: the key type is consistent with the
+// algorithm, all required members are present and the intended key "use"
+// is consistent with the "key_ops" values.
+func (r Record) Validate() error {
+	// Key type and algorithm
+	kty := r.KeyType
+	if kty == "PSS" {
+		kty = "RSA" // backwards compatibility
+	}
+	if r.Alg != "" && keyType(jwa.Alg(r.Alg)) != kty {
+		return errors.Errorf("invalid 'alg' value '%s' for key type '%s'", r.Alg, r.KeyType)
+	}
+
+	// Required members
+	rec := r
+	rec.KeyType = kty
+	if _, err := rec.Thumbprint(); err != nil {
+		return err
+	}
+
+	// Intended use
+	if r.Use != "" && r.Use != "sig" && r.Use != "enc" {
+		return errors.Errorf("invalid 'use' value '%s'", r.Use)
+	}
+
+	// Key operations
+	seen := make(map[string]bool)
+	for _, op := range r.KeyOps {
+		use, ok := keyOps[op]
+		if !ok {
+			return errors.Errorf("invalid 'key_ops' value '%s'", op)
+		}
+		if seen[op] {
+			return errors.Errorf("duplicated 'key_ops' value '%s'", op)
+		}
+		seen[op] = true
+		if r.Use != "" && r.Use != use {
+			return errors.Errorf("'key_ops' value '%s' is inconsistent with 'use' value '%s'", op, r.Use)
+		}
+	}
+	if (seen["sign"] || seen["decrypt"] || seen["unwrapKey"]) && !r.hasPrivateKey() {
+		return errors.New("'key_ops' requires private key information")
+	}
+	return nil
+}
+
+// Validate all records in the set; key identifiers must be unique.
+func (s Set) Validate() error {
+	ids := make(map[string]bool)
+	for i, rec := range s.Keys {
+		if err := rec.Validate(); err != nil {
+			return errors.Wrapf(err, "key %d", i)
+		}
+		if rec.KeyID == "" {
+			continue
+		}
+		if ids[rec.KeyID] {
+			return errors.Errorf("duplicated key id: '%s'", rec.KeyID)
+		}
+		ids[rec.KeyID] = true
+	}
+	return nil
+}
+
+// Reports whether the record includes private key information.
+func (r Record) hasPrivateKey() bool {
+	return r.D != "" || r.K != ""
+}
+
+// Key type required for the provided algorithm.
+func keyType(alg jwa.Alg) string {
+	switch {
+	case alg == jwa.EdDSA:
+		return "OKP"
+	case strings.HasPrefix(string(alg), "HS"):
+		return "oct"
+	case strings.HasPrefix(string(alg), "RS"), strings.HasPrefix(string(alg), "PS"):
+		return "RSA"
+	case strings.HasPrefix(string(alg), "ES"):
+		return "EC"
+	default:
+		return ""
+	}
+}
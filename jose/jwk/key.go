@@ -45,6 +45,15 @@ func New(alg jwa.Alg) (Key, error) {
 			k.(*ecKey).alg = alg
 		}
 		return k, err
+	case "Ed":
+		if alg != jwa.EdDSA {
+			return nil, errors.Errorf("invalid 'alg' value '%s'", alg)
+		}
+		k, err := newOKP()
+		if err == nil {
+			k.(*okpKey).alg = alg
+		}
+		return k, err
 	default:
 		return nil, errors.Errorf("invalid 'alg' value '%s'", alg)
 	}
@@ -146,6 +146,9 @@ func (k *ecKey) UnmarshalBinary(data []byte) error {
 }
 
 func (k *ecKey) Export(safe bool) Record {
+	// Coordinates must be padded to the full size of the curve
+	// https://www.rfc-editor.org/rfc/rfc7518.html#section-6.2.1.2
+	size := (k.sk.Curve.Params().BitSize + 7) / 8
 	rec := Record{
 		KeyID:   k.ID(),
 		KeyType: "EC",
@@ -153,12 +156,12 @@ func (k *ecKey) Export(safe bool) Record {
 		Alg:     string(k.alg),
 		KeyOps:  []string{"verify"},
 		Crv:     k.sk.Curve.Params().Name,
-		X:       b64.EncodeToString(k.sk.PublicKey.X.Bytes()),
-		Y:       b64.EncodeToString(k.sk.PublicKey.Y.Bytes()),
+		X:       b64.EncodeToString(k.sk.PublicKey.X.FillBytes(make([]byte, size))),
+		Y:       b64.EncodeToString(k.sk.PublicKey.Y.FillBytes(make([]byte, size))),
 	}
 	if !safe {
 		rec.KeyOps = append(rec.KeyOps, "sign")
-		rec.D = b64.EncodeToString(k.sk.D.Bytes())
+		rec.D = b64.EncodeToString(k.sk.D.FillBytes(make([]byte, size)))
 	}
 	return rec
 }
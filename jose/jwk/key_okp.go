@@ -0,0 +1,151 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+
+	"go.bryk.io/pkg/errors"
+	cryptoutils "go.bryk.io/pkg/internal/crypto"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// OKP generates a new random Ed25519 cryptographic key.
+// https://www.rfc-editor.org/rfc/rfc8037.html
+func newOKP() (Key, error) {
+	_, sk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &okpKey{sk: sk, pk: sk.Public().(ed25519.PublicKey)}, nil // nolint: forcetypeassert
+}
+
+type okpKey struct {
+	sk  ed25519.PrivateKey
+	pk  ed25519.PublicKey
+	id  string
+	alg jwa.Alg
+}
+
+func (k *okpKey) ID() string {
+	if k.id != "" {
+		return k.id
+	}
+	k.id = cryptoutils.RandomID()
+	return k.id
+}
+
+func (k *okpKey) SetID(id string) {
+	k.id = id
+}
+
+func (k *okpKey) Alg() jwa.Alg {
+	return k.alg
+}
+
+func (k *okpKey) Sign(_ io.Reader, data []byte, _ crypto.SignerOpts) ([]byte, error) {
+	// No private key
+	if k.sk == nil {
+		return nil, errors.New("key is 'verify' only")
+	}
+
+	// EdDSA signs the original data directly, no pre-hashing is required
+	return ed25519.Sign(k.sk, data), nil
+}
+
+func (k *okpKey) Verify(_ crypto.Hash, data, signature []byte) bool {
+	if len(k.pk) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(k.pk, data, signature)
+}
+
+func (k *okpKey) Public() crypto.PublicKey {
+	return k.pk
+}
+
+func (k *okpKey) MarshalBinary() ([]byte, error) {
+	if k.sk == nil {
+		return nil, errors.New("key is 'verify' only")
+	}
+	kb, err := x509.MarshalPKCS8PrivateKey(k.sk)
+	if err != nil {
+		return nil, errors.New("failed to marshal generated key")
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: kb,
+	}), nil
+}
+
+func (k *okpKey) UnmarshalBinary(data []byte) error {
+	bl, _ := pem.Decode(data)
+	if bl == nil {
+		return errors.New("invalid PEM data")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(bl.Bytes)
+	if err != nil {
+		return err
+	}
+	sk, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return errors.New("invalid key type")
+	}
+	k.sk = sk
+	k.pk = sk.Public().(ed25519.PublicKey) // nolint: forcetypeassert
+	return nil
+}
+
+func (k *okpKey) Export(safe bool) Record {
+	rec := Record{
+		KeyID:   k.ID(),
+		KeyType: "OKP",
+		Use:     "sig",
+		Alg:     string(k.alg),
+		KeyOps:  []string{"verify"},
+		Crv:     "Ed25519",
+		X:       b64.EncodeToString(k.pk),
+	}
+	if !safe && k.sk != nil {
+		rec.KeyOps = append(rec.KeyOps, "sign")
+		rec.D = b64.EncodeToString(k.sk.Seed())
+	}
+	return rec
+}
+
+func (k *okpKey) Import(r Record) error {
+	// validate key type and curve identifier
+	if r.KeyType != "OKP" || r.Crv != "Ed25519" {
+		return errors.Errorf("invalid key type: '%s/%s'", r.KeyType, r.Crv)
+	}
+
+	// decode public key
+	x, err := b64.DecodeString(r.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return errors.New("invalid 'x' value")
+	}
+	k.id = r.KeyID
+	k.alg = jwa.Alg(r.Alg)
+	k.pk = x
+	k.sk = nil
+
+	// no private key available
+	if r.D == "" {
+		return nil
+	}
+
+	// decode private key
+	d, err := b64.DecodeString(r.D)
+	if err != nil || len(d) != ed25519.SeedSize {
+		return errors.New("invalid 'd' value")
+	}
+	sk := ed25519.NewKeyFromSeed(d)
+	if !sk.Public().(ed25519.PublicKey).Equal(k.pk) { // nolint: forcetypeassert
+		return errors.New("public and private key values don't match")
+	}
+	k.sk = sk
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/pem"
 	"io"
 	"math/big"
+	"strings"
 
 	"go.bryk.io/pkg/errors"
 	cryptoutils "go.bryk.io/pkg/internal/crypto"
@@ -112,13 +113,9 @@ func (k *rsaKey) UnmarshalBinary(data []byte) error {
 }
 
 func (k *rsaKey) Export(safe bool) Record {
-	kyt := "RSA"
-	if k.pss {
-		kyt = "PSS"
-	}
 	rec := Record{
 		KeyID:   k.ID(),
-		KeyType: kyt,
+		KeyType: "RSA",
 		Use:     "sig",
 		Alg:     string(k.alg),
 		KeyOps:  []string{"verify"},
@@ -154,7 +151,8 @@ func (k *rsaKey) Import(r Record) error {
 	}
 	k.alg = jwa.Alg(r.Alg)
 	k.id = r.KeyID
-	k.pss = r.KeyType == "PSS"
+	// "PSS" key type is supported for backwards compatibility
+	k.pss = r.KeyType == "PSS" || strings.HasPrefix(r.Alg, "PS")
 	k.key = key
 
 	// no private key available
@@ -1,11 +1,20 @@
 package jwk
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	tdd "github.com/stretchr/testify/assert"
 	"go.bryk.io/pkg/jose/jwa"
@@ -77,4 +86,205 @@ var standardMethods = []string{
 	string(jwa.PS256),
 	string(jwa.PS384),
 	string(jwa.PS512),
+	string(jwa.EdDSA),
+}
+
+func TestThumbprint(t *testing.T) {
+	assert := tdd.New(t)
+
+	// https://www.rfc-editor.org/rfc/rfc7638.html#section-3.1
+	rec := Record{
+		KeyType: "RSA",
+		E:       "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECP" +
+			"ebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY" +
+			"368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0f" +
+			"M4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+	tp, err := rec.Thumbprint()
+	assert.Nil(err, "thumbprint")
+	assert.Equal("NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", tp)
+
+	// Private members don't affect the thumbprint
+	k, _ := New(jwa.ES256)
+	tp1, _ := k.Export(true).Thumbprint()
+	tp2, _ := k.Export(false).Thumbprint()
+	assert.Equal(tp1, tp2)
+
+	_, err = Record{KeyType: "EC"}.Thumbprint()
+	assert.NotNil(err, "missing members")
+}
+
+func TestGenerate(t *testing.T) {
+	assert := tdd.New(t)
+	cases := map[string][]jwa.Alg{
+		"RSA": {jwa.RS256, jwa.PS256},
+		"EC":  {jwa.ES256, jwa.ES384, jwa.ES512},
+		"OKP": {jwa.EdDSA},
+		"oct": {jwa.HS256},
+	}
+	for kty, algs := range cases {
+		for _, alg := range algs {
+			rec, err := Generate(kty, alg)
+			assert.Nil(err, "generate %s/%s", kty, alg)
+			assert.Equal(kty, rec.KeyType)
+			tp, _ := rec.Thumbprint()
+			assert.Equal(tp, rec.KeyID, "key id")
+
+			// Generated records can be imported
+			k, err := Import(rec)
+			assert.Nil(err, "import")
+			assert.Equal(alg, k.Alg())
+		}
+	}
+
+	// Invalid combinations
+	_, err := Generate("EC", jwa.RS256)
+	assert.NotNil(err, "invalid key type")
+	_, err = Generate("OKP", jwa.ES256)
+	assert.NotNil(err, "invalid key type")
+}
+
+func TestValidate(t *testing.T) {
+	assert := tdd.New(t)
+	rec, _ := Generate("EC", jwa.ES256)
+	assert.Nil(rec.Validate())
+
+	// Public records can't include private operations
+	pub := rec
+	pub.D = ""
+	assert.NotNil(pub.Validate(), "private operation")
+
+	// Inconsistent use
+	inv := rec
+	inv.Use = "enc"
+	assert.NotNil(inv.Validate(), "inconsistent use")
+
+	// Invalid operations
+	inv = rec
+	inv.KeyOps = []string{"verify", "verify"}
+	assert.NotNil(inv.Validate(), "duplicated operation")
+	inv.KeyOps = []string{"launch"}
+	assert.NotNil(inv.Validate(), "invalid operation")
+
+	// Missing members
+	inv = rec
+	inv.Y = ""
+	assert.NotNil(inv.Validate(), "missing member")
+
+	// Duplicated key identifiers in a set
+	set := Set{Keys: []Record{rec, rec}}
+	assert.NotNil(set.Validate(), "duplicated key id")
+}
+
+func TestPEM(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Keys", func(t *testing.T) {
+		for _, alg := range []jwa.Alg{jwa.RS256, jwa.PS256, jwa.ES256, jwa.ES384, jwa.ES512, jwa.EdDSA} {
+			rec, err := Generate(keyType(alg), alg)
+			assert.Nil(err, "generate")
+
+			// Private key
+			data, err := rec.MarshalPEM(false)
+			assert.Nil(err, "marshal PEM")
+			assert.Contains(string(data), "PRIVATE KEY")
+			rec2, err := ParsePEM(data)
+			assert.Nil(err, "parse PEM")
+			assert.Equal(rec.KeyID, rec2.KeyID, "invalid key")
+			assert.Equal(rec.D, rec2.D, "invalid private key")
+			assert.Nil(rec2.Validate(), "invalid record")
+
+			// Public key
+			der, err := rec.MarshalDER(true)
+			assert.Nil(err, "marshal DER")
+			rec3, err := ParseDER(der)
+			assert.Nil(err, "parse DER")
+			assert.Equal(rec.KeyID, rec3.KeyID, "invalid key")
+			assert.Empty(rec3.D, "private key included")
+
+			// Restored keys are functional
+			priv, err := rec2.PrivateKey()
+			assert.Nil(err, "private key")
+			rec4, err := FromCryptoKey(priv, alg)
+			assert.Nil(err, "from private key")
+			k, err := Import(rec4)
+			assert.Nil(err, "import")
+			hf, _ := alg.HashFunction()
+			sig, err := k.Sign(rand.Reader, []byte("message"), hf)
+			assert.Nil(err, "sign")
+			rec3.Alg = string(alg) // PEM/DER encodings don't include the algorithm
+			k2, _ := Import(rec3)
+			assert.True(k2.Verify(hf, []byte("message"), sig), "verify")
+		}
+	})
+
+	t.Run("Legacy", func(t *testing.T) {
+		rk, _ := rsa.GenerateKey(rand.Reader, 2048)
+		data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rk)})
+		rec, err := ParsePEM(data)
+		assert.Nil(err, "PKCS#1")
+		assert.Equal(string(jwa.RS256), rec.Alg)
+
+		ek, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		der, _ := x509.MarshalECPrivateKey(ek)
+		rec, err = ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+		assert.Nil(err, "SEC 1")
+		assert.Equal(string(jwa.ES384), rec.Alg)
+	})
+
+	t.Run("Certificates", func(t *testing.T) {
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		tpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, pub, priv)
+		assert.Nil(err, "create certificate")
+
+		// Certificate only
+		rec, err := ParseDER(der)
+		assert.Nil(err, "parse certificate")
+		assert.Len(rec.CertificateChain, 1)
+		assert.NotEmpty(rec.CertificateThumbprintSHA2)
+		chain, err := rec.Certificates()
+		assert.Nil(err, "certificates")
+		assert.Equal(der, chain[0].Raw)
+
+		// Private key with certificate chain
+		pk, _ := x509.MarshalPKCS8PrivateKey(priv)
+		data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pk})
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		rec2, err := ParsePEM(data)
+		assert.Nil(err, "parse PEM")
+		assert.Equal(rec.KeyID, rec2.KeyID)
+		assert.Equal(rec.CertificateChain, rec2.CertificateChain)
+		out, err := rec2.MarshalPEM(false)
+		assert.Nil(err, "marshal PEM")
+		assert.Equal(data, out)
+
+		// Mismatched certificate
+		_, other, _ := ed25519.GenerateKey(rand.Reader)
+		pk, _ = x509.MarshalPKCS8PrivateKey(other)
+		data = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pk})
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		_, err = ParsePEM(data)
+		assert.NotNil(err, "mismatched certificate")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParseDER([]byte("invalid"))
+		assert.NotNil(err, "invalid DER")
+		_, err = ParsePEM([]byte("invalid"))
+		assert.NotNil(err, "invalid PEM")
+		_, err = FromCryptoKey("invalid", "")
+		assert.NotNil(err, "invalid key")
+		ek, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		_, err = FromCryptoKey(ek, jwa.ES384)
+		assert.NotNil(err, "invalid curve")
+		_, err = FromCryptoKey(ek, jwa.RS256)
+		assert.NotNil(err, "invalid alg")
+	})
 }
@@ -0,0 +1,286 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1" // nolint: gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// FromCryptoKey returns the JWK record for a standard public or private key.
+// Supported values are RSA, ECDSA and Ed25519 keys, either as values or
+// pointers. If no `alg` is provided, a default value is selected based on
+// the key type. The JWK thumbprint is used as key identifier.
+func FromCryptoKey(key interface{}, alg jwa.Alg) (Record, error) {
+	var (
+		k       Key
+		private = true
+	)
+	switch v := key.(type) {
+	case *rsa.PrivateKey:
+		v.Precompute()
+		k = &rsaKey{key: v}
+	case *rsa.PublicKey:
+		k, private = &rsaKey{key: &rsa.PrivateKey{PublicKey: *v}}, false
+	case *ecdsa.PrivateKey:
+		k = &ecKey{sk: v}
+	case *ecdsa.PublicKey:
+		k, private = &ecKey{sk: &ecdsa.PrivateKey{PublicKey: *v}}, false
+	case ed25519.PrivateKey:
+		k = &okpKey{sk: v, pk: v.Public().(ed25519.PublicKey)} // nolint: forcetypeassert
+	case *ed25519.PrivateKey:
+		k = &okpKey{sk: *v, pk: v.Public().(ed25519.PublicKey)} // nolint: forcetypeassert
+	case ed25519.PublicKey:
+		k, private = &okpKey{pk: v}, false
+	case *ed25519.PublicKey:
+		k, private = &okpKey{pk: *v}, false
+	default:
+		return Record{}, errors.Errorf("unsupported key type: %T", key)
+	}
+
+	// Export record, private key information is included when available
+	rec := k.Export(!private)
+	if alg == "" {
+		alg = defaultAlg(rec)
+	}
+	if alg == "" || keyType(alg) != rec.KeyType {
+		return Record{}, errors.Errorf("invalid 'alg' value '%s' for key type '%s'", alg, rec.KeyType)
+	}
+	if rec.KeyType == "EC" && defaultAlg(rec) != alg {
+		return Record{}, errors.Errorf("invalid 'alg' value '%s' for curve '%s'", alg, rec.Crv)
+	}
+	rec.Alg = string(alg)
+	tp, err := rec.Thumbprint()
+	if err != nil {
+		return Record{}, err
+	}
+	rec.KeyID = tp
+	return rec, nil
+}
+
+// FromCertificates returns the JWK record for the public key in a X.509
+// certificate chain. The first certificate must contain the key and each
+// subsequent certificate should certify the previous one. The chain and the
+// leaf certificate thumbprints are included in the record.
+func FromCertificates(chain []*x509.Certificate) (Record, error) {
+	if len(chain) == 0 {
+		return Record{}, errors.New("empty certificate chain")
+	}
+	rec, err := FromCryptoKey(chain[0].PublicKey, "")
+	if err != nil {
+		return Record{}, err
+	}
+	setChain(&rec, chain)
+	return rec, nil
+}
+
+// ParseDER returns the JWK record for a DER-encoded key. Supported values
+// are PKCS#8 private keys, PKIX public keys and X.509 certificates.
+func ParseDER(der []byte) (Record, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return FromCryptoKey(key, "")
+	}
+	if key, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return FromCryptoKey(key, "")
+	}
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		return FromCertificates([]*x509.Certificate{cert})
+	}
+	return Record{}, errors.New("unsupported DER content")
+}
+
+// ParsePEM returns the JWK record for the PEM-encoded key material provided.
+// Supported block types are: "PRIVATE KEY" (PKCS#8), "EC PRIVATE KEY" (SEC 1),
+// "RSA PRIVATE KEY" (PKCS#1), "PUBLIC KEY" (PKIX), "RSA PUBLIC KEY" (PKCS#1)
+// and "CERTIFICATE". When a private key and a certificate chain are both
+// provided, the chain must correspond to the private key.
+func ParsePEM(data []byte) (Record, error) {
+	var (
+		key   interface{}
+		chain []*x509.Certificate
+		block *pem.Block
+		err   error
+	)
+	for {
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, cErr := x509.ParseCertificate(block.Bytes)
+			if cErr != nil {
+				return Record{}, errors.Wrap(cErr, "invalid certificate")
+			}
+			chain = append(chain, cert)
+			continue
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return Record{}, errors.Wrapf(err, "invalid '%s' block", block.Type)
+		}
+	}
+
+	// Only certificates provided
+	if key == nil {
+		return FromCertificates(chain)
+	}
+
+	// Key with an optional certificate chain
+	rec, err := FromCryptoKey(key, "")
+	if err != nil || len(chain) == 0 {
+		return rec, err
+	}
+	pub, err := FromCryptoKey(chain[0].PublicKey, "")
+	if err != nil {
+		return Record{}, err
+	}
+	if pub.KeyID != rec.KeyID {
+		return Record{}, errors.New("certificate doesn't match the private key")
+	}
+	setChain(&rec, chain)
+	return rec, nil
+}
+
+// PublicKey returns the standard public key for the record. The returned
+// value will be of type *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func (r Record) PublicKey() (crypto.PublicKey, error) {
+	k, err := r.cryptoKey()
+	if err != nil {
+		return nil, err
+	}
+	switch v := k.(type) {
+	case *rsaKey:
+		return &v.key.PublicKey, nil
+	case *ecKey:
+		return &v.sk.PublicKey, nil
+	case *okpKey:
+		return v.pk, nil
+	default:
+		return nil, errors.Errorf("unsupported key type '%s'", r.KeyType)
+	}
+}
+
+// PrivateKey returns the standard private key for the record. The returned
+// value will be of type *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey.
+func (r Record) PrivateKey() (crypto.PrivateKey, error) {
+	if !r.hasPrivateKey() {
+		return nil, errors.New("no private key available")
+	}
+	k, err := r.cryptoKey()
+	if err != nil {
+		return nil, err
+	}
+	switch v := k.(type) {
+	case *rsaKey:
+		return v.key, nil
+	case *ecKey:
+		return v.sk, nil
+	case *okpKey:
+		return v.sk, nil
+	default:
+		return nil, errors.Errorf("unsupported key type '%s'", r.KeyType)
+	}
+}
+
+// Certificates returns the X.509 certificate chain included in the record,
+// if any.
+func (r Record) Certificates() ([]*x509.Certificate, error) {
+	chain := make([]*x509.Certificate, len(r.CertificateChain))
+	for i, c := range r.CertificateChain {
+		// "x5c" values use standard base64 encoding (not base64url)
+		der, err := base64.StdEncoding.DecodeString(c)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid 'x5c' value")
+		}
+		if chain[i], err = x509.ParseCertificate(der); err != nil {
+			return nil, errors.Wrap(err, "invalid 'x5c' value")
+		}
+	}
+	return chain, nil
+}
+
+// MarshalDER returns the DER encoding of the key in the record. Private keys
+// are encoded using PKCS#8 and public keys using PKIX. When `public` is true
+// only the public key is encoded.
+func (r Record) MarshalDER(public bool) ([]byte, error) {
+	if public || !r.hasPrivateKey() {
+		pub, err := r.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKIXPublicKey(pub)
+	}
+	priv, err := r.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return x509.MarshalPKCS8PrivateKey(priv)
+}
+
+// MarshalPEM returns the PEM encoding of the key in the record, using a
+// "PRIVATE KEY" (PKCS#8) or "PUBLIC KEY" (PKIX) block. When `public` is true
+// only the public key is encoded. If the record includes a certificate chain
+// it is appended as "CERTIFICATE" blocks.
+func (r Record) MarshalPEM(public bool) ([]byte, error) {
+	der, err := r.MarshalDER(public)
+	if err != nil {
+		return nil, err
+	}
+	block := &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	if !public && r.hasPrivateKey() {
+		block.Type = "PRIVATE KEY"
+	}
+	buf := bytes.NewBuffer(pem.EncodeToMemory(block))
+	chain, err := r.Certificates()
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range chain {
+		buf.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	return buf.Bytes(), nil
+}
+
+// Restore the key instance for the record; "alg" is inferred if not set.
+func (r Record) cryptoKey() (Key, error) {
+	if r.KeyType == "oct" {
+		return nil, errors.New("symmetric keys are not supported")
+	}
+	if r.Alg == "" {
+		r.Alg = string(defaultAlg(r))
+	}
+	return Import(r)
+}
+
+// Attach a certificate chain to the record.
+func setChain(rec *Record, chain []*x509.Certificate) {
+	rec.CertificateChain = make([]string, len(chain))
+	for i, cert := range chain {
+		rec.CertificateChain[i] = base64.StdEncoding.EncodeToString(cert.Raw)
+	}
+	s1 := sha1.Sum(chain[0].Raw) // nolint: gosec
+	s2 := sha256.Sum256(chain[0].Raw)
+	rec.CertificateThumbprintSHA1 = b64.EncodeToString(s1[:])
+	rec.CertificateThumbprintSHA2 = b64.EncodeToString(s2[:])
+}
@@ -0,0 +1,214 @@
+package jwk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+// Maximum size allowed for a remote JWK set document.
+const maxRemoteSetSize = 1 << 20
+
+// RemoteKeySet fetches and caches a JWK set published by an external
+// party, for example an identity provider. The set is refreshed when the
+// cached version expires or when an unknown key identifier is requested,
+// allowing to seamlessly handle key rotations. Remote key sets are safe
+// for concurrent use.
+type RemoteKeySet struct {
+	url        string
+	client     *http.Client
+	ttl        time.Duration
+	minRefresh time.Duration
+	keys       []Key
+	fetched    time.Time
+	attempted  time.Time
+	mu         sync.Mutex
+}
+
+// RemoteOption elements provide a functional-style configuration mechanism
+// for remote key sets.
+type RemoteOption func(rs *RemoteKeySet) error
+
+// WithTTL adjust the maximum time a fetched set is cached before being
+// refreshed. Default value is 1 hour.
+func WithTTL(ttl time.Duration) RemoteOption {
+	return func(rs *RemoteKeySet) error {
+		if ttl <= 0 {
+			return errors.New("invalid TTL value")
+		}
+		rs.ttl = ttl
+		return nil
+	}
+}
+
+// WithRefreshInterval adjust the minimum time between refresh attempts
+// triggered by unknown key identifiers. This prevents a flood of requests
+// to the remote server when validating invalid tokens. Default value is
+// 1 minute.
+func WithRefreshInterval(interval time.Duration) RemoteOption {
+	return func(rs *RemoteKeySet) error {
+		rs.minRefresh = interval
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to fetch the remote set.
+func WithHTTPClient(client *http.Client) RemoteOption {
+	return func(rs *RemoteKeySet) error {
+		rs.client = client
+		return nil
+	}
+}
+
+// NewRemoteKeySet returns a new remote key set instance for the provided
+// URL. Only HTTPS locations are supported. The set is fetched lazily, when
+// a key is first requested.
+func NewRemoteKeySet(location string, opts ...RemoteOption) (*RemoteKeySet, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid URL")
+	}
+	if u.Scheme != "https" {
+		return nil, errors.New("only HTTPS locations are supported")
+	}
+	rs := &RemoteKeySet{
+		url:        u.String(),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		ttl:        time.Hour,
+		minRefresh: time.Minute,
+	}
+	for _, opt := range opts {
+		if err = opt(rs); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// Resolve returns the key with the provided identifier. The remote set is
+// refreshed if the cached version expired or if the key identifier is not
+// known.
+func (rs *RemoteKeySet) Resolve(kid string) (Key, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	// Refresh expired set; if the refresh fails the cached keys are
+	// still used until a new version is available
+	if time.Since(rs.fetched) > rs.ttl {
+		if err := rs.refresh(context.Background()); err != nil && len(rs.keys) == 0 {
+			return nil, err
+		}
+	}
+	if k := rs.get(kid); k != nil {
+		return k, nil
+	}
+
+	// Unknown key, the remote set might have been rotated
+	if time.Since(rs.attempted) > rs.minRefresh {
+		if err := rs.refresh(context.Background()); err != nil {
+			return nil, err
+		}
+		if k := rs.get(kid); k != nil {
+			return k, nil
+		}
+	}
+	return nil, errors.Errorf("unknown key identifier '%s'", kid)
+}
+
+// Refresh forces fetching the latest version of the remote set.
+func (rs *RemoteKeySet) Refresh(ctx context.Context) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.refresh(ctx)
+}
+
+// Set returns the cached version of the remote set.
+func (rs *RemoteKeySet) Set() Set {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	set := Set{Keys: make([]Record, len(rs.keys))}
+	for i, k := range rs.keys {
+		set.Keys[i] = k.Export(true)
+	}
+	return set
+}
+
+// Fetch and decode the remote set. Must be called with the lock held.
+func (rs *RemoteKeySet) refresh(ctx context.Context) error {
+	rs.attempted = time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rs.url, nil)
+	if err != nil {
+		return errors.Wrap(err, "invalid request")
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := rs.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch key set")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to fetch key set: %s", res.Status)
+	}
+	set := Set{}
+	if err = json.NewDecoder(io.LimitReader(res.Body, maxRemoteSetSize)).Decode(&set); err != nil {
+		return errors.Wrap(err, "invalid key set")
+	}
+
+	// Keys not intended for signatures, or using unsupported algorithms,
+	// are ignored
+	keys := make([]Key, 0, len(set.Keys))
+	for _, rec := range set.Keys {
+		if rec.Use != "" && rec.Use != "sig" {
+			continue
+		}
+		if rec.Alg == "" {
+			rec.Alg = string(defaultAlg(rec))
+		}
+		if k, err := Import(rec); err == nil {
+			keys = append(keys, k)
+		}
+	}
+	rs.keys = keys
+	rs.fetched = time.Now()
+	return nil
+}
+
+// Return the cached key with the provided identifier, if any.
+func (rs *RemoteKeySet) get(kid string) Key {
+	for _, k := range rs.keys {
+		if k.ID() == kid {
+			return k
+		}
+	}
+	return nil
+}
+
+// The "alg" parameter is optional, when not provided use the default
+// algorithm based on the key type and curve.
+func defaultAlg(rec Record) jwa.Alg {
+	switch rec.KeyType {
+	case "RSA":
+		return jwa.RS256
+	case "OKP":
+		return jwa.EdDSA
+	case "EC":
+		switch rec.Crv {
+		case "P-256":
+			return jwa.ES256
+		case "P-384":
+			return jwa.ES384
+		case "P-521":
+			return jwa.ES512
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package jwk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+)
+
+func TestRemoteKeySet(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Remote server publishing a key set
+	var (
+		hits int32
+		mu   sync.Mutex
+		set  = Set{}
+	)
+	publish := func(keys ...Key) {
+		mu.Lock()
+		defer mu.Unlock()
+		set.Keys = nil
+		for _, k := range keys {
+			set.Keys = append(set.Keys, k.Export(true))
+		}
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	k1, _ := New(jwa.ES256)
+	k1.SetID("key-1")
+	k2, _ := New(jwa.EdDSA)
+	k2.SetID("key-2")
+	publish(k1)
+
+	// Only HTTPS locations are supported
+	_, err := NewRemoteKeySet("http://example.com/jwks.json")
+	assert.NotNil(err, "insecure location")
+
+	rs, err := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()), WithRefreshInterval(0))
+	assert.Nil(err, "new remote set")
+
+	// Set is fetched lazily and cached
+	k, err := rs.Resolve("key-1")
+	assert.Nil(err, "resolve key")
+	assert.Equal(jwa.ES256, k.Alg())
+	_, _ = rs.Resolve("key-1")
+	assert.Equal(int32(1), atomic.LoadInt32(&hits))
+	assert.Len(rs.Set().Keys, 1)
+
+	// Unknown key triggers a refresh
+	publish(k1, k2)
+	k, err = rs.Resolve("key-2")
+	assert.Nil(err, "resolve rotated key")
+	assert.Equal(jwa.EdDSA, k.Alg())
+	assert.Equal(int32(2), atomic.LoadInt32(&hits))
+
+	// Unknown key after refresh
+	_, err = rs.Resolve("key-3")
+	assert.NotNil(err, "unknown key")
+
+	// Refresh attempts are rate-limited
+	rs2, _ := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()), WithRefreshInterval(time.Hour))
+	_, _ = rs2.Resolve("key-1")
+	before := atomic.LoadInt32(&hits)
+	_, err = rs2.Resolve("invalid")
+	assert.NotNil(err, "unknown key")
+	assert.Equal(before, atomic.LoadInt32(&hits))
+}
@@ -0,0 +1,23 @@
+package jwk
+
+import (
+	"go.bryk.io/pkg/errors"
+)
+
+// KeyResolver provides access to the cryptographic keys available to
+// validate signatures, based on the key identifier. Static key sets and
+// remote key sets both satisfy this interface.
+type KeyResolver interface {
+	// Resolve returns the key with the provided identifier.
+	Resolve(kid string) (Key, error)
+}
+
+// Resolve returns the key with the provided identifier from the set.
+func (s Set) Resolve(kid string) (Key, error) {
+	for _, rec := range s.Keys {
+		if rec.KeyID == kid {
+			return Import(rec)
+		}
+	}
+	return nil, errors.Errorf("unknown key identifier '%s'", kid)
+}
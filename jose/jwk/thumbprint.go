@@ -0,0 +1,40 @@
+package jwk
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Thumbprint returns the JWK thumbprint of the record, as described in
+// RFC-7638. The thumbprint is calculated using SHA-256 over the required
+// members of the public key and returned as a base64url-encoded string.
+// https://www.rfc-editor.org/rfc/rfc7638.html
+func (r Record) Thumbprint() (string, error) {
+	// Required members, encoding/json sorts map keys lexicographically
+	var members map[string]string
+	switch r.KeyType {
+	case "EC":
+		members = map[string]string{"crv": r.Crv, "kty": r.KeyType, "x": r.X, "y": r.Y}
+	case "OKP":
+		members = map[string]string{"crv": r.Crv, "kty": r.KeyType, "x": r.X}
+	case "RSA":
+		members = map[string]string{"e": r.E, "kty": r.KeyType, "n": r.N}
+	case "oct":
+		members = map[string]string{"k": r.K, "kty": r.KeyType}
+	default:
+		return "", errors.Errorf("unsupported key type '%s'", r.KeyType)
+	}
+	for k, v := range members {
+		if v == "" {
+			return "", errors.Errorf("missing required member '%s'", k)
+		}
+	}
+	js, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(js)
+	return b64.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,46 @@
+/*
+Package jws implements JSON Web Signature as described in RFC-7515.
+
+JSON Web Signature (JWS) represents content secured with digital signatures
+or Message Authentication Codes (MACs) using JSON-based data structures.
+Keys and algorithms are the same used by the "jwk" and "jwt" packages.
+
+# Compact Serialization
+
+The compact serialization is a URL-safe string including the protected
+header, the payload and the signature.
+
+	key, _ := jwk.New(jwa.ES256)
+	token, err := Sign([]byte("content to sign"), key, nil)
+
+	// Verify the signature and retrieve the payload
+	payload, err := Verify(token, key)
+
+# Detached Payloads
+
+When signing large artifacts it's usually not desirable to include the
+content in the signature. A detached signature omits the payload, which
+must be provided separately when verifying it.
+
+	sig, err := SignDetached(artifact, key, nil)
+	err = VerifyDetached(sig, artifact, key)
+
+# JSON Serialization
+
+The general JSON serialization allows the same payload to be signed by
+multiple parties; for example, to countersign an artifact. Detached
+payloads are also supported.
+
+	msg := NewMessage([]byte("contract"), false)
+	_ = msg.Sign(aliceKey, nil)
+	_ = msg.Sign(bobKey, nil)
+	js, _ := json.Marshal(msg)
+
+	// Verify all signatures in a received message
+	msg, _ = ParseMessage(js, nil)
+	err := msg.VerifyAll(keySet)
+
+More information:
+https://www.rfc-editor.org/rfc/rfc7515.html
+*/
+package jws
@@ -0,0 +1,166 @@
+package jws
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// Base64 encoding used by all serialization formats.
+var b64 = base64.RawURLEncoding
+
+// Header parameters used by JWS instances.
+// https://www.rfc-editor.org/rfc/rfc7515.html#section-4.1
+type Header struct {
+	// Cryptographic algorithm used to secure the JWS.
+	Algorithm string `json:"alg,omitempty"`
+
+	// Identifier for the key used to secure the JWS.
+	KeyID string `json:"kid,omitempty"`
+
+	// Media type of the complete JWS.
+	Type string `json:"typ,omitempty"`
+
+	// Media type of the secured content (the payload).
+	ContentType string `json:"cty,omitempty"`
+
+	// Public key corresponding to the key used to secure the JWS.
+	JWK *jwk.Record `json:"jwk,omitempty"`
+}
+
+// Sign the payload using the provided key and return the JWS compact
+// serialization. Optional header values can be provided, the "alg" and
+// "kid" values are always set based on the key used.
+// https://www.rfc-editor.org/rfc/rfc7515.html#section-7.1
+func Sign(payload []byte, key jwk.Key, header *Header) (string, error) {
+	protected, signature, err := sign(payload, key, header)
+	if err != nil {
+		return "", err
+	}
+	return protected + "." + b64.EncodeToString(payload) + "." + signature, nil
+}
+
+// SignDetached works like 'Sign' but the payload is not included in the
+// result; producing a compact serialization with an empty payload segment.
+// The payload must be provided separately to verify the signature. Useful
+// when signing large artifacts.
+// https://www.rfc-editor.org/rfc/rfc7515.html#appendix-F
+func SignDetached(payload []byte, key jwk.Key, header *Header) (string, error) {
+	protected, signature, err := sign(payload, key, header)
+	if err != nil {
+		return "", err
+	}
+	return protected + ".." + signature, nil
+}
+
+// Verify a JWS compact serialization using the provided key. On success
+// the payload is returned.
+func Verify(token string, key jwk.Key) ([]byte, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, errors.New("invalid JWS compact serialization")
+	}
+	if segments[1] == "" {
+		return nil, errors.New("detached payload, use 'VerifyDetached'")
+	}
+	payload, err := b64.DecodeString(segments[1])
+	if err != nil {
+		return nil, errors.New("invalid payload segment")
+	}
+	if _, err = verify(segments[0], segments[1], segments[2], key); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// VerifyDetached verifies a JWS compact serialization with a detached
+// payload using the provided key.
+func VerifyDetached(token string, payload []byte, key jwk.Key) error {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 || segments[1] != "" {
+		return errors.New("invalid JWS detached compact serialization")
+	}
+	_, err := verify(segments[0], b64.EncodeToString(payload), segments[2], key)
+	return err
+}
+
+// ParseHeader returns the protected header of a JWS compact serialization,
+// without verifying its signature. Useful to select the key required for
+// verification.
+func ParseHeader(token string) (*Header, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, errors.New("invalid JWS compact serialization")
+	}
+	return decodeHeader(segments[0])
+}
+
+// Produce the encoded protected header and signature for the payload.
+func sign(payload []byte, key jwk.Key, header *Header) (string, string, error) {
+	alg := key.Alg()
+	if alg == "" || alg == jwa.NONE {
+		return "", "", errors.New("invalid key algorithm")
+	}
+	he := Header{}
+	if header != nil {
+		he = *header
+	}
+	he.Algorithm = string(alg)
+	he.KeyID = key.ID()
+	js, err := json.Marshal(he)
+	if err != nil {
+		return "", "", errors.Wrap(err, "invalid header")
+	}
+	protected := b64.EncodeToString(js)
+	hf, err := alg.HashFunction()
+	if err != nil {
+		return "", "", err
+	}
+	input := protected + "." + b64.EncodeToString(payload)
+	sig, err := key.Sign(rand.Reader, []byte(input), hf)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to produce signature")
+	}
+	return protected, b64.EncodeToString(sig), nil
+}
+
+// Verify the signature over the encoded protected header and payload.
+func verify(protected, payload, signature string, key jwk.Key) (*Header, error) {
+	he, err := decodeHeader(protected)
+	if err != nil {
+		return nil, err
+	}
+	if he.Algorithm != string(key.Alg()) {
+		return nil, errors.New("invalid 'alg' header")
+	}
+	sig, err := b64.DecodeString(signature)
+	if err != nil {
+		return nil, errors.New("invalid signature segment")
+	}
+	hf, err := key.Alg().HashFunction()
+	if err != nil {
+		return nil, err
+	}
+	if !key.Verify(hf, []byte(protected+"."+payload), sig) {
+		return nil, errors.New("invalid signature")
+	}
+	return he, nil
+}
+
+// Decode an encoded protected header.
+func decodeHeader(protected string) (*Header, error) {
+	js, err := b64.DecodeString(protected)
+	if err != nil {
+		return nil, errors.New("invalid protected header")
+	}
+	he := new(Header)
+	if err = json.Unmarshal(js, he); err != nil {
+		return nil, errors.New("invalid protected header")
+	}
+	return he, nil
+}
@@ -0,0 +1,118 @@
+package jws
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+func TestCompact(t *testing.T) {
+	assert := tdd.New(t)
+	content := []byte("content to sign")
+	methods := []jwa.Alg{jwa.HS256, jwa.RS256, jwa.PS384, jwa.ES256, jwa.ES512, jwa.EdDSA}
+	for _, alg := range methods {
+		t.Run(string(alg), func(t *testing.T) {
+			key, err := jwk.New(alg)
+			assert.Nil(err, "new key")
+
+			// Attached payload
+			token, err := Sign(content, key, &Header{Type: "JOSE"})
+			assert.Nil(err, "sign")
+			payload, err := Verify(token, key)
+			assert.Nil(err, "verify")
+			assert.Equal(content, payload, "invalid payload")
+			he, err := ParseHeader(token)
+			assert.Nil(err, "parse header")
+			assert.Equal(string(alg), he.Algorithm, "invalid alg")
+			assert.Equal(key.ID(), he.KeyID, "invalid kid")
+			assert.Equal("JOSE", he.Type, "invalid typ")
+
+			// Detached payload
+			sig, err := SignDetached(content, key, nil)
+			assert.Nil(err, "sign detached")
+			assert.Contains(sig, "..", "payload not detached")
+			assert.Nil(VerifyDetached(sig, content, key), "verify detached")
+			assert.NotNil(VerifyDetached(sig, []byte("other content"), key), "invalid payload")
+			_, err = Verify(sig, key)
+			assert.NotNil(err, "detached payload")
+
+			// Tampered content
+			segments := strings.Split(token, ".")
+			segments[1] = b64.EncodeToString([]byte("tampered"))
+			_, err = Verify(strings.Join(segments, "."), key)
+			assert.NotNil(err, "tampered payload")
+		})
+	}
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		k1, _ := jwk.New(jwa.ES256)
+		k2, _ := jwk.New(jwa.ES384)
+		token, _ := Sign(content, k1, nil)
+		_, err := Verify(token, k2)
+		assert.NotNil(err, "invalid alg")
+		_, err = Verify("invalid.token", k1)
+		assert.NotNil(err, "invalid token")
+	})
+}
+
+func TestMessage(t *testing.T) {
+	assert := tdd.New(t)
+	content := []byte("contract to countersign")
+	k1, _ := jwk.New(jwa.ES256)
+	k2, _ := jwk.New(jwa.EdDSA)
+	k3, _ := jwk.New(jwa.RS256)
+	set := jwk.Set{Keys: []jwk.Record{k1.Export(true), k2.Export(true)}}
+
+	for _, detached := range []bool{false, true} {
+		msg := NewMessage(content, detached)
+		assert.Nil(msg.Sign(k1, nil), "first signature")
+		assert.Nil(msg.Sign(k2, &Header{ContentType: "text/plain"}), "second signature")
+		assert.NotNil(msg.Sign(k1, nil), "duplicate signature")
+
+		js, err := json.Marshal(msg)
+		assert.Nil(err, "marshal")
+
+		var input []byte
+		if detached {
+			input = content
+			assert.NotContains(string(js), "payload", "payload not detached")
+		}
+		msg2, err := ParseMessage(js, input)
+		assert.Nil(err, "parse")
+		assert.Equal(content, msg2.Content(), "invalid content")
+		assert.Nil(msg2.VerifyAll(set), "verify all")
+		assert.Nil(msg2.Verify(k2), "verify")
+		assert.NotNil(msg2.Verify(k3), "unknown signer")
+
+		signers, err := msg2.Signers()
+		assert.Nil(err, "signers")
+		assert.Len(signers, 2)
+		assert.Equal("text/plain", signers[1].ContentType)
+
+		// Partial key set
+		partial := jwk.Set{Keys: []jwk.Record{k1.Export(true)}}
+		assert.NotNil(msg2.VerifyAll(partial), "missing key")
+
+		// Tampered content
+		if detached {
+			msg3, _ := ParseMessage(js, []byte("tampered"))
+			assert.NotNil(msg3.VerifyAll(set), "tampered content")
+		}
+	}
+
+	t.Run("Compact", func(t *testing.T) {
+		msg := NewMessage(content, false)
+		assert.Nil(msg.Sign(k1, nil))
+		token, err := msg.Compact(k1.ID())
+		assert.Nil(err, "compact")
+		payload, err := Verify(token, k1)
+		assert.Nil(err, "verify compact")
+		assert.Equal(content, payload)
+		_, err = msg.Compact(k2.ID())
+		assert.NotNil(err, "unknown signer")
+	})
+}
@@ -0,0 +1,162 @@
+package jws
+
+import (
+	"encoding/json"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// Signature entry in a JWS JSON serialization.
+// https://www.rfc-editor.org/rfc/rfc7515.html#section-7.2.1
+type Signature struct {
+	// Encoded protected header.
+	Protected string `json:"protected"`
+
+	// Unprotected header values.
+	Header map[string]interface{} `json:"header,omitempty"`
+
+	// Encoded signature value.
+	Signature string `json:"signature"`
+}
+
+// Message is a JWS using the general JSON serialization; it allows a payload
+// to be signed by multiple parties, for example to countersign an artifact.
+// https://www.rfc-editor.org/rfc/rfc7515.html#section-7.2
+type Message struct {
+	// Encoded payload; empty for detached payloads.
+	Payload string `json:"payload,omitempty"`
+
+	// Signatures over the payload.
+	Signatures []Signature `json:"signatures"`
+
+	payload  []byte
+	detached bool
+}
+
+// NewMessage returns a new message instance for the provided payload. When
+// `detached` is true the payload is not included in the serialized message
+// and must be provided separately to verify its signatures.
+func NewMessage(payload []byte, detached bool) *Message {
+	m := &Message{
+		Signatures: []Signature{},
+		payload:    payload,
+		detached:   detached,
+	}
+	if !detached {
+		m.Payload = b64.EncodeToString(payload)
+	}
+	return m
+}
+
+// ParseMessage decodes a message using the general JSON serialization. If
+// the message has a detached payload, it must be provided.
+func ParseMessage(data []byte, detachedPayload []byte) (*Message, error) {
+	m := new(Message)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "invalid JWS JSON serialization")
+	}
+	if len(m.Signatures) == 0 {
+		return nil, errors.New("no signatures available")
+	}
+	if m.Payload == "" {
+		m.detached = true
+		m.payload = detachedPayload
+		return m, nil
+	}
+	payload, err := b64.DecodeString(m.Payload)
+	if err != nil {
+		return nil, errors.New("invalid payload")
+	}
+	m.payload = payload
+	return m, nil
+}
+
+// Content returns the message payload.
+func (m *Message) Content() []byte {
+	return m.payload
+}
+
+// Sign adds a new signature to the message using the provided key. Optional
+// protected header values can be provided, the "alg" and "kid" values are
+// always set based on the key used.
+func (m *Message) Sign(key jwk.Key, header *Header) error {
+	for _, s := range m.Signatures {
+		if he, err := decodeHeader(s.Protected); err == nil && he.KeyID == key.ID() {
+			return errors.Errorf("message already signed by key '%s'", key.ID())
+		}
+	}
+	protected, signature, err := sign(m.payload, key, header)
+	if err != nil {
+		return err
+	}
+	m.Signatures = append(m.Signatures, Signature{
+		Protected: protected,
+		Signature: signature,
+	})
+	return nil
+}
+
+// Signers returns the protected header of every signature in the message.
+func (m *Message) Signers() ([]*Header, error) {
+	list := make([]*Header, len(m.Signatures))
+	for i, s := range m.Signatures {
+		he, err := decodeHeader(s.Protected)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = he
+	}
+	return list, nil
+}
+
+// Verify the signature produced with the provided key. An error is returned
+// if the message doesn't include a valid signature by the key.
+func (m *Message) Verify(key jwk.Key) error {
+	encoded := b64.EncodeToString(m.payload)
+	for _, s := range m.Signatures {
+		he, err := decodeHeader(s.Protected)
+		if err != nil || he.KeyID != key.ID() {
+			continue
+		}
+		_, err = verify(s.Protected, encoded, s.Signature, key)
+		return err
+	}
+	return errors.Errorf("no signature available for key '%s'", key.ID())
+}
+
+// VerifyAll verifies every signature in the message, resolving the
+// required keys using the provided resolver.
+func (m *Message) VerifyAll(keys jwk.KeyResolver) error {
+	if len(m.Signatures) == 0 {
+		return errors.New("no signatures available")
+	}
+	encoded := b64.EncodeToString(m.payload)
+	for i, s := range m.Signatures {
+		he, err := decodeHeader(s.Protected)
+		if err != nil {
+			return errors.Wrapf(err, "signature %d", i)
+		}
+		key, err := keys.Resolve(he.KeyID)
+		if err != nil {
+			return errors.Wrapf(err, "signature %d", i)
+		}
+		if _, err = verify(s.Protected, encoded, s.Signature, key); err != nil {
+			return errors.Wrapf(err, "signature %d", i)
+		}
+	}
+	return nil
+}
+
+// Compact returns the JWS compact serialization for the signature produced
+// with the provided key identifier.
+func (m *Message) Compact(kid string) (string, error) {
+	for _, s := range m.Signatures {
+		he, err := decodeHeader(s.Protected)
+		if err != nil || he.KeyID != kid {
+			continue
+		}
+		return s.Protected + "." + m.Payload + "." + s.Signature, nil
+	}
+	return "", errors.Errorf("no signature available for key '%s'", kid)
+}
@@ -27,6 +27,8 @@ const (
 	ErrCtyValidation = "cty header is invalid"
 	// ErrAmrValidation is the error for an invalid "amr" claim.
 	ErrAmrValidation = "amr claim is invalid"
+	// ErrCnfValidation is the error for an invalid "cnf" claim.
+	ErrCnfValidation = "cnf claim is invalid"
 )
 
 // Check functions allow to execute verifications against a JWT instance.
@@ -158,3 +160,65 @@ func ContentTypeCheck(cty string) Check {
 		return nil
 	}
 }
+
+// ConfirmationCheck validates the "cnf" claim; the token must be bound to
+// the key with the provided JWK thumbprint.
+// https://www.rfc-editor.org/rfc/rfc9449.html#section-6.1
+func ConfirmationCheck(jkt string) Check {
+	return func(token *Token) error {
+		holder := struct {
+			Confirmation *Confirmation `json:"cnf"`
+		}{}
+		if err := token.Decode(&holder); err != nil {
+			return err
+		}
+		if holder.Confirmation == nil || holder.Confirmation.JKT != jkt {
+			return errors.New(ErrCnfValidation)
+		}
+		return nil
+	}
+}
+
+// ValidityCheck validates the "exp", "nbf" and "iat" claims against the
+// current time, tolerating a clock difference of up to `leeway`. The "exp"
+// claim is required. When used with a validator, its clock source is used
+// and its skew tolerance is added to `leeway`.
+func ValidityCheck(leeway time.Duration) Check {
+	return timeCheck(leeway, true)
+}
+
+// StandardChecks returns a common validation policy for access tokens;
+// verifying the issuer, the audience (when provided) and the token validity
+// period with the provided `leeway`. Additional checks can be appended to
+// the returned list as required.
+func StandardChecks(issuer string, audience []string, leeway time.Duration) []Check {
+	checks := []Check{IssuerCheck(issuer)}
+	if len(audience) > 0 {
+		checks = append(checks, AudienceCheck(audience))
+	}
+	return append(checks, ValidityCheck(leeway))
+}
+
+// Validate time-based claims using the token's clock source.
+func timeCheck(leeway time.Duration, requireExp bool) Check {
+	return func(token *Token) error {
+		pl, err := token.RegisteredClaims()
+		if err != nil {
+			return err
+		}
+		now := token.now()
+		tolerance := leeway + token.skew
+		if pl.ExpirationTime != 0 || requireExp {
+			if now.After(time.Unix(pl.ExpirationTime, 0).Add(tolerance)) {
+				return errors.New(ErrExpValidation)
+			}
+		}
+		if now.Before(time.Unix(pl.NotBefore, 0).Add(-tolerance)) {
+			return errors.New(ErrNbfValidation)
+		}
+		if now.Before(time.Unix(pl.IssuedAt, 0).Add(-tolerance)) {
+			return errors.New(ErrIatValidation)
+		}
+		return nil
+	}
+}
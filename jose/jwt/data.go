@@ -1,11 +1,16 @@
 package jwt
 
+import (
+	"go.bryk.io/pkg/jose/jwk"
+)
+
 // Header is based on the JWT specification from RFC-7519.
 //
 //	typ (type)         - media type
 //	alg (algorithm)    - cryptographic algorithm used to generate the token
 //	cty (content type) - used to convey structural information about the token
 //	kid (key id)       - identifier for the cryptographic key used to sign the token
+//	jwk (JSON web key) - public key used to sign the token
 type Header struct {
 	// Declare the media type of this complete JWT.
 	Type string `json:"typ,omitempty"`
@@ -18,6 +23,9 @@ type Header struct {
 
 	// An optional identifier for the cryptographic key used to generate the JWT.
 	KeyID string `json:"kid,omitempty"`
+
+	// Public key used to generate the JWT. Used, for example, by DPoP proofs.
+	JWK *jwk.Record `json:"jwk,omitempty"`
 }
 
 // RegisteredClaims for the JWT payload section according to the RFC-7519.
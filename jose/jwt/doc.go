@@ -33,6 +33,130 @@ contains a collision resistant namespace.
 Private claims are the custom claims created to share information between parties that
 agree on using them and are neither registered nor public claims.
 
+Public and private claims can be provided using user-defined structures; numeric, boolean
+and nested values are preserved when decoding the token.
+
+	type MyClaims struct {
+		RegisteredClaims
+		Admin bool  `json:"admin"`
+		Quota int64 `json:"quota"`
+	}
+
+	token, _ := gen.Issue("", params, MyClaims{Admin: true, Quota: 100})
+
+	claims := MyClaims{}
+	_ = token.Decode(&claims)
+
+# Signing Algorithms
+
+Tokens can be signed using HMAC (HS*), RSA (RS*, PS*), ECDSA (ES*) and EdDSA
+(Ed25519) keys. Elliptic curve keys produce considerably smaller tokens than
+RSA-based ones. Ed25519 keys from the "crypto/ed25519" package can be used
+directly to issue tokens.
+
+	kp, _ := ed25519.New()
+	gen, _ := NewGenerator("acme.com", WithEd25519Key(kp))
+
+Keys that never leave an HSM or cloud KMS can be used through the "crypto/signer"
+package. Signatures are produced by the device and only the public key is
+exported.
+
+	hsm, _ := pkcs11.New(module, pkcs11.WithPIN(pin))
+	s, _ := hsm.Signer("jwt-key")
+	gen, _ := NewGenerator("acme.com", WithSigner(s))
+
+# Key Rotation
+
+A generator can hold multiple keys; new tokens are signed with the active key
+when no specific key is requested. When rotating keys, the previously active
+key is retired but remains available to validate existing tokens for a grace
+period. Rotation can also be scheduled.
+
+	gen, _ := NewGenerator("acme.com", WithRotationPolicy(RotationPolicy{
+		Interval: 24 * time.Hour,
+		Grace:    2 * time.Hour,
+		NewKey:   func() (jwk.Key, error) { return jwk.New(jwa.EdDSA) },
+	}))
+	defer gen.Close()
+
+	// Publish the public keys for token consumers
+	http.Handle("/.well-known/jwks.json", gen.JWKSHandler(time.Hour))
+
+# Proof-of-Possession
+
+Access tokens can be bound to a client key using DPoP (RFC-9449). The client
+includes a proof, signed with its key, on every request; the server validates
+the proof and ensures the token is bound to the same key.
+
+	// Issue a token bound to the client key
+	jkt, _ := clientKey.Export(true).Thumbprint()
+	token, _ := gen.Issue("", &TokenParameters{Subject: "rick", Audience: aud, KeyBinding: jkt})
+
+	// Client produces a proof for each request
+	proof, _ := NewDPoPProof(clientKey, DPoPRequest{Method: "GET", URI: uri, AccessToken: token.String()})
+
+	// Server validates the proof and the token binding
+	jkt, err := verifier.Verify(proof, DPoPRequest{Method: r.Method, URI: uri, AccessToken: token})
+	err = validator.Validate(token, ConfirmationCheck(jkt))
+
+# Validation Policies
+
+Common validations can be applied using policy presets; the returned checks can
+be extended as required. Time-based checks tolerate a `leeway` to account for
+clock differences between the issuer and the validator. A clock source and skew
+tolerance can also be set directly on the validator.
+
+	validator, _ := NewValidator(
+		WithValidationKeys(jwks),
+		WithClockSkew(30*time.Second),
+	)
+	checks := StandardChecks("acme.com", []string{"https://api.acme.com"}, 0)
+	err := validator.Validate(token, append(checks, SubjectCheck("rick"))...)
+
+# Revocation
+
+Tokens remain valid until expired. To invalidate compromised tokens earlier, a
+revocation checker can be consulted when validating tokens. Tokens can be revoked
+individually or for a specific subject. In-memory and Redis-based implementations
+are available.
+
+	rl := NewRedisRevocationList(redisClient, "jwt:revoked")
+	validator, _ := NewValidator(WithValidationKeys(jwks), WithRevocationChecker(rl))
+
+	// Revoke a single token, or all tokens for a subject
+	_ = rl.RevokeToken(ctx, claims.JTI, time.Unix(claims.ExpirationTime, 0))
+	_ = rl.RevokeSubject(ctx, "rick", 720*time.Hour)
+
+# HTTP Middleware
+
+Bearer tokens included in HTTP requests can be validated using a middleware
+handler. For valid tokens, the token and its decoded claims are available to
+upstream handlers on the request context.
+
+	mw := Middleware(validator, MiddlewareOptions{
+		Checks: []Check{IssuerCheck("acme.com")},
+		Claims: func() interface{} { return new(MyClaims) },
+	})
+	srv := mw(handler)
+
+	// On the handler
+	claims, _ := ClaimsFromContext(r.Context())
+
+# Token Introspection
+
+Resource servers can delegate token validation to a single service exposing
+a token introspection endpoint, as described in RFC-7662. The response includes
+the token claims for active tokens; invalid, expired, revoked or unsigned tokens
+are reported as inactive. The endpoint must be protected; all requests are
+rejected unless an 'Authorize' hook is provided.
+
+	handler := IntrospectionHandler(validator, IntrospectionOptions{
+		Checks:     []Check{IssuerCheck("acme.com")},
+		Revocation: rl,
+		Authorize:  authorizeResourceServer,
+	})
+	http.Handle("/oauth/introspect", handler)
+
 More information:
 https://tools.ietf.org/html/rfc7519
 */
@@ -0,0 +1,325 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	cryptoutils "go.bryk.io/pkg/internal/crypto"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// Media type used for DPoP proofs.
+const dpopType = "dpop+jwt"
+
+// Confirmation claim used to bind a token to a specific key.
+// https://www.rfc-editor.org/rfc/rfc7800.html#section-3.1
+type Confirmation struct {
+	// JWK SHA-256 thumbprint of the key the token is bound to.
+	JKT string `json:"jkt,omitempty"`
+}
+
+// DPoPClaims are included in the payload of DPoP proofs.
+// https://www.rfc-editor.org/rfc/rfc9449.html#section-4.2
+type DPoPClaims struct {
+	// Unique identifier for the proof.
+	JTI string `json:"jti"`
+
+	// HTTP method of the request the proof is attached to.
+	Method string `json:"htm"`
+
+	// HTTP target URI of the request, without query and fragment parts.
+	URI string `json:"htu"`
+
+	// Creation time of the proof.
+	IssuedAt int64 `json:"iat"`
+
+	// Hash of the access token sent along the proof, if any.
+	AccessTokenHash string `json:"ath,omitempty"`
+
+	// Nonce value provided by the server, if any.
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// DPoPRequest describes the HTTP request a DPoP proof is produced for, or
+// validated against.
+type DPoPRequest struct {
+	// HTTP method.
+	Method string
+
+	// HTTP target URI.
+	URI string
+
+	// Access token sent along the proof. Optional.
+	AccessToken string
+
+	// Nonce value provided by the server. Optional.
+	Nonce string
+}
+
+// NewDPoPProof produces a DPoP proof for the provided request, signed with
+// the client's key. Only asymmetric keys can be used. The public key is
+// included in the proof header.
+// https://www.rfc-editor.org/rfc/rfc9449.html#section-4
+func NewDPoPProof(key jwk.Key, req DPoPRequest) (string, error) {
+	if !isAsymmetric(key.Alg()) {
+		return "", errors.Errorf("unsupported 'alg' value '%s'", key.Alg())
+	}
+	htu, err := normalizeURI(req.URI)
+	if err != nil {
+		return "", err
+	}
+	pub := key.Export(true)
+	claims := DPoPClaims{
+		JTI:      cryptoutils.RandomID(),
+		Method:   strings.ToUpper(req.Method),
+		URI:      htu,
+		IssuedAt: time.Now().Unix(),
+		Nonce:    req.Nonce,
+	}
+	if req.AccessToken != "" {
+		claims.AccessTokenHash = accessTokenHash(req.AccessToken)
+	}
+	token := &Token{
+		pl: claims,
+		he: Header{
+			Type:      dpopType,
+			Algorithm: string(key.Alg()),
+			JWK:       &pub,
+		},
+	}
+	if err = sign(token, key); err != nil {
+		return "", errors.Wrap(err, "failed to sign proof")
+	}
+	return token.String(), nil
+}
+
+// DPoPVerifier validates DPoP proofs received by a server. Verifiers are
+// safe for concurrent use.
+type DPoPVerifier struct {
+	window time.Duration
+	replay ReplayCache
+	algs   []jwa.Alg
+}
+
+// DPoPOption elements provide a functional-style configuration mechanism
+// for DPoP verifiers.
+type DPoPOption func(v *DPoPVerifier) error
+
+// WithProofWindow adjust the maximum age, and clock skew, accepted for the
+// "iat" claim of the proofs. Defaults to 1 minute.
+func WithProofWindow(window time.Duration) DPoPOption {
+	return func(v *DPoPVerifier) error {
+		if window <= 0 {
+			return errors.New("invalid window value")
+		}
+		v.window = window
+		return nil
+	}
+}
+
+// WithReplayCache sets the cache used to detect replayed proofs. Defaults
+// to an in-memory cache. A shared cache is required when running multiple
+// server instances.
+func WithReplayCache(rc ReplayCache) DPoPOption {
+	return func(v *DPoPVerifier) error {
+		v.replay = rc
+		return nil
+	}
+}
+
+// WithProofAlgorithms restricts the signature algorithms accepted for the
+// proofs. By default, all supported asymmetric algorithms are accepted.
+func WithProofAlgorithms(algs ...jwa.Alg) DPoPOption {
+	return func(v *DPoPVerifier) error {
+		for _, alg := range algs {
+			if !isAsymmetric(alg) {
+				return errors.Errorf("unsupported 'alg' value '%s'", alg)
+			}
+		}
+		v.algs = algs
+		return nil
+	}
+}
+
+// NewDPoPVerifier returns a new DPoP proof verifier instance.
+func NewDPoPVerifier(opts ...DPoPOption) (*DPoPVerifier, error) {
+	v := &DPoPVerifier{window: time.Minute}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	if v.replay == nil {
+		v.replay = NewMemoryReplayCache()
+	}
+	return v, nil
+}
+
+// Verify validates a DPoP proof for the provided request. On success, the
+// JWK thumbprint of the client key is returned; for access tokens bound to
+// a key it must match the confirmation ("cnf") claim, see 'ConfirmationCheck'.
+// https://www.rfc-editor.org/rfc/rfc9449.html#section-4.3
+func (v *DPoPVerifier) Verify(proof string, req DPoPRequest) (string, error) {
+	t, err := Parse(proof)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid proof")
+	}
+
+	// Header validations
+	he := t.Header()
+	alg := jwa.Alg(he.Algorithm)
+	if he.Type != dpopType {
+		return "", errors.New("invalid 'typ' header")
+	}
+	if !isAsymmetric(alg) || (len(v.algs) > 0 && !containsAlg(alg, v.algs)) {
+		return "", errors.New(ErrAlgValidation)
+	}
+	if he.JWK == nil {
+		return "", errors.New("missing 'jwk' header")
+	}
+	if he.JWK.D != "" || he.JWK.P != "" || he.JWK.K != "" {
+		return "", errors.New("'jwk' header must not include private key material")
+	}
+
+	// Signature
+	rec := *he.JWK
+	rec.Alg = string(alg)
+	key, err := jwk.Import(rec)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid 'jwk' header")
+	}
+	if err = verify(proof, key); err != nil {
+		return "", err
+	}
+
+	// Payload validations
+	claims := DPoPClaims{}
+	if err = t.Decode(&claims); err != nil {
+		return "", errors.Wrap(err, "invalid proof")
+	}
+	if claims.JTI == "" {
+		return "", errors.New(ErrJtiValidation)
+	}
+	if !strings.EqualFold(claims.Method, req.Method) {
+		return "", errors.New("'htm' claim is invalid")
+	}
+	htu, err := normalizeURI(req.URI)
+	if err != nil {
+		return "", err
+	}
+	if claims.URI != htu {
+		return "", errors.New("'htu' claim is invalid")
+	}
+	iat := time.Unix(claims.IssuedAt, 0)
+	if time.Since(iat) > v.window || time.Until(iat) > v.window {
+		return "", errors.New(ErrIatValidation)
+	}
+	if req.Nonce != "" && claims.Nonce != req.Nonce {
+		return "", errors.New("'nonce' claim is invalid")
+	}
+	if req.AccessToken != "" && claims.AccessTokenHash != accessTokenHash(req.AccessToken) {
+		return "", errors.New("'ath' claim is invalid")
+	}
+
+	// Replay protection
+	if v.replay.Seen(claims.JTI, iat.Add(v.window)) {
+		return "", errors.New("proof was already used")
+	}
+	return he.JWK.Thumbprint()
+}
+
+// NewDPoPNonce returns a random value that can be provided to clients as
+// a server nonce for DPoP proofs.
+func NewDPoPNonce() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return b64.EncodeToString(buf)
+}
+
+// ReplayCache keeps track of previously seen unique identifiers to detect
+// replay attacks.
+type ReplayCache interface {
+	// Seen registers the identifier until its expiration time, and
+	// reports whether it was already registered.
+	Seen(id string, exp time.Time) bool
+}
+
+// MemoryReplayCache is an in-memory replay cache implementation, suitable
+// for single-instance deployments.
+type MemoryReplayCache struct {
+	entries map[string]time.Time
+	mu      sync.Mutex
+}
+
+// NewMemoryReplayCache returns a new in-memory replay cache.
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{entries: make(map[string]time.Time)}
+}
+
+// Seen registers the identifier until its expiration time, and reports
+// whether it was already registered. Expired entries are removed.
+func (rc *MemoryReplayCache) Seen(id string, exp time.Time) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := time.Now()
+	for k, v := range rc.entries {
+		if now.After(v) {
+			delete(rc.entries, k)
+		}
+	}
+	if _, ok := rc.entries[id]; ok {
+		return true
+	}
+	rc.entries[id] = exp
+	return false
+}
+
+// Hash value for the "ath" claim.
+func accessTokenHash(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return b64.EncodeToString(digest[:])
+}
+
+// The "htu" claim excludes query and fragment parts.
+func normalizeURI(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", errors.New("invalid URI")
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
+
+// Only asymmetric algorithms can be used with DPoP proofs.
+func isAsymmetric(alg jwa.Alg) bool {
+	if alg == jwa.EdDSA {
+		return true
+	}
+	if len(alg) < 2 {
+		return false
+	}
+	switch alg[0:2] {
+	case "RS", "PS", "ES":
+		return true
+	default:
+		return false
+	}
+}
+
+// Helper method to look for a specific algorithm in the provided list.
+func containsAlg(alg jwa.Alg, list []jwa.Alg) bool {
+	for _, a := range list {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
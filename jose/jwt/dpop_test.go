@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+func TestDPoP(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Authorization server key and client key
+	sk, _ := jwk.New(jwa.ES256)
+	ck, _ := jwk.New(jwa.EdDSA)
+	jkt, err := ck.Export(true).Thumbprint()
+	assert.Nil(err, "thumbprint")
+
+	// Issue an access token bound to the client key
+	tg, _ := NewGenerator("acme.com", WithKey(sk))
+	params := &TokenParameters{
+		Subject:    "rick",
+		Audience:   []string{"https://api.acme.com"},
+		NotBefore:  "0ms",
+		KeyBinding: jkt,
+	}
+	at, err := tg.Issue("", params)
+	assert.Nil(err, "issue token")
+	assert.Nil(tg.Validate(at.String(), ConfirmationCheck(jkt)), "validate binding")
+	assert.NotNil(tg.Validate(at.String(), ConfirmationCheck("other")), "invalid binding")
+
+	// Client produces a proof for a request
+	req := DPoPRequest{
+		Method:      http.MethodGet,
+		URI:         "https://api.acme.com/resource?id=1",
+		AccessToken: at.String(),
+	}
+	proof, err := NewDPoPProof(ck, req)
+	assert.Nil(err, "new proof")
+	pt, _ := Parse(proof)
+	assert.Equal("dpop+jwt", pt.Header().Type)
+	assert.Empty(pt.Header().JWK.D, "private key in proof")
+
+	// Resource server validates the proof and the token binding
+	verifier, err := NewDPoPVerifier()
+	assert.Nil(err, "new verifier")
+	res, err := verifier.Verify(proof, req)
+	assert.Nil(err, "verify proof")
+	assert.Equal(jkt, res)
+	assert.Nil(tg.Validate(at.String(), ConfirmationCheck(res)), "validate binding")
+
+	// Replayed proof
+	_, err = verifier.Verify(proof, req)
+	assert.NotNil(err, "replayed proof")
+
+	// Proofs don't match the request
+	invalid := []DPoPRequest{
+		{Method: http.MethodPost, URI: req.URI, AccessToken: req.AccessToken},
+		{Method: req.Method, URI: "https://api.acme.com/other", AccessToken: req.AccessToken},
+		{Method: req.Method, URI: req.URI, AccessToken: "other-token"},
+		{Method: req.Method, URI: req.URI, AccessToken: req.AccessToken, Nonce: "server-nonce"},
+	}
+	for _, r := range invalid {
+		proof, _ = NewDPoPProof(ck, req)
+		_, err = verifier.Verify(proof, r)
+		assert.NotNil(err, "invalid request")
+	}
+
+	// Symmetric keys are not supported
+	hk, _ := jwk.New(jwa.HS256)
+	_, err = NewDPoPProof(hk, req)
+	assert.NotNil(err, "symmetric key")
+
+	// Expired proof
+	strict, _ := NewDPoPVerifier(WithProofWindow(time.Millisecond), WithProofAlgorithms(jwa.EdDSA))
+	proof, _ = NewDPoPProof(ck, req)
+	time.Sleep(1100 * time.Millisecond)
+	_, err = strict.Verify(proof, req)
+	assert.NotNil(err, "expired proof")
+}
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -13,10 +16,15 @@ import (
 // Generator instances can be used to generate new tokens and validate
 // the ones previously issued.
 type Generator struct {
-	name string
-	keys []jwk.Key
-	none bool
-	mu   sync.Mutex
+	name    string
+	keys    []jwk.Key
+	none    bool
+	active  string
+	retired map[string]time.Time
+	policy  *RotationPolicy
+	halt    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
 }
 
 // NewGenerator returns a new generator instance ready to be used.
@@ -25,15 +33,27 @@ type Generator struct {
 // all generated tokens.
 func NewGenerator(issuer string, opts ...GeneratorOption) (*Generator, error) {
 	g := &Generator{
-		name: issuer,
-		none: false,
-		keys: []jwk.Key{},
+		name:    issuer,
+		none:    false,
+		keys:    []jwk.Key{},
+		retired: make(map[string]time.Time),
 	}
 	for _, opt := range opts {
 		if err := opt(g); err != nil {
 			return nil, err
 		}
 	}
+	if g.policy != nil {
+		// Generate initial signing key if required
+		if g.active == "" {
+			if err := g.rotateWithPolicy(); err != nil {
+				return nil, err
+			}
+		}
+		g.halt = make(chan struct{})
+		g.wg.Add(1)
+		go g.rotate()
+	}
 	return g, nil
 }
 
@@ -61,8 +81,14 @@ func (g *Generator) IsSupported(alg jwa.Alg) bool {
 
 // Issue a new token signed using the selected key and based on the provided
 // configuration parameters. Pass "none" as the `keyID` to issue a jwa.NONE
-// token, if supported by the generator.
-func (g *Generator) Issue(keyID string, params *TokenParameters) (*Token, error) {
+// token, if supported by the generator. Pass an empty `keyID` to use the
+// active signing key. Retired keys can't be used to issue new tokens.
+//
+// Additional user-defined claims can be provided as any value that can be
+// encoded as a JSON object, for example a custom claims structure. These
+// are merged with the 'CustomClaims' set on the parameters; registered
+// claims always take precedence.
+func (g *Generator) Issue(keyID string, params *TokenParameters, claims ...interface{}) (*Token, error) {
 	// Verify "none" is supported if requested
 	if strings.ToLower(keyID) == "none" && !g.none {
 		return nil, errors.New("unsupported method")
@@ -76,7 +102,15 @@ func (g *Generator) Issue(keyID string, params *TokenParameters) (*Token, error)
 	// Verify key name
 	var key jwk.Key
 	if strings.ToLower(keyID) != "none" {
-		key = getKey(keyID, g.keys)
+		g.mu.Lock()
+		g.prune()
+		if keyID == "" {
+			keyID = g.active
+		}
+		if _, ok := g.retired[keyID]; !ok {
+			key = getKey(keyID, g.keys)
+		}
+		g.mu.Unlock()
 		if key == nil {
 			return nil, errors.Errorf("invalid key name '%s'", keyID)
 		}
@@ -98,12 +132,29 @@ func (g *Generator) Issue(keyID string, params *TokenParameters) (*Token, error)
 	}
 
 	// Handle custom data
+	merged := false
 	if params.CustomClaims != nil {
+		claims = append([]interface{}{params.CustomClaims}, claims...)
+	}
+	if len(claims) > 0 {
 		var err error
-		pl, err = merge(pl, params.CustomClaims)
+		pl, err = merge(append([]interface{}{pl}, claims...)...)
 		if err != nil {
 			return nil, err
 		}
+		merged = true
+	}
+
+	// Key binding confirmation
+	if params.KeyBinding != "" {
+		var err error
+		pl, err = merge(pl, map[string]interface{}{
+			"cnf": Confirmation{JKT: params.KeyBinding},
+		})
+		if err != nil {
+			return nil, err
+		}
+		merged = true
 	}
 
 	// Generate token and sign instance
@@ -115,6 +166,16 @@ func (g *Generator) Issue(keyID string, params *TokenParameters) (*Token, error)
 			ContentType: params.ContentType,
 		},
 	}
+	if merged {
+		// preserve the exact encoding of the merged claims
+		data, err := json.Marshal(pl)
+		if err != nil {
+			return nil, err
+		}
+		if err = token.setPayload(data); err != nil {
+			return nil, err
+		}
+	}
 	if key != nil {
 		token.he.KeyID = key.ID()
 		if err := sign(token, key); err != nil {
@@ -142,7 +203,10 @@ func (g *Generator) Validate(token string, checks ...Check) error {
 
 	// Verify signature for secure tokens
 	if t.Header().Algorithm != string(jwa.NONE) {
+		g.mu.Lock()
+		g.prune()
 		key := getKey(t.Header().KeyID, g.keys)
+		g.mu.Unlock()
 		if key == nil {
 			return errors.New("invalid key identifier")
 		}
@@ -156,19 +220,12 @@ func (g *Generator) Validate(token string, checks ...Check) error {
 }
 
 // AddKey will register a new cryptographic key with the token generator. If the
-// identifier is already used the method will return an error.
+// identifier is already used the method will return an error. The first key
+// registered is used as the active signing key by default.
 func (g *Generator) AddKey(keys ...jwk.Key) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	for _, k := range keys {
-		for _, ek := range g.keys {
-			if ek.ID() == k.ID() {
-				return errors.Errorf("duplicated key id: '%s'", k.ID())
-			}
-		}
-		g.keys = append(g.keys, k)
-	}
-	return nil
+	return g.addKey(keys...)
 }
 
 // RemoveKey can be used to decommission an existing cryptographic key from the
@@ -188,6 +245,82 @@ func (g *Generator) RemoveKey(id string) {
 		g.keys[len(g.keys)-1] = nil        // erase last element (write zero value).
 		g.keys = g.keys[:len(g.keys)-1]    // truncate slice.
 	}
+	delete(g.retired, id)
+	if g.active == id {
+		g.active = ""
+	}
+}
+
+// SetActiveKey selects the key used by default to sign new tokens.
+func (g *Generator) SetActiveKey(id string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if getKey(id, g.keys) == nil {
+		return errors.Errorf("invalid key name '%s'", id)
+	}
+	if _, ok := g.retired[id]; ok {
+		return errors.Errorf("key '%s' is retired", id)
+	}
+	g.active = id
+	return nil
+}
+
+// ActiveKey returns the identifier of the key used by default to sign new
+// tokens; or an empty string if no key is active.
+func (g *Generator) ActiveKey() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.active
+}
+
+// Rotate registers a new key and sets it as the active signing key. The
+// previously active key is retired; it can't be used to issue new tokens
+// but remains available to validate existing ones for the `grace` period,
+// after which is removed from the generator. The grace period should be at
+// least equal to the maximum lifetime of the tokens issued.
+func (g *Generator) Rotate(k jwk.Key, grace time.Duration) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rotateKey(k, grace)
+}
+
+// Close stops the automatic key rotation process, if any.
+func (g *Generator) Close() {
+	g.mu.Lock()
+	halt := g.halt
+	g.halt = nil
+	g.mu.Unlock()
+	if halt != nil {
+		close(halt)
+		g.wg.Wait()
+	}
+}
+
+// JWKS returns the JSON-encoded JWK set containing the public keys
+// available on the generator; suitable to be published for token consumers.
+// https://www.rfc-editor.org/rfc/rfc7517.html#section-5
+func (g *Generator) JWKS() ([]byte, error) {
+	return json.Marshal(g.ExportKeys(true))
+}
+
+// JWKSHandler returns an HTTP handler that publishes the JWK set containing
+// the public keys available on the generator. Consumers are allowed to cache
+// the document for `maxAge`.
+func (g *Generator) JWKSHandler(maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		js, err := g.JWKS()
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+		_, _ = w.Write(js)
+	})
 }
 
 // ExportKeys returns the cryptographic keys available on the generator as a
@@ -195,9 +328,102 @@ func (g *Generator) RemoveKey(id string) {
 // included in the exported data.
 // https://www.rfc-editor.org/rfc/rfc7517.html#section-5
 func (g *Generator) ExportKeys(safe bool) jwk.Set {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.prune()
 	set := jwk.Set{Keys: make([]jwk.Record, len(g.keys))}
 	for i, k := range g.keys {
 		set.Keys[i] = k.Export(safe)
 	}
 	return set
 }
+
+// Register new keys. Must be called with the lock held.
+func (g *Generator) addKey(keys ...jwk.Key) error {
+	for _, k := range keys {
+		for _, ek := range g.keys {
+			if ek.ID() == k.ID() {
+				return errors.Errorf("duplicated key id: '%s'", k.ID())
+			}
+		}
+		g.keys = append(g.keys, k)
+		if g.active == "" {
+			g.active = k.ID()
+		}
+	}
+	return nil
+}
+
+// Register a new active key and retire the current one. Must be called
+// with the lock held.
+func (g *Generator) rotateKey(k jwk.Key, grace time.Duration) error {
+	prev := g.active
+	g.active = ""
+	if err := g.addKey(k); err != nil {
+		g.active = prev
+		return err
+	}
+	g.active = k.ID()
+	if prev != "" {
+		g.retired[prev] = time.Now().Add(grace)
+	}
+	g.prune()
+	return nil
+}
+
+// Remove retired keys after its grace period. Must be called with the
+// lock held.
+func (g *Generator) prune() {
+	now := time.Now()
+	for id, deadline := range g.retired {
+		if now.Before(deadline) {
+			continue
+		}
+		for i, k := range g.keys {
+			if k.ID() == id {
+				g.keys = append(g.keys[:i], g.keys[i+1:]...)
+				break
+			}
+		}
+		delete(g.retired, id)
+	}
+}
+
+// Automatic key rotation process.
+func (g *Generator) rotate() {
+	defer g.wg.Done()
+	g.mu.Lock()
+	halt := g.halt
+	g.mu.Unlock()
+	ticker := time.NewTicker(g.policy.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-halt:
+			return
+		case <-ticker.C:
+			if err := g.rotateWithPolicy(); err != nil && g.policy.OnError != nil {
+				g.policy.OnError(err)
+			}
+		}
+	}
+}
+
+// Perform a single rotation using the configured policy.
+func (g *Generator) rotateWithPolicy() error {
+	k, err := g.policy.NewKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate key")
+	}
+	g.mu.Lock()
+	prev := g.active
+	err = g.rotateKey(k, g.policy.Grace)
+	g.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if g.policy.OnRotate != nil {
+		g.policy.OnRotate(k.ID(), prev)
+	}
+	return nil
+}
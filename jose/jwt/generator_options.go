@@ -1,6 +1,11 @@
 package jwt
 
 import (
+	"time"
+
+	"go.bryk.io/pkg/crypto/ed25519"
+	"go.bryk.io/pkg/crypto/signer"
+	"go.bryk.io/pkg/errors"
 	"go.bryk.io/pkg/jose/jwk"
 )
 
@@ -39,3 +44,65 @@ func WithKeySet(set jwk.Set) GeneratorOption {
 		return nil
 	}
 }
+
+// WithEd25519Key registers an Ed25519 key pair on the generator instance,
+// used to issue 'EdDSA' tokens. The key ID is set to the JWK thumbprint
+// of the public key.
+func WithEd25519Key(kp *ed25519.KeyPair) GeneratorOption {
+	return func(g *Generator) error {
+		k, err := jwk.Import(kp.ToJWK(false))
+		if err != nil {
+			return err
+		}
+		return g.AddKey(k)
+	}
+}
+
+// WithSigner registers a signer on the generator instance; for example, to
+// issue tokens using a key stored in an HSM or cloud KMS. The key ID is set
+// to the signer's identifier.
+func WithSigner(s signer.Signer) GeneratorOption {
+	return func(g *Generator) error {
+		k, err := signer.JWK(s)
+		if err != nil {
+			return err
+		}
+		return g.AddKey(k)
+	}
+}
+
+// RotationPolicy adjust the automatic rotation of the generator's signing
+// keys.
+type RotationPolicy struct {
+	// How often a new signing key is generated.
+	Interval time.Duration
+
+	// Period of time retired keys remain available to validate existing
+	// tokens. Should be at least equal to the maximum lifetime of the
+	// tokens issued.
+	Grace time.Duration
+
+	// Produce a new signing key.
+	NewKey func() (jwk.Key, error)
+
+	// Hook executed after every rotation, for example to persist the new
+	// key or notify consumers about the updated JWK set. Optional.
+	OnRotate func(active, retired string)
+
+	// Hook executed if a rotation fails. Optional.
+	OnError func(err error)
+}
+
+// WithRotationPolicy enables the automatic rotation of signing keys. The
+// rotation process runs in the background until the generator is closed.
+// If no signing key is available, an initial one is generated when creating
+// the generator.
+func WithRotationPolicy(policy RotationPolicy) GeneratorOption {
+	return func(g *Generator) error {
+		if policy.Interval <= 0 || policy.NewKey == nil {
+			return errors.New("invalid rotation policy")
+		}
+		g.policy = &policy
+		return nil
+	}
+}
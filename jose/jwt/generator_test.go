@@ -1,11 +1,20 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/crypto/ed25519"
 	"go.bryk.io/pkg/jose/jwa"
 	"go.bryk.io/pkg/jose/jwk"
 )
@@ -150,6 +159,215 @@ func TestNewGenerator(t *testing.T) {
 	})
 }
 
+func TestEd25519Key(t *testing.T) {
+	assert := tdd.New(t)
+	kp, err := ed25519.New()
+	assert.Nil(err, "new key")
+	defer kp.Destroy()
+
+	tg, err := NewGenerator("acme.com", WithEd25519Key(kp))
+	assert.Nil(err, "new generator")
+	assert.True(tg.IsSupported(jwa.EdDSA), "supported method")
+
+	kid := kp.ToJWK(true).KeyID
+	token, err := tg.Issue(kid, &TokenParameters{
+		Subject:   "Rick Sanchez",
+		Audience:  []string{"https://bryk.io"},
+		NotBefore: "0ms",
+	})
+	assert.Nil(err, "issue token")
+	assert.Equal(string(jwa.EdDSA), token.Header().Algorithm)
+	assert.Equal(kid, token.Header().KeyID)
+	assert.Nil(tg.Validate(token.String()), "validate")
+
+	// Tokens can be verified directly with the key pair
+	segments := strings.Split(token.String(), ".")
+	sig, _ := b64.DecodeString(segments[2])
+	assert.True(kp.Verify([]byte(segments[0]+"."+segments[1]), sig), "verify signature")
+
+	// Validator using only public keys
+	tv, err := NewValidator(WithValidationKeys(tg.ExportKeys(true)))
+	assert.Nil(err, "new validator")
+	assert.Nil(tv.Validate(token.String()), "validate")
+}
+
+// Signer backed by a standard library key, used to simulate a key held by
+// an HSM.
+type hsmKey struct {
+	crypto.Signer
+}
+
+func (k *hsmKey) ID() string {
+	return "hsm-key"
+}
+
+func (k *hsmKey) Algorithm() jwa.Alg {
+	return jwa.ES256
+}
+
+func TestSigner(t *testing.T) {
+	assert := tdd.New(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tg, err := NewGenerator("acme.com", WithSigner(&hsmKey{Signer: key}))
+	assert.Nil(err, "new generator")
+	assert.True(tg.IsSupported(jwa.ES256), "supported method")
+
+	token, err := tg.Issue("hsm-key", &TokenParameters{
+		Subject:   "Rick Sanchez",
+		Audience:  []string{"https://bryk.io"},
+		NotBefore: "0ms",
+	})
+	assert.Nil(err, "issue token")
+	assert.Equal("hsm-key", token.Header().KeyID)
+	assert.Nil(tg.Validate(token.String()), "validate")
+
+	// Only public keys are exported
+	set := tg.ExportKeys(false)
+	assert.Len(set.Keys, 1)
+	assert.Empty(set.Keys[0].D, "private key exported")
+	tv, err := NewValidator(WithValidationKeys(set))
+	assert.Nil(err, "new validator")
+	assert.Nil(tv.Validate(token.String()), "validate")
+}
+
+func TestKeyRotation(t *testing.T) {
+	assert := tdd.New(t)
+	params := func() *TokenParameters {
+		return &TokenParameters{
+			Subject:   "Rick Sanchez",
+			Audience:  []string{"https://bryk.io"},
+			NotBefore: "0ms",
+		}
+	}
+
+	t.Run("Manual", func(t *testing.T) {
+		k1, _ := jwk.New(jwa.ES256)
+		k1.SetID("key-1")
+		tg, err := NewGenerator("acme.com", WithKey(k1))
+		assert.Nil(err, "new generator")
+		assert.Equal("key-1", tg.ActiveKey())
+
+		// Active key is used by default
+		t1, err := tg.Issue("", params())
+		assert.Nil(err, "issue token")
+		assert.Equal("key-1", t1.Header().KeyID)
+
+		// Rotate
+		k2, _ := jwk.New(jwa.EdDSA)
+		k2.SetID("key-2")
+		assert.Nil(tg.Rotate(k2, 50*time.Millisecond), "rotate")
+		assert.Equal("key-2", tg.ActiveKey())
+		t2, _ := tg.Issue("", params())
+		assert.Equal("key-2", t2.Header().KeyID)
+
+		// Retired keys can't issue tokens but are still published
+		_, err = tg.Issue("key-1", params())
+		assert.NotNil(err, "retired key")
+		assert.NotNil(tg.SetActiveKey("key-1"), "retired key")
+		assert.Nil(tg.Validate(t1.String()), "validate with retired key")
+		assert.Len(tg.ExportKeys(true).Keys, 2)
+
+		// After the grace period, retired keys are removed
+		time.Sleep(60 * time.Millisecond)
+		assert.NotNil(tg.Validate(t1.String()), "retired key removed")
+		assert.Nil(tg.Validate(t2.String()), "validate with active key")
+		assert.Len(tg.ExportKeys(true).Keys, 1)
+	})
+
+	t.Run("Scheduled", func(t *testing.T) {
+		var rotations int32
+		tg, err := NewGenerator("acme.com", WithRotationPolicy(RotationPolicy{
+			Interval: 20 * time.Millisecond,
+			Grace:    time.Second,
+			NewKey: func() (jwk.Key, error) {
+				return jwk.New(jwa.ES256)
+			},
+			OnRotate: func(_, _ string) {
+				atomic.AddInt32(&rotations, 1)
+			},
+		}))
+		assert.Nil(err, "new generator")
+		first := tg.ActiveKey()
+		assert.NotEmpty(first, "initial key")
+		time.Sleep(70 * time.Millisecond)
+		tg.Close()
+		assert.True(atomic.LoadInt32(&rotations) >= 3, "rotations")
+		assert.NotEqual(first, tg.ActiveKey())
+	})
+
+	t.Run("JWKS", func(t *testing.T) {
+		k, _ := jwk.New(jwa.EdDSA)
+		tg, _ := NewGenerator("acme.com", WithKey(k))
+		rec := httptest.NewRecorder()
+		tg.JWKSHandler(time.Hour).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jwks.json", nil))
+		assert.Equal(http.StatusOK, rec.Code)
+		assert.Equal("public, max-age=3600", rec.Header().Get("Cache-Control"))
+		set := jwk.Set{}
+		assert.Nil(json.Unmarshal(rec.Body.Bytes(), &set), "decode set")
+		assert.Len(set.Keys, 1)
+		assert.Empty(set.Keys[0].D, "private key published")
+	})
+}
+
+func TestTypedClaims(t *testing.T) {
+	assert := tdd.New(t)
+	k, _ := jwk.New(jwa.EdDSA)
+	tg, _ := NewGenerator("acme.com", WithKey(k))
+
+	type profile struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	type claims struct {
+		RegisteredClaims
+		Admin   bool    `json:"admin"`
+		Quota   int64   `json:"quota"`
+		Ratio   float64 `json:"ratio"`
+		Profile profile `json:"profile"`
+	}
+	custom := claims{
+		Admin: true,
+		Quota: 9007199254740993, // larger than 2^53
+		Ratio: 0.75,
+		Profile: profile{
+			Name:   "Rick Sanchez",
+			Scopes: []string{"read", "write"},
+		},
+	}
+
+	// Registered claims take precedence
+	custom.Issuer = "evil.com"
+	token, err := tg.Issue("", &TokenParameters{
+		Subject:   "rick",
+		Audience:  []string{"https://bryk.io"},
+		NotBefore: "0ms",
+	}, custom)
+	assert.Nil(err, "issue token")
+	assert.Nil(tg.Validate(token.String()), "validate")
+
+	// Decode parsed token
+	parsed, err := Parse(token.String())
+	assert.Nil(err, "parse token")
+	res := claims{}
+	assert.Nil(parsed.Decode(&res), "decode claims")
+	assert.Equal("acme.com", res.Issuer)
+	assert.Equal("rick", res.Subject)
+	assert.True(res.Admin)
+	assert.Equal(custom.Quota, res.Quota)
+	assert.Equal(custom.Ratio, res.Ratio)
+	assert.Equal(custom.Profile, res.Profile)
+
+	// Numeric claims retrieved with 'Get' use the standard representation
+	for _, tt := range []*Token{token, parsed} {
+		ratio, err := tt.Get("/ratio")
+		assert.Nil(err, "get claim")
+		assert.Equal(0.75, ratio)
+		iat, err := tt.Get("/iat")
+		assert.Nil(err, "get claim")
+		assert.IsType(float64(0), iat)
+	}
+}
+
 func standardMethods() []jwa.Alg {
 	return []jwa.Alg{
 		jwa.NONE,
@@ -165,6 +383,7 @@ func standardMethods() []jwa.Alg {
 		jwa.PS256,
 		jwa.PS384,
 		jwa.PS512,
+		jwa.EdDSA,
 	}
 }
 
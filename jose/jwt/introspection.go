@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// IntrospectionOptions adjust the behavior of the token introspection handler.
+type IntrospectionOptions struct {
+	// Additional checks applied to every token. Time-based checks, i.e.,
+	// "exp", "nbf" and "iat" claims, are always applied.
+	Checks []Check
+
+	// Revocation checker consulted for every token. Not required if the
+	// validator is already configured with a revocation checker.
+	Revocation RevocationChecker
+
+	// Authorize the caller of the endpoint; the specification requires
+	// the endpoint to be protected to prevent token scanning attacks. If
+	// not provided, all requests are rejected.
+	Authorize func(r *http.Request) bool
+}
+
+// IntrospectionHandler returns an HTTP handler implementing the token
+// introspection endpoint described in RFC-7662. Tokens are received on the
+// "token" form parameter of POST requests and validated using the provided
+// validator. For valid tokens the response includes `"active": true` and
+// all the token claims; for invalid, expired, revoked or unsigned tokens
+// only `"active": false` is returned.
+// https://www.rfc-editor.org/rfc/rfc7662.html
+func IntrospectionHandler(v *Validator, opts IntrospectionOptions) http.Handler {
+	checks := append([]Check{timeCheck(0, false)}, opts.Checks...)
+	if opts.Revocation != nil {
+		checks = append(checks, RevocationCheck(opts.Revocation))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if opts.Authorize == nil || !opts.Authorize(r) {
+			introspectionError(w, http.StatusUnauthorized, "invalid_client")
+			return
+		}
+		value := strings.TrimSpace(r.PostFormValue("token"))
+		if value == "" {
+			introspectionError(w, http.StatusBadRequest, "invalid_request")
+			return
+		}
+
+		// Inactive token
+		res := map[string]interface{}{"active": false}
+		if err := v.Validate(value, checks...); err != nil {
+			introspectionResponse(w, http.StatusOK, res)
+			return
+		}
+
+		// Active token, the "active" value can't be overridden by claims.
+		// Unsigned tokens are rejected by the validator.
+		token, _ := Parse(value)
+		if err := token.Decode(&res); err != nil {
+			introspectionResponse(w, http.StatusOK, map[string]interface{}{"active": false})
+			return
+		}
+		res["active"] = true
+		if _, ok := res["token_type"]; !ok {
+			res["token_type"] = "Bearer"
+		}
+		introspectionResponse(w, http.StatusOK, res)
+	})
+}
+
+// Encode an introspection response.
+func introspectionResponse(w http.ResponseWriter, status int, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Encode an introspection error, as described in RFC-6749.
+// https://www.rfc-editor.org/rfc/rfc6749.html#section-5.2
+func introspectionError(w http.ResponseWriter, status int, code string) {
+	introspectionResponse(w, status, map[string]string{"error": code})
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+func TestIntrospection(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Issue tokens
+	k, _ := jwk.New(jwa.ES256)
+	tg, _ := NewGenerator("acme.com", WithKey(k), WithSupportForNone())
+	params := &TokenParameters{
+		Subject:      "rick",
+		Audience:     []string{"https://bryk.io"},
+		CustomClaims: map[string]string{"scope": "read write"},
+	}
+	token, err := tg.Issue(k.ID(), params)
+	assert.Nil(err, "issue token")
+	params.UniqueIdentifier = ""
+	revoked, _ := tg.Issue(k.ID(), params)
+	claims, _ := revoked.RegisteredClaims()
+	params.UniqueIdentifier = ""
+	unsigned, _ := tg.Issue("none", params)
+
+	// Introspection endpoint
+	rl := NewMemoryRevocationList()
+	assert.Nil(rl.RevokeToken(context.Background(), claims.JTI, time.Now().Add(time.Hour)))
+	val, _ := NewValidator(WithValidationKeys(tg.ExportKeys(true)))
+	handler := IntrospectionHandler(val, IntrospectionOptions{
+		Checks:     []Check{IssuerCheck("acme.com")},
+		Revocation: rl,
+		Authorize: func(r *http.Request) bool {
+			user, pass, ok := r.BasicAuth()
+			return ok && user == "resource-server" && pass == "secret"
+		},
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	introspect := func(token string, auth bool) (int, map[string]interface{}) {
+		form := url.Values{}
+		if token != "" {
+			form.Set("token", token)
+		}
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if auth {
+			req.SetBasicAuth("resource-server", "secret")
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, nil
+		}
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		body := map[string]interface{}{}
+		_ = json.NewDecoder(res.Body).Decode(&body)
+		return res.StatusCode, body
+	}
+
+	// Active token
+	status, res := introspect(token.String(), true)
+	assert.Equal(http.StatusOK, status)
+	assert.Equal(true, res["active"])
+	assert.Equal("rick", res["sub"])
+	assert.Equal("read write", res["scope"])
+	assert.Equal("Bearer", res["token_type"])
+
+	// Inactive tokens
+	status, res = introspect(revoked.String(), true)
+	assert.Equal(http.StatusOK, status)
+	assert.Equal(map[string]interface{}{"active": false}, res, "revoked token")
+	_, res = introspect("invalid.token.value", true)
+	assert.Equal(map[string]interface{}{"active": false}, res, "invalid token")
+	_, res = introspect(unsigned.String(), true)
+	assert.Equal(map[string]interface{}{"active": false}, res, "unsigned token")
+
+	// Invalid requests
+	status, res = introspect(token.String(), false)
+	assert.Equal(http.StatusUnauthorized, status)
+	assert.Equal("invalid_client", res["error"])
+	status, _ = introspect("", true)
+	assert.Equal(http.StatusBadRequest, status)
+	res2, err := http.Get(srv.URL) // nolint: noctx
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, res2.StatusCode)
+	_ = res2.Body.Close()
+
+	// Endpoint without an authorization hook rejects all requests
+	open := httptest.NewServer(IntrospectionHandler(val, IntrospectionOptions{}))
+	defer open.Close()
+	form := url.Values{"token": {token.String()}}
+	res3, err := http.PostForm(open.URL, form) // nolint: noctx
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res3.StatusCode)
+	_ = res3.Body.Close()
+}
@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Context keys used by the token validation middleware.
+type tokenContextKey struct{}
+type claimsContextKey struct{}
+
+// MiddlewareOptions adjust the behavior of the token validation middleware.
+type MiddlewareOptions struct {
+	// Additional checks applied to every token. Time-based checks, i.e.,
+	// "exp", "nbf" and "iat" claims, are always applied using the time when
+	// each request is received and the validator's clock settings.
+	Checks []Check
+
+	// Header used to retrieve the token. Defaults to "Authorization".
+	Header string
+
+	// Authentication scheme expected as prefix for the token value in
+	// the header. Defaults to "Bearer"; an explicit "-" value disables
+	// the prefix validation.
+	Scheme string
+
+	// Name of a cookie used to retrieve the token when not provided in
+	// the request headers. Disabled by default.
+	Cookie string
+
+	// When enabled, requests without a token are allowed to continue.
+	// Invalid tokens are always rejected.
+	Optional bool
+
+	// Returns a new holder used to decode the token claims, for example
+	// a pointer to a custom claims structure. The decoded value is
+	// available to upstream handlers using 'ClaimsFromContext'. If not
+	// provided, claims are decoded as '*RegisteredClaims'.
+	Claims func() interface{}
+
+	// Custom handler used to produce error responses. By default, a 401
+	// status is returned with the corresponding "WWW-Authenticate" header.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// ErrMissingToken is returned when no token is provided in a request and
+// the middleware is not configured as optional.
+var ErrMissingToken = errors.New("missing token")
+
+// Middleware returns an HTTP handler that extracts the bearer token from
+// incoming requests and validates it using the provided validator and the
+// configured checks. For valid tokens, the token instance and its decoded
+// claims are injected into the request context.
+//
+// Upstream elements can retrieve the token and its claims with:
+//
+//	token, ok := TokenFromContext(ctx)
+//	claims, ok := ClaimsFromContext(ctx)
+func Middleware(v *Validator, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.Header == "" {
+		opts.Header = "Authorization"
+	}
+	if opts.Scheme == "" {
+		opts.Scheme = "Bearer"
+	}
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = defaultErrorHandler(opts.Scheme)
+	}
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			// Retrieve token
+			value := tokenFromRequest(r, opts)
+			if value == "" {
+				if opts.Optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				opts.ErrorHandler(w, r, ErrMissingToken)
+				return
+			}
+
+			// Validate token
+			ctx, err := v.Authenticate(r.Context(), value, opts.Claims, opts.Checks...)
+			if err != nil {
+				opts.ErrorHandler(w, r, err)
+				return
+			}
+
+			// Call the next handler in the chain.
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Authenticate validates the provided token value and returns a new context
+// including the token instance and its decoded claims; accessible using
+// 'TokenFromContext' and 'ClaimsFromContext'. Time-based checks are always
+// applied in addition to the provided ones. `claims` returns a new holder
+// used to decode the token claims, if not provided claims are decoded as
+// '*RegisteredClaims'. Unsigned tokens, i.e., using the 'NONE' algorithm,
+// are always rejected. This method is used by the HTTP middleware and can
+// be used to integrate token validation with other transports, like RPC
+// interceptors.
+func (v *Validator) Authenticate(
+	ctx context.Context,
+	value string,
+	claims func() interface{},
+	checks ...Check) (context.Context, error) {
+	// Validate token
+	checks = append([]Check{timeCheck(0, false)}, checks...)
+	if err := v.Validate(value, checks...); err != nil {
+		return nil, err
+	}
+
+	// Decode claims
+	token, _ := Parse(value)
+	var holder interface{} = new(RegisteredClaims)
+	if claims != nil {
+		holder = claims()
+	}
+	if err := token.Decode(holder); err != nil {
+		return nil, errors.Wrap(err, "failed to decode claims")
+	}
+	ctx = context.WithValue(ctx, tokenContextKey{}, token)
+	return context.WithValue(ctx, claimsContextKey{}, holder), nil
+}
+
+// TokenFromContext returns the validated token instance injected by the
+// middleware or 'Authenticate', if available.
+func TokenFromContext(ctx context.Context) (*Token, bool) {
+	t, ok := ctx.Value(tokenContextKey{}).(*Token)
+	return t, ok
+}
+
+// ClaimsFromContext returns the decoded token claims injected by the
+// middleware or 'Authenticate', if available. The value type is determined
+// by the 'Claims' setting on the middleware options.
+func ClaimsFromContext(ctx context.Context) (interface{}, bool) {
+	c := ctx.Value(claimsContextKey{})
+	return c, c != nil
+}
+
+// Retrieve the raw token value from the request headers or cookie.
+func tokenFromRequest(r *http.Request, opts MiddlewareOptions) string {
+	if value := strings.TrimSpace(r.Header.Get(opts.Header)); value != "" {
+		if opts.Scheme == "-" {
+			return value
+		}
+		scheme, token, ok := strings.Cut(value, " ")
+		if !ok || !strings.EqualFold(scheme, opts.Scheme) {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+	if opts.Cookie != "" {
+		if c, err := r.Cookie(opts.Cookie); err == nil {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// Default error response, as described in RFC-6750.
+// https://www.rfc-editor.org/rfc/rfc6750.html#section-3
+func defaultErrorHandler(scheme string) func(w http.ResponseWriter, r *http.Request, err error) {
+	if scheme == "-" {
+		scheme = "Bearer"
+	}
+	return func(w http.ResponseWriter, _ *http.Request, err error) {
+		challenge := scheme
+		if !errors.Is(err, ErrMissingToken) {
+			challenge = fmt.Sprintf(`%s error="invalid_token"`, scheme)
+		}
+		w.Header().Set("WWW-Authenticate", challenge)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	}
+}
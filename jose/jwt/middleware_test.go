@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+func TestMiddleware(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Issue token
+	k, _ := jwk.New(jwa.ES256)
+	k.SetID("master-key")
+	tg, _ := NewGenerator("acme.com", WithKey(k))
+	token, err := tg.Issue("master-key", &TokenParameters{
+		Subject:      "rick",
+		Audience:     []string{"https://bryk.io"},
+		NotBefore:    "0ms",
+		CustomClaims: map[string]string{"role": "admin"},
+	})
+	assert.Nil(err, "issue token")
+	val, _ := NewValidator(WithValidationKeys(tg.ExportKeys(true)))
+
+	// Typed claims
+	type claims struct {
+		RegisteredClaims
+		Role string `json:"role"`
+	}
+
+	// Sample handler
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := ClaimsFromContext(r.Context()); ok {
+			_, _ = w.Write([]byte(c.(*claims).Role + ":" + c.(*claims).Subject))
+			return
+		}
+		_, _ = w.Write([]byte("anonymous"))
+	})
+	mw := Middleware(val, MiddlewareOptions{
+		Checks: []Check{IssuerCheck("acme.com")},
+		Claims: func() interface{} { return new(claims) },
+	})
+	srv := mw(handler)
+	call := func(h http.Handler, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		res := call(srv, "Bearer "+token.String())
+		assert.Equal(http.StatusOK, res.Code)
+		assert.Equal("admin:rick", res.Body.String())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		res := call(srv, "")
+		assert.Equal(http.StatusUnauthorized, res.Code)
+		assert.Equal("Bearer", res.Header().Get("WWW-Authenticate"))
+
+		// Optional token
+		opt := Middleware(val, MiddlewareOptions{Optional: true})(handler)
+		res = call(opt, "")
+		assert.Equal(http.StatusOK, res.Code)
+		assert.Equal("anonymous", res.Body.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		res := call(srv, "Bearer "+token.String()+"invalid")
+		assert.Equal(http.StatusUnauthorized, res.Code)
+		assert.Contains(res.Header().Get("WWW-Authenticate"), "invalid_token")
+
+		// Failed check
+		strict := Middleware(val, MiddlewareOptions{Checks: []Check{IssuerCheck("other.com")}})(handler)
+		res = call(strict, "Bearer "+token.String())
+		assert.Equal(http.StatusUnauthorized, res.Code)
+
+		// Wrong scheme
+		res = call(srv, "Basic "+token.String())
+		assert.Equal(http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		// Forged token using the 'NONE' algorithm
+		ng, _ := NewGenerator("acme.com", WithSupportForNone())
+		forged, err := ng.Issue("none", &TokenParameters{
+			Subject:      "admin",
+			Audience:     []string{"https://bryk.io"},
+			NotBefore:    "0ms",
+			CustomClaims: map[string]string{"role": "admin"},
+		})
+		assert.Nil(err, "issue unsigned token")
+		res := call(srv, "Bearer "+forged.String())
+		assert.Equal(http.StatusUnauthorized, res.Code)
+		assert.Contains(res.Header().Get("WWW-Authenticate"), "invalid_token")
+	})
+
+	t.Run("ErrorHandler", func(t *testing.T) {
+		custom := Middleware(val, MiddlewareOptions{
+			ErrorHandler: func(w http.ResponseWriter, _ *http.Request, err error) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+			},
+		})(handler)
+		res := call(custom, "")
+		assert.Equal(http.StatusForbidden, res.Code)
+		assert.Contains(res.Body.String(), ErrMissingToken.Error())
+	})
+}
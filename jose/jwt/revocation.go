@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.bryk.io/pkg/errors"
+)
+
+// ErrRevoked is the error for a revoked token.
+const ErrRevoked = "token was revoked"
+
+// RevocationChecker instances are consulted when validating tokens, allowing
+// compromised tokens to be invalidated before its expiration.
+type RevocationChecker interface {
+	// IsRevoked reports whether the token with the provided claims was
+	// revoked.
+	IsRevoked(ctx context.Context, claims RegisteredClaims) (bool, error)
+}
+
+// RevocationCheck validates the token was not revoked, using the provided
+// revocation checker.
+func RevocationCheck(rc RevocationChecker) Check {
+	return func(token *Token) error {
+		pl, err := token.RegisteredClaims()
+		if err != nil {
+			return err
+		}
+		revoked, err := rc.IsRevoked(context.Background(), pl)
+		if err != nil {
+			return errors.Wrap(err, "failed to verify revocation status")
+		}
+		if revoked {
+			return errors.New(ErrRevoked)
+		}
+		return nil
+	}
+}
+
+// MemoryRevocationList is an in-memory revocation checker, suitable for
+// single-instance deployments. Tokens can be revoked individually, based on
+// its "jti" claim, or for a specific subject, based on its "sub" claim.
+type MemoryRevocationList struct {
+	tokens   map[string]time.Time
+	subjects map[string]subjectRevocation
+	mu       sync.Mutex
+}
+
+type subjectRevocation struct {
+	revokedAt time.Time
+	expires   time.Time
+}
+
+// NewMemoryRevocationList returns a new in-memory revocation list.
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]subjectRevocation),
+	}
+}
+
+// RevokeToken revokes the token with the provided unique identifier. The
+// entry is kept until the token's expiration time `exp`.
+func (rl *MemoryRevocationList) RevokeToken(_ context.Context, jti string, exp time.Time) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.tokens[jti] = exp
+	return nil
+}
+
+// RevokeSubject revokes all tokens issued for the subject up to this point.
+// The entry is kept for `ttl`, it should be at least equal to the maximum
+// lifetime of the tokens issued.
+func (rl *MemoryRevocationList) RevokeSubject(_ context.Context, sub string, ttl time.Duration) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	rl.subjects[sub] = subjectRevocation{revokedAt: now, expires: now.Add(ttl)}
+	return nil
+}
+
+// IsRevoked reports whether the token with the provided claims was revoked.
+func (rl *MemoryRevocationList) IsRevoked(_ context.Context, claims RegisteredClaims) (bool, error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.prune()
+	if _, ok := rl.tokens[claims.JTI]; ok && claims.JTI != "" {
+		return true, nil
+	}
+	if sr, ok := rl.subjects[claims.Subject]; ok && claims.Subject != "" {
+		return claims.IssuedAt <= sr.revokedAt.Unix(), nil
+	}
+	return false, nil
+}
+
+// Remove expired entries. Must be called with the lock held.
+func (rl *MemoryRevocationList) prune() {
+	now := time.Now()
+	for k, exp := range rl.tokens {
+		if now.After(exp) {
+			delete(rl.tokens, k)
+		}
+	}
+	for k, sr := range rl.subjects {
+		if now.After(sr.expires) {
+			delete(rl.subjects, k)
+		}
+	}
+}
+
+// RedisRevocationList is a revocation checker backed by a Redis server,
+// suitable to share revocation information across multiple instances.
+// Entries are stored with an expiration time and removed automatically.
+type RedisRevocationList struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisRevocationList returns a new revocation list using the provided
+// Redis client. All keys are stored using the `prefix` value provided.
+func NewRedisRevocationList(client redis.UniversalClient, prefix string) *RedisRevocationList {
+	if prefix == "" {
+		prefix = "jwt:revoked"
+	}
+	return &RedisRevocationList{client: client, prefix: prefix}
+}
+
+// RevokeToken revokes the token with the provided unique identifier. The
+// entry is kept until the token's expiration time `exp`.
+func (rl *RedisRevocationList) RevokeToken(ctx context.Context, jti string, exp time.Time) error {
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		return nil // token already expired
+	}
+	return rl.client.Set(ctx, rl.key("jti", jti), "1", ttl).Err()
+}
+
+// RevokeSubject revokes all tokens issued for the subject up to this point.
+// The entry is kept for `ttl`, it should be at least equal to the maximum
+// lifetime of the tokens issued.
+func (rl *RedisRevocationList) RevokeSubject(ctx context.Context, sub string, ttl time.Duration) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	return rl.client.Set(ctx, rl.key("sub", sub), now, ttl).Err()
+}
+
+// IsRevoked reports whether the token with the provided claims was revoked.
+func (rl *RedisRevocationList) IsRevoked(ctx context.Context, claims RegisteredClaims) (bool, error) {
+	if claims.JTI != "" {
+		n, err := rl.client.Exists(ctx, rl.key("jti", claims.JTI)).Result()
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+	if claims.Subject == "" {
+		return false, nil
+	}
+	revokedAt, err := rl.client.Get(ctx, rl.key("sub", claims.Subject)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return claims.IssuedAt <= revokedAt, nil
+}
+
+func (rl *RedisRevocationList) key(kind, id string) string {
+	return rl.prefix + ":" + kind + ":" + id
+}
@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+)
+
+// Revocation list implementations used in tests.
+type revocationList interface {
+	RevocationChecker
+	RevokeToken(ctx context.Context, jti string, exp time.Time) error
+	RevokeSubject(ctx context.Context, sub string, ttl time.Duration) error
+}
+
+func TestRevocation(t *testing.T) {
+	assert := tdd.New(t)
+	ctx := context.Background()
+
+	// Redis server
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	k, _ := jwk.New(jwa.ES256)
+	tg, _ := NewGenerator("acme.com", WithKey(k))
+	issue := func(sub string) *Token {
+		token, err := tg.Issue("", &TokenParameters{
+			Subject:    sub,
+			Audience:   []string{"https://bryk.io"},
+			NotBefore:  "0ms",
+			Expiration: "1h",
+		})
+		assert.Nil(err, "issue token")
+		return token
+	}
+
+	lists := map[string]revocationList{
+		"Memory": NewMemoryRevocationList(),
+		"Redis":  NewRedisRevocationList(rc, ""),
+	}
+	for name, rl := range lists {
+		t.Run(name, func(t *testing.T) {
+			val, err := NewValidator(WithValidationKeys(tg.ExportKeys(true)), WithRevocationChecker(rl))
+			assert.Nil(err, "new validator")
+
+			// Revoke a single token
+			t1 := issue("rick")
+			t2 := issue("rick")
+			assert.Nil(val.Validate(t1.String()), "validate")
+			rcl, _ := t1.RegisteredClaims()
+			assert.Nil(rl.RevokeToken(ctx, rcl.JTI, time.Unix(rcl.ExpirationTime, 0)), "revoke token")
+			assert.NotNil(val.Validate(t1.String()), "revoked token")
+			assert.Nil(val.Validate(t2.String()), "validate")
+
+			// Revoke all tokens for a subject
+			t3 := issue("morty")
+			assert.Nil(rl.RevokeSubject(ctx, "rick", time.Hour), "revoke subject")
+			assert.NotNil(val.Validate(t2.String()), "revoked subject")
+			assert.Nil(val.Validate(t3.String()), "validate")
+
+			// Tokens issued after the revocation are valid
+			time.Sleep(time.Second)
+			assert.Nil(val.Validate(issue("rick").String()), "validate")
+
+			// Check usage on generators
+			assert.NotNil(tg.Validate(t2.String(), RevocationCheck(rl)), "revoked subject")
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.bryk.io/pkg/errors"
 	xjson "go.bryk.io/pkg/internal/json"
@@ -14,6 +15,15 @@ type Token struct {
 	he Header
 	pl interface{}
 	sg []byte
+
+	// Original encoding of the payload, if available; used to decode
+	// the claims without losing precision on numeric values.
+	raw []byte
+
+	// Clock source and skew tolerance used by time-based checks; set
+	// by the validator.
+	clock func() time.Time
+	skew  time.Duration
 }
 
 // Parse returns a functional token instance from its compact string representation.
@@ -38,7 +48,7 @@ func Parse(token string) (*Token, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err = json.Unmarshal(data, &t.pl); err != nil {
+	if err = t.setPayload(data); err != nil {
 		return nil, err
 	}
 
@@ -94,8 +104,13 @@ func (t *Token) RegisteredClaims() (RegisteredClaims, error) {
 }
 
 // Decode will load the token payload segment (i.e., claims content) into the
-// provided holder.
+// provided holder. The holder can be any user-defined claims structure, for
+// example one embedding 'RegisteredClaims'; numeric, boolean and nested values
+// are preserved.
 func (t *Token) Decode(v interface{}) error {
+	if t.raw != nil {
+		return json.Unmarshal(t.raw, &v)
+	}
 	pb, err := json.Marshal(t.pl)
 	if err != nil {
 		return err
@@ -103,6 +118,19 @@ func (t *Token) Decode(v interface{}) error {
 	return json.Unmarshal(pb, &v)
 }
 
+// Set the token payload from its JSON encoding. The original encoding is
+// preserved for 'Decode', while numeric values on the payload returned by
+// 'Get' use the standard 'float64' representation.
+func (t *Token) setPayload(data []byte) error {
+	var pl interface{}
+	if err := json.Unmarshal(data, &pl); err != nil {
+		return err
+	}
+	t.pl = pl
+	t.raw = data
+	return nil
+}
+
 // Validate will apply the provided validator functions to the token instance.
 func (t *Token) Validate(checks ...Check) error {
 	for _, vl := range checks {
@@ -135,6 +163,9 @@ func (t *Token) segment(seg string) ([]byte, error) {
 	case "he":
 		return encode(t.he, true)
 	case "pl":
+		if t.raw != nil {
+			return encode(t.raw, false)
+		}
 		return encode(t.pl, true)
 	case "sg":
 		return encode(t.sg, false)
@@ -154,3 +185,11 @@ func (t *Token) material() ([]byte, error) {
 	}
 	return []byte(fmt.Sprintf("%s.%s", hb, pb)), nil
 }
+
+// Current time as reported by the token's clock source.
+func (t *Token) now() time.Time {
+	if t.clock != nil {
+		return t.clock()
+	}
+	return time.Now()
+}
@@ -50,6 +50,12 @@ type TokenParameters struct {
 	// claim, the latter will take precedence and override the custom value.
 	CustomClaims interface{}
 
+	// JWK thumbprint of the client key the token is bound to, as described
+	// by RFC-9449. When set, a confirmation ("cnf") claim is included in the
+	// token and only requests including a valid DPoP proof produced with the
+	// same key should be accepted. Optional.
+	KeyBinding string
+
 	// Produced when parsing 'NotBefore'.
 	nbf time.Duration
 
@@ -87,6 +93,10 @@ func (tp *TokenParameters) GetChecks() []Check {
 	if tp.ContentType != "" {
 		checks = append(checks, ContentTypeCheck(tp.ContentType))
 	}
+	// 'cnf' validation
+	if tp.KeyBinding != "" {
+		checks = append(checks, ConfirmationCheck(tp.KeyBinding))
+	}
 	return checks
 }
 
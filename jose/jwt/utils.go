@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
@@ -89,7 +90,7 @@ func merge(item ...interface{}) (map[string]interface{}, error) {
 			return nil, errors.New("failed to encode item")
 		}
 		m := make(map[string]interface{})
-		if err := json.Unmarshal(b, &m); err != nil {
+		if err := decodeJSON(b, &m); err != nil {
 			return nil, errors.New("failed to re-encode item")
 		}
 
@@ -101,6 +102,14 @@ func merge(item ...interface{}) (map[string]interface{}, error) {
 	return res, nil
 }
 
+// Decode JSON content preserving the original representation of numeric
+// values; avoids losing precision on large integers.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
 // Retrieve a key instance based on its `id`.
 func getKey(id string, keys []jwk.Key) jwk.Key {
 	for _, k := range keys {
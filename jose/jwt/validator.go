@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"time"
+
 	"go.bryk.io/pkg/errors"
 	"go.bryk.io/pkg/jose/jwa"
 	"go.bryk.io/pkg/jose/jwk"
@@ -8,9 +10,15 @@ import (
 
 // Validator instances can be used when tokens need to be validated but
 // issuing is not possible or desired. For example when retrieving the
-// server's JWK key set including only public keys.
+// server's JWK key set including only public keys. Only tokens signed with
+// one of the configured keys are accepted; unsecured tokens using the 'NONE'
+// algorithm, or with no algorithm at all, are always rejected.
 type Validator struct {
-	keys []jwk.Key
+	keys       []jwk.Key
+	resolvers  []jwk.KeyResolver
+	revocation RevocationChecker
+	clock      func() time.Time
+	skew       time.Duration
 }
 
 // NewValidator returns a new token validator instance ready to be used.
@@ -24,39 +32,63 @@ func NewValidator(opts ...ValidatorOption) (*Validator, error) {
 	return v, nil
 }
 
-// Validate a previously generated token instance.
+// Validate a previously generated token instance. Unsecured tokens, i.e.,
+// using the 'NONE' algorithm, are always rejected.
 //  1. Is the string a valid JWT?
-//  2. Is 'alg' supported by the generator?
+//  2. Is 'alg' backed by a configured key?
 //  3. Is the digital signature valid?
 //  4. Run all provided checks
+//  5. Was the token revoked?
 func (v *Validator) Validate(token string, checks ...Check) error {
 	t, err := Parse(token)
 	if err != nil {
 		return err
 	}
+	t.clock = v.clock
+	t.skew = v.skew
+
+	// Revocation status is verified after all other validations
+	if v.revocation != nil {
+		checks = append(checks, RevocationCheck(v.revocation))
+	}
 
-	// 'NONE' tokens require only payload validations
+	// 'NONE' tokens are never accepted by the validator
 	alg := jwa.Alg(t.Header().Algorithm)
-	if alg == jwa.NONE {
-		return t.Validate(checks...)
+	if alg == jwa.NONE || alg == "" {
+		return errors.New("unsupported 'alg' header")
 	}
 
 	// Verify 'alg' is supported
-	if !isSupported(jwa.Alg(t.Header().Algorithm), v.keys) {
+	if len(v.resolvers) == 0 && !isSupported(alg, v.keys) {
 		return errors.New("unsupported 'alg' header")
 	}
 
 	// Verify signature for secure tokens
-	if t.Header().Algorithm != string(jwa.NONE) {
-		key := getKey(t.Header().KeyID, v.keys)
-		if key == nil {
-			return errors.New("invalid key identifier")
-		}
-		if err = verify(token, key); err != nil {
-			return err
-		}
+	key := v.getKey(t.Header().KeyID)
+	if key == nil {
+		return errors.New("invalid key identifier")
+	}
+	if key.Alg() != alg {
+		return errors.New("unsupported 'alg' header")
+	}
+	if err = verify(token, key); err != nil {
+		return err
 	}
 
 	// Basic payload validations
 	return t.Validate(checks...)
 }
+
+// Retrieve a key instance based on its `id`; static keys are used first
+// and resolvers are queried in the order they were registered.
+func (v *Validator) getKey(id string) jwk.Key {
+	if k := getKey(id, v.keys); k != nil {
+		return k
+	}
+	for _, r := range v.resolvers {
+		if k, err := r.Resolve(id); err == nil {
+			return k
+		}
+	}
+	return nil
+}
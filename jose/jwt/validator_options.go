@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"time"
+
+	"go.bryk.io/pkg/errors"
 	"go.bryk.io/pkg/jose/jwk"
 )
 
@@ -8,10 +11,16 @@ import (
 // for token validators.
 type ValidatorOption func(v *Validator) error
 
-// WithValidationKeys registers the keys provided in the JWK set to be used
-// for token validation.
-func WithValidationKeys(set jwk.Set) ValidatorOption {
+// WithValidationKeys registers the keys to be used for token validation.
+// The keys can be provided as a static JWK set or using a key resolver,
+// for example a remote key set published by an identity provider.
+func WithValidationKeys(src jwk.KeyResolver) ValidatorOption {
 	return func(v *Validator) error {
+		set, ok := src.(jwk.Set)
+		if !ok {
+			v.resolvers = append(v.resolvers, src)
+			return nil
+		}
 		keys, err := expandSet(set)
 		if err != nil {
 			return err
@@ -20,3 +29,39 @@ func WithValidationKeys(set jwk.Set) ValidatorOption {
 		return nil
 	}
 }
+
+// WithRevocationChecker sets a revocation checker to be consulted when
+// validating tokens.
+func WithRevocationChecker(rc RevocationChecker) ValidatorOption {
+	return func(v *Validator) error {
+		v.revocation = rc
+		return nil
+	}
+}
+
+// WithClock sets the clock source used by time-based checks, like the ones
+// produced by 'ValidityCheck' and 'StandardChecks'. Defaults to the local
+// system time.
+func WithClock(clock func() time.Time) ValidatorOption {
+	return func(v *Validator) error {
+		if clock == nil {
+			return errors.New("invalid clock source")
+		}
+		v.clock = clock
+		return nil
+	}
+}
+
+// WithClockSkew sets the maximum clock difference tolerated by time-based
+// checks, like the ones produced by 'ValidityCheck' and 'StandardChecks'.
+// Useful when tokens are produced by systems with clocks not perfectly
+// synchronized. Defaults to 0.
+func WithClockSkew(skew time.Duration) ValidatorOption {
+	return func(v *Validator) error {
+		if skew < 0 {
+			return errors.New("invalid clock skew value")
+		}
+		v.skew = skew
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"testing"
+	"time"
 
 	tdd "github.com/stretchr/testify/assert"
 	"go.bryk.io/pkg/jose/jwa"
@@ -44,3 +45,47 @@ func TestValidator(t *testing.T) {
 	valChecks = append(valChecks, IssuerCheck("acme.com"))
 	assert.Nil(val.Validate(token.String(), valChecks...), "validate failed")
 }
+
+func TestValidationPolicy(t *testing.T) {
+	assert := tdd.New(t)
+	mk, _ := jwk.New(jwa.ES256)
+	tg, _ := NewGenerator("acme.com")
+	assert.Nil(tg.AddKey(mk), "add key")
+	aud := []string{"https://bryk.io"}
+	token, err := tg.Issue(mk.ID(), &TokenParameters{
+		Subject:    "Rick Sanchez",
+		Audience:   aud,
+		Expiration: "10m",
+		NotBefore:  "1m",
+	})
+	assert.Nil(err, "new token")
+	jwks := tg.ExportKeys(true)
+
+	// Invalid options
+	_, err = NewValidator(WithClockSkew(-1 * time.Second))
+	assert.NotNil(err, "negative skew")
+	_, err = NewValidator(WithClock(nil))
+	assert.NotNil(err, "nil clock")
+
+	// Token is not valid yet
+	val, _ := NewValidator(WithValidationKeys(jwks))
+	assert.NotNil(val.Validate(token.String(), StandardChecks("acme.com", aud, 0)...), "nbf")
+	assert.Nil(val.Validate(token.String(), StandardChecks("acme.com", aud, 2*time.Minute)...), "leeway")
+	assert.NotNil(val.Validate(token.String(), StandardChecks("other.com", aud, 2*time.Minute)...), "iss")
+	assert.NotNil(val.Validate(token.String(), StandardChecks("acme.com", []string{"other"}, 2*time.Minute)...), "aud")
+
+	// Skew tolerance configured on the validator
+	val, _ = NewValidator(WithValidationKeys(jwks), WithClockSkew(2*time.Minute))
+	assert.Nil(val.Validate(token.String(), StandardChecks("acme.com", aud, 0)...), "skew")
+
+	// Custom clock source
+	at := time.Now().Add(5 * time.Minute)
+	val, _ = NewValidator(WithValidationKeys(jwks), WithClock(func() time.Time { return at }))
+	assert.Nil(val.Validate(token.String(), StandardChecks("acme.com", aud, 0)...), "clock")
+	at = at.Add(10 * time.Minute)
+	err = val.Validate(token.String(), StandardChecks("acme.com", aud, 0)...)
+	assert.NotNil(err, "expired")
+	assert.Equal(ErrExpValidation, err.Error())
+	assert.Nil(val.Validate(token.String(), StandardChecks("acme.com", aud, 10*time.Minute)...), "expired with leeway")
+
+}
@@ -2,6 +2,7 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sync"
 
@@ -26,6 +27,11 @@ type CharmOptions struct {
 
 	// AsJSON enables the use of JSON as the log entry format.
 	AsJSON bool
+
+	// A destination for all produced messages. Use a `RotatingFile` to write
+	// messages to a local file with automatic rotation. If no sink is
+	// specified `os.Stderr` will be used by default.
+	Sink io.Writer
 }
 
 type charmHandler struct {
@@ -38,7 +44,11 @@ type charmHandler struct {
 //
 //	More information: https://github.com/charmbracelet/log
 func WithCharm(opt CharmOptions) Logger {
-	cl := charm.NewWithOptions(os.Stderr, charm.Options{
+	var output io.Writer = os.Stderr
+	if opt.Sink != nil {
+		output = opt.Sink
+	}
+	cl := charm.NewWithOptions(output, charm.Options{
 		Prefix:          opt.Prefix,
 		Level:           charm.DebugLevel,
 		TimeFormat:      opt.TimeFormat,
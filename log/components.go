@@ -0,0 +1,294 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentField is the name of the field used to identify the component
+// producing a message.
+const ComponentField = "component"
+
+// Registry manages named sub-loggers, or components, each with its own
+// independent level. Levels can be adjusted at runtime and the changes are
+// applied immediately to all the loggers created for the component; for
+// example, to enable debug messages for a single component in production.
+//
+// Components without an explicit level use the registry's default level.
+type Registry struct {
+	mu         sync.Mutex
+	base       Logger
+	def        atomic.Uint32
+	components map[string]*componentLevel
+}
+
+// Level settings for a component.
+type componentLevel struct {
+	lvl      atomic.Uint32
+	explicit atomic.Bool
+}
+
+// NewRegistry returns a new component registry. Messages are produced using
+// the `base` logger, and `lvl` is used as default level for all components.
+// The level of the base logger is managed by the registry and should not be
+// adjusted directly.
+func NewRegistry(base Logger, lvl Level) *Registry {
+	base.SetLevel(Debug) // filtering is performed by the registry
+	r := &Registry{
+		base:       base,
+		components: make(map[string]*componentLevel),
+	}
+	r.def.Store(uint32(lvl))
+	return r
+}
+
+// Component returns a logger instance for the component `name`. Every
+// message produced will include the component name in the `ComponentField`.
+func (r *Registry) Component(name string) Logger {
+	ll := r.base.Sub(Fields{ComponentField: name})
+	ll.SetLevel(Debug)
+	return &componentLogger{
+		name: name,
+		reg:  r,
+		cl:   r.get(name),
+		log:  ll,
+	}
+}
+
+// SetLevel adjusts the level for the component `name`.
+func (r *Registry) SetLevel(name string, lvl Level) {
+	cl := r.get(name)
+	cl.lvl.Store(uint32(lvl))
+	cl.explicit.Store(true)
+}
+
+// Reset removes the explicit level set for the component `name`, so the
+// default level is used again.
+func (r *Registry) Reset(name string) {
+	r.get(name).explicit.Store(false)
+}
+
+// Level returns the level currently used by the component `name`.
+func (r *Registry) Level(name string) Level {
+	return r.level(r.get(name))
+}
+
+// SetDefaultLevel adjusts the level used by all components without an
+// explicit level.
+func (r *Registry) SetDefaultLevel(lvl Level) {
+	r.def.Store(uint32(lvl))
+}
+
+// DefaultLevel returns the level used by all components without an
+// explicit level.
+func (r *Registry) DefaultLevel() Level {
+	return Level(r.def.Load())
+}
+
+// Levels returns the level currently used by all registered components.
+func (r *Registry) Levels() map[string]Level {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make(map[string]Level, len(r.components))
+	for name, cl := range r.components {
+		list[name] = r.level(cl)
+	}
+	return list
+}
+
+// ServeHTTP provides an administrative endpoint to inspect and adjust the
+// levels used by the registered components. Use a `GET` request to retrieve
+// the current settings, and a `PUT` request to adjust the level for a
+// component; if no component is specified the default level is adjusted.
+// An empty level resets the component to the default level.
+//
+//	curl -X PUT -d '{"component":"rpc","level":"debug"}' localhost:9090/log/levels
+//
+// The endpoint allows modifying the application behavior and should only be
+// exposed on an internal or properly secured interface.
+func (r *Registry) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var change struct {
+			Component string `json:"component"`
+			Level     string `json:"level"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&change); err != nil {
+			http.Error(res, "invalid request", http.StatusBadRequest)
+			return
+		}
+		if change.Component != "" && change.Level == "" {
+			r.Reset(change.Component)
+			break
+		}
+		lvl, err := ParseLevel(change.Level)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if change.Component == "" {
+			r.SetDefaultLevel(lvl)
+		} else {
+			r.SetLevel(change.Component, lvl)
+		}
+	default:
+		res.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(res).Encode(r.report())
+}
+
+// Current settings, as returned by the HTTP endpoint.
+func (r *Registry) report() interface{} {
+	levels := r.Levels()
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	type entry struct {
+		Name     string `json:"name"`
+		Level    string `json:"level"`
+		Explicit bool   `json:"explicit"`
+	}
+	list := make([]entry, len(names))
+	for i, name := range names {
+		list[i] = entry{
+			Name:     name,
+			Level:    levels[name].String(),
+			Explicit: r.get(name).explicit.Load(),
+		}
+	}
+	return struct {
+		Default    string  `json:"default"`
+		Components []entry `json:"components"`
+	}{
+		Default:    r.DefaultLevel().String(),
+		Components: list,
+	}
+}
+
+// Settings for a component, registered on first use.
+func (r *Registry) get(name string) *componentLevel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cl, ok := r.components[name]
+	if !ok {
+		cl = new(componentLevel)
+		r.components[name] = cl
+	}
+	return cl
+}
+
+func (r *Registry) level(cl *componentLevel) Level {
+	if cl.explicit.Load() {
+		return Level(cl.lvl.Load())
+	}
+	return r.DefaultLevel()
+}
+
+// Logger instance for a registered component.
+type componentLogger struct {
+	name string
+	reg  *Registry
+	cl   *componentLevel
+	log  Logger
+}
+
+func (c *componentLogger) enabled(lvl Level) bool {
+	return lvl >= c.reg.level(c.cl)
+}
+
+func (c *componentLogger) SetLevel(lvl Level) {
+	c.reg.SetLevel(c.name, lvl)
+}
+
+func (c *componentLogger) Sub(tags Fields) Logger {
+	ll := c.log.Sub(tags)
+	ll.SetLevel(Debug)
+	return c.with(ll)
+}
+
+func (c *componentLogger) WithFields(fields Fields) Logger {
+	return c.with(c.log.WithFields(fields))
+}
+
+func (c *componentLogger) WithField(key string, value interface{}) Logger {
+	return c.with(c.log.WithField(key, value))
+}
+
+func (c *componentLogger) Debug(args ...interface{}) {
+	c.Print(Debug, args...)
+}
+
+func (c *componentLogger) Debugf(format string, args ...interface{}) {
+	c.Printf(Debug, format, args...)
+}
+
+func (c *componentLogger) Info(args ...interface{}) {
+	c.Print(Info, args...)
+}
+
+func (c *componentLogger) Infof(format string, args ...interface{}) {
+	c.Printf(Info, format, args...)
+}
+
+func (c *componentLogger) Warning(args ...interface{}) {
+	c.Print(Warning, args...)
+}
+
+func (c *componentLogger) Warningf(format string, args ...interface{}) {
+	c.Printf(Warning, format, args...)
+}
+
+func (c *componentLogger) Error(args ...interface{}) {
+	c.Print(Error, args...)
+}
+
+func (c *componentLogger) Errorf(format string, args ...interface{}) {
+	c.Printf(Error, format, args...)
+}
+
+func (c *componentLogger) Panic(args ...interface{}) {
+	c.Print(Panic, args...)
+}
+
+func (c *componentLogger) Panicf(format string, args ...interface{}) {
+	c.Printf(Panic, format, args...)
+}
+
+func (c *componentLogger) Fatal(args ...interface{}) {
+	c.Print(Fatal, args...)
+}
+
+func (c *componentLogger) Fatalf(format string, args ...interface{}) {
+	c.Printf(Fatal, format, args...)
+}
+
+func (c *componentLogger) Print(level Level, args ...interface{}) {
+	if c.enabled(level) {
+		lPrint(c.log, level, args...)
+	}
+}
+
+func (c *componentLogger) Printf(level Level, format string, args ...interface{}) {
+	if c.enabled(level) {
+		lPrintf(c.log, level, format, args...)
+	}
+}
+
+// Component logger sharing the same level settings.
+func (c *componentLogger) with(ll Logger) Logger {
+	return &componentLogger{
+		name: c.name,
+		reg:  c.reg,
+		cl:   c.cl,
+		log:  ll,
+	}
+}
@@ -0,0 +1,143 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+// concurrent-safe buffer used as log sink.
+type testSink struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *testSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *testSink) flush() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.buf.String()
+	s.buf.Reset()
+	return out
+}
+
+func TestParseLevel(t *testing.T) {
+	assert := tdd.New(t)
+	for _, lvl := range []Level{Debug, Info, Warning, Error, Panic, Fatal} {
+		res, err := ParseLevel(strings.ToUpper(lvl.String()))
+		assert.Nil(err)
+		assert.Equal(lvl, res)
+	}
+	res, err := ParseLevel("warn")
+	assert.Nil(err)
+	assert.Equal(Warning, res)
+	_, err = ParseLevel("verbose")
+	assert.NotNil(err)
+}
+
+func TestRegistry(t *testing.T) {
+	assert := tdd.New(t)
+	sink := new(testSink)
+	reg := NewRegistry(WithZero(ZeroOptions{Sink: sink}), Info)
+	rpc := reg.Component("rpc")
+	db := reg.Component("db")
+
+	t.Run("DefaultLevel", func(t *testing.T) {
+		rpc.Debug("rpc debug message")
+		rpc.Info("rpc info message")
+		out := sink.flush()
+		assert.NotContains(out, "rpc debug message")
+		assert.Contains(out, "rpc info message")
+		assert.Contains(out, `"component":"rpc"`)
+	})
+
+	t.Run("ComponentLevel", func(t *testing.T) {
+		reg.SetLevel("rpc", Debug)
+		rpc.WithField("method", "ping").Debug("rpc debug message")
+		db.Debug("db debug message")
+		out := sink.flush()
+		assert.Contains(out, "rpc debug message")
+		assert.NotContains(out, "db debug message")
+		assert.Equal(Debug, reg.Level("rpc"))
+		assert.Equal(Info, reg.Level("db"))
+
+		// sub-loggers share the component settings
+		sub := rpc.Sub(Fields{"peer": "127.0.0.1"})
+		sub.Debug("sub debug message")
+		assert.Contains(sink.flush(), "sub debug message")
+
+		// adjust level using the component logger
+		db.SetLevel(Error)
+		db.Warning("db warning message")
+		assert.Empty(sink.flush())
+		assert.Equal(Error, reg.Levels()["db"])
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		reg.Reset("rpc")
+		reg.SetDefaultLevel(Warning)
+		rpc.Info("rpc info message")
+		assert.Empty(sink.flush())
+		assert.Equal(Warning, reg.Level("rpc"))
+		reg.SetDefaultLevel(Info)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		srv := httptest.NewServer(reg)
+		defer srv.Close()
+
+		put := func(body string) (*http.Response, error) {
+			req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader(body))
+			return http.DefaultClient.Do(req)
+		}
+
+		res, err := put(`{"component":"db","level":"debug"}`)
+		assert.Nil(err)
+		assert.Equal(http.StatusOK, res.StatusCode)
+		_ = res.Body.Close()
+		assert.Equal(Debug, reg.Level("db"))
+		db.Debug("db debug message")
+		assert.Contains(sink.flush(), "db debug message")
+
+		res, err = put(`{"component":"db","level":"verbose"}`)
+		assert.Nil(err)
+		assert.Equal(http.StatusBadRequest, res.StatusCode)
+		_ = res.Body.Close()
+
+		res, err = put(`{"level":"error"}`)
+		assert.Nil(err)
+		_ = res.Body.Close()
+		assert.Equal(Error, reg.DefaultLevel())
+
+		res, err = http.Get(srv.URL)
+		assert.Nil(err)
+		report := struct {
+			Default    string `json:"default"`
+			Components []struct {
+				Name     string `json:"name"`
+				Level    string `json:"level"`
+				Explicit bool   `json:"explicit"`
+			} `json:"components"`
+		}{}
+		assert.Nil(json.NewDecoder(res.Body).Decode(&report))
+		_ = res.Body.Close()
+		assert.Equal("error", report.Default)
+		assert.Len(report.Components, 2)
+		assert.Equal("db", report.Components[0].Name)
+		assert.Equal("debug", report.Components[0].Level)
+		assert.True(report.Components[0].Explicit)
+		assert.Equal("rpc", report.Components[1].Name)
+		assert.Equal("error", report.Components[1].Level)
+	})
+}
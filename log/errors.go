@@ -0,0 +1,22 @@
+package log
+
+import (
+	"go.bryk.io/pkg/errors"
+)
+
+// ErrorFields returns the structured attributes attached to `err` using
+// 'errors.WithFields', along with the error message on the "error" key.
+// Useful to include error details on log messages.
+//
+//	log.WithFields(ErrorFields(err)).Error("operation failed")
+func ErrorFields(err error) Fields {
+	fields := Fields{}
+	if err == nil {
+		return fields
+	}
+	for k, v := range errors.Fields(err).Values() {
+		fields[k] = v
+	}
+	fields["error"] = err.Error()
+	return fields
+}
@@ -0,0 +1,18 @@
+package log
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/metadata"
+)
+
+func TestErrorFields(t *testing.T) {
+	assert := tdd.New(t)
+	err := errors.WithFields(errors.New("failed"), metadata.FromMap(Fields{"user": "rick"}))
+	fields := ErrorFields(errors.Wrap(err, "request"))
+	assert.Equal("rick", fields["user"])
+	assert.Equal("request: failed", fields["error"])
+	assert.Empty(ErrorFields(nil))
+}
@@ -0,0 +1,354 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Format used to timestamp rotated log files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Extension used for compressed log files.
+const compressSuffix = ".gz"
+
+// FileOptions defines the settings available to write log messages to a
+// local file with automatic rotation.
+type FileOptions struct {
+	// Location of the log file. Rotated files are stored in the same
+	// directory, using the original name with a timestamp. For example:
+	// `server-2024-11-04T18-30-00.000.log`.
+	Path string `json:"path" yaml:"path" mapstructure:"path"`
+
+	// Maximum size, in megabytes, of the log file before it gets rotated.
+	// Defaults to 100 megabytes.
+	MaxSize int `json:"max_size" yaml:"max_size" mapstructure:"max_size"`
+
+	// Maximum time to retain rotated files. By default, files are not
+	// removed based on age.
+	MaxAge time.Duration `json:"max_age" yaml:"max_age" mapstructure:"max_age"`
+
+	// Maximum number of rotated files to retain. By default, all rotated
+	// files are retained (subject to `MaxAge`).
+	MaxBackups int `json:"max_backups" yaml:"max_backups" mapstructure:"max_backups"`
+
+	// Whether rotated files should be compressed using gzip.
+	Compress bool `json:"compress" yaml:"compress" mapstructure:"compress"`
+}
+
+// RotatingFile is an `io.WriteCloser` that writes to a local file, rotating
+// it when the maximum size is reached. Old files are removed based on the
+// retention settings provided and can be optionally compressed. The file
+// is opened, or created, on the first write operation.
+type RotatingFile struct {
+	opts    FileOptions
+	file    *os.File
+	size    int64
+	mu      sync.Mutex
+	mill    chan struct{}
+	done    chan struct{}
+	millMu  sync.Mutex
+	startMu sync.Once
+	closeMu sync.Once
+}
+
+// NewRotatingFile returns a new file writer with rotation support.
+func NewRotatingFile(opts FileOptions) (*RotatingFile, error) {
+	if opts.Path == "" {
+		return nil, errors.New("a file path is required")
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 100
+	}
+	return &RotatingFile{
+		opts: opts,
+		mill: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}, nil
+}
+
+// Write the provided content to the log file. If the write would cause the
+// file to exceed the maximum size, it is rotated first.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if int64(len(p)) > rf.maxSize() {
+		return 0, errors.Errorf("write length %d exceeds the maximum file size", len(p))
+	}
+	if rf.file == nil {
+		if err := rf.openExistingOrNew(len(p)); err != nil {
+			return 0, err
+		}
+	}
+	if rf.size+int64(len(p)) > rf.maxSize() {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current file, moves it aside with a timestamp in the
+// name and starts a new one. Useful to rotate files on external signals,
+// like SIGHUP.
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.rotate()
+}
+
+// Close the current file and stop background maintenance tasks.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	rf.closeMu.Do(func() {
+		close(rf.done)
+	})
+
+	// wait for any maintenance task in progress
+	rf.millMu.Lock()
+	rf.millMu.Unlock() // nolint: staticcheck
+	return rf.closeFile()
+}
+
+func (rf *RotatingFile) maxSize() int64 {
+	return int64(rf.opts.MaxSize) * 1024 * 1024
+}
+
+// Open the log file if it exists and there's enough space available,
+// otherwise rotate it. Must be called while holding the lock.
+func (rf *RotatingFile) openExistingOrNew(writeLen int) error {
+	info, err := os.Stat(rf.opts.Path)
+	if os.IsNotExist(err) {
+		return rf.openNew()
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to inspect log file")
+	}
+	if info.Size()+int64(writeLen) >= rf.maxSize() {
+		return rf.rotate()
+	}
+	file, err := os.OpenFile(rf.opts.Path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return rf.openNew()
+	}
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// Move the existing file aside, if any, and create a new one. Must be
+// called while holding the lock.
+func (rf *RotatingFile) openNew() error {
+	if err := os.MkdirAll(filepath.Dir(rf.opts.Path), 0o750); err != nil {
+		return errors.Wrap(err, "failed to create log directory")
+	}
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(rf.opts.Path); err == nil {
+		mode = info.Mode()
+		if err := os.Rename(rf.opts.Path, rf.backupName(time.Now())); err != nil {
+			return errors.Wrap(err, "failed to rotate log file")
+		}
+	}
+	file, err := os.OpenFile(rf.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log file")
+	}
+	rf.file = file
+	rf.size = 0
+	return nil
+}
+
+// Must be called while holding the lock.
+func (rf *RotatingFile) rotate() error {
+	if err := rf.closeFile(); err != nil {
+		return err
+	}
+	if err := rf.openNew(); err != nil {
+		return err
+	}
+	rf.startMu.Do(func() {
+		go rf.maintenance()
+	})
+	select {
+	case rf.mill <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Must be called while holding the lock.
+func (rf *RotatingFile) closeFile() error {
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+// Name used for a rotated file. The timestamp is adjusted if required to
+// avoid overwriting an existing backup, for example when rotating several
+// times within the same millisecond.
+func (rf *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := rf.nameParts()
+	for {
+		name := filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+		if !exists(name) && !exists(name+compressSuffix) {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return !os.IsNotExist(err)
+}
+
+// Directory, rotated files prefix and extension.
+func (rf *RotatingFile) nameParts() (string, string, string) {
+	dir := filepath.Dir(rf.opts.Path)
+	name := filepath.Base(rf.opts.Path)
+	ext := filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// Background task applying the retention and compression settings
+// after every rotation.
+func (rf *RotatingFile) maintenance() {
+	for {
+		select {
+		case <-rf.done:
+			return
+		case <-rf.mill:
+			rf.millMu.Lock()
+			select {
+			case <-rf.done:
+			default:
+				_ = rf.cleanupFiles()
+			}
+			rf.millMu.Unlock()
+		}
+	}
+}
+
+// Logged file rotated previously.
+type backupFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// Apply retention and compression settings to rotated files.
+func (rf *RotatingFile) cleanup() error {
+	rf.millMu.Lock()
+	defer rf.millMu.Unlock()
+	return rf.cleanupFiles()
+}
+
+// Must be called while holding the maintenance lock.
+func (rf *RotatingFile) cleanupFiles() error {
+	backups, err := rf.backups()
+	if err != nil {
+		return err
+	}
+
+	var remove []backupFile
+	if rf.opts.MaxBackups > 0 && len(backups) > rf.opts.MaxBackups {
+		remove = append(remove, backups[rf.opts.MaxBackups:]...)
+		backups = backups[:rf.opts.MaxBackups]
+	}
+	if rf.opts.MaxAge > 0 {
+		cutoff := time.Now().Add(-rf.opts.MaxAge)
+		keep := backups[:0]
+		for _, b := range backups {
+			if b.timestamp.Before(cutoff) {
+				remove = append(remove, b)
+				continue
+			}
+			keep = append(keep, b)
+		}
+		backups = keep
+	}
+	for _, b := range remove {
+		_ = os.Remove(b.path)
+	}
+	if rf.opts.Compress {
+		for _, b := range backups {
+			if !strings.HasSuffix(b.path, compressSuffix) {
+				if err := compressFile(b.path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Rotated files available, sorted from newest to oldest.
+func (rf *RotatingFile) backups() ([]backupFile, error) {
+	dir, prefix, ext := rf.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read log directory")
+	}
+	var list []backupFile
+	for _, el := range entries {
+		name := el.Name()
+		if el.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(name, compressSuffix), ext), prefix)
+		t, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		list = append(list, backupFile{path: filepath.Join(dir, name), timestamp: t})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].timestamp.After(list[j].timestamp)
+	})
+	return list, nil
+}
+
+// Compress a file using gzip and remove the original.
+func compressFile(src string) (err error) {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return errors.Wrap(err, "failed to open log file")
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(src+compressSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create compressed file")
+	}
+	gz := gzip.NewWriter(out)
+	defer func() {
+		if err != nil {
+			_ = os.Remove(src + compressSuffix)
+		}
+	}()
+	if _, err = io.Copy(gz, in); err != nil {
+		_ = out.Close()
+		return errors.Wrap(err, "failed to compress log file")
+	}
+	if err = gz.Close(); err != nil {
+		_ = out.Close()
+		return errors.Wrap(err, "failed to compress log file")
+	}
+	if err = out.Close(); err != nil {
+		return errors.Wrap(err, "failed to compress log file")
+	}
+	return os.Remove(src)
+}
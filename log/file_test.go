@@ -0,0 +1,142 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	assert := tdd.New(t)
+	chunk := bytes.Repeat([]byte("x"), 600*1024)
+
+	t.Run("Validate", func(t *testing.T) {
+		_, err := NewRotatingFile(FileOptions{})
+		assert.NotNil(err, "path is required")
+
+		rf, err := NewRotatingFile(FileOptions{Path: filepath.Join(t.TempDir(), "app.log"), MaxSize: 1})
+		assert.Nil(err)
+		_, err = rf.Write(bytes.Repeat([]byte("x"), 2*1024*1024))
+		assert.NotNil(err, "write larger than max size")
+		assert.Nil(rf.Close())
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		dir := t.TempDir()
+		rf, err := NewRotatingFile(FileOptions{Path: filepath.Join(dir, "app.log"), MaxSize: 1})
+		assert.Nil(err)
+		defer func() {
+			_ = rf.Close()
+		}()
+		for i := 0; i < 3; i++ {
+			n, err := rf.Write(chunk)
+			assert.Nil(err)
+			assert.Equal(len(chunk), n)
+		}
+		backups, err := rf.backups()
+		assert.Nil(err)
+		assert.Len(backups, 2, "backups")
+		info, err := os.Stat(filepath.Join(dir, "app.log"))
+		assert.Nil(err)
+		assert.Equal(int64(len(chunk)), info.Size(), "current file")
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := FileOptions{Path: filepath.Join(dir, "app.log"), MaxSize: 1}
+		rf, _ := NewRotatingFile(opts)
+		_, _ = rf.Write([]byte("first\n"))
+		assert.Nil(rf.Close())
+
+		rf, _ = NewRotatingFile(opts)
+		_, _ = rf.Write([]byte("second\n"))
+		assert.Nil(rf.Close())
+		content, err := os.ReadFile(opts.Path)
+		assert.Nil(err)
+		assert.Equal("first\nsecond\n", string(content), "append to existing file")
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		dir := t.TempDir()
+		rf, _ := NewRotatingFile(FileOptions{
+			Path:       filepath.Join(dir, "app.log"),
+			MaxSize:    1,
+			MaxBackups: 2,
+			Compress:   true,
+		})
+		defer func() {
+			_ = rf.Close()
+		}()
+		for i := 0; i < 5; i++ {
+			_, err := rf.Write(chunk)
+			assert.Nil(err)
+		}
+		assert.Nil(rf.cleanup())
+		backups, err := rf.backups()
+		assert.Nil(err)
+		assert.Len(backups, 2, "max backups")
+		for _, b := range backups {
+			assert.True(strings.HasSuffix(b.path, compressSuffix), "compressed")
+			f, err := os.Open(b.path)
+			assert.Nil(err)
+			gz, err := gzip.NewReader(f)
+			assert.Nil(err)
+			content, err := io.ReadAll(gz)
+			assert.Nil(err)
+			assert.Equal(chunk, content, "compressed content")
+			_ = f.Close()
+		}
+	})
+
+	t.Run("MaxAge", func(t *testing.T) {
+		dir := t.TempDir()
+		rf, _ := NewRotatingFile(FileOptions{
+			Path:    filepath.Join(dir, "app.log"),
+			MaxSize: 1,
+			MaxAge:  time.Hour,
+		})
+		old := rf.backupName(time.Now().Add(-2 * time.Hour))
+		assert.Nil(os.WriteFile(old, []byte("old"), 0o600))
+		_, _ = rf.Write([]byte("current\n"))
+		assert.Nil(rf.Rotate())
+		assert.Nil(rf.cleanup())
+		assert.Nil(rf.Close())
+		_, err := os.Stat(old)
+		assert.True(os.IsNotExist(err), "expired backup removed")
+		backups, _ := rf.backups()
+		assert.Len(backups, 1, "recent backup retained")
+	})
+
+	t.Run("UniqueBackups", func(t *testing.T) {
+		dir := t.TempDir()
+		rf, _ := NewRotatingFile(FileOptions{Path: filepath.Join(dir, "app.log")})
+		for i := 0; i < 10; i++ {
+			_, err := rf.Write([]byte("content\n"))
+			assert.Nil(err)
+			assert.Nil(rf.Rotate())
+		}
+		assert.Nil(rf.Close())
+		backups, err := rf.backups()
+		assert.Nil(err)
+		assert.Len(backups, 10, "backups overwritten")
+	})
+
+	t.Run("Logger", func(t *testing.T) {
+		dir := t.TempDir()
+		rf, err := NewRotatingFile(FileOptions{Path: filepath.Join(dir, "app.log")})
+		assert.Nil(err)
+		ll := WithZero(ZeroOptions{Sink: rf})
+		ll.WithField("component", "test").Info("message stored on file")
+		assert.Nil(rf.Close())
+		content, err := os.ReadFile(filepath.Join(dir, "app.log"))
+		assert.Nil(err)
+		assert.Contains(string(content), "message stored on file")
+	})
+}
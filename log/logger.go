@@ -1,5 +1,11 @@
 package log
 
+import (
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
 // maximum number of fields that can be added to a log entry.
 const maxFields = 50
 
@@ -58,6 +64,27 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel returns the level value for its textual representation,
+// for example: "debug", "info" or "warning". Matching is case-insensitive.
+func ParseLevel(value string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return Debug, nil
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	case "panic":
+		return Panic, nil
+	case "fatal":
+		return Fatal, nil
+	default:
+		return Debug, errors.Errorf("invalid level: '%s'", value)
+	}
+}
+
 // SimpleLogger defines the requirements of the log handler as a minimal
 // interface to allow for easy customization and prevent hard dependencies
 // on a specific implementation. Logs are managed at 6 distinct levels:
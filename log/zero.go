@@ -44,7 +44,8 @@ type ZeroOptions struct {
 
 	// A destination for all produced messages. This can be a file, network
 	// connection, or any other element supporting the `io.Writer` interface.
-	// If no sink is specified `os.Stdout` will be used by default.
+	// Use a `RotatingFile` to write messages to a local file with automatic
+	// rotation. If no sink is specified `os.Stdout` will be used by default.
 	Sink io.Writer
 }
 
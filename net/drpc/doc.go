@@ -75,6 +75,15 @@ For example, to start a typical production server.
 	}
 	srv.Start()
 
+JWT bearer credentials can be validated using the "AuthByJWT" middleware. For
+valid tokens, the token and its decoded claims are available to service handlers.
+
+	validator, _ := jwt.NewValidator(jwt.WithValidationKeys(jwks))
+	auth := srvmw.AuthByJWT("authorization", validator, nil, jwt.IssuerCheck("acme.com"))
+
+	// On the service handler
+	claims, _ := jwt.ClaimsFromContext(ctx)
+
 # Client
 
 This package simplifies the process of running a DRPC client in production
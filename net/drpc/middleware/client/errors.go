@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"storj.io/drpc"
+)
+
+// Marker used by servers to include error reports in the error messages.
+const reportMarker = " ‹report:"
+
+// ErrorDecoding restores the original errors returned by servers using the
+// 'ErrorPropagation' middleware; including wrapped causes, hints, tags, fields,
+// events and, when provided by the server, stacktraces. The restored errors
+// can be used with 'errors.Is' and 'errors.As'.
+func ErrorDecoding() Middleware {
+	return func(next Interceptor) Interceptor {
+		return errorDecoding{next: next}
+	}
+}
+
+type errorDecoding struct {
+	next Interceptor
+}
+
+func (md errorDecoding) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	return decodeError(md.next.Invoke(ctx, rpc, enc, in, out))
+}
+
+func (md errorDecoding) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	st, err := md.next.NewStream(ctx, rpc, enc)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return errorStream{Stream: st}, nil
+}
+
+type errorStream struct {
+	drpc.Stream
+}
+
+func (es errorStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	return decodeError(es.Stream.MsgSend(msg, enc))
+}
+
+func (es errorStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	return decodeError(es.Stream.MsgRecv(msg, enc))
+}
+
+// Restore an error including a report, if possible.
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	i := strings.LastIndex(msg, reportMarker)
+	if i < 0 || !strings.HasSuffix(msg, "›") {
+		return err
+	}
+	report, dErr := base64.RawURLEncoding.DecodeString(strings.TrimSuffix(msg[i+len(reportMarker):], "›"))
+	if dErr != nil {
+		return err
+	}
+	if ok, rec := errors.CodecGRPC().Unmarshal(report); ok {
+		return rec
+	}
+	return err
+}
@@ -2,8 +2,8 @@ package client
 
 import (
 	"context"
-	"fmt"
 
+	"go.bryk.io/pkg/errors"
 	"storj.io/drpc"
 )
 
@@ -27,7 +27,7 @@ type panicRecovery struct {
 func (md panicRecovery) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) (err error) {
 	defer func() {
 		if v := recover(); v != nil {
-			err = fmt.Errorf("%s: %s", md.tag, v)
+			err = errors.Wrap(errors.FromPanic(v), md.tag)
 		}
 	}()
 	err = md.next.Invoke(ctx, rpc, enc, in, out)
@@ -37,7 +37,7 @@ func (md panicRecovery) Invoke(ctx context.Context, rpc string, enc drpc.Encodin
 func (md panicRecovery) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (st drpc.Stream, err error) {
 	defer func() {
 		if v := recover(); v != nil {
-			err = fmt.Errorf("%s: %s", md.tag, v)
+			err = errors.Wrap(errors.FromPanic(v), md.tag)
 		}
 	}()
 	st, err = md.next.NewStream(ctx, rpc, enc)
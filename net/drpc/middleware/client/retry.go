@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"go.bryk.io/pkg/errors"
 	xlog "go.bryk.io/pkg/log"
 	"go.bryk.io/pkg/metadata"
 	"storj.io/drpc"
@@ -14,6 +15,8 @@ import (
 // attempt. Multiple tries introduce an increasingly longer backoff delay to
 // account for transient failures on the remote. The specific delay for each
 // attempt is calculated (in ms) as: `delay * (factor * attempt_number)`.
+// Errors explicitly marked as permanent (see 'errors.MarkPermanent') are
+// returned without further attempts.
 func Retry(limit uint, ll xlog.Logger) Middleware {
 	return func(next Interceptor) Interceptor {
 		return retry{
@@ -47,6 +50,9 @@ func (md retry) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, o
 		if err == nil {
 			return nil
 		}
+		if errors.IsPermanent(err) {
+			return err
+		}
 
 		// Operation fields
 		fields := metadata.FromMap(map[string]interface{}{
@@ -83,6 +89,9 @@ func (md retry) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (d
 		if err == nil {
 			return st, nil
 		}
+		if errors.IsPermanent(err) {
+			return nil, err
+		}
 
 		// Operation fields
 		fields := metadata.FromMap(map[string]interface{}{
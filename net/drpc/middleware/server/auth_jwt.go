@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwt"
+	"google.golang.org/grpc/codes"
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+	"storj.io/drpc/drpcmetadata"
+)
+
+// AuthByJWT validates JWT bearer credentials using the provided validator and
+// checks; time-based checks are always applied. The token must be present in
+// the request's metadata under `key`, optionally prefixed with the "Bearer"
+// scheme. For valid tokens, the token instance and its decoded claims are
+// available to service handlers. `claims` returns a new holder used to decode
+// the token claims, if not provided claims are decoded as '*jwt.RegisteredClaims'.
+// Unsigned tokens are always rejected. Authentication errors are returned with
+// the 'codes.Unauthenticated' error code.
+//
+//	token, _ := jwt.TokenFromContext(ctx)
+//	claims, _ := jwt.ClaimsFromContext(ctx)
+func AuthByJWT(key string, v *jwt.Validator, claims func() interface{}, checks ...jwt.Check) Middleware {
+	return func(next drpc.Handler) drpc.Handler {
+		return authJWT{
+			mKey:   key,
+			val:    v,
+			claims: claims,
+			checks: checks,
+			next:   next,
+		}
+	}
+}
+
+type authJWT struct {
+	mKey   string
+	val    *jwt.Validator
+	claims func() interface{}
+	checks []jwt.Check
+	next   drpc.Handler
+}
+
+func (md authJWT) HandleRPC(stream drpc.Stream, rpc string) (err error) {
+	data, ok := drpcmetadata.Get(stream.Context())
+	if !ok {
+		return unauthenticated("authentication: missing credentials") // no metadata available
+	}
+	token, ok := data[md.mKey]
+	if !ok {
+		return unauthenticated("authentication: missing credentials") // no token set
+	}
+	if scheme, value, found := strings.Cut(token, " "); found && strings.EqualFold(scheme, "bearer") {
+		token = strings.TrimSpace(value)
+	}
+	ctx, err := md.val.Authenticate(stream.Context(), token, md.claims, md.checks...)
+	if err != nil {
+		return unauthenticated("authentication: invalid credentials") // invalid token
+	}
+	return md.next.HandleRPC(authStream{Stream: stream, ctx: ctx}, rpc) // continue
+}
+
+// Authentication error including the 'codes.Unauthenticated' error code.
+func unauthenticated(msg string) error {
+	return drpcerr.WithCode(errors.New(msg), uint64(codes.Unauthenticated))
+}
+
+// Stream wrapper to expose the authenticated context to upstream handlers.
+type authStream struct {
+	drpc.Stream
+	ctx context.Context
+}
+
+func (as authStream) Context() context.Context {
+	return as.ctx
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"go.bryk.io/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
+)
+
+// Marker used to include error reports in the messages returned to clients.
+const reportMarker = " ‹report:"
+
+// ErrorPropagation encodes the structure of the errors returned by service
+// handlers (wrapped causes, hints, tags, fields and events) as part of the
+// error message transmitted to the client. Clients using the 'ErrorDecoding'
+// middleware can restore the original errors and use 'errors.Is' and
+// 'errors.As' on them.
+//
+// The report is only base64-encoded, any peer or intermediary logging the
+// error message has access to its contents. Errors are redacted before
+// encoding (use 'errors.WithStatusRedactor' to adjust the redaction
+// settings) and stacktraces are omitted unless 'errors.WithStackTrace'
+// is provided.
+func ErrorPropagation(opts ...errors.StatusOption) Middleware {
+	return func(next drpc.Handler) drpc.Handler {
+		return errorPropagation{next: next, opts: opts}
+	}
+}
+
+type errorPropagation struct {
+	next drpc.Handler
+	opts []errors.StatusOption
+}
+
+func (md errorPropagation) HandleRPC(stream drpc.Stream, rpc string) error {
+	err := md.next.HandleRPC(stream, rpc)
+	if err == nil {
+		return nil
+	}
+	st := errors.ToStatus(err, codes.Unknown, md.opts...)
+	report, rErr := proto.Marshal(st.Proto())
+	if rErr != nil {
+		return err
+	}
+	msg := fmt.Sprintf("%s%s%s›", st.Message(), reportMarker, base64.RawURLEncoding.EncodeToString(report))
+	return drpcerr.WithCode(errors.New(msg), drpcerr.Code(err))
+}
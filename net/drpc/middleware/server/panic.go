@@ -1,8 +1,7 @@
 package server
 
 import (
-	"fmt"
-
+	"go.bryk.io/pkg/errors"
 	"storj.io/drpc"
 )
 
@@ -26,7 +25,7 @@ type panicRecovery struct {
 func (md panicRecovery) HandleRPC(stream drpc.Stream, rpc string) (err error) {
 	defer func() {
 		if v := recover(); v != nil {
-			err = fmt.Errorf("%s: %s", md.tag, v)
+			err = errors.Wrap(errors.FromPanic(v), md.tag)
 		}
 	}()
 	err = md.next.HandleRPC(stream, rpc)
@@ -2,6 +2,10 @@ package drpc
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -17,15 +21,20 @@ import (
 	"github.com/gorilla/websocket"
 	tdd "github.com/stretchr/testify/assert"
 	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+	"go.bryk.io/pkg/jose/jwt"
 	xlog "go.bryk.io/pkg/log"
 	clMW "go.bryk.io/pkg/net/drpc/middleware/client"
 	srvMW "go.bryk.io/pkg/net/drpc/middleware/server"
 	"go.bryk.io/pkg/net/drpc/ws"
 	sampleV1 "go.bryk.io/pkg/proto/sample/v1"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"storj.io/drpc"
+	"storj.io/drpc/drpcerr"
 )
 
 func TestMain(m *testing.M) {
@@ -42,6 +51,32 @@ func TestMetadata(t *testing.T) {
 	assert.Equal(md["user.id"], "user-123", "invalid value")
 }
 
+func TestErrorPropagation(t *testing.T) {
+	assert := tdd.New(t)
+	errNotFound := errors.New("record not found")
+	handler := errHandler(func() error { return errors.Wrapf(errNotFound, "storage (%s)", "rick@c137.com") })
+
+	// Without error propagation only the message is available
+	cl := clMW.ErrorDecoding()(errInvoker{handler: handler})
+	err := cl.Invoke(context.Background(), "/sample", nil, nil, nil)
+	assert.False(errors.Is(err, errNotFound), "cause analysis")
+
+	// With error propagation
+	cl = clMW.ErrorDecoding()(errInvoker{handler: srvMW.ErrorPropagation()(handler)})
+	err = cl.Invoke(context.Background(), "/sample", nil, nil, nil)
+	assert.True(errors.Is(err, errNotFound), "cause analysis")
+	assert.Equal("storage (‹×›): record not found", err.Error(), "redacted message")
+	var oe *errors.Error
+	assert.True(errors.As(err, &oe), "type casting")
+	assert.Empty(oe.StackTrace(), "stacktrace is omitted by default")
+
+	// Stacktrace is included only when requested
+	cl = clMW.ErrorDecoding()(errInvoker{handler: srvMW.ErrorPropagation(errors.WithStackTrace())(handler)})
+	err = cl.Invoke(context.Background(), "/sample", nil, nil, nil)
+	assert.True(errors.As(err, &oe), "type casting")
+	assert.NotEmpty(oe.StackTrace(), "stacktrace")
+}
+
 func TestPool(t *testing.T) {
 	assert := tdd.New(t)
 
@@ -287,6 +322,55 @@ func TestServer(t *testing.T) {
 		_ = srv.Stop()
 	})
 
+	t.Run("WithTLSSigner", func(t *testing.T) {
+		caCert, _ := os.ReadFile("testdata/ca.sample_cer")
+		cert, _ := os.ReadFile("testdata/server.sample_cer")
+		key, _ := os.ReadFile("testdata/server.sample_key")
+		port, endpoint := getRandomPort()
+
+		// Private key held by an external signer
+		kp, err := tls.X509KeyPair(cert, key)
+		assert.Nil(err, "load key pair")
+		ks, _ := kp.PrivateKey.(crypto.Signer)
+
+		// Signer must match the certificate
+		other, _ := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+		_, err = NewServer(WithPort(port), WithTLS(ServerTLS{Cert: cert, Signer: other}))
+		assert.NotNil(err, "invalid signer")
+
+		// RPC server
+		srv, err := NewServer(
+			WithPort(port),
+			WithServiceProvider(sampleServiceProvider()),
+			WithTLS(ServerTLS{
+				Cert:      cert,
+				Signer:    ks,
+				CustomCAs: [][]byte{caCert},
+			}),
+		)
+		assert.Nil(err, "new server")
+		go func() {
+			_ = srv.Start()
+		}()
+
+		// Client connection
+		cl, err := NewClient("tcp", endpoint, WithClientTLS(ClientTLS{
+			CustomCAs:  [][]byte{caCert},
+			ServerName: "node-01",
+		}))
+		assert.Nil(err, "new client")
+
+		// RPC client
+		client := sampleV1.NewDRPCFooAPIClient(cl)
+		res, err := client.Ping(context.Background(), &emptypb.Empty{})
+		assert.Nil(err, "ping")
+		assert.True(res.Ok, "ping result")
+
+		// Close client connection and stop server
+		assert.Nil(cl.Close(), "close client connection")
+		_ = srv.Stop()
+	})
+
 	t.Run("WithHTTP", func(t *testing.T) {
 		// RPC server
 		port, endpoint := getRandomPort()
@@ -444,6 +528,78 @@ func TestServer(t *testing.T) {
 		_ = srv.Stop()
 	})
 
+	t.Run("WithAuthByJWT", func(t *testing.T) {
+		// Token generator and validator
+		mk, _ := jwk.New(jwa.ES256)
+		tg, _ := jwt.NewGenerator("acme.com", jwt.WithSupportForNone())
+		_ = tg.AddKey(mk)
+		token, _ := tg.Issue(mk.ID(), &jwt.TokenParameters{
+			Subject:  "rick",
+			Audience: []string{"rpc.acme.com"},
+		})
+		unsigned, _ := tg.Issue("none", &jwt.TokenParameters{
+			Subject:  "admin",
+			Audience: []string{"rpc.acme.com"},
+		})
+		tv, _ := jwt.NewValidator(jwt.WithValidationKeys(tg.ExportKeys(true)))
+
+		// Auth middleware
+		auth := srvMW.AuthByJWT("authorization", tv, nil, jwt.AudienceCheck([]string{"rpc.acme.com"}))
+
+		// RPC server
+		port, endpoint := getRandomPort()
+		opts := []Option{
+			WithPort(port),
+			WithServiceProvider(sampleServiceProvider()),
+			WithMiddleware(append(smw, auth)...),
+		}
+		srv, err := NewServer(opts...)
+		assert.Nil(err, "new server")
+		go func() {
+			_ = srv.Start()
+		}()
+
+		// RPC client
+		cl, err := NewClient("tcp", endpoint)
+		assert.Nil(err, "client connection")
+		client := sampleV1.NewDRPCFooAPIClient(cl)
+
+		// No credentials
+		_, err = client.Ping(context.Background(), &emptypb.Empty{})
+		assert.NotNil(err, "invalid auth")
+		assert.Equal(err.Error(), "authentication: missing credentials")
+
+		// Invalid credentials
+		ctx := ContextWithMetadata(context.Background(), map[string]string{
+			"authorization": "Bearer invalid-credentials",
+		})
+		_, err = client.Ping(ctx, &emptypb.Empty{})
+		assert.NotNil(err, "invalid auth")
+		assert.Equal(err.Error(), "authentication: invalid credentials")
+		assert.Equal(uint64(codes.Unauthenticated), drpcerr.Code(err))
+
+		// Unsigned token
+		ctx = ContextWithMetadata(context.Background(), map[string]string{
+			"authorization": "Bearer " + unsigned.String(),
+		})
+		_, err = client.Ping(ctx, &emptypb.Empty{})
+		assert.NotNil(err, "unsigned token")
+		assert.Equal(uint64(codes.Unauthenticated), drpcerr.Code(err))
+
+		// Authenticated
+		ctx = ContextWithMetadata(context.Background(), map[string]string{
+			"authorization": "Bearer " + token.String(),
+		})
+		_, err = client.Ping(ctx, &emptypb.Empty{})
+		assert.Nil(err, "invalid auth")
+
+		// Close client connection
+		assert.Nil(cl.Close(), "close client connection")
+
+		// Stop server
+		_ = srv.Stop()
+	})
+
 	t.Run("WithAuthByCertificate", func(t *testing.T) {
 		// Load sample credentials
 		port, endpoint := getRandomPort()
@@ -853,3 +1009,27 @@ func getRandomPort() (uint, string) {
 	port += uint(rand.Intn(122))
 	return port, fmt.Sprintf(":%d", port)
 }
+
+// Handler returning the error produced by the provided function.
+type errHandler func() error
+
+func (h errHandler) HandleRPC(_ drpc.Stream, _ string) error {
+	return h()
+}
+
+// Client interceptor simulating the transmission of errors over the wire;
+// only the error message is preserved.
+type errInvoker struct {
+	handler drpc.Handler
+}
+
+func (ei errInvoker) Invoke(_ context.Context, rpc string, _ drpc.Encoding, _, _ drpc.Message) error {
+	if err := ei.handler.HandleRPC(nil, rpc); err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func (ei errInvoker) NewStream(_ context.Context, _ string, _ drpc.Encoding) (drpc.Stream, error) {
+	return nil, errors.New("not supported")
+}
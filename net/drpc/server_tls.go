@@ -1,8 +1,11 @@
 package drpc
 
 import (
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
+
+	"go.bryk.io/pkg/crypto/signer"
 )
 
 // RecommendedCiphers provides a default list of secure/modern ciphers.
@@ -31,6 +34,10 @@ type ServerTLS struct {
 	// Server private key, PEM-encoded.
 	PrivateKey []byte
 
+	// Signer used as server private key instead of 'PrivateKey'; for
+	// example, a key stored in an HSM. See the "crypto/signer" package.
+	Signer crypto.Signer
+
 	// List of ciphers to allow.
 	SupportedCiphers []uint16
 
@@ -47,7 +54,15 @@ type ServerTLS struct {
 // Generate a proper TLS configuration to use on the server.
 func (opts ServerTLS) conf() (*tls.Config, error) {
 	// Load key/pair
-	cert, err := tls.X509KeyPair(opts.Cert, opts.PrivateKey)
+	var (
+		cert tls.Certificate
+		err  error
+	)
+	if opts.Signer != nil {
+		cert, err = signer.TLSCertificate(opts.Cert, opts.Signer)
+	} else {
+		cert, err = tls.X509KeyPair(opts.Cert, opts.PrivateKey)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -19,5 +19,16 @@ and manage an HTTP(S) server instance.
 
 	// When no longer required, gracefully stop the server
 	_ = server.Stop(true)
+
+Handlers can return errors instead of writing error responses directly. Returned
+errors are rendered as RFC-7807 problem details documents.
+
+	mux.Handle("/records", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		rec, err := store.Get(r.URL.Query().Get("id"))
+		if err != nil {
+			return err // i.e. errors.MarkCategory(err, errors.NotFound)
+		}
+		return json.NewEncoder(w).Encode(rec)
+	}))
 */
 package http
@@ -0,0 +1,21 @@
+package http
+
+import (
+	lib "net/http"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// HandlerFunc adapts a function returning an error to be used as an HTTP
+// handler. Returned errors are rendered as problem details documents, as
+// described in RFC-7807, with a status code based on the error markers
+// (see 'errors.HTTPStatus'). Handlers returning an error must not write
+// a response.
+type HandlerFunc func(lib.ResponseWriter, *lib.Request) error
+
+// ServeHTTP calls fn(w, r) and renders the returned error, if any.
+func (fn HandlerFunc) ServeHTTP(w lib.ResponseWriter, r *lib.Request) {
+	if err := fn(w, r); err != nil {
+		errors.WriteProblem(w, r, err)
+	}
+}
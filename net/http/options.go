@@ -4,6 +4,8 @@ import (
 	"fmt"
 	lib "net/http"
 	"time"
+
+	otelProm "go.bryk.io/pkg/otel/prometheus"
 )
 
 // Option allows adjusting server settings following a functional pattern.
@@ -70,3 +72,20 @@ func WithMiddleware(md ...func(lib.Handler) lib.Handler) Option {
 		return nil
 	}
 }
+
+// WithPrometheus allows generating and consuming metrics from the server
+// instance using the Prometheus standards and tooling. All requests handled
+// are instrumented, and if `path` is not empty the collected metrics are
+// exposed on it; requests to the metrics endpoint are not instrumented.
+//
+//	prom, _ := otelProm.NewOperator(prometheus.NewRegistry())
+//	srv, _ := NewServer(WithHandler(router), WithPrometheus(prom, "/metrics"))
+func WithPrometheus(prometheus otelProm.Operator, path string) Option {
+	return func(srv *Server) error {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.prometheus = prometheus
+		srv.metricsPath = path
+		return nil
+	}
+}
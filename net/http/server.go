@@ -6,6 +6,8 @@ import (
 	lib "net/http"
 	"sync"
 	"time"
+
+	otelProm "go.bryk.io/pkg/otel/prometheus"
 )
 
 // Server provides the main HTTP(S) service provider.
@@ -16,6 +18,9 @@ type Server struct {
 	mu   sync.Mutex
 	tls  *tls.Config
 	port int
+
+	prometheus  otelProm.Operator // Prometheus support
+	metricsPath string            // Prometheus metrics endpoint
 }
 
 // NewServer returns a new read-to-use server instance adjusted with the
@@ -39,10 +44,21 @@ func NewServer(options ...Option) (*Server, error) {
 		}
 	}
 
+	// Setup prometheus metrics before any functional middleware; this
+	// allows exemplars to be linked to the trace started by middleware.
+	if srv.prometheus != nil && srv.sh != nil {
+		srv.sh = srv.prometheus.HTTPMiddleware()(srv.sh)
+	}
+
 	// Apply middleware
 	for _, mw := range srv.mw {
 		srv.sh = mw(srv.sh)
 	}
+
+	// Expose collected metrics
+	if srv.prometheus != nil && srv.metricsPath != "" {
+		srv.sh = metricsEndpoint(srv.metricsPath, srv.prometheus.MetricsHandler(), srv.sh)
+	}
 	return srv, nil
 }
 
@@ -65,3 +81,18 @@ func (srv *Server) Stop(graceful bool) error {
 	}
 	return srv.nh.Shutdown(context.Background())
 }
+
+// Serve requests to `path` using the metrics handler `mh`; all other requests
+// are forwarded to `next`, or the default mux if no handler was provided.
+func metricsEndpoint(path string, mh, next lib.Handler) lib.Handler {
+	if next == nil {
+		next = lib.DefaultServeMux
+	}
+	return lib.HandlerFunc(func(w lib.ResponseWriter, r *lib.Request) {
+		if r.URL.Path == path {
+			mh.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
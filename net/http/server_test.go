@@ -6,6 +6,7 @@ import (
 	"io"
 	"math/rand"
 	lib "net/http"
+	"net/http/httptest"
 	"net/http/httputil"
 	"os"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
 	xlog "go.bryk.io/pkg/log"
 	mwGzip "go.bryk.io/pkg/net/middleware/gzip"
 	mwHeaders "go.bryk.io/pkg/net/middleware/headers"
@@ -180,3 +182,25 @@ func getRandomPort() (int, string) {
 	port += rand.Intn(122)
 	return port, fmt.Sprintf("http://localhost:%d", port)
 }
+
+func TestHandlerFunc(t *testing.T) {
+	assert := tdd.New(t)
+	h := HandlerFunc(func(w lib.ResponseWriter, r *lib.Request) error {
+		if r.URL.Query().Get("id") == "" {
+			return errors.MarkCategory(errors.New("record not found"), errors.NotFound)
+		}
+		_, err := w.Write([]byte("ok"))
+		return err
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(lib.MethodGet, "/records?id=123", nil))
+	assert.Equal(lib.StatusOK, rec.Code)
+	assert.Equal("ok", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(lib.MethodGet, "/records", nil))
+	assert.Equal(lib.StatusNotFound, rec.Code)
+	assert.Equal(errors.ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(rec.Body.String(), `"detail":"record not found"`)
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 
+	"go.bryk.io/pkg/crypto/signer"
 	"go.bryk.io/pkg/errors"
 )
 
@@ -15,6 +17,10 @@ type TLS struct {
 	// Server private key, PEM-encoded.
 	PrivateKey []byte
 
+	// Signer used as server private key instead of 'PrivateKey'; for
+	// example, a key stored in an HSM. See the "crypto/signer" package.
+	Signer crypto.Signer
+
 	// List of ciphers to allow.
 	SupportedCiphers []uint16
 
@@ -50,7 +56,15 @@ var recommendedCurves = []tls.CurveID{
 // settings.
 func (t TLS) Expand() (*tls.Config, error) {
 	// Load key/pair
-	cert, err := tls.X509KeyPair(t.Cert, t.PrivateKey)
+	var (
+		cert tls.Certificate
+		err  error
+	)
+	if t.Signer != nil {
+		cert, err = signer.TLSCertificate(t.Cert, t.Signer)
+	} else {
+		cert, err = tls.X509KeyPair(t.Cert, t.PrivateKey)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load key pair")
 	}
@@ -1,8 +1,9 @@
 package recovery
 
 import (
-	"fmt"
 	"net/http"
+
+	"go.bryk.io/pkg/errors"
 )
 
 // Handler allows the server to convert unhandled panic events into an
@@ -14,7 +15,7 @@ func Handler() func(handler http.Handler) http.Handler {
 			defer func() {
 				if v := recover(); v != nil {
 					w.WriteHeader(http.StatusInternalServerError)
-					_, _ = w.Write([]byte(fmt.Sprintf("%s", v)))
+					_, _ = w.Write([]byte(errors.FromPanic(v).Error()))
 				}
 			}()
 			next.ServeHTTP(w, r)
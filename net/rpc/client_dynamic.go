@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.bryk.io/pkg/errors"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// DynamicClient allows to invoke the methods exposed by a server without
+// access to its generated client code. Services and message types are
+// discovered at runtime using the server reflection protocol; requests and
+// responses are exchanged as JSON documents. Only unary methods are supported.
+//
+// The server must have reflection enabled, refer to `WithReflection`.
+type DynamicClient struct {
+	cc       grpc.ClientConnInterface
+	files    *protoregistry.Files
+	services []protoreflect.ServiceDescriptor
+	mu       sync.Mutex
+}
+
+// NewDynamicClient returns a dynamic client using the provided connection.
+func NewDynamicClient(cc grpc.ClientConnInterface) *DynamicClient {
+	return &DynamicClient{cc: cc}
+}
+
+// Services returns the descriptors for all the services exposed by the
+// server, excluding the reflection service. The results are cached after
+// the first successful request; use `Refresh` to load them again.
+func (dc *DynamicClient) Services(ctx context.Context) ([]protoreflect.ServiceDescriptor, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.files == nil {
+		if err := dc.load(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return append([]protoreflect.ServiceDescriptor{}, dc.services...), nil
+}
+
+// Refresh discards the cached service descriptors.
+func (dc *DynamicClient) Refresh() {
+	dc.mu.Lock()
+	dc.files = nil
+	dc.services = nil
+	dc.mu.Unlock()
+}
+
+// Method returns the descriptor for a method using its full name, either
+// as "package.Service/Method" or "package.Service.Method".
+func (dc *DynamicClient) Method(ctx context.Context, name string) (protoreflect.MethodDescriptor, error) {
+	if _, err := dc.Services(ctx); err != nil {
+		return nil, err
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	name = strings.ReplaceAll(strings.TrimPrefix(name, "/"), "/", ".")
+	desc, err := dc.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, errors.Errorf("unknown method: %s", name)
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, errors.Errorf("not a method: %s", name)
+	}
+	return md, nil
+}
+
+// Invoke executes a unary method using the provided JSON-encoded request,
+// and returns the JSON-encoded response. Call options can be adjusted on
+// the client connection.
+func (dc *DynamicClient) Invoke(ctx context.Context, method string, input []byte) ([]byte, error) {
+	md, err := dc.Method(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, errors.Errorf("streaming methods are not supported: %s", md.FullName())
+	}
+
+	// Decode request
+	req := dynamicpb.NewMessage(md.Input())
+	if len(strings.TrimSpace(string(input))) > 0 {
+		dec := protojson.UnmarshalOptions{Resolver: dc.resolver()}
+		if err := dec.Unmarshal(input, req); err != nil {
+			return nil, errors.Wrap(err, "invalid request")
+		}
+	}
+
+	// Submit request
+	res := dynamicpb.NewMessage(md.Output())
+	path := "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+	if err := dc.cc.Invoke(ctx, path, req, res); err != nil {
+		return nil, err
+	}
+	enc := protojson.MarshalOptions{Resolver: dc.resolver(), EmitUnpopulated: true}
+	return enc.Marshal(res)
+}
+
+// Type resolver used for `Any` values.
+func (dc *DynamicClient) resolver() *dynamicpb.Types {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return dynamicpb.NewTypes(dc.files)
+}
+
+// Load service descriptors using the server reflection protocol. Must be
+// called while holding the lock.
+func (dc *DynamicClient) load(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := rpb.NewServerReflectionClient(dc.cc).ServerReflectionInfo(ctx)
+	if err != nil {
+		return errors.Wrap(err, "reflection not available")
+	}
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+
+	// List services
+	res, err := reflectionRequest(stream, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, svc := range res.GetListServicesResponse().GetService() {
+		if !strings.HasPrefix(svc.GetName(), "grpc.reflection.") {
+			names = append(names, svc.GetName())
+		}
+	}
+	sort.Strings(names)
+
+	// Get file descriptors, including dependencies
+	fds := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, name := range names {
+		res, err = reflectionRequest(stream, &rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: name},
+		})
+		if err != nil {
+			return err
+		}
+		if err = collectFiles(stream, res, fds); err != nil {
+			return err
+		}
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range fds {
+		set.File = append(set.File, fd)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return errors.Wrap(err, "invalid service descriptors")
+	}
+
+	// Locate services
+	var services []protoreflect.ServiceDescriptor
+	for _, name := range names {
+		desc, err := files.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return errors.Wrapf(err, "service not found: %s", name)
+		}
+		if sd, ok := desc.(protoreflect.ServiceDescriptor); ok {
+			services = append(services, sd)
+		}
+	}
+	dc.files = files
+	dc.services = services
+	return nil
+}
+
+// Register the file descriptors included in a reflection response, and
+// request any missing dependency.
+func collectFiles(stream rpb.ServerReflection_ServerReflectionInfoClient, res *rpb.ServerReflectionResponse, fds map[string]*descriptorpb.FileDescriptorProto) error { // nolint:lll
+	var added []*descriptorpb.FileDescriptorProto
+	for _, raw := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(raw, fd); err != nil {
+			return errors.Wrap(err, "invalid file descriptor")
+		}
+		if _, ok := fds[fd.GetName()]; !ok {
+			fds[fd.GetName()] = fd
+			added = append(added, fd)
+		}
+	}
+	for _, fd := range added {
+		for _, dep := range fd.GetDependency() {
+			if _, ok := fds[dep]; ok {
+				continue
+			}
+			res, err := reflectionRequest(stream, &rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err == nil {
+				if err = collectFiles(stream, res, fds); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// use the local registry as fallback, e.g. for well-known types
+			local, lErr := protoregistry.GlobalFiles.FindFileByPath(dep)
+			if lErr != nil {
+				return errors.Wrapf(err, "dependency not available: %s", dep)
+			}
+			fds[dep] = protodesc.ToFileDescriptorProto(local)
+		}
+	}
+	return nil
+}
+
+// Submit a request using the reflection stream and wait for its response.
+func reflectionRequest(stream rpb.ServerReflection_ServerReflectionInfoClient, req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) { // nolint:lll
+	if err := stream.Send(req); err != nil {
+		return nil, errors.Wrap(err, "reflection request failed")
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		return nil, errors.Wrap(err, "reflection request failed")
+	}
+	if er := res.GetErrorResponse(); er != nil {
+		return nil, errors.New(er.GetErrorMessage())
+	}
+	return res, nil
+}
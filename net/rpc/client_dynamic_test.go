@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthV1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func TestDynamicClient(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Server with reflection enabled
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	hs.SetServingStatus("sample", healthV1.HealthCheckResponse_NOT_SERVING)
+	healthV1.RegisterHealthServer(srv, hs)
+	reflection.Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+
+	cc, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(err)
+	defer func() {
+		_ = cc.Close()
+	}()
+	ctx := context.Background()
+	dc := NewDynamicClient(cc)
+
+	// Discover services
+	services, err := dc.Services(ctx)
+	assert.Nil(err)
+	assert.Len(services, 1)
+	assert.Equal("grpc.health.v1.Health", string(services[0].FullName()))
+
+	// Invoke methods
+	res, err := dc.Invoke(ctx, "grpc.health.v1.Health/Check", []byte(`{"service": "sample"}`))
+	assert.Nil(err)
+	assert.JSONEq(`{"status": "NOT_SERVING"}`, string(res))
+	_, err = dc.Invoke(ctx, "/grpc.health.v1.Health/Check", []byte(`{"invalid": true}`))
+	assert.NotNil(err, "invalid request")
+	_, err = dc.Invoke(ctx, "grpc.health.v1.Health.Watch", nil)
+	assert.NotNil(err, "streaming method")
+	_, err = dc.Invoke(ctx, "grpc.health.v1.Health/Unknown", nil)
+	assert.NotNil(err, "unknown method")
+	dc.Refresh()
+	_, err = dc.Method(ctx, "grpc.health.v1.Health/Watch")
+	assert.Nil(err)
+}
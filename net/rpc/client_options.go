@@ -110,6 +110,20 @@ func WithCompression() ClientOption {
 	}
 }
 
+// WithErrorDecoding will restore the original errors returned by servers using
+// the 'WithErrorPropagation' option; including wrapped causes, hints, tags, fields,
+// events and, when provided by the server, stacktraces. The restored errors can be
+// used with 'errors.Is' and 'errors.As'.
+func WithErrorDecoding() ClientOption {
+	return func(c *Client) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.middlewareUnary = append(c.middlewareUnary, errUnaryClientInterceptor())
+		c.middlewareStream = append(c.middlewareStream, errStreamClientInterceptor())
+		return nil
+	}
+}
+
 // WithDialOptions will set additional gRPC dial options to be used by the client instance.
 func WithDialOptions(opts ...grpc.DialOption) ClientOption {
 	return func(c *Client) (err error) {
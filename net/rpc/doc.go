@@ -67,6 +67,36 @@ your application and passing it along using the "WithServiceProvider" option.
 		WithServiceProvider(&echoProvider{}),
 	}
 
+# Authentication
+
+Requests can be automatically authenticated using JWT bearer credentials. Tokens are
+validated using a "jwt.Validator" instance; for valid tokens, the token and its decoded
+claims are available to service handlers.
+
+	validator, _ := jwt.NewValidator(jwt.WithValidationKeys(jwks))
+	serverOpts = append(serverOpts, WithAuthByJWT(validator, nil, jwt.IssuerCheck("acme.com")))
+
+	// On the service handler
+	claims, _ := jwt.ClaimsFromContext(ctx)
+
+# Error Propagation
+
+By default, only the error message and status code are transmitted to clients. The
+error structure (wrapped causes, hints, tags, fields and events) can be preserved
+across service boundaries, allowing clients to use "errors.Is" and "errors.As" on
+the returned errors. Errors are redacted before being transmitted and stacktraces
+are only included when requested using "errors.WithStackTrace".
+
+	// On the server
+	serverOpts = append(serverOpts, WithErrorPropagation())
+
+	// On the client
+	clientOpts = append(clientOpts, WithErrorDecoding())
+
+Errors returned by the HTTP gateway can be rendered as RFC-7807 problem details
+documents using the "WithProblemDetails" gateway option; HTTP status codes are
+selected based on the error classification markers.
+
 # Client
 
 In order to interact with an RPC server and access the provided functionality you need
@@ -134,6 +164,13 @@ the provided context.
 		fmt.Printf("connection state: %s", state)
 	}
 
+For servers with reflection enabled, a 'DynamicClient' can be used to discover the services
+available and invoke unary methods at runtime, without access to the generated client code.
+Requests and responses are exchanged as JSON documents.
+
+	dc := NewDynamicClient(conn)
+	res, err := dc.Invoke(ctx, "grpc.health.v1.Health/Check", []byte(`{"service": "api"}`))
+
 For more information about functional style configuration options check the original article
 by Dave Cheney: https://dave.cheney.net/2014/10/17/functional-options-for-friendly-apis.
 */
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+
+	mwRecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"go.bryk.io/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Encode errors returned by the server including its structure as status
+// details. Status errors are returned as-is.
+func encodeError(err error, opts ...errors.StatusOption) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok { // nolint: errorlint
+		return err
+	}
+	return errors.ToStatus(err, codes.Unknown, opts...).Err()
+}
+
+// Convert panic events into 'Internal' status errors. When error propagation
+// is enabled, the status includes the structure of the panic error; and, if
+// requested, the stacktrace of the panicking goroutine.
+func recoverError(propagate bool, opts ...errors.StatusOption) mwRecovery.RecoveryHandlerFunc {
+	return func(p interface{}) error {
+		if !propagate {
+			return status.Errorf(codes.Internal, "%v", p)
+		}
+		return errors.ToStatus(errors.FromPanic(p), codes.Internal, opts...).Err()
+	}
+}
+
+// Restore errors encoded by the server, if possible.
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return errors.FromStatus(st)
+	}
+	return err
+}
+
+func errUnaryServerInterceptor(opts ...errors.StatusOption) grpc.UnaryServerInterceptor {
+	// nolint: lll
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		res, err := handler(ctx, req)
+		return res, encodeError(err, opts...)
+	}
+}
+
+func errStreamServerInterceptor(opts ...errors.StatusOption) grpc.StreamServerInterceptor {
+	// nolint: lll
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return encodeError(handler(srv, stream), opts...)
+	}
+}
+
+func errUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	// nolint: lll
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return decodeError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+func errStreamClientInterceptor() grpc.StreamClientInterceptor {
+	// nolint: lll
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, decodeError(err)
+		}
+		return &errClientStream{ClientStream: cs}, nil
+	}
+}
+
+type errClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errClientStream) SendMsg(m interface{}) error {
+	return decodeError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errClientStream) RecvMsg(m interface{}) error {
+	return decodeError(s.ClientStream.RecvMsg(m))
+}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"go.bryk.io/pkg/errors"
 	otelHttp "go.bryk.io/pkg/otel/http"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 // Invalid HTTP2 headers
@@ -118,3 +120,19 @@ func isHeaderValid(header string) bool {
 	}
 	return true
 }
+
+// Render error responses as RFC-7807 problem details documents.
+func problemDetailsHandler(
+	_ context.Context,
+	_ *gwRuntime.ServeMux,
+	_ gwRuntime.Marshaler,
+	res http.ResponseWriter,
+	req *http.Request,
+	err error) {
+	if st, ok := status.FromError(err); ok {
+		if rec := errors.FromStatus(st); rec != nil {
+			err = rec
+		}
+	}
+	errors.WriteProblem(res, req, err)
+}
@@ -141,6 +141,14 @@ func WithUnaryErrorHandler(eh GatewayUnaryErrorHandler) GatewayOption {
 	}
 }
 
+// WithProblemDetails renders all unary error responses returned by the gateway
+// as problem details documents, as described in RFC-7807. Error reports included
+// by servers using 'WithErrorPropagation' are used to select the HTTP status
+// code. This option replaces any handler set with 'WithUnaryErrorHandler'.
+func WithProblemDetails() GatewayOption {
+	return WithUnaryErrorHandler(problemDetailsHandler)
+}
+
 // WithPrettyJSON provides a convenient mechanism to enable pretty printed JSON
 // responses for requests with a specific content-type header. A usual value to
 // use is `application/json+pretty`.
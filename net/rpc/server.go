@@ -64,6 +64,8 @@ type Server struct {
 	protoValidator   *protovalidate.Validator       // Protobuf validator (based on reflection)
 	resourceLimits   ResourceLimits                 // Settings to prevent resources abuse
 	panicRecovery    bool                           // Enable panic recovery interceptor
+	errorPropagation bool                           // Encode error details on status responses
+	errorOpts        []errors.StatusOption          // Settings used to encode error details
 	inputValidation  bool                           // Enable automatic input validation
 	reflection       bool                           // Enable server reflection protocol
 	healthCheck      HealthCheck                    // Enable health checks
@@ -219,6 +221,8 @@ func (srv *Server) reset() {
 	srv.tlsConfig = nil
 	srv.clientCAs = [][]byte{}
 	srv.panicRecovery = false
+	srv.errorPropagation = false
+	srv.errorOpts = nil
 	srv.inputValidation = false
 	srv.gateway = nil
 	srv.opts = []grpc.ServerOption{}
@@ -456,6 +460,12 @@ func (srv *Server) getMiddleware() (unary []grpc.UnaryServerInterceptor, stream
 		stream = append(stream, si)
 	}
 
+	// If enabled, encode errors produced by all other operational middleware
+	if srv.errorPropagation {
+		unary = append(unary, errUnaryServerInterceptor(srv.errorOpts...))
+		stream = append(stream, errStreamServerInterceptor(srv.errorOpts...))
+	}
+
 	// If enabled, token validator must be the first operational middleware in the chain
 	if srv.tokenValidator != nil {
 		unary = append(unary, mwAuth.UnaryServerInterceptor(mwAuth.AuthFunc(srv.tokenValidator)))
@@ -484,8 +494,9 @@ func (srv *Server) getMiddleware() (unary []grpc.UnaryServerInterceptor, stream
 
 	// If enabled, panic recovery must be the last middleware to chain
 	if srv.panicRecovery {
-		unary = append(unary, mwRecovery.UnaryServerInterceptor())
-		stream = append(stream, mwRecovery.StreamServerInterceptor())
+		handler := mwRecovery.WithRecoveryHandler(recoverError(srv.errorPropagation, srv.errorOpts...))
+		unary = append(unary, mwRecovery.UnaryServerInterceptor(handler))
+		stream = append(stream, mwRecovery.StreamServerInterceptor(handler))
 	}
 	return unary, stream
 }
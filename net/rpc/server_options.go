@@ -6,6 +6,7 @@ import (
 
 	"github.com/bufbuild/protovalidate-go"
 	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwt"
 	"go.bryk.io/pkg/net/rpc/ws"
 	otelProm "go.bryk.io/pkg/otel/prometheus"
 	"google.golang.org/grpc"
@@ -41,7 +42,8 @@ func WithServiceProvider(sp ServiceProvider) ServerOption {
 }
 
 // WithPanicRecovery allows the server to convert panic events into a gRPC error with
-// status 'Internal'.
+// status 'Internal'. When used along 'WithErrorPropagation' and 'errors.WithStackTrace',
+// the error includes the stacktrace of the panicking goroutine.
 func WithPanicRecovery() ServerOption {
 	return func(srv *Server) error {
 		srv.mu.Lock()
@@ -51,6 +53,26 @@ func WithPanicRecovery() ServerOption {
 	}
 }
 
+// WithErrorPropagation enables the server to encode the structure of the
+// errors returned by service handlers (wrapped causes, hints, tags, fields
+// and events) as status details. Clients using the 'WithErrorDecoding'
+// option can restore the original errors and use 'errors.Is' and 'errors.As'
+// on them. Status errors returned by handlers are preserved as-is.
+//
+// Status details are visible to any client of the server. Errors are redacted
+// before encoding (use 'errors.WithStatusRedactor' to adjust the redaction
+// settings) and stacktraces are omitted unless 'errors.WithStackTrace' is
+// provided.
+func WithErrorPropagation(opts ...errors.StatusOption) ServerOption {
+	return func(srv *Server) error {
+		srv.mu.Lock()
+		srv.errorPropagation = true
+		srv.errorOpts = opts
+		srv.mu.Unlock()
+		return nil
+	}
+}
+
 // WithNetworkInterface specifies which network interface to use to listen for incoming
 // requests.
 func WithNetworkInterface(name string) ServerOption {
@@ -193,6 +215,40 @@ func WithAuthByToken(tv TokenValidator) ServerOption {
 	}
 }
 
+// WithAuthByJWT enables automatic authentication for all methods enabled on the
+// server using JWT bearer credentials. Tokens are validated using the provided
+// validator and checks; time-based checks are always applied. For valid tokens,
+// the token instance and its decoded claims are available to service handlers.
+// `claims` returns a new holder used to decode the token claims, if not provided
+// claims are decoded as '*jwt.RegisteredClaims'. Unsigned tokens are always
+// rejected with a 'codes.Unauthenticated' error.
+//
+//	token, _ := jwt.TokenFromContext(ctx)
+//	claims, _ := jwt.ClaimsFromContext(ctx)
+func WithAuthByJWT(v *jwt.Validator, claims func() interface{}, checks ...jwt.Check) ServerOption {
+	return func(srv *Server) error {
+		if v == nil {
+			return errors.New("a token validator is required")
+		}
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		// Prepare authentication function
+		srv.tokenValidator = func(ctx context.Context) (context.Context, error) {
+			token, err := GetAuthToken(ctx, "bearer")
+			if err != nil {
+				return nil, err
+			}
+			ctx, err = v.Authenticate(ctx, token, claims, checks...)
+			if err != nil {
+				return nil, status.Errorf(codes.Unauthenticated, "invalid auth token: %s", err)
+			}
+			return ctx, nil
+		}
+		return nil
+	}
+}
+
 // WithUnaryMiddleware allows including custom middleware functions when processing
 // incoming unary RPC requests. Order is important when chaining multiple middleware.
 func WithUnaryMiddleware(entry ...grpc.UnaryServerInterceptor) ServerOption {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -16,6 +17,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	tdd "github.com/stretchr/testify/assert"
 	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/jose/jwa"
+	"go.bryk.io/pkg/jose/jwk"
+	"go.bryk.io/pkg/jose/jwt"
 	"go.bryk.io/pkg/log"
 	mwGzip "go.bryk.io/pkg/net/middleware/gzip"
 	"go.bryk.io/pkg/net/rpc/ws"
@@ -27,6 +31,7 @@ import (
 	sampleV1 "go.bryk.io/pkg/proto/sample/v1"
 	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -966,6 +971,80 @@ func TestServer(t *testing.T) {
 		assert.Nil(srv.Stop(false), "stop server error")
 	})
 
+	t.Run("WithAuthByJWT", func(t *testing.T) {
+		// Service provider
+		ss := new(barProvider)
+
+		// Token generator and validator
+		mk, _ := jwk.New(jwa.ES256)
+		tg, _ := jwt.NewGenerator("acme.com", jwt.WithSupportForNone())
+		assert.Nil(tg.AddKey(mk), "add key")
+		params := &jwt.TokenParameters{
+			Subject:  "rick",
+			Audience: []string{"rpc.acme.com"},
+			Method:   string(jwa.ES256),
+		}
+		token, _ := tg.Issue(mk.ID(), params)
+		params.Method = string(jwa.NONE)
+		unsigned, _ := tg.Issue("none", params)
+		tv, _ := jwt.NewValidator(jwt.WithValidationKeys(tg.ExportKeys(true)))
+
+		// Start server
+		ca, _ := os.ReadFile("testdata/ca.sample_cer")
+		cert, _ := os.ReadFile("testdata/server.sample_cer")
+		key, _ := os.ReadFile("testdata/server.sample_key")
+		options := append(serverOpts[:],
+			WithServiceProvider(ss),
+			WithNetworkInterface(NetworkInterfaceAll),
+			WithAuthByJWT(tv, nil, jwt.IssuerCheck("acme.com")),
+			WithTLS(ServerTLSConfig{
+				Cert:       cert,
+				PrivateKey: key,
+				CustomCAs:  [][]byte{ca},
+			}),
+		)
+		srv, err := NewServer(options...)
+		if err != nil {
+			assert.Fail(err.Error())
+			return
+		}
+		serverReady := make(chan bool)
+		go func() {
+			_ = srv.Start(serverReady)
+		}()
+		<-serverReady
+
+		// Use a client connection with the provided token
+		ping := func(token string) error {
+			customOptions := []ClientOption{
+				WithInsecureSkipVerify(),
+				WithTimeout(1 * time.Second),
+				WithAuthToken(token),
+				WithClientTLS(ClientTLSConfig{
+					CustomCAs: [][]byte{ca},
+				}),
+			}
+			customOptions = append(customOptions, clientOpts...)
+			conn, err := NewClientConnection(srv.Endpoint(), customOptions...)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+			_, err = sampleV1.NewFooAPIClient(conn).Ping(context.Background(), &empty.Empty{})
+			return err
+		}
+		assert.Nil(ping(token.String()), "valid token")
+		err = ping(unsigned.String())
+		assert.NotNil(err, "unsigned token")
+		assert.Equal(codes.Unauthenticated, status.Code(err))
+		assert.NotNil(ping("invalid-token"), "invalid token")
+
+		// Stop server
+		assert.Nil(srv.Stop(false), "stop server error")
+	})
+
 	t.Run("Metadata", func(t *testing.T) {
 		data := make(map[string]string)
 		data["foo"] = fmt.Sprintf("%s\n", "bar")
@@ -1317,3 +1396,74 @@ func (ep *echoProvider) ServerSetup(server *grpc.Server) {
 func (ep *echoProvider) GatewaySetup() GatewayRegisterFunc {
 	return sampleV1.RegisterEchoAPIHandler
 }
+
+func TestErrorPropagation(t *testing.T) {
+	assert := tdd.New(t)
+	errNotFound := errors.New("record not found")
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return nil, errors.Wrap(errNotFound, "storage")
+	}
+	invoker := func(server grpc.UnaryServerInterceptor) grpc.UnaryInvoker {
+		return func(ctx context.Context, _ string, req, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			_, err := server(ctx, req, nil, handler)
+			// Simulate transmission over the wire
+			data, _ := proto.Marshal(status.Convert(err).Proto())
+			st := new(spb.Status)
+			_ = proto.Unmarshal(data, st)
+			return status.FromProto(st).Err()
+		}
+	}
+	client := errUnaryClientInterceptor()
+
+	// Without error propagation only the message is available
+	noop := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		res, err := handler(ctx, req)
+		if err != nil {
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+		return res, nil
+	}
+	err := client(context.Background(), "/sample", nil, nil, nil, invoker(noop))
+	assert.False(errors.Is(err, errNotFound), "cause analysis")
+
+	// With error propagation
+	err = client(context.Background(), "/sample", nil, nil, nil, invoker(errUnaryServerInterceptor()))
+	assert.True(errors.Is(err, errNotFound), "cause analysis")
+	assert.Equal("storage: record not found", err.Error())
+	var oe *errors.Error
+	assert.True(errors.As(err, &oe), "type casting")
+	assert.Empty(oe.StackTrace(), "stacktrace is omitted by default")
+
+	// Stacktrace is included only when requested
+	withStack := errUnaryServerInterceptor(errors.WithStackTrace())
+	err = client(context.Background(), "/sample", nil, nil, nil, invoker(withStack))
+	assert.True(errors.As(err, &oe), "type casting")
+	assert.NotEmpty(oe.StackTrace(), "stacktrace")
+
+	// Status errors are preserved
+	handler = func(_ context.Context, _ interface{}) (interface{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+	err = client(context.Background(), "/sample", nil, nil, nil, invoker(errUnaryServerInterceptor()))
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func TestProblemDetails(t *testing.T) {
+	assert := tdd.New(t)
+	err := errors.MarkCategory(errors.New("record not found"), errors.NotFound)
+
+	// Error returned by a server using error propagation
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/records/123", nil)
+	problemDetailsHandler(context.Background(), nil, nil, rec, req, encodeError(err))
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.Equal(errors.ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(rec.Body.String(), `"detail":"record not found"`)
+	assert.Contains(rec.Body.String(), `"instance":"/records/123"`)
+
+	// Regular status errors
+	rec = httptest.NewRecorder()
+	problemDetailsHandler(context.Background(), nil, nil, rec, req, status.Error(codes.PermissionDenied, "denied"))
+	assert.Equal(http.StatusForbidden, rec.Code)
+	assert.Contains(rec.Body.String(), `"detail":"denied"`)
+}
@@ -1,9 +1,11 @@
 package rpc
 
 import (
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 
+	"go.bryk.io/pkg/crypto/signer"
 	"go.bryk.io/pkg/errors"
 )
 
@@ -37,6 +39,10 @@ type ServerTLSConfig struct {
 	// Server private key, PEM-encoded.
 	PrivateKey []byte
 
+	// Signer used as server private key instead of 'PrivateKey'; for
+	// example, a key stored in an HSM. See the "crypto/signer" package.
+	Signer crypto.Signer
+
 	// List of ciphers to allow.
 	SupportedCiphers []uint16
 
@@ -53,7 +59,15 @@ type ServerTLSConfig struct {
 // Generate a proper TLS configuration to use on the server.
 func serverTLSConf(opts ServerTLSConfig) (*tls.Config, error) {
 	// Load key/pair
-	cert, err := tls.X509KeyPair(opts.Cert, opts.PrivateKey)
+	var (
+		cert tls.Certificate
+		err  error
+	)
+	if opts.Signer != nil {
+		cert, err = signer.TLSCertificate(opts.Cert, opts.Signer)
+	} else {
+		cert, err = tls.X509KeyPair(opts.Cert, opts.PrivateKey)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load key pair")
 	}
@@ -4,5 +4,15 @@ Package api provides the utilities necessary to instrument an application or lib
 The instrumentation of a piece of code is independent of setting up any specific
 monitoring implementation when executing it. To setup a monitoring pipeline/stack
 at runtime use the `sdk` package.
+
+# Metrics
+
+Simple helpers are provided to record measurements without having to manage
+individual instruments. Instruments are created on first use and cached by
+name.
+
+	api.Counter("requests.total").Add(ctx, 1, api.String("route", "/ping"))
+	api.Histogram("requests.duration", api.WithUnit("ms")).Record(ctx, 12.5)
+	api.Gauge("queue.size").Record(ctx, float64(len(queue)))
 */
 package api
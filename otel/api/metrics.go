@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	apiMetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// Attribute is a key-value pair used to qualify measurements.
+type Attribute = attribute.KeyValue
+
+// String returns a new attribute with a string value.
+func String(key, value string) Attribute {
+	return attribute.String(key, value)
+}
+
+// Int returns a new attribute with an integer value.
+func Int(key string, value int) Attribute {
+	return attribute.Int(key, value)
+}
+
+// Int64 returns a new attribute with an int64 value.
+func Int64(key string, value int64) Attribute {
+	return attribute.Int64(key, value)
+}
+
+// Float64 returns a new attribute with a float64 value.
+func Float64(key string, value float64) Attribute {
+	return attribute.Float64(key, value)
+}
+
+// Bool returns a new attribute with a boolean value.
+func Bool(key string, value bool) Attribute {
+	return attribute.Bool(key, value)
+}
+
+// MetricOption adjust the settings used when creating an instrument.
+type MetricOption func(conf *metricConfig)
+
+// WithDescription sets a human-readable description for the instrument.
+func WithDescription(desc string) MetricOption {
+	return func(conf *metricConfig) {
+		conf.desc = desc
+	}
+}
+
+// WithUnit sets the unit of measure used by the instrument, using the
+// UCUM format. For example: "ms", "By" or "{request}".
+func WithUnit(unit string) MetricOption {
+	return func(conf *metricConfig) {
+		conf.unit = unit
+	}
+}
+
+// WithBuckets sets the explicit bucket boundaries used to aggregate
+// histogram measurements. Ignored for other instruments.
+func WithBuckets(bounds ...float64) MetricOption {
+	return func(conf *metricConfig) {
+		conf.buckets = bounds
+	}
+}
+
+type metricConfig struct {
+	desc    string
+	unit    string
+	buckets []float64
+}
+
+// CounterInstrument records monotonically increasing values, like the
+// number of requests processed.
+type CounterInstrument struct {
+	inst apiMetric.Int64Counter
+}
+
+// Add increments the counter by the provided value; must be non-negative.
+func (c *CounterInstrument) Add(ctx context.Context, value int64, attrs ...Attribute) {
+	c.inst.Add(ctx, value, apiMetric.WithAttributes(attrs...))
+}
+
+// HistogramInstrument records a distribution of values, like the duration
+// of requests.
+type HistogramInstrument struct {
+	inst apiMetric.Float64Histogram
+}
+
+// Record adds a single measurement to the distribution.
+func (h *HistogramInstrument) Record(ctx context.Context, value float64, attrs ...Attribute) {
+	h.inst.Record(ctx, value, apiMetric.WithAttributes(attrs...))
+}
+
+// GaugeInstrument records the current value of a measurement, like the
+// size of a queue or a temperature reading.
+type GaugeInstrument struct {
+	inst apiMetric.Float64Gauge
+}
+
+// Record sets the current value of the gauge.
+func (g *GaugeInstrument) Record(ctx context.Context, value float64, attrs ...Attribute) {
+	g.inst.Record(ctx, value, apiMetric.WithAttributes(attrs...))
+}
+
+// Instruments are cached by kind and name.
+var instruments sync.Map
+
+// Counter returns the counter instrument registered with the provided
+// name, creating it if required. Options are only used when the instrument
+// is created.
+//
+//	api.Counter("requests").Add(ctx, 1, api.String("route", "/ping"))
+func Counter(name string, opts ...MetricOption) *CounterInstrument {
+	conf := newMetricConfig(opts...)
+	inst := cached("counter:"+name, func() interface{} {
+		ic, err := getMeter().Int64Counter(name,
+			apiMetric.WithDescription(conf.desc),
+			apiMetric.WithUnit(conf.unit))
+		if err != nil {
+			apiOtel.Handle(err)
+			ic, _ = noop.Meter{}.Int64Counter(name)
+		}
+		return &CounterInstrument{inst: ic}
+	})
+	return inst.(*CounterInstrument)
+}
+
+// Histogram returns the histogram instrument registered with the provided
+// name, creating it if required. Options are only used when the instrument
+// is created.
+//
+//	api.Histogram("request.duration", api.WithUnit("ms")).Record(ctx, 12.5)
+func Histogram(name string, opts ...MetricOption) *HistogramInstrument {
+	conf := newMetricConfig(opts...)
+	inst := cached("histogram:"+name, func() interface{} {
+		hOpts := []apiMetric.Float64HistogramOption{
+			apiMetric.WithDescription(conf.desc),
+			apiMetric.WithUnit(conf.unit),
+		}
+		if len(conf.buckets) > 0 {
+			hOpts = append(hOpts, apiMetric.WithExplicitBucketBoundaries(conf.buckets...))
+		}
+		ih, err := getMeter().Float64Histogram(name, hOpts...)
+		if err != nil {
+			apiOtel.Handle(err)
+			ih, _ = noop.Meter{}.Float64Histogram(name)
+		}
+		return &HistogramInstrument{inst: ih}
+	})
+	return inst.(*HistogramInstrument)
+}
+
+// Gauge returns the gauge instrument registered with the provided name,
+// creating it if required. Options are only used when the instrument is
+// created.
+//
+//	api.Gauge("queue.size").Record(ctx, float64(len(queue)))
+func Gauge(name string, opts ...MetricOption) *GaugeInstrument {
+	conf := newMetricConfig(opts...)
+	inst := cached("gauge:"+name, func() interface{} {
+		ig, err := getMeter().Float64Gauge(name,
+			apiMetric.WithDescription(conf.desc),
+			apiMetric.WithUnit(conf.unit))
+		if err != nil {
+			apiOtel.Handle(err)
+			ig, _ = noop.Meter{}.Float64Gauge(name)
+		}
+		return &GaugeInstrument{inst: ig}
+	})
+	return inst.(*GaugeInstrument)
+}
+
+// Return the cached instrument for `key`, using `create` to initialize
+// it if required.
+func cached(key string, create func() interface{}) interface{} {
+	if inst, ok := instruments.Load(key); ok {
+		return inst
+	}
+	inst, _ := instruments.LoadOrStore(key, create())
+	return inst
+}
+
+// Instruments created with the global meter are automatically updated
+// when a meter provider is registered using the `sdk` package.
+func getMeter() apiMetric.Meter {
+	return apiOtel.Meter(tracerName, apiMetric.WithInstrumentationVersion(tracerVersion))
+}
+
+func newMetricConfig(opts ...MetricOption) *metricConfig {
+	conf := &metricConfig{}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	return conf
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics(t *testing.T) {
+	assert := tdd.New(t)
+	reader := sdkMetric.NewManualReader()
+	apiOtel.SetMeterProvider(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+	ctx := context.Background()
+
+	// Instruments are cached
+	counter := Counter("test.requests", WithDescription("requests processed"))
+	assert.Equal(counter, Counter("test.requests"), "cached instrument")
+	counter.Add(ctx, 1, String("route", "/ping"))
+	Counter("test.requests").Add(ctx, 2, String("route", "/ping"))
+	Histogram("test.duration", WithUnit("ms"), WithBuckets(10, 100)).Record(ctx, 12.5, Bool("ok", true))
+	Gauge("test.queue").Record(ctx, 3, Int("shard", 1))
+	Gauge("test.queue").Record(ctx, 7, Int("shard", 1))
+
+	// Collect
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(reader.Collect(ctx, &rm), "collect")
+	assert.Len(rm.ScopeMetrics, 1, "scope metrics")
+	assert.Equal(tracerName, rm.ScopeMetrics[0].Scope.Name, "scope name")
+	got := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+
+	sum, ok := got["test.requests"].Data.(metricdata.Sum[int64])
+	assert.True(ok, "counter data")
+	assert.Equal("requests processed", got["test.requests"].Description)
+	assert.Equal(int64(3), sum.DataPoints[0].Value, "counter value")
+	route, _ := sum.DataPoints[0].Attributes.Value("route")
+	assert.Equal("/ping", route.AsString(), "counter attributes")
+
+	hist, ok := got["test.duration"].Data.(metricdata.Histogram[float64])
+	assert.True(ok, "histogram data")
+	assert.Equal("ms", got["test.duration"].Unit)
+	assert.Equal([]float64{10, 100}, hist.DataPoints[0].Bounds, "histogram buckets")
+	assert.Equal(uint64(1), hist.DataPoints[0].Count, "histogram count")
+
+	gauge, ok := got["test.queue"].Data.(metricdata.Gauge[float64])
+	assert.True(ok, "gauge data")
+	assert.Equal(float64(7), gauge.DataPoints[0].Value, "gauge value")
+	assert.Equal(attribute.NewSet(Int("shard", 1)), gauge.DataPoints[0].Attributes)
+}
@@ -61,4 +61,36 @@ type SpanManaged interface {
 
 	// SetAttributes adjust multiple attributes of the Span.
 	SetAttributes(attributes map[string]interface{})
+
+	// EventData produces a log marker during the execution of the span,
+	// including a structured payload stored in the `event.data` attribute.
+	EventData(msg string, payload interface{}, attributes ...map[string]interface{})
+
+	// AddLink associates the span with the span available in the
+	// provided context; usually part of a different trace.
+	AddLink(ctx context.Context, attributes ...map[string]interface{})
+
+	// RecordError registers an exception event on the span without
+	// marking it as failed.
+	RecordError(err error, attributes ...map[string]interface{})
+
+	// SetBool adjust `key` to report a boolean `value` as attribute of the Span.
+	SetBool(key string, value bool)
+
+	// SetInt adjust `key` to report an integer `value` as attribute of the Span.
+	SetInt(key string, value int)
+
+	// SetFloat adjust `key` to report a float `value` as attribute of the Span.
+	SetFloat(key string, value float64)
+
+	// SetString adjust `key` to report a string `value` as attribute of the Span.
+	SetString(key string, value string)
+
+	// SetStringSlice adjust `key` to report a list of strings as attribute
+	// of the Span.
+	SetStringSlice(key string, value []string)
+
+	// SetIntSlice adjust `key` to report a list of integers as attribute
+	// of the Span.
+	SetIntSlice(key string, value []int)
 }
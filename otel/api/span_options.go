@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+
 	"go.bryk.io/pkg/otel"
 	apiTrace "go.opentelemetry.io/otel/trace"
 )
@@ -34,6 +36,20 @@ func WithStartOptions(opts ...apiTrace.SpanStartOption) SpanOption {
 	}
 }
 
+// WithLink associates the new span with the span available in the
+// provided context; usually part of a different trace. Can be used
+// multiple times to add several links.
+func WithLink(ctx context.Context, attrs map[string]interface{}) SpanOption {
+	return func(conf *spanConfig) {
+		sc := apiTrace.SpanContextFromContext(ctx)
+		if !sc.IsValid() {
+			return
+		}
+		link := apiTrace.Link{SpanContext: sc, Attributes: otel.Attributes(attrs).Expand()}
+		conf.opts = append(conf.opts, apiTrace.WithLinks(link))
+	}
+}
+
 type spanConfig struct {
 	kind  SpanKind
 	opts  []apiTrace.SpanStartOption
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanHelpers(t *testing.T) {
+	assert := tdd.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder))
+	tr := tracer{tr: tp.Tracer("test")}
+
+	// Span in a separate trace
+	other := tr.Start(context.Background(), "other")
+	other.End(nil)
+
+	task := tr.Start(context.Background(), "task", WithLink(other.Context(), map[string]interface{}{"kind": "origin"}))
+	task.AddLink(other.Context(), map[string]interface{}{"kind": "follows"})
+	task.AddLink(context.Background()) // ignored, no span available
+	task.EventData("payload", map[string]interface{}{"id": 1, "name": "rick"})
+	task.RecordError(errors.New("recoverable"), map[string]interface{}{"retry": true})
+	task.SetBool("ok", true)
+	task.SetInt("count", 3)
+	task.SetFloat("ratio", 0.5)
+	task.SetString("name", "sample")
+	task.SetStringSlice("tags", []string{"a", "b"})
+	task.SetIntSlice("ids", []int{1, 2})
+	task.End(nil)
+
+	spans := recorder.Ended()
+	assert.Len(spans, 2)
+	sp := spans[1]
+	assert.Equal(codes.Ok, sp.Status().Code, "error recorded without failing span")
+
+	// Links
+	assert.Len(sp.Links(), 2, "links")
+	for _, link := range sp.Links() {
+		assert.Equal(other.TraceID(), link.SpanContext.TraceID().String())
+	}
+
+	// Events
+	assert.Len(sp.Events(), 2, "events")
+	payload := attribute.NewSet(sp.Events()[0].Attributes...)
+	data, _ := payload.Value("event.data")
+	assert.JSONEq(`{"id":1,"name":"rick"}`, data.AsString(), "event payload")
+	errEvent := attribute.NewSet(sp.Events()[1].Attributes...)
+	assert.Equal("exception", sp.Events()[1].Name)
+	assert.True(errEvent.HasValue("exception.stacktrace"), "error stacktrace")
+	assert.True(errEvent.HasValue("retry"), "error attributes")
+
+	// Typed attributes
+	attrs := attribute.NewSet(sp.Attributes()...)
+	for key, kind := range map[string]attribute.Type{
+		"ok":    attribute.BOOL,
+		"count": attribute.INT64,
+		"ratio": attribute.FLOAT64,
+		"name":  attribute.STRING,
+		"tags":  attribute.STRINGSLICE,
+		"ids":   attribute.INT64SLICE,
+	} {
+		v, ok := attrs.Value(attribute.Key(key))
+		assert.True(ok, key)
+		assert.Equal(kind, v.Type(), key)
+	}
+}
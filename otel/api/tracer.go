@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"go.bryk.io/pkg/errors"
@@ -59,7 +60,8 @@ func (s span) Unwrap() apiTrace.Span {
 // error implements the `errors.HasStack` interface, the original
 // stacktrace will be preserved in the span's attributes. If a
 // report can be extracted from the error, it will be stored in the
-// `exception.report` attribute.
+// `exception.report` attribute. Structured fields attached to the
+// error are included as `exception.field.<key>` attributes.
 func (s span) End(err error) {
 	// finish task
 	if err == nil {
@@ -68,29 +70,7 @@ func (s span) End(err error) {
 		return
 	}
 
-	// exception event metadata attributes
-	attrs := otel.Attributes{}
-
-	// record error
-	opts := []apiTrace.EventOption{}
-	var se errors.HasStack
-	if errors.As(err, &se) {
-		// preserve original error value to be reported when
-		// using the Sentry integration
-		if errPayload, encErr := errCodec.Marshal(err); encErr == nil {
-			attrs.Set(string(ExceptionReportKey), string(errPayload))
-		}
-
-		// preserve original trace in `exception.stacktrace`
-		attrs.Set(string(semConv.ExceptionStacktraceKey), fmt.Sprintf("%+v", err))
-		opts = append(opts, apiTrace.WithStackTrace(false))
-	} else {
-		// if there's no stacktrace in the error already, let the
-		// framework capture one
-		opts = append(opts, apiTrace.WithStackTrace(true))
-	}
-	opts = append(opts, apiTrace.WithAttributes(attrs.Expand()...))
-	s.sp.RecordError(err, opts...)
+	s.recordError(err, nil)
 	s.sp.SetStatus(codes.Error, err.Error())
 	s.sp.End()
 }
@@ -137,3 +117,115 @@ func (s span) SetAttributes(attributes map[string]interface{}) {
 	attrs := otel.Attributes(attributes)
 	s.sp.SetAttributes(attrs.Expand()...)
 }
+
+// EventData produces a log marker during the execution of the span,
+// including a structured payload. The payload is encoded in JSON format
+// and stored in the `event.data` attribute.
+func (s span) EventData(msg string, payload interface{}, attrs ...map[string]interface{}) {
+	fields := otel.Attributes{}
+	for _, attr := range attrs {
+		fields.Join(attr)
+	}
+	if data, err := json.Marshal(payload); err == nil {
+		fields.Set("event.data", string(data))
+	} else {
+		fields.Set("event.data", fmt.Sprintf("%+v", payload))
+	}
+	s.sp.AddEvent(msg, apiTrace.WithAttributes(fields.Expand()...))
+}
+
+// AddLink associates the span with the span available in the provided
+// context. Links are useful to relate operations that belong to
+// different traces, like batch jobs processing messages produced by
+// several requests.
+func (s span) AddLink(ctx context.Context, attrs ...map[string]interface{}) {
+	sc := apiTrace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	fields := otel.Attributes{}
+	for _, attr := range attrs {
+		fields.Join(attr)
+	}
+	s.sp.AddLink(apiTrace.Link{SpanContext: sc, Attributes: fields.Expand()})
+}
+
+// RecordError registers an exception event on the span without marking
+// it as failed. Stacktrace, report and structured fields are preserved
+// the same way as when using `End`.
+func (s span) RecordError(err error, attrs ...map[string]interface{}) {
+	if err == nil {
+		return
+	}
+	fields := otel.Attributes{}
+	for _, attr := range attrs {
+		fields.Join(attr)
+	}
+	s.recordError(err, fields)
+}
+
+// SetBool adjust `key` to report a boolean `value` as attribute of the Span.
+func (s span) SetBool(key string, value bool) {
+	s.sp.SetAttributes(attribute.Bool(key, value))
+}
+
+// SetInt adjust `key` to report an integer `value` as attribute of the Span.
+func (s span) SetInt(key string, value int) {
+	s.sp.SetAttributes(attribute.Int(key, value))
+}
+
+// SetFloat adjust `key` to report a float `value` as attribute of the Span.
+func (s span) SetFloat(key string, value float64) {
+	s.sp.SetAttributes(attribute.Float64(key, value))
+}
+
+// SetString adjust `key` to report a string `value` as attribute of the Span.
+func (s span) SetString(key string, value string) {
+	s.sp.SetAttributes(attribute.String(key, value))
+}
+
+// SetStringSlice adjust `key` to report a list of strings as attribute
+// of the Span.
+func (s span) SetStringSlice(key string, value []string) {
+	s.sp.SetAttributes(attribute.StringSlice(key, value))
+}
+
+// SetIntSlice adjust `key` to report a list of integers as attribute
+// of the Span.
+func (s span) SetIntSlice(key string, value []int) {
+	s.sp.SetAttributes(attribute.IntSlice(key, value))
+}
+
+// Register an exception event for `err`. Additional attributes are
+// included in the event, if provided.
+func (s span) recordError(err error, attrs otel.Attributes) {
+	if attrs == nil {
+		attrs = otel.Attributes{}
+	}
+
+	// structured error fields
+	for k, v := range errors.Fields(err).Values() {
+		attrs.Set(fmt.Sprintf("exception.field.%s", k), v)
+	}
+
+	// record error
+	opts := []apiTrace.EventOption{}
+	var se errors.HasStack
+	if errors.As(err, &se) {
+		// preserve original error value to be reported when
+		// using the Sentry integration
+		if errPayload, encErr := errCodec.Marshal(err); encErr == nil {
+			attrs.Set(string(ExceptionReportKey), string(errPayload))
+		}
+
+		// preserve original trace in `exception.stacktrace`
+		attrs.Set(string(semConv.ExceptionStacktraceKey), fmt.Sprintf("%+v", err))
+		opts = append(opts, apiTrace.WithStackTrace(false))
+	} else {
+		// if there's no stacktrace in the error already, let the
+		// framework capture one
+		opts = append(opts, apiTrace.WithStackTrace(true))
+	}
+	opts = append(opts, apiTrace.WithAttributes(attrs.Expand()...))
+	s.sp.RecordError(err, opts...)
+}
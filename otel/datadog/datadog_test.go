@@ -0,0 +1,78 @@
+package datadog
+
+import (
+	"context"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExporter(t *testing.T) {
+	assert := tdd.New(t)
+	t.Setenv("DD_ENV", "staging")
+	t.Setenv("DD_TAGS", "team:core,region:us-east-1")
+
+	_, err := NewExporter(&Options{Protocol: "udp"})
+	assert.NotNil(err, "unsupported protocol")
+
+	dd, err := NewExporter(&Options{
+		Service:  "my-service",
+		Version:  "0.1.0",
+		Insecure: true,
+		Tags:     map[string]string{"region": "eu-west-1"},
+	})
+	assert.Nil(err, "new exporter")
+	assert.Equal("localhost:4317", dd.opts.Endpoint, "default endpoint")
+	assert.Len(dd.Options(), 5, "setup options")
+
+	// Unified service tags
+	attrs := dd.ResourceAttributes()
+	assert.Equal("my-service", attrs.Get("service.name"))
+	assert.Equal("0.1.0", attrs.Get("service.version"))
+	assert.Equal("staging", attrs.Get("deployment.environment"))
+	assert.Equal("core", attrs.Get("team"))
+	assert.Equal("eu-west-1", attrs.Get("region"), "explicit tags take precedence")
+}
+
+func TestPropagator(t *testing.T) {
+	assert := tdd.New(t)
+	tid, _ := trace.TraceIDFromHex("640cb0bc00000000a3ce929d0e0e4736")
+	sid, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	// Inject
+	carrier := propagation.MapCarrier{}
+	Propagator().Inject(ctx, carrier)
+	assert.Equal("11803532876627986230", carrier.Get(traceIDHeader))
+	assert.Equal("67667974448284343", carrier.Get(parentIDHeader))
+	assert.Equal("1", carrier.Get(priorityHeader))
+	assert.Equal("_dd.p.tid=640cb0bc00000000", carrier.Get(tagsHeader))
+
+	// Extract
+	sc := trace.SpanContextFromContext(Propagator().Extract(context.Background(), carrier))
+	assert.True(sc.IsRemote())
+	assert.True(sc.IsSampled())
+	assert.Equal(tid, sc.TraceID())
+	assert.Equal(sid, sc.SpanID())
+
+	// 64-bit trace identifiers and dropped traces
+	carrier = propagation.MapCarrier{
+		traceIDHeader:  "11803532876627986230",
+		parentIDHeader: "67667974448284343",
+		priorityHeader: "-1",
+	}
+	sc = trace.SpanContextFromContext(Propagator().Extract(context.Background(), carrier))
+	assert.Equal("0000000000000000a3ce929d0e0e4736", sc.TraceID().String())
+	assert.False(sc.IsSampled())
+
+	// Invalid values
+	carrier = propagation.MapCarrier{traceIDHeader: "invalid"}
+	sc = trace.SpanContextFromContext(Propagator().Extract(context.Background(), carrier))
+	assert.False(sc.IsValid())
+}
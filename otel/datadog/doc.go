@@ -0,0 +1,29 @@
+/*
+Package datadog provides an OpenTelemetry integration for Datadog.
+
+Telemetry data (traces, metrics and logs) is submitted using OTLP to a
+Datadog Agent, or OpenTelemetry Collector, with OTLP ingestion enabled.
+Service, environment and version details are reported using the unified
+service tagging conventions, so the data produced is correlated across
+the Datadog product.
+
+	dd, _ := datadog.NewExporter(&datadog.Options{
+		Service:     "my-service",
+		Environment: "production",
+		Version:     "0.1.0",
+	})
+	app, _ := sdk.Setup(dd.Options()...)
+
+Settings not provided are loaded from the standard Datadog environment
+variables: DD_AGENT_HOST, DD_API_KEY, DD_ENV, DD_SERVICE, DD_VERSION and
+DD_TAGS.
+
+Services instrumented with Datadog tracing libraries can be part of the
+same traces by including the Datadog propagator, which handles the
+"x-datadog-*" headers.
+
+More information:
+https://docs.datadoghq.com/opentelemetry/
+https://docs.datadoghq.com/getting_started/tagging/unified_service_tagging
+*/
+package datadog
@@ -0,0 +1,289 @@
+package datadog
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/otel"
+	"go.bryk.io/pkg/otel/sdk"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	semConv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Header used to provide the Datadog API key.
+const apiKeyHeader = "dd-api-key"
+
+// Exporter provides a Datadog integration for OpenTelemetry. Traces, metrics
+// and logs are submitted to a Datadog Agent (or OpenTelemetry Collector)
+// using OTLP. Metrics are reported using delta temporality, as recommended
+// by Datadog.
+//
+// More information:
+// https://docs.datadoghq.com/opentelemetry/interoperability/otlp_ingest_in_the_agent
+type Exporter struct {
+	opts    *Options
+	traces  sdkTrace.SpanExporter
+	metrics sdkMetric.Exporter
+	logs    sdkLog.Exporter
+}
+
+// Options defines the configuration settings for the Datadog exporter.
+type Options struct {
+	// OTLP endpoint of the Datadog Agent. If not provided, the agent is
+	// expected on the host set by DD_AGENT_HOST (or "localhost") using the
+	// default OTLP port for the protocol selected.
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+
+	// Protocol used to submit data, either "grpc" (default) or "http".
+	Protocol string `mapstructure:"protocol" yaml:"protocol" json:"protocol"`
+
+	// Disable transport security; usually the case when submitting data
+	// to a local Datadog Agent.
+	Insecure bool `mapstructure:"insecure" yaml:"insecure" json:"insecure"`
+
+	// Datadog API key. Only required when submitting data to an endpoint
+	// that requires authentication, like the Datadog OTLP intake or a
+	// gateway. Defaults to DD_API_KEY.
+	APIKey string `mapstructure:"api_key" yaml:"api_key" json:"api_key"`
+
+	// Service name; reported as the `service` tag. Defaults to DD_SERVICE.
+	Service string `mapstructure:"service" yaml:"service" json:"service"`
+
+	// Environment identifier; reported as the `env` tag. Defaults to DD_ENV.
+	Environment string `mapstructure:"environment" yaml:"environment" json:"environment"`
+
+	// Service version; reported as the `version` tag. Defaults to DD_VERSION.
+	Version string `mapstructure:"version" yaml:"version" json:"version"`
+
+	// Additional tags to include on all telemetry data. Values set on
+	// DD_TAGS (using the "key:value" format) are also included.
+	Tags map[string]string `mapstructure:"tags" yaml:"tags" json:"tags"`
+}
+
+// NewExporter returns a new Datadog exporter instance.
+func NewExporter(opts *Options) (*Exporter, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	opts.loadEnv()
+	if opts.Protocol != "grpc" && opts.Protocol != "http" {
+		return nil, errors.Errorf("unsupported protocol: %s", opts.Protocol)
+	}
+	var err error
+	de := &Exporter{opts: opts}
+	if opts.Protocol == "http" {
+		err = de.setupHTTP()
+	} else {
+		err = de.setupGRPC()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return de, nil
+}
+
+// SpanExporter returns the exporter used to submit traces.
+func (de *Exporter) SpanExporter() sdkTrace.SpanExporter {
+	return de.traces
+}
+
+// MetricExporter returns the exporter used to submit metrics.
+func (de *Exporter) MetricExporter() sdkMetric.Exporter {
+	return de.metrics
+}
+
+// LogExporter returns the exporter used to submit log records.
+func (de *Exporter) LogExporter() sdkLog.Exporter {
+	return de.logs
+}
+
+// Propagator returns a carrier that handles Datadog-specific details
+// across service boundaries; required when interacting with services
+// instrumented using Datadog tracing libraries.
+func (de *Exporter) Propagator() propagation.TextMapPropagator {
+	return Propagator()
+}
+
+// ResourceAttributes returns the attributes used to report the unified
+// service tags, and any additional tags, for all telemetry data.
+func (de *Exporter) ResourceAttributes() otel.Attributes {
+	attrs := otel.Attributes{}
+	for k, v := range de.opts.Tags {
+		attrs.Set(k, v)
+	}
+	if de.opts.Service != "" {
+		attrs.Set(string(semConv.ServiceNameKey), de.opts.Service)
+	}
+	if de.opts.Version != "" {
+		attrs.Set(string(semConv.ServiceVersionKey), de.opts.Version)
+	}
+	if de.opts.Environment != "" {
+		attrs.Set(string(semConv.DeploymentEnvironmentKey), de.opts.Environment)
+	}
+	return attrs
+}
+
+// Options returns the settings required to setup an instrumented application
+// that submits all its telemetry data to Datadog.
+//
+//	app, err := sdk.Setup(dd.Options()...)
+func (de *Exporter) Options() []sdk.Option {
+	return []sdk.Option{
+		sdk.WithSpanExporter(de.traces),
+		sdk.WithMetricExporter(de.metrics),
+		sdk.WithLogExporter(de.logs),
+		sdk.WithPropagator(de.Propagator()),
+		sdk.WithResourceAttributes(de.ResourceAttributes()),
+	}
+}
+
+// Load settings not explicitly provided from environment variables.
+func (opts *Options) loadEnv() {
+	if opts.Protocol == "" {
+		opts.Protocol = "grpc"
+	}
+	if opts.Endpoint == "" {
+		host := os.Getenv("DD_AGENT_HOST")
+		if host == "" {
+			host = "localhost"
+		}
+		port := "4317"
+		if opts.Protocol == "http" {
+			port = "4318"
+		}
+		opts.Endpoint = net.JoinHostPort(host, port)
+	}
+	if opts.APIKey == "" {
+		opts.APIKey = os.Getenv("DD_API_KEY")
+	}
+	if opts.Service == "" {
+		opts.Service = os.Getenv("DD_SERVICE")
+	}
+	if opts.Environment == "" {
+		opts.Environment = os.Getenv("DD_ENV")
+	}
+	if opts.Version == "" {
+		opts.Version = os.Getenv("DD_VERSION")
+	}
+
+	// DD_TAGS values are separated by commas or spaces; explicitly provided
+	// tags take precedence
+	tags := map[string]string{}
+	for _, tag := range strings.FieldsFunc(os.Getenv("DD_TAGS"), func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		if k, v, ok := strings.Cut(tag, ":"); ok && k != "" {
+			tags[k] = v
+		}
+	}
+	for k, v := range opts.Tags {
+		tags[k] = v
+	}
+	opts.Tags = tags
+}
+
+// Request headers included on all export operations.
+func (opts *Options) headers() map[string]string {
+	headers := map[string]string{}
+	if opts.APIKey != "" {
+		headers[apiKeyHeader] = opts.APIKey
+	}
+	return headers
+}
+
+func (de *Exporter) setupHTTP() (err error) {
+	ctx := context.Background()
+	traceOpts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(de.opts.Endpoint),
+		otlptracehttp.WithHeaders(de.opts.headers()),
+		otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
+	}
+	metricOpts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(de.opts.Endpoint),
+		otlpmetrichttp.WithHeaders(de.opts.headers()),
+		otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
+		otlpmetrichttp.WithTemporalitySelector(deltaTemporality),
+	}
+	logOpts := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(de.opts.Endpoint),
+		otlploghttp.WithHeaders(de.opts.headers()),
+		otlploghttp.WithCompression(otlploghttp.GzipCompression),
+	}
+	if de.opts.Insecure {
+		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+		logOpts = append(logOpts, otlploghttp.WithInsecure())
+	}
+	if de.traces, err = otlptracehttp.New(ctx, traceOpts...); err != nil {
+		return err
+	}
+	if de.metrics, err = otlpmetrichttp.New(ctx, metricOpts...); err != nil {
+		return err
+	}
+	de.logs, err = otlploghttp.New(ctx, logOpts...)
+	return err
+}
+
+func (de *Exporter) setupGRPC() (err error) {
+	ctx := context.Background()
+	traceOpts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(de.opts.Endpoint),
+		otlptracegrpc.WithHeaders(de.opts.headers()),
+		otlptracegrpc.WithCompressor(gzip.Name),
+	}
+	metricOpts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(de.opts.Endpoint),
+		otlpmetricgrpc.WithHeaders(de.opts.headers()),
+		otlpmetricgrpc.WithCompressor(gzip.Name),
+		otlpmetricgrpc.WithTemporalitySelector(deltaTemporality),
+	}
+	logOpts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(de.opts.Endpoint),
+		otlploggrpc.WithHeaders(de.opts.headers()),
+		otlploggrpc.WithCompressor(gzip.Name),
+	}
+	if de.opts.Insecure {
+		traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+		logOpts = append(logOpts, otlploggrpc.WithInsecure())
+	} else {
+		creds := credentials.NewClientTLSFromCert(nil, "")
+		traceOpts = append(traceOpts, otlptracegrpc.WithTLSCredentials(creds))
+		metricOpts = append(metricOpts, otlpmetricgrpc.WithTLSCredentials(creds))
+		logOpts = append(logOpts, otlploggrpc.WithTLSCredentials(creds))
+	}
+	if de.traces, err = otlptracegrpc.New(ctx, traceOpts...); err != nil {
+		return err
+	}
+	if de.metrics, err = otlpmetricgrpc.New(ctx, metricOpts...); err != nil {
+		return err
+	}
+	de.logs, err = otlploggrpc.New(ctx, logOpts...)
+	return err
+}
+
+// Datadog expects delta temporality for monotonic instruments.
+// https://docs.datadoghq.com/opentelemetry/guide/otlp_delta_temporality
+func deltaTemporality(kind sdkMetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkMetric.InstrumentKindUpDownCounter, sdkMetric.InstrumentKindObservableUpDownCounter:
+		return metricdata.CumulativeTemporality
+	default:
+		return metricdata.DeltaTemporality
+	}
+}
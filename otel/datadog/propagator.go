@@ -0,0 +1,106 @@
+package datadog
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers used by Datadog tracing libraries.
+// https://docs.datadoghq.com/tracing/trace_collection/trace_context_propagation
+const (
+	traceIDHeader  = "x-datadog-trace-id"
+	parentIDHeader = "x-datadog-parent-id"
+	priorityHeader = "x-datadog-sampling-priority"
+	tagsHeader     = "x-datadog-tags"
+)
+
+// Propagation tag used to transmit the upper 64 bits of 128-bit trace IDs.
+const traceIDTag = "_dd.p.tid"
+
+type ddPropagator struct{}
+
+// Propagator returns a carrier that handles the trace context headers used by
+// Datadog tracing libraries ("x-datadog-*"). Datadog uses 64-bit decimal
+// identifiers; the upper 64 bits of the trace ID are transmitted using the
+// "_dd.p.tid" propagation tag.
+func Propagator() propagation.TextMapPropagator {
+	return ddPropagator{}
+}
+
+// Inject sets Datadog-related values from the Context into the carrier.
+//
+// https://opentelemetry.io/docs/reference/specification/context/api-propagators/#inject
+func (p ddPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	tid := sc.TraceID()
+	sid := sc.SpanID()
+	priority := "0"
+	if sc.IsSampled() {
+		priority = "1"
+	}
+	carrier.Set(traceIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(tid[8:]), 10))
+	carrier.Set(parentIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10))
+	carrier.Set(priorityHeader, priority)
+	if upper := tid[:8]; binary.BigEndian.Uint64(upper) != 0 {
+		carrier.Set(tagsHeader, traceIDTag+"="+hex.EncodeToString(upper))
+	}
+}
+
+// Extract reads cross-cutting concerns from the carrier into a Context.
+//
+// https://opentelemetry.io/docs/reference/specification/context/api-propagators/#extract
+func (p ddPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	lower, err := strconv.ParseUint(carrier.Get(traceIDHeader), 10, 64)
+	if err != nil || lower == 0 {
+		return ctx
+	}
+	parent, err := strconv.ParseUint(carrier.Get(parentIDHeader), 10, 64)
+	if err != nil || parent == 0 {
+		return ctx
+	}
+
+	// build identifiers
+	var (
+		tid trace.TraceID
+		sid trace.SpanID
+	)
+	binary.BigEndian.PutUint64(tid[8:], lower)
+	binary.BigEndian.PutUint64(sid[:], parent)
+	for _, tag := range strings.Split(carrier.Get(tagsHeader), ",") {
+		k, v, _ := strings.Cut(tag, "=")
+		if k != traceIDTag {
+			continue
+		}
+		if upper, err := hex.DecodeString(v); err == nil && len(upper) == 8 {
+			copy(tid[:8], upper)
+		}
+	}
+
+	// positive priority values mean the trace is sampled
+	var flags trace.TraceFlags
+	if priority, err := strconv.Atoi(carrier.Get(priorityHeader)); err == nil && priority > 0 {
+		flags = trace.FlagsSampled
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	}))
+}
+
+// Fields returns the keys whose values are set with Inject.
+//
+// https://opentelemetry.io/docs/reference/specification/context/api-propagators/#fields
+func (p ddPropagator) Fields() []string {
+	return []string{traceIDHeader, parentIDHeader, priorityHeader, tagsHeader}
+}
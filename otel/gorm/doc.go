@@ -1,6 +1,17 @@
 /*
 Package gorm provides an OpenTelemetry instrumentation for the GORM library.
 
+The plugin can scrub literal values from the reported statements, and flag
+operations exceeding a latency threshold as slow queries; slow queries are
+recorded as span events and logged as warnings.
+
+	plg := otelGorm.Plugin(
+		otelGorm.WithSanitizedQueries(),
+		otelGorm.WithSlowQueryThreshold(500*time.Millisecond),
+		otelGorm.WithLogger(log),
+	)
+	db.Use(plg)
+
 More information:
 https://github.com/go-gorm/opentelemetry
 */
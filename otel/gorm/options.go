@@ -1,6 +1,9 @@
 package gorm
 
 import (
+	"time"
+
+	xlog "go.bryk.io/pkg/log"
 	"go.bryk.io/pkg/otel"
 	semConv "go.opentelemetry.io/otel/semconv/v1.20.0"
 )
@@ -32,6 +35,33 @@ func WithoutQueryVariables() Option {
 	}
 }
 
+// WithSanitizedQueries configures the db.statement attribute to replace
+// all literal values in the query with a `?` placeholder. Query variables
+// are excluded as well. Useful to prevent sensitive information from being
+// included in the spans.
+func WithSanitizedQueries() Option {
+	return func(p *plugin) {
+		p.sanitize = true
+	}
+}
+
+// WithSlowQueryThreshold reports operations taking longer than the provided
+// duration as slow queries. Slow queries are marked with a `db.slow_query`
+// attribute and a "slow query" span event, and are logged as warnings when
+// a logger is provided using `WithLogger`.
+func WithSlowQueryThreshold(threshold time.Duration) Option {
+	return func(p *plugin) {
+		p.slowThreshold = threshold
+	}
+}
+
+// WithLogger sets the logger instance used to report slow queries.
+func WithLogger(ll xlog.Logger) Option {
+	return func(p *plugin) {
+		p.log = ll
+	}
+}
+
 // WithQueryFormatter configures a query formatter.
 func WithQueryFormatter(queryFormatter func(query string) string) Option {
 	return func(p *plugin) {
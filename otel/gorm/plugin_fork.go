@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
+	xlog "go.bryk.io/pkg/log"
+	otelSQL "go.bryk.io/pkg/otel/sql"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -21,8 +24,13 @@ import (
 
 var (
 	dbRowsAffected = attribute.Key("db.rows_affected")
+	dbRowsReturned = attribute.Key("db.rows_returned")
+	dbSlowQuery    = attribute.Key("db.slow_query")
 )
 
+// Key used to store the start time of each operation.
+const startKey = "otel:start"
+
 // list of common errors that can be ignored by default.
 var commonErrors = []error{
 	gorm.ErrRecordNotFound, // no data
@@ -39,6 +47,9 @@ type plugin struct {
 	ignoredErrors    []error
 	excludeQueryVars bool
 	excludeMetrics   bool
+	sanitize         bool
+	slowThreshold    time.Duration
+	log              xlog.Logger
 	queryFormatter   func(query string) string
 }
 
@@ -78,22 +89,22 @@ func (p plugin) Initialize(db *gorm.DB) error {
 		name     string
 	}{
 		{cb.Create().Before("gorm:create"), p.before("orm.Create"), "before:create"},
-		{cb.Create().After("gorm:create"), p.after(), "after:create"},
+		{cb.Create().After("gorm:create"), p.after("orm.Create"), "after:create"},
 
 		{cb.Query().Before("gorm:query"), p.before("orm.Query"), "before:select"},
-		{cb.Query().After("gorm:query"), p.after(), "after:select"},
+		{cb.Query().After("gorm:query"), p.after("orm.Query"), "after:select"},
 
 		{cb.Delete().Before("gorm:delete"), p.before("orm.Delete"), "before:delete"},
-		{cb.Delete().After("gorm:delete"), p.after(), "after:delete"},
+		{cb.Delete().After("gorm:delete"), p.after("orm.Delete"), "after:delete"},
 
 		{cb.Update().Before("gorm:update"), p.before("orm.Update"), "before:update"},
-		{cb.Update().After("gorm:update"), p.after(), "after:update"},
+		{cb.Update().After("gorm:update"), p.after("orm.Update"), "after:update"},
 
 		{cb.Row().Before("gorm:row"), p.before("orm.Row"), "before:row"},
-		{cb.Row().After("gorm:row"), p.after(), "after:row"},
+		{cb.Row().After("gorm:row"), p.after("orm.Row"), "after:row"},
 
 		{cb.Raw().Before("gorm:raw"), p.before("orm.Raw"), "before:raw"},
-		{cb.Raw().After("gorm:raw"), p.after(), "after:raw"},
+		{cb.Raw().After("gorm:raw"), p.after("orm.Raw"), "after:raw"},
 	}
 
 	var firstErr error
@@ -108,14 +119,23 @@ func (p plugin) Initialize(db *gorm.DB) error {
 func (p *plugin) before(spanName string) gormHookFunc {
 	return func(tx *gorm.DB) {
 		tx.Statement.Context, _ = p.tracer.Start(tx.Statement.Context, spanName, trace.WithSpanKind(trace.SpanKindClient))
+		if p.slowThreshold > 0 {
+			tx.InstanceSet(startKey, time.Now())
+		}
 	}
 }
 
-func (p *plugin) after() gormHookFunc {
+func (p *plugin) after(operation string) gormHookFunc {
 	return func(tx *gorm.DB) {
 		// start span
 		span := trace.SpanFromContext(tx.Statement.Context)
 		defer span.End()
+
+		// slow operations are reported even if the span is not sampled
+		elapsed, slow := p.elapsed(tx)
+		if slow {
+			p.slowQuery(tx, span, elapsed)
+		}
 		if !span.IsRecording() {
 			return
 		}
@@ -127,20 +147,15 @@ func (p *plugin) after() gormHookFunc {
 			attrs = append(attrs, sys)
 		}
 
-		vars := tx.Statement.Vars
-		var query string
-		if p.excludeQueryVars {
-			query = tx.Statement.SQL.String()
-		} else {
-			query = tx.Dialector.Explain(tx.Statement.SQL.String(), vars...)
-		}
-
-		attrs = append(attrs, semConv.DBStatementKey.String(p.formatQuery(query)))
+		attrs = append(attrs, semConv.DBStatementKey.String(p.statement(tx)))
 		if tx.Statement.Table != "" {
 			attrs = append(attrs, semConv.DBSQLTableKey.String(tx.Statement.Table))
 		}
 		if tx.Statement.RowsAffected != -1 {
 			attrs = append(attrs, dbRowsAffected.Int64(tx.Statement.RowsAffected))
+			if operation == "orm.Query" {
+				attrs = append(attrs, dbRowsReturned.Int64(tx.Statement.RowsAffected))
+			}
 		}
 		span.SetAttributes(attrs...)
 
@@ -169,6 +184,21 @@ func (p *plugin) ignoreError(err error) bool {
 	return false
 }
 
+// Statement reported for the operation. When sanitization is enabled the
+// query variables are never included.
+func (p *plugin) statement(tx *gorm.DB) string {
+	var query string
+	switch {
+	case p.sanitize:
+		query = otelSQL.Sanitize(tx.Statement.SQL.String())
+	case p.excludeQueryVars:
+		query = tx.Statement.SQL.String()
+	default:
+		query = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+	}
+	return p.formatQuery(query)
+}
+
 func (p *plugin) formatQuery(query string) string {
 	if p.queryFormatter != nil {
 		return p.queryFormatter(query)
@@ -176,6 +206,51 @@ func (p *plugin) formatQuery(query string) string {
 	return query
 }
 
+// Time elapsed since the operation started, and whether the operation
+// exceeded the slow query threshold.
+func (p *plugin) elapsed(tx *gorm.DB) (time.Duration, bool) {
+	if p.slowThreshold <= 0 {
+		return 0, false
+	}
+	val, ok := tx.InstanceGet(startKey)
+	if !ok {
+		return 0, false
+	}
+	start, ok := val.(time.Time)
+	if !ok {
+		return 0, false
+	}
+	elapsed := time.Since(start)
+	return elapsed, elapsed >= p.slowThreshold
+}
+
+// Report a slow operation as a span event and, if a logger is available,
+// as a warning message.
+func (p *plugin) slowQuery(tx *gorm.DB, span trace.Span, elapsed time.Duration) {
+	query := p.statement(tx)
+	if span.IsRecording() {
+		span.SetAttributes(dbSlowQuery.Bool(true))
+		span.AddEvent("slow query", trace.WithAttributes(
+			semConv.DBStatementKey.String(query),
+			attribute.Int64("db.duration_ms", elapsed.Milliseconds()),
+			attribute.Int64("db.slow_query.threshold_ms", p.slowThreshold.Milliseconds()),
+		))
+	}
+	if p.log != nil {
+		sc := span.SpanContext()
+		fields := xlog.Fields{
+			"gorm.sql":        query,
+			"gorm.rows":       tx.Statement.RowsAffected,
+			"gorm.elapsed_ms": elapsed.Milliseconds(),
+		}
+		if sc.IsValid() {
+			fields["telemetry.trace.id"] = sc.TraceID().String()
+			fields["telemetry.span.id"] = sc.SpanID().String()
+		}
+		p.log.WithFields(fields).Warningf("SLOW SQL >= %v", p.slowThreshold)
+	}
+}
+
 func dbSystem(tx *gorm.DB) attribute.KeyValue {
 	switch tx.Dialector.Name() {
 	case "mysql":
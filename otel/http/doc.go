@@ -1,4 +1,16 @@
 /*
 Package otelhttp provide utilities to instrument HTTP clients and servers based on Go's net/http package.
+
+Besides spans, server handlers record RED (rate, errors, duration) metrics for
+all processed requests:
+
+  - `http.server.request.count`: number of requests handled.
+  - `http.server.request.duration`: histogram of request durations, in seconds.
+  - `http.server.active_requests`: number of in-flight requests.
+
+Metrics include the `http.request.method`, `http.route` and
+`http.response.status_code` attributes. Use `WithRouteFormatter` to report
+route templates instead of request paths, and `WithoutMetrics` to disable
+the metrics altogether.
 */
 package otelhttp
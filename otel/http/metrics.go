@@ -0,0 +1,128 @@
+package otelhttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	apiMetric "go.opentelemetry.io/otel/metric"
+)
+
+// Instrumentation scope used for the server metrics.
+const meterName = "go.bryk.io/pkg/otel/http"
+
+// Attributes used to qualify the server metrics.
+const (
+	lblMethod = attribute.Key("http.request.method")
+	lblRoute  = attribute.Key("http.route")
+	lblStatus = attribute.Key("http.response.status_code")
+)
+
+// RouteFormatter returns the value used as `http.route` attribute when
+// reporting metrics for a request. To avoid high-cardinality metrics the
+// value should be a template (e.g., "/users/{id}") instead of the actual
+// request path.
+type RouteFormatter func(r *http.Request) string
+
+// RED (rate, errors, duration) metrics recorded by server handlers.
+type serverMetrics struct {
+	requests apiMetric.Int64Counter
+	duration apiMetric.Float64Histogram
+	active   apiMetric.Int64UpDownCounter
+}
+
+func newServerMetrics() *serverMetrics {
+	// instruments created with the global meter are automatically updated
+	// when a meter provider is registered using the `sdk` package
+	meter := apiOtel.Meter(meterName)
+	sm := &serverMetrics{}
+	var err error
+	if sm.requests, err = meter.Int64Counter("http.server.request.count",
+		apiMetric.WithDescription("Number of HTTP requests handled."),
+		apiMetric.WithUnit("{request}")); err != nil {
+		apiOtel.Handle(err)
+	}
+	if sm.duration, err = meter.Float64Histogram("http.server.request.duration",
+		apiMetric.WithDescription("Duration of HTTP server requests."),
+		apiMetric.WithUnit("s"),
+		apiMetric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10)); err != nil {
+		apiOtel.Handle(err)
+	}
+	if sm.active, err = meter.Int64UpDownCounter("http.server.active_requests",
+		apiMetric.WithDescription("Number of active HTTP server requests."),
+		apiMetric.WithUnit("{request}")); err != nil {
+		apiOtel.Handle(err)
+	}
+	return sm
+}
+
+// Returns a handler recording request count, duration and in-flight
+// requests. The route value is obtained using `rf`.
+func (sm *serverMetrics) handler(next http.Handler, rf RouteFormatter, ft []Filter) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		for _, f := range ft {
+			if !f(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		ctx := r.Context()
+		base := []attribute.KeyValue{
+			lblMethod.String(r.Method),
+			lblRoute.String(rf(r)),
+		}
+		sm.active.Add(ctx, 1, apiMetric.WithAttributes(base...))
+		start := time.Now()
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			sm.record(ctx, base, rw.status, time.Since(start))
+		}()
+		next.ServeHTTP(rw, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+func (sm *serverMetrics) record(ctx context.Context, base []attribute.KeyValue, status int, elapsed time.Duration) {
+	sm.active.Add(ctx, -1, apiMetric.WithAttributes(base...))
+	attrs := apiMetric.WithAttributes(append(base, lblStatus.Int(status))...)
+	sm.requests.Add(ctx, 1, attrs)
+	sm.duration.Record(ctx, elapsed.Seconds(), attrs)
+}
+
+// Capture the status code used for the response. Use `http.ResponseController`
+// to access optional interfaces of the underlying writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	if !sr.wroteHeader {
+		sr.status = code
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	return sr.ResponseWriter.Write(b)
+}
+
+func (sr *statusRecorder) Flush() {
+	if fl, ok := sr.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+	}
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// Default route formatter.
+func routeFormatter(r *http.Request) string {
+	return r.URL.Path
+}
@@ -32,9 +32,12 @@ type Monitor interface {
 
 type httpMonitor struct {
 	nf SpanNameFormatter // span name formatter
+	rf RouteFormatter    // route formatter
 	ft []Filter          // operation filters
 	rt string            // report trace ID
 	ev bool              // report events
+	nm bool              // disable server metrics
+	sm *serverMetrics    // server metrics
 }
 
 // NewMonitor returns a ready to use monitor instance that can be used to
@@ -47,6 +50,9 @@ func NewMonitor(opts ...Option) Monitor {
 	for _, opt := range opts {
 		opt(mon)
 	}
+	if !mon.nm {
+		mon.sm = newServerMetrics()
+	}
 	return mon
 }
 
@@ -82,13 +88,13 @@ func (e *httpMonitor) Client(base http.RoundTripper) http.Client {
 // Handler adds instrumentation to the provided HTTP handler using the
 // `operation` value provided as the span name.
 func (e *httpMonitor) Handler(operation string, handler http.Handler) http.Handler {
-	return contrib.NewHandler(e.getHandler(handler), operation, e.settings()...)
+	return contrib.NewHandler(e.getHandler(handler, operation), operation, e.settings()...)
 }
 
 // HandlerFunc adds instrumentation to the provided HTTP handler function
 // using the `operation` value provided as the span name.
 func (e *httpMonitor) HandlerFunc(operation string, hf http.HandlerFunc) http.Handler {
-	return contrib.NewHandler(e.getHandler(hf), operation, e.settings()...)
+	return contrib.NewHandler(e.getHandler(hf, operation), operation, e.settings()...)
 }
 
 // ServerMiddleware provides a mechanism to easily instrument an HTTP
@@ -103,16 +109,28 @@ func (e *httpMonitor) ServerMiddleware() func(http.Handler) http.Handler {
 		return e.nf(r)
 	}))
 	return func(handler http.Handler) http.Handler {
-		return contrib.NewHandler(e.getHandler(handler), "", options...)
+		return contrib.NewHandler(e.getHandler(handler, ""), "", options...)
 	}
 }
 
-// Returns a handler that, if enabled, reports the trace ID in the response.
-func (e *httpMonitor) getHandler(next http.Handler) http.Handler {
-	if e.rt == "" {
+// Returns a handler that, if enabled, reports the trace ID in the response
+// and records server metrics. When provided, `operation` is used as route
+// for the metrics unless a custom route formatter is set.
+func (e *httpMonitor) getHandler(next http.Handler, operation string) http.Handler {
+	if e.rt != "" {
+		next = reportTraceID(next, e.rt)
+	}
+	if e.sm == nil {
 		return next
 	}
-	return reportTraceID(next, e.rt)
+	rf := e.rf
+	if rf == nil {
+		rf = routeFormatter
+		if operation != "" {
+			rf = func(_ *http.Request) string { return operation }
+		}
+	}
+	return e.sm.handler(next, rf, e.ft)
 }
 
 // Default span name formatter.
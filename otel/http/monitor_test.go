@@ -0,0 +1,81 @@
+package otelhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	apiOtel "go.opentelemetry.io/otel"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestServerMetrics(t *testing.T) {
+	assert := tdd.New(t)
+	reader := sdkMetric.NewManualReader()
+	apiOtel.SetMeterProvider(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+
+	mon := NewMonitor(WithFilter(FilterByPath([]string{"/ping"})))
+	router := http.NewServeMux()
+	router.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	router.HandleFunc("/fail", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := mon.ServerMiddleware()(router)
+	for _, path := range []string{"/ok", "/ok", "/fail", "/ping"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	named := mon.Handler("custom", router)
+	named.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ok", nil))
+
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(reader.Collect(context.Background(), &rm), "collect")
+	got := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != meterName {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			got[m.Name] = m
+		}
+	}
+
+	// Request count by route and status; filtered requests are excluded
+	counts := map[string]int64{}
+	sum, ok := got["http.server.request.count"].Data.(metricdata.Sum[int64])
+	assert.True(ok, "request count")
+	for _, dp := range sum.DataPoints {
+		route, _ := dp.Attributes.Value(lblRoute)
+		status, _ := dp.Attributes.Value(lblStatus)
+		method, _ := dp.Attributes.Value(lblMethod)
+		counts[method.AsString()+" "+route.AsString()+" "+status.Emit()] = dp.Value
+	}
+	assert.Equal(map[string]int64{
+		"GET /ok 200":     2,
+		"GET /fail 418":   1,
+		"POST custom 200": 1,
+	}, counts)
+
+	// Duration histogram
+	hist, ok := got["http.server.request.duration"].Data.(metricdata.Histogram[float64])
+	assert.True(ok, "request duration")
+	assert.Len(hist.DataPoints, 3)
+
+	// No requests in-flight
+	active, ok := got["http.server.active_requests"].Data.(metricdata.Sum[int64])
+	assert.True(ok, "active requests")
+	for _, dp := range active.DataPoints {
+		assert.Equal(int64(0), dp.Value)
+		assert.False(dp.Attributes.HasValue(lblStatus))
+	}
+
+	// Metrics disabled
+	assert.Nil(NewMonitor(WithoutMetrics()).(*httpMonitor).sm)
+}
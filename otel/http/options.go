@@ -52,3 +52,22 @@ func WithFilter(f Filter) Option {
 		mon.ft = append(mon.ft, f)
 	}
 }
+
+// WithRouteFormatter adjust the value used as `http.route` attribute
+// when reporting server metrics. By default the operation name is used
+// for handlers, and the request path for the server middleware. Use a
+// route template (e.g., "/users/{id}") to avoid high-cardinality metrics
+// when paths include variable segments.
+func WithRouteFormatter(rf RouteFormatter) Option {
+	return func(mon *httpMonitor) {
+		mon.rf = rf
+	}
+}
+
+// WithoutMetrics prevents the server handlers from recording request
+// count, duration and in-flight requests metrics.
+func WithoutMetrics() Option {
+	return func(mon *httpMonitor) {
+		mon.nm = true
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Package profiling provides continuous profiling capabilities for instrumented
+applications.
+
+A profiler collects pprof profiles (CPU, heap, goroutines, etc.) on a regular
+interval and submits them to a storage backend using an exporter. Profiles are
+tagged using the resource attributes of the application; using the values
+provided by the SDK setup allows correlating the profiles with the rest of the
+telemetry data (traces, metrics and logs) reported by the application.
+
+	app, _ := sdk.Setup(sdk.WithServiceName("my-service"))
+	exp, _ := profiling.NewPyroscopeExporter(&profiling.PyroscopeOptions{
+		Endpoint: "http://localhost:4040",
+	})
+	profiler, _ := profiling.NewProfiler(&profiling.Options{
+		Profiles:   []profiling.Type{profiling.CPU, profiling.Heap},
+		Attributes: app.ResourceAttributes(),
+		Exporter:   exp,
+	})
+	_ = profiler.Start()
+	defer profiler.Stop()
+
+An exporter for Pyroscope is provided; other backends, like Parca, can be
+supported by implementing the `Exporter` interface. Support for the OTLP
+profiles signal will be added once it is stable.
+*/
+package profiling
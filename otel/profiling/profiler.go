@@ -0,0 +1,217 @@
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/log"
+	"go.bryk.io/pkg/otel"
+)
+
+// Type of profile collected.
+type Type string
+
+const (
+	// CPU profile; collected for the duration of each interval.
+	CPU Type = "cpu"
+
+	// Heap profile; a sample of memory allocations of live objects.
+	Heap Type = "heap"
+
+	// Goroutine profile; stack traces of all current goroutines.
+	Goroutine Type = "goroutine"
+
+	// Mutex profile; stack traces of holders of contended mutexes. Requires
+	// enabling it with `runtime.SetMutexProfileFraction`.
+	Mutex Type = "mutex"
+
+	// Block profile; stack traces that led to blocking on synchronization
+	// primitives. Requires enabling it with `runtime.SetBlockProfileRate`.
+	Block Type = "block"
+)
+
+// Profile collected during a specific time window. Data is encoded
+// using the pprof format.
+type Profile struct {
+	// Type of profile.
+	Type Type
+
+	// Start of the collection window.
+	Start time.Time
+
+	// End of the collection window.
+	End time.Time
+
+	// Profile data in pprof format.
+	Data []byte
+}
+
+// Exporter instances submit the collected profiles to a storage backend.
+type Exporter interface {
+	// Export submits a single profile. Attributes include the resource
+	// details of the profiled application.
+	Export(ctx context.Context, p *Profile, attrs otel.Attributes) error
+}
+
+// Options defines the configuration settings for a profiler instance.
+type Options struct {
+	// Profiles to collect. Defaults to CPU and Heap.
+	Profiles []Type `mapstructure:"profiles" yaml:"profiles" json:"profiles"`
+
+	// Collection interval. Defaults to 15 seconds.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval" json:"interval"`
+
+	// Resource attributes describing the profiled application. Use the
+	// values returned by the SDK setup to correlate profiles with the
+	// rest of the telemetry data.
+	//
+	//	Attributes: app.ResourceAttributes()
+	Attributes otel.Attributes `mapstructure:"attributes" yaml:"attributes" json:"attributes"`
+
+	// Exporter used to submit the collected profiles.
+	Exporter Exporter `mapstructure:"-" yaml:"-" json:"-"`
+
+	// Logger used to report failed collection or export operations.
+	// Discarded by default.
+	Logger log.Logger `mapstructure:"-" yaml:"-" json:"-"`
+}
+
+// Profiler instances collect profiles of the running application on a
+// regular interval and submit them using an exporter.
+type Profiler struct {
+	opts   *Options
+	ctx    context.Context
+	halt   context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	active bool
+}
+
+// NewProfiler returns a new profiler instance. Use `Start` to begin
+// collecting profiles.
+func NewProfiler(opts *Options) (*Profiler, error) {
+	if opts == nil || opts.Exporter == nil {
+		return nil, errors.New("an exporter is required")
+	}
+	if len(opts.Profiles) == 0 {
+		opts.Profiles = []Type{CPU, Heap}
+	}
+	for _, pt := range opts.Profiles {
+		if pt != CPU && pprof.Lookup(string(pt)) == nil {
+			return nil, errors.Errorf("unsupported profile type: %s", pt)
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 15 * time.Second
+	}
+	if opts.Attributes == nil {
+		opts.Attributes = otel.Attributes{}
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Discard()
+	}
+	return &Profiler{opts: opts}, nil
+}
+
+// Start collecting profiles in the background.
+func (pr *Profiler) Start() error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.active {
+		return errors.New("profiler already started")
+	}
+	pr.ctx, pr.halt = context.WithCancel(context.Background())
+	pr.active = true
+	pr.wg.Add(1)
+	go pr.loop()
+	return nil
+}
+
+// Stop collecting profiles. Profiles for the current interval are
+// collected and submitted before returning.
+func (pr *Profiler) Stop() {
+	pr.mu.Lock()
+	if !pr.active {
+		pr.mu.Unlock()
+		return
+	}
+	pr.active = false
+	pr.halt()
+	pr.mu.Unlock()
+	pr.wg.Wait()
+}
+
+func (pr *Profiler) loop() {
+	defer pr.wg.Done()
+	for {
+		done := pr.collect()
+		if done {
+			return
+		}
+	}
+}
+
+// Collect and export profiles for a single interval. Returns true
+// if the profiler was stopped.
+func (pr *Profiler) collect() bool {
+	var (
+		cpu   = new(bytes.Buffer)
+		start = time.Now()
+		done  = false
+	)
+
+	// CPU profiling covers the whole interval
+	cpuActive := pr.enabled(CPU)
+	if cpuActive {
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			cpuActive = false
+			pr.opts.Logger.WithField("error.message", err.Error()).Warning("failed to start CPU profile")
+		}
+	}
+	select {
+	case <-pr.ctx.Done():
+		done = true
+	case <-time.After(pr.opts.Interval):
+	}
+	if cpuActive {
+		pprof.StopCPUProfile()
+	}
+	end := time.Now()
+
+	// submit profiles
+	ctx, cancel := context.WithTimeout(context.Background(), pr.opts.Interval)
+	defer cancel()
+	for _, pt := range pr.opts.Profiles {
+		p := &Profile{Type: pt, Start: start, End: end}
+		if pt == CPU {
+			if !cpuActive {
+				continue
+			}
+			p.Data = cpu.Bytes()
+		} else {
+			buf := new(bytes.Buffer)
+			if err := pprof.Lookup(string(pt)).WriteTo(buf, 0); err != nil {
+				pr.opts.Logger.WithField("error.message", err.Error()).Warningf("failed to collect %s profile", pt)
+				continue
+			}
+			p.Data = buf.Bytes()
+		}
+		if err := pr.opts.Exporter.Export(ctx, p, pr.opts.Attributes); err != nil {
+			pr.opts.Logger.WithField("error.message", err.Error()).Warningf("failed to export %s profile", pt)
+		}
+	}
+	return done
+}
+
+func (pr *Profiler) enabled(pt Type) bool {
+	for _, el := range pr.opts.Profiles {
+		if el == pt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package profiling
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/otel"
+)
+
+// Exporter keeping all profiles in memory.
+type recorder struct {
+	mu       sync.Mutex
+	profiles []*Profile
+}
+
+func (r *recorder) Export(_ context.Context, p *Profile, _ otel.Attributes) error {
+	r.mu.Lock()
+	r.profiles = append(r.profiles, p)
+	r.mu.Unlock()
+	return nil
+}
+
+func TestProfiler(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Invalid settings
+	_, err := NewProfiler(&Options{})
+	assert.NotNil(err, "missing exporter")
+	_, err = NewProfiler(&Options{Exporter: new(recorder), Profiles: []Type{"invalid"}})
+	assert.NotNil(err, "invalid profile type")
+
+	// Collect profiles
+	rec := new(recorder)
+	pr, err := NewProfiler(&Options{
+		Profiles: []Type{CPU, Heap, Goroutine},
+		Interval: 100 * time.Millisecond,
+		Exporter: rec,
+	})
+	assert.Nil(err, "new profiler")
+	assert.Nil(pr.Start(), "start")
+	assert.NotNil(pr.Start(), "already started")
+	time.Sleep(250 * time.Millisecond)
+	pr.Stop()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.GreaterOrEqual(len(rec.profiles), 6, "profiles collected")
+	for _, p := range rec.profiles {
+		assert.NotEmpty(p.Data, "profile data")
+		assert.True(p.End.After(p.Start), "collection window")
+	}
+}
+
+func TestPyroscope(t *testing.T) {
+	assert := tdd.New(t)
+
+	var (
+		name    string
+		tenant  string
+		payload []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		name = rq.URL.Query().Get("name")
+		tenant = rq.Header.Get("X-Scope-OrgID")
+		if f, _, err := rq.FormFile("profile"); err == nil {
+			payload, _ = io.ReadAll(f)
+		}
+	}))
+	defer srv.Close()
+
+	_, err := NewPyroscopeExporter(&PyroscopeOptions{})
+	assert.NotNil(err, "missing endpoint")
+
+	exp, err := NewPyroscopeExporter(&PyroscopeOptions{
+		Endpoint: srv.URL,
+		TenantID: "team-a",
+	})
+	assert.Nil(err, "new exporter")
+	attrs := otel.Attributes{
+		"service.name":           "my-service",
+		"service.version":        "0.1.0",
+		"deployment.environment": "dev",
+	}
+	p := &Profile{Type: Heap, Start: time.Now(), End: time.Now(), Data: []byte("pprof-data")}
+	assert.Nil(exp.Export(context.Background(), p, attrs))
+	assert.Equal("my-service{deployment_environment=dev,service_version=0.1.0}", name)
+	assert.Equal("team-a", tenant)
+	assert.Equal([]byte("pprof-data"), payload)
+}
@@ -0,0 +1,154 @@
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.bryk.io/pkg/otel"
+)
+
+// Default name used when no `service.name` attribute is available.
+const unknownService = "unknown_service"
+
+// PyroscopeOptions defines the configuration settings to submit profiles to
+// a Pyroscope server.
+type PyroscopeOptions struct {
+	// Server endpoint. For example: "http://localhost:4040".
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+
+	// Bearer token used for authentication, if required.
+	AuthToken string `mapstructure:"auth_token" yaml:"auth_token" json:"auth_token"`
+
+	// Basic authentication credentials, if required. Used by Grafana Cloud.
+	BasicAuthUser     string `mapstructure:"basic_auth_user" yaml:"basic_auth_user" json:"basic_auth_user"`
+	BasicAuthPassword string `mapstructure:"basic_auth_password" yaml:"basic_auth_password" json:"basic_auth_password"`
+
+	// Tenant identifier for multi-tenant deployments.
+	TenantID string `mapstructure:"tenant_id" yaml:"tenant_id" json:"tenant_id"`
+
+	// HTTP client used; a client with a 10 seconds timeout is used
+	// by default.
+	Client *http.Client `mapstructure:"-" yaml:"-" json:"-"`
+}
+
+type pyroscope struct {
+	opts     *PyroscopeOptions
+	endpoint *url.URL
+}
+
+// NewPyroscopeExporter returns an exporter that submits profiles to a
+// Pyroscope server using its ingestion API. Profiles are reported using
+// the `service.name` attribute as application name, and all other resource
+// attributes as tags.
+//
+// https://grafana.com/docs/pyroscope/latest/reference-server-api/
+func NewPyroscopeExporter(opts *PyroscopeOptions) (Exporter, error) {
+	if opts == nil || opts.Endpoint == "" {
+		return nil, errors.New("an endpoint is required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/") + "/ingest")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid endpoint")
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &pyroscope{opts: opts, endpoint: endpoint}, nil
+}
+
+func (ps *pyroscope) Export(ctx context.Context, p *Profile, attrs otel.Attributes) error {
+	// profile data is submitted as a multipart form
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+	fw, err := form.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+	if _, err = fw.Write(p.Data); err != nil {
+		return err
+	}
+	if err = form.Close(); err != nil {
+		return err
+	}
+
+	// series details are provided as query parameters
+	query := url.Values{}
+	query.Set("name", seriesName(attrs))
+	query.Set("from", strconv.FormatInt(p.Start.Unix(), 10))
+	query.Set("until", strconv.FormatInt(p.End.Unix(), 10))
+	query.Set("format", "pprof")
+	query.Set("spyName", "gospy")
+	if p.Type == CPU {
+		query.Set("sampleRate", "100") // default CPU profiling rate
+	}
+	endpoint := *ps.endpoint
+	endpoint.RawQuery = query.Encode()
+
+	// submit request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if ps.opts.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+ps.opts.AuthToken)
+	}
+	if ps.opts.BasicAuthUser != "" {
+		req.SetBasicAuth(ps.opts.BasicAuthUser, ps.opts.BasicAuthPassword)
+	}
+	if ps.opts.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", ps.opts.TenantID)
+	}
+	res, err := ps.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Series name using the Pyroscope format: `app{tag=value,...}`. Tag names
+// are restricted to alphanumeric characters and underscores.
+func seriesName(attrs otel.Attributes) string {
+	app := unknownService
+	if name, ok := attrs.Get("service.name").(string); ok && name != "" {
+		app = name
+	}
+	tags := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		if k == "service.name" {
+			continue
+		}
+		value := strings.NewReplacer(",", "_", "{", "_", "}", "_", "=", "_").Replace(fmt.Sprint(v))
+		tags = append(tags, fmt.Sprintf("%s=%s", tagName(k), value))
+	}
+	sort.Strings(tags)
+	return fmt.Sprintf("%s{%s}", app, strings.Join(tags, ","))
+}
+
+func tagName(k string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, k)
+}
@@ -1,4 +1,19 @@
 /*
 Package prometheus provides utilities to collect and consume metrics (instrumentation data).
+
+Histograms produced by the operator can include exemplars, linking individual
+observations to the active trace, and can optionally be emitted as native
+histograms.
+
+	prom, _ := NewOperatorWithOptions(Options{
+		Exemplars:        true,
+		NativeHistograms: true,
+	})
+
+	// instrument gRPC servers
+	rpcOpts := []rpc.ServerOption{rpc.WithPrometheus(prom)}
+
+	// instrument HTTP servers and expose the collected metrics
+	httpOpts := []http.Option{http.WithPrometheus(prom, "/metrics")}
 */
 package prometheus
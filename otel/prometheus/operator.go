@@ -1,16 +1,20 @@
 package prometheus
 
 import (
+	"context"
 	"net/http"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
-	gp "github.com/grpc-ecosystem/go-grpc-prometheus"
+	gp "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	dto "github.com/prometheus/client_model/go"
 	"go.bryk.io/pkg/log"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -31,43 +35,100 @@ type Operator interface {
 	// Client returns the unary and stream interceptor required to instrument a
 	// gRPC client instance. Captured metrics include histograms by default; this
 	// allows calculating service latency but is expensive.
-	//   https://github.com/grpc-ecosystem/go-grpc-prometheus#histograms
+	//   https://github.com/grpc-ecosystem/go-grpc-middleware/tree/main/providers/prometheus
 	Client() (grpc.UnaryClientInterceptor, grpc.StreamClientInterceptor)
 
 	// Server returns required gRPC interceptors to instrument a server instance.
 	// Captured metrics include histograms by default; this allows calculating service
 	// latency but is expensive.
-	//   https://github.com/grpc-ecosystem/go-grpc-prometheus#histograms
+	//   https://github.com/grpc-ecosystem/go-grpc-middleware/tree/main/providers/prometheus
 	//
 	// Example Grafana base dashboard:
 	//   https://grafana.com/grafana/dashboards/9186
 	Server() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor)
+
+	// HTTPMiddleware returns a middleware to instrument HTTP handlers. Captured
+	// metrics include the number of requests handled and their latency, by
+	// method and status code; as "http_requests_total" and
+	// "http_request_duration_seconds" respectively.
+	HTTPMiddleware() func(http.Handler) http.Handler
+}
+
+// Options available to adjust the behavior of an operator instance.
+type Options struct {
+	// Metrics registry. If not provided, a new empty one will be created.
+	Registry *prometheus.Registry
+
+	// Additional metric collectors to register.
+	Collectors []prometheus.Collector
+
+	// Attach exemplars to counters and histograms, linking observations to
+	// the active (sampled) trace using its "trace_id" and "span_id". Exemplars
+	// are only exposed using the OpenMetrics format, which is enabled
+	// automatically when this option is set. Disabled by default.
+	Exemplars bool
+
+	// Emit native histograms, in addition to the classic ones. Native
+	// histograms are only exposed using the protobuf format and require the
+	// feature to be enabled in the Prometheus server.
+	//   https://prometheus.io/docs/concepts/metric_types/#histogram
+	NativeHistograms bool
+
+	// Growth factor between native histogram buckets. Defaults to 1.1.
+	NativeHistogramBucketFactor float64
+
+	// Maximum number of native histogram buckets. Defaults to 160.
+	NativeHistogramMaxBuckets uint32
 }
 
 // Prometheus support capabilities. These are optional and abstracted away
 // from the main operator instance.
 type handler struct {
-	registry   *prometheus.Registry   // Main metrics registry
-	extras     []prometheus.Collector // User-provided metric collectors
-	srvMetrics *gp.ServerMetrics      // Server metrics
-	cltMetrics *gp.ClientMetrics      // Client metrics
+	opts        Options                // Operator settings
+	registry    *prometheus.Registry   // Main metrics registry
+	extras      []prometheus.Collector // User-provided metric collectors
+	srvMetrics  *gp.ServerMetrics      // Server metrics
+	cltMetrics  *gp.ClientMetrics      // Client metrics
+	httpMetrics *httpMetrics           // HTTP server metrics
+	mu          sync.Mutex
 }
 
 // NewOperator returns a ready-to-use operator instance. An operator allows to
 // easily collect and consume instrumentation data. Host and runtime metrics are
 // collected by default, in addition to any additional collector provided. If you
 // don't provide a prometheus registry `reg`, a new empty one will be created by
-// default.
+// default. Use `NewOperatorWithOptions` to enable exemplars or native histograms.
 //
 //	prom, _ := pkg.NewOperator(prometheus.NewRegistry())
 //	opts := []rpc.ServerOption{WithPrometheus(prom)}
 func NewOperator(reg *prometheus.Registry, cols ...prometheus.Collector) (Operator, error) {
-	if reg == nil {
-		reg = prometheus.NewRegistry()
+	return NewOperatorWithOptions(Options{
+		Registry:   reg,
+		Collectors: cols,
+	})
+}
+
+// NewOperatorWithOptions returns a ready-to-use operator instance using the
+// provided settings.
+//
+//	prom, _ := pkg.NewOperatorWithOptions(Options{
+//		Exemplars:        true,
+//		NativeHistograms: true,
+//	})
+func NewOperatorWithOptions(opts Options) (Operator, error) {
+	if opts.Registry == nil {
+		opts.Registry = prometheus.NewRegistry()
+	}
+	if opts.NativeHistogramBucketFactor <= 1 {
+		opts.NativeHistogramBucketFactor = 1.1
+	}
+	if opts.NativeHistogramMaxBuckets == 0 {
+		opts.NativeHistogramMaxBuckets = 160
 	}
 	ps := &handler{
-		registry: reg,
-		extras:   append([]prometheus.Collector{}, cols...),
+		opts:     opts,
+		registry: opts.Registry,
+		extras:   append([]prometheus.Collector{}, opts.Collectors...),
 	}
 	if err := ps.init(); err != nil {
 		return nil, err
@@ -119,7 +180,7 @@ func (ps *handler) MetricsHandler() http.Handler {
 		DisableCompression:  false,                    // Always use compression
 		MaxRequestsInFlight: 10,                       // Maximum number of simultaneous requests
 		Timeout:             5 * time.Second,          // If exceeded, respond with a 503 ServiceUnavailable
-		EnableOpenMetrics:   false,                    // OpenMetrics support
+		EnableOpenMetrics:   ps.opts.Exemplars,        // OpenMetrics support, required for exemplars
 	})
 }
 
@@ -132,26 +193,155 @@ func (ps *handler) InitializeMetrics(srv *grpc.Server) {
 
 func (ps *handler) Client() (grpc.UnaryClientInterceptor, grpc.StreamClientInterceptor) {
 	// Register client metrics
+	ps.mu.Lock()
 	if ps.cltMetrics == nil {
-		ps.cltMetrics = gp.NewClientMetrics()
-		ps.cltMetrics.EnableClientHandlingTimeHistogram()
+		ps.cltMetrics = gp.NewClientMetrics(gp.WithClientHandlingTimeHistogram(ps.histogramOpts))
 		_ = ps.registry.Register(prometheus.Collector(ps.cltMetrics))
 	}
+	ps.mu.Unlock()
 
 	// Return interceptors
-	return ps.cltMetrics.UnaryClientInterceptor(), ps.cltMetrics.StreamClientInterceptor()
+	opts := ps.interceptorOpts()
+	return ps.cltMetrics.UnaryClientInterceptor(opts...), ps.cltMetrics.StreamClientInterceptor(opts...)
 }
 
 func (ps *handler) Server() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
 	// Register server metrics
+	ps.mu.Lock()
 	if ps.srvMetrics == nil {
-		ps.srvMetrics = gp.NewServerMetrics()
-		ps.srvMetrics.EnableHandlingTimeHistogram()
+		ps.srvMetrics = gp.NewServerMetrics(gp.WithServerHandlingTimeHistogram(ps.histogramOpts))
 		_ = ps.registry.Register(prometheus.Collector(ps.srvMetrics))
 	}
+	ps.mu.Unlock()
 
 	// Return interceptors
-	return ps.srvMetrics.UnaryServerInterceptor(), ps.srvMetrics.StreamServerInterceptor()
+	opts := ps.interceptorOpts()
+	return ps.srvMetrics.UnaryServerInterceptor(opts...), ps.srvMetrics.StreamServerInterceptor(opts...)
+}
+
+func (ps *handler) HTTPMiddleware() func(http.Handler) http.Handler {
+	// Register HTTP metrics
+	ps.mu.Lock()
+	if ps.httpMetrics == nil {
+		ps.httpMetrics = newHTTPMetrics(ps.histogramOpts)
+		_ = ps.registry.Register(prometheus.Collector(ps.httpMetrics))
+	}
+	hm := ps.httpMetrics
+	ps.mu.Unlock()
+
+	// Return middleware
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			hm.observe(r.Method, rec.status, time.Since(start), ps.exemplar(r.Context()))
+		})
+	}
+}
+
+// Options used by the gRPC interceptors.
+func (ps *handler) interceptorOpts() []gp.Option {
+	if !ps.opts.Exemplars {
+		return nil
+	}
+	return []gp.Option{gp.WithExemplarFromContext(ps.exemplar)}
+}
+
+// Adjust the settings used by all histograms produced by the operator.
+func (ps *handler) histogramOpts(opts *prometheus.HistogramOpts) {
+	if !ps.opts.NativeHistograms {
+		return
+	}
+	opts.NativeHistogramBucketFactor = ps.opts.NativeHistogramBucketFactor
+	opts.NativeHistogramMaxBucketNumber = ps.opts.NativeHistogramMaxBuckets
+	opts.NativeHistogramMinResetDuration = time.Hour
+}
+
+// Exemplar labels linking an observation to the active trace, if any.
+func (ps *handler) exemplar(ctx context.Context) prometheus.Labels {
+	if !ps.opts.Exemplars {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsSampled() {
+		return nil
+	}
+	return prometheus.Labels{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}
+}
+
+// Metrics captured for HTTP handlers.
+type httpMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newHTTPMetrics(adjust func(*prometheus.HistogramOpts)) *httpMetrics {
+	ho := prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Histogram of latency (seconds) of HTTP requests handled by the server.",
+		Buckets: prometheus.DefBuckets,
+	}
+	adjust(&ho)
+	return &httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests handled by the server.",
+		}, []string{"method", "code"}),
+		duration: prometheus.NewHistogramVec(ho, []string{"method", "code"}),
+	}
+}
+
+func (hm *httpMetrics) Describe(ch chan<- *prometheus.Desc) {
+	hm.requests.Describe(ch)
+	hm.duration.Describe(ch)
+}
+
+func (hm *httpMetrics) Collect(ch chan<- prometheus.Metric) {
+	hm.requests.Collect(ch)
+	hm.duration.Collect(ch)
+}
+
+func (hm *httpMetrics) observe(method string, status int, elapsed time.Duration, exemplar prometheus.Labels) {
+	code := strconv.Itoa(status)
+	counter := hm.requests.WithLabelValues(method, code)
+	histogram := hm.duration.WithLabelValues(method, code)
+	if exemplar == nil {
+		counter.Inc()
+		histogram.Observe(elapsed.Seconds())
+		return
+	}
+	counter.(prometheus.ExemplarAdder).AddWithExemplar(1, exemplar)
+	histogram.(prometheus.ExemplarObserver).ObserveWithExemplar(elapsed.Seconds(), exemplar)
+}
+
+// Capture the status code of an HTTP response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	if !sr.wroteHeader {
+		sr.status = code
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *statusRecorder) Flush() {
+	if fl, ok := sr.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+	}
+}
+
+// Unwrap returns the original writer; used by `http.ResponseController`.
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
 }
 
 // Minimal prometheus error logger implementation.
@@ -0,0 +1,107 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	tdd "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+func TestDefaults(t *testing.T) {
+	assert := tdd.New(t)
+	op, err := NewOperator(prometheus.NewRegistry())
+	assert.Nil(err, "new operator")
+
+	// gRPC metrics keep the same names and labels
+	ctx := context.Background()
+	method := "/sample.v1.EchoAPI/Ping"
+	si, _ := op.Server()
+	_, err = si(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(_ context.Context, _ interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Nil(err)
+	ci, _ := op.Client()
+	err = ci(ctx, method, nil, nil, nil, func(_ context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error { // nolint:lll
+		return nil
+	})
+	assert.Nil(err)
+	mf, err := op.GatherMetrics()
+	assert.Nil(err, "gather metrics")
+	labels := map[string][]string{}
+	for _, f := range mf {
+		for _, l := range f.GetMetric()[0].GetLabel() {
+			labels[f.GetName()] = append(labels[f.GetName()], l.GetName())
+		}
+	}
+	common := []string{"grpc_method", "grpc_service", "grpc_type"}
+	withCode := []string{"grpc_code", "grpc_method", "grpc_service", "grpc_type"}
+	for _, side := range []string{"server", "client"} {
+		assert.Equal(common, labels["grpc_"+side+"_started_total"])
+		assert.Equal(withCode, labels["grpc_"+side+"_handled_total"])
+		assert.Equal(common, labels["grpc_"+side+"_msg_received_total"])
+		assert.Equal(common, labels["grpc_"+side+"_msg_sent_total"])
+		assert.Equal(common, labels["grpc_"+side+"_handling_seconds"])
+	}
+
+	// OpenMetrics is not negotiated unless exemplars are enabled
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	op.MetricsHandler().ServeHTTP(rec, req)
+	assert.False(strings.HasPrefix(rec.Header().Get("Content-Type"), "application/openmetrics-text"))
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	assert := tdd.New(t)
+	op, err := NewOperatorWithOptions(Options{
+		Exemplars:        true,
+		NativeHistograms: true,
+	})
+	assert.Nil(err, "new operator")
+
+	// Instrumented handler
+	handler := op.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	tid, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	sid, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/users", nil).WithContext(ctx)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Collected metrics
+	mf, err := op.GatherMetrics()
+	assert.Nil(err, "gather metrics")
+	found := false
+	for _, f := range mf {
+		if f.GetName() != "http_request_duration_seconds" {
+			continue
+		}
+		found = true
+		m := f.GetMetric()[0]
+		assert.Len(m.GetLabel(), 2)
+		assert.Equal("404", m.GetLabel()[0].GetValue(), "status code")
+		assert.Equal(uint64(1), m.GetHistogram().GetSampleCount())
+		assert.NotZero(m.GetHistogram().GetSchema(), "native histogram")
+	}
+	assert.True(found, "missing HTTP metrics")
+
+	// Exemplars are exposed using OpenMetrics
+	rec := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	op.MetricsHandler().ServeHTTP(rec, req)
+	body, _ := io.ReadAll(rec.Result().Body)
+	assert.True(strings.Contains(string(body), `trace_id="4bf92f3577b34da6a3ce929d0e0e4736"`), "exemplar")
+}
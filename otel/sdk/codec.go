@@ -0,0 +1,660 @@
+package sdk
+
+import (
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkResource "go.opentelemetry.io/otel/sdk/resource"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metrics are stored by persistent queues using the OTLP protobuf
+// representation, i.e., as 'ExportMetricsServiceRequest' payloads, and
+// restored to their original types before being submitted to the exporters.
+// OTLP encodes histogram sums and extrema as floating point values, so the
+// aggregations using integer values are marked using the metric metadata.
+
+// Metadata entry used to mark aggregations using integer values.
+const metricValueTypeKey = "otel.sdk.queue.value_type"
+
+// Encode a metrics collection.
+func encodeMetrics(rm *metricdata.ResourceMetrics) ([]byte, error) {
+	res := &resourcepb.Resource{}
+	schema := ""
+	if rm.Resource != nil {
+		res.Attributes = encodeAttrs(rm.Resource.Attributes())
+		schema = rm.Resource.SchemaURL()
+	}
+	scopes := make([]*metricpb.ScopeMetrics, len(rm.ScopeMetrics))
+	for i, sm := range rm.ScopeMetrics {
+		list := make([]*metricpb.Metric, len(sm.Metrics))
+		for j, m := range sm.Metrics {
+			em, err := encodeMetric(m)
+			if err != nil {
+				return nil, err
+			}
+			list[j] = em
+		}
+		scopes[i] = &metricpb.ScopeMetrics{
+			Scope: &commonpb.InstrumentationScope{
+				Name:       sm.Scope.Name,
+				Version:    sm.Scope.Version,
+				Attributes: encodeAttrs(sm.Scope.Attributes.ToSlice()),
+			},
+			SchemaUrl: sm.Scope.SchemaURL,
+			Metrics:   list,
+		}
+	}
+	return proto.Marshal(&colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{{
+			Resource:     res,
+			ScopeMetrics: scopes,
+			SchemaUrl:    schema,
+		}},
+	})
+}
+
+// Restore a metrics collection.
+func decodeMetrics(data []byte) ([]*metricdata.ResourceMetrics, error) {
+	req := new(colmetricpb.ExportMetricsServiceRequest)
+	if err := proto.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	list := make([]*metricdata.ResourceMetrics, len(req.ResourceMetrics))
+	for i, rm := range req.ResourceMetrics {
+		attrs, err := decodeAttrs(rm.GetResource().GetAttributes())
+		if err != nil {
+			return nil, err
+		}
+		scopes := make([]metricdata.ScopeMetrics, len(rm.ScopeMetrics))
+		for j, sm := range rm.ScopeMetrics {
+			scopeAttrs, err := decodeAttrs(sm.GetScope().GetAttributes())
+			if err != nil {
+				return nil, err
+			}
+			metrics := make([]metricdata.Metrics, len(sm.Metrics))
+			for k, m := range sm.Metrics {
+				if metrics[k], err = decodeMetric(m); err != nil {
+					return nil, err
+				}
+			}
+			scope := instrumentation.Scope{
+				Name:      sm.GetScope().GetName(),
+				Version:   sm.GetScope().GetVersion(),
+				SchemaURL: sm.SchemaUrl,
+			}
+			if len(scopeAttrs) > 0 {
+				scope.Attributes = attribute.NewSet(scopeAttrs...)
+			}
+			scopes[j] = metricdata.ScopeMetrics{Scope: scope, Metrics: metrics}
+		}
+		list[i] = &metricdata.ResourceMetrics{
+			Resource:     sdkResource.NewWithAttributes(rm.SchemaUrl, attrs...),
+			ScopeMetrics: scopes,
+		}
+	}
+	return list, nil
+}
+
+func encodeMetric(m metricdata.Metrics) (*metricpb.Metric, error) {
+	em := &metricpb.Metric{
+		Name:        m.Name,
+		Description: m.Description,
+		Unit:        m.Unit,
+	}
+	switch agg := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		em.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: encodePoints(agg.DataPoints)}}
+	case metricdata.Gauge[float64]:
+		em.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: encodePoints(agg.DataPoints)}}
+	case metricdata.Sum[int64]:
+		em.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			DataPoints:             encodePoints(agg.DataPoints),
+			AggregationTemporality: encodeTemporality(agg.Temporality),
+			IsMonotonic:            agg.IsMonotonic,
+		}}
+	case metricdata.Sum[float64]:
+		em.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			DataPoints:             encodePoints(agg.DataPoints),
+			AggregationTemporality: encodeTemporality(agg.Temporality),
+			IsMonotonic:            agg.IsMonotonic,
+		}}
+	case metricdata.Histogram[int64]:
+		em.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			DataPoints:             encodeHistogramPoints(agg.DataPoints),
+			AggregationTemporality: encodeTemporality(agg.Temporality),
+		}}
+	case metricdata.Histogram[float64]:
+		em.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			DataPoints:             encodeHistogramPoints(agg.DataPoints),
+			AggregationTemporality: encodeTemporality(agg.Temporality),
+		}}
+	case metricdata.ExponentialHistogram[int64]:
+		em.Data = &metricpb.Metric_ExponentialHistogram{ExponentialHistogram: &metricpb.ExponentialHistogram{
+			DataPoints:             encodeExpHistogramPoints(agg.DataPoints),
+			AggregationTemporality: encodeTemporality(agg.Temporality),
+		}}
+	case metricdata.ExponentialHistogram[float64]:
+		em.Data = &metricpb.Metric_ExponentialHistogram{ExponentialHistogram: &metricpb.ExponentialHistogram{
+			DataPoints:             encodeExpHistogramPoints(agg.DataPoints),
+			AggregationTemporality: encodeTemporality(agg.Temporality),
+		}}
+	case metricdata.Summary:
+		em.Data = &metricpb.Metric_Summary{Summary: &metricpb.Summary{
+			DataPoints: encodeSummaryPoints(agg.DataPoints),
+		}}
+	default:
+		return nil, errors.Errorf("unsupported aggregation for metric '%s': %T", m.Name, m.Data)
+	}
+	switch m.Data.(type) {
+	case metricdata.Gauge[int64], metricdata.Sum[int64],
+		metricdata.Histogram[int64], metricdata.ExponentialHistogram[int64]:
+		em.Metadata = encodeAttrs([]attribute.KeyValue{attribute.String(metricValueTypeKey, "int")})
+	}
+	return em, nil
+}
+
+func decodeMetric(m *metricpb.Metric) (metricdata.Metrics, error) {
+	dm := metricdata.Metrics{
+		Name:        m.Name,
+		Description: m.Description,
+		Unit:        m.Unit,
+	}
+	isInt := false
+	for _, kv := range m.Metadata {
+		if kv.Key == metricValueTypeKey && kv.GetValue().GetStringValue() == "int" {
+			isInt = true
+		}
+	}
+	var err error
+	switch data := m.Data.(type) {
+	case *metricpb.Metric_Gauge:
+		if isInt {
+			dm.Data, err = decodeGauge[int64](data.Gauge)
+		} else {
+			dm.Data, err = decodeGauge[float64](data.Gauge)
+		}
+	case *metricpb.Metric_Sum:
+		if isInt {
+			dm.Data, err = decodeSum[int64](data.Sum)
+		} else {
+			dm.Data, err = decodeSum[float64](data.Sum)
+		}
+	case *metricpb.Metric_Histogram:
+		if isInt {
+			dm.Data, err = decodeHistogram[int64](data.Histogram)
+		} else {
+			dm.Data, err = decodeHistogram[float64](data.Histogram)
+		}
+	case *metricpb.Metric_ExponentialHistogram:
+		if isInt {
+			dm.Data, err = decodeExpHistogram[int64](data.ExponentialHistogram)
+		} else {
+			dm.Data, err = decodeExpHistogram[float64](data.ExponentialHistogram)
+		}
+	case *metricpb.Metric_Summary:
+		dm.Data, err = decodeSummary(data.Summary)
+	default:
+		err = errors.Errorf("unsupported aggregation for metric '%s'", m.Name)
+	}
+	return dm, err
+}
+
+func decodeGauge[N int64 | float64](g *metricpb.Gauge) (metricdata.Gauge[N], error) {
+	points, err := decodePoints[N](g.GetDataPoints())
+	return metricdata.Gauge[N]{DataPoints: points}, err
+}
+
+func decodeSum[N int64 | float64](s *metricpb.Sum) (metricdata.Sum[N], error) {
+	points, err := decodePoints[N](s.GetDataPoints())
+	return metricdata.Sum[N]{
+		DataPoints:  points,
+		Temporality: decodeTemporality(s.GetAggregationTemporality()),
+		IsMonotonic: s.GetIsMonotonic(),
+	}, err
+}
+
+func decodeHistogram[N int64 | float64](h *metricpb.Histogram) (metricdata.Histogram[N], error) {
+	points, err := decodeHistogramPoints[N](h.GetDataPoints())
+	return metricdata.Histogram[N]{
+		DataPoints:  points,
+		Temporality: decodeTemporality(h.GetAggregationTemporality()),
+	}, err
+}
+
+func decodeExpHistogram[N int64 | float64](h *metricpb.ExponentialHistogram) (metricdata.ExponentialHistogram[N], error) { // nolint:lll
+	points, err := decodeExpHistogramPoints[N](h.GetDataPoints())
+	return metricdata.ExponentialHistogram[N]{
+		DataPoints:  points,
+		Temporality: decodeTemporality(h.GetAggregationTemporality()),
+	}, err
+}
+
+func decodeSummary(s *metricpb.Summary) (metricdata.Summary, error) {
+	list := make([]metricdata.SummaryDataPoint, len(s.GetDataPoints()))
+	for i, p := range s.GetDataPoints() {
+		attrs, err := decodeAttrs(p.Attributes)
+		if err != nil {
+			return metricdata.Summary{}, err
+		}
+		quantiles := make([]metricdata.QuantileValue, len(p.QuantileValues))
+		for j, q := range p.QuantileValues {
+			quantiles[j] = metricdata.QuantileValue{Quantile: q.Quantile, Value: q.Value}
+		}
+		list[i] = metricdata.SummaryDataPoint{
+			Attributes:     attribute.NewSet(attrs...),
+			StartTime:      decodeTime(p.StartTimeUnixNano),
+			Time:           decodeTime(p.TimeUnixNano),
+			Count:          p.Count,
+			Sum:            p.Sum,
+			QuantileValues: quantiles,
+		}
+	}
+	return metricdata.Summary{DataPoints: list}, nil
+}
+
+func encodePoints[N int64 | float64](list []metricdata.DataPoint[N]) []*metricpb.NumberDataPoint {
+	points := make([]*metricpb.NumberDataPoint, len(list))
+	for i, p := range list {
+		dp := &metricpb.NumberDataPoint{
+			Attributes:        encodeAttrs(p.Attributes.ToSlice()),
+			StartTimeUnixNano: encodeTime(p.StartTime),
+			TimeUnixNano:      encodeTime(p.Time),
+			Exemplars:         encodeExemplars(p.Exemplars),
+		}
+		switch v := any(p.Value).(type) {
+		case int64:
+			dp.Value = &metricpb.NumberDataPoint_AsInt{AsInt: v}
+		case float64:
+			dp.Value = &metricpb.NumberDataPoint_AsDouble{AsDouble: v}
+		}
+		points[i] = dp
+	}
+	return points
+}
+
+func decodePoints[N int64 | float64](list []*metricpb.NumberDataPoint) ([]metricdata.DataPoint[N], error) {
+	points := make([]metricdata.DataPoint[N], len(list))
+	for i, p := range list {
+		attrs, err := decodeAttrs(p.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		exemplars, err := decodeExemplars[N](p.Exemplars)
+		if err != nil {
+			return nil, err
+		}
+		var value N
+		switch v := p.Value.(type) {
+		case *metricpb.NumberDataPoint_AsInt:
+			value = N(v.AsInt)
+		case *metricpb.NumberDataPoint_AsDouble:
+			value = N(v.AsDouble)
+		}
+		points[i] = metricdata.DataPoint[N]{
+			Attributes: attribute.NewSet(attrs...),
+			StartTime:  decodeTime(p.StartTimeUnixNano),
+			Time:       decodeTime(p.TimeUnixNano),
+			Value:      value,
+			Exemplars:  exemplars,
+		}
+	}
+	return points, nil
+}
+
+func encodeHistogramPoints[N int64 | float64](list []metricdata.HistogramDataPoint[N]) []*metricpb.HistogramDataPoint {
+	points := make([]*metricpb.HistogramDataPoint, len(list))
+	for i, p := range list {
+		sum := float64(p.Sum)
+		points[i] = &metricpb.HistogramDataPoint{
+			Attributes:        encodeAttrs(p.Attributes.ToSlice()),
+			StartTimeUnixNano: encodeTime(p.StartTime),
+			TimeUnixNano:      encodeTime(p.Time),
+			Count:             p.Count,
+			Sum:               &sum,
+			BucketCounts:      p.BucketCounts,
+			ExplicitBounds:    p.Bounds,
+			Exemplars:         encodeExemplars(p.Exemplars),
+			Min:               encodeExtrema(p.Min),
+			Max:               encodeExtrema(p.Max),
+		}
+	}
+	return points
+}
+
+func decodeHistogramPoints[N int64 | float64](list []*metricpb.HistogramDataPoint) ([]metricdata.HistogramDataPoint[N], error) { // nolint:lll
+	points := make([]metricdata.HistogramDataPoint[N], len(list))
+	for i, p := range list {
+		attrs, err := decodeAttrs(p.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		exemplars, err := decodeExemplars[N](p.Exemplars)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = metricdata.HistogramDataPoint[N]{
+			Attributes:   attribute.NewSet(attrs...),
+			StartTime:    decodeTime(p.StartTimeUnixNano),
+			Time:         decodeTime(p.TimeUnixNano),
+			Count:        p.Count,
+			Bounds:       p.ExplicitBounds,
+			BucketCounts: p.BucketCounts,
+			Min:          decodeExtrema[N](p.Min),
+			Max:          decodeExtrema[N](p.Max),
+			Sum:          N(p.GetSum()),
+			Exemplars:    exemplars,
+		}
+	}
+	return points, nil
+}
+
+func encodeExpHistogramPoints[N int64 | float64](list []metricdata.ExponentialHistogramDataPoint[N]) []*metricpb.ExponentialHistogramDataPoint { // nolint:lll
+	points := make([]*metricpb.ExponentialHistogramDataPoint, len(list))
+	for i, p := range list {
+		sum := float64(p.Sum)
+		points[i] = &metricpb.ExponentialHistogramDataPoint{
+			Attributes:        encodeAttrs(p.Attributes.ToSlice()),
+			StartTimeUnixNano: encodeTime(p.StartTime),
+			TimeUnixNano:      encodeTime(p.Time),
+			Count:             p.Count,
+			Sum:               &sum,
+			Scale:             p.Scale,
+			ZeroCount:         p.ZeroCount,
+			Positive: &metricpb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       p.PositiveBucket.Offset,
+				BucketCounts: p.PositiveBucket.Counts,
+			},
+			Negative: &metricpb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       p.NegativeBucket.Offset,
+				BucketCounts: p.NegativeBucket.Counts,
+			},
+			Exemplars:     encodeExemplars(p.Exemplars),
+			Min:           encodeExtrema(p.Min),
+			Max:           encodeExtrema(p.Max),
+			ZeroThreshold: p.ZeroThreshold,
+		}
+	}
+	return points
+}
+
+func decodeExpHistogramPoints[N int64 | float64](list []*metricpb.ExponentialHistogramDataPoint) ([]metricdata.ExponentialHistogramDataPoint[N], error) { // nolint:lll
+	points := make([]metricdata.ExponentialHistogramDataPoint[N], len(list))
+	for i, p := range list {
+		attrs, err := decodeAttrs(p.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		exemplars, err := decodeExemplars[N](p.Exemplars)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = metricdata.ExponentialHistogramDataPoint[N]{
+			Attributes: attribute.NewSet(attrs...),
+			StartTime:  decodeTime(p.StartTimeUnixNano),
+			Time:       decodeTime(p.TimeUnixNano),
+			Count:      p.Count,
+			Min:        decodeExtrema[N](p.Min),
+			Max:        decodeExtrema[N](p.Max),
+			Sum:        N(p.GetSum()),
+			Scale:      p.Scale,
+			ZeroCount:  p.ZeroCount,
+			PositiveBucket: metricdata.ExponentialBucket{
+				Offset: p.GetPositive().GetOffset(),
+				Counts: p.GetPositive().GetBucketCounts(),
+			},
+			NegativeBucket: metricdata.ExponentialBucket{
+				Offset: p.GetNegative().GetOffset(),
+				Counts: p.GetNegative().GetBucketCounts(),
+			},
+			ZeroThreshold: p.ZeroThreshold,
+			Exemplars:     exemplars,
+		}
+	}
+	return points, nil
+}
+
+func encodeSummaryPoints(list []metricdata.SummaryDataPoint) []*metricpb.SummaryDataPoint {
+	points := make([]*metricpb.SummaryDataPoint, len(list))
+	for i, p := range list {
+		quantiles := make([]*metricpb.SummaryDataPoint_ValueAtQuantile, len(p.QuantileValues))
+		for j, q := range p.QuantileValues {
+			quantiles[j] = &metricpb.SummaryDataPoint_ValueAtQuantile{Quantile: q.Quantile, Value: q.Value}
+		}
+		points[i] = &metricpb.SummaryDataPoint{
+			Attributes:        encodeAttrs(p.Attributes.ToSlice()),
+			StartTimeUnixNano: encodeTime(p.StartTime),
+			TimeUnixNano:      encodeTime(p.Time),
+			Count:             p.Count,
+			Sum:               p.Sum,
+			QuantileValues:    quantiles,
+		}
+	}
+	return points
+}
+
+func encodeExemplars[N int64 | float64](list []metricdata.Exemplar[N]) []*metricpb.Exemplar {
+	if len(list) == 0 {
+		return nil
+	}
+	exemplars := make([]*metricpb.Exemplar, len(list))
+	for i, e := range list {
+		ex := &metricpb.Exemplar{
+			FilteredAttributes: encodeAttrs(e.FilteredAttributes),
+			TimeUnixNano:       encodeTime(e.Time),
+			SpanId:             e.SpanID,
+			TraceId:            e.TraceID,
+		}
+		switch v := any(e.Value).(type) {
+		case int64:
+			ex.Value = &metricpb.Exemplar_AsInt{AsInt: v}
+		case float64:
+			ex.Value = &metricpb.Exemplar_AsDouble{AsDouble: v}
+		}
+		exemplars[i] = ex
+	}
+	return exemplars
+}
+
+func decodeExemplars[N int64 | float64](list []*metricpb.Exemplar) ([]metricdata.Exemplar[N], error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	exemplars := make([]metricdata.Exemplar[N], len(list))
+	for i, e := range list {
+		attrs, err := decodeAttrs(e.FilteredAttributes)
+		if err != nil {
+			return nil, err
+		}
+		var value N
+		switch v := e.Value.(type) {
+		case *metricpb.Exemplar_AsInt:
+			value = N(v.AsInt)
+		case *metricpb.Exemplar_AsDouble:
+			value = N(v.AsDouble)
+		}
+		exemplars[i] = metricdata.Exemplar[N]{
+			FilteredAttributes: attrs,
+			Time:               decodeTime(e.TimeUnixNano),
+			Value:              value,
+			SpanID:             e.SpanId,
+			TraceID:            e.TraceId,
+		}
+	}
+	return exemplars, nil
+}
+
+func encodeExtrema[N int64 | float64](e metricdata.Extrema[N]) *float64 {
+	v, ok := e.Value()
+	if !ok {
+		return nil
+	}
+	f := float64(v)
+	return &f
+}
+
+func decodeExtrema[N int64 | float64](v *float64) metricdata.Extrema[N] {
+	if v == nil {
+		return metricdata.Extrema[N]{}
+	}
+	return metricdata.NewExtrema(N(*v))
+}
+
+func encodeTemporality(t metricdata.Temporality) metricpb.AggregationTemporality {
+	switch t {
+	case metricdata.DeltaTemporality:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	case metricdata.CumulativeTemporality:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	default:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED
+	}
+}
+
+func decodeTemporality(t metricpb.AggregationTemporality) metricdata.Temporality {
+	switch t {
+	case metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+		return metricdata.DeltaTemporality
+	case metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE:
+		return metricdata.CumulativeTemporality
+	default:
+		return metricdata.Temporality(0)
+	}
+}
+
+func encodeTime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano()) // nolint:gosec
+}
+
+func decodeTime(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns)) // nolint:gosec
+}
+
+func encodeAttrs(list []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(list) == 0 {
+		return nil
+	}
+	attrs := make([]*commonpb.KeyValue, len(list))
+	for i, kv := range list {
+		attrs[i] = &commonpb.KeyValue{Key: string(kv.Key), Value: encodeValue(kv.Value)}
+	}
+	return attrs
+}
+
+func encodeValue(v attribute.Value) *commonpb.AnyValue {
+	av := new(commonpb.AnyValue)
+	switch v.Type() {
+	case attribute.BOOL:
+		av.Value = &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}
+	case attribute.INT64:
+		av.Value = &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}
+	case attribute.FLOAT64:
+		av.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}
+	case attribute.BOOLSLICE:
+		list := v.AsBoolSlice()
+		values := make([]*commonpb.AnyValue, len(list))
+		for i, b := range list {
+			values[i] = &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: b}}
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		list := v.AsInt64Slice()
+		values := make([]*commonpb.AnyValue, len(list))
+		for i, n := range list {
+			values[i] = &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: n}}
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		list := v.AsFloat64Slice()
+		values := make([]*commonpb.AnyValue, len(list))
+		for i, f := range list {
+			values[i] = &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		list := v.AsStringSlice()
+		values := make([]*commonpb.AnyValue, len(list))
+		for i, s := range list {
+			values[i] = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	default:
+		av.Value = &commonpb.AnyValue_StringValue{StringValue: v.AsString()}
+	}
+	return av
+}
+
+func decodeAttrs(list []*commonpb.KeyValue) ([]attribute.KeyValue, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	attrs := make([]attribute.KeyValue, len(list))
+	for i, kv := range list {
+		v, err := decodeValue(kv.GetValue())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid attribute '%s'", kv.Key)
+		}
+		attrs[i] = attribute.KeyValue{Key: attribute.Key(kv.Key), Value: v}
+	}
+	return attrs, nil
+}
+
+// Attribute arrays are restored based on the type of their first element;
+// empty arrays are restored as string slices.
+func decodeValue(av *commonpb.AnyValue) (attribute.Value, error) {
+	switch v := av.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return attribute.StringValue(v.StringValue), nil
+	case *commonpb.AnyValue_BoolValue:
+		return attribute.BoolValue(v.BoolValue), nil
+	case *commonpb.AnyValue_IntValue:
+		return attribute.Int64Value(v.IntValue), nil
+	case *commonpb.AnyValue_DoubleValue:
+		return attribute.Float64Value(v.DoubleValue), nil
+	case *commonpb.AnyValue_ArrayValue:
+		values := v.ArrayValue.GetValues()
+		if len(values) == 0 {
+			return attribute.StringSliceValue([]string{}), nil
+		}
+		switch values[0].GetValue().(type) {
+		case *commonpb.AnyValue_BoolValue:
+			list := make([]bool, len(values))
+			for i, e := range values {
+				list[i] = e.GetBoolValue()
+			}
+			return attribute.BoolSliceValue(list), nil
+		case *commonpb.AnyValue_IntValue:
+			list := make([]int64, len(values))
+			for i, e := range values {
+				list[i] = e.GetIntValue()
+			}
+			return attribute.Int64SliceValue(list), nil
+		case *commonpb.AnyValue_DoubleValue:
+			list := make([]float64, len(values))
+			for i, e := range values {
+				list[i] = e.GetDoubleValue()
+			}
+			return attribute.Float64SliceValue(list), nil
+		case *commonpb.AnyValue_StringValue:
+			list := make([]string, len(values))
+			for i, e := range values {
+				list[i] = e.GetStringValue()
+			}
+			return attribute.StringSliceValue(list), nil
+		}
+	}
+	return attribute.Value{}, errors.New("unsupported value type")
+}
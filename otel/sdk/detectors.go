@@ -0,0 +1,291 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/contrib/detectors/gcp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkResource "go.opentelemetry.io/otel/sdk/resource"
+	semConv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Maximum time spent by detectors querying metadata endpoints; when not running
+// on the expected platform the endpoints are usually unreachable.
+const detectorTimeout = 2 * time.Second
+
+// Default location of the namespace file mounted on Kubernetes pods.
+const k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Default AWS EC2 instance metadata service (IMDSv2) endpoint.
+const ec2MetadataEndpoint = "http://169.254.169.254"
+
+// KubernetesDetector returns a resource detector that collects pod, namespace,
+// node and cluster details when running on Kubernetes. Values are read from
+// the following environment variables, usually set using the downward API:
+// K8S_POD_NAME, K8S_POD_UID, K8S_NAMESPACE_NAME, K8S_NODE_NAME and
+// K8S_CLUSTER_NAME.
+//
+//	env:
+//	  - name: K8S_POD_NAME
+//	    valueFrom:
+//	      fieldRef:
+//	        fieldPath: metadata.name
+//
+// If not available, the pod name and namespace are obtained from the hostname
+// and the service account namespace file.
+func KubernetesDetector() sdkResource.Detector {
+	return &k8sDetector{nsFile: k8sNamespaceFile}
+}
+
+// ECSDetector returns a resource detector that collects cluster, task and
+// container details when running on AWS ECS (EC2 or Fargate). Values are
+// obtained using the task metadata endpoint (version 4).
+func ECSDetector() sdkResource.Detector {
+	return &ecsDetector{hc: &http.Client{Timeout: detectorTimeout}}
+}
+
+// EKSDetector returns a resource detector that collects Kubernetes and AWS
+// instance details when running on AWS EKS.
+func EKSDetector() sdkResource.Detector {
+	return &eksDetector{
+		k8s: &k8sDetector{nsFile: k8sNamespaceFile},
+		ec2: &ec2Detector{
+			endpoint: ec2MetadataEndpoint,
+			hc:       &http.Client{Timeout: detectorTimeout},
+		},
+	}
+}
+
+// EC2Detector returns a resource detector that collects account, region and
+// instance details when running on AWS EC2. Values are obtained using the
+// instance metadata service (IMDSv2).
+func EC2Detector() sdkResource.Detector {
+	return &ec2Detector{
+		endpoint: ec2MetadataEndpoint,
+		hc:       &http.Client{Timeout: detectorTimeout},
+	}
+}
+
+// GCPDetector returns a resource detector that collects project, location and
+// workload details when running on GCE, GKE, App Engine, Cloud Run or Cloud
+// Functions.
+func GCPDetector() sdkResource.Detector {
+	return gcp.NewDetector()
+}
+
+type k8sDetector struct {
+	nsFile string
+}
+
+func (d *k8sDetector) Detect(_ context.Context) (*sdkResource.Resource, error) {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return sdkResource.Empty(), nil
+	}
+	podName := os.Getenv("K8S_POD_NAME")
+	if podName == "" {
+		podName, _ = os.Hostname()
+	}
+	namespace := os.Getenv("K8S_NAMESPACE_NAME")
+	if namespace == "" {
+		if ns, err := os.ReadFile(d.nsFile); err == nil {
+			namespace = strings.TrimSpace(string(ns))
+		}
+	}
+	attrs := collect(
+		semConv.K8SPodNameKey.String(podName),
+		semConv.K8SPodUIDKey.String(os.Getenv("K8S_POD_UID")),
+		semConv.K8SNamespaceNameKey.String(namespace),
+		semConv.K8SNodeNameKey.String(os.Getenv("K8S_NODE_NAME")),
+		semConv.K8SClusterNameKey.String(os.Getenv("K8S_CLUSTER_NAME")),
+	)
+	return sdkResource.NewWithAttributes(semConv.SchemaURL, attrs...), nil
+}
+
+type ecsDetector struct {
+	hc *http.Client
+}
+
+// Subset of the details provided by the ECS task metadata endpoint.
+// https://docs.aws.amazon.com/AmazonECS/latest/developerguide/task-metadata-endpoint-v4.html
+type ecsTask struct {
+	Cluster          string `json:"Cluster"`
+	TaskARN          string `json:"TaskARN"`
+	Family           string `json:"Family"`
+	Revision         string `json:"Revision"`
+	AvailabilityZone string `json:"AvailabilityZone"`
+	LaunchType       string `json:"LaunchType"`
+}
+
+// Subset of the details provided by the ECS container metadata endpoint.
+type ecsContainer struct {
+	DockerID     string `json:"DockerId"`
+	Name         string `json:"Name"`
+	ContainerARN string `json:"ContainerARN"`
+	LogOptions   struct {
+		Group string `json:"awslogs-group"`
+	} `json:"LogOptions"`
+}
+
+func (d *ecsDetector) Detect(ctx context.Context) (*sdkResource.Resource, error) {
+	endpoint := os.Getenv("ECS_CONTAINER_METADATA_URI_V4")
+	if endpoint == "" {
+		return sdkResource.Empty(), nil
+	}
+	task := ecsTask{}
+	if err := getJSON(ctx, d.hc, endpoint+"/task", nil, &task); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve ECS task metadata")
+	}
+	container := ecsContainer{}
+	if err := getJSON(ctx, d.hc, endpoint, nil, &container); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve ECS container metadata")
+	}
+	attrs := collect(
+		semConv.CloudProviderAWS,
+		semConv.CloudPlatformAWSECS,
+		semConv.CloudAvailabilityZoneKey.String(task.AvailabilityZone),
+		semConv.AWSECSClusterARNKey.String(task.Cluster),
+		semConv.AWSECSTaskARNKey.String(task.TaskARN),
+		semConv.AWSECSTaskFamilyKey.String(task.Family),
+		semConv.AWSECSTaskRevisionKey.String(task.Revision),
+		semConv.AWSECSLaunchtypeKey.String(strings.ToLower(task.LaunchType)),
+		semConv.AWSECSContainerARNKey.String(container.ContainerARN),
+		semConv.ContainerIDKey.String(container.DockerID),
+		semConv.ContainerNameKey.String(container.Name),
+	)
+	if container.LogOptions.Group != "" {
+		attrs = append(attrs, semConv.AWSLogGroupNamesKey.StringSlice([]string{container.LogOptions.Group}))
+	}
+	return sdkResource.NewWithAttributes(semConv.SchemaURL, attrs...), nil
+}
+
+type ec2Detector struct {
+	endpoint string
+	hc       *http.Client
+}
+
+// Subset of the instance identity document.
+// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instance-identity-documents.html
+type ec2Identity struct {
+	AccountID        string `json:"accountId"`
+	Region           string `json:"region"`
+	AvailabilityZone string `json:"availabilityZone"`
+	InstanceID       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	ImageID          string `json:"imageId"`
+}
+
+func (d *ec2Detector) Detect(ctx context.Context) (*sdkResource.Resource, error) {
+	doc, ok := d.identity(ctx)
+	if !ok {
+		return sdkResource.Empty(), nil
+	}
+	attrs := collect(
+		semConv.CloudProviderAWS,
+		semConv.CloudPlatformAWSEC2,
+		semConv.CloudAccountIDKey.String(doc.AccountID),
+		semConv.CloudRegionKey.String(doc.Region),
+		semConv.CloudAvailabilityZoneKey.String(doc.AvailabilityZone),
+		semConv.HostIDKey.String(doc.InstanceID),
+		semConv.HostTypeKey.String(doc.InstanceType),
+		semConv.HostImageIDKey.String(doc.ImageID),
+	)
+	return sdkResource.NewWithAttributes(semConv.SchemaURL, attrs...), nil
+}
+
+// Retrieve the instance identity document; returns false if the metadata
+// service is not available.
+func (d *ec2Detector) identity(ctx context.Context) (*ec2Identity, bool) {
+	ctx, cancel := context.WithTimeout(ctx, detectorTimeout)
+	defer cancel()
+
+	// get session token
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPut, d.endpoint+"/latest/api/token", nil)
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	res, err := d.hc.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	token, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, false
+	}
+
+	// get identity document
+	doc := new(ec2Identity)
+	headers := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+	if err := getJSON(ctx, d.hc, d.endpoint+"/latest/dynamic/instance-identity/document", headers, doc); err != nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+type eksDetector struct {
+	k8s *k8sDetector
+	ec2 *ec2Detector
+}
+
+func (d *eksDetector) Detect(ctx context.Context) (*sdkResource.Resource, error) {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return sdkResource.Empty(), nil
+	}
+	k8s, err := d.k8s.Detect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := d.ec2.identity(ctx)
+	if !ok {
+		return k8s, nil
+	}
+	attrs := collect(
+		semConv.CloudProviderAWS,
+		semConv.CloudPlatformAWSEKS,
+		semConv.CloudAccountIDKey.String(doc.AccountID),
+		semConv.CloudRegionKey.String(doc.Region),
+		semConv.CloudAvailabilityZoneKey.String(doc.AvailabilityZone),
+		semConv.HostIDKey.String(doc.InstanceID),
+		semConv.HostTypeKey.String(doc.InstanceType),
+	)
+	return sdkResource.Merge(k8s, sdkResource.NewWithAttributes(semConv.SchemaURL, attrs...))
+}
+
+// Retrieve and decode a JSON document.
+func getJSON(ctx context.Context, hc *http.Client, url string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, hv := range headers {
+		req.Header.Set(k, hv)
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// Remove attributes with empty values.
+func collect(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	var list []attribute.KeyValue
+	for _, kv := range attrs {
+		if kv.Value.Type() == attribute.STRING && kv.Value.AsString() == "" {
+			continue
+		}
+		list = append(list, kv)
+	}
+	return list
+}
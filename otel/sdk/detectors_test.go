@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/otel"
+	sdkResource "go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestDetectors(t *testing.T) {
+	t.Run("Kubernetes", func(t *testing.T) {
+		assert := tdd.New(t)
+		nsFile := filepath.Join(t.TempDir(), "namespace")
+		assert.Nil(os.WriteFile(nsFile, []byte("production\n"), 0600))
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+		t.Setenv("K8S_POD_NAME", "api-7d4b9c")
+		t.Setenv("K8S_NODE_NAME", "node-1")
+
+		res, err := (&k8sDetector{nsFile: nsFile}).Detect(context.Background())
+		assert.Nil(err)
+		attrs := resourceAttributes(res)
+		assert.Equal("api-7d4b9c", attrs["k8s.pod.name"])
+		assert.Equal("production", attrs["k8s.namespace.name"])
+		assert.Equal("node-1", attrs["k8s.node.name"])
+		assert.NotContains(attrs, "k8s.cluster.name", "empty values")
+	})
+
+	t.Run("ECS", func(t *testing.T) {
+		assert := tdd.New(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			if rq.URL.Path == "/task" {
+				_ = json.NewEncoder(rw).Encode(ecsTask{
+					Cluster:    "arn:aws:ecs:us-west-2:111122223333:cluster/default",
+					TaskARN:    "arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd",
+					Family:     "api",
+					Revision:   "3",
+					LaunchType: "FARGATE",
+				})
+				return
+			}
+			_ = json.NewEncoder(rw).Encode(ecsContainer{DockerID: "cd189a933e5849daa93386466019ab50", Name: "api"})
+		}))
+		defer srv.Close()
+
+		// Not running on ECS
+		det := ECSDetector()
+		res, err := det.Detect(context.Background())
+		assert.Nil(err)
+		assert.Equal(0, res.Len())
+
+		// Running on ECS
+		t.Setenv("ECS_CONTAINER_METADATA_URI_V4", srv.URL)
+		res, err = det.Detect(context.Background())
+		assert.Nil(err)
+		attrs := resourceAttributes(res)
+		assert.Equal("aws_ecs", attrs["cloud.platform"])
+		assert.Equal("fargate", attrs["aws.ecs.launchtype"])
+		assert.Equal("api", attrs["aws.ecs.task.family"])
+		assert.Equal("cd189a933e5849daa93386466019ab50", attrs["container.id"])
+	})
+
+	t.Run("EC2", func(t *testing.T) {
+		assert := tdd.New(t)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			switch rq.URL.Path {
+			case "/latest/api/token":
+				_, _ = rw.Write([]byte("session-token"))
+			case "/latest/dynamic/instance-identity/document":
+				if rq.Header.Get("X-aws-ec2-metadata-token") != "session-token" {
+					rw.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				_ = json.NewEncoder(rw).Encode(ec2Identity{
+					AccountID:  "111122223333",
+					Region:     "us-west-2",
+					InstanceID: "i-1234567890abcdef0",
+				})
+			}
+		}))
+		defer srv.Close()
+
+		det := &ec2Detector{endpoint: srv.URL, hc: srv.Client()}
+		res, err := det.Detect(context.Background())
+		assert.Nil(err)
+		attrs := resourceAttributes(res)
+		assert.Equal("aws_ec2", attrs["cloud.platform"])
+		assert.Equal("us-west-2", attrs["cloud.region"])
+		assert.Equal("i-1234567890abcdef0", attrs["host.id"])
+	})
+
+	t.Run("Setup", func(t *testing.T) {
+		assert := tdd.New(t)
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+		t.Setenv("K8S_POD_NAME", "api-7d4b9c")
+		t.Setenv("K8S_NAMESPACE_NAME", "staging")
+		app, err := Setup(
+			WithResourceDetectors(KubernetesDetector()),
+			WithResourceAttributes(otel.Attributes{"k8s.namespace.name": "custom"}),
+		)
+		assert.Nil(err)
+		attrs := resourceAttributes(app.resource)
+		assert.Equal("api-7d4b9c", attrs["k8s.pod.name"])
+		assert.Equal("custom", attrs["k8s.namespace.name"], "user attributes take precedence")
+	})
+}
+
+func resourceAttributes(res *sdkResource.Resource) map[string]string {
+	attrs := map[string]string{}
+	for _, kv := range res.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
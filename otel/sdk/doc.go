@@ -3,5 +3,89 @@ Package sdk provides the utilities necessary to setup a monitoring implementatio
 
 Setting up a specific monitoring pipeline/stack is independent of instrumenting an
 application or library. For instrumentation utilities use the `api` package.
+
+Traces, metrics and log records are all handled by the same pipeline, sharing
+the resource attributes of the instrumented application. Log records are
+exported in batches when a log exporter is registered.
+
+	app, err := Setup(
+		WithServiceName("my-service"),
+		WithSpanExporter(traceExp),
+		WithMetricExporter(metricExp),
+		WithLogExporter(logExp),
+	)
+
+Messages produced by the application's logger can be forwarded as log records
+using the `WithLogBridge` option; for other logger instances use `LogBridge`.
+Records are correlated with the active span when the trace identifiers are
+included in the message.
+
+	app.Logger().WithFields(ContextFields(ctx)).Info("processing request")
+
+When enabled with `WithExemplars`, metric measurements recorded in the context
+of a sampled span include exemplars with the trace and span identifiers; this
+allows navigating from a metric sample to representative traces.
+
+Infrastructure attributes can be collected automatically by enabling the
+resource detectors for the platform(s) the application runs on.
+
+	app, err := Setup(
+		WithServiceName("my-service"),
+		WithResourceDetectors(KubernetesDetector(), EKSDetector()),
+	)
+
+Metric streams can be adjusted using views; for example to drop instruments,
+limit the attributes recorded or customize histogram bucket boundaries.
+
+	app, err := Setup(
+		WithMetricExporter(metricExp),
+		WithViewConfig(ViewConfig{
+			Instrument: "http.server.request.duration",
+			Attributes: []string{"http.request.method", "http.route"},
+			Buckets:    []float64{0.01, 0.05, 0.1, 0.5, 1, 5},
+		}),
+	)
+
+Telemetry data can be submitted to several exporters simultaneously; each
+exporter uses its own endpoint, credentials and TLS settings, and may be
+restricted to specific signals. For example, to submit traces to a vendor
+while sending metrics and logs to a local collector.
+
+	app, err := Setup(
+		WithExporterConfig(ExporterConfig{
+			Type:     ExporterTypeOTLP,
+			Endpoint: "otlp.vendor.com:443",
+			Headers:  map[string]string{"api-key": "..."},
+			Signals:  []string{SignalTraces},
+		}),
+		WithExporterConfig(ExporterConfig{
+			Type:     ExporterTypeOTLP,
+			Endpoint: "collector:4317",
+			Signals:  []string{SignalMetrics, SignalLogs},
+			TLS:      &ExporterTLS{CAFile: "/etc/certs/ca.pem"},
+		}),
+	)
+
+Spans and metrics can be stored on disk before being submitted to the
+exporters, so telemetry data survives collector outages and process
+restarts. Data is stored using the OTLP protobuf representation. Metrics
+are stored for all metric exporters. Spans are stored only for the OTLP
+exporters set with "WithExporterConfig"; exporters set with
+"WithSpanExporter" don't use the queue, and other OTLP trace clients can be
+wrapped using "NewPersistentTraceClient". Stored data is limited by size
+and age; discarded batches are reported using the "otel.sdk.queue.dropped"
+metric.
+
+	app, err := Setup(
+		WithExporterConfig(ExporterConfig{
+			Type:     ExporterTypeOTLP,
+			Endpoint: "collector:4317",
+		}),
+		WithPersistentQueue(PersistentQueueOptions{
+			Directory: "/var/lib/my-service/telemetry",
+			MaxSize:   50 * 1024 * 1024,
+			MaxAge:    6 * time.Hour,
+		}),
+	)
 */
 package sdk
@@ -0,0 +1,349 @@
+package sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Supported exporter types.
+const (
+	// ExporterTypeOTLP submits telemetry data to an OTLP endpoint; for
+	// example an OpenTelemetry collector or a vendor ingestion API.
+	ExporterTypeOTLP = "otlp"
+
+	// ExporterTypeStdout prints telemetry data to standard output.
+	ExporterTypeStdout = "stdout"
+)
+
+// Telemetry signals.
+const (
+	// SignalTraces represents spans produced by the application.
+	SignalTraces = "traces"
+
+	// SignalMetrics represents measurements produced by the application.
+	SignalMetrics = "metrics"
+
+	// SignalLogs represents OpenTelemetry log records.
+	SignalLogs = "logs"
+)
+
+// ExporterConfig provides a declarative definition of a telemetry exporter;
+// for example, loaded from a config file. Several exporters can be registered
+// simultaneously, each one with its own endpoint, credentials and signals.
+type ExporterConfig struct {
+	// Exporter type, either "otlp" or "stdout".
+	Type string `mapstructure:"type" yaml:"type" json:"type"`
+
+	// Signals submitted to the exporter: "traces", "metrics" and/or "logs".
+	// All signals are submitted if not provided.
+	Signals []string `mapstructure:"signals" yaml:"signals" json:"signals"`
+
+	// OTLP endpoint. Defaults to "localhost:4317" when using gRPC and
+	// "localhost:4318" when using HTTP.
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+
+	// OTLP protocol, either "grpc" (default) or "http".
+	Protocol string `mapstructure:"protocol" yaml:"protocol" json:"protocol"`
+
+	// Disable transport security for OTLP exporters.
+	Insecure bool `mapstructure:"insecure" yaml:"insecure" json:"insecure"`
+
+	// Additional headers included on OTLP requests; commonly used to
+	// provide authentication credentials.
+	Headers map[string]string `mapstructure:"headers" yaml:"headers" json:"headers"`
+
+	// TLS settings used by OTLP exporters. If not provided, the system
+	// certificate authorities are used to verify the endpoint.
+	TLS *ExporterTLS `mapstructure:"tls" yaml:"tls" json:"tls"`
+
+	// Pretty print the output of stdout exporters.
+	Pretty bool `mapstructure:"pretty" yaml:"pretty" json:"pretty"`
+}
+
+// ExporterTLS defines the transport security settings used by an exporter.
+type ExporterTLS struct {
+	// PEM-encoded certificate authorities file used to verify the endpoint.
+	// The system certificate authorities are used if not provided.
+	CAFile string `mapstructure:"ca_file" yaml:"ca_file" json:"ca_file"`
+
+	// PEM-encoded client certificate and private key files; required when
+	// the endpoint uses mutual TLS authentication.
+	CertFile string `mapstructure:"cert_file" yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `mapstructure:"key_file" yaml:"key_file" json:"key_file"`
+
+	// Server name used to verify the endpoint certificate. Defaults to the
+	// endpoint host.
+	ServerName string `mapstructure:"server_name" yaml:"server_name" json:"server_name"`
+}
+
+// Validate the exporter settings.
+func (ec ExporterConfig) validate() error {
+	switch ec.Type {
+	case ExporterTypeOTLP, ExporterTypeStdout:
+	default:
+		return errors.Errorf("unsupported exporter type: %s", ec.Type)
+	}
+	switch ec.Protocol {
+	case "", "grpc", "http":
+	default:
+		return errors.Errorf("unsupported protocol: %s", ec.Protocol)
+	}
+	for _, s := range ec.Signals {
+		switch s {
+		case SignalTraces, SignalMetrics, SignalLogs:
+		default:
+			return errors.Errorf("unsupported signal: %s", s)
+		}
+	}
+	if ec.TLS != nil && (ec.TLS.CertFile == "") != (ec.TLS.KeyFile == "") {
+		return errors.New("both certificate and key files are required")
+	}
+	return nil
+}
+
+// Determine if the signal is submitted to the exporter.
+func (ec ExporterConfig) enabled(signal string) bool {
+	if len(ec.Signals) == 0 {
+		return true
+	}
+	for _, s := range ec.Signals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// Build the exporters for all the enabled signals. Exporters for disabled
+// signals are returned as `nil`. If `queue` is provided, OTLP spans are
+// stored on disk before being submitted; metric exporters are wrapped by
+// the caller.
+func (ec ExporterConfig) build(queue *PersistentQueueOptions) (sdkTrace.SpanExporter, sdkMetric.Exporter, sdkLog.Exporter, error) { // nolint:lll
+	var (
+		se  sdkTrace.SpanExporter
+		me  sdkMetric.Exporter
+		le  sdkLog.Exporter
+		err error
+	)
+	if err = ec.validate(); err != nil {
+		return nil, nil, nil, err
+	}
+	if ec.enabled(SignalTraces) {
+		if se, err = ec.spanExporter(queue); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "trace exporter")
+		}
+	}
+	if ec.enabled(SignalMetrics) {
+		if me, err = ec.metricExporter(); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "metric exporter")
+		}
+	}
+	if ec.enabled(SignalLogs) {
+		if le, err = ec.logExporter(); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "log exporter")
+		}
+	}
+	return se, me, le, nil
+}
+
+func (ec ExporterConfig) spanExporter(queue *PersistentQueueOptions) (sdkTrace.SpanExporter, error) {
+	if ec.Type == ExporterTypeStdout {
+		var opts []stdouttrace.Option
+		if ec.Pretty {
+			opts = append(opts, stdouttrace.WithPrettyPrint())
+		}
+		return stdouttrace.New(opts...)
+	}
+	client, err := ec.traceClient()
+	if err != nil {
+		return nil, err
+	}
+	if queue != nil {
+		if client, err = NewPersistentTraceClient(client, *queue); err != nil {
+			return nil, err
+		}
+	}
+	return otlptrace.New(context.Background(), client)
+}
+
+func (ec ExporterConfig) traceClient() (otlptrace.Client, error) {
+	tc, err := ec.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if ec.Protocol == "http" {
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(ec.endpoint()),
+			otlptracehttp.WithHeaders(ec.Headers),
+			otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
+		}
+		if ec.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else if tc != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tc))
+		}
+		return otlptracehttp.NewClient(opts...), nil
+	}
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(ec.endpoint()),
+		otlptracegrpc.WithHeaders(ec.Headers),
+		otlptracegrpc.WithCompressor(gzip.Name),
+	}
+	if ec.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(grpcCredentials(tc)))
+	}
+	return otlptracegrpc.NewClient(opts...), nil
+}
+
+func (ec ExporterConfig) metricExporter() (sdkMetric.Exporter, error) {
+	if ec.Type == ExporterTypeStdout {
+		var opts []stdoutmetric.Option
+		if ec.Pretty {
+			opts = append(opts, stdoutmetric.WithPrettyPrint())
+		}
+		return stdoutmetric.New(opts...)
+	}
+	tc, err := ec.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if ec.Protocol == "http" {
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(ec.endpoint()),
+			otlpmetrichttp.WithHeaders(ec.Headers),
+			otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
+		}
+		if ec.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		} else if tc != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tc))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(ec.endpoint()),
+		otlpmetricgrpc.WithHeaders(ec.Headers),
+		otlpmetricgrpc.WithCompressor(gzip.Name),
+	}
+	if ec.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(grpcCredentials(tc)))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+func (ec ExporterConfig) logExporter() (sdkLog.Exporter, error) {
+	if ec.Type == ExporterTypeStdout {
+		return LogExporterStdout(ec.Pretty)
+	}
+	tc, err := ec.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if ec.Protocol == "http" {
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(ec.endpoint()),
+			otlploghttp.WithHeaders(ec.Headers),
+			otlploghttp.WithCompression(otlploghttp.GzipCompression),
+		}
+		if ec.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		} else if tc != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(tc))
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(ec.endpoint()),
+		otlploggrpc.WithHeaders(ec.Headers),
+		otlploggrpc.WithCompressor(gzip.Name),
+	}
+	if ec.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(grpcCredentials(tc)))
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
+
+// Endpoint used by OTLP exporters.
+func (ec ExporterConfig) endpoint() string {
+	if ec.Endpoint != "" {
+		return ec.Endpoint
+	}
+	if ec.Protocol == "http" {
+		return "localhost:4318"
+	}
+	return "localhost:4317"
+}
+
+// Load the TLS settings used by OTLP exporters. Returns `nil` when using the
+// default settings.
+func (ec ExporterConfig) tlsConfig() (*tls.Config, error) {
+	if ec.Insecure || ec.TLS == nil {
+		return nil, nil
+	}
+	conf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: ec.TLS.ServerName,
+	}
+	if ec.TLS.CAFile != "" {
+		ca, err := os.ReadFile(ec.TLS.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load CA file")
+		}
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.New("failed to append provided CA certificates")
+		}
+	}
+	if ec.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(ec.TLS.CertFile, ec.TLS.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load key pair")
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
+
+// Transport credentials used by gRPC exporters; the system certificate
+// authorities are used by default.
+func grpcCredentials(tc *tls.Config) credentials.TransportCredentials {
+	if tc == nil {
+		return credentials.NewClientTLSFromCert(nil, "")
+	}
+	return credentials.NewTLS(tc)
+}
+
+// Normalize protocol names provided to the OTLP utilities.
+func otlpProtocol(protocol string) string {
+	if strings.ToLower(protocol) == "http" {
+		return "http"
+	}
+	return "grpc"
+}
@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExporterConfig(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []ExporterConfig{
+			{Type: "zipkin"},
+			{Type: ExporterTypeOTLP, Protocol: "udp"},
+			{Type: ExporterTypeOTLP, Signals: []string{"profiles"}},
+			{Type: ExporterTypeOTLP, TLS: &ExporterTLS{CertFile: "client.crt"}},
+			{Type: ExporterTypeOTLP, TLS: &ExporterTLS{CAFile: "missing-ca.pem"}},
+		}
+		for _, ec := range invalid {
+			_, _, _, err := ec.build(nil)
+			assert.NotNil(err, ec)
+		}
+		_, err := Setup(WithExporterConfig(ExporterConfig{Type: "zipkin"}))
+		assert.NotNil(err, "setup should fail")
+	})
+
+	t.Run("Signals", func(t *testing.T) {
+		ec := ExporterConfig{
+			Type:     ExporterTypeOTLP,
+			Protocol: "http",
+			Signals:  []string{SignalTraces},
+			Headers:  map[string]string{"api-key": "secret"},
+		}
+		se, me, le, err := ec.build(nil)
+		assert.Nil(err, "build")
+		assert.NotNil(se, "trace exporter")
+		assert.Nil(me, "metrics are disabled")
+		assert.Nil(le, "logs are disabled")
+		assert.Equal("localhost:4318", ec.endpoint(), "default endpoint")
+		_ = se.Shutdown(context.Background())
+	})
+
+	t.Run("Setup", func(t *testing.T) {
+		app, err := Setup(WithExporterConfig(
+			ExporterConfig{Type: ExporterTypeOTLP, Insecure: true, Signals: []string{SignalMetrics}},
+			ExporterConfig{Type: ExporterTypeStdout, Signals: []string{SignalTraces, SignalLogs}},
+		))
+		assert.Nil(err, "setup")
+		assert.Len(app.traceExporters, 1)
+		assert.Len(app.metricExporters, 1)
+		assert.Len(app.logExporters, 1)
+		assert.NotNil(app.LoggerProvider(), "log provider")
+	})
+}
+
+func TestFanOut(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Register several sinks for each signal
+	spansA := tracetest.NewInMemoryExporter()
+	spansB := tracetest.NewInMemoryExporter()
+	reader := sdkMetric.NewManualReader()
+	app, err := Setup(
+		WithServiceName("fan-out"),
+		WithSpanExporter(spansA),
+		WithSpanExporter(spansB),
+		WithMetricReader(reader),
+	)
+	assert.Nil(err, "setup")
+
+	// Produce telemetry data
+	ctx := context.Background()
+	_, span := app.traceProvider.Tracer("test").Start(ctx, "operation")
+	span.End()
+	counter, err := app.meterProvider.Meter("test").Int64Counter("requests")
+	assert.Nil(err, "counter")
+	counter.Add(ctx, 1)
+
+	// All trace exporters receive the spans
+	assert.Nil(app.traceProvider.ForceFlush(ctx))
+	assert.Len(spansA.GetSpans(), 1)
+	assert.Len(spansB.GetSpans(), 1)
+
+	// Metrics are available on the additional reader
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(reader.Collect(ctx, &rm))
+	assert.Len(rm.ScopeMetrics, 1)
+	app.Flush(ctx)
+}
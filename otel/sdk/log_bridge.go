@@ -0,0 +1,242 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/log"
+	"go.bryk.io/pkg/metadata"
+	otelLog "go.opentelemetry.io/otel/log"
+	logGlobal "go.opentelemetry.io/otel/log/global"
+	apiTrace "go.opentelemetry.io/otel/trace"
+)
+
+// Default instrumentation scope used for log records emitted by the bridge.
+const logBridgeName = "go.bryk.io/pkg/otel/sdk"
+
+// LogBridge returns a logger instance that emits all messages as OpenTelemetry
+// log records using the provided logger provider; if `lp` is nil the global
+// provider is used. Records are correlated with a trace when the message
+// includes the trace and span identifiers as fields, as returned by
+// `ContextFields`.
+//
+// The bridge only emits records, calling `Panic` or `Fatal` won't stop the
+// application; use it alongside a regular logger with `log.Composite`.
+func LogBridge(name string, lp otelLog.LoggerProvider) log.Logger {
+	if lp == nil {
+		lp = logGlobal.GetLoggerProvider()
+	}
+	if name == "" {
+		name = logBridgeName
+	}
+	return &logBridge{
+		ll:     lp.Logger(name),
+		tags:   metadata.New(),
+		fields: metadata.New(),
+	}
+}
+
+// ContextFields returns the trace and span identifiers of the span in `ctx`,
+// if any, as log fields. Log records emitted using these fields are
+// correlated with the span.
+//
+//	ll.WithFields(ContextFields(ctx)).Info("processing request")
+func ContextFields(ctx context.Context) log.Fields {
+	sc := apiTrace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return log.Fields{}
+	}
+	return log.Fields{
+		lblTraceID: sc.TraceID().String(),
+		lblSpanID:  sc.SpanID().String(),
+	}
+}
+
+type logBridge struct {
+	mu     sync.Mutex
+	ll     otelLog.Logger
+	lvl    log.Level
+	tags   metadata.MD
+	fields metadata.MD
+}
+
+func (lb *logBridge) SetLevel(lvl log.Level) {
+	lb.mu.Lock()
+	lb.lvl = lvl
+	lb.mu.Unlock()
+}
+
+func (lb *logBridge) Sub(tags log.Fields) log.Logger {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	st := lb.tags.Copy()
+	st.Load(tags)
+	return &logBridge{
+		ll:     lb.ll,
+		lvl:    lb.lvl,
+		tags:   st,
+		fields: metadata.New(),
+	}
+}
+
+func (lb *logBridge) WithFields(fields log.Fields) log.Logger {
+	lb.mu.Lock()
+	lb.fields.Load(fields)
+	lb.mu.Unlock()
+	return lb
+}
+
+func (lb *logBridge) WithField(key string, value interface{}) log.Logger {
+	lb.mu.Lock()
+	lb.fields.Set(key, value)
+	lb.mu.Unlock()
+	return lb
+}
+
+func (lb *logBridge) Debug(args ...interface{}) {
+	lb.emit(log.Debug, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Debugf(format string, args ...interface{}) {
+	lb.emit(log.Debug, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) Info(args ...interface{}) {
+	lb.emit(log.Info, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Infof(format string, args ...interface{}) {
+	lb.emit(log.Info, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) Warning(args ...interface{}) {
+	lb.emit(log.Warning, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Warningf(format string, args ...interface{}) {
+	lb.emit(log.Warning, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) Error(args ...interface{}) {
+	lb.emit(log.Error, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Errorf(format string, args ...interface{}) {
+	lb.emit(log.Error, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) Panic(args ...interface{}) {
+	lb.emit(log.Panic, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Panicf(format string, args ...interface{}) {
+	lb.emit(log.Panic, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) Fatal(args ...interface{}) {
+	lb.emit(log.Fatal, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Fatalf(format string, args ...interface{}) {
+	lb.emit(log.Fatal, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) Print(level log.Level, args ...interface{}) {
+	lb.emit(level, fmt.Sprint(args...))
+}
+
+func (lb *logBridge) Printf(level log.Level, format string, args ...interface{}) {
+	lb.emit(level, fmt.Sprintf(format, args...))
+}
+
+func (lb *logBridge) emit(level log.Level, msg string) {
+	// collect fields; fields are only used for the next message
+	lb.mu.Lock()
+	if lb.lvl > level {
+		lb.fields.Clear()
+		lb.mu.Unlock()
+		return
+	}
+	fields := metadata.New()
+	fields.Join(lb.tags, lb.fields)
+	lb.fields.Clear()
+	lb.mu.Unlock()
+
+	// build record
+	now := time.Now()
+	rec := otelLog.Record{}
+	rec.SetTimestamp(now)
+	rec.SetObservedTimestamp(now)
+	rec.SetSeverity(severity(level))
+	rec.SetSeverityText(level.String())
+	rec.SetBody(otelLog.StringValue(msg))
+	for k, v := range fields.Values() {
+		if k == lblTraceID || k == lblSpanID {
+			continue
+		}
+		rec.AddAttributes(logKV(k, v))
+	}
+	lb.ll.Emit(logContext(fields), rec)
+}
+
+// Restore the span context from the trace and span identifiers included
+// in the log fields, if any.
+func logContext(fields metadata.MD) context.Context {
+	ctx := context.Background()
+	traceID, err := apiTrace.TraceIDFromHex(fmt.Sprint(fields.Get(lblTraceID)))
+	if err != nil {
+		return ctx
+	}
+	spanID, err := apiTrace.SpanIDFromHex(fmt.Sprint(fields.Get(lblSpanID)))
+	if err != nil {
+		return ctx
+	}
+	return apiTrace.ContextWithSpanContext(ctx, apiTrace.NewSpanContext(apiTrace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+}
+
+// Map log levels to OpenTelemetry severity values.
+func severity(level log.Level) otelLog.Severity {
+	switch level {
+	case log.Debug:
+		return otelLog.SeverityDebug
+	case log.Info:
+		return otelLog.SeverityInfo
+	case log.Warning:
+		return otelLog.SeverityWarn
+	case log.Error:
+		return otelLog.SeverityError
+	case log.Panic:
+		return otelLog.SeverityFatal
+	case log.Fatal:
+		return otelLog.SeverityFatal4
+	default:
+		return otelLog.SeverityUndefined
+	}
+}
+
+// Convert a log field to a log record attribute.
+func logKV(k string, value interface{}) otelLog.KeyValue {
+	switch v := value.(type) {
+	case bool:
+		return otelLog.Bool(k, v)
+	case int:
+		return otelLog.Int(k, v)
+	case int64:
+		return otelLog.Int64(k, v)
+	case float64:
+		return otelLog.Float64(k, v)
+	case string:
+		return otelLog.String(k, v)
+	case []byte:
+		return otelLog.Bytes(k, v)
+	default:
+		// use the same representation as span attributes
+		kv := kvAny(k, value)
+		return otelLog.String(k, kv.Value.Emit())
+	}
+}
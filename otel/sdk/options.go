@@ -6,7 +6,10 @@ import (
 	"go.bryk.io/pkg/log"
 	"go.bryk.io/pkg/otel"
 	"go.opentelemetry.io/otel/propagation"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
 	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	sdkResource "go.opentelemetry.io/otel/sdk/resource"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -43,12 +46,28 @@ func WithSpanLimits(sl sdkTrace.SpanLimits) Option {
 // propagation mechanisms by default:
 //   - W3C Trace Context (https://www.w3.org/TR/trace-context/)
 //   - W3C Baggage (https://www.w3.org/TR/baggage/)
+//
+// To replace the default propagators use `WithPropagators` instead.
 func WithPropagator(mp propagation.TextMapPropagator) Option {
 	return func(op *Instrumentation) {
 		op.propagators = append(op.propagators, mp)
 	}
 }
 
+// WithPropagators replace the propagation mechanisms used by the application;
+// by default W3C Trace Context and W3C Baggage are used. This is useful when
+// interoperating with components using different propagation formats, for
+// example Envoy or Zipkin services using B3 headers. Use `Propagators` to
+// select the mechanisms to use by name.
+//
+//	props, _ := Propagators(PropagatorTraceContext, PropagatorB3Multi)
+//	app, _ := Setup(WithPropagators(props...))
+func WithPropagators(list ...propagation.TextMapPropagator) Option {
+	return func(op *Instrumentation) {
+		op.propagators = append([]propagation.TextMapPropagator{}, list...)
+	}
+}
+
 // WithSpanProcessor registers a new span processor in the trace provider
 // processing chain.
 func WithSpanProcessor(sp sdkTrace.SpanProcessor) Option {
@@ -67,6 +86,17 @@ func WithResourceAttributes(fields otel.Attributes) Option {
 	}
 }
 
+// WithResourceDetectors enables additional detectors to automatically collect
+// infrastructure attributes; for example `KubernetesDetector`, `ECSDetector`,
+// `EKSDetector`, `EC2Detector` or `GCPDetector`. Detectors not applicable to
+// the platform the application is running on are ignored. Attributes provided
+// with `WithResourceAttributes` take precedence over the detected ones.
+func WithResourceDetectors(detectors ...sdkResource.Detector) Option {
+	return func(op *Instrumentation) {
+		op.detectors = append(op.detectors, detectors...)
+	}
+}
+
 // WithBaseLogger set the output handler. If not provided, all output is
 // discarded by default. The application will create an extended logger
 // using all the attributes discovered/provided during the setup process.
@@ -76,11 +106,23 @@ func WithBaseLogger(ll log.Logger) Option {
 	}
 }
 
+// WithLogBridge forwards all messages produced by the application's logger
+// as OpenTelemetry log records, in addition to the base logger output. Log
+// records are correlated with traces when the messages include the span
+// identifiers, for example using `ContextFields`. Requires a log exporter
+// or processor to be configured.
+func WithLogBridge() Option {
+	return func(op *Instrumentation) {
+		op.logBridge = true
+	}
+}
+
 // WithSpanExporter enables a trace (i.e. span) exporter as data sink for the
 // application. If no exporter is set, all traces are discarded by default.
+// Can be used multiple times to submit spans to several exporters.
 func WithSpanExporter(exp sdkTrace.SpanExporter) Option {
 	return func(op *Instrumentation) {
-		op.traceExporter = exp
+		op.traceExporters = append(op.traceExporters, exp)
 	}
 }
 
@@ -91,14 +133,85 @@ func WithSpanExporter(exp sdkTrace.SpanExporter) Option {
 func WithSampler(ss sdkTrace.Sampler) Option {
 	return func(op *Instrumentation) {
 		op.sampler = ss
+		op.samplerConf = nil
+	}
+}
+
+// WithSamplerConfig adjust the sampling strategy used by the application
+// using a declarative configuration; for example, loaded from a config file.
+// Setup will fail if the configuration provided is invalid.
+//
+//	WithSamplerConfig(SamplerConfig{Strategy: SamplerRatio, Ratio: 0.5, ParentBased: true})
+func WithSamplerConfig(conf SamplerConfig) Option {
+	return func(op *Instrumentation) {
+		op.samplerConf = &conf
 	}
 }
 
 // WithMetricExporter configures the application's meter provider to export
-// the caputered metrics data using a "push" mechanism.
+// the caputered metrics data using a "push" mechanism. Can be used multiple
+// times to submit metrics to several exporters.
 func WithMetricExporter(exp sdkMetric.Exporter) Option {
 	return func(op *Instrumentation) {
-		op.metricExporter = exp
+		op.metricExporters = append(op.metricExporters, exp)
+	}
+}
+
+// WithMetricReader registers an additional reader with the application's
+// meter provider; for example, to expose the captured metrics data using a
+// "pull" mechanism like Prometheus. Producers for precomputed runtime metrics
+// are only attached to the readers created for metric exporters.
+func WithMetricReader(rd sdkMetric.Reader) Option {
+	return func(op *Instrumentation) {
+		op.metricReaders = append(op.metricReaders, rd)
+	}
+}
+
+// WithLogExporter enables a log records exporter as data sink for the
+// application. Log records are submitted to the exporter in batches. If no
+// exporter (or processor) is set, OpenTelemetry logs are discarded by
+// default. Can be used multiple times to submit log records to several
+// exporters.
+func WithLogExporter(exp sdkLog.Exporter) Option {
+	return func(op *Instrumentation) {
+		op.logExporters = append(op.logExporters, exp)
+	}
+}
+
+// WithExporterConfig registers telemetry exporters using a declarative
+// configuration; for example, loaded from a config file. Each exporter can
+// use its own endpoint, credentials and TLS settings, and submit only some
+// signals. Setup will fail if any of the configurations provided is invalid.
+//
+//	WithExporterConfig(
+//		ExporterConfig{Type: ExporterTypeOTLP, Endpoint: "collector:4317"},
+//		ExporterConfig{Type: ExporterTypeStdout, Signals: []string{SignalTraces}},
+//	)
+func WithExporterConfig(conf ...ExporterConfig) Option {
+	return func(op *Instrumentation) {
+		op.exporterConfs = append(op.exporterConfs, conf...)
+	}
+}
+
+// WithPersistentQueue stores the telemetry data produced on disk before
+// submitting it to the exporters; this allows the data to survive collector
+// outages and process restarts. Metrics are stored for all metric exporters,
+// and spans for the OTLP exporters set with `WithExporterConfig`. Exporters
+// set with `WithSpanExporter` don't use the queue; OTLP trace clients can be
+// wrapped using `NewPersistentTraceClient` instead. Each exporter uses its
+// own queue, stored on a sub-directory of `conf.Directory`. Setup will fail
+// if the configuration provided is invalid.
+func WithPersistentQueue(conf PersistentQueueOptions) Option {
+	return func(op *Instrumentation) {
+		op.queueConf = &conf
+	}
+}
+
+// WithLogProcessor registers a new log record processor in the log provider
+// processing chain.
+func WithLogProcessor(lp sdkLog.Processor) Option {
+	return func(op *Instrumentation) {
+		op.logProcessors = append(op.logProcessors, lp)
 	}
 }
 
@@ -115,10 +228,12 @@ func WithHostMetrics() Option {
 }
 
 // WithRuntimeMetrics enables the application to capture the conventional runtime
-// metrics specified by OpenTelemetry. The provided interval value sets the
-// minimum interval between calls to runtime.ReadMemStats(), which is a relatively
-// expensive call to make frequently. The default interval value is 10 seconds,
-// passing a value <= 0 uses the default.
+// metrics specified by OpenTelemetry; including heap usage, goroutine counts,
+// scheduler latency ("go.schedule.duration") and garbage collector activity
+// ("go.gc.pause.duration" and "go.gc.count"). The provided interval value sets
+// the minimum interval between calls to runtime.ReadMemStats(), which is a
+// relatively expensive call to make frequently. The default interval value is
+// 10 seconds, passing a value <= 0 uses the default.
 func WithRuntimeMetrics(interval time.Duration) Option {
 	return func(op *Instrumentation) {
 		if interval.Seconds() <= 0 {
@@ -129,12 +244,45 @@ func WithRuntimeMetrics(interval time.Duration) Option {
 	}
 }
 
-// WithExemplars enable experimental support for exemplars by settings
-// the adequate ENV variables
+// WithExemplars enable support for exemplars on the captured metrics. Measurements
+// recorded in the context of a sampled span are kept as exemplars including the
+// trace and span identifiers; allowing to navigate from a metric sample (e.g., a
+// latency spike) to representative traces.
 //
-// https://github.com/open-telemetry/opentelemetry-go/blob/main/sdk/metric/internal/x/README.md
+// https://opentelemetry.io/docs/specs/otel/metrics/data-model/#exemplars
 func WithExemplars() Option {
+	return WithExemplarFilter(exemplar.TraceBasedFilter)
+}
+
+// WithExemplarFilter adjust the filter used to determine which measurements
+// are offered as exemplars. For example, `exemplar.AlwaysOnFilter` will
+// consider all measurements, even those recorded outside a sampled span.
+func WithExemplarFilter(filter exemplar.Filter) Option {
+	return func(op *Instrumentation) {
+		op.exemplarFilter = filter
+	}
+}
+
+// WithMetricViews registers views to adjust the metric streams produced by
+// instruments. Views can be used to rename or drop instruments, filter the
+// attributes recorded, or customize the aggregation used. If multiple views
+// match an instrument, a metric stream is produced for each one of them.
+func WithMetricViews(views ...sdkMetric.View) Option {
+	return func(op *Instrumentation) {
+		op.views = append(op.views, views...)
+	}
+}
+
+// WithViewConfig registers metric views using a declarative configuration;
+// for example, loaded from a config file. Setup will fail if any of the
+// configurations provided is invalid.
+//
+//	WithViewConfig(ViewConfig{
+//		Instrument: "http.server.request.duration",
+//		Buckets:    []float64{0.01, 0.05, 0.1, 0.5, 1, 5},
+//	})
+func WithViewConfig(conf ...ViewConfig) Option {
 	return func(op *Instrumentation) {
-		op.exemplars = true
+		op.viewConfs = append(op.viewConfs, conf...)
 	}
 }
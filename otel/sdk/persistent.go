@@ -0,0 +1,321 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Instrumentation scope used to report the state of persistent queues.
+const queueScopeName = "go.bryk.io/pkg/otel/sdk/queue"
+
+// Maximum time allowed for a single export attempt.
+const queueExportTimeout = 30 * time.Second
+
+// PersistentQueueOptions defines the configuration settings for disk-backed
+// export queues. Telemetry data is stored locally before being submitted to
+// the exporter, so it survives collector outages and process restarts.
+type PersistentQueueOptions struct {
+	// Directory used to store the telemetry data pending to be exported.
+	// Required.
+	Directory string `mapstructure:"directory" yaml:"directory" json:"directory"`
+
+	// Maximum size, in bytes, of the data stored. The oldest batches are
+	// discarded when the limit is exceeded. Defaults to 100MB.
+	MaxSize int64 `mapstructure:"max_size" yaml:"max_size" json:"max_size"`
+
+	// Maximum age of stored batches; older batches are discarded without
+	// being exported. Defaults to 24 hours.
+	MaxAge time.Duration `mapstructure:"max_age" yaml:"max_age" json:"max_age"`
+
+	// Time to wait before retrying a failed export. Defaults to 5 seconds.
+	RetryInterval time.Duration `mapstructure:"retry_interval" yaml:"retry_interval" json:"retry_interval"`
+}
+
+func (opts *PersistentQueueOptions) validate() error {
+	if opts.Directory == "" {
+		return errors.New("a directory is required")
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 100 * 1024 * 1024
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 24 * time.Hour
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 5 * time.Second
+	}
+	return nil
+}
+
+// NewPersistentTraceClient returns an OTLP trace client that stores spans on
+// disk and submits them using `client` in the background. Spans are stored
+// using the OTLP protobuf representation produced by the trace exporter, use
+// `otlptrace.New` to create an exporter with the returned client. Failed
+// exports are retried until successful or until the stored data exceeds the
+// age limit. Data pending from a previous execution using the same directory
+// is exported automatically.
+func NewPersistentTraceClient(client otlptrace.Client, opts PersistentQueueOptions) (otlptrace.Client, error) {
+	pq, err := newPersistentQueue(SignalTraces, opts, func(ctx context.Context, data []byte) error {
+		req := new(coltracepb.ExportTraceServiceRequest)
+		if err := proto.Unmarshal(data, req); err != nil {
+			return errors.MarkPermanent(err)
+		}
+		return client.UploadTraces(ctx, req.ResourceSpans)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &persistentTraceClient{client: client, pq: pq}, nil
+}
+
+type persistentTraceClient struct {
+	client otlptrace.Client
+	pq     *persistentQueue
+}
+
+func (pc *persistentTraceClient) Start(ctx context.Context) error {
+	if err := pc.client.Start(ctx); err != nil {
+		return err
+	}
+	pc.pq.start()
+	return nil
+}
+
+func (pc *persistentTraceClient) Stop(ctx context.Context) error {
+	return pc.pq.shutdown(ctx, pc.client.Stop)
+}
+
+func (pc *persistentTraceClient) UploadTraces(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	data, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans})
+	if err != nil {
+		pc.pq.drop(ctx, 1, "invalid")
+		return err
+	}
+	return pc.pq.enqueue(ctx, data)
+}
+
+// NewPersistentMetricExporter returns an exporter that stores metrics on disk
+// and submits them to `exp` in the background. Metrics are stored using the
+// OTLP protobuf representation and restored before being submitted, so any
+// exporter can be used. Failed exports are retried until successful or until
+// the stored data exceeds the age limit. Data pending from a previous
+// execution using the same directory is exported automatically.
+func NewPersistentMetricExporter(exp sdkMetric.Exporter, opts PersistentQueueOptions) (sdkMetric.Exporter, error) {
+	pq, err := newPersistentQueue(SignalMetrics, opts, func(ctx context.Context, data []byte) error {
+		list, err := decodeMetrics(data)
+		if err != nil {
+			return errors.MarkPermanent(err)
+		}
+		for _, rm := range list {
+			if err = exp.Export(ctx, rm); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	pq.start()
+	return &persistentMetricExporter{exp: exp, pq: pq}, nil
+}
+
+type persistentMetricExporter struct {
+	exp sdkMetric.Exporter
+	pq  *persistentQueue
+}
+
+func (pe *persistentMetricExporter) Temporality(kind sdkMetric.InstrumentKind) metricdata.Temporality {
+	return pe.exp.Temporality(kind)
+}
+
+func (pe *persistentMetricExporter) Aggregation(kind sdkMetric.InstrumentKind) sdkMetric.Aggregation {
+	return pe.exp.Aggregation(kind)
+}
+
+func (pe *persistentMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	if len(rm.ScopeMetrics) == 0 {
+		return nil
+	}
+	data, err := encodeMetrics(rm)
+	if err != nil {
+		pe.pq.drop(ctx, 1, "invalid")
+		return err
+	}
+	return pe.pq.enqueue(ctx, data)
+}
+
+func (pe *persistentMetricExporter) ForceFlush(ctx context.Context) error {
+	if err := pe.pq.flush(ctx); err != nil {
+		return err
+	}
+	return pe.exp.ForceFlush(ctx)
+}
+
+func (pe *persistentMetricExporter) Shutdown(ctx context.Context) error {
+	return pe.pq.shutdown(ctx, pe.exp.Shutdown)
+}
+
+// Background worker submitting the batches stored in a disk queue.
+type persistentQueue struct {
+	queue   *diskQueue
+	opts    PersistentQueueOptions
+	signal  string
+	send    func(ctx context.Context, data []byte) error
+	notify  chan struct{}
+	ctx     context.Context
+	halt    context.CancelFunc
+	done    chan struct{}
+	dropped metric.Int64Counter
+	mu      sync.Mutex // serialize export attempts
+	started sync.Once
+	once    sync.Once
+}
+
+func newPersistentQueue(signal string, opts PersistentQueueOptions, send func(context.Context, []byte) error) (*persistentQueue, error) { // nolint:lll
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	queue, err := newDiskQueue(opts.Directory, opts.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Discarded batches are reported using the global meter provider.
+	dropped, _ := apiOtel.Meter(queueScopeName).Int64Counter("otel.sdk.queue.dropped",
+		metric.WithDescription("Telemetry batches discarded by persistent export queues."),
+		metric.WithUnit("{batch}"))
+	pq := &persistentQueue{
+		queue:   queue,
+		opts:    opts,
+		signal:  signal,
+		send:    send,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		dropped: dropped,
+	}
+	pq.ctx, pq.halt = context.WithCancel(context.Background())
+	return pq, nil
+}
+
+// Start the background worker.
+func (pq *persistentQueue) start() {
+	pq.started.Do(func() {
+		go pq.loop()
+	})
+}
+
+// Store a new batch and notify the background worker.
+func (pq *persistentQueue) enqueue(ctx context.Context, data []byte) error {
+	dropped, err := pq.queue.push(data)
+	pq.drop(ctx, dropped, "size")
+	if err != nil {
+		return err
+	}
+	select {
+	case pq.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Submit all stored batches, oldest first. Returns on the first failed
+// export attempt; the batch is kept for a later retry.
+func (pq *persistentQueue) flush(ctx context.Context) error {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entry, err := pq.queue.peek()
+		if err != nil || entry == nil {
+			return err
+		}
+		if time.Since(entry.created) > pq.opts.MaxAge {
+			_ = pq.queue.remove(entry.name)
+			pq.drop(ctx, 1, "age")
+			continue
+		}
+		sctx, cancel := context.WithTimeout(ctx, queueExportTimeout)
+		err = pq.send(sctx, entry.data)
+		cancel()
+		if err != nil && !errors.IsPermanent(err) {
+			return err
+		}
+		if err != nil {
+			pq.drop(ctx, 1, "invalid")
+		}
+		_ = pq.queue.remove(entry.name)
+	}
+}
+
+// Stop the background worker, attempt to submit all pending batches and
+// shutdown the exporter. Batches not submitted remain stored and will be
+// exported on the next execution. The exporter is always shutdown, even if
+// `ctx` expires before the pending batches are submitted.
+func (pq *persistentQueue) shutdown(ctx context.Context, next func(context.Context) error) error {
+	var err error
+	pq.once.Do(func() {
+		pq.halt()
+		pq.started.Do(func() {
+			close(pq.done) // worker never started
+		})
+		select {
+		case <-pq.done:
+			if err = pq.flush(ctx); err != nil {
+				err = errors.Wrapf(err, "failed to export pending %s", pq.signal)
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if sErr := next(ctx); sErr != nil && err == nil {
+			err = sErr
+		}
+	})
+	return err
+}
+
+func (pq *persistentQueue) loop() {
+	defer close(pq.done)
+	for {
+		wait := pq.notify
+		var retry <-chan time.Time
+		if err := pq.flush(pq.ctx); err != nil {
+			// wait before retrying; new batches don't trigger an attempt
+			retry = time.After(pq.opts.RetryInterval)
+			wait = nil
+		}
+		select {
+		case <-pq.ctx.Done():
+			return
+		case <-wait:
+		case <-retry:
+		}
+	}
+}
+
+// Report discarded batches.
+func (pq *persistentQueue) drop(ctx context.Context, count int, reason string) {
+	if count == 0 || pq.dropped == nil {
+		return
+	}
+	pq.dropped.Add(ctx, int64(count), metric.WithAttributes(
+		attribute.String("signal", pq.signal),
+		attribute.String("reason", reason)))
+}
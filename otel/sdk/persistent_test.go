@@ -0,0 +1,328 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	apiTrace "go.opentelemetry.io/otel/trace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Trace client failing until enabled.
+type flakyClient struct {
+	mu    sync.Mutex
+	ready bool
+	spans []*tracepb.ResourceSpans
+}
+
+func (fc *flakyClient) Start(_ context.Context) error {
+	return nil
+}
+
+func (fc *flakyClient) Stop(_ context.Context) error {
+	return nil
+}
+
+func (fc *flakyClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if !fc.ready {
+		return errors.New("collector unavailable")
+	}
+	fc.spans = append(fc.spans, spans...)
+	return nil
+}
+
+func (fc *flakyClient) enable() {
+	fc.mu.Lock()
+	fc.ready = true
+	fc.mu.Unlock()
+}
+
+func (fc *flakyClient) count() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return len(fc.spans)
+}
+
+// Metric exporter failing until enabled.
+type flakyMetricExporter struct {
+	mu      sync.Mutex
+	ready   bool
+	metrics []*metricdata.ResourceMetrics
+}
+
+func (fe *flakyMetricExporter) Temporality(kind sdkMetric.InstrumentKind) metricdata.Temporality {
+	return sdkMetric.DefaultTemporalitySelector(kind)
+}
+
+func (fe *flakyMetricExporter) Aggregation(kind sdkMetric.InstrumentKind) sdkMetric.Aggregation {
+	return sdkMetric.DefaultAggregationSelector(kind)
+}
+
+func (fe *flakyMetricExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if !fe.ready {
+		return errors.New("collector unavailable")
+	}
+	fe.metrics = append(fe.metrics, rm)
+	return nil
+}
+
+func (fe *flakyMetricExporter) ForceFlush(_ context.Context) error {
+	return nil
+}
+
+func (fe *flakyMetricExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func (fe *flakyMetricExporter) enable() {
+	fe.mu.Lock()
+	fe.ready = true
+	fe.mu.Unlock()
+}
+
+func (fe *flakyMetricExporter) count() int {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return len(fe.metrics)
+}
+
+// Collect a sample set of metrics using all supported aggregations.
+func sampleMetrics(ctx context.Context) (metricdata.ResourceMetrics, error) {
+	reader := sdkMetric.NewManualReader()
+	provider := sdkMetric.NewMeterProvider(
+		sdkMetric.WithReader(reader),
+		sdkMetric.WithExemplarFilter(exemplar.AlwaysOnFilter),
+		sdkMetric.WithView(sdkMetric.NewView(
+			sdkMetric.Instrument{Name: "size"},
+			sdkMetric.Stream{Aggregation: sdkMetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}},
+		)))
+	meter := provider.Meter("codec", metric.WithInstrumentationVersion("0.1.0"))
+	counter, _ := meter.Int64Counter("requests")
+	counter.Add(ctx, 3, metric.WithAttributes(
+		attribute.String("method", "GET"),
+		attribute.Int64Slice("codes", []int64{200, 404})))
+	latency, _ := meter.Float64Histogram("latency")
+	latency.Record(ctx, 0.25)
+	items, _ := meter.Int64Histogram("items")
+	items.Record(ctx, 9007199254740)
+	size, _ := meter.Int64Histogram("size")
+	size.Record(ctx, 512)
+	gauge, _ := meter.Float64Gauge("temperature")
+	gauge.Record(ctx, 21.5, metric.WithAttributes(attribute.Bool("indoor", true)))
+	updown, _ := meter.Int64UpDownCounter("active")
+	updown.Add(ctx, -2)
+	rm := metricdata.ResourceMetrics{}
+	err := reader.Collect(ctx, &rm)
+	return rm, err
+}
+
+func TestCodec(t *testing.T) {
+	assert := tdd.New(t)
+	original, err := sampleMetrics(context.Background())
+	assert.Nil(err, "collect")
+	data, err := encodeMetrics(&original)
+	assert.Nil(err, "encode")
+	restored, err := decodeMetrics(data)
+	assert.Nil(err, "decode")
+	assert.Len(restored, 1)
+	metricdatatest.AssertEqual(t, original, *restored[0])
+	again, _ := encodeMetrics(restored[0])
+	assert.Equal(data, again, "lossless encoding")
+
+	// Invalid data
+	_, err = decodeMetrics([]byte("invalid"))
+	assert.NotNil(err, "invalid payload")
+}
+
+func TestDiskQueue(t *testing.T) {
+	assert := tdd.New(t)
+	dir := t.TempDir()
+	q, err := newDiskQueue(dir, 11)
+	assert.Nil(err, "new queue")
+
+	// size limit
+	_, err = q.push([]byte("larger than the limit"))
+	assert.NotNil(err, "batch too large")
+	for _, b := range []string{"first", "second", "third"} {
+		_, err = q.push([]byte(b))
+		assert.Nil(err, "push")
+	}
+	assert.Equal(2, q.len(), "oldest batch should be discarded")
+
+	// FIFO order
+	entry, err := q.peek()
+	assert.Nil(err, "peek")
+	assert.Equal("second", string(entry.data))
+	assert.WithinDuration(time.Now(), entry.created, time.Second)
+	assert.Nil(q.remove(entry.name))
+	entry, _ = q.peek()
+	assert.Equal("third", string(entry.data))
+
+	// pending batches are loaded on startup
+	q, err = newDiskQueue(dir, 11)
+	assert.Nil(err, "new queue")
+	assert.Equal(1, q.len(), "pending batches")
+	dropped, err := q.push([]byte("the fourth"))
+	assert.Nil(err, "push")
+	assert.Equal(1, dropped, "oldest batch should be discarded")
+	entry, _ = q.peek()
+	assert.Equal("the fourth", string(entry.data))
+}
+
+func TestPersistentQueueShutdown(t *testing.T) {
+	assert := tdd.New(t)
+	opts := PersistentQueueOptions{Directory: t.TempDir()}
+	release := make(chan struct{})
+	sending := make(chan struct{}, 1)
+	pq, err := newPersistentQueue(SignalTraces, opts, func(_ context.Context, _ []byte) error {
+		sending <- struct{}{}
+		<-release // ignore cancellation
+		return nil
+	})
+	assert.Nil(err, "new queue")
+	pq.start()
+	assert.Nil(pq.enqueue(context.Background(), []byte("batch")))
+	<-sending
+
+	// The exporter is shutdown even if the context expires
+	stopped := 0
+	next := func(_ context.Context) error {
+		stopped++
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(pq.shutdown(ctx, next), context.DeadlineExceeded)
+	assert.Equal(1, stopped, "exporter not stopped")
+	assert.Nil(pq.shutdown(context.Background(), next))
+	assert.Equal(1, stopped, "exporter stopped more than once")
+	close(release)
+}
+
+func TestPersistentExporter(t *testing.T) {
+	assert := tdd.New(t)
+	dir := t.TempDir()
+	opts := PersistentQueueOptions{Directory: dir, RetryInterval: 10 * time.Millisecond}
+	spans := tracetest.SpanStubs{{
+		Name:     "operation",
+		SpanKind: apiTrace.SpanKindServer,
+		Attributes: []attribute.KeyValue{
+			attribute.Int64("id", 9007199254740993),
+			attribute.StringSlice("tags", []string{"a", "b"}),
+		},
+		Status: sdkTrace.Status{Code: codes.Error, Description: "failed"},
+	}}.Snapshots()
+	newExporter := func(client otlptrace.Client, opts PersistentQueueOptions) (*otlptrace.Exporter, error) {
+		pc, err := NewPersistentTraceClient(client, opts)
+		if err != nil {
+			return nil, err
+		}
+		return otlptrace.New(context.Background(), pc)
+	}
+
+	// Spans are stored while the collector is unavailable
+	sink := new(flakyClient)
+	exp, err := newExporter(sink, opts)
+	assert.Nil(err, "new exporter")
+	assert.Nil(exp.ExportSpans(context.Background(), spans), "export")
+	assert.Nil(exp.ExportSpans(context.Background(), spans), "export")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	assert.NotNil(exp.Shutdown(ctx), "pending data")
+	cancel()
+	assert.Equal(0, sink.count())
+
+	// Pending data is submitted after a restart
+	sink.enable()
+	exp, err = newExporter(sink, opts)
+	assert.Nil(err, "new exporter")
+	assert.Eventually(func() bool { return sink.count() == 2 }, time.Second, 10*time.Millisecond)
+	assert.Nil(exp.Shutdown(context.Background()))
+
+	// Stored spans use the OTLP representation
+	span := sink.spans[0].ScopeSpans[0].Spans[0]
+	assert.Equal("operation", span.Name)
+	assert.Equal(int64(9007199254740993), span.Attributes[0].Value.GetIntValue())
+	assert.Equal("failed", span.Status.Message)
+
+	// Expired data is discarded
+	_, err = NewPersistentTraceClient(sink, PersistentQueueOptions{})
+	assert.NotNil(err, "directory is required")
+	opts.MaxAge = time.Nanosecond
+	sink = new(flakyClient)
+	exp, err = newExporter(sink, opts)
+	assert.Nil(err, "new exporter")
+	assert.Nil(exp.ExportSpans(context.Background(), spans), "export")
+	sink.enable()
+	assert.Nil(exp.Shutdown(context.Background()))
+	assert.Equal(0, sink.count())
+
+	// Setup
+	app, err := Setup(
+		WithExporterConfig(ExporterConfig{
+			Type:     ExporterTypeOTLP,
+			Protocol: "http",
+			Signals:  []string{SignalTraces, SignalMetrics},
+		}),
+		WithPersistentQueue(PersistentQueueOptions{Directory: dir}))
+	assert.Nil(err, "setup")
+	assert.DirExists(filepath.Join(dir, SignalTraces, "0"))
+	assert.DirExists(filepath.Join(dir, SignalMetrics, "0"))
+	_, ok := app.metricExporters[0].(*persistentMetricExporter)
+	assert.True(ok, "exporter should be wrapped")
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	app.Flush(ctx)
+	cancel()
+}
+
+func TestPersistentMetricExporter(t *testing.T) {
+	assert := tdd.New(t)
+	ctx := context.Background()
+	opts := PersistentQueueOptions{Directory: t.TempDir(), RetryInterval: 10 * time.Millisecond}
+	rm, err := sampleMetrics(ctx)
+	assert.Nil(err, "collect")
+
+	// Metrics are stored while the collector is unavailable
+	sink := new(flakyMetricExporter)
+	exp, err := NewPersistentMetricExporter(sink, opts)
+	assert.Nil(err, "new exporter")
+	assert.Nil(exp.Export(ctx, &rm), "export")
+	assert.Nil(exp.Export(ctx, &metricdata.ResourceMetrics{}), "empty collection")
+	sctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	assert.NotNil(exp.Shutdown(sctx), "pending data")
+	cancel()
+	assert.Equal(0, sink.count())
+
+	// Pending data is submitted after a restart
+	sink.enable()
+	exp, err = NewPersistentMetricExporter(sink, opts)
+	assert.Nil(err, "new exporter")
+	assert.Eventually(func() bool { return sink.count() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Nil(exp.ForceFlush(ctx))
+	assert.Nil(exp.Shutdown(ctx))
+	metricdatatest.AssertEqual(t, rm, *sink.metrics[0])
+	assert.Equal(sdkMetric.DefaultTemporalitySelector(sdkMetric.InstrumentKindCounter),
+		exp.Temporality(sdkMetric.InstrumentKindCounter))
+
+	// Invalid configuration
+	_, err = NewPersistentMetricExporter(sink, PersistentQueueOptions{})
+	assert.NotNil(err, "directory is required")
+}
@@ -0,0 +1,193 @@
+package sdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// File extensions used by the disk queue.
+const (
+	batchExt = ".batch"
+	tmpExt   = ".tmp"
+)
+
+// Simple FIFO queue storing each batch of telemetry data as a file in a
+// local directory. File names include the creation time, so the content
+// of the directory is naturally sorted from oldest to newest. The directory
+// is only scanned when the queue is created, stored batches and their size
+// are tracked in memory afterwards.
+type diskQueue struct {
+	dir     string
+	maxSize int64
+	seq     uint64
+	items   []queueItem // sorted from oldest to newest
+	size    int64       // total size of stored batches
+	mu      sync.Mutex
+}
+
+// Batch stored in the queue directory.
+type queueItem struct {
+	name string
+	size int64
+}
+
+// Entry stored in the queue.
+type queueEntry struct {
+	name    string
+	data    []byte
+	created time.Time
+}
+
+func newDiskQueue(dir string, maxSize int64) (*diskQueue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrap(err, "failed to create queue directory")
+	}
+
+	// remove incomplete writes from a previous execution
+	tmp, _ := filepath.Glob(filepath.Join(dir, "*"+tmpExt))
+	for _, f := range tmp {
+		_ = os.Remove(f)
+	}
+
+	// load batches pending from a previous execution
+	q := &diskQueue{dir: dir, maxSize: maxSize}
+	if err := q.load(); err != nil {
+		return nil, errors.Wrap(err, "failed to read queue directory")
+	}
+	return q, nil
+}
+
+// Store a new batch. If the queue size limit is exceeded, the oldest
+// batches are discarded; the number of batches discarded is returned.
+func (q *diskQueue) push(data []byte) (int, error) {
+	if q.maxSize > 0 && int64(len(data)) > q.maxSize {
+		return 1, errors.New("batch exceeds the queue size limit")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// write to a temporary file and rename it once completed, this
+	// prevents reading partial batches
+	q.seq++
+	name := fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), q.seq%1e6)
+	tmp := filepath.Join(q.dir, name+tmpExt)
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	if err := os.Rename(tmp, filepath.Join(q.dir, name+batchExt)); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	q.items = append(q.items, queueItem{name: name + batchExt, size: int64(len(data))})
+	q.size += int64(len(data))
+	return q.enforceSize(), nil
+}
+
+// Return the oldest batch available, or `nil` if the queue is empty.
+func (q *diskQueue) peek() (*queueEntry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) > 0 {
+		item := q.items[0]
+		data, err := os.ReadFile(filepath.Join(q.dir, item.name))
+		if os.IsNotExist(err) {
+			q.drop(0) // removed externally
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &queueEntry{
+			name:    item.name,
+			data:    data,
+			created: createdAt(item.name),
+		}, nil
+	}
+	return nil, nil
+}
+
+// Remove a batch from the queue.
+func (q *diskQueue) remove(name string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := os.Remove(filepath.Join(q.dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i, item := range q.items {
+		if item.name == name {
+			q.drop(i)
+			break
+		}
+	}
+	return nil
+}
+
+// Number of batches in the queue.
+func (q *diskQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Discard the oldest batches until the size limit is met. Must be called
+// while holding the lock.
+func (q *diskQueue) enforceSize() int {
+	if q.maxSize <= 0 {
+		return 0
+	}
+	dropped := 0
+	for q.size > q.maxSize && len(q.items) > 0 {
+		err := os.Remove(filepath.Join(q.dir, q.items[0].name))
+		if err != nil && !os.IsNotExist(err) {
+			break
+		}
+		q.drop(0)
+		dropped++
+	}
+	return dropped
+}
+
+// Stop tracking the batch at index `i`. Must be called while holding the lock.
+func (q *diskQueue) drop(i int) {
+	q.size -= q.items[i].size
+	q.items = append(q.items[:i], q.items[i+1:]...)
+}
+
+// Load the batches available in the queue directory, sorted from oldest to
+// newest.
+func (q *diskQueue) load() error {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	for _, el := range entries {
+		if el.IsDir() || !strings.HasSuffix(el.Name(), batchExt) {
+			continue
+		}
+		info, err := el.Info()
+		if err != nil {
+			continue
+		}
+		q.items = append(q.items, queueItem{name: el.Name(), size: info.Size()})
+		q.size += info.Size()
+	}
+	return nil
+}
+
+// Creation time of a batch, based on its file name.
+func createdAt(name string) time.Time {
+	ts, err := strconv.ParseInt(strings.SplitN(name, "-", 2)[0], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, ts)
+}
@@ -0,0 +1,129 @@
+package sdk
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Instrumentation scope used for the garbage collector metrics.
+const gcScopeName = "go.bryk.io/pkg/otel/sdk/runtime"
+
+// Runtime metrics used to report garbage collector activity. Stop-the-world
+// pauses are reported using "/sched/pauses/total/gc:seconds" on Go >= 1.22,
+// with "/gc/pauses:seconds" used as fallback.
+const (
+	rmGCPauses       = "/sched/pauses/total/gc:seconds"
+	rmGCPausesLegacy = "/gc/pauses:seconds"
+	rmGCCycles       = "/gc/cycles/total:gc-cycles"
+)
+
+// Producer reporting garbage collector pauses and cycles as precomputed
+// metrics. The OTEL instruments API doesn't support recording histograms
+// with custom buckets, as provided by the runtime.
+type gcProducer struct {
+	mu      sync.Mutex
+	start   time.Time
+	samples []metrics.Sample
+}
+
+func newGCProducer() sdkMetric.Producer {
+	pauses := rmGCPausesLegacy
+	for _, d := range metrics.All() {
+		if d.Name == rmGCPauses {
+			pauses = rmGCPauses
+		}
+	}
+	return &gcProducer{
+		start: time.Now(),
+		samples: []metrics.Sample{
+			{Name: pauses},
+			{Name: rmGCCycles},
+		},
+	}
+}
+
+func (p *gcProducer) Produce(_ context.Context) ([]metricdata.ScopeMetrics, error) {
+	p.mu.Lock()
+	metrics.Read(p.samples)
+	now := time.Now()
+	pauses := p.samples[0].Value
+	cycles := p.samples[1].Value
+	p.mu.Unlock()
+
+	if pauses.Kind() != metrics.KindFloat64Histogram || cycles.Kind() != metrics.KindUint64 {
+		return nil, errors.New("unable to obtain garbage collector metrics from the runtime")
+	}
+	return []metricdata.ScopeMetrics{
+		{
+			Scope: instrumentation.Scope{Name: gcScopeName},
+			Metrics: []metricdata.Metrics{
+				{
+					Name:        "go.gc.pause.duration",
+					Description: "Distribution of individual stop-the-world pauses caused by the garbage collector.",
+					Unit:        "s",
+					Data: metricdata.Histogram[float64]{
+						Temporality: metricdata.CumulativeTemporality,
+						DataPoints:  []metricdata.HistogramDataPoint[float64]{histogram(pauses.Float64Histogram(), p.start, now)},
+					},
+				},
+				{
+					Name:        "go.gc.count",
+					Description: "Count of completed garbage collector cycles.",
+					Unit:        "{gc_cycle}",
+					Data: metricdata.Sum[int64]{
+						Temporality: metricdata.CumulativeTemporality,
+						IsMonotonic: true,
+						DataPoints: []metricdata.DataPoint[int64]{
+							{
+								StartTime: p.start,
+								Time:      now,
+								Value:     int64(cycles.Uint64()), // nolint: gosec
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// Convert a runtime histogram into an OTEL histogram data point. Runtime
+// histograms include the lower bound of the first bucket, and may include
+// an explicit +Inf upper bound.
+func histogram(rh *metrics.Float64Histogram, start, now time.Time) metricdata.HistogramDataPoint[float64] {
+	bounds := rh.Buckets[1:]
+	counts := append([]uint64{}, rh.Counts...)
+	if bounds[len(bounds)-1] == math.Inf(1) {
+		bounds = bounds[:len(bounds)-1]
+	} else {
+		counts = append(counts, 0) // implicit +Inf bucket
+	}
+	var (
+		count uint64
+		sum   float64
+	)
+	for i, c := range rh.Counts {
+		// the sum is an estimate; observations are assumed to be at
+		// the lower bound of each bucket
+		count += c
+		if lower := rh.Buckets[i]; c > 0 && !math.IsInf(lower, -1) {
+			sum += lower * float64(c)
+		}
+	}
+	return metricdata.HistogramDataPoint[float64]{
+		StartTime:    start,
+		Time:         now,
+		Count:        count,
+		Sum:          sum,
+		Bounds:       bounds,
+		BucketCounts: counts,
+	}
+}
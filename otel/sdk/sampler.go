@@ -0,0 +1,137 @@
+package sdk
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Supported sampling strategies. The names used match the values supported
+// by the standard `OTEL_TRACES_SAMPLER` environment variable.
+const (
+	// SamplerAlwaysOn samples every trace.
+	SamplerAlwaysOn = "always_on"
+
+	// SamplerAlwaysOff samples no traces.
+	SamplerAlwaysOff = "always_off"
+
+	// SamplerRatio samples a given fraction of traces.
+	SamplerRatio = "traceidratio"
+)
+
+// SamplerConfig provides a declarative definition of the sampling strategy
+// used by the application; suitable to be loaded from configuration files.
+//
+//	sampler, err := SamplerConfig{
+//		Strategy:    SamplerRatio,
+//		Ratio:       0.25,
+//		ParentBased: true,
+//		Rules: []SamplingRule{
+//			{Span: "health/*", Strategy: SamplerAlwaysOff},
+//		},
+//	}.Sampler()
+type SamplerConfig struct {
+	// Sampling strategy used; "always_on" by default.
+	Strategy string `json:"strategy" yaml:"strategy" mapstructure:"strategy"`
+
+	// Fraction of traces sampled, between 0 and 1; only used by the
+	// "traceidratio" strategy.
+	Ratio float64 `json:"ratio" yaml:"ratio" mapstructure:"ratio"`
+
+	// Respect the sampling decision of the parent span, if any. The
+	// strategy is only used for root spans.
+	ParentBased bool `json:"parent_based" yaml:"parent_based" mapstructure:"parent_based"`
+
+	// Rules used to adjust the sampling strategy for specific spans. Rules
+	// are evaluated in order; the first rule matching the span name is used.
+	Rules []SamplingRule `json:"rules" yaml:"rules" mapstructure:"rules"`
+}
+
+// SamplingRule adjust the sampling strategy used for spans with a name
+// matching a given pattern.
+type SamplingRule struct {
+	// Span name pattern, using the syntax supported by `path.Match`.
+	// For example: "health/*" or "GET /api/*".
+	Span string `json:"span" yaml:"span" mapstructure:"span"`
+
+	// Sampling strategy used for matching spans.
+	Strategy string `json:"strategy" yaml:"strategy" mapstructure:"strategy"`
+
+	// Fraction of matching traces sampled, between 0 and 1; only used by
+	// the "traceidratio" strategy.
+	Ratio float64 `json:"ratio" yaml:"ratio" mapstructure:"ratio"`
+}
+
+// Sampler returns the sampler instance described by the configuration.
+func (sc SamplerConfig) Sampler() (sdkTrace.Sampler, error) {
+	base, err := newSampler(sc.Strategy, sc.Ratio)
+	if err != nil {
+		return nil, err
+	}
+	if len(sc.Rules) > 0 {
+		sns := &spanNameSampler{fallback: base}
+		for _, r := range sc.Rules {
+			if _, err := path.Match(r.Span, ""); err != nil {
+				return nil, errors.Errorf("invalid span name pattern: %s", r.Span)
+			}
+			rs, err := newSampler(r.Strategy, r.Ratio)
+			if err != nil {
+				return nil, err
+			}
+			sns.rules = append(sns.rules, nameRule{pattern: r.Span, sampler: rs})
+		}
+		base = sns
+	}
+	if sc.ParentBased {
+		base = sdkTrace.ParentBased(base)
+	}
+	return base, nil
+}
+
+// Build a basic sampler instance.
+func newSampler(strategy string, ratio float64) (sdkTrace.Sampler, error) {
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case "", SamplerAlwaysOn:
+		return sdkTrace.AlwaysSample(), nil
+	case SamplerAlwaysOff:
+		return sdkTrace.NeverSample(), nil
+	case SamplerRatio:
+		if ratio < 0 || ratio > 1 {
+			return nil, errors.Errorf("invalid sampling ratio: %v", ratio)
+		}
+		return sdkTrace.TraceIDRatioBased(ratio), nil
+	default:
+		return nil, errors.Errorf("unsupported sampling strategy: %s", strategy)
+	}
+}
+
+type nameRule struct {
+	pattern string
+	sampler sdkTrace.Sampler
+}
+
+// Sampler delegating the sampling decision based on the span name.
+type spanNameSampler struct {
+	rules    []nameRule
+	fallback sdkTrace.Sampler
+}
+
+func (s *spanNameSampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	for _, r := range s.rules {
+		if ok, _ := path.Match(r.pattern, p.Name); ok {
+			return r.sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *spanNameSampler) Description() string {
+	rules := make([]string, len(s.rules))
+	for i, r := range s.rules {
+		rules[i] = fmt.Sprintf("%s:%s", r.pattern, r.sampler.Description())
+	}
+	return fmt.Sprintf("SpanNameBased{rules:[%s],fallback:%s}", strings.Join(rules, ","), s.fallback.Description())
+}
@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSamplerConfig(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Invalid configurations
+	_, err := SamplerConfig{Strategy: "probabilistic"}.Sampler()
+	assert.NotNil(err, "unsupported strategy")
+	_, err = SamplerConfig{Strategy: SamplerRatio, Ratio: 1.5}.Sampler()
+	assert.NotNil(err, "invalid ratio")
+	_, err = SamplerConfig{Rules: []SamplingRule{{Span: "[health"}}}.Sampler()
+	assert.NotNil(err, "invalid pattern")
+	_, err = Setup(WithSamplerConfig(SamplerConfig{Strategy: "probabilistic"}))
+	assert.NotNil(err, "setup with invalid configuration")
+
+	// Per-span-name rules
+	conf := SamplerConfig{
+		Strategy:    SamplerAlwaysOn,
+		ParentBased: true,
+		Rules: []SamplingRule{
+			{Span: "health/*", Strategy: SamplerAlwaysOff},
+			{Span: "GET /api/*", Strategy: SamplerRatio, Ratio: 0},
+		},
+	}
+	app, err := Setup(WithSamplerConfig(conf))
+	assert.Nil(err, "new operator")
+	defer app.Flush(context.Background())
+
+	tracer := app.traceProvider.Tracer("test")
+	sampled := func(ctx context.Context, name string) bool {
+		_, span := tracer.Start(ctx, name)
+		defer span.End()
+		return span.SpanContext().IsSampled()
+	}
+	assert.False(sampled(context.Background(), "health/ready"))
+	assert.False(sampled(context.Background(), "GET /api/users"))
+	assert.True(sampled(context.Background(), "POST /api/users"))
+
+	// Parent decision takes precedence
+	ctx, parent := tracer.Start(context.Background(), "operation")
+	defer parent.End()
+	assert.True(sampled(ctx, "health/ready"), "parent based")
+
+	// Explicit sampler replaces the configuration
+	app2, err := Setup(WithSamplerConfig(conf), WithSampler(sdkTrace.NeverSample()))
+	assert.Nil(err, "new operator")
+	defer app2.Flush(context.Background())
+	_, span := app2.traceProvider.Tracer("test").Start(context.Background(), "operation")
+	assert.False(span.SpanContext().IsSampled())
+	span.End()
+}
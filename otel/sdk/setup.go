@@ -2,16 +2,21 @@ package sdk
 
 import (
 	"context"
-	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
+	"go.bryk.io/pkg/errors"
 	"go.bryk.io/pkg/log"
 	"go.bryk.io/pkg/otel"
 	"go.opentelemetry.io/contrib/instrumentation/host"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	apiOtel "go.opentelemetry.io/otel"
+	logGlobal "go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
 	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 	sdkResource "go.opentelemetry.io/otel/sdk/resource"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 )
@@ -21,11 +26,18 @@ type Instrumentation struct {
 	log               log.Logger                      // logger instance
 	attrs             otel.Attributes                 // user-provided additional attributes
 	resource          *sdkResource.Resource           // OTEL resource definition
+	detectors         []sdkResource.Detector          // additional resource detectors
 	spanProcessors    []sdkTrace.SpanProcessor        // span processing chain
-	traceExporter     sdkTrace.SpanExporter           // trace sink components
-	metricExporter    sdkMetric.Exporter              // metric sink components
+	traceExporters    []sdkTrace.SpanExporter         // trace sink components
+	metricExporters   []sdkMetric.Exporter            // metric sink components
+	metricReaders     []sdkMetric.Reader              // metric "pull" components
+	logExporters      []sdkLog.Exporter               // log records sink components
+	exporterConfs     []ExporterConfig                // declarative exporters
+	queueConf         *PersistentQueueOptions         // disk-backed export queues
+	logProcessors     []sdkLog.Processor              // log records processing chain
 	traceProvider     *sdkTrace.TracerProvider        // main traces provider
 	meterProvider     *sdkMetric.MeterProvider        // main metrics provider
+	loggerProvider    *sdkLog.LoggerProvider          // main log records provider
 	propagator        propagation.TextMapPropagator   // default composite propagator
 	propagators       []propagation.TextMapPropagator // list of individual text map propagators
 	hostMetrics       bool                            // capture standard host metrics
@@ -33,7 +45,11 @@ type Instrumentation struct {
 	runtimeMetricsInt time.Duration                   // runtime memory capture interval
 	spanLimits        sdkTrace.SpanLimits             // default span limits
 	sampler           sdkTrace.Sampler                // trace sampler strategy used
-	exemplars         bool                            // enable exemplar support
+	samplerConf       *SamplerConfig                  // declarative sampler configuration
+	exemplarFilter    exemplar.Filter                 // exemplar support
+	views             []sdkMetric.View                // metric views
+	viewConfs         []ViewConfig                    // declarative metric views
+	logBridge         bool                            // emit log messages as OTEL log records
 }
 
 // Setup a new OpenTelemetry instrumented application.
@@ -42,7 +58,6 @@ func Setup(options ...Option) (*Instrumentation, error) {
 	app := &Instrumentation{
 		log:               log.Discard(),            // discard logs
 		attrs:             otel.Attributes{},        // no custom attributes
-		traceExporter:     new(noOpExporter),        // discard traces and metrics
 		sampler:           sdkTrace.AlwaysSample(),  // track all traces by default
 		spanLimits:        sdkTrace.NewSpanLimits(), // apply default span limits
 		runtimeMetricsInt: time.Duration(10) * time.Second,
@@ -55,13 +70,59 @@ func Setup(options ...Option) (*Instrumentation, error) {
 	for _, setting := range options {
 		setting(app)
 	}
+	if app.samplerConf != nil {
+		ss, err := app.samplerConf.Sampler()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid sampler configuration")
+		}
+		app.sampler = ss
+	}
+	for _, vc := range app.viewConfs {
+		view, err := vc.View()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid view configuration for '%s'", vc.Instrument)
+		}
+		app.views = append(app.views, view)
+	}
+	if app.queueConf != nil {
+		if err := app.queueConf.validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid persistent queue configuration")
+		}
+		if len(app.traceExporters) > 0 {
+			app.log.Warning("span exporters set with 'WithSpanExporter' don't use the persistent queue")
+		}
+	}
+	for i, ec := range app.exporterConfs {
+		se, me, le, err := ec.build(app.queueOptions(SignalTraces, i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exporter configuration for '%s'", ec.Type)
+		}
+		if se != nil {
+			app.traceExporters = append(app.traceExporters, se)
+		}
+		if me != nil {
+			app.metricExporters = append(app.metricExporters, me)
+		}
+		if le != nil {
+			app.logExporters = append(app.logExporters, le)
+		}
+	}
+	if app.queueConf != nil {
+		if err := app.setupQueues(); err != nil {
+			return nil, errors.Wrap(err, "invalid persistent queue configuration")
+		}
+	}
 
 	// Setup OTEL resource and collect its attributes. The setup process
 	// automatically collects environment information.
 	var err error
-	app.resource, err = setupResource(app.attrs)
+	app.resource, err = setupResource(app.attrs, app.detectors)
 	if err != nil {
-		return nil, err
+		// failures on resource detectors produce a partial resource
+		if !errors.Is(err, sdkResource.ErrPartialResource) || app.resource == nil {
+			return nil, err
+		}
+		app.log.WithField(lblErrorMsg, err.Error()).Warning("resource detection failed")
 	}
 	attrs := otel.Attributes{}
 	attrs.Load(app.resource.Attributes())
@@ -74,9 +135,16 @@ func Setup(options ...Option) (*Instrumentation, error) {
 	// and trace context propagator.
 	app.propagator = propagation.NewCompositeTextMapPropagator(app.propagators...)
 
-	// Prepare traces and metrics providers.
+	// Prepare traces, metrics and logs providers.
 	app.setupProviders()
 
+	// Forward log messages as OTEL log records. Messages produced for spans
+	// are already exported as part of the traces; so the base logger is used
+	// by the span processor.
+	if app.logBridge && app.loggerProvider != nil {
+		app.log = log.Composite(app.log, LogBridge("", app.loggerProvider))
+	}
+
 	// Set OTEL globals.
 	apiOtel.SetErrorHandler(errorHandler{ll: app.log}) // error handler
 	apiOtel.SetTextMapPropagator(app.propagator)       // propagator(s)
@@ -85,6 +153,9 @@ func Setup(options ...Option) (*Instrumentation, error) {
 		apiOtel.SetMeterProvider(app.meterProvider) // metric provider
 		app.captureStandardMetrics()                // start collecting common metrics
 	}
+	if app.loggerProvider != nil {
+		logGlobal.SetLoggerProvider(app.loggerProvider) // log records provider
+	}
 	return app, nil
 }
 
@@ -93,27 +164,87 @@ func (app *Instrumentation) Logger() log.Logger {
 	return app.log
 }
 
-// Flush immediately exports all spans that have not yet been exported for all
-// the registered span processors and shut down them down. No further data will
-// be captured or processed after this call.
+// ResourceAttributes returns the attributes of the resource, collected and
+// provided, describing the instrumented application. Useful to correlate
+// data produced by other components, like profilers, with the telemetry
+// data reported by the application.
+func (app *Instrumentation) ResourceAttributes() otel.Attributes {
+	attrs := otel.Attributes{}
+	attrs.Load(app.resource.Attributes())
+	return attrs
+}
+
+// LoggerProvider returns the provider used to emit OpenTelemetry log records.
+// Returns `nil` if no log exporter or processor was configured.
+func (app *Instrumentation) LoggerProvider() *sdkLog.LoggerProvider {
+	return app.loggerProvider
+}
+
+// Flush immediately exports all spans and log records that have not yet been
+// exported for all the registered processors and shut down them down. No
+// further data will be captured or processed after this call.
 func (app *Instrumentation) Flush(ctx context.Context) {
-	// Stop trace provider and exporter
+	// Stop trace provider and exporters
 	_ = app.traceProvider.ForceFlush(ctx)
 	_ = app.traceProvider.Shutdown(ctx)
-	_ = app.traceExporter.Shutdown(ctx)
+	for _, exp := range app.traceExporters {
+		_ = exp.Shutdown(ctx)
+	}
 
 	// Stop metric provider
 	if app.meterProvider != nil {
 		_ = app.meterProvider.Shutdown(ctx)
 	}
+
+	// Stop log provider
+	if app.loggerProvider != nil {
+		_ = app.loggerProvider.ForceFlush(ctx)
+		_ = app.loggerProvider.Shutdown(ctx)
+	}
 }
 
-// Create the metrics and traces providers.
+// Wrap the metric exporters using disk-backed queues. Queues for the OTLP
+// trace exporters are set when building the exporter configurations.
+func (app *Instrumentation) setupQueues() error {
+	for i, exp := range app.metricExporters {
+		pe, err := NewPersistentMetricExporter(exp, *app.queueOptions(SignalMetrics, i))
+		if err != nil {
+			return err
+		}
+		app.metricExporters[i] = pe
+	}
+	return nil
+}
+
+// Settings for the disk-backed queue of the exporter at position `i` for
+// `signal`; each exporter uses its own directory. Returns `nil` if no
+// persistent queue is configured.
+func (app *Instrumentation) queueOptions(signal string, i int) *PersistentQueueOptions {
+	if app.queueConf == nil {
+		return nil
+	}
+	conf := *app.queueConf
+	conf.Directory = filepath.Join(conf.Directory, signal, strconv.Itoa(i))
+	return &conf
+}
+
+// Create the traces, logs and metrics providers.
 func (app *Instrumentation) setupProviders() {
+	// Completed spans are submitted in batches to each exporter; every
+	// exporter uses its own processor so a slow or failing sink doesn't
+	// affect the rest. All traces are discarded if no exporter is set.
+	if len(app.traceExporters) == 0 {
+		app.traceExporters = append(app.traceExporters, new(noOpExporter))
+	}
+	batchers := make([]sdkTrace.SpanProcessor, len(app.traceExporters))
+	for i, exp := range app.traceExporters {
+		batchers[i] = sdkTrace.NewBatchSpanProcessor(exp)
+	}
+
 	// Custom span processor chain to generate logs.
 	spc := logSpans{
-		log:  app.log,                                           // custom `SpanProcessor` to generate logs
-		Next: sdkTrace.NewBatchSpanProcessor(app.traceExporter), // submit completed spans to the exporter
+		log:  app.log,     // custom `SpanProcessor` to generate logs
+		Next: batchers[0], // submit completed spans to the exporter
 	}
 
 	// Trace provider options.
@@ -123,6 +254,9 @@ func (app *Instrumentation) setupProviders() {
 		sdkTrace.WithRawSpanLimits(app.spanLimits), // use default span limits
 		sdkTrace.WithSpanProcessor(spc),            // set the span processing chain
 	}
+	for _, bsp := range batchers[1:] {
+		tpOpts = append(tpOpts, sdkTrace.WithSpanProcessor(bsp))
+	}
 	for _, sp := range app.spanProcessors {
 		tpOpts = append(tpOpts, sdkTrace.WithSpanProcessor(sp))
 	}
@@ -132,26 +266,57 @@ func (app *Instrumentation) setupProviders() {
 	// trace provider -> tracer -> span
 	app.traceProvider = sdkTrace.NewTracerProvider(tpOpts...)
 
-	// If no metrics exporter was provided, skip provider setup.
-	if app.metricExporter == nil {
+	// Create the log provider; log records are submitted in batches to each
+	// exporter.
+	if len(app.logExporters) > 0 || len(app.logProcessors) > 0 {
+		lpOpts := []sdkLog.LoggerProviderOption{
+			sdkLog.WithResource(app.resource),
+		}
+		for _, exp := range app.logExporters {
+			lpOpts = append(lpOpts, sdkLog.WithProcessor(sdkLog.NewBatchProcessor(exp)))
+		}
+		for _, lp := range app.logProcessors {
+			lpOpts = append(lpOpts, sdkLog.WithProcessor(lp))
+		}
+		app.loggerProvider = sdkLog.NewLoggerProvider(lpOpts...)
+	}
+
+	// If no metrics exporter or reader was provided, skip provider setup.
+	if len(app.metricExporters) == 0 && len(app.metricReaders) == 0 {
 		return
 	}
 
-	// Enable exemplar support.
-	// https://github.com/open-telemetry/opentelemetry-go/blob/main/sdk/metric/internal/x/README.md#exemplars
-	if app.exemplars {
-		if err := os.Setenv("OTEL_GO_X_EXEMPLAR", "true"); err != nil {
-			app.log.WithField(lblErrorMsg, err.Error()).Warning("failed to enable exemplar support")
-		}
-		if err := os.Setenv("OTEL_METRICS_EXEMPLAR_FILTER", "always_on"); err != nil {
-			app.log.WithField(lblErrorMsg, err.Error()).Warning("failed to enable exemplar support")
-		}
+	// Runtime metrics reported as precomputed histograms are obtained using
+	// producers attached to the "reader".
+	var readerOpts []sdkMetric.PeriodicReaderOption
+	if app.runtimeMetrics {
+		readerOpts = append(readerOpts,
+			sdkMetric.WithProducer(runtime.NewProducer()),
+			sdkMetric.WithProducer(newGCProducer()))
 	}
 
-	// Create meter provider instance using the provided "reader".
+	// Create meter provider instance using a periodic "reader" for each
+	// exporter, along with any additional readers provided.
 	metricProviderOpts := []sdkMetric.Option{
 		sdkMetric.WithResource(app.resource),
-		sdkMetric.WithReader(sdkMetric.NewPeriodicReader(app.metricExporter)),
+	}
+	for _, exp := range app.metricExporters {
+		reader := sdkMetric.NewPeriodicReader(exp, readerOpts...)
+		metricProviderOpts = append(metricProviderOpts, sdkMetric.WithReader(reader))
+	}
+	for _, rd := range app.metricReaders {
+		metricProviderOpts = append(metricProviderOpts, sdkMetric.WithReader(rd))
+	}
+
+	// Enable exemplar support; measurements are offered to the exemplar
+	// reservoirs based on the filter used.
+	if app.exemplarFilter != nil {
+		metricProviderOpts = append(metricProviderOpts, sdkMetric.WithExemplarFilter(app.exemplarFilter))
+	}
+	// Views are applied in the order they were registered; if no view
+	// matches an instrument the default aggregation is used.
+	if len(app.views) > 0 {
+		metricProviderOpts = append(metricProviderOpts, sdkMetric.WithView(app.views...))
 	}
 	app.meterProvider = sdkMetric.NewMeterProvider(metricProviderOpts...)
 }
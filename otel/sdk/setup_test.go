@@ -2,14 +2,22 @@ package sdk
 
 import (
 	"context"
+	"encoding/hex"
 	"net/http"
+	"runtime"
 	"testing"
 	"time"
 
 	tdd "github.com/stretchr/testify/assert"
 	"go.bryk.io/pkg/log"
 	"go.bryk.io/pkg/otel"
+	apiOtel "go.opentelemetry.io/otel"
+	otelLog "go.opentelemetry.io/otel/log"
+	logGlobal "go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
 	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -55,6 +63,210 @@ func TestSetup(t *testing.T) {
 	log.Info("application message")
 }
 
+func TestLogs(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Log exporter
+	logExp, err := LogExporterStdout(true)
+	assert.Nil(err, "failed to create log exporter")
+
+	// Setup instrumented application
+	rec := new(logRecorder)
+	app, err := Setup(
+		WithServiceName("my-service"),
+		WithLogExporter(logExp),
+		WithLogProcessor(rec),
+	)
+	assert.Nil(err, "new operator")
+	assert.NotNil(app.LoggerProvider(), "logger provider")
+
+	// Emit a log record using the global provider
+	var record otelLog.Record
+	record.SetSeverity(otelLog.SeverityInfo)
+	record.SetBody(otelLog.StringValue("application message"))
+	logGlobal.GetLoggerProvider().Logger("test").Emit(context.Background(), record)
+	app.Flush(context.Background())
+
+	// Records are exported with the instrumentation resource
+	assert.Len(rec.records, 1, "log records")
+	assert.Equal("application message", rec.records[0].Body().AsString())
+	found := false
+	res := rec.records[0].Resource()
+	for _, kv := range res.Attributes() {
+		if string(kv.Key) == lblSvcName {
+			found = kv.Value.AsString() == "my-service"
+		}
+	}
+	assert.True(found, "resource attributes")
+}
+
+func TestLogBridge(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Setup instrumented application
+	rec := new(logRecorder)
+	app, err := Setup(
+		WithServiceName("my-service"),
+		WithLogProcessor(rec),
+		WithLogBridge(),
+	)
+	assert.Nil(err, "new operator")
+
+	// Messages are correlated with the active span
+	task := app.traceProvider.Tracer("test")
+	ctx, span := task.Start(context.Background(), "operation")
+	app.Logger().WithFields(ContextFields(ctx)).WithField("user", "rick").Warning("access denied")
+	span.End()
+	app.Logger().Debug("uncorrelated message")
+	app.Flush(context.Background())
+
+	assert.Len(rec.records, 2, "log records")
+	r := rec.records[0]
+	assert.Equal("access denied", r.Body().AsString())
+	assert.Equal(otelLog.SeverityWarn, r.Severity())
+	assert.Equal(span.SpanContext().TraceID(), r.TraceID(), "trace id")
+	assert.Equal(span.SpanContext().SpanID(), r.SpanID(), "span id")
+	attrs := map[string]string{}
+	r.WalkAttributes(func(kv otelLog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.AsString()
+		return true
+	})
+	assert.Equal("rick", attrs["user"])
+	assert.NotContains(attrs, lblTraceID)
+	assert.False(rec.records[1].TraceID().IsValid(), "uncorrelated record")
+}
+
+func TestExemplars(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Setup instrumented application
+	exp := new(metricRecorder)
+	app, err := Setup(
+		WithServiceName("my-service"),
+		WithMetricExporter(exp),
+		WithExemplars(),
+	)
+	assert.Nil(err, "new operator")
+
+	// Record a measurement in the context of a sampled span
+	counter, _ := app.meterProvider.Meter("test").Int64Counter("requests")
+	ctx, span := app.traceProvider.Tracer("test").Start(context.Background(), "operation")
+	counter.Add(ctx, 1)
+	span.End()
+	app.Flush(context.Background())
+
+	// Exemplar is linked to the span
+	assert.Len(exp.traces, 1, "exemplars")
+	assert.Contains(exp.traces, span.SpanContext().TraceID().String())
+}
+
+func TestPropagators(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Unsupported mechanism
+	_, err := Propagators(PropagatorTraceContext, "jaeger")
+	assert.NotNil(err, "unsupported propagator")
+
+	// Replace default propagators
+	props, err := Propagators(PropagatorB3Multi, PropagatorXRay)
+	assert.Nil(err, "propagators")
+	app, err := Setup(WithPropagators(props...))
+	assert.Nil(err, "new operator")
+	defer app.Flush(context.Background())
+
+	// Context is propagated using the selected formats only
+	ctx, span := app.traceProvider.Tracer("test").Start(context.Background(), "operation")
+	defer span.End()
+	headers := http.Header{}
+	apiOtel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(headers))
+	assert.Equal(span.SpanContext().TraceID().String(), headers.Get("X-B3-TraceId"))
+	assert.NotEmpty(headers.Get("X-Amzn-Trace-Id"))
+	assert.Empty(headers.Get("traceparent"))
+}
+
+func TestRuntimeMetrics(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Setup instrumented application
+	exp := new(metricRecorder)
+	app, err := Setup(
+		WithServiceName("my-service"),
+		WithMetricExporter(exp),
+		WithRuntimeMetrics(0),
+	)
+	assert.Nil(err, "new operator")
+	runtime.GC()
+	app.Flush(context.Background())
+
+	// Heap and goroutines metrics names depend on the runtime instrumentation
+	// settings; 'OTEL_GO_X_DEPRECATED_RUNTIME_METRICS'
+	assert.NotEmpty(exp.names)
+	assert.Condition(func() bool {
+		for _, name := range exp.names {
+			if name == "go.goroutine.count" || name == "process.runtime.go.goroutines" {
+				return true
+			}
+		}
+		return false
+	}, "goroutine count")
+
+	// Scheduler and GC metrics are reported
+	assert.Contains(exp.names, "go.schedule.duration")
+	assert.Contains(exp.names, "go.gc.pause.duration")
+	assert.Contains(exp.names, "go.gc.count")
+}
+
+// Metric exporter collecting the names of all metrics and the trace
+// identifiers of all the exemplars received.
+type metricRecorder struct {
+	names  []string
+	traces []string
+}
+
+func (mr *metricRecorder) Temporality(k sdkMetric.InstrumentKind) metricdata.Temporality {
+	return sdkMetric.DefaultTemporalitySelector(k)
+}
+
+func (mr *metricRecorder) Aggregation(k sdkMetric.InstrumentKind) sdkMetric.Aggregation {
+	return sdkMetric.DefaultAggregationSelector(k)
+}
+
+func (mr *metricRecorder) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			mr.names = append(mr.names, m.Name)
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				for _, ex := range dp.Exemplars {
+					mr.traces = append(mr.traces, hex.EncodeToString(ex.TraceID))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (mr *metricRecorder) ForceFlush(_ context.Context) error { return nil }
+
+func (mr *metricRecorder) Shutdown(_ context.Context) error { return nil }
+
+// Log processor keeping all emitted records in memory.
+type logRecorder struct {
+	records []sdkLog.Record
+}
+
+func (lr *logRecorder) OnEmit(_ context.Context, record *sdkLog.Record) error {
+	lr.records = append(lr.records, record.Clone())
+	return nil
+}
+
+func (lr *logRecorder) Shutdown(_ context.Context) error { return nil }
+
+func (lr *logRecorder) ForceFlush(_ context.Context) error { return nil }
+
 // Verify a local collector instance is available using its `health check`
 // endpoint.
 func isCollectorAvailable() bool {
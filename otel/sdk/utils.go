@@ -7,21 +7,21 @@ import (
 	"reflect"
 	"strings"
 
+	"go.bryk.io/pkg/errors"
 	"go.bryk.io/pkg/log"
 	"go.bryk.io/pkg/otel"
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
 	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkResource "go.opentelemetry.io/otel/sdk/resource"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 	semConv "go.opentelemetry.io/otel/semconv/v1.20.0"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/encoding/gzip"
 )
 
 const (
@@ -39,9 +39,56 @@ const (
 	lblErrorMsg         = "error.message"
 )
 
+// Supported propagation mechanisms.
+const (
+	// PropagatorTraceContext uses W3C Trace Context headers.
+	// https://www.w3.org/TR/trace-context/
+	PropagatorTraceContext = "tracecontext"
+
+	// PropagatorBaggage uses W3C Baggage headers.
+	// https://www.w3.org/TR/baggage/
+	PropagatorBaggage = "baggage"
+
+	// PropagatorB3 uses the B3 single header format ("b3").
+	// https://github.com/openzipkin/b3-propagation#single-header
+	PropagatorB3 = "b3"
+
+	// PropagatorB3Multi uses the B3 multiple headers format ("x-b3-*").
+	// https://github.com/openzipkin/b3-propagation#multiple-headers
+	PropagatorB3Multi = "b3multi"
+
+	// PropagatorXRay uses the AWS X-Ray header format ("X-Amzn-Trace-Id").
+	// https://docs.aws.amazon.com/xray/latest/devguide/xray-concepts.html#xray-concepts-tracingheader
+	PropagatorXRay = "xray"
+)
+
+// Propagators returns the propagation mechanisms for the provided names, in
+// the same order. The names used match the values supported by the standard
+// `OTEL_PROPAGATORS` environment variable.
+func Propagators(names ...string) ([]propagation.TextMapPropagator, error) {
+	list := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case PropagatorTraceContext:
+			list = append(list, propagation.TraceContext{})
+		case PropagatorBaggage:
+			list = append(list, propagation.Baggage{})
+		case PropagatorB3:
+			list = append(list, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			list = append(list, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorXRay:
+			list = append(list, xray.Propagator{})
+		default:
+			return nil, errors.Errorf("unsupported propagator: %s", name)
+		}
+	}
+	return list, nil
+}
+
 // WithExporterStdout is a utility method to automatically setup and attach
-// trace and metric exporters to send the generated telemetry data to standard
-// output.
+// trace, metric and log exporters to send the generated telemetry data to
+// standard output.
 func WithExporterStdout(pretty bool) []Option {
 	var opts []Option
 	se, me, err := ExporterStdout(pretty)
@@ -49,12 +96,15 @@ func WithExporterStdout(pretty bool) []Option {
 		opts = append(opts, WithSpanExporter(se))
 		opts = append(opts, WithMetricExporter(me))
 	}
+	if le, err := LogExporterStdout(pretty); err == nil {
+		opts = append(opts, WithLogExporter(le))
+	}
 	return opts
 }
 
 // WithExporterOTLP is a utility method to automatically setup and attach
-// trace and metric exporters to send the generated telemetry data to an OTLP
-// exporter instance.
+// trace, metric and log exporters to send the generated telemetry data to an
+// OTLP exporter instance.
 // https://opentelemetry.io/docs/collector/
 func WithExporterOTLP(endpoint string, insecure bool, headers map[string]string, protocol string) []Option {
 	var opts []Option
@@ -63,9 +113,35 @@ func WithExporterOTLP(endpoint string, insecure bool, headers map[string]string,
 		opts = append(opts, WithSpanExporter(se))
 		opts = append(opts, WithMetricExporter(me))
 	}
+	if le, err := LogExporterOTLP(endpoint, insecure, headers, protocol); err == nil {
+		opts = append(opts, WithLogExporter(le))
+	}
 	return opts
 }
 
+// LogExporterStdout returns a new log records exporter to send telemetry
+// data to standard output.
+func LogExporterStdout(pretty bool) (sdkLog.Exporter, error) {
+	var opts []stdoutlog.Option
+	if pretty {
+		opts = append(opts, stdoutlog.WithPrettyPrint())
+	}
+	return stdoutlog.New(opts...)
+}
+
+// LogExporterOTLP returns an initialized OTLP log records exporter instance
+// utilizing the requested protocol.
+func LogExporterOTLP(endpoint string, insecure bool, headers map[string]string, protocol string) (sdkLog.Exporter, error) { // nolint:lll
+	conf := ExporterConfig{
+		Type:     ExporterTypeOTLP,
+		Endpoint: endpoint,
+		Protocol: otlpProtocol(protocol),
+		Insecure: insecure,
+		Headers:  headers,
+	}
+	return conf.logExporter()
+}
+
 // ExporterStdout returns a new trace exporter to send telemetry data
 // to standard output.
 func ExporterStdout(pretty bool) (sdkTrace.SpanExporter, sdkMetric.Exporter, error) {
@@ -89,93 +165,32 @@ func ExporterStdout(pretty bool) (sdkTrace.SpanExporter, sdkMetric.Exporter, err
 }
 
 // ExporterOTLP returns an initialized OTLP exporter instance utilizing
-// the requested protocol.
+// the requested protocol. The default endpoint for the collector is
+// "localhost:4317" when using gRPC and "localhost:4318" when using HTTP.
+// Use `ExporterConfig` to customize the TLS settings or to submit each
+// signal to a different endpoint.
 func ExporterOTLP(endpoint string, insecure bool, headers map[string]string, protocol string) (sdkTrace.SpanExporter, sdkMetric.Exporter, error) { // nolint:lll
-	if protocol == "http" {
-		return otlpHTTP(endpoint, insecure, headers)
-	}
-	return otlpGRPC(endpoint, insecure, headers)
-}
-
-// Returns an initialized OTLP exporter instance utilizing
-// HTTP with protobuf payloads. The default endpoint for the collector
-// is "localhost:4318".
-func otlpHTTP(endpoint string, insecure bool, headers map[string]string) (sdkTrace.SpanExporter, sdkMetric.Exporter, error) { // nolint:lll
-	if endpoint == "" {
-		endpoint = "localhost:4318"
+	conf := ExporterConfig{
+		Type:     ExporterTypeOTLP,
+		Endpoint: endpoint,
+		Protocol: otlpProtocol(protocol),
+		Insecure: insecure,
+		Headers:  headers,
 	}
-	ctx := context.Background()
-	traceOpts := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(endpoint),
-		otlptracehttp.WithHeaders(headers),
-		otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
-	}
-	metricOpts := []otlpmetrichttp.Option{
-		otlpmetrichttp.WithEndpoint(endpoint),
-		otlpmetrichttp.WithHeaders(headers),
-		otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
-	}
-	if insecure {
-		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
-		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
-	}
-
-	// Trace exporter
-	traceExp, err := otlptracehttp.New(ctx, traceOpts...)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Metric exporter
-	metricExp, err := otlpmetrichttp.New(ctx, metricOpts...)
-	if err != nil {
-		return nil, nil, err
-	}
-	return traceExp, metricExp, nil
-}
-
-// Returns an initialized OTLP exporter instance utilizing gRPC.
-// The default endpoint for the collector is "localhost:4317".
-func otlpGRPC(endpoint string, insecure bool, headers map[string]string) (sdkTrace.SpanExporter, sdkMetric.Exporter, error) { // nolint:lll
-	if endpoint == "" {
-		endpoint = "localhost:4317"
-	}
-	ctx := context.Background()
-	traceOpts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(endpoint),
-		otlptracegrpc.WithHeaders(headers),
-		otlptracegrpc.WithCompressor(gzip.Name),
-	}
-	metricOpts := []otlpmetricgrpc.Option{
-		otlpmetricgrpc.WithEndpoint(endpoint),
-		otlpmetricgrpc.WithHeaders(headers),
-		otlpmetricgrpc.WithCompressor(gzip.Name),
-	}
-	if insecure {
-		traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
-		metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
-	} else {
-		creds := credentials.NewClientTLSFromCert(nil, "")
-		traceOpts = append(traceOpts, otlptracegrpc.WithTLSCredentials(creds))
-		metricOpts = append(metricOpts, otlpmetricgrpc.WithTLSCredentials(creds))
-	}
-
-	// Trace exporter
-	traceExp, err := otlptracegrpc.New(ctx, traceOpts...)
+	traceExp, err := conf.spanExporter(nil)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	// Metric exporter
-	metricExp, err := otlpmetricgrpc.New(ctx, metricOpts...)
+	metricExp, err := conf.metricExporter()
 	if err != nil {
 		return nil, nil, err
 	}
 	return traceExp, metricExp, nil
 }
 
-// Collect environment information and setup the OTEL resource.
-func setupResource(attrs otel.Attributes) (*sdkResource.Resource, error) {
+// Collect environment information and setup the OTEL resource. Attributes
+// provided by the user take precedence over the detected ones.
+func setupResource(attrs otel.Attributes, detectors []sdkResource.Detector) (*sdkResource.Resource, error) {
 	return sdkResource.New(context.Background(),
 		sdkResource.WithOS(),
 		sdkResource.WithHost(),
@@ -185,6 +200,7 @@ func setupResource(attrs otel.Attributes) (*sdkResource.Resource, error) {
 		sdkResource.WithProcessRuntimeName(),
 		sdkResource.WithProcessRuntimeVersion(),
 		sdkResource.WithProcessRuntimeDescription(),
+		sdkResource.WithDetectors(detectors...),
 		sdkResource.WithAttributes(expand(attrs)...))
 }
 
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"sort"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// ViewConfig provides a declarative definition of a metric view; suitable
+// to be loaded from configuration files. Views allow to adjust the metric
+// streams produced by instruments; for example to rename or drop instruments,
+// limit the attributes recorded (reducing cardinality) or customize the
+// bucket boundaries used for histograms.
+//
+//	view, err := ViewConfig{
+//		Instrument: "http.server.request.duration",
+//		Attributes: []string{"http.request.method", "http.route"},
+//		Buckets:    []float64{0.01, 0.05, 0.1, 0.5, 1, 5},
+//	}.View()
+type ViewConfig struct {
+	// Name of the instrument(s) the view applies to. Supports the "*"
+	// and "?" wildcards. Required.
+	Instrument string `json:"instrument" yaml:"instrument" mapstructure:"instrument"`
+
+	// Restrict the view to instruments created by the instrumentation
+	// scope (meter) with this name. Optional.
+	Meter string `json:"meter" yaml:"meter" mapstructure:"meter"`
+
+	// New name for the metric stream. Can't be used when the instrument
+	// name includes wildcards.
+	Rename string `json:"rename" yaml:"rename" mapstructure:"rename"`
+
+	// New description for the metric stream.
+	Description string `json:"description" yaml:"description" mapstructure:"description"`
+
+	// Drop all measurements recorded by matching instruments.
+	Drop bool `json:"drop" yaml:"drop" mapstructure:"drop"`
+
+	// Attribute keys to keep; all other attributes are removed.
+	Attributes []string `json:"attributes" yaml:"attributes" mapstructure:"attributes"`
+
+	// Attribute keys to remove; all other attributes are kept. Ignored
+	// when `Attributes` is provided.
+	ExcludeAttributes []string `json:"exclude_attributes" yaml:"exclude_attributes" mapstructure:"exclude_attributes"`
+
+	// Explicit bucket boundaries used to aggregate measurements as a
+	// histogram. Values must be in increasing order.
+	Buckets []float64 `json:"buckets" yaml:"buckets" mapstructure:"buckets"`
+}
+
+// View returns the metric view described by the configuration.
+func (vc ViewConfig) View() (sdkMetric.View, error) {
+	if vc.Instrument == "" {
+		return nil, errors.New("instrument name is required")
+	}
+	if vc.Rename != "" && strings.ContainsAny(vc.Instrument, "*?") {
+		return nil, errors.Errorf("can't rename instruments matched using wildcards: %s", vc.Instrument)
+	}
+	if vc.Drop && len(vc.Buckets) > 0 {
+		return nil, errors.New("'drop' and 'buckets' can't be used together")
+	}
+	if !sort.Float64sAreSorted(vc.Buckets) {
+		return nil, errors.Errorf("bucket boundaries must be in increasing order: %v", vc.Buckets)
+	}
+
+	// selection criteria
+	criteria := sdkMetric.Instrument{Name: vc.Instrument}
+	if vc.Meter != "" {
+		criteria.Scope = instrumentation.Scope{Name: vc.Meter}
+	}
+
+	// stream adjustments
+	mask := sdkMetric.Stream{
+		Name:        vc.Rename,
+		Description: vc.Description,
+	}
+	switch {
+	case vc.Drop:
+		mask.Aggregation = sdkMetric.AggregationDrop{}
+	case len(vc.Buckets) > 0:
+		mask.Aggregation = sdkMetric.AggregationExplicitBucketHistogram{Boundaries: vc.Buckets}
+	}
+	switch {
+	case len(vc.Attributes) > 0:
+		mask.AttributeFilter = attribute.NewAllowKeysFilter(keys(vc.Attributes)...)
+	case len(vc.ExcludeAttributes) > 0:
+		mask.AttributeFilter = attribute.NewDenyKeysFilter(keys(vc.ExcludeAttributes)...)
+	}
+	return sdkMetric.NewView(criteria, mask), nil
+}
+
+func keys(list []string) []attribute.Key {
+	res := make([]attribute.Key, len(list))
+	for i, k := range list {
+		res[i] = attribute.Key(k)
+	}
+	return res
+}
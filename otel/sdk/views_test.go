@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestViewConfig(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Invalid configurations
+	_, err := ViewConfig{}.View()
+	assert.NotNil(err, "missing instrument")
+	_, err = ViewConfig{Instrument: "http.*", Rename: "requests"}.View()
+	assert.NotNil(err, "rename with wildcards")
+	_, err = ViewConfig{Instrument: "latency", Drop: true, Buckets: []float64{1, 2}}.View()
+	assert.NotNil(err, "drop with buckets")
+	_, err = ViewConfig{Instrument: "latency", Buckets: []float64{5, 1}}.View()
+	assert.NotNil(err, "unsorted buckets")
+	_, err = Setup(WithViewConfig(ViewConfig{Instrument: "latency", Buckets: []float64{5, 1}}))
+	assert.NotNil(err, "setup with invalid configuration")
+
+	// Build views
+	var views []sdkMetric.View
+	for _, vc := range []ViewConfig{
+		{Instrument: "latency", Rename: "request.latency", Buckets: []float64{10, 100}},
+		{Instrument: "debug.*", Drop: true},
+		{Instrument: "requests", Attributes: []string{"route"}},
+		{Instrument: "errors", ExcludeAttributes: []string{"user"}},
+	} {
+		view, err := vc.View()
+		assert.Nil(err, "view")
+		views = append(views, view)
+	}
+	reader := sdkMetric.NewManualReader()
+	mp := sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader), sdkMetric.WithView(views...))
+	meter := mp.Meter("test")
+
+	// Record measurements
+	ctx := context.Background()
+	attrs := metric.WithAttributes(
+		attribute.String("route", "/ping"),
+		attribute.String("user", "rick"),
+	)
+	latency, _ := meter.Float64Histogram("latency")
+	latency.Record(ctx, 50, attrs)
+	debug, _ := meter.Int64Counter("debug.calls")
+	debug.Add(ctx, 1, attrs)
+	requests, _ := meter.Int64Counter("requests")
+	requests.Add(ctx, 1, attrs)
+	errs, _ := meter.Int64Counter("errors")
+	errs.Add(ctx, 1, attrs)
+
+	// Verify streams
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(reader.Collect(ctx, &rm), "collect")
+	got := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+	assert.Len(got, 3, "dropped instrument")
+	hist, ok := got["request.latency"].Data.(metricdata.Histogram[float64])
+	assert.True(ok, "renamed instrument")
+	assert.Equal([]float64{10, 100}, hist.DataPoints[0].Bounds, "custom buckets")
+	sum := got["requests"].Data.(metricdata.Sum[int64])
+	assert.Equal(1, sum.DataPoints[0].Attributes.Len(), "allowed attributes")
+	assert.True(sum.DataPoints[0].Attributes.HasValue("route"))
+	sum = got["errors"].Data.(metricdata.Sum[int64])
+	assert.False(sum.DataPoints[0].Attributes.HasValue("user"), "excluded attributes")
+	assert.True(sum.DataPoints[0].Attributes.HasValue("route"))
+}
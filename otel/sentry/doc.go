@@ -15,5 +15,37 @@ and error tracking purposes.
 
 More information:
 https://docs.sentry.io/platforms/go/performance/instrumentation/opentelemetry
+
+# Profiling and Release Health
+
+Transaction profiles are collected when `ProfilingSampleRate` is set, as a
+ratio of the traces sampled using `TracesSampleRate`. Enable `ReleaseHealth`
+to report every request handled as a session, which allows tracking crash
+free and error rates per release.
+
+	rep, err := sentry.NewReporter(&sentry.Options{
+		DSN:                         dsn,
+		Release:                     "my-service@1.2.0",
+		EnablePerformanceMonitoring: true,
+		TracesSampleRate:            0.5,
+		ProfilingSampleRate:         0.2,
+		ReleaseHealth:               true,
+	})
+
+# Cron Monitoring
+
+Periodic jobs can be reported as Sentry Cron check-ins, to get alerted
+when a job fails, takes too long to complete or stops running.
+
+	mon := reporter.Monitor("nightly-cleanup", &sentry.MonitorOptions{
+		Schedule: "0 3 * * *",
+		Margin:   5 * time.Minute,
+	})
+	err := mon.Run(ctx, func(ctx context.Context) error {
+		// job logic
+		return nil
+	})
+
+https://docs.sentry.io/product/crons
 */
 package sentry
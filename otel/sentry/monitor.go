@@ -0,0 +1,191 @@
+package sentry
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/getsentry/sentry-go"
+	"go.bryk.io/pkg/errors"
+	apiOtel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation scope used for the spans created by monitors.
+const monitorScope = "go.bryk.io/pkg/otel/sentry"
+
+// MonitorOptions defines the configuration settings for a Sentry Cron
+// monitor. Settings are submitted with every check-in, which allows
+// Sentry to create and update the monitor automatically ("upsert").
+//
+// https://docs.sentry.io/product/crons
+type MonitorOptions struct {
+	// Crontab expression describing when the job is expected to run.
+	// For example: "*/10 * * * *". Takes precedence over `Interval`.
+	Schedule string `mapstructure:"schedule" yaml:"schedule" json:"schedule"`
+
+	// Fixed interval between job executions. Values are reported with
+	// the largest unit (minute, hour, day or week) that represents them
+	// exactly; with a minimum of 1 minute.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval" json:"interval"`
+
+	// Time allowed after the expected check-in time before the job is
+	// considered as missed. Rounded to minutes.
+	Margin time.Duration `mapstructure:"margin" yaml:"margin" json:"margin"`
+
+	// Time allowed for the job to complete before it is considered as
+	// failed due to timeout. Rounded to minutes.
+	MaxRuntime time.Duration `mapstructure:"max_runtime" yaml:"max_runtime" json:"max_runtime"`
+
+	// TZ database identifier of the timezone used by the schedule.
+	// For example: "America/Mexico_City".
+	Timezone string `mapstructure:"timezone" yaml:"timezone" json:"timezone"`
+
+	// Number of consecutive failed check-ins required to create an issue.
+	FailureThreshold int64 `mapstructure:"failure_threshold" yaml:"failure_threshold" json:"failure_threshold"`
+
+	// Number of consecutive successful check-ins required to resolve an issue.
+	RecoveryThreshold int64 `mapstructure:"recovery_threshold" yaml:"recovery_threshold" json:"recovery_threshold"`
+}
+
+// Monitor instances report the execution of periodic jobs as Sentry Cron
+// check-ins. Sentry will alert when a job fails, takes too long to complete
+// or stops running altogether.
+type Monitor struct {
+	slug string
+	hub  *sdk.Hub
+	conf *sdk.MonitorConfig
+}
+
+// CheckIn represents a single in-progress execution of a monitored job.
+// Use `Finish` to report its final status.
+type CheckIn struct {
+	id    *sdk.EventID
+	start time.Time
+	mon   *Monitor
+}
+
+// Monitor returns a new Sentry Cron monitor handler. The `slug` value
+// identifies the monitor in the Sentry project.
+//
+//	mon := reporter.Monitor("nightly-cleanup", &sentry.MonitorOptions{
+//		Schedule: "0 3 * * *",
+//		Margin:   5 * time.Minute,
+//	})
+//	err := mon.Run(ctx, cleanup)
+func (sr *Reporter) Monitor(slug string, opts *MonitorOptions) *Monitor {
+	return newMonitor(sr.hub, slug, opts)
+}
+
+func newMonitor(hub *sdk.Hub, slug string, opts *MonitorOptions) *Monitor {
+	mon := &Monitor{slug: slug, hub: hub}
+	if opts != nil {
+		mon.conf = opts.config()
+	}
+	return mon
+}
+
+// Start reports a new job execution as "in progress".
+func (m *Monitor) Start() *CheckIn {
+	return &CheckIn{
+		id:    m.capture(&sdk.CheckIn{Status: sdk.CheckInStatusInProgress}),
+		start: time.Now(),
+		mon:   m,
+	}
+}
+
+// Run the provided job function, reporting its execution as check-ins.
+// The job runs as an instrumented task, using the global tracer provider;
+// spans created using the provided context are linked to it. If the job
+// returns an error, or panics, the check-in is reported as failed. Errors
+// returned by the job are returned as-is.
+func (m *Monitor) Run(ctx context.Context, job func(ctx context.Context) error) (err error) {
+	ctx, span := apiOtel.Tracer(monitorScope).Start(ctx, m.slug,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attribute.String("monitor.slug", m.slug)))
+	ci := m.Start()
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.FromRecover(rec)
+			ci.Finish(err)
+			endSpan(span, err)
+			panic(rec)
+		}
+		ci.Finish(err)
+		endSpan(span, err)
+	}()
+	return job(ctx)
+}
+
+// Finish reports the final status of the job execution. The check-in is
+// reported as failed if `err` is not nil.
+func (ci *CheckIn) Finish(err error) {
+	status := sdk.CheckInStatusOK
+	if err != nil {
+		status = sdk.CheckInStatusError
+	}
+	checkIn := &sdk.CheckIn{
+		Status:   status,
+		Duration: time.Since(ci.start),
+	}
+	if ci.id != nil {
+		checkIn.ID = *ci.id
+	}
+	ci.mon.capture(checkIn)
+}
+
+func (m *Monitor) capture(ci *sdk.CheckIn) *sdk.EventID {
+	ci.MonitorSlug = m.slug
+	return m.hub.CaptureCheckIn(ci, m.conf)
+}
+
+func (mo *MonitorOptions) config() *sdk.MonitorConfig {
+	conf := &sdk.MonitorConfig{
+		CheckInMargin:         minutes(mo.Margin),
+		MaxRuntime:            minutes(mo.MaxRuntime),
+		Timezone:              mo.Timezone,
+		FailureIssueThreshold: mo.FailureThreshold,
+		RecoveryThreshold:     mo.RecoveryThreshold,
+	}
+	switch {
+	case mo.Schedule != "":
+		conf.Schedule = sdk.CrontabSchedule(mo.Schedule)
+	case mo.Interval > 0:
+		conf.Schedule = intervalSchedule(mo.Interval)
+	}
+	return conf
+}
+
+// Express `d` using the largest unit that represents it exactly.
+func intervalSchedule(d time.Duration) sdk.MonitorSchedule {
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+	)
+	switch {
+	case d%week == 0:
+		return sdk.IntervalSchedule(int64(d/week), sdk.MonitorScheduleUnitWeek)
+	case d%day == 0:
+		return sdk.IntervalSchedule(int64(d/day), sdk.MonitorScheduleUnitDay)
+	case d%time.Hour == 0:
+		return sdk.IntervalSchedule(int64(d/time.Hour), sdk.MonitorScheduleUnitHour)
+	default:
+		return sdk.IntervalSchedule(max(minutes(d), 1), sdk.MonitorScheduleUnitMinute)
+	}
+}
+
+// Round `d` to minutes.
+func minutes(d time.Duration) int64 {
+	return int64(d.Round(time.Minute) / time.Minute)
+}
+
+// Complete the span used to track a job execution, marking it as failed
+// if `err` is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
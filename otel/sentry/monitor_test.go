@@ -0,0 +1,101 @@
+package sentry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/getsentry/sentry-go"
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/errors"
+)
+
+func TestMonitor(t *testing.T) {
+	assert := tdd.New(t)
+	tr := new(testTransport)
+	client, err := sdk.NewClient(sdk.ClientOptions{
+		Dsn:       "https://public@sentry.example.com/1",
+		Transport: tr,
+	})
+	assert.Nil(err, "new client")
+	hub := sdk.NewHub(client, sdk.NewScope())
+
+	// Successful execution
+	mon := newMonitor(hub, "nightly-job", &MonitorOptions{
+		Interval:   2 * time.Hour,
+		Margin:     5 * time.Minute,
+		MaxRuntime: 30 * time.Minute,
+	})
+	assert.Nil(mon.Run(context.Background(), func(_ context.Context) error { return nil }))
+	events := tr.list()
+	assert.Len(events, 2, "check-ins")
+	assert.Equal(sdk.CheckInStatusInProgress, events[0].CheckIn.Status)
+	assert.Equal(sdk.CheckInStatusOK, events[1].CheckIn.Status)
+	assert.Equal(events[0].CheckIn.ID, events[1].CheckIn.ID, "same check-in")
+	assert.Equal("nightly-job", events[1].CheckIn.MonitorSlug)
+	assert.Equal(int64(5), events[1].MonitorConfig.CheckInMargin)
+	assert.Equal(int64(30), events[1].MonitorConfig.MaxRuntime)
+	assert.Equal(sdk.IntervalSchedule(2, sdk.MonitorScheduleUnitHour), events[1].MonitorConfig.Schedule)
+
+	// Failed execution
+	tr.reset()
+	mon = newMonitor(hub, "cleanup", &MonitorOptions{Schedule: "*/10 * * * *"})
+	assert.NotNil(mon.Run(context.Background(), func(_ context.Context) error {
+		return errors.New("failed")
+	}))
+	events = tr.list()
+	assert.Len(events, 2, "check-ins")
+	assert.Equal(sdk.CheckInStatusError, events[1].CheckIn.Status)
+	assert.Equal(sdk.CrontabSchedule("*/10 * * * *"), events[1].MonitorConfig.Schedule)
+
+	// Panics are reported as failures
+	tr.reset()
+	assert.Panics(func() {
+		_ = mon.Run(context.Background(), func(_ context.Context) error { panic("boom") })
+	})
+	events = tr.list()
+	assert.Len(events, 2, "check-ins")
+	assert.Equal(sdk.CheckInStatusError, events[1].CheckIn.Status)
+
+	// Manual check-ins
+	tr.reset()
+	ci := newMonitor(hub, "manual", nil).Start()
+	ci.Finish(nil)
+	events = tr.list()
+	assert.Len(events, 2, "check-ins")
+	assert.Nil(events[1].MonitorConfig, "no monitor config")
+
+	// Interval units
+	assert.Equal(sdk.IntervalSchedule(1, sdk.MonitorScheduleUnitWeek), intervalSchedule(7*24*time.Hour))
+	assert.Equal(sdk.IntervalSchedule(3, sdk.MonitorScheduleUnitDay), intervalSchedule(72*time.Hour))
+	assert.Equal(sdk.IntervalSchedule(90, sdk.MonitorScheduleUnitMinute), intervalSchedule(90*time.Minute))
+	assert.Equal(sdk.IntervalSchedule(1, sdk.MonitorScheduleUnitMinute), intervalSchedule(10*time.Second))
+}
+
+type testTransport struct {
+	mu     sync.Mutex
+	events []*sdk.Event
+}
+
+func (tt *testTransport) Flush(_ time.Duration) bool { return true }
+
+func (tt *testTransport) Configure(_ sdk.ClientOptions) {}
+
+func (tt *testTransport) SendEvent(event *sdk.Event) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.events = append(tt.events, event)
+}
+
+func (tt *testTransport) list() []*sdk.Event {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	return append([]*sdk.Event{}, tt.events...)
+}
+
+func (tt *testTransport) reset() {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.events = nil
+}
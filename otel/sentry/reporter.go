@@ -5,6 +5,7 @@ import (
 	"time"
 
 	sdk "github.com/getsentry/sentry-go"
+	"go.bryk.io/pkg/errors"
 	"go.opentelemetry.io/otel/propagation"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 )
@@ -24,9 +25,10 @@ import (
 // More information:
 // https://docs.sentry.io/platforms/go/performance/instrumentation/opentelemetry
 type Reporter struct {
-	hub    *sdk.Hub
-	client *sdk.Client
-	opts   *Options
+	hub      *sdk.Hub
+	client   *sdk.Client
+	opts     *Options
+	sessions *sessionAggregator
 }
 
 // Options defines the configuration settings for the Sentry reporter.
@@ -55,7 +57,8 @@ type Options struct {
 
 	// The sample rate for profiling traces in the range [0.0, 1.0].
 	// Relative to `TracesSampleRate`; i.e., it is a ratio of profiled
-	// traces out of all sampled traces.
+	// traces out of all sampled traces. Profiles are collected for
+	// transactions and require `EnablePerformanceMonitoring`.
 	ProfilingSampleRate float64 `mapstructure:"profiling_sample_rate" yaml:"profiling_sample_rate" json:"profiling_sample_rate"` // nolint: lll
 
 	// The maximum time to wait for events to be sent before shutdown.
@@ -63,10 +66,24 @@ type Options struct {
 
 	// Maximum number of events per-span to keep. Defaults to 100.
 	MaxEvents int `mapstructure:"max_events" yaml:"max_events" json:"max_events"`
+
+	// Report release health data. Every request handled (server or consumer
+	// root spans) is counted as a session; sessions for spans with an error
+	// status are marked as "errored". Requires a `Release` value.
+	ReleaseHealth bool `mapstructure:"release_health" yaml:"release_health" json:"release_health"`
 }
 
 // NewReporter returns a new Sentry reporter instance.
 func NewReporter(opts *Options) (*Reporter, error) {
+	if opts.TracesSampleRate < 0 || opts.TracesSampleRate > 1 {
+		return nil, errors.New("traces sample rate must be in the range [0.0, 1.0]")
+	}
+	if opts.ProfilingSampleRate < 0 || opts.ProfilingSampleRate > 1 {
+		return nil, errors.New("profiling sample rate must be in the range [0.0, 1.0]")
+	}
+	if opts.ProfilingSampleRate > 0 && !opts.EnablePerformanceMonitoring {
+		return nil, errors.New("profiling requires performance monitoring to be enabled")
+	}
 	err := sdk.Init(sdk.ClientOptions{
 		Dsn:                opts.DSN,
 		Debug:              false,
@@ -98,11 +115,17 @@ func NewReporter(opts *Options) (*Reporter, error) {
 	if opts.FlushTimeout == 0 {
 		opts.FlushTimeout = 2 * time.Second // default flush timeout
 	}
-	return &Reporter{
+	rep := &Reporter{
 		hub:    sdk.CurrentHub(),
 		client: sdk.CurrentHub().Client(),
 		opts:   opts,
-	}, nil
+	}
+	if opts.ReleaseHealth && opts.DSN != "" {
+		if rep.sessions, err = newSessionAggregator(opts.DSN, opts.Release, opts.Environment); err != nil {
+			return nil, err
+		}
+	}
+	return rep, nil
 }
 
 // Context returns a new context instance with the current Sentry
@@ -122,5 +145,5 @@ func (sr *Reporter) Propagator() propagation.TextMapPropagator {
 // SpanProcessor handles the link between OpenTelemetry spans and
 // Sentry transactions.
 func (sr *Reporter) SpanProcessor() sdkTrace.SpanProcessor {
-	return newSentrySpanProcessor(sr.hub, sr.opts.FlushTimeout, sr.opts.MaxEvents)
+	return newSentrySpanProcessor(sr.hub, sr.opts.FlushTimeout, sr.opts.MaxEvents, sr.sessions)
 }
@@ -0,0 +1,173 @@
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	sdk "github.com/getsentry/sentry-go"
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Interval used to submit aggregated sessions.
+const sessionsFlushInterval = time.Minute
+
+// Release health for long-running services is reported using "request mode"
+// sessions; every request (server or consumer root span) handled is counted
+// as a session. Sessions are aggregated per minute and submitted periodically.
+//
+// https://develop.sentry.dev/sdk/telemetry/sessions
+type sessionAggregator struct {
+	mu      sync.Mutex
+	buckets map[time.Time]*sessionBucket
+	attrs   sessionAttrs
+	dsn     *sdk.Dsn
+	client  *http.Client
+	halt    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+type sessionBucket struct {
+	Started time.Time `json:"started"`
+	Exited  int       `json:"exited,omitempty"`
+	Errored int       `json:"errored,omitempty"`
+}
+
+type sessionAttrs struct {
+	Release     string `json:"release"`
+	Environment string `json:"environment,omitempty"`
+}
+
+func newSessionAggregator(dsn, release, env string) (*sessionAggregator, error) {
+	if release == "" {
+		return nil, errors.New("release health requires a release identifier")
+	}
+	target, err := sdk.NewDsn(dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid DSN")
+	}
+	sa := &sessionAggregator{
+		buckets: make(map[time.Time]*sessionBucket),
+		attrs:   sessionAttrs{Release: release, Environment: env},
+		dsn:     target,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		halt:    make(chan struct{}),
+	}
+	sa.wg.Add(1)
+	go sa.loop()
+	return sa, nil
+}
+
+// Register a new session for the provided root span, if applicable.
+func (sa *sessionAggregator) track(s sdkTrace.ReadOnlySpan) {
+	if kind := s.SpanKind(); kind != trace.SpanKindServer && kind != trace.SpanKindConsumer {
+		return
+	}
+	sa.record(s.StartTime(), s.Status().Code == codes.Error)
+}
+
+func (sa *sessionAggregator) record(started time.Time, errored bool) {
+	key := started.UTC().Truncate(time.Minute)
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	bucket, ok := sa.buckets[key]
+	if !ok {
+		bucket = &sessionBucket{Started: key}
+		sa.buckets[key] = bucket
+	}
+	if errored {
+		bucket.Errored++
+	} else {
+		bucket.Exited++
+	}
+}
+
+// Submit all aggregated sessions.
+func (sa *sessionAggregator) flush(ctx context.Context) error {
+	sa.mu.Lock()
+	if len(sa.buckets) == 0 {
+		sa.mu.Unlock()
+		return nil
+	}
+	aggregates := make([]*sessionBucket, 0, len(sa.buckets))
+	for _, bucket := range sa.buckets {
+		aggregates = append(aggregates, bucket)
+	}
+	sa.buckets = make(map[time.Time]*sessionBucket)
+	sa.mu.Unlock()
+
+	// build envelope
+	payload, err := json.Marshal(map[string]interface{}{
+		"aggregates": aggregates,
+		"attrs":      sa.attrs,
+	})
+	if err != nil {
+		return err
+	}
+	header, _ := json.Marshal(map[string]interface{}{
+		"sent_at": time.Now().UTC(),
+		"dsn":     sa.dsn.String(),
+	})
+	body := new(bytes.Buffer)
+	body.Write(header)
+	body.WriteString("\n{\"type\":\"sessions\"}\n")
+	body.Write(payload)
+	body.WriteString("\n")
+
+	// submit request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sa.dsn.GetAPIURL().String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=go.bryk.io/pkg, sentry_key=%s",
+		sa.dsn.GetPublicKey()))
+	res, err := sa.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Stop the background processing and submit pending sessions.
+func (sa *sessionAggregator) close(ctx context.Context) error {
+	sa.once.Do(func() {
+		close(sa.halt)
+	})
+	sa.wg.Wait()
+	return sa.flush(ctx)
+}
+
+func (sa *sessionAggregator) loop() {
+	defer sa.wg.Done()
+	ticker := time.NewTicker(sessionsFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sa.halt:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), sessionsFlushInterval)
+			if err := sa.flush(ctx); err != nil {
+				sdk.Logger.Printf("failed to submit sessions: %s", err)
+			}
+			cancel()
+		}
+	}
+}
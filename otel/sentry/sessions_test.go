@@ -0,0 +1,82 @@
+package sentry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSessions(t *testing.T) {
+	assert := tdd.New(t)
+	envelopes := make(chan []string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/api/1/envelope/", r.URL.Path)
+		assert.Contains(r.Header.Get("X-Sentry-Auth"), "sentry_key=public")
+		var lines []string
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		envelopes <- lines
+	}))
+	defer srv.Close()
+	dsn := strings.Replace(srv.URL, "http://", "http://public@", 1) + "/1"
+
+	_, err := newSessionAggregator(dsn, "", "")
+	assert.NotNil(err, "release is required")
+	sa, err := newSessionAggregator(dsn, "app@0.1.0", "testing")
+	assert.Nil(err, "new aggregator")
+
+	// Only server and consumer spans are counted
+	start := time.Date(2024, 5, 1, 10, 30, 15, 0, time.UTC)
+	spans := []sdkTrace.ReadOnlySpan{
+		tracetest.SpanStub{SpanKind: trace.SpanKindServer, StartTime: start}.Snapshot(),
+		tracetest.SpanStub{SpanKind: trace.SpanKindServer, StartTime: start.Add(10 * time.Second)}.Snapshot(),
+		tracetest.SpanStub{
+			SpanKind:  trace.SpanKindConsumer,
+			StartTime: start.Add(20 * time.Second),
+			Status:    sdkTrace.Status{Code: codes.Error},
+		}.Snapshot(),
+		tracetest.SpanStub{SpanKind: trace.SpanKindClient, StartTime: start}.Snapshot(),
+		tracetest.SpanStub{SpanKind: trace.SpanKindServer, StartTime: start.Add(time.Minute)}.Snapshot(),
+	}
+	for _, s := range spans {
+		sa.track(s)
+	}
+	assert.Nil(sa.close(context.Background()), "close")
+
+	// Verify envelope
+	lines := <-envelopes
+	assert.Len(lines, 3)
+	assert.JSONEq(`{"type":"sessions"}`, lines[1])
+	payload := struct {
+		Aggregates []sessionBucket `json:"aggregates"`
+		Attrs      sessionAttrs    `json:"attrs"`
+	}{}
+	assert.Nil(json.Unmarshal([]byte(lines[2]), &payload))
+	assert.Equal("app@0.1.0", payload.Attrs.Release)
+	assert.Equal("testing", payload.Attrs.Environment)
+	assert.Len(payload.Aggregates, 2, "one bucket per minute")
+	counts := map[string]sessionBucket{}
+	for _, b := range payload.Aggregates {
+		counts[b.Started.Format("15:04")] = b
+	}
+	assert.Equal(2, counts["10:30"].Exited)
+	assert.Equal(1, counts["10:30"].Errored)
+	assert.Equal(1, counts["10:31"].Exited)
+
+	// Nothing left to submit
+	assert.Nil(sa.flush(context.Background()))
+	assert.Len(envelopes, 0)
+}
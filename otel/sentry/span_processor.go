@@ -24,6 +24,7 @@ type sentrySpanProcessor struct {
 	flushTimeout time.Duration
 	maxEvents    int
 	errCodec     errors.Codec
+	sessions     *sessionAggregator
 	mu           sync.Mutex
 }
 
@@ -31,7 +32,7 @@ type sentrySpanProcessor struct {
 // At the moment we do not support multiple instances.
 var sentrySpanProcessorInstance *sentrySpanProcessor
 
-func newSentrySpanProcessor(hub *sdk.Hub, ft time.Duration, maxEvents int) sdkTrace.SpanProcessor {
+func newSentrySpanProcessor(hub *sdk.Hub, ft time.Duration, maxEvents int, sessions *sessionAggregator) sdkTrace.SpanProcessor {
 	if sentrySpanProcessorInstance != nil {
 		return sentrySpanProcessorInstance
 	}
@@ -40,6 +41,7 @@ func newSentrySpanProcessor(hub *sdk.Hub, ft time.Duration, maxEvents int) sdkTr
 		hub:          hub,
 		flushTimeout: ft,
 		maxEvents:    maxEvents,
+		sessions:     sessions,
 		errCodec:     errors.CodecJSON(false), // ! make this configurable
 	}
 	return sentrySpanProcessorInstance
@@ -135,6 +137,11 @@ func (ssp *sentrySpanProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
 		}
 	}
 
+	// release health
+	if sentrySpan.IsTransaction() && ssp.sessions != nil {
+		ssp.sessions.track(s)
+	}
+
 	// capture span
 	sentrySpan.Status = getStatus(s)
 	sentrySpan.EndTime = s.EndTime()
@@ -146,11 +153,21 @@ func (ssp *sentrySpanProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
 func (ssp *sentrySpanProcessor) Shutdown(ctx context.Context) error {
 	// ~ per the spec: "shutdown MUST include the effects of ForceFlush"
 	sentrySpanMap.Clear()
+	if ssp.sessions != nil {
+		if err := ssp.sessions.close(ctx); err != nil {
+			return err
+		}
+	}
 	return ssp.ForceFlush(ctx)
 }
 
 // https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/trace/sdk.md#forceflush-1
-func (ssp *sentrySpanProcessor) ForceFlush(_ context.Context) error {
+func (ssp *sentrySpanProcessor) ForceFlush(ctx context.Context) error {
+	if ssp.sessions != nil {
+		if err := ssp.sessions.flush(ctx); err != nil {
+			return err
+		}
+	}
 	return flushSpanProcessor(ssp.hub, ssp.flushTimeout)
 }
 
@@ -0,0 +1,27 @@
+/*
+Package sql provides an OpenTelemetry instrumentation for applications
+using the standard `database/sql` package directly.
+
+Instrumented connections produce a span for every operation performed,
+including the executed statement. By default statements are sanitized to
+replace literal values (strings and numbers) with a `?` placeholder, to
+avoid leaking sensitive information into the telemetry data.
+
+	db, err := otelSql.Open("postgres", dsn,
+		otelSql.WithDBSystem("postgresql"),
+		otelSql.WithDBName("users"),
+	)
+
+Connection pool statistics (open, idle and in-use connections, wait times,
+etc.) are reported as metrics for `*sql.DB` instances returned by `Open`
+and `OpenDB`. Use `ReportDBStats` when wrapping drivers manually.
+
+	// register an instrumented version of an existing driver
+	driverName, _ := otelSql.Register("postgres")
+	db, _ := sql.Open(driverName, dsn)
+	_ = otelSql.ReportDBStats(db)
+
+More information:
+https://github.com/XSAM/otelsql
+*/
+package sql
@@ -0,0 +1,136 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+
+	"github.com/XSAM/otelsql"
+	"go.bryk.io/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	semConv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+// list of common errors that are ignored by default.
+var commonErrors = []error{
+	driver.ErrSkip, // skip operation
+	io.EOF,         // end of rows iterator
+}
+
+// Open provides a wrapper around the standard `sql.Open` function. The
+// returned `*sql.DB` instance produces spans for every operation performed
+// and reports connection pool metrics.
+//
+//	db, err := otelSql.Open("postgres", dsn, otelSql.WithDBSystem("postgresql"))
+func Open(driverName, dataSourceName string, opts ...Option) (*sql.DB, error) {
+	cfg := newConfig(opts...)
+	db, err := otelsql.Open(driverName, dataSourceName, cfg.options()...)
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.reportStats(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// OpenDB provides a wrapper around the standard `sql.OpenDB` function.
+// The returned `*sql.DB` instance produces spans for every operation
+// performed and reports connection pool metrics.
+func OpenDB(connector driver.Connector, opts ...Option) (*sql.DB, error) {
+	cfg := newConfig(opts...)
+	db := otelsql.OpenDB(connector, cfg.options()...)
+	if err := cfg.reportStats(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// WrapDriver returns an instrumented version of the provided driver.
+// Connection pool metrics are not reported automatically when wrapping
+// drivers, use `ReportDBStats` for the `*sql.DB` instances created.
+func WrapDriver(dri driver.Driver, opts ...Option) driver.Driver {
+	return otelsql.WrapDriver(dri, newConfig(opts...).options()...)
+}
+
+// Register an instrumented version of an existing driver. The returned
+// name can be used with the standard `sql.Open` function. Connection
+// pool metrics are not reported automatically for registered drivers,
+// use `ReportDBStats` for the `*sql.DB` instances created.
+//
+//	driverName, _ := otelSql.Register("postgres")
+//	db, _ := sql.Open(driverName, dsn)
+func Register(driverName string, opts ...Option) (string, error) {
+	return otelsql.Register(driverName, newConfig(opts...).options()...)
+}
+
+// ReportDBStats enables reporting connection pool statistics for the
+// provided `*sql.DB` instance as metrics.
+func ReportDBStats(db *sql.DB, opts ...Option) error {
+	cfg := newConfig(opts...)
+	cfg.excludeMetrics = false
+	return cfg.reportStats(db)
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		ignoredErrors:  append([]error{}, commonErrors...),
+		queryFormatter: Sanitize,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func (c *config) reportStats(db *sql.DB) error {
+	if c.excludeMetrics {
+		return nil
+	}
+	var opts []otelsql.Option
+	opts = append(opts, otelsql.WithAttributes(c.attrs...))
+	if c.meterProvider != nil {
+		opts = append(opts, otelsql.WithMeterProvider(c.meterProvider))
+	}
+	return errors.Wrap(otelsql.RegisterDBStatsMetrics(db, opts...), "failed to report DB stats")
+}
+
+// Settings used by the underlying instrumentation library.
+func (c *config) options() []otelsql.Option {
+	opts := []otelsql.Option{
+		otelsql.WithAttributes(c.attrs...),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			Ping:           c.ping,
+			DisableErrSkip: true,
+			DisableQuery:   true, // statements are formatted before being reported
+			RecordError:    c.recordError,
+		}),
+		otelsql.WithAttributesGetter(c.statement),
+	}
+	if c.tracerProvider != nil {
+		opts = append(opts, otelsql.WithTracerProvider(c.tracerProvider))
+	}
+	if c.meterProvider != nil {
+		opts = append(opts, otelsql.WithMeterProvider(c.meterProvider))
+	}
+	return opts
+}
+
+// Formatted db.statement attribute.
+func (c *config) statement(_ context.Context, _ otelsql.Method, query string, _ []driver.NamedValue) []attribute.KeyValue {
+	if c.excludeQuery || query == "" {
+		return nil
+	}
+	if c.queryFormatter != nil {
+		query = c.queryFormatter(query)
+	}
+	return []attribute.KeyValue{semConv.DBStatementKey.String(query)}
+}
+
+// Determine if an error should be recorded on the active span.
+func (c *config) recordError(err error) bool {
+	return !errors.IsAny(err, c.ignoredErrors...)
+}
@@ -0,0 +1,103 @@
+package sql
+
+import (
+	"go.bryk.io/pkg/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semConv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option defines a function that configures the instrumentation behavior.
+type Option func(c *config)
+
+// WithAttributes register additional attributes that will be used
+// when creating spans and reporting metrics.
+func WithAttributes(attrs map[string]interface{}) Option {
+	return func(c *config) {
+		kv := otel.Attributes(attrs)
+		c.attrs = append(c.attrs, kv.Expand()...)
+	}
+}
+
+// WithDBSystem configures a db.system attribute. For example: "postgresql",
+// "mysql" or "sqlite".
+func WithDBSystem(name string) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, semConv.DBSystemKey.String(name))
+	}
+}
+
+// WithDBName configures a db.name attribute.
+func WithDBName(name string) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, semConv.DBNameKey.String(name))
+	}
+}
+
+// WithQueryFormatter configures the function used to process statements
+// before being added to spans as a db.statement attribute. By default
+// statements are processed using `Sanitize`.
+func WithQueryFormatter(queryFormatter func(query string) string) Option {
+	return func(c *config) {
+		c.queryFormatter = queryFormatter
+	}
+}
+
+// WithoutQuery prevents the db.statement attribute from being included
+// in spans.
+func WithoutQuery() Option {
+	return func(c *config) {
+		c.excludeQuery = true
+	}
+}
+
+// WithoutMetrics prevents DBStats metrics from being reported.
+func WithoutMetrics() Option {
+	return func(c *config) {
+		c.excludeMetrics = true
+	}
+}
+
+// WithPing enables the creation of spans for Ping requests.
+func WithPing() Option {
+	return func(c *config) {
+		c.ping = true
+	}
+}
+
+// WithIgnoredError registered errors that should be ignored when reporting
+// spans. This is useful to avoid unnecessary reports for common errors like
+// "no rows".
+func WithIgnoredError(errors ...error) Option {
+	return func(c *config) {
+		c.ignoredErrors = append(c.ignoredErrors, errors...)
+	}
+}
+
+// WithTracerProvider sets the trace provider used. If not provided, the
+// global provider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider used. If not provided, the
+// global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+type config struct {
+	attrs          []attribute.KeyValue
+	ignoredErrors  []error
+	queryFormatter func(query string) string
+	excludeQuery   bool
+	excludeMetrics bool
+	ping           bool
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
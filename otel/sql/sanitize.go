@@ -0,0 +1,110 @@
+package sql
+
+import "strings"
+
+// Sanitize replaces literal values in the provided SQL statement with a
+// `?` placeholder. Quoted strings and numeric values are replaced, while
+// identifiers, keywords, comments and existing placeholders (like `?` or
+// `$1`) are preserved.
+//
+//	SELECT * FROM users WHERE email = 'rick@c137.com' AND age > 70
+//	SELECT * FROM users WHERE email = ? AND age > ?
+func Sanitize(query string) string {
+	var (
+		sb  strings.Builder
+		src = []rune(query)
+		n   = len(src)
+	)
+	sb.Grow(len(query))
+	for i := 0; i < n; i++ {
+		r := src[i]
+		switch {
+		// string literals; quotes are escaped by doubling them
+		case r == '\'':
+			for i++; i < n; i++ {
+				if src[i] == '\\' {
+					i++
+					continue
+				}
+				if src[i] == '\'' {
+					if i+1 < n && src[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			sb.WriteRune('?')
+
+		// quoted identifiers are preserved
+		case r == '"' || r == '`':
+			j := i + 1
+			for j < n && src[j] != r {
+				j++
+			}
+			if j == n {
+				j--
+			}
+			sb.WriteString(string(src[i : j+1]))
+			i = j
+
+		// comments are preserved
+		case r == '-' && i+1 < n && src[i+1] == '-':
+			j := i
+			for j < n && src[j] != '\n' {
+				j++
+			}
+			sb.WriteString(string(src[i:j]))
+			i = j - 1
+		case r == '/' && i+1 < n && src[i+1] == '*':
+			j := i + 2
+			for j+1 < n && !(src[j] == '*' && src[j+1] == '/') {
+				j++
+			}
+			j = min(j+1, n-1)
+			sb.WriteString(string(src[i : j+1]))
+			i = j
+
+		// numeric literals, including a leading sign when not
+		// used as an operator
+		case isDigit(r) || (r == '.' && i+1 < n && isDigit(src[i+1])) ||
+			(r == '-' && i+1 < n && isDigit(src[i+1]) && !operand(src[:i])):
+			if i > 0 && (isIdent(src[i-1]) || src[i-1] == '$') {
+				// part of an identifier or positional placeholder
+				sb.WriteRune(r)
+				continue
+			}
+			j := i + 1
+			for j < n && (isIdent(src[j]) || src[j] == '.') {
+				j++
+			}
+			sb.WriteRune('?')
+			i = j - 1
+
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Reports whether the last non-space character in `prev` completes an
+// operand; in which case a following `-` is a subtraction operator.
+func operand(prev []rune) bool {
+	for i := len(prev) - 1; i >= 0; i-- {
+		r := prev[i]
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			continue
+		}
+		return isIdent(r) || r == ')' || r == '?' || r == '\'' || r == '"' || r == '`'
+	}
+	return false
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || isDigit(r) || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,158 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSanitize(t *testing.T) {
+	assert := tdd.New(t)
+	tests := map[string]string{
+		"SELECT * FROM users WHERE email = 'rick@c137.com' AND age > 70":  "SELECT * FROM users WHERE email = ? AND age > ?",
+		"INSERT INTO t1 (a, b) VALUES ('it''s', -3.5), ($1, $2)":          "INSERT INTO t1 (a, b) VALUES (?, ?), ($1, $2)",
+		`SELECT "col1", ` + "`col2`" + ` FROM t WHERE id IN (1, 2, 0x1F)`: `SELECT "col1", ` + "`col2`" + ` FROM t WHERE id IN (?, ?, ?)`,
+		"UPDATE t SET a = a-1 WHERE b = ? -- keep 'comment' 1":            "UPDATE t SET a = a-? WHERE b = ? -- keep 'comment' 1",
+		"SELECT /* hint 42 */ 1e3, .5, 'unterminated":                     "SELECT /* hint 42 */ ?, ?, ?",
+	}
+	for query, expected := range tests {
+		assert.Equal(expected, Sanitize(query))
+	}
+}
+
+func TestOpenDB(t *testing.T) {
+	assert := tdd.New(t)
+	spans := tracetest.NewSpanRecorder()
+	tp := sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(spans))
+	reader := sdkMetric.NewManualReader()
+	mp := sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader))
+
+	db, err := OpenDB(&fakeConnector{},
+		WithDBSystem("fake"),
+		WithTracerProvider(tp),
+		WithMeterProvider(mp),
+		WithIgnoredError(errIgnored))
+	assert.Nil(err, "open db")
+	defer func() {
+		_ = db.Close()
+	}()
+
+	// Execute operations
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, "INSERT INTO users (name) VALUES ('rick')")
+	assert.Nil(err, "exec")
+	_, err = db.ExecContext(ctx, "DELETE FROM users WHERE id = 1")
+	assert.NotNil(err, "exec with error")
+	_, err = db.ExecContext(ctx, "ignored error")
+	assert.NotNil(err, "exec with ignored error")
+
+	// Spans include the sanitized statement
+	var statements []string
+	for _, span := range spans.Ended() {
+		attrs := attribute.NewSet(span.Attributes()...)
+		if sys, ok := attrs.Value("db.system"); ok {
+			assert.Equal("fake", sys.AsString(), "db.system attribute")
+		}
+		st, ok := attrs.Value("db.statement")
+		if !ok {
+			continue
+		}
+		statements = append(statements, st.AsString())
+		switch st.AsString() {
+		case "DELETE FROM users WHERE id = ?":
+			assert.Len(span.Events(), 1, "error recorded")
+		case "ignored error":
+			assert.Len(span.Events(), 0, "error ignored")
+		}
+	}
+	assert.Contains(statements, "INSERT INTO users (name) VALUES (?)")
+	assert.Contains(statements, "DELETE FROM users WHERE id = ?")
+
+	// Connection pool metrics
+	rm := metricdata.ResourceMetrics{}
+	assert.Nil(reader.Collect(ctx, &rm), "collect metrics")
+	var names []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names = append(names, m.Name)
+		}
+	}
+	assert.Contains(names, "db.sql.connection.open", "pool metrics")
+
+	// Query without statements
+	spans = tracetest.NewSpanRecorder()
+	tp = sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(spans))
+	db2, err := OpenDB(&fakeConnector{}, WithTracerProvider(tp), WithoutQuery(), WithoutMetrics())
+	assert.Nil(err, "open db")
+	_, err = db2.ExecContext(ctx, "INSERT INTO users (name) VALUES ('rick')")
+	assert.Nil(err, "exec")
+	assert.NotEmpty(spans.Ended(), "spans")
+	for _, span := range spans.Ended() {
+		attrs := attribute.NewSet(span.Attributes()...)
+		assert.False(attrs.HasValue("db.statement"), "no statement")
+	}
+	_ = db2.Close()
+}
+
+var errIgnored = errors.New("ignored error")
+
+// Minimal driver implementation used for testing.
+type fakeConnector struct{}
+
+func (fc *fakeConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+func (fc *fakeConnector) Driver() driver.Driver {
+	return &fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fd *fakeDriver) Open(_ string) (driver.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fc *fakeConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (fc *fakeConn) Close() error {
+	return nil
+}
+
+func (fc *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (fc *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	switch query {
+	case "ignored error":
+		return nil, errIgnored
+	case "DELETE FROM users WHERE id = 1":
+		return nil, errors.New("permission denied")
+	default:
+		return driver.RowsAffected(1), nil
+	}
+}
+
+func (fc *fakeConn) QueryContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fr *fakeRows) Columns() []string           { return []string{} }
+func (fr *fakeRows) Close() error                { return nil }
+func (fr *fakeRows) Next(_ []driver.Value) error { return io.EOF }
@@ -0,0 +1,182 @@
+package vc
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// CredentialsContext is the base JSON-LD context required on all
+	// credentials.
+	// https://www.w3.org/TR/vc-data-model/#contexts
+	CredentialsContext = "https://www.w3.org/2018/credentials/v1"
+
+	// CredentialType is the base type required on all credentials.
+	CredentialType = "VerifiableCredential"
+)
+
+const (
+	// ErrInvalidCredential is returned when a credential is not structurally
+	// valid; for example when required properties are missing.
+	ErrInvalidCredential = "invalid credential"
+	// ErrInvalidProof is returned when a credential's proof is missing or
+	// can't be verified.
+	ErrInvalidProof = "invalid proof"
+	// ErrNotYetValid is returned when the issuance date of a credential is
+	// in the future.
+	ErrNotYetValid = "credential not yet valid"
+	// ErrExpired is returned when the expiration date of a credential is in
+	// the past.
+	ErrExpired = "credential expired"
+	// ErrRevoked is returned when the status of a credential indicates it was
+	// revoked by its issuer.
+	ErrRevoked = "credential revoked"
+	// ErrSuspended is returned when the status of a credential indicates it
+	// is temporarily suspended by its issuer.
+	ErrSuspended = "credential suspended"
+	// ErrInvalidSchema is returned when a credential doesn't conform to one of
+	// its declared schemas.
+	ErrInvalidSchema = "credential doesn't conform to its schema"
+)
+
+// Credential is a set of one or more claims made by an issuer about a
+// subject. A verifiable credential is a tamper-evident credential whose
+// authorship can be cryptographically verified.
+// https://www.w3.org/TR/vc-data-model/
+type Credential struct {
+	// JSON-LD context statement for the credential. The first value must
+	// be 'CredentialsContext'.
+	Context []interface{} `json:"@context"`
+
+	// Unique identifier for the credential, optional.
+	ID string `json:"id,omitempty"`
+
+	// Credential types; must include 'CredentialType'.
+	Type []string `json:"type"`
+
+	// DID of the entity issuing the credential.
+	Issuer string `json:"issuer"`
+
+	// Date and time, in the RFC-3339 format, when the credential becomes
+	// valid.
+	IssuanceDate string `json:"issuanceDate"`
+
+	// Date and time, in the RFC-3339 format, when the credential ceases to
+	// be valid, optional.
+	ExpirationDate string `json:"expirationDate,omitempty"`
+
+	// Claims about the subject(s) of the credential. The "id" claim, if
+	// present, identifies the subject.
+	Subject map[string]interface{} `json:"credentialSubject"`
+
+	// Mechanism to discover the current status of the credential; for
+	// example, to check if it was revoked.
+	Status *Status `json:"credentialStatus,omitempty"`
+
+	// Data schemas used to verify the structure and contents of the
+	// credential.
+	Schema []Schema `json:"credentialSchema,omitempty"`
+
+	// Data integrity proof, if the credential is not secured as a JWT.
+	Proof *Proof `json:"proof,omitempty"`
+}
+
+// Status provides information about the current status of a credential.
+// Entries on a status list are supported by default.
+// https://www.w3.org/TR/vc-status-list/#statuslist2021entry
+type Status struct {
+	// Identifier for the status entry.
+	ID string `json:"id,omitempty"`
+
+	// Status mechanism, for example "StatusList2021Entry".
+	Type string `json:"type"`
+
+	// Purpose of the status entry: "revocation" or "suspension".
+	Purpose string `json:"statusPurpose,omitempty"`
+
+	// Position of the credential on the status list.
+	ListIndex string `json:"statusListIndex,omitempty"`
+
+	// URL of the credential containing the status list.
+	ListCredential string `json:"statusListCredential,omitempty"`
+}
+
+// Schema provides a reference to a data schema used to verify the
+// structure of a credential.
+// https://www.w3.org/TR/vc-data-model/#data-schemas
+type Schema struct {
+	// Location of the schema.
+	ID string `json:"id"`
+
+	// Schema mechanism, for example "JsonSchemaValidator2018".
+	Type string `json:"type"`
+}
+
+// SubjectID returns the identifier of the credential's subject, if any.
+func (c *Credential) SubjectID() string {
+	id, _ := c.Subject["id"].(string)
+	return id
+}
+
+// HasType returns true if `t` is one of the credential types.
+func (c *Credential) HasType(t string) bool {
+	for _, v := range c.Type {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Issued returns the issuance date of the credential.
+func (c *Credential) Issued() (time.Time, error) {
+	return time.Parse(time.RFC3339, c.IssuanceDate)
+}
+
+// Expires returns the expiration date of the credential, if any. A zero
+// value is returned for credentials with no expiration date.
+func (c *Credential) Expires() (time.Time, error) {
+	if c.ExpirationDate == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, c.ExpirationDate)
+}
+
+// Validate the credential is structurally valid. The proof is not
+// verified.
+func (c *Credential) Validate() error {
+	if len(c.Context) == 0 || c.Context[0] != CredentialsContext {
+		return errors.Errorf("%s: first context must be '%s'", ErrInvalidCredential, CredentialsContext)
+	}
+	if !c.HasType(CredentialType) {
+		return errors.Errorf("%s: type must include '%s'", ErrInvalidCredential, CredentialType)
+	}
+	if c.Issuer == "" {
+		return errors.Errorf("%s: missing issuer", ErrInvalidCredential)
+	}
+	if len(c.Subject) == 0 {
+		return errors.Errorf("%s: missing subject", ErrInvalidCredential)
+	}
+	if _, err := c.Issued(); err != nil {
+		return errors.Errorf("%s: invalid issuance date", ErrInvalidCredential)
+	}
+	if _, err := c.Expires(); err != nil {
+		return errors.Errorf("%s: invalid expiration date", ErrInvalidCredential)
+	}
+	return nil
+}
+
+// Returns a deep copy of the credential.
+func (c *Credential) clone() (*Credential, error) {
+	js, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	cp := new(Credential)
+	if err = json.Unmarshal(js, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
@@ -0,0 +1,112 @@
+/*
+Package vc provides issuance and verification of W3C Verifiable Credentials.
+
+A credential is a set of one or more claims made by an issuer about a subject. A
+verifiable credential is a tamper-evident credential whose authorship can be
+cryptographically verified. Credentials are issued using a verification method,
+registered as an "assertionMethod", from the issuer's DID.
+
+	id, _ := did.NewKeyIdentifier(did.KeyTypeEd)
+	issuer, _ := vc.NewIssuer(id, id.VerificationMethods()[0].ID)
+
+	cred := &vc.Credential{
+		Type: []string{"UniversityDegreeCredential"},
+		Subject: map[string]interface{}{
+			"id":     "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"degree": "Bachelor of Science and Arts",
+		},
+	}
+
+# Securing Mechanisms
+
+Credentials can be secured using a data integrity proof attached to the
+credential, or as a JWT including the credential in its "vc" claim.
+
+	// Data integrity proof
+	signed, _ := issuer.Issue(cred)
+
+	// JWT
+	token, _ := issuer.IssueJWT(cred)
+
+Data integrity proofs use the JSON Canonicalization Scheme (JCS), so no
+JSON-LD processing is required. Ed25519 keys produce "eddsa-jcs-2022" proofs
+and P-256 keys produce "ecdsa-jcs-2019" proofs. JWTs can also be produced
+using secp256k1 (ES256K) and RSA (RS256) keys.
+
+# Verification
+
+A verifier resolves the issuer's DID to retrieve the verification method used
+to secure the credential; only "did:key" identifiers are supported by default,
+use a resolver instance to enable additional methods.
+
+	verifier, _ := vc.NewVerifier(vc.WithResolver(resolverInstance))
+	err := verifier.Verify(signed)
+	cred, err := verifier.VerifyJWT(token)
+
+In addition to the proof, the validity period of the credential is verified.
+Status and schema checks are enabled by providing a status checker and a
+schema validator.
+
+	checker := vc.NewStatusListChecker(vc.StatusListOptions{})
+	schemas, _ := vc.NewJSONSchemaValidator(map[string][]byte{
+		"https://example.com/schemas/degree.json": degreeSchema,
+	})
+	verifier, _ := vc.NewVerifier(
+		vc.WithStatusChecker(checker),
+		vc.WithSchemaValidator(schemas),
+	)
+
+# Status Lists
+
+Issuers can revoke or suspend credentials by publishing a status list
+credential. Each credential includes a "credentialStatus" entry pointing to
+its position on the list.
+
+	list := vc.NewStatusList(0)
+	_ = list.Set(94567, true)
+	encoded, _ := list.Encode() // "encodedList" value
+
+# Presentations
+
+Holders share credentials with verifiers using presentations. The presentation
+is secured using an "authentication" verification method of the holder and is
+bound to the challenge and domain values provided by the verifier, to prevent
+replay attacks.
+
+	holder, _ := vc.NewHolder(holderID, holderKeyID)
+	p := new(vc.Presentation)
+	_ = p.AddCredential(signed)
+	_ = p.AddCredentialJWT(token)
+	vp, _ := holder.Present(p, challenge, "verifier.com")
+
+	// Verify the presentation and all its credentials
+	creds, err := verifier.VerifyPresentation(vp, challenge, "verifier.com")
+
+# Selective Disclosure
+
+Credentials secured as SD-JWT allow holders to reveal only some of the
+subject claims. Selective disclosure is not supported by the data integrity
+cryptosuites available.
+
+	sd, _ := issuer.IssueSDJWT(cred, "degree", "name")
+	partial, _ := vc.Disclose(sd, "degree") // "name" is withheld
+	_ = p.AddCredentialJWT(partial)
+
+# Presentation Exchange
+
+Verifiers can describe the credentials they require using a presentation
+definition. Holders use the definition to select the credentials to present,
+and verifiers to evaluate the submission.
+
+	// Holder
+	_ = definition.Submit(p)
+	vp, _ := holder.Present(p, challenge, "verifier.com")
+
+	// Verifier
+	creds, _ := verifier.VerifyPresentation(vp, challenge, "verifier.com")
+	err := definition.Evaluate(vp, creds)
+
+More information:
+https://www.w3.org/TR/vc-data-model/
+*/
+package vc
@@ -0,0 +1,318 @@
+package vc
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.bryk.io/pkg/errors"
+)
+
+// Credential formats used on presentation submissions.
+// https://identity.foundation/claim-format-registry/
+const (
+	// FormatLDP is used for credentials secured with a data integrity proof.
+	FormatLDP = "ldp_vc"
+
+	// FormatJWT is used for credentials secured as a JWT.
+	FormatJWT = "jwt_vc"
+
+	// FormatSDJWT is used for credentials secured as an SD-JWT.
+	FormatSDJWT = "vc+sd-jwt"
+)
+
+// ErrSubmission is returned when a presentation doesn't satisfy the
+// requirements of a presentation definition.
+const ErrSubmission = "invalid presentation submission"
+
+// PresentationDefinition describes the proofs a verifier requires from a
+// holder, as specified by the DIF "Presentation Exchange" specification.
+// https://identity.foundation/presentation-exchange/spec/v2.0.0/
+type PresentationDefinition struct {
+	// Unique identifier for the definition.
+	ID string `json:"id"`
+
+	// Human-friendly name for the definition, optional.
+	Name string `json:"name,omitempty"`
+
+	// Purpose for which the definition's inputs are requested, optional.
+	Purpose string `json:"purpose,omitempty"`
+
+	// Credentials required by the verifier; each descriptor must be
+	// satisfied by one of the credentials presented.
+	InputDescriptors []InputDescriptor `json:"input_descriptors"`
+}
+
+// InputDescriptor describes a credential required by a verifier.
+type InputDescriptor struct {
+	// Unique identifier for the descriptor.
+	ID string `json:"id"`
+
+	// Human-friendly name for the descriptor, optional.
+	Name string `json:"name,omitempty"`
+
+	// Purpose for which the credential is requested, optional.
+	Purpose string `json:"purpose,omitempty"`
+
+	// Requirements the credential must satisfy.
+	Constraints *Constraints `json:"constraints,omitempty"`
+}
+
+// Constraints on the contents of a credential.
+type Constraints struct {
+	// When set to "required", the credential subject must include only the
+	// claims referenced by the fields; i.e., all other claims must be
+	// withheld using selective disclosure.
+	LimitDisclosure string `json:"limit_disclosure,omitempty"`
+
+	// Claims required on the credential.
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Field describes a claim required on a credential.
+type Field struct {
+	// Unique identifier for the field, optional.
+	ID string `json:"id,omitempty"`
+
+	// JSONPath expressions used to locate the claim on the credential;
+	// the first one producing a value is used. Expressions are evaluated
+	// on the credential data model, regardless of its securing mechanism.
+	Path []string `json:"path"`
+
+	// Purpose for which the claim is requested, optional.
+	Purpose string `json:"purpose,omitempty"`
+
+	// JSON Schema the claim value must conform to, optional. The subset of
+	// keywords supported by 'JSONSchemaValidator' is available.
+	Filter json.RawMessage `json:"filter,omitempty"`
+
+	// Optional fields don't need to be present on the credential.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// PresentationSubmission describes how the credentials on a presentation
+// satisfy the input descriptors of a presentation definition.
+type PresentationSubmission struct {
+	// Unique identifier for the submission.
+	ID string `json:"id"`
+
+	// Presentation definition the submission is for.
+	DefinitionID string `json:"definition_id"`
+
+	// Credentials used to satisfy each input descriptor.
+	DescriptorMap []Descriptor `json:"descriptor_map"`
+}
+
+// Descriptor links an input descriptor with a credential on a presentation.
+type Descriptor struct {
+	// Input descriptor identifier.
+	ID string `json:"id"`
+
+	// Format of the credential.
+	Format string `json:"format"`
+
+	// JSONPath expression, relative to the presentation, used to locate
+	// the credential; for example "$.verifiableCredential[0]".
+	Path string `json:"path"`
+}
+
+// Submit selects, from the credentials included in the presentation, the
+// ones satisfying each input descriptor and sets the submission on the
+// presentation. An error is returned if any input descriptor can't be
+// satisfied. Intended to be used by holders before securing the
+// presentation.
+func (pd *PresentationDefinition) Submit(p *Presentation) error {
+	creds, secured, err := p.credentials()
+	if err != nil {
+		return err
+	}
+	docs, err := toGeneric(creds)
+	if err != nil {
+		return err
+	}
+	sub := &PresentationSubmission{
+		ID:           uuid.NewString(),
+		DefinitionID: pd.ID,
+	}
+	for _, desc := range pd.InputDescriptors {
+		selected := -1
+		for i := range creds {
+			if desc.match(docs[i], creds[i]) == nil {
+				selected = i
+				break
+			}
+		}
+		if selected == -1 {
+			return errors.Errorf("%s: input descriptor '%s' can't be satisfied", ErrSubmission, desc.ID)
+		}
+		sub.DescriptorMap = append(sub.DescriptorMap, Descriptor{
+			ID:     desc.ID,
+			Format: credentialFormat(secured[selected]),
+			Path:   "$.verifiableCredential[" + strconv.Itoa(selected) + "]",
+		})
+	}
+	p.Submission = sub
+	return nil
+}
+
+// Evaluate the submission on a presentation against the definition;
+// `creds` must be the verified credentials included in the presentation,
+// as returned by 'Verifier.VerifyPresentation'. An error is returned if any
+// input descriptor is not satisfied.
+func (pd *PresentationDefinition) Evaluate(p *Presentation, creds []*Credential) error {
+	sub := p.Submission
+	if sub == nil || sub.DefinitionID != pd.ID {
+		return errors.Errorf("%s: missing submission for '%s'", ErrSubmission, pd.ID)
+	}
+	_, secured, err := p.credentials()
+	if err != nil {
+		return err
+	}
+	if len(secured) != len(creds) {
+		return errors.Errorf("%s: credentials mismatch", ErrSubmission)
+	}
+	docs, err := toGeneric(creds)
+	if err != nil {
+		return err
+	}
+	for _, desc := range pd.InputDescriptors {
+		entry := sub.descriptor(desc.ID)
+		if entry == nil {
+			return errors.Errorf("%s: input descriptor '%s' not submitted", ErrSubmission, desc.ID)
+		}
+		i := credentialIndex(entry.Path)
+		if i < 0 || i >= len(creds) {
+			return errors.Errorf("%s: invalid path for '%s'", ErrSubmission, desc.ID)
+		}
+		if entry.Format != credentialFormat(secured[i]) {
+			return errors.Errorf("%s: invalid format for '%s'", ErrSubmission, desc.ID)
+		}
+		if err = desc.match(docs[i], creds[i]); err != nil {
+			return errors.Errorf("%s: %s", ErrSubmission, err)
+		}
+	}
+	return nil
+}
+
+// Return the submission entry for an input descriptor.
+func (ps *PresentationSubmission) descriptor(id string) *Descriptor {
+	for i, d := range ps.DescriptorMap {
+		if d.ID == id {
+			return &ps.DescriptorMap[i]
+		}
+	}
+	return nil
+}
+
+// Verify a credential satisfies the descriptor constraints; `doc` is the
+// decoded JSON representation of the credential.
+func (desc InputDescriptor) match(doc interface{}, cred *Credential) error {
+	if desc.Constraints == nil {
+		return nil
+	}
+	referenced := map[string]bool{"id": true}
+	for _, f := range desc.Constraints.Fields {
+		for _, p := range f.Path {
+			if claim := subjectClaim(p); claim != "" {
+				referenced[claim] = true
+			}
+		}
+		if err := f.match(doc); err != nil {
+			return errors.Errorf("input descriptor '%s': %s", desc.ID, err)
+		}
+	}
+	if desc.Constraints.LimitDisclosure == "required" {
+		for claim := range cred.Subject {
+			if !referenced[claim] {
+				return errors.Errorf("input descriptor '%s': claim '%s' must not be disclosed", desc.ID, claim)
+			}
+		}
+	}
+	return nil
+}
+
+// Verify the field is present, and valid, on the credential.
+func (f Field) match(doc interface{}) error {
+	var filter *jsonSchema
+	if len(f.Filter) > 0 {
+		filter = new(jsonSchema)
+		if err := json.Unmarshal(f.Filter, filter); err != nil {
+			return errors.Wrap(err, "invalid filter")
+		}
+		if err := filter.compile(); err != nil {
+			return errors.Wrap(err, "invalid filter")
+		}
+	}
+	for _, p := range f.Path {
+		nodes, err := queryPath(doc, p)
+		if err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+		if filter == nil {
+			return nil
+		}
+		for _, n := range nodes {
+			if filter.validate(p, n) == nil {
+				return nil
+			}
+		}
+		break
+	}
+	if f.Optional {
+		return nil
+	}
+	return errors.Errorf("required field not satisfied: %s", strings.Join(f.Path, ", "))
+}
+
+// Return the subject claim referenced by a path, if any; for example
+// "$.credentialSubject.degree" references the "degree" claim.
+func subjectClaim(path string) string {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) < 2 {
+		return ""
+	}
+	if segments[0].name != "credentialSubject" || segments[1].isIndex || segments[1].wildcard {
+		return ""
+	}
+	return segments[1].name
+}
+
+// Return the format of a secured credential.
+func credentialFormat(secured interface{}) string {
+	token, ok := secured.(string)
+	switch {
+	case !ok:
+		return FormatLDP
+	case strings.Contains(token, "~"):
+		return FormatSDJWT
+	default:
+		return FormatJWT
+	}
+}
+
+// Return the decoded JSON representation of the credentials.
+func toGeneric(creds []*Credential) ([]interface{}, error) {
+	js, err := json.Marshal(creds)
+	if err != nil {
+		return nil, err
+	}
+	var docs []interface{}
+	return docs, json.Unmarshal(js, &docs)
+}
+
+// Return the position of the credential referenced by a descriptor path,
+// or -1 if the path is invalid.
+func credentialIndex(path string) int {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) != 2 {
+		return -1
+	}
+	if segments[0].name != "verifiableCredential" || !segments[1].isIndex {
+		return -1
+	}
+	return segments[1].index
+}
@@ -0,0 +1,109 @@
+package vc
+
+import (
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func TestQueryPath(t *testing.T) {
+	assert := tdd.New(t)
+	doc := map[string]interface{}{
+		"type": []interface{}{"VerifiableCredential", "UniversityDegreeCredential"},
+		"credentialSubject": map[string]interface{}{
+			"degree": map[string]interface{}{"type": "BachelorDegree"},
+		},
+	}
+	cases := map[string]int{
+		"$.type":                                1,
+		"$.type[1]":                             1,
+		"$.type[*]":                             2,
+		"$['credentialSubject']['degree'].type": 1,
+		"$.credentialSubject.degree.type":       1,
+		"$.credentialSubject.name":              0,
+		"$.type[5]":                             0,
+	}
+	for path, expected := range cases {
+		nodes, err := queryPath(doc, path)
+		assert.Nil(err, path)
+		assert.Len(nodes, expected, path)
+	}
+	for _, path := range []string{"type", "$..type", "$.type[x]", "$[unclosed"} {
+		_, err := queryPath(doc, path)
+		assert.NotNil(err, path)
+	}
+}
+
+func TestPresentationExchange(t *testing.T) {
+	assert := tdd.New(t)
+	is := newIssuer(t, did.KeyTypeEd)
+	holder := newHolder(t)
+	verifier, _ := NewVerifier()
+
+	pd := &PresentationDefinition{
+		ID: "degree-check",
+		InputDescriptors: []InputDescriptor{
+			{
+				ID: "degree",
+				Constraints: &Constraints{
+					LimitDisclosure: "required",
+					Fields: []Field{
+						{
+							Path:   []string{"$.type"},
+							Filter: []byte(`{"type": "array", "items": {"type": "string"}}`),
+						},
+						{
+							Path:   []string{"$.credentialSubject.degree"},
+							Filter: []byte(`{"type": "string", "pattern": "^Bachelor"}`),
+						},
+						{
+							Path:     []string{"$.credentialSubject.gpa"},
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Holder credentials
+	cred := sampleCredential()
+	cred.Subject["name"] = "Jane Doe"
+	ldp, err := is.Issue(cred)
+	assert.Nil(err, "issue")
+	sd, err := is.IssueSDJWT(cred, "degree", "name")
+	assert.Nil(err, "issue")
+
+	t.Run("NotSatisfied", func(t *testing.T) {
+		// All claims disclosed
+		p := new(Presentation)
+		assert.Nil(p.AddCredential(ldp))
+		assert.Nil(p.AddCredentialJWT(sd))
+		assert.NotNil(pd.Submit(p), "limit disclosure")
+	})
+
+	t.Run("Submit", func(t *testing.T) {
+		disclosed, err := Disclose(sd, "degree")
+		assert.Nil(err, "disclose")
+		p := new(Presentation)
+		assert.Nil(p.AddCredential(ldp))
+		assert.Nil(p.AddCredentialJWT(disclosed))
+		assert.Nil(pd.Submit(p), "submit")
+		assert.Equal("$.verifiableCredential[1]", p.Submission.DescriptorMap[0].Path)
+		assert.Equal(FormatSDJWT, p.Submission.DescriptorMap[0].Format)
+
+		// Verifier
+		vp, err := holder.Present(p, "challenge-value", "")
+		assert.Nil(err, "present")
+		creds, err := verifier.VerifyPresentation(vp, "challenge-value", "")
+		assert.Nil(err, "verify")
+		assert.Nil(pd.Evaluate(vp, creds), "evaluate")
+
+		// Submission pointing to the wrong credential
+		vp.Submission.DescriptorMap[0].Path = "$.verifiableCredential[0]"
+		assert.NotNil(pd.Evaluate(vp, creds), "invalid submission")
+		vp.Submission.DescriptorMap[0].Path = "$.verifiableCredential[3]"
+		assert.NotNil(pd.Evaluate(vp, creds), "invalid path")
+	})
+}
@@ -0,0 +1,102 @@
+package vc
+
+import (
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// Issuer instances produce verifiable credentials using a verification
+// method from a DID. The verification method must include its private key
+// and be registered as an "assertionMethod" on the DID.
+type Issuer struct {
+	id  *did.Identifier
+	key *did.VerificationKey
+}
+
+// NewIssuer returns a new issuer instance using the verification method
+// `keyID`, either a fragment or a full DID URL, from the `id` DID.
+func NewIssuer(id *did.Identifier, keyID string) (*Issuer, error) {
+	key := id.VerificationMethod(keyID)
+	if key == nil {
+		return nil, errors.New("invalid key identifier")
+	}
+	if len(key.Private) == 0 {
+		return nil, errors.New("no private key available")
+	}
+	if !hasReference(id.Document(true).AssertionMethod, key.ID) {
+		return nil, errors.Errorf("'%s' is not an assertion method", key.ID)
+	}
+	return &Issuer{id: id, key: key}, nil
+}
+
+// DID of the issuer.
+func (is *Issuer) DID() string {
+	return is.id.DID()
+}
+
+// Issue a new credential secured with a data integrity proof. Missing
+// base context, type, issuer and issuance date values are set
+// automatically. The original credential is not modified.
+func (is *Issuer) Issue(cred *Credential) (*Credential, error) {
+	vc, err := is.prepare(cred)
+	if err != nil {
+		return nil, err
+	}
+	vc.Proof, err = createProof(vc, vc.Context, is.key, Proof{Purpose: purposeAssertion})
+	if err != nil {
+		return nil, err
+	}
+	return vc, nil
+}
+
+// IssueJWT returns a new credential secured as a JWT. Missing base
+// context, type, issuer and issuance date values are set automatically.
+// https://www.w3.org/TR/vc-data-model/#json-web-token
+func (is *Issuer) IssueJWT(cred *Credential) (string, error) {
+	vc, err := is.prepare(cred)
+	if err != nil {
+		return "", err
+	}
+	claims, err := credentialClaims(vc)
+	if err != nil {
+		return "", err
+	}
+	return signJWT(claims, is.key, "JWT")
+}
+
+// Return a validated copy of the credential, with no proof and default
+// values set.
+func (is *Issuer) prepare(cred *Credential) (*Credential, error) {
+	vc, err := cred.clone()
+	if err != nil {
+		return nil, err
+	}
+	vc.Proof = nil
+	if len(vc.Context) == 0 || vc.Context[0] != CredentialsContext {
+		vc.Context = append([]interface{}{CredentialsContext}, vc.Context...)
+	}
+	if !vc.HasType(CredentialType) {
+		vc.Type = append([]string{CredentialType}, vc.Type...)
+	}
+	if vc.Issuer == "" {
+		vc.Issuer = is.id.DID()
+	}
+	if vc.Issuer != is.id.DID() {
+		return nil, errors.Errorf("%s: issuer mismatch", ErrInvalidCredential)
+	}
+	if vc.IssuanceDate == "" {
+		vc.IssuanceDate = time.Now().UTC().Format(time.RFC3339)
+	}
+	return vc, vc.Validate()
+}
+
+func hasReference(list []string, ref string) bool {
+	for _, v := range list {
+		if v == ref {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Produce the JSON Canonicalization Scheme (JCS) representation of `v`.
+// Object members are sorted by their UTF-16 code units, numbers use the
+// ECMAScript serialization of IEEE-754 double values and strings use the
+// minimal escaping required. No insignificant whitespace is included.
+// https://www.rfc-editor.org/rfc/rfc8785
+func canonicalize(v interface{}) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalJSON(js)
+}
+
+// Produce the JCS representation of the JSON document `js`.
+func canonicalJSON(js []byte) ([]byte, error) {
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := jcsValue(buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func jcsValue(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case string:
+		jcsString(buf, val)
+	case json.Number:
+		f, err := strconv.ParseFloat(val.String(), 64)
+		if err != nil {
+			return errors.Errorf("invalid number: %s", val)
+		}
+		num, err := jcsNumber(f)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(num)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, el := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := jcsValue(buf, el); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return utf16Less(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			jcsString(buf, k)
+			buf.WriteByte(':')
+			if err := jcsValue(buf, val[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return errors.Errorf("unsupported value type: %T", v)
+	}
+	return nil
+}
+
+// Serialize a string value; only the quotation mark, the reverse solidus
+// and control characters are escaped.
+func jcsString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xf])
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// Serialize a number using the ECMAScript `Number.prototype.toString`
+// algorithm, as required by JCS.
+// https://262.ecma-international.org/10.0/#sec-tostring-applied-to-the-number-type
+func jcsNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errors.New("invalid number: NaN and Infinity are not allowed")
+	}
+	if f == 0 {
+		return "0", nil // includes negative zero
+	}
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// Shortest decimal digits that round-trip, and the exponent `n` such
+	// that the value is `0.digits * 10^n`
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, err := strconv.Atoi(exp)
+	if err != nil {
+		return "", errors.Errorf("invalid number: %v", f)
+	}
+	k := len(digits)
+	n := e + 1
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k), nil
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:], nil
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits, nil
+	}
+	res := digits[:1]
+	if k > 1 {
+		res += "." + digits[1:]
+	}
+	if n-1 >= 0 {
+		return sign + res + "e+" + strconv.Itoa(n-1), nil
+	}
+	return sign + res + "e" + strconv.Itoa(n-1), nil
+}
+
+// Compare strings using their UTF-16 code units.
+func utf16Less(a, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
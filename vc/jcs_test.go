@@ -0,0 +1,96 @@
+package vc
+
+import (
+	"math"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+)
+
+// Test vectors from RFC-8785.
+// https://www.rfc-editor.org/rfc/rfc8785
+func TestCanonicalize(t *testing.T) {
+	assert := tdd.New(t)
+
+	t.Run("Numbers", func(t *testing.T) {
+		// Appendix B
+		vectors := map[uint64]string{
+			0x0000000000000000: "0",
+			0x8000000000000000: "0",
+			0x0000000000000001: "5e-324",
+			0x8000000000000001: "-5e-324",
+			0x7fefffffffffffff: "1.7976931348623157e+308",
+			0xffefffffffffffff: "-1.7976931348623157e+308",
+			0x4340000000000000: "9007199254740992",
+			0xc340000000000000: "-9007199254740992",
+			0x4430000000000000: "295147905179352830000",
+			0x44b52d02c7e14af5: "9.999999999999997e+22",
+			0x44b52d02c7e14af6: "1e+23",
+			0x44b52d02c7e14af7: "1.0000000000000001e+23",
+			0x444b1ae4d6e2ef4e: "999999999999999700000",
+			0x444b1ae4d6e2ef4f: "999999999999999900000",
+			0x444b1ae4d6e2ef50: "1e+21",
+			0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+			0x3eb0c6f7a0b5ed8d: "0.000001",
+			0x41b3de4355555553: "333333333.3333332",
+			0x41b3de4355555554: "333333333.33333325",
+			0x41b3de4355555555: "333333333.3333333",
+			0x41b3de4355555556: "333333333.3333334",
+			0x41b3de4355555557: "333333333.33333343",
+			0xbecbf647612f3696: "-0.0000033333333333333333",
+			0x43143ff3c1cb0959: "1424953923781206.2",
+		}
+		for bits, expected := range vectors {
+			res, err := jcsNumber(math.Float64frombits(bits))
+			assert.Nil(err)
+			assert.Equal(expected, res, "0x%016x", bits)
+		}
+		_, err := jcsNumber(math.NaN())
+		assert.NotNil(err, "NaN")
+		_, err = jcsNumber(math.Inf(1))
+		assert.NotNil(err, "Infinity")
+	})
+
+	t.Run("Sample", func(t *testing.T) {
+		// Section 3.2.2
+		input := `{
+			"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+			"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+			"literals": [null, true, false]
+		}`
+		expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+			`"string":"€$\u000f\nA'B\"\\\\\"/"}`
+		res, err := canonicalJSON([]byte(input))
+		assert.Nil(err)
+		assert.Equal(expected, string(res))
+	})
+
+	t.Run("Sorting", func(t *testing.T) {
+		// Section 3.2.3
+		input := `{
+			"€": "Euro Sign",
+			"\r": "Carriage Return",
+			"דּ": "Hebrew Letter Dalet With Dagesh",
+			"1": "One",
+			"😀": "Emoji: Grinning Face",
+			"\u0080": "Control",
+			"ö": "Latin Small Letter O With Diaeresis"
+		}`
+		expected := `{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control",` +
+			`"ö":"Latin Small Letter O With Diaeresis","€":"Euro Sign",` +
+			`"😀":"Emoji: Grinning Face","` + "דּ" + `":"Hebrew Letter Dalet With Dagesh"}`
+		res, err := canonicalJSON([]byte(input))
+		assert.Nil(err)
+		assert.Equal(expected, string(res))
+	})
+
+	t.Run("Strings", func(t *testing.T) {
+		// Only required characters are escaped
+		res, err := canonicalize(map[string]interface{}{"v": "<a&b>\u2028\u2029\u007f"})
+		assert.Nil(err)
+		assert.Equal("{\"v\":\"<a&b>\u2028\u2029\u007f\"}", string(res))
+		res, err = canonicalJSON([]byte(`{"v":1.0,"w":1e3}`))
+		assert.Nil(err)
+		assert.Equal(`{"v":1,"w":1000}`, string(res))
+	})
+}
@@ -0,0 +1,114 @@
+package vc
+
+import (
+	"strconv"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Evaluate a JSONPath expression against a decoded JSON document and return
+// all matching values. The following subset of the syntax is supported:
+//
+//	$                root element
+//	.name            object member
+//	['name']         object member, using bracket notation
+//	[0]              array element
+//	.* or [*]        all object members or array elements
+//
+// https://www.rfc-editor.org/rfc/rfc9535
+func queryPath(doc interface{}, path string) ([]interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []interface{}{doc}
+	for _, seg := range segments {
+		var next []interface{}
+		for _, n := range nodes {
+			next = append(next, seg.apply(n)...)
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// Single step on a JSONPath expression.
+type pathSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Return the values selected by the segment on node `n`.
+func (ps pathSegment) apply(n interface{}) []interface{} {
+	switch val := n.(type) {
+	case map[string]interface{}:
+		if ps.wildcard {
+			res := make([]interface{}, 0, len(val))
+			for _, v := range val {
+				res = append(res, v)
+			}
+			return res
+		}
+		if v, ok := val[ps.name]; ok && !ps.isIndex {
+			return []interface{}{v}
+		}
+	case []interface{}:
+		if ps.wildcard {
+			return val
+		}
+		if ps.isIndex && ps.index >= 0 && ps.index < len(val) {
+			return []interface{}{val[ps.index]}
+		}
+	}
+	return nil
+}
+
+// Split a JSONPath expression into its segments.
+func parsePath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.Errorf("invalid path: %s", path)
+	}
+	var segments []pathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, errors.Errorf("invalid path: %s", path)
+			}
+			segments = append(segments, pathSegment{name: name, wildcard: name == "*"})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, errors.Errorf("invalid path: %s", path)
+			}
+			sel := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case sel == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				segments = append(segments, pathSegment{name: sel[1 : len(sel)-1]})
+			default:
+				i, err := strconv.Atoi(sel)
+				if err != nil {
+					return nil, errors.Errorf("invalid path: %s", path)
+				}
+				segments = append(segments, pathSegment{index: i, isIndex: true})
+			}
+		default:
+			return nil, errors.Errorf("invalid path: %s", path)
+		}
+	}
+	return segments, nil
+}
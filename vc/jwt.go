@@ -0,0 +1,217 @@
+package vc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+// Base64 encoding used by JWT segments.
+var b64 = base64.RawURLEncoding
+
+// Header of JWT-secured credentials.
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+// Claims of JWT-secured credentials and presentations; the credential (or
+// presentation) is included in the "vc" (or "vp") claim and its properties
+// mapped to registered claims.
+// https://www.w3.org/TR/vc-data-model/#jwt-encoding
+type jwtClaims struct {
+	Issuer    string        `json:"iss"`
+	Subject   string        `json:"sub,omitempty"`
+	ID        string        `json:"jti,omitempty"`
+	Audience  string        `json:"aud,omitempty"`
+	Nonce     string        `json:"nonce,omitempty"`
+	NotBefore int64         `json:"nbf"`
+	Expires   int64         `json:"exp,omitempty"`
+	SDAlg     string        `json:"_sd_alg,omitempty"`
+	VC        *Credential   `json:"vc,omitempty"`
+	VP        *Presentation `json:"vp,omitempty"`
+}
+
+// Produce a compact JWS over `claims` using `key`; `typ` is used as the
+// media type of the token.
+func signJWT(claims *jwtClaims, key *did.VerificationKey, typ string) (string, error) {
+	alg, err := jwsAlg(key.Type)
+	if err != nil {
+		return "", err
+	}
+	hd, err := json.Marshal(jwtHeader{Algorithm: alg, KeyID: key.ID, Type: typ})
+	if err != nil {
+		return "", err
+	}
+	pl, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := b64.EncodeToString(hd) + "." + b64.EncodeToString(pl)
+	sig, err := jwsSign(key, []byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + b64.EncodeToString(sig), nil
+}
+
+// Decode a compact JWS without verifying its signature.
+func parseJWT(token string) (*jwtHeader, *jwtClaims, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, nil, errors.New("invalid JWT")
+	}
+	hd := new(jwtHeader)
+	if err := decodeSegment(segments[0], hd); err != nil {
+		return nil, nil, errors.New("invalid JWT header")
+	}
+	claims := new(jwtClaims)
+	if err := decodeSegment(segments[1], claims); err != nil {
+		return nil, nil, errors.New("invalid JWT claims")
+	}
+	return hd, claims, nil
+}
+
+// Verify the signature of a compact JWS using `key`.
+func verifyJWT(token string, hd *jwtHeader, key *did.VerificationKey) error {
+	alg, err := jwsAlg(key.Type)
+	if err != nil {
+		return err
+	}
+	if hd.Algorithm != alg {
+		return errors.Errorf("%s: invalid 'alg' header", ErrInvalidProof)
+	}
+	pos := strings.LastIndex(token, ".")
+	sig, err := b64.DecodeString(token[pos+1:])
+	if err != nil {
+		return errors.Errorf("%s: invalid signature segment", ErrInvalidProof)
+	}
+	if !jwsVerify(key, []byte(token[:pos]), sig) {
+		return errors.New(ErrInvalidProof)
+	}
+	return nil
+}
+
+// Return the JWT claims for a credential.
+func credentialClaims(cred *Credential) (*jwtClaims, error) {
+	issued, err := cred.Issued()
+	if err != nil {
+		return nil, errors.Errorf("%s: invalid issuance date", ErrInvalidCredential)
+	}
+	expires, err := cred.Expires()
+	if err != nil {
+		return nil, errors.Errorf("%s: invalid expiration date", ErrInvalidCredential)
+	}
+	claims := &jwtClaims{
+		Issuer:    cred.Issuer,
+		Subject:   cred.SubjectID(),
+		ID:        cred.ID,
+		NotBefore: issued.Unix(),
+		VC:        cred,
+	}
+	if !expires.IsZero() {
+		claims.Expires = expires.Unix()
+	}
+	return claims, nil
+}
+
+// Restore a credential from its JWT claims. Registered claims take
+// precedence over the values in the "vc" claim.
+func claimsCredential(claims *jwtClaims) (*Credential, error) {
+	cred := claims.VC
+	if cred == nil {
+		return nil, errors.Errorf("%s: missing 'vc' claim", ErrInvalidCredential)
+	}
+	if cred.Issuer != "" && cred.Issuer != claims.Issuer {
+		return nil, errors.Errorf("%s: issuer mismatch", ErrInvalidCredential)
+	}
+	cred.Issuer = claims.Issuer
+	if claims.ID != "" {
+		cred.ID = claims.ID
+	}
+	if claims.Subject != "" {
+		if cred.Subject == nil {
+			cred.Subject = map[string]interface{}{}
+		}
+		cred.Subject["id"] = claims.Subject
+	}
+	cred.IssuanceDate = time.Unix(claims.NotBefore, 0).UTC().Format(time.RFC3339)
+	if claims.Expires != 0 {
+		cred.ExpirationDate = time.Unix(claims.Expires, 0).UTC().Format(time.RFC3339)
+	}
+	return cred, nil
+}
+
+// Return the JWS algorithm used for signatures produced with keys of
+// type `kt`.
+func jwsAlg(kt did.KeyType) (string, error) {
+	switch kt {
+	case did.KeyTypeEd:
+		return "EdDSA", nil
+	case did.KeyTypeP256:
+		return "ES256", nil
+	case did.KeyTypeSecp256k1:
+		return "ES256K", nil
+	case did.KeyTypeRSA:
+		return "RS256", nil
+	default:
+		return "", errors.Errorf("unsupported key type for JWT: %s", kt)
+	}
+}
+
+// Produce a JWS signature. ECDSA signatures use the fixed-size "r | s"
+// representation required by RFC-7518.
+func jwsSign(key *did.VerificationKey, input []byte) ([]byte, error) {
+	switch key.Type {
+	case did.KeyTypeSecp256k1:
+		digest := sha256.Sum256(input)
+		sig, err := key.Sign(digest[:])
+		if err != nil {
+			return nil, err
+		}
+		return derToRaw(sig, 32)
+	case did.KeyTypeP256:
+		sig, err := key.Sign(input)
+		if err != nil {
+			return nil, err
+		}
+		return derToRaw(sig, 32)
+	default:
+		return key.Sign(input)
+	}
+}
+
+// Verify a JWS signature.
+func jwsVerify(key *did.VerificationKey, input, sig []byte) bool {
+	switch key.Type {
+	case did.KeyTypeSecp256k1:
+		der, err := rawToDER(sig)
+		if err != nil {
+			return false
+		}
+		digest := sha256.Sum256(input)
+		return key.Verify(digest[:], der)
+	case did.KeyTypeP256:
+		der, err := rawToDER(sig)
+		if err != nil {
+			return false
+		}
+		return key.Verify(input, der)
+	default:
+		return key.Verify(input, sig)
+	}
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	js, err := b64.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, v)
+}
@@ -0,0 +1,336 @@
+package vc
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// PresentationType is the base type required on all presentations.
+	PresentationType = "VerifiablePresentation"
+
+	// ErrInvalidPresentation is returned when a presentation is not
+	// structurally valid.
+	ErrInvalidPresentation = "invalid presentation"
+
+	// ErrInvalidBinding is returned when the challenge or domain of a
+	// presentation don't match the expected values.
+	ErrInvalidBinding = "invalid presentation binding"
+)
+
+// Proof purposes.
+const (
+	purposeAssertion      = "assertionMethod"
+	purposeAuthentication = "authentication"
+)
+
+// Presentation combines one or more credentials, possibly from different
+// issuers, to be shared with a verifier. The presentation is secured by its
+// holder, binding it to a specific challenge and domain to prevent replay
+// attacks.
+// https://www.w3.org/TR/vc-data-model/#presentations
+type Presentation struct {
+	// JSON-LD context statement for the presentation. The first value
+	// must be 'CredentialsContext'.
+	Context []interface{} `json:"@context"`
+
+	// Unique identifier for the presentation, optional.
+	ID string `json:"id,omitempty"`
+
+	// Presentation types; must include 'PresentationType'.
+	Type []string `json:"type"`
+
+	// DID of the entity producing the presentation.
+	Holder string `json:"holder,omitempty"`
+
+	// Credentials included in the presentation. Credentials secured with
+	// a data integrity proof are included as objects, JWT-secured ones as
+	// strings.
+	Credentials []json.RawMessage `json:"verifiableCredential,omitempty"`
+
+	// Presentation exchange submission, if the presentation is produced to
+	// satisfy a presentation definition.
+	Submission *PresentationSubmission `json:"presentation_submission,omitempty"`
+
+	// Data integrity proof, if the presentation is not secured as a JWT.
+	Proof *Proof `json:"proof,omitempty"`
+}
+
+// AddCredential includes a credential secured with a data integrity proof.
+func (p *Presentation) AddCredential(cred *Credential) error {
+	if cred.Proof == nil {
+		return errors.Errorf("%s: missing proof", ErrInvalidCredential)
+	}
+	js, err := json.Marshal(cred)
+	if err != nil {
+		return err
+	}
+	p.Credentials = append(p.Credentials, js)
+	return nil
+}
+
+// AddCredentialJWT includes a credential secured as a JWT or SD-JWT. For
+// SD-JWT credentials use 'Disclose' to select the claims to reveal.
+func (p *Presentation) AddCredentialJWT(token string) error {
+	if _, _, err := parseJWT(strings.Split(token, "~")[0]); err != nil {
+		return err
+	}
+	js, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	p.Credentials = append(p.Credentials, js)
+	return nil
+}
+
+// Validate the presentation is structurally valid. The proof is not
+// verified.
+func (p *Presentation) Validate() error {
+	if len(p.Context) == 0 || p.Context[0] != CredentialsContext {
+		return errors.Errorf("%s: first context must be '%s'", ErrInvalidPresentation, CredentialsContext)
+	}
+	if !hasReference(p.Type, PresentationType) {
+		return errors.Errorf("%s: type must include '%s'", ErrInvalidPresentation, PresentationType)
+	}
+	if p.Holder == "" {
+		return errors.Errorf("%s: missing holder", ErrInvalidPresentation)
+	}
+	return nil
+}
+
+// Returns a deep copy of the presentation.
+func (p *Presentation) clone() (*Presentation, error) {
+	js, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	cp := new(Presentation)
+	if err = json.Unmarshal(js, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Decode the credentials included in the presentation, without verifying
+// them. Returns the credentials and their secured representation; a
+// 'Credential' or a JWT string.
+func (p *Presentation) credentials() ([]*Credential, []interface{}, error) {
+	var (
+		list    []*Credential
+		secured []interface{}
+	)
+	for _, raw := range p.Credentials {
+		var token string
+		if err := json.Unmarshal(raw, &token); err == nil {
+			cred, err := decodeJWT(token)
+			if err != nil {
+				return nil, nil, err
+			}
+			list = append(list, cred)
+			secured = append(secured, token)
+			continue
+		}
+		cred := new(Credential)
+		if err := json.Unmarshal(raw, cred); err != nil {
+			return nil, nil, errors.Errorf("%s: invalid credential entry", ErrInvalidPresentation)
+		}
+		list = append(list, cred)
+		secured = append(secured, cred)
+	}
+	return list, secured, nil
+}
+
+// Holder instances produce presentations using a verification method from
+// a DID. The verification method must include its private key and be
+// registered as an "authentication" method on the DID.
+type Holder struct {
+	id  *did.Identifier
+	key *did.VerificationKey
+}
+
+// NewHolder returns a new holder instance using the verification method
+// `keyID`, either a fragment or a full DID URL, from the `id` DID.
+func NewHolder(id *did.Identifier, keyID string) (*Holder, error) {
+	key := id.VerificationMethod(keyID)
+	if key == nil {
+		return nil, errors.New("invalid key identifier")
+	}
+	if len(key.Private) == 0 {
+		return nil, errors.New("no private key available")
+	}
+	if !hasReference(id.Document(true).Authentication, key.ID) {
+		return nil, errors.Errorf("'%s' is not an authentication method", key.ID)
+	}
+	return &Holder{id: id, key: key}, nil
+}
+
+// DID of the holder.
+func (h *Holder) DID() string {
+	return h.id.DID()
+}
+
+// Present returns a copy of the presentation secured with a data integrity
+// proof bound to the `challenge` and `domain` values provided by the
+// verifier. Missing base context, type and holder values are set
+// automatically.
+func (h *Holder) Present(p *Presentation, challenge, domain string) (*Presentation, error) {
+	vp, err := h.prepare(p)
+	if err != nil {
+		return nil, err
+	}
+	vp.Proof, err = createProof(vp, vp.Context, h.key, Proof{
+		Purpose:   purposeAuthentication,
+		Challenge: challenge,
+		Domain:    domain,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vp, nil
+}
+
+// PresentJWT returns the presentation secured as a JWT; the `challenge`
+// and `domain` values are used as the "nonce" and "aud" claims.
+// https://www.w3.org/TR/vc-data-model/#jwt-encoding
+func (h *Holder) PresentJWT(p *Presentation, challenge, domain string) (string, error) {
+	vp, err := h.prepare(p)
+	if err != nil {
+		return "", err
+	}
+	claims := &jwtClaims{
+		Issuer:    vp.Holder,
+		ID:        vp.ID,
+		Audience:  domain,
+		Nonce:     challenge,
+		NotBefore: time.Now().Unix(),
+		VP:        vp,
+	}
+	return signJWT(claims, h.key, "JWT")
+}
+
+// Return a validated copy of the presentation, with no proof and default
+// values set.
+func (h *Holder) prepare(p *Presentation) (*Presentation, error) {
+	vp, err := p.clone()
+	if err != nil {
+		return nil, err
+	}
+	vp.Proof = nil
+	if len(vp.Context) == 0 || vp.Context[0] != CredentialsContext {
+		vp.Context = append([]interface{}{CredentialsContext}, vp.Context...)
+	}
+	if !hasReference(vp.Type, PresentationType) {
+		vp.Type = append([]string{PresentationType}, vp.Type...)
+	}
+	if vp.Holder == "" {
+		vp.Holder = h.id.DID()
+	}
+	if vp.Holder != h.id.DID() {
+		return nil, errors.Errorf("%s: holder mismatch", ErrInvalidPresentation)
+	}
+	return vp, vp.Validate()
+}
+
+// VerifyPresentation validates a presentation secured with a data integrity
+// proof, and all the credentials it includes. The proof must be bound to
+// the expected `challenge` and `domain` values. On success the verified
+// credentials are returned, in the same order they appear on the
+// presentation.
+func (v *Verifier) VerifyPresentation(p *Presentation, challenge, domain string) ([]*Credential, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if p.Proof == nil || p.Proof.Purpose != purposeAuthentication {
+		return nil, errors.Errorf("%s: missing authentication proof", ErrInvalidProof)
+	}
+	if p.Proof.Challenge != challenge || p.Proof.Domain != domain {
+		return nil, errors.New(ErrInvalidBinding)
+	}
+	key, err := v.key(p.Holder, p.Proof.VerificationMethod, did.AuthenticationVM)
+	if err != nil {
+		return nil, err
+	}
+	doc := *p
+	doc.Proof = nil
+	if err = verifyProof(&doc, doc.Context, key, p.Proof); err != nil {
+		return nil, err
+	}
+	return v.verifyCredentials(p)
+}
+
+// VerifyPresentationJWT validates a presentation secured as a JWT, and all
+// the credentials it includes. The "nonce" and "aud" claims must match the
+// expected `challenge` and `domain` values. On success the presentation and
+// the verified credentials are returned.
+func (v *Verifier) VerifyPresentationJWT(token, challenge, domain string) (*Presentation, []*Credential, error) {
+	hd, claims, err := parseJWT(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := claims.VP
+	if p == nil {
+		return nil, nil, errors.Errorf("%s: missing 'vp' claim", ErrInvalidPresentation)
+	}
+	if p.Holder != "" && p.Holder != claims.Issuer {
+		return nil, nil, errors.Errorf("%s: holder mismatch", ErrInvalidPresentation)
+	}
+	p.Holder = claims.Issuer
+	if err = p.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if claims.Nonce != challenge || claims.Audience != domain {
+		return nil, nil, errors.New(ErrInvalidBinding)
+	}
+	key, err := v.key(p.Holder, hd.KeyID, did.AuthenticationVM)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = verifyJWT(token, hd, key); err != nil {
+		return nil, nil, err
+	}
+	creds, err := v.verifyCredentials(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, creds, nil
+}
+
+// Verify all credentials included in a presentation.
+func (v *Verifier) verifyCredentials(p *Presentation) ([]*Credential, error) {
+	_, secured, err := p.credentials()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Credential, len(secured))
+	for i, el := range secured {
+		switch sc := el.(type) {
+		case string:
+			list[i], err = v.VerifyJWT(sc)
+		case *Credential:
+			list[i], err = sc, v.Verify(sc)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "credential %d", i)
+		}
+	}
+	return list, nil
+}
+
+// Decode a credential secured as a JWT, or SD-JWT, without verifying it.
+func decodeJWT(token string) (*Credential, error) {
+	jwt, disclosures, sd := splitSDJWT(token)
+	_, claims, err := parseJWT(jwt)
+	if err != nil {
+		return nil, err
+	}
+	if sd {
+		if err = applyDisclosures(claims, disclosures); err != nil {
+			return nil, err
+		}
+	}
+	return claimsCredential(claims)
+}
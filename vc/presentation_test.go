@@ -0,0 +1,129 @@
+package vc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func newHolder(t *testing.T) *Holder {
+	id, err := did.NewKeyIdentifier(did.KeyTypeEd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHolder(id, id.VerificationMethods()[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestPresentation(t *testing.T) {
+	assert := tdd.New(t)
+	is := newIssuer(t, did.KeyTypeEd)
+	holder := newHolder(t)
+	verifier, _ := NewVerifier()
+
+	// Holder credentials
+	ldp, err := is.Issue(sampleCredential())
+	assert.Nil(err, "issue")
+	jwt, err := is.IssueJWT(sampleCredential())
+	assert.Nil(err, "issue JWT")
+	p := new(Presentation)
+	assert.Nil(p.AddCredential(ldp))
+	assert.Nil(p.AddCredentialJWT(jwt))
+	assert.NotNil(p.AddCredential(sampleCredential()), "missing proof")
+	assert.NotNil(p.AddCredentialJWT("invalid-token"), "invalid token")
+
+	t.Run("DataIntegrity", func(t *testing.T) {
+		vp, err := holder.Present(p, "challenge-value", "verifier.com")
+		assert.Nil(err, "present")
+		assert.Equal(holder.DID(), vp.Holder)
+
+		// Round-trip
+		js, _ := json.Marshal(vp)
+		restored := new(Presentation)
+		assert.Nil(json.Unmarshal(js, restored))
+		creds, err := verifier.VerifyPresentation(restored, "challenge-value", "verifier.com")
+		assert.Nil(err, "verify")
+		assert.Len(creds, 2)
+
+		// Invalid binding
+		_, err = verifier.VerifyPresentation(restored, "other-challenge", "verifier.com")
+		assert.Equal(ErrInvalidBinding, err.Error())
+
+		// Tampered binding
+		restored.Proof.Challenge = "other-challenge"
+		_, err = verifier.VerifyPresentation(restored, "other-challenge", "verifier.com")
+		assert.NotNil(err, "tampered proof")
+	})
+
+	t.Run("JWT", func(t *testing.T) {
+		token, err := holder.PresentJWT(p, "challenge-value", "verifier.com")
+		assert.Nil(err, "present")
+		vp, creds, err := verifier.VerifyPresentationJWT(token, "challenge-value", "verifier.com")
+		assert.Nil(err, "verify")
+		assert.Equal(holder.DID(), vp.Holder)
+		assert.Len(creds, 2)
+		_, _, err = verifier.VerifyPresentationJWT(token, "challenge-value", "other.com")
+		assert.Equal(ErrInvalidBinding, err.Error())
+	})
+
+	t.Run("InvalidCredential", func(t *testing.T) {
+		tampered := *ldp
+		tampered.Subject = map[string]interface{}{"id": "did:example:123", "degree": "PhD"}
+		vp := new(Presentation)
+		assert.Nil(vp.AddCredential(&tampered))
+		vp, err := holder.Present(vp, "challenge-value", "")
+		assert.Nil(err, "present")
+		_, err = verifier.VerifyPresentation(vp, "challenge-value", "")
+		assert.NotNil(err, "invalid credential")
+	})
+}
+
+func TestSelectiveDisclosure(t *testing.T) {
+	assert := tdd.New(t)
+	is := newIssuer(t, did.KeyTypeEd)
+	verifier, _ := NewVerifier()
+
+	cred := sampleCredential()
+	cred.Subject["name"] = "Jane Doe"
+	cred.Subject["gpa"] = 3.8
+	token, err := is.IssueSDJWT(cred, "degree", "name", "gpa")
+	assert.Nil(err, "issue")
+	assert.Equal(4, strings.Count(token, "~"))
+	_, err = is.IssueSDJWT(cred, "unknown")
+	assert.NotNil(err, "invalid claim")
+
+	// All claims disclosed
+	vc, err := verifier.VerifyJWT(token)
+	assert.Nil(err, "verify")
+	assert.Equal("Jane Doe", vc.Subject["name"])
+	assert.Equal(3.8, vc.Subject["gpa"])
+
+	// Partial disclosure
+	partial, err := Disclose(token, "degree")
+	assert.Nil(err, "disclose")
+	vc, err = verifier.VerifyJWT(partial)
+	assert.Nil(err, "verify")
+	assert.Equal("Bachelor of Science and Arts", vc.Subject["degree"])
+	assert.Nil(vc.Subject["name"])
+	assert.Nil(vc.Subject["gpa"])
+	assert.Nil(vc.Subject["_sd"])
+
+	// Unknown or repeated disclosures
+	disc, _ := newDisclosure("degree", "PhD")
+	_, err = verifier.VerifyJWT(partial + disc + "~")
+	assert.NotNil(err, "unknown disclosure")
+	parts := strings.Split(partial, "~")
+	_, err = verifier.VerifyJWT(partial + parts[1] + "~")
+	assert.NotNil(err, "repeated disclosure")
+
+	// Not supported for other mechanisms
+	plain, _ := is.IssueJWT(cred)
+	_, err = Disclose(plain, "degree")
+	assert.NotNil(err, "not an SD-JWT")
+}
@@ -0,0 +1,189 @@
+package vc
+
+import (
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// ProofType is the type used by all data integrity proofs.
+	// https://www.w3.org/TR/vc-data-integrity/
+	ProofType = "DataIntegrityProof"
+
+	// SuiteEdDSA is the cryptosuite used for proofs produced with
+	// Ed25519 keys.
+	// https://www.w3.org/TR/vc-di-eddsa/#eddsa-jcs-2022
+	SuiteEdDSA = "eddsa-jcs-2022"
+
+	// SuiteECDSA is the cryptosuite used for proofs produced with
+	// P-256 keys.
+	// https://www.w3.org/TR/vc-di-ecdsa/#ecdsa-jcs-2019
+	SuiteECDSA = "ecdsa-jcs-2019"
+)
+
+// Proof is a data integrity proof; it allows to verify the authenticity
+// and integrity of a document.
+// https://www.w3.org/TR/vc-data-integrity/#proofs
+type Proof struct {
+	// JSON-LD context, only set when producing the proof configuration.
+	Context []interface{} `json:"@context,omitempty"`
+
+	// Proof type, must be 'ProofType'.
+	Type string `json:"type"`
+
+	// Cryptographic suite used to produce the proof.
+	Cryptosuite string `json:"cryptosuite"`
+
+	// Creation timestamp in the RFC-3339 format.
+	Created string `json:"created,omitempty"`
+
+	// Verification method used to produce the proof.
+	VerificationMethod string `json:"verificationMethod"`
+
+	// The specific intent for the proof; for example "assertionMethod"
+	// or "authentication".
+	Purpose string `json:"proofPurpose"`
+
+	// Random or pseudo-random value used to mitigate replay attacks.
+	Challenge string `json:"challenge,omitempty"`
+
+	// Operational domain the proof is restricted to.
+	Domain string `json:"domain,omitempty"`
+
+	// Multibase-encoded proof value.
+	Value string `json:"proofValue,omitempty"`
+}
+
+// Secure `doc` with a data integrity proof. The proof is returned and must
+// be attached to the document by the caller.
+// https://www.w3.org/TR/vc-data-integrity/#add-proof
+func createProof(doc interface{}, ctx []interface{}, key *did.VerificationKey, opts Proof) (*Proof, error) {
+	suite, err := cryptosuite(key.Type)
+	if err != nil {
+		return nil, err
+	}
+	proof := opts
+	proof.Type = ProofType
+	proof.Cryptosuite = suite
+	proof.VerificationMethod = key.ID
+	proof.Created = time.Now().UTC().Format(time.RFC3339)
+	input, err := proofInput(doc, ctx, &proof)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to produce proof")
+	}
+	if key.Type == did.KeyTypeP256 {
+		if sig, err = derToRaw(sig, 32); err != nil {
+			return nil, err
+		}
+	}
+	proof.Value = "z" + base58.Encode(sig)
+	return &proof, nil
+}
+
+// Verify the data integrity proof produced for `doc`.
+// https://www.w3.org/TR/vc-data-integrity/#verify-proof
+func verifyProof(doc interface{}, ctx []interface{}, key *did.VerificationKey, proof *Proof) error {
+	suite, err := cryptosuite(key.Type)
+	if err != nil {
+		return err
+	}
+	if proof.Type != ProofType || proof.Cryptosuite != suite {
+		return errors.Errorf("%s: unsupported proof type", ErrInvalidProof)
+	}
+	if len(proof.Value) < 2 || proof.Value[0] != 'z' {
+		return errors.Errorf("%s: invalid proof value", ErrInvalidProof)
+	}
+	sig, err := base58.Decode(proof.Value[1:])
+	if err != nil {
+		return errors.Errorf("%s: invalid proof value", ErrInvalidProof)
+	}
+	if key.Type == did.KeyTypeP256 {
+		if sig, err = rawToDER(sig); err != nil {
+			return errors.Errorf("%s: invalid proof value", ErrInvalidProof)
+		}
+	}
+	cfg := *proof
+	cfg.Value = ""
+	input, err := proofInput(doc, ctx, &cfg)
+	if err != nil {
+		return err
+	}
+	if !key.Verify(input, sig) {
+		return errors.New(ErrInvalidProof)
+	}
+	return nil
+}
+
+// Return the input value for proofs over `doc`. Both the document and the
+// proof configuration are canonicalized using JCS.
+//
+//	input = sha256(proof_config) | sha256(document)
+func proofInput(doc interface{}, ctx []interface{}, cfg *Proof) ([]byte, error) {
+	cfg.Context = ctx
+	defer func() {
+		cfg.Context = nil
+	}()
+	pc, err := canonicalize(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := canonicalize(doc)
+	if err != nil {
+		return nil, err
+	}
+	ph := sha256.Sum256(pc)
+	dh := sha256.Sum256(dc)
+	return append(ph[:], dh[:]...), nil
+}
+
+// Return the cryptosuite used for proofs produced with keys of type `kt`.
+func cryptosuite(kt did.KeyType) (string, error) {
+	switch kt {
+	case did.KeyTypeEd:
+		return SuiteEdDSA, nil
+	case did.KeyTypeP256:
+		return SuiteECDSA, nil
+	default:
+		return "", errors.Errorf("unsupported key type for data integrity proofs: %s", kt)
+	}
+}
+
+// ASN.1 structure of ECDSA signatures.
+type ecSignature struct {
+	R, S *big.Int
+}
+
+// Convert an ASN.1 encoded ECDSA signature to its fixed-size "r | s"
+// representation.
+func derToRaw(der []byte, size int) ([]byte, error) {
+	sig := new(ecSignature)
+	if _, err := asn1.Unmarshal(der, sig); err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+	raw := make([]byte, 2*size)
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+	return raw, nil
+}
+
+// Convert a fixed-size "r | s" ECDSA signature to its ASN.1 encoding.
+func rawToDER(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, errors.New("invalid signature size")
+	}
+	size := len(raw) / 2
+	return asn1.Marshal(ecSignature{
+		R: new(big.Int).SetBytes(raw[:size]),
+		S: new(big.Int).SetBytes(raw[size:]),
+	})
+}
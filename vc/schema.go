@@ -0,0 +1,252 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// SchemaValidator instances verify credentials conform to their declared
+// data schemas.
+type SchemaValidator interface {
+	// Validate returns an error if the credential doesn't conform to the
+	// referenced schema.
+	Validate(schema Schema, cred *Credential) error
+}
+
+// JSONSchemaValidator validates credentials using locally registered JSON
+// schemas; remote schemas are never retrieved. The following subset of the
+// JSON Schema vocabulary is supported: "type", "properties", "required",
+// "additionalProperties" (boolean), "items", "enum", "const", "minimum",
+// "maximum", "minLength", "maxLength", "pattern", "minItems" and "maxItems".
+// https://json-schema.org/draft/2020-12/json-schema-validation
+type JSONSchemaValidator struct {
+	schemas map[string]*jsonSchema
+}
+
+// Supported schema keywords.
+type jsonSchema struct {
+	Type                 interface{}            `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Const                interface{}            `json:"const,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// NewJSONSchemaValidator returns a validator instance for the provided
+// schemas; indexed by their identifier, i.e., the "id" value used on
+// "credentialSchema" entries.
+func NewJSONSchemaValidator(schemas map[string][]byte) (*JSONSchemaValidator, error) {
+	sv := &JSONSchemaValidator{schemas: make(map[string]*jsonSchema)}
+	for id, src := range schemas {
+		js := new(jsonSchema)
+		if err := json.Unmarshal(src, js); err != nil {
+			return nil, errors.Wrapf(err, "invalid schema '%s'", id)
+		}
+		if err := js.compile(); err != nil {
+			return nil, errors.Wrapf(err, "invalid schema '%s'", id)
+		}
+		sv.schemas[id] = js
+	}
+	return sv, nil
+}
+
+// Validate the credential against the referenced schema. Unknown schemas
+// are rejected.
+func (sv *JSONSchemaValidator) Validate(schema Schema, cred *Credential) error {
+	js, ok := sv.schemas[schema.ID]
+	if !ok {
+		return errors.Errorf("unknown schema: %s", schema.ID)
+	}
+
+	// Validate the JSON representation of the credential
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if err = js.validate("$", doc); err != nil {
+		return errors.Errorf("%s: %s", ErrInvalidSchema, err)
+	}
+	return nil
+}
+
+// Compile regular expressions on the schema.
+func (js *jsonSchema) compile() (err error) {
+	if js.Pattern != "" {
+		if js.pattern, err = regexp.Compile(js.Pattern); err != nil {
+			return err
+		}
+	}
+	for _, p := range js.Properties {
+		if err = p.compile(); err != nil {
+			return err
+		}
+	}
+	if js.Items != nil {
+		return js.Items.compile()
+	}
+	return nil
+}
+
+// Validate `v` against the schema; `path` is used to report the location
+// of the first violation found.
+func (js *jsonSchema) validate(path string, v interface{}) error {
+	if js.Type != nil && !js.matchType(v) {
+		return fmt.Errorf("%s: invalid type, expected %v", path, js.Type)
+	}
+	if js.Const != nil && !equal(js.Const, v) {
+		return fmt.Errorf("%s: invalid value", path)
+	}
+	if len(js.Enum) > 0 {
+		found := false
+		for _, e := range js.Enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value not allowed", path)
+		}
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return js.validateObject(path, val)
+	case []interface{}:
+		return js.validateArray(path, val)
+	case string:
+		size := len([]rune(val))
+		if js.MinLength != nil && size < *js.MinLength {
+			return fmt.Errorf("%s: value too short", path)
+		}
+		if js.MaxLength != nil && size > *js.MaxLength {
+			return fmt.Errorf("%s: value too long", path)
+		}
+		if js.pattern != nil && !js.pattern.MatchString(val) {
+			return fmt.Errorf("%s: value doesn't match pattern", path)
+		}
+	case float64:
+		if js.Minimum != nil && val < *js.Minimum {
+			return fmt.Errorf("%s: value below minimum", path)
+		}
+		if js.Maximum != nil && val > *js.Maximum {
+			return fmt.Errorf("%s: value above maximum", path)
+		}
+	}
+	return nil
+}
+
+func (js *jsonSchema) validateObject(path string, obj map[string]interface{}) error {
+	for _, k := range js.Required {
+		if _, ok := obj[k]; !ok {
+			return fmt.Errorf("%s: missing required property '%s'", path, k)
+		}
+	}
+	for k, val := range obj {
+		ps, ok := js.Properties[k]
+		if !ok {
+			if js.AdditionalProperties != nil && !*js.AdditionalProperties {
+				return fmt.Errorf("%s: additional property '%s' not allowed", path, k)
+			}
+			continue
+		}
+		if err := ps.validate(path+"."+k, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (js *jsonSchema) validateArray(path string, list []interface{}) error {
+	if js.MinItems != nil && len(list) < *js.MinItems {
+		return fmt.Errorf("%s: not enough items", path)
+	}
+	if js.MaxItems != nil && len(list) > *js.MaxItems {
+		return fmt.Errorf("%s: too many items", path)
+	}
+	if js.Items == nil {
+		return nil
+	}
+	for i, el := range list {
+		if err := js.Items.validate(fmt.Sprintf("%s[%d]", path, i), el); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check the JSON type of `v`; "type" can be a single value or a list.
+func (js *jsonSchema) matchType(v interface{}) bool {
+	var types []string
+	switch t := js.Type.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, el := range t {
+			if s, ok := el.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	for _, t := range types {
+		if jsonType(v) == t || (t == "number" && jsonType(v) == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the JSON type name for a decoded value.
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return strings.ToLower(fmt.Sprintf("%T", v))
+	}
+}
+
+// Compare decoded JSON values.
+func equal(a, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
@@ -0,0 +1,165 @@
+package vc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// Hash algorithm used to produce disclosure digests.
+const sdAlg = "sha-256"
+
+// IssueSDJWT returns a new credential secured as an SD-JWT; the subject
+// claims listed in `disclosable` are replaced by salted digests and
+// provided as separate disclosures, allowing the holder to reveal only
+// some of them when presenting the credential. Only top-level subject
+// claims, other than "id", can be disclosable.
+// https://datatracker.ietf.org/doc/draft-ietf-oauth-selective-disclosure-jwt/
+func (is *Issuer) IssueSDJWT(cred *Credential, disclosable ...string) (string, error) {
+	vc, err := is.prepare(cred)
+	if err != nil {
+		return "", err
+	}
+	var (
+		digests     []string
+		disclosures []string
+	)
+	for _, name := range disclosable {
+		value, ok := vc.Subject[name]
+		if !ok || name == "id" {
+			return "", errors.Errorf("invalid disclosable claim: %s", name)
+		}
+		disc, err := newDisclosure(name, value)
+		if err != nil {
+			return "", err
+		}
+		delete(vc.Subject, name)
+		digests = append(digests, disclosureDigest(disc))
+		disclosures = append(disclosures, disc)
+	}
+	sort.Strings(digests)
+	vc.Subject["_sd"] = digests
+	claims, err := credentialClaims(vc)
+	if err != nil {
+		return "", err
+	}
+	claims.SDAlg = sdAlg
+	token, err := signJWT(claims, is.key, "vc+sd-jwt")
+	if err != nil {
+		return "", err
+	}
+	return token + "~" + strings.Join(append(disclosures, ""), "~"), nil
+}
+
+// Disclose returns a copy of the SD-JWT `token` including only the
+// disclosures for the claims listed in `claims`; all other disclosable
+// claims are withheld from the verifier.
+func Disclose(token string, claims ...string) (string, error) {
+	jwt, disclosures, ok := splitSDJWT(token)
+	if !ok {
+		return "", errors.New("selective disclosure is only supported for SD-JWT credentials")
+	}
+	keep := []string{jwt}
+	for _, disc := range disclosures {
+		name, _, err := decodeDisclosure(disc)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range claims {
+			if c == name {
+				keep = append(keep, disc)
+				break
+			}
+		}
+	}
+	return strings.Join(append(keep, ""), "~"), nil
+}
+
+// Split an SD-JWT into its issuer-signed JWT and disclosures.
+func splitSDJWT(token string) (string, []string, bool) {
+	parts := strings.Split(token, "~")
+	if len(parts) < 2 {
+		return token, nil, false
+	}
+	var disclosures []string
+	for _, d := range parts[1:] {
+		if d != "" {
+			disclosures = append(disclosures, d)
+		}
+	}
+	return parts[0], disclosures, true
+}
+
+// Restore the disclosed claims into the credential subject. Every
+// disclosure must match a digest on the subject and can only be used once.
+func applyDisclosures(claims *jwtClaims, disclosures []string) error {
+	cred := claims.VC
+	if cred == nil || cred.Subject == nil {
+		return errors.Errorf("%s: missing 'vc' claim", ErrInvalidCredential)
+	}
+	if claims.SDAlg != sdAlg {
+		return errors.Errorf("%s: unsupported '_sd_alg' value", ErrInvalidCredential)
+	}
+	digests := map[string]bool{}
+	list, _ := cred.Subject["_sd"].([]interface{})
+	for _, d := range list {
+		if s, ok := d.(string); ok {
+			digests[s] = true
+		}
+	}
+	delete(cred.Subject, "_sd")
+	for _, disc := range disclosures {
+		digest := disclosureDigest(disc)
+		if !digests[digest] {
+			return errors.Errorf("%s: unknown disclosure", ErrInvalidProof)
+		}
+		delete(digests, digest) // disclosures can't be repeated
+		name, value, err := decodeDisclosure(disc)
+		if err != nil {
+			return err
+		}
+		if _, ok := cred.Subject[name]; ok || name == "_sd" {
+			return errors.Errorf("%s: invalid disclosure for '%s'", ErrInvalidProof, name)
+		}
+		cred.Subject[name] = value
+	}
+	return nil
+}
+
+// Produce the disclosure for a claim.
+//
+//	disclosure = base64url(json([salt, name, value]))
+func newDisclosure(name string, value interface{}) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	js, err := json.Marshal([]interface{}{b64.EncodeToString(salt), name, value})
+	if err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(js), nil
+}
+
+// Return the claim name and value from a disclosure.
+func decodeDisclosure(disc string) (string, interface{}, error) {
+	var el []interface{}
+	if err := decodeSegment(disc, &el); err != nil || len(el) != 3 {
+		return "", nil, errors.New("invalid disclosure")
+	}
+	name, ok := el[1].(string)
+	if !ok {
+		return "", nil, errors.New("invalid disclosure")
+	}
+	return name, el[2], nil
+}
+
+// Return the digest of a disclosure.
+func disclosureDigest(disc string) string {
+	h := sha256.Sum256([]byte(disc))
+	return b64.EncodeToString(h[:])
+}
@@ -0,0 +1,230 @@
+package vc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+const (
+	// StatusListEntryType is the status type for credentials using a
+	// status list.
+	// https://www.w3.org/TR/vc-status-list/#statuslist2021entry
+	StatusListEntryType = "StatusList2021Entry"
+
+	// StatusListCredentialType is the type of credentials publishing a
+	// status list.
+	StatusListCredentialType = "StatusList2021Credential"
+
+	// StatusRevocation is used for status entries that can't be reversed.
+	StatusRevocation = "revocation"
+
+	// StatusSuspension is used for status entries that can be reversed.
+	StatusSuspension = "suspension"
+)
+
+// Minimum size, in entries, of a status list; used to provide group
+// privacy for credential holders.
+const minStatusListSize = 131072
+
+// Maximum size allowed for retrieved status list credentials.
+const maxStatusListCredentialSize = 1 << 20
+
+// StatusChecker instances verify the status of credentials.
+type StatusChecker interface {
+	// Check returns an error if the status entry of a credential is not
+	// valid; for example if the credential was revoked.
+	Check(status *Status) error
+}
+
+// StatusList is a bitstring where each position represents the status of
+// a credential; a set bit means the credential was revoked or suspended,
+// depending on the list purpose.
+// https://www.w3.org/TR/vc-status-list/
+type StatusList struct {
+	bits []byte
+}
+
+// NewStatusList returns an empty status list with, at least, `size`
+// entries. The minimum size is 131,072 entries.
+func NewStatusList(size int) *StatusList {
+	if size < minStatusListSize {
+		size = minStatusListSize
+	}
+	return &StatusList{bits: make([]byte, (size+7)/8)}
+}
+
+// DecodeStatusList restores a status list from its encoded representation.
+func DecodeStatusList(encoded string) (*StatusList, error) {
+	// tolerate multibase prefix and padding
+	encoded = strings.TrimRight(strings.TrimPrefix(encoded, "u"), "=")
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		if compressed, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			return nil, errors.New("invalid status list encoding")
+		}
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid status list")
+	}
+	bits, err := io.ReadAll(io.LimitReader(zr, minStatusListSize*64))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid status list")
+	}
+	return &StatusList{bits: bits}, nil
+}
+
+// Size returns the number of entries on the list.
+func (sl *StatusList) Size() int {
+	return len(sl.bits) * 8
+}
+
+// Set the status for the entry at `index`.
+func (sl *StatusList) Set(index int, status bool) error {
+	if index < 0 || index >= sl.Size() {
+		return errors.Errorf("invalid status list index: %d", index)
+	}
+	mask := byte(1 << (7 - index%8))
+	if status {
+		sl.bits[index/8] |= mask
+	} else {
+		sl.bits[index/8] &^= mask
+	}
+	return nil
+}
+
+// Get the status for the entry at `index`.
+func (sl *StatusList) Get(index int) (bool, error) {
+	if index < 0 || index >= sl.Size() {
+		return false, errors.Errorf("invalid status list index: %d", index)
+	}
+	return sl.bits[index/8]&(1<<(7-index%8)) != 0, nil
+}
+
+// Encode returns the GZIP-compressed, base64url-encoded representation of
+// the list; used as the "encodedList" value of a status list credential.
+func (sl *StatusList) Encode() (string, error) {
+	buf := bytes.NewBuffer(nil)
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write(sl.bits); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// StatusListOptions defines the configuration settings available for a
+// status list checker.
+type StatusListOptions struct {
+	// HTTP client used to retrieve status list credentials. If not provided,
+	// the default client is used.
+	Client *http.Client
+
+	// Verifier used to validate retrieved status list credentials. If not
+	// provided, status list credentials are not verified.
+	Verifier *Verifier
+
+	// Timeout for each individual request. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// StatusListChecker verifies "StatusList2021Entry" status entries by
+// retrieving the referenced status list credential.
+type StatusListChecker struct {
+	opts StatusListOptions
+}
+
+// NewStatusListChecker returns a new status list checker instance.
+func NewStatusListChecker(opts StatusListOptions) *StatusListChecker {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &StatusListChecker{opts: opts}
+}
+
+// Check the status entry of a credential. Status entries of unknown types
+// are rejected.
+func (sc *StatusListChecker) Check(status *Status) error {
+	if status.Type != StatusListEntryType {
+		return errors.Errorf("unsupported status type: %s", status.Type)
+	}
+	index, err := strconv.Atoi(status.ListIndex)
+	if err != nil {
+		return errors.Errorf("invalid status list index: %s", status.ListIndex)
+	}
+	list, purpose, err := sc.fetch(status.ListCredential)
+	if err != nil {
+		return err
+	}
+	if purpose != status.Purpose {
+		return errors.Errorf("status purpose mismatch: %s", purpose)
+	}
+	set, err := list.Get(index)
+	if err != nil {
+		return err
+	}
+	if !set {
+		return nil
+	}
+	if purpose == StatusSuspension {
+		return errors.New(ErrSuspended)
+	}
+	return errors.New(ErrRevoked)
+}
+
+// Retrieve the status list credential available at `endpoint` and return
+// the decoded list and its purpose.
+func (sc *StatusListChecker) fetch(endpoint string) (*StatusList, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sc.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "invalid status list credential")
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := sc.opts.Client.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to retrieve status list")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, "", errors.Errorf("failed to retrieve status list: %s", res.Status)
+	}
+	cred := new(Credential)
+	body := io.LimitReader(res.Body, maxStatusListCredentialSize)
+	if err = json.NewDecoder(body).Decode(cred); err != nil {
+		return nil, "", errors.Wrap(err, "invalid status list credential")
+	}
+	if !cred.HasType(StatusListCredentialType) {
+		return nil, "", errors.New("invalid status list credential")
+	}
+	if sc.opts.Verifier != nil {
+		if err = sc.opts.Verifier.Verify(cred); err != nil {
+			return nil, "", errors.Wrap(err, "invalid status list credential")
+		}
+	}
+	purpose, _ := cred.Subject["statusPurpose"].(string)
+	encoded, _ := cred.Subject["encodedList"].(string)
+	list, err := DecodeStatusList(encoded)
+	if err != nil {
+		return nil, "", err
+	}
+	return list, purpose, nil
+}
@@ -0,0 +1,240 @@
+package vc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tdd "github.com/stretchr/testify/assert"
+	"go.bryk.io/pkg/did"
+)
+
+func newIssuer(t *testing.T, kt did.KeyType) *Issuer {
+	id, err := did.NewKeyIdentifier(kt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := NewIssuer(id, id.VerificationMethods()[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return is
+}
+
+func sampleCredential() *Credential {
+	return &Credential{
+		Type: []string{"UniversityDegreeCredential"},
+		Subject: map[string]interface{}{
+			"id":     "did:example:ebfeb1f712ebc6f1c276e12ec21",
+			"degree": "Bachelor of Science and Arts",
+		},
+	}
+}
+
+func TestIssue(t *testing.T) {
+	assert := tdd.New(t)
+	verifier, err := NewVerifier()
+	assert.Nil(err, "new verifier")
+
+	for _, kt := range []did.KeyType{did.KeyTypeEd, did.KeyTypeP256} {
+		t.Run(kt.String(), func(t *testing.T) {
+			is := newIssuer(t, kt)
+			cred, err := is.Issue(sampleCredential())
+			assert.Nil(err, "issue")
+			assert.Equal(is.DID(), cred.Issuer)
+			assert.Equal(CredentialsContext, cred.Context[0])
+			assert.True(cred.HasType(CredentialType))
+			assert.Nil(verifier.Verify(cred), "verify")
+
+			// Round-trip
+			js, _ := json.Marshal(cred)
+			restored := new(Credential)
+			assert.Nil(json.Unmarshal(js, restored))
+			assert.Nil(verifier.Verify(restored), "verify restored")
+
+			// Tampered claims
+			restored.Subject["degree"] = "PhD"
+			assert.NotNil(verifier.Verify(restored), "tampered credential")
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		is := newIssuer(t, did.KeyTypeSecp256k1)
+		_, err := is.Issue(sampleCredential())
+		assert.NotNil(err, "unsupported key type")
+	})
+}
+
+func TestIssueJWT(t *testing.T) {
+	assert := tdd.New(t)
+	verifier, err := NewVerifier()
+	assert.Nil(err, "new verifier")
+
+	for _, kt := range []did.KeyType{did.KeyTypeEd, did.KeyTypeP256, did.KeyTypeSecp256k1} {
+		t.Run(kt.String(), func(t *testing.T) {
+			is := newIssuer(t, kt)
+			token, err := is.IssueJWT(sampleCredential())
+			assert.Nil(err, "issue")
+			cred, err := verifier.VerifyJWT(token)
+			assert.Nil(err, "verify")
+			assert.Equal(is.DID(), cred.Issuer)
+			assert.Equal("did:example:ebfeb1f712ebc6f1c276e12ec21", cred.SubjectID())
+
+			// Token issued by a different DID
+			other := newIssuer(t, kt)
+			forged, _ := other.IssueJWT(sampleCredential())
+			hd, claims, _ := parseJWT(forged)
+			claims.Issuer = is.DID()
+			claims.VC.Issuer = is.DID()
+			hd.KeyID = is.key.ID
+			hds, _ := json.Marshal(hd)
+			pls, _ := json.Marshal(claims)
+			sig := forged[strings.LastIndex(forged, ".")+1:]
+			_, err = verifier.VerifyJWT(b64.EncodeToString(hds) + "." + b64.EncodeToString(pls) + "." + sig)
+			assert.NotNil(err, "forged token")
+		})
+	}
+}
+
+func TestValidityPeriod(t *testing.T) {
+	assert := tdd.New(t)
+	is := newIssuer(t, did.KeyTypeEd)
+	now := time.Now().UTC()
+
+	cred := sampleCredential()
+	cred.IssuanceDate = now.Add(-2 * time.Hour).Format(time.RFC3339)
+	cred.ExpirationDate = now.Add(-1 * time.Hour).Format(time.RFC3339)
+	expired, err := is.Issue(cred)
+	assert.Nil(err, "issue")
+
+	cred = sampleCredential()
+	cred.IssuanceDate = now.Add(time.Hour).Format(time.RFC3339)
+	future, err := is.Issue(cred)
+	assert.Nil(err, "issue")
+
+	verifier, _ := NewVerifier()
+	err = verifier.Verify(expired)
+	assert.Equal(ErrExpired, err.Error())
+	err = verifier.Verify(future)
+	assert.Equal(ErrNotYetValid, err.Error())
+
+	// Adjusted clock
+	verifier, _ = NewVerifier(WithClock(func() time.Time { return now.Add(-90 * time.Minute) }))
+	assert.Nil(verifier.Verify(expired))
+	verifier, _ = NewVerifier(WithClockSkew(2 * time.Hour))
+	assert.Nil(verifier.Verify(future))
+}
+
+func TestStatusList(t *testing.T) {
+	assert := tdd.New(t)
+
+	// Bitstring
+	list := NewStatusList(0)
+	assert.Equal(minStatusListSize, list.Size())
+	assert.Nil(list.Set(94567, true))
+	encoded, err := list.Encode()
+	assert.Nil(err, "encode")
+	restored, err := DecodeStatusList(encoded)
+	assert.Nil(err, "decode")
+	set, _ := restored.Get(94567)
+	assert.True(set)
+	set, _ = restored.Get(94568)
+	assert.False(set)
+	assert.NotNil(restored.Set(-1, true))
+
+	// Status list credential
+	is := newIssuer(t, did.KeyTypeEd)
+	var published []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(published)
+	}))
+	defer srv.Close()
+	publish := func() {
+		encoded, _ := list.Encode()
+		slc, err := is.Issue(&Credential{
+			ID:   srv.URL,
+			Type: []string{StatusListCredentialType},
+			Subject: map[string]interface{}{
+				"type":          "StatusList2021",
+				"statusPurpose": StatusRevocation,
+				"encodedList":   encoded,
+			},
+		})
+		assert.Nil(err, "issue status list")
+		published, _ = json.Marshal(slc)
+	}
+	publish()
+
+	issueWithStatus := func(index string) *Credential {
+		cred := sampleCredential()
+		cred.Status = &Status{
+			Type:           StatusListEntryType,
+			Purpose:        StatusRevocation,
+			ListIndex:      index,
+			ListCredential: srv.URL,
+		}
+		cred, err := is.Issue(cred)
+		assert.Nil(err, "issue")
+		return cred
+	}
+	valid := issueWithStatus("10")
+	revoked := issueWithStatus("94567")
+
+	base, _ := NewVerifier()
+	checker := NewStatusListChecker(StatusListOptions{Client: srv.Client(), Verifier: base})
+	verifier, _ := NewVerifier(WithStatusChecker(checker))
+	assert.Nil(verifier.Verify(valid), "valid status")
+	err = verifier.Verify(revoked)
+	assert.Equal(ErrRevoked, err.Error())
+
+	// Reinstate credential
+	_ = list.Set(94567, false)
+	publish()
+	assert.Nil(verifier.Verify(revoked), "reinstated")
+}
+
+func TestJSONSchemaValidator(t *testing.T) {
+	assert := tdd.New(t)
+	schemaID := "https://example.com/schemas/degree.json"
+	sv, err := NewJSONSchemaValidator(map[string][]byte{
+		schemaID: []byte(`{
+			"type": "object",
+			"required": ["credentialSubject"],
+			"properties": {
+				"credentialSubject": {
+					"type": "object",
+					"required": ["id", "degree"],
+					"properties": {
+						"id": {"type": "string", "pattern": "^did:"},
+						"degree": {"type": "string", "enum": ["Bachelor of Science and Arts", "PhD"]},
+						"gpa": {"type": "number", "minimum": 0, "maximum": 4}
+					}
+				}
+			}
+		}`),
+	})
+	assert.Nil(err, "new validator")
+	verifier, _ := NewVerifier(WithSchemaValidator(sv))
+	is := newIssuer(t, did.KeyTypeEd)
+
+	cred := sampleCredential()
+	cred.Schema = []Schema{{ID: schemaID, Type: "JsonSchema"}}
+	valid, err := is.Issue(cred)
+	assert.Nil(err, "issue")
+	assert.Nil(verifier.Verify(valid), "valid credential")
+
+	cred.Subject["gpa"] = 5
+	invalid, err := is.Issue(cred)
+	assert.Nil(err, "issue")
+	assert.NotNil(verifier.Verify(invalid), "invalid credential")
+
+	cred.Subject["gpa"] = 3.5
+	cred.Schema = []Schema{{ID: "https://example.com/unknown.json", Type: "JsonSchema"}}
+	unknown, err := is.Issue(cred)
+	assert.Nil(err, "issue")
+	assert.NotNil(verifier.Verify(unknown), "unknown schema")
+}
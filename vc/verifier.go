@@ -0,0 +1,162 @@
+package vc
+
+import (
+	"time"
+
+	"go.bryk.io/pkg/did"
+	"go.bryk.io/pkg/did/resolver"
+	"go.bryk.io/pkg/errors"
+)
+
+// Resolver instances provide access to the DID documents required to
+// verify credentials. A 'resolver.Instance' satisfies this interface.
+type Resolver interface {
+	// Resolve a DID into a DID document.
+	Resolve(id string, opts *resolver.ResolutionOptions) (*resolver.Result, error)
+}
+
+// Verifier instances validate credentials secured with data integrity
+// proofs or as JWTs. The following checks are performed:
+//  1. Is the credential structurally valid?
+//  2. Is the proof (or JWT signature) valid, and was it produced with an
+//     assertion method of the issuer?
+//  3. Is the credential within its validity period?
+//  4. Is the credential status valid? Only if a status checker is available.
+//  5. Does the credential conform to its schemas? Only if a schema validator
+//     is available.
+type Verifier struct {
+	resolver Resolver
+	status   StatusChecker
+	schema   SchemaValidator
+	clock    func() time.Time
+	skew     time.Duration
+}
+
+// NewVerifier returns a new credential verifier instance. By default,
+// issuer DIDs are resolved using a resolver with support for the "did:key"
+// method only.
+func NewVerifier(opts ...VerifierOption) (*Verifier, error) {
+	v := &Verifier{clock: time.Now}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	if v.resolver == nil {
+		ri, err := resolver.New()
+		if err != nil {
+			return nil, err
+		}
+		v.resolver = ri
+	}
+	return v, nil
+}
+
+// Verify a credential secured with a data integrity proof.
+func (v *Verifier) Verify(cred *Credential) error {
+	if err := cred.Validate(); err != nil {
+		return err
+	}
+	if cred.Proof == nil || cred.Proof.Purpose != purposeAssertion {
+		return errors.Errorf("%s: missing assertion proof", ErrInvalidProof)
+	}
+	key, err := v.key(cred.Issuer, cred.Proof.VerificationMethod, did.AssertionVM)
+	if err != nil {
+		return err
+	}
+	doc := *cred
+	doc.Proof = nil
+	if err = verifyProof(&doc, doc.Context, key, cred.Proof); err != nil {
+		return err
+	}
+	return v.check(cred)
+}
+
+// VerifyJWT validates a credential secured as a JWT, or SD-JWT, and on
+// success returns the decoded credential. For SD-JWT credentials only the
+// disclosed claims are included in the credential subject.
+func (v *Verifier) VerifyJWT(token string) (*Credential, error) {
+	jwt, disclosures, sd := splitSDJWT(token)
+	hd, claims, err := parseJWT(jwt)
+	if err != nil {
+		return nil, err
+	}
+	if sd {
+		if err = applyDisclosures(claims, disclosures); err != nil {
+			return nil, err
+		}
+	}
+	cred, err := claimsCredential(claims)
+	if err != nil {
+		return nil, err
+	}
+	if err = cred.Validate(); err != nil {
+		return nil, err
+	}
+	key, err := v.key(cred.Issuer, hd.KeyID, did.AssertionVM)
+	if err != nil {
+		return nil, err
+	}
+	if err = verifyJWT(jwt, hd, key); err != nil {
+		return nil, err
+	}
+	return cred, v.check(cred)
+}
+
+// Run validity period, status and schema checks.
+func (v *Verifier) check(cred *Credential) error {
+	now := v.clock()
+	issued, _ := cred.Issued()
+	if now.Add(v.skew).Before(issued) {
+		return errors.New(ErrNotYetValid)
+	}
+	expires, _ := cred.Expires()
+	if !expires.IsZero() && now.Add(-v.skew).After(expires) {
+		return errors.New(ErrExpired)
+	}
+	if cred.Status != nil && v.status != nil {
+		if err := v.status.Check(cred.Status); err != nil {
+			return err
+		}
+	}
+	if v.schema != nil {
+		for _, sc := range cred.Schema {
+			if err := v.schema.Validate(sc, cred); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Retrieve the verification method `vm` from the `controller` DID document.
+// The verification method must be enabled for the `rel` relationship.
+func (v *Verifier) key(controller, vm string, rel did.VerificationRelationship) (*did.VerificationKey, error) {
+	ref, err := did.Parse(vm)
+	if err != nil || ref.DID() != controller {
+		return nil, errors.Errorf("%s: invalid verification method '%s'", ErrInvalidProof, vm)
+	}
+	res, err := v.resolver.Resolve(controller, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve '%s'", controller)
+	}
+	var refs []string
+	switch rel {
+	case did.AuthenticationVM:
+		refs = res.Document.Authentication
+	case did.AssertionVM:
+		refs = res.Document.AssertionMethod
+	default:
+		return nil, errors.New("unsupported verification relationship")
+	}
+	if !hasReference(refs, vm) {
+		return nil, errors.Errorf("%s: '%s' is not enabled for the proof purpose", ErrInvalidProof, vm)
+	}
+	for _, k := range res.Document.VerificationMethod {
+		if k.ID == vm {
+			key := k
+			return &key, nil
+		}
+	}
+	return nil, errors.Errorf("%s: unknown verification method '%s'", ErrInvalidProof, vm)
+}
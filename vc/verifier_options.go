@@ -0,0 +1,65 @@
+package vc
+
+import (
+	"time"
+
+	"go.bryk.io/pkg/errors"
+)
+
+// VerifierOption elements provide a functional-style configuration mechanism
+// for credential verifiers.
+type VerifierOption func(v *Verifier) error
+
+// WithResolver sets the resolver used to retrieve the DID documents of
+// credential issuers.
+func WithResolver(r Resolver) VerifierOption {
+	return func(v *Verifier) error {
+		if r == nil {
+			return errors.New("invalid resolver")
+		}
+		v.resolver = r
+		return nil
+	}
+}
+
+// WithStatusChecker enables status verification for credentials including
+// a "credentialStatus" entry.
+func WithStatusChecker(sc StatusChecker) VerifierOption {
+	return func(v *Verifier) error {
+		v.status = sc
+		return nil
+	}
+}
+
+// WithSchemaValidator enables validation of credentials against the data
+// schemas declared on their "credentialSchema" entries.
+func WithSchemaValidator(sv SchemaValidator) VerifierOption {
+	return func(v *Verifier) error {
+		v.schema = sv
+		return nil
+	}
+}
+
+// WithClock sets the clock source used to verify validity periods. Defaults
+// to the local system time.
+func WithClock(clock func() time.Time) VerifierOption {
+	return func(v *Verifier) error {
+		if clock == nil {
+			return errors.New("invalid clock source")
+		}
+		v.clock = clock
+		return nil
+	}
+}
+
+// WithClockSkew sets the maximum clock difference tolerated when verifying
+// validity periods. Defaults to 0.
+func WithClockSkew(skew time.Duration) VerifierOption {
+	return func(v *Verifier) error {
+		if skew < 0 {
+			return errors.New("invalid clock skew value")
+		}
+		v.skew = skew
+		return nil
+	}
+}